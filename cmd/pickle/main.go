@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -10,10 +11,13 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/shortontech/pickle/pkg/collection"
 	"github.com/shortontech/pickle/pkg/exporter"
 	"github.com/shortontech/pickle/pkg/generator"
 	picklemcp "github.com/shortontech/pickle/pkg/mcp"
+	"github.com/shortontech/pickle/pkg/names"
 	"github.com/shortontech/pickle/pkg/scaffold"
+	"github.com/shortontech/pickle/pkg/schema"
 	"github.com/shortontech/pickle/pkg/squeeze"
 	"github.com/shortontech/pickle/pkg/watcher"
 )
@@ -41,7 +45,7 @@ func main() {
 		cmdExport()
 	case "mcp":
 		cmdMCP()
-	case "migrate", "migrate:rollback", "migrate:fresh", "migrate:status", "db:seed":
+	case "migrate", "migrate:rollback", "migrate:fresh", "migrate:status", "migrate:sql", "db:seed":
 		if os.Args[1] == "db:seed" && helpRequested(os.Args[2:]) {
 			dbSeedUsage()
 			return
@@ -58,6 +62,16 @@ func main() {
 		cmdMigrate()
 	case "graphql:schema":
 		cmdGraphQLSchema()
+	case "erd":
+		if err := runERDCommand(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+			os.Exit(1)
+		}
+	case "collection":
+		if err := runCollectionCommand(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+			os.Exit(1)
+		}
 	case "make:controller":
 		cmdMakeController()
 	case "make:migration":
@@ -68,10 +82,16 @@ func main() {
 		cmdMakeMiddleware()
 	case "make:job":
 		cmdMakeJob()
+	case "make:command":
+		cmdMakeCommand()
 	case "make:seeder":
 		cmdMakeSeeder()
+	case "make:factory":
+		cmdMakeFactory()
 	case "make:policy":
 		cmdMakePolicy()
+	case "make:access-policy":
+		cmdMakeAccessPolicy()
 	case "make:action":
 		cmdMakeAction()
 	case "make:scope":
@@ -122,16 +142,17 @@ func usage() {
 	fmt.Println(`Usage: pickle <command>
 
 Commands:
-  create <name>     Create a new Pickle project
-  generate          Generate all files from project sources
+  create <name>     Create a new Pickle project (--module <path>, --docker)
+  generate          Generate all files from project sources (--skip-broken: skip migrations that don't compile)
   export            Export a standalone Go application
   --watch           Watch for changes and regenerate on save
   mcp               Start the MCP server (stdio transport)
   mcp --http :9921  Start the MCP server (SSE over HTTP)
-  migrate           Run all pending migrations
+  migrate           Run all pending migrations (--strict: fail if an applied one was edited)
   migrate:rollback  Roll back the last batch of migrations
   migrate:fresh     Drop all tables and re-run all migrations
-  migrate:status    Show migration status
+  migrate:status    Show migration status (--json for CI)
+  migrate:sql       Write each migration's up/down SQL to database/migrations/sql
   db:seed           Run a compiled database seed scenario
   policies:rollback Roll back the last batch of role policies
   policies:status   Show role policy status
@@ -146,18 +167,24 @@ Commands:
   make:request      Scaffold a new request class
   make:middleware    Scaffold a new middleware
   make:job              Scaffold a new job
+  make:command          Scaffold a custom app command (e.g. report:daily)
   make:seeder           Scaffold a root seed scenario
+  make:factory          Scaffold a test data factory for a model
   make:policy          Scaffold a new role policy
+  make:access-policy   Scaffold an authorization policy (app/policies)
   make:action          Scaffold a new action + gate (model/action)
   make:scope           Scaffold a new scope (model/scope)
   make:graphql-policy  Scaffold a new GraphQL policy
   graphql:schema       Print the current GraphQL SDL
+  erd                  Write a Mermaid ER diagram to docs/schema.mmd (--out <file>, - for stdout)
+  collection           Write a Postman collection and environment for the routes to docs/ (--out <file>, --env-out <file>)
   squeeze              Run static analysis on your Pickle project
 
 Options:
   --project <dir>   Project directory (default: current directory)
   --app <name>      Target a specific app in a monorepo (requires pickle.yaml with apps)
   --live            With squeeze, inspect live PostgreSQL RLS through the generated app
+  --format <fmt>    With squeeze, report as text (default), json or sarif
   --help, -h        Show this help
   --version, -v     Show version`)
 }
@@ -225,7 +252,7 @@ func cmdExport() {
 
 func cmdCreate() {
 	if len(os.Args) < 3 {
-		fmt.Fprintf(os.Stderr, "Usage: pickle create <project-name>\n")
+		fmt.Fprintf(os.Stderr, "Usage: pickle create <project-name> [--module <path>] [--docker]\n")
 		os.Exit(1)
 	}
 
@@ -245,11 +272,15 @@ func cmdCreate() {
 
 	// Use project name as module name, allow override with --module
 	moduleName := projectName
+	var opts scaffold.Options
 	args := os.Args[3:]
 	for i := 0; i < len(args); i++ {
-		if args[i] == "--module" && i+1 < len(args) {
+		switch {
+		case args[i] == "--module" && i+1 < len(args):
 			moduleName = args[i+1]
 			i++
+		case args[i] == "--docker":
+			opts.Docker = true
 		}
 	}
 
@@ -259,7 +290,7 @@ func cmdCreate() {
 	}
 
 	fmt.Printf("pickle create: %s\n", projectName)
-	if err := scaffold.Create(moduleName, targetDir); err != nil {
+	if err := scaffold.CreateWithOptions(moduleName, targetDir, opts); err != nil {
 		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
 		os.Exit(1)
 	}
@@ -291,6 +322,9 @@ func cmdCreate() {
 
 	fmt.Printf("\npickle: project %q created successfully!\n", projectName)
 	fmt.Printf("  cd %s && pickle --watch\n", projectName)
+	if opts.Docker {
+		fmt.Println("  docker compose up -d db && pickle migrate   # or: docker compose up --build")
+	}
 }
 
 func cmdMCP() {
@@ -334,6 +368,7 @@ func cmdMCP() {
 func cmdGenerate() {
 	projectDir := "."
 	appFilter := ""
+	skipBroken := false
 	args := os.Args[2:]
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -347,6 +382,8 @@ func cmdGenerate() {
 				appFilter = args[i+1]
 				i++
 			}
+		case "--skip-broken":
+			skipBroken = true
 		}
 	}
 
@@ -368,6 +405,7 @@ func cmdGenerate() {
 				fmt.Fprintf(os.Stderr, "pickle: app %s: %v\n", name, err)
 				os.Exit(1)
 			}
+			project.SkipBrokenMigrations = skipBroken
 			fmt.Printf("pickle generate: [%s] %s\n", name, project.Dir)
 			if err := generator.Generate(project, picklePkgDir); err != nil {
 				fmt.Fprintf(os.Stderr, "pickle: app %s: %v\n", name, err)
@@ -397,13 +435,14 @@ func cmdGenerate() {
 				Name:           name,
 				Dir:            absDir,
 				HTTPDir:        filepath.Join(absDir, "http"),
-				HTTPPkg:        "pickle",
+				HTTPPkg:        project.Layout.HTTPPkg,
 				RequestsDir:    filepath.Join(absDir, "http", "requests"),
 				CommandsDir:    filepath.Join(absDir, "commands"),
 				RowPolicyOwner: svc.RowPolicyOwner,
 			})
 		}
 
+		project.SkipBrokenMigrations = skipBroken
 		picklePkgDir := findPicklePkgDir()
 		fmt.Printf("pickle generate: %s (%d services)\n", project.Dir, len(project.Services))
 		if err := generator.Generate(project, picklePkgDir); err != nil {
@@ -428,6 +467,7 @@ func cmdGenerate() {
 		os.Exit(1)
 	}
 
+	project.SkipBrokenMigrations = skipBroken
 	picklePkgDir := findPicklePkgDir()
 
 	fmt.Printf("pickle generate: %s\n", project.Dir)
@@ -481,14 +521,25 @@ func projectFromAppConfig(rootDir string, appCfg squeeze.AppConfig) (*generator.
 
 func cmdMigrate() {
 	projectDir := "."
+	jsonOutput := false
 	args := os.Args[2:]
 	for i := 0; i < len(args); i++ {
 		if args[i] == "--project" && i+1 < len(args) {
 			projectDir = args[i+1]
 			i++
+		} else if args[i] == "--json" {
+			jsonOutput = true
 		}
 	}
 
+	// With --json, stdout carries only the project binary's JSON; pickle's
+	// own progress and the generator's output go to stderr.
+	stdout := os.Stdout
+	if jsonOutput {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
 	project, err := generator.DetectProject(projectDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
@@ -525,7 +576,7 @@ func cmdMigrate() {
 	}
 	cmd := exec.Command("go", forwarded...)
 	cmd.Dir = project.Dir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 
 	// Load .env from project root
@@ -688,6 +739,7 @@ func cmdWatch() {
 				Name:          app.name,
 				ProjectDir:    app.project.Dir,
 				MigrationDirs: migDirs,
+				ExtraDirs:     cfg.Watch.Dirs,
 			})
 		}
 
@@ -698,7 +750,7 @@ func cmdWatch() {
 				if app.name == appName {
 					fmt.Printf("\n  [%s] changed: %d file(s)\n", appName, len(changed))
 					fmt.Println("  regenerating...")
-					if err := generator.Generate(app.project, picklePkgDir); err != nil {
+					if err := generator.GenerateSelective(app.project, picklePkgDir, changed); err != nil {
 						fmt.Fprintf(os.Stderr, "  [%s] error: %v\n", appName, err)
 					} else {
 						fmt.Printf("  [%s] done\n", appName)
@@ -728,7 +780,7 @@ func cmdWatch() {
 				Name:           name,
 				Dir:            absDir,
 				HTTPDir:        filepath.Join(absDir, "http"),
-				HTTPPkg:        "pickle",
+				HTTPPkg:        project.Layout.HTTPPkg,
 				RequestsDir:    filepath.Join(absDir, "http", "requests"),
 				CommandsDir:    filepath.Join(absDir, "commands"),
 				RowPolicyOwner: svc.RowPolicyOwner,
@@ -742,7 +794,7 @@ func cmdWatch() {
 			fmt.Fprintf(os.Stderr, "pickle: generate failed: %v\n", err)
 		}
 
-		watchDirs := watcher.WatchDirsForServices(svcDirs)
+		watchDirs := append(watcher.WatchDirsForServices(svcDirs), cfg.Watch.Dirs...)
 		fmt.Println("  watching for changes (ctrl+c to stop)")
 		if err := watcher.WatchWithDirs(project.Dir, watchDirs, func(changed []string) {
 			fmt.Printf("\n  changed: %d file(s)\n", len(changed))
@@ -773,7 +825,8 @@ func cmdWatch() {
 	}
 
 	fmt.Println("  watching for changes (ctrl+c to stop)")
-	if err := watcher.Watch(project.Dir, func(changed []string) {
+	watchDirs := append(append([]string{}, watcher.WatchDirs...), cfg.Watch.Dirs...)
+	if err := watcher.WatchWithDirs(project.Dir, watchDirs, func(changed []string) {
 		fmt.Printf("\n  changed: %d file(s)\n", len(changed))
 		for _, path := range changed {
 			rel, _ := filepath.Rel(project.Dir, path)
@@ -784,7 +837,7 @@ func cmdWatch() {
 		}
 
 		fmt.Println("  regenerating...")
-		if err := generator.Generate(project, picklePkgDir); err != nil {
+		if err := generator.GenerateSelective(project, picklePkgDir, changed); err != nil {
 			fmt.Fprintf(os.Stderr, "  error: %v\n", err)
 		} else {
 			fmt.Println("  done")
@@ -832,9 +885,9 @@ func cmdMakeController() {
 }
 
 func cmdMakeMigration() {
-	name, projectDir := parseMakeArgs()
+	name, projectDir, columns := parseMakeMigrationArgs()
 	if name == "" {
-		fmt.Fprintf(os.Stderr, "Usage: pickle make:migration <name>\n")
+		fmt.Fprintf(os.Stderr, "Usage: pickle make:migration <name> [--columns=\"title:string,user_id:uuid:fk=users.id\"]\n")
 		os.Exit(1)
 	}
 	project, err := generator.DetectProject(projectDir)
@@ -842,7 +895,7 @@ func cmdMakeMigration() {
 		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
 		os.Exit(1)
 	}
-	relPath, err := scaffold.MakeMigration(name, project.Dir)
+	relPath, err := scaffold.MakeMigrationWithColumns(name, project.Dir, columns)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
 		os.Exit(1)
@@ -850,6 +903,39 @@ func cmdMakeMigration() {
 	fmt.Printf("  created %s\n", relPath)
 }
 
+func parseMakeMigrationArgs() (name, projectDir, columns string) {
+	projectDir = "."
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--project":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "pickle: --project requires a directory")
+				os.Exit(1)
+			}
+			projectDir = args[i+1]
+			i++
+		case args[i] == "--columns":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "pickle: --columns requires a column list")
+				os.Exit(1)
+			}
+			columns = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--columns="):
+			columns = strings.TrimPrefix(args[i], "--columns=")
+		case strings.HasPrefix(args[i], "-"):
+			fmt.Fprintf(os.Stderr, "pickle: unknown flag %q\n", args[i])
+			os.Exit(1)
+		default:
+			if name == "" {
+				name = args[i]
+			}
+		}
+	}
+	return
+}
+
 func cmdMakeRequest() {
 	name, projectDir := parseMakeArgs()
 	if name == "" {
@@ -907,6 +993,26 @@ func cmdMakeJob() {
 	fmt.Printf("  created %s\n", relPath)
 }
 
+func cmdMakeCommand() {
+	name, projectDir := parseMakeArgs()
+	if name == "" {
+		fmt.Fprintf(os.Stderr, "Usage: pickle make:command <name>   (e.g. report:daily)\n")
+		os.Exit(1)
+	}
+	project, err := generator.DetectProject(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	relPath, err := scaffold.MakeCommand(name, project.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  created %s\n", relPath)
+	fmt.Println("  run pickle generate to register it")
+}
+
 func cmdMakeSeeder() {
 	name, projectDir, valueSeeder := parseMakeSeederArgs()
 	if name == "" {
@@ -931,6 +1037,42 @@ func cmdMakeSeeder() {
 	fmt.Printf("  created %s\n", relPath)
 }
 
+func cmdMakeFactory() {
+	name, projectDir := parseMakeArgs()
+	if name == "" {
+		fmt.Fprintf(os.Stderr, "Usage: pickle make:factory <Model>\n")
+		os.Exit(1)
+	}
+	project, err := generator.DetectProject(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	tables, _, _, err := generator.RunSchemaInspector(project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: schema inspection failed: %v\n", err)
+		os.Exit(1)
+	}
+	name = strings.TrimSuffix(name, "Factory")
+	var table *schema.Table
+	for _, t := range tables {
+		if t.Name == name || names.TableToStructName(t.Name) == name {
+			table = t
+			break
+		}
+	}
+	if table == nil {
+		fmt.Fprintf(os.Stderr, "pickle: table or model %q not found\n", name)
+		os.Exit(1)
+	}
+	relPath, err := scaffold.MakeFactory(table, project.Dir, project.ModulePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  created %s\n", relPath)
+}
+
 func parseMakeSeederArgs() (name, projectDir string, value bool) {
 	projectDir = "."
 	args := os.Args[2:]
@@ -977,6 +1119,25 @@ func cmdMakePolicy() {
 	fmt.Printf("  created %s\n", relPath)
 }
 
+func cmdMakeAccessPolicy() {
+	name, projectDir := parseMakeArgs()
+	if name == "" {
+		fmt.Fprintf(os.Stderr, "Usage: pickle make:access-policy <Name>\n")
+		os.Exit(1)
+	}
+	project, err := generator.DetectProject(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	relPath, err := scaffold.MakeAccessPolicy(name, project.Dir, project.ModulePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("  created %s\n", relPath)
+}
+
 func cmdMakeAction() {
 	name, projectDir := parseMakeArgs()
 	if name == "" {
@@ -1081,11 +1242,118 @@ func cmdGraphQLSchema() {
 	}
 }
 
+// runERDCommand renders the migrated schema as a Mermaid erDiagram and writes
+// it to docs/schema.mmd in the project, or to --out ("-" for out).
+func runERDCommand(args []string, out io.Writer) error {
+	projectDir := "."
+	outPath := filepath.Join("docs", "schema.mmd")
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--project", "--out":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			if args[i] == "--project" {
+				projectDir = args[i+1]
+			} else {
+				outPath = args[i+1]
+			}
+			i++
+		default:
+			return fmt.Errorf("usage: pickle erd [--out <file>] [--project <dir>]")
+		}
+	}
+
+	project, err := generator.DetectProject(projectDir)
+	if err != nil {
+		return err
+	}
+	tables, _, _, err := generator.RunSchemaInspector(project)
+	if err != nil {
+		return fmt.Errorf("schema inspection failed: %w", err)
+	}
+	diagram := generator.GenerateERDiagram(tables)
+
+	if outPath == "-" {
+		_, err = fmt.Fprint(out, diagram)
+		return err
+	}
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(project.Dir, outPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, []byte(diagram), 0o644); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "pickle erd: wrote %s (%d tables)\n", outPath, len(tables))
+	return err
+}
+
+func runCollectionCommand(args []string, out io.Writer) error {
+	projectDir := "."
+	outPath := filepath.Join("docs", "postman_collection.json")
+	envPath := filepath.Join("docs", "postman_environment.json")
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--project", "--out", "--env-out":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			switch args[i] {
+			case "--project":
+				projectDir = args[i+1]
+			case "--out":
+				outPath = args[i+1]
+			default:
+				envPath = args[i+1]
+			}
+			i++
+		default:
+			return fmt.Errorf("usage: pickle collection [--out <file>] [--env-out <file>] [--project <dir>]")
+		}
+	}
+
+	project, err := generator.DetectProject(projectDir)
+	if err != nil {
+		return err
+	}
+	coll, env, err := collection.Generate(project)
+	if err != nil {
+		return fmt.Errorf("building collection: %w", err)
+	}
+	for _, file := range []struct {
+		path string
+		data any
+	}{{outPath, coll}, {envPath, env}} {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := file.path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(project.Dir, path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "pickle collection: wrote %s\n", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func cmdSqueeze() {
 	projectDir := "."
 	hard := false
 	noSuppress := false
 	live := false
+	format := squeeze.FormatText
 	args := os.Args[2:]
 	for i := 0; i < len(args); i++ {
 		if args[i] == "--project" && i+1 < len(args) {
@@ -1097,13 +1365,28 @@ func cmdSqueeze() {
 			noSuppress = true
 		} else if args[i] == "--live" {
 			live = true
+		} else if args[i] == "--format" && i+1 < len(args) {
+			format = args[i+1]
+			i++
 		}
 	}
+	switch format {
+	case squeeze.FormatText, squeeze.FormatJSON, squeeze.FormatSARIF:
+	default:
+		fmt.Fprintf(os.Stderr, "pickle: unknown squeeze format %q (use text, json or sarif)\n", format)
+		os.Exit(1)
+	}
 
-	fmt.Println("\nAnalyzing Pickle project...")
+	// JSON and SARIF reports own stdout; progress goes to stderr.
+	status := os.Stdout
+	if format != squeeze.FormatText {
+		status = os.Stderr
+	}
+
+	fmt.Fprintln(status, "\nAnalyzing Pickle project...")
 	var liveRLS []squeeze.LiveRLSObservation
 	if live {
-		fmt.Println("  inspecting live PostgreSQL RLS state...")
+		fmt.Fprintln(status, "  inspecting live PostgreSQL RLS state...")
 		var err error
 		liveRLS, err = squeeze.InspectProjectRLS(projectDir)
 		if err != nil {
@@ -1118,11 +1401,11 @@ func cmdSqueeze() {
 	}
 	findings := result.Findings
 	if len(result.RowPolicyProofs) > 0 {
-		fmt.Println("\nRow-policy enforcement:")
+		fmt.Fprintln(status, "\nRow-policy enforcement:")
 		for _, proof := range result.RowPolicyProofs {
-			fmt.Printf("  %s: %s\n", proof.Table, proof.Classification)
-			fmt.Printf("    rule IDs: %s\n", strings.Join(proof.RuleIDs, ", "))
-			fmt.Printf("    evidence: %s\n", strings.Join(proof.Evidence, ", "))
+			fmt.Fprintf(status, "  %s: %s\n", proof.Table, proof.Classification)
+			fmt.Fprintf(status, "    rule IDs: %s\n", strings.Join(proof.RuleIDs, ", "))
+			fmt.Fprintf(status, "    evidence: %s\n", strings.Join(proof.Evidence, ", "))
 		}
 	}
 
@@ -1133,34 +1416,31 @@ func cmdSqueeze() {
 		}
 	}
 
-	if len(findings) == 0 {
-		fmt.Println("No findings.")
-		if result.Suppressed > 0 {
-			fmt.Printf("suppressed: %d\n", result.Suppressed)
-		}
-		return
-	}
-
-	// Print findings grouped by file
-	currentFile := ""
 	errors, warnings := 0, 0
 	for _, f := range findings {
-		if f.File != currentFile {
-			currentFile = f.File
-			fmt.Printf("\n  %s\n", currentFile)
-		}
-
-		color := "\033[33m" // yellow for warning
 		if f.Severity == squeeze.SeverityError {
-			color = "\033[31m" // red for error
 			errors++
 		} else {
 			warnings++
 		}
-		fmt.Printf("    %sline %d\033[0m [%s] %s\n", color, f.Line, f.Rule, f.Message)
 	}
 
-	fmt.Printf("\nFound %d error(s), %d warning(s), suppressed: %d\n", errors, warnings, result.Suppressed)
+	if format == squeeze.FormatText && len(findings) == 0 {
+		fmt.Println("No findings.")
+		if result.Suppressed > 0 {
+			fmt.Printf("suppressed: %d\n", result.Suppressed)
+		}
+		return
+	}
+
+	report, err := squeeze.FormatFindings(findings, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pickle: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(report)
+
+	fmt.Fprintf(status, "\nFound %d error(s), %d warning(s), suppressed: %d\n", errors, warnings, result.Suppressed)
 	if errors > 0 {
 		os.Exit(1)
 	}
@@ -0,0 +1,387 @@
+// Package collection builds Postman v2.1 collections from a Pickle project's
+// routes and request structs, for importing the API into Postman or Insomnia.
+package collection
+
+import (
+	"encoding/json"
+	"go/ast"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shortontech/pickle/pkg/generator"
+	"github.com/shortontech/pickle/pkg/squeeze"
+)
+
+// SchemaURL identifies the Postman collection format written by Build.
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// DefaultBaseURL is used when the project's .env has no APP_URL.
+const DefaultBaseURL = "http://localhost:8080"
+
+// Collection is a Postman v2.1 collection.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// Info names the collection.
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is a folder, when Item is set, or a single request.
+type Item struct {
+	Name    string   `json:"name"`
+	Item    []Item   `json:"item,omitempty"`
+	Request *Request `json:"request,omitempty"`
+}
+
+// Request is one route.
+type Request struct {
+	Method      string   `json:"method"`
+	Header      []Header `json:"header"`
+	URL         URL      `json:"url"`
+	Body        *Body    `json:"body,omitempty"`
+	Auth        *Auth    `json:"auth,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Header is a request header.
+type Header struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// URL is a request URL, split the way Postman stores it. Path segments
+// starting with ":" are path variables.
+type URL struct {
+	Raw      string       `json:"raw"`
+	Host     []string     `json:"host"`
+	Path     []string     `json:"path"`
+	Query    []QueryParam `json:"query,omitempty"`
+	Variable []Variable   `json:"variable,omitempty"`
+}
+
+// QueryParam is a query-string parameter. Example parameters are disabled
+// until the user fills them in.
+type QueryParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// Variable is a collection or path variable.
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Body is a raw JSON request body.
+type Body struct {
+	Mode    string         `json:"mode"`
+	Raw     string         `json:"raw"`
+	Options map[string]any `json:"options,omitempty"`
+}
+
+// Auth is the bearer authentication sent on routes behind auth middleware.
+type Auth struct {
+	Type   string         `json:"type"`
+	Bearer []AuthVariable `json:"bearer"`
+}
+
+// AuthVariable is a value of an Auth method.
+type AuthVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+// Environment is a Postman environment holding the variables the
+// collection's requests use.
+type Environment struct {
+	Name   string             `json:"name"`
+	Values []EnvironmentValue `json:"values"`
+	Scope  string             `json:"_postman_variable_scope"`
+}
+
+// EnvironmentValue is one environment variable.
+type EnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Generate reads a project's routes, controllers and request structs and
+// builds its collection and environment. The collection is named after the
+// module, and the environment's baseUrl comes from APP_URL in .env.
+func Generate(project *generator.Project) (*Collection, *Environment, error) {
+	cfg, err := squeeze.LoadConfig(project.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	routes, err := squeeze.ParseRoutes(filepath.Join(project.Dir, "routes"))
+	if err != nil {
+		return nil, nil, err
+	}
+	methods, err := squeeze.ParseControllers(filepath.Join(project.Dir, "app", "http", "controllers"))
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, nil, err
+	}
+	requests, err := generator.ScanRequests(project.Layout.RequestsDir)
+	if err != nil && !os.IsNotExist(err) && !strings.Contains(err.Error(), "no such file") {
+		return nil, nil, err
+	}
+
+	name := path.Base(project.ModulePath)
+	baseURL := generator.ParseDotEnv(filepath.Join(project.Dir, ".env"))["APP_URL"]
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return Build(name, baseURL, routes, methods, requests, cfg.Squeeze.Middleware), NewEnvironment(name, baseURL), nil
+}
+
+// Build returns a collection with a folder per route group and a request
+// per route. Routes whose controller method binds a request struct get an
+// example JSON body, or example query parameters for GET, built from the
+// struct's fields. Routes behind auth middleware send {{token}} as a bearer
+// token.
+func Build(name, baseURL string, routes []squeeze.AnalyzedRoute, methods map[string]*squeeze.ControllerMethod, requests []generator.RequestDef, mw squeeze.MiddlewareConfig) *Collection {
+	byName := make(map[string]generator.RequestDef, len(requests))
+	for _, req := range requests {
+		byName[req.Name] = req
+	}
+
+	c := &Collection{
+		Info:     Info{Name: name, Schema: SchemaURL},
+		Variable: []Variable{{Key: "baseUrl", Value: baseURL}},
+	}
+	folders := make(map[string]int)
+	for _, route := range routes {
+		item := Item{Name: route.Method + " " + route.Path, Request: buildRequest(route, methods, byName, mw)}
+		if route.Name != "" {
+			item.Name = route.Name
+		}
+		if route.Group == "" {
+			c.Item = append(c.Item, item)
+			continue
+		}
+		i, ok := folders[route.Group]
+		if !ok {
+			i = len(c.Item)
+			folders[route.Group] = i
+			c.Item = append(c.Item, Item{Name: route.Group})
+		}
+		c.Item[i].Item = append(c.Item[i].Item, item)
+	}
+	return c
+}
+
+// NewEnvironment returns an environment defining baseUrl and an empty token.
+func NewEnvironment(name, baseURL string) *Environment {
+	return &Environment{
+		Name: name,
+		Values: []EnvironmentValue{
+			{Key: "baseUrl", Value: baseURL, Type: "default", Enabled: true},
+			{Key: "token", Value: "", Type: "secret", Enabled: true},
+		},
+		Scope: "environment",
+	}
+}
+
+func buildRequest(route squeeze.AnalyzedRoute, methods map[string]*squeeze.ControllerMethod, requests map[string]generator.RequestDef, mw squeeze.MiddlewareConfig) *Request {
+	r := &Request{Method: route.Method, Header: []Header{}, URL: buildURL(route.Path)}
+	if route.ControllerType != "" && route.MethodName != "" {
+		r.Description = route.ControllerType + "." + route.MethodName
+	}
+	if route.HasAuthMiddleware(mw) {
+		r.Auth = &Auth{Type: "bearer", Bearer: []AuthVariable{{Key: "token", Value: "{{token}}", Type: "string"}}}
+	}
+
+	method, ok := methods[route.ControllerType+"."+route.MethodName]
+	if !ok {
+		return r
+	}
+	req, ok := requests[boundRequest(method.Body, requests)]
+	if !ok {
+		return r
+	}
+	if route.Method == "GET" {
+		for _, field := range req.Fields {
+			if field.JSONTag == "-" || field.FormTag == "-" {
+				continue
+			}
+			key := field.FormTag
+			if key == "" {
+				key = jsonKey(field)
+			}
+			r.URL.Query = append(r.URL.Query, QueryParam{Key: key, Value: queryValue(exampleValue(field)), Disabled: true})
+		}
+		return r
+	}
+	raw, _ := json.MarshalIndent(exampleBody(req), "", "  ")
+	r.Header = append(r.Header, Header{Key: "Content-Type", Value: "application/json"})
+	r.Body = &Body{Mode: "raw", Raw: string(raw), Options: map[string]any{"raw": map[string]string{"language": "json"}}}
+	return r
+}
+
+// buildURL splits a route path into Postman's URL form, declaring a path
+// variable for each :param segment.
+func buildURL(routePath string) URL {
+	u := URL{Raw: "{{baseUrl}}" + routePath, Host: []string{"{{baseUrl}}"}, Path: []string{}}
+	for _, seg := range strings.Split(strings.Trim(routePath, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		u.Path = append(u.Path, seg)
+		if param, ok := strings.CutPrefix(seg, ":"); ok {
+			u.Variable = append(u.Variable, Variable{Key: param, Value: ""})
+		}
+	}
+	return u
+}
+
+// boundRequest returns the name of the first request struct body binds
+// through a generated Bind<Request> call, or "".
+func boundRequest(body *ast.BlockStmt, requests map[string]generator.RequestDef) string {
+	if body == nil {
+		return ""
+	}
+	found := ""
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found != "" {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var fn string
+		switch f := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			fn = f.Sel.Name
+		case *ast.Ident:
+			fn = f.Name
+		}
+		if name, ok := strings.CutPrefix(fn, "Bind"); ok {
+			if _, known := requests[name]; known {
+				found = name
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// exampleBody returns a JSON object with an example value for each field
+// the request decodes.
+func exampleBody(req generator.RequestDef) map[string]any {
+	body := make(map[string]any)
+	for _, field := range req.Fields {
+		if field.JSONTag == "-" {
+			continue
+		}
+		body[jsonKey(field)] = exampleValue(field)
+	}
+	return body
+}
+
+func jsonKey(field generator.RequestField) string {
+	if field.JSONTag != "" {
+		return field.JSONTag
+	}
+	return field.Name
+}
+
+// exampleTime is the instant example time values show.
+var exampleTime = time.Date(2024, time.January, 15, 9, 30, 0, 0, time.UTC)
+
+// exampleValue picks a value for a field from its validate rules and Go
+// type: the first oneof option, an email or URL for those rules, a number's
+// min, and the zero value otherwise.
+func exampleValue(field generator.RequestField) any {
+	rules := map[string]string{}
+	for _, rule := range strings.Split(field.Validate, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		rules[key] = value
+	}
+	typ := strings.TrimPrefix(field.Type, "*")
+
+	switch {
+	case rules["oneof"] != "":
+		option := strings.Fields(rules["oneof"])[0]
+		if isNumeric(typ) {
+			if n, err := strconv.ParseFloat(option, 64); err == nil {
+				return n
+			}
+		}
+		return option
+	case field.IsResourceID:
+		return ""
+	case typ == "time.Time":
+		layout := time.RFC3339
+		if field.Format != "" {
+			layout = field.Format
+		}
+		return exampleTime.Format(layout)
+	case typ == "uuid.UUID" || hasRule(rules, "uuid", "uuid4"):
+		return "00000000-0000-0000-0000-000000000000"
+	case hasRule(rules, "email"):
+		return "user@example.com"
+	case hasRule(rules, "url", "uri", "http_url"):
+		return "https://example.com"
+	case typ == "bool":
+		return false
+	case isNumeric(typ):
+		for _, key := range []string{"min", "gte", "gt"} {
+			if n, err := strconv.ParseFloat(rules[key], 64); err == nil {
+				return n
+			}
+		}
+		return 0
+	case typ == "string":
+		return ""
+	case strings.HasPrefix(typ, "[]"):
+		return []any{}
+	case strings.HasPrefix(typ, "map["):
+		return map[string]any{}
+	}
+	return nil
+}
+
+func hasRule(rules map[string]string, names ...string) bool {
+	for _, name := range names {
+		if _, ok := rules[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumeric(typ string) bool {
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// queryValue renders an example value as a query-string value.
+func queryValue(v any) string {
+	switch v := v.(type) {
+	case nil, []any, map[string]any:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,149 @@
+package collection
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/generator"
+	"github.com/shortontech/pickle/pkg/squeeze"
+)
+
+func writeFile(t *testing.T, path, src string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func buildFixture(t *testing.T) *Collection {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "routes", "web.go"), `package routes
+
+import (
+	pickle "myapp/app/http"
+	"myapp/app/http/controllers"
+	"myapp/app/http/middleware"
+)
+
+var API = pickle.Routes(func(r *pickle.Router) {
+	r.Get("/health", controllers.HealthController{}.Show)
+	r.Group("/api", func(r *pickle.Router) {
+		r.Get("/posts", controllers.PostController{}.Index)
+		r.Post("/posts", controllers.PostController{}.Store, middleware.Auth)
+		r.Get("/posts/:id", controllers.PostController{}.Show).Name("posts.show")
+	})
+})
+`)
+	writeFile(t, filepath.Join(dir, "controllers", "post_controller.go"), `package controllers
+
+type PostController struct{}
+
+func (c PostController) Index(ctx *pickle.Context) pickle.Response {
+	req, err := requests.BindListPostsRequest(ctx.Request())
+	_ = err
+	return ctx.JSON(200, req)
+}
+
+func (c PostController) Store(ctx *pickle.Context) pickle.Response {
+	req, err := requests.BindCreatePostRequest(ctx.Request())
+	_ = err
+	return ctx.JSON(201, req)
+}
+`)
+	routes, err := squeeze.ParseRoutes(filepath.Join(dir, "routes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods, err := squeeze.ParseControllers(filepath.Join(dir, "controllers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := []generator.RequestDef{
+		{Name: "CreatePostRequest", Fields: []generator.RequestField{
+			{Name: "Title", Type: "string", JSONTag: "title", Validate: "required"},
+			{Name: "Status", Type: "string", JSONTag: "status", Validate: "oneof=draft published"},
+			{Name: "AuthorEmail", Type: "string", JSONTag: "author_email", Validate: "email"},
+			{Name: "Rating", Type: "*int", JSONTag: "rating", Validate: "min=1,max=5"},
+			{Name: "Internal", Type: "string", JSONTag: "-"},
+		}},
+		{Name: "ListPostsRequest", Fields: []generator.RequestField{
+			{Name: "Page", Type: "int", JSONTag: "page", Validate: "min=1"},
+			{Name: "Search", Type: "string", JSONTag: "search", FormTag: "q"},
+		}},
+	}
+	return Build("myapp", "http://localhost:8080", routes, methods, requests, squeeze.MiddlewareConfig{})
+}
+
+func TestBuildGroupsRoutesIntoFolders(t *testing.T) {
+	c := buildFixture(t)
+	if c.Info.Schema != SchemaURL || c.Info.Name != "myapp" {
+		t.Errorf("unexpected info: %+v", c.Info)
+	}
+	if len(c.Item) != 2 {
+		t.Fatalf("expected a top-level request and a folder, got %d items", len(c.Item))
+	}
+	if c.Item[0].Name != "GET /health" || c.Item[0].Request == nil {
+		t.Errorf("expected ungrouped route at the top level, got %+v", c.Item[0])
+	}
+	folder := c.Item[1]
+	if folder.Name != "/api" || len(folder.Item) != 3 {
+		t.Fatalf("expected /api folder with 3 requests, got %+v", folder)
+	}
+	show := folder.Item[2]
+	if show.Name != "posts.show" {
+		t.Errorf("expected route name as item name, got %q", show.Name)
+	}
+	u := show.Request.URL
+	if u.Raw != "{{baseUrl}}/api/posts/:id" || strings.Join(u.Path, "/") != "api/posts/:id" {
+		t.Errorf("unexpected url: %+v", u)
+	}
+	if len(u.Variable) != 1 || u.Variable[0].Key != "id" {
+		t.Errorf("expected id path variable, got %+v", u.Variable)
+	}
+	if show.Request.Body != nil || show.Request.Auth != nil {
+		t.Errorf("show should have no body or auth: %+v", show.Request)
+	}
+}
+
+func TestBuildExampleBodiesFromRequestFields(t *testing.T) {
+	store := buildFixture(t).Item[1].Item[1].Request
+	if store.Method != "POST" || store.Body == nil {
+		t.Fatalf("expected POST with body, got %+v", store)
+	}
+	want := `{
+  "author_email": "user@example.com",
+  "rating": 1,
+  "status": "draft",
+  "title": ""
+}`
+	if store.Body.Raw != want {
+		t.Errorf("body = %s\nwant %s", store.Body.Raw, want)
+	}
+	if store.Auth == nil || store.Auth.Bearer[0].Value != "{{token}}" {
+		t.Errorf("expected bearer auth behind Auth middleware, got %+v", store.Auth)
+	}
+}
+
+func TestBuildQueryParamsForGet(t *testing.T) {
+	index := buildFixture(t).Item[1].Item[0].Request
+	if index.Body != nil {
+		t.Errorf("GET should not have a body")
+	}
+	q := index.URL.Query
+	if len(q) != 2 || q[0].Key != "page" || q[0].Value != "1" || q[1].Key != "q" || !q[1].Disabled {
+		t.Errorf("unexpected query params: %+v", q)
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := NewEnvironment("myapp", "https://api.example.com")
+	if env.Scope != "environment" || len(env.Values) != 2 || env.Values[0].Key != "baseUrl" || env.Values[0].Value != "https://api.example.com" {
+		t.Errorf("unexpected environment: %+v", env)
+	}
+}
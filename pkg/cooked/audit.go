@@ -104,11 +104,8 @@ func auditIP(ctx *Context) string {
 }
 
 func auditRequestID(ctx *Context) string {
-	if ctx == nil || ctx.request == nil {
+	if ctx == nil {
 		return ""
 	}
-	if id := ctx.request.Header.Get("X-Request-ID"); id != "" {
-		return id
-	}
-	return ctx.request.Header.Get("X-Request-Id")
+	return ctx.RequestID()
 }
@@ -10,7 +10,7 @@ type CreateUserActionsTable_2026_03_25_000003 struct {
 func (m *CreateUserActionsTable_2026_03_25_000003) Up() {
 	m.CreateTable("user_actions", func(t *Table) {
 		t.AppendOnly()
-		t.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+		t.UUID("id").PrimaryKey().DefaultRaw("uuid_generate_v7()")
 		t.UUID("user_id").NotNull().ForeignKey("users", "id")
 		t.Integer("action_type_id").NotNull().ForeignKey("action_types", "id")
 		t.UUID("resource_id").NotNull()
@@ -18,7 +18,7 @@ func (m *CreateUserActionsTable_2026_03_25_000003) Up() {
 		t.UUID("role_id").Nullable().ForeignKey("roles", "id")
 		t.String("ip_address", 45).Nullable()
 		t.String("request_id", 100).Nullable()
-		t.Timestamp("created_at").NotNull().Default("NOW()")
+		t.Timestamp("created_at").NotNull().DefaultRaw("NOW()")
 	})
 
 	m.AddIndex("user_actions", "user_id")
@@ -1,12 +1,17 @@
 package jwt
 
 import (
+	"crypto"
 	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/x509"
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"hash"
@@ -24,56 +29,139 @@ import (
 // The specific reason is logged server-side but never exposed to callers.
 var ErrInvalidToken = errors.New("jwt: invalid token")
 
-// Driver implements JWT-based authentication using HMAC signing (HS256/HS384/HS512).
-// All crypto uses Go's stdlib — no third-party JWT library.
-// Tokens are tracked in a jwt_tokens table for revocation support.
+// Driver implements JWT-based authentication using HMAC (HS256/HS384/HS512)
+// or RSA (RS256/RS384/RS512) signing. All crypto uses Go's stdlib — no
+// third-party JWT library. Tokens are tracked in a jwt_tokens table for
+// revocation support.
 type Driver struct {
-	db        *sql.DB
-	secret    string
-	issuer    string
-	expiry    int // seconds
-	algorithm string
+	db            *sql.DB
+	secret        string
+	privateKey    *rsa.PrivateKey // RS* only; nil on verify-only services
+	publicKey     *rsa.PublicKey  // RS* only
+	issuer        string
+	expiry        int // access token lifetime, seconds
+	refreshExpiry int // refresh token lifetime, seconds
+	algorithm     string
 }
 
+// Token types carried in the typ claim. Access tokens omit it.
+const (
+	TokenTypeAccess  = ""
+	TokenTypeRefresh = "refresh"
+)
+
 // NewDriver creates a JWT auth driver. Config is read from environment:
-//   - JWT_SECRET: HMAC signing key (required)
+//   - JWT_ALGORITHM: HS256, HS384, HS512, RS256, RS384, or RS512 (default: HS256)
+//   - JWT_SECRET: HMAC signing key (required for HS*)
+//   - JWT_PRIVATE_KEY: PEM RSA private key (RS*; omit on services that only verify)
+//   - JWT_PUBLIC_KEY: PEM RSA public key (RS*; derived from the private key if unset)
 //   - JWT_ISSUER: expected issuer claim (optional)
-//   - JWT_EXPIRY: token lifetime in seconds (default: 3600)
-//   - JWT_ALGORITHM: HS256, HS384, or HS512 (default: HS256)
+//   - JWT_ACCESS_TTL: access token lifetime (default: 1h; JWT_EXPIRY is the legacy name)
+//   - JWT_REFRESH_TTL: refresh token lifetime (default: 720h)
+//
+// TTLs are either a number of seconds ("3600") or a Go duration ("15m").
+// Panics on missing or malformed keys so misconfiguration fails at startup.
 func NewDriver(env func(string, string) string, db *sql.DB) *Driver {
-	expiry := 3600
-	if v := env("JWT_EXPIRY", ""); v != "" {
-		// Simple atoi without importing strconv
-		n := 0
-		for _, c := range v {
-			if c >= '0' && c <= '9' {
-				n = n*10 + int(c-'0')
-			}
-		}
-		if n > 0 {
-			expiry = n
-		}
+	d, err := newDriver(env, db)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+func newDriver(env func(string, string) string, db *sql.DB) (*Driver, error) {
+	expiry := parseTTL("JWT_EXPIRY", env("JWT_EXPIRY", ""), 3600)
+	expiry = parseTTL("JWT_ACCESS_TTL", env("JWT_ACCESS_TTL", ""), expiry)
+	refreshExpiry := parseTTL("JWT_REFRESH_TTL", env("JWT_REFRESH_TTL", ""), 30*24*3600)
+
+	d := &Driver{
+		db:            db,
+		issuer:        env("JWT_ISSUER", ""),
+		expiry:        expiry,
+		refreshExpiry: refreshExpiry,
+		algorithm:     env("JWT_ALGORITHM", "HS256"),
 	}
 
-	secret := env("JWT_SECRET", "")
-	alg := env("JWT_ALGORITHM", "HS256")
+	if strings.HasPrefix(d.algorithm, "RS") {
+		if err := d.loadRSAKeys(env("JWT_PRIVATE_KEY", ""), env("JWT_PUBLIC_KEY", "")); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
 
-	if secret == "" {
-		panic("jwt: JWT_SECRET is required")
+	d.secret = env("JWT_SECRET", "")
+	if d.secret == "" {
+		return nil, errors.New("jwt: JWT_SECRET is required")
 	}
 
 	minLen := map[string]int{"HS256": 32, "HS384": 48, "HS512": 64}
-	if min, ok := minLen[alg]; ok && len(secret) < min {
-		panic(fmt.Sprintf("jwt: JWT_SECRET must be at least %d bytes for %s, got %d", min, alg, len(secret)))
+	if min, ok := minLen[d.algorithm]; ok && len(d.secret) < min {
+		return nil, fmt.Errorf("jwt: JWT_SECRET must be at least %d bytes for %s, got %d", min, d.algorithm, len(d.secret))
+	}
+	return d, nil
+}
+
+// loadRSAKeys parses the PEM keys for an RS* algorithm. At least one key is
+// required; a public key alone gives a driver that can only verify.
+func (d *Driver) loadRSAKeys(privatePEM, publicPEM string) error {
+	if _, ok := rsaHashes[d.algorithm]; !ok {
+		return fmt.Errorf("jwt: unsupported JWT_ALGORITHM %q (want RS256, RS384, or RS512)", d.algorithm)
+	}
+	if privatePEM == "" && publicPEM == "" {
+		return fmt.Errorf("jwt: JWT_PRIVATE_KEY or JWT_PUBLIC_KEY is required for %s", d.algorithm)
 	}
 
-	return &Driver{
-		db:        db,
-		secret:    secret,
-		issuer:    env("JWT_ISSUER", ""),
-		expiry:    expiry,
-		algorithm: alg,
+	if privatePEM != "" {
+		key, err := parseRSAPrivateKey(privatePEM)
+		if err != nil {
+			return fmt.Errorf("jwt: JWT_PRIVATE_KEY: %w", err)
+		}
+		d.privateKey = key
+		d.publicKey = &key.PublicKey
+	}
+	if publicPEM != "" {
+		key, err := parseRSAPublicKey(publicPEM)
+		if err != nil {
+			return fmt.Errorf("jwt: JWT_PUBLIC_KEY: %w", err)
+		}
+		if d.privateKey != nil && !d.privateKey.PublicKey.Equal(key) {
+			return errors.New("jwt: JWT_PUBLIC_KEY does not match JWT_PRIVATE_KEY")
+		}
+		d.publicKey = key
+	}
+	if bits := d.publicKey.N.BitLen(); bits < 2048 {
+		return fmt.Errorf("jwt: RSA key must be at least 2048 bits, got %d", bits)
 	}
+	return nil
+}
+
+// parseTTL converts a TTL setting to seconds. Empty, non-positive or
+// unparseable values keep fallback; the latter two are logged.
+func parseTTL(key, v string, fallback int) int {
+	if v == "" {
+		return fallback
+	}
+	n, digits := 0, true
+	for _, c := range v {
+		if c < '0' || c > '9' {
+			digits = false
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	if !digits {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("jwt: invalid %s=%q, using %ds", key, v, fallback)
+			return fallback
+		}
+		n = int(d / time.Second)
+	}
+	if n <= 0 {
+		log.Printf("jwt: invalid %s=%q, using %ds", key, v, fallback)
+		return fallback
+	}
+	return n
 }
 
 // Claims represents standard + custom JWT claims.
@@ -84,6 +172,7 @@ type Claims struct {
 	ExpiresAt int64          `json:"exp,omitempty"`
 	IssuedAt  int64          `json:"iat,omitempty"`
 	Role      string         `json:"role,omitempty"`
+	Type      string         `json:"typ,omitempty"`
 	Extra     map[string]any `json:"-"`
 }
 
@@ -98,11 +187,38 @@ func (d *Driver) Authenticate(r *http.Request) (*pickle.AuthInfo, error) {
 	return d.ValidateToken(token)
 }
 
-// SignToken creates a signed JWT from the given claims and registers it in the
-// jwt_tokens table for revocation tracking. The token is not valid unless it
-// exists in the table.
+// SignToken creates a signed access token from the given claims and registers
+// it in the jwt_tokens table for revocation tracking. The token is not valid
+// unless it exists in the table.
 func (d *Driver) SignToken(claims Claims) (string, error) {
-	if d.secret == "" {
+	claims.Type = TokenTypeAccess
+	return d.sign(claims, d.expiry)
+}
+
+// SignRefreshToken creates a signed refresh token, valid for JWT_REFRESH_TTL.
+// Refresh tokens are tracked and revoked like access tokens, but are only
+// accepted by Refresh — ValidateToken rejects them.
+func (d *Driver) SignRefreshToken(claims Claims) (string, error) {
+	claims.Type = TokenTypeRefresh
+	return d.sign(claims, d.refreshExpiry)
+}
+
+// Refresh validates a refresh token and mints a new access token for the
+// same subject and role. The refresh token stays valid until it expires or
+// is revoked.
+func (d *Driver) Refresh(refreshToken string) (string, error) {
+	claims, err := d.verify(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return "", err
+	}
+	return d.SignToken(Claims{Subject: claims.Subject, Role: claims.Role})
+}
+
+func (d *Driver) sign(claims Claims, ttl int) (string, error) {
+	if d.isRSA() && d.privateKey == nil {
+		return "", errors.New("jwt: private key not configured")
+	}
+	if !d.isRSA() && d.secret == "" {
 		return "", errors.New("jwt: secret not configured")
 	}
 	if d.db == nil {
@@ -113,8 +229,8 @@ func (d *Driver) SignToken(claims Claims) (string, error) {
 	if claims.IssuedAt == 0 {
 		claims.IssuedAt = now
 	}
-	if claims.ExpiresAt == 0 && d.expiry > 0 {
-		claims.ExpiresAt = now + int64(d.expiry)
+	if claims.ExpiresAt == 0 && ttl > 0 {
+		claims.ExpiresAt = now + int64(ttl)
 	}
 	if claims.Issuer == "" && d.issuer != "" {
 		claims.Issuer = d.issuer
@@ -137,7 +253,7 @@ func (d *Driver) SignToken(claims Claims) (string, error) {
 	payloadEnc := base64URLEncode(payload)
 
 	signingInput := header + "." + payloadEnc
-	sig, err := hmacSign([]byte(signingInput), []byte(d.secret), alg)
+	sig, err := d.signBytes([]byte(signingInput), alg)
 	if err != nil {
 		return "", err
 	}
@@ -155,9 +271,27 @@ func (d *Driver) SignToken(claims Claims) (string, error) {
 	return signingInput + "." + base64URLEncode(sig), nil
 }
 
-// ValidateToken parses and validates a JWT string, returning AuthInfo on success.
+// ValidateToken parses and validates an access token, returning AuthInfo on
+// success. Refresh tokens are rejected.
 func (d *Driver) ValidateToken(tokenStr string) (*pickle.AuthInfo, error) {
-	if d.secret == "" {
+	claims, err := d.verify(tokenStr, TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	return &pickle.AuthInfo{
+		UserID: claims.Subject,
+		Role:   claims.Role,
+		Claims: *claims,
+	}, nil
+}
+
+// verify checks signature, token type, expiry, issuer and revocation.
+func (d *Driver) verify(tokenStr, wantType string) (*Claims, error) {
+	if d.isRSA() && d.publicKey == nil {
+		log.Printf("jwt: rejected token reason=public_key_not_configured")
+		return nil, ErrInvalidToken
+	}
+	if !d.isRSA() && d.secret == "" {
 		log.Printf("jwt: rejected token reason=secret_not_configured")
 		return nil, ErrInvalidToken
 	}
@@ -198,7 +332,7 @@ func (d *Driver) ValidateToken(tokenStr string) (*pickle.AuthInfo, error) {
 		log.Printf("jwt: rejected token reason=invalid_signature_encoding")
 		return nil, ErrInvalidToken
 	}
-	if !hmacVerify([]byte(signingInput), sig, []byte(d.secret), alg) {
+	if !d.verifyBytes([]byte(signingInput), sig, alg) {
 		log.Printf("jwt: rejected token reason=invalid_signature")
 		return nil, ErrInvalidToken
 	}
@@ -215,6 +349,12 @@ func (d *Driver) ValidateToken(tokenStr string) (*pickle.AuthInfo, error) {
 		return nil, ErrInvalidToken
 	}
 
+	// Check token type, so a refresh token can't be used as an access token
+	if claims.Type != wantType {
+		log.Printf("jwt: rejected token jti=%s reason=wrong_token_type typ=%q", claims.JTI, claims.Type)
+		return nil, ErrInvalidToken
+	}
+
 	// Check expiry
 	if claims.ExpiresAt > 0 && time.Now().Unix() > claims.ExpiresAt {
 		log.Printf("jwt: rejected token jti=%s reason=token_expired", claims.JTI)
@@ -248,11 +388,7 @@ func (d *Driver) ValidateToken(tokenStr string) (*pickle.AuthInfo, error) {
 		}
 	}
 
-	return &pickle.AuthInfo{
-		UserID: claims.Subject,
-		Role:   claims.Role,
-		Claims: claims,
-	}, nil
+	return &claims, nil
 }
 
 // RevokeToken revokes a single token by JTI.
@@ -281,6 +417,89 @@ func (d *Driver) RevokeAllForUser(userID string) error {
 
 // --- internal helpers ---
 
+var rsaHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+func (d *Driver) isRSA() bool {
+	return strings.HasPrefix(d.algorithm, "RS")
+}
+
+func (d *Driver) signBytes(input []byte, alg string) ([]byte, error) {
+	if !d.isRSA() {
+		return hmacSign(input, []byte(d.secret), alg)
+	}
+	h := rsaHashes[alg].New()
+	h.Write(input)
+	return rsa.SignPKCS1v15(rand.Reader, d.privateKey, rsaHashes[alg], h.Sum(nil))
+}
+
+func (d *Driver) verifyBytes(input, sig []byte, alg string) bool {
+	if !d.isRSA() {
+		return hmacVerify(input, sig, []byte(d.secret), alg)
+	}
+	h := rsaHashes[alg].New()
+	h.Write(input)
+	return rsa.VerifyPKCS1v15(d.publicKey, rsaHashes[alg], h.Sum(nil), sig) == nil
+}
+
+// decodePEM returns the first PEM block in s. Env files often store keys on
+// one line with literal \n separators, so those are expanded first.
+func decodePEM(s string) (*pem.Block, error) {
+	s = strings.ReplaceAll(s, `\n`, "\n")
+	block, _ := pem.Decode([]byte(strings.TrimSpace(s)))
+	if block == nil {
+		return nil, errors.New("not a PEM-encoded key")
+	}
+	return block, nil
+}
+
+func parseRSAPrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, err := decodePEM(s)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an RSA key, got %T", key)
+		}
+		return rsaKey, nil
+	}
+	return nil, fmt.Errorf("unexpected PEM block %q (want RSA PRIVATE KEY or PRIVATE KEY)", block.Type)
+}
+
+func parseRSAPublicKey(s string) (*rsa.PublicKey, error) {
+	block, err := decodePEM(s)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an RSA key, got %T", key)
+		}
+		return rsaKey, nil
+	}
+	return nil, fmt.Errorf("unexpected PEM block %q (want PUBLIC KEY or RSA PUBLIC KEY)", block.Type)
+}
+
 func hmacHashFunc(alg string) func() hash.Hash {
 	switch alg {
 	case "HS384":
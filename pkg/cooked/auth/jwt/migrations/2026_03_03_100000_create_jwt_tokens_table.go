@@ -13,7 +13,7 @@ func (m *CreateJwtTokensTable_2026_03_03_100000) Up() {
 		t.UUID("user_id").NotNull()
 		t.Timestamp("expires_at").NotNull()
 		t.Timestamp("revoked_at").Nullable()
-		t.Timestamp("created_at").NotNull().Default("NOW()")
+		t.Timestamp("created_at").NotNull().DefaultRaw("NOW()")
 	})
 
 	m.AddIndex("jwt_tokens", "user_id")
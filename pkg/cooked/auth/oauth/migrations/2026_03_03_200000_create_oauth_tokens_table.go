@@ -12,7 +12,7 @@ func (m *CreateOauthTokensTable_2026_03_03_200000) Up() {
 		t.String("token", 255).PrimaryKey()
 		t.String("client_id", 255).NotNull()
 		t.Timestamp("expires_at").NotNull()
-		t.Timestamp("created_at").NotNull().Default("NOW()")
+		t.Timestamp("created_at").NotNull().DefaultRaw("NOW()")
 	})
 
 	m.AddIndex("oauth_tokens", "client_id")
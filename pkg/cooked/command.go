@@ -50,6 +50,13 @@ func (a *App) Run(args []string) {
 	a.serveFn()
 }
 
+// HasCommand reports whether Run dispatches name to a command rather than
+// starting the HTTP server.
+func (a *App) HasCommand(name string) bool {
+	_, ok := a.commands[name]
+	return ok
+}
+
 // PrintCommands prints available commands to stderr.
 func (a *App) PrintCommands() {
 	fmt.Fprintln(os.Stderr, "Available commands:")
@@ -41,6 +41,16 @@ func TestBuildApp(t *testing.T) {
 	_ = serveCalled
 }
 
+func TestAppHasCommand(t *testing.T) {
+	app := BuildApp(func() {}, func() {}, &mockCommand{name: "report:daily"})
+	if !app.HasCommand("report:daily") {
+		t.Error("expected report:daily to be registered")
+	}
+	if app.HasCommand("report") {
+		t.Error("unexpected command report")
+	}
+}
+
 func TestAppRunNoArgsCallsServe(t *testing.T) {
 	serveCalled := false
 	app := BuildApp(
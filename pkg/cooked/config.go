@@ -8,12 +8,16 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var envOnce sync.Once
+var envMu sync.RWMutex
 var envMap map[string]string
 
 // Env returns the value of the environment variable named by key,
@@ -21,7 +25,10 @@ var envMap map[string]string
 // .env file if it exists.
 func Env(key, fallback string) string {
 	envOnce.Do(loadEnv)
-	if v, ok := envMap[key]; ok {
+	envMu.RLock()
+	v, ok := envMap[key]
+	envMu.RUnlock()
+	if ok {
 		return v
 	}
 	if v := os.Getenv(key); v != "" {
@@ -30,12 +37,90 @@ func Env(key, fallback string) string {
 	return fallback
 }
 
+// RequireEnv returns an error naming every key that Env resolves to an
+// empty value, so startup reports all missing configuration at once
+// instead of failing on the first one. A key may list alternatives
+// separated by "|" ("JWT_PRIVATE_KEY|JWT_PUBLIC_KEY"), any one of which
+// satisfies it.
+func RequireEnv(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		alternatives := strings.Split(key, "|")
+		set := false
+		for _, alt := range alternatives {
+			set = set || Env(alt, "") != ""
+		}
+		if !set {
+			missing = append(missing, strings.Join(alternatives, " or "))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("pickle: missing required environment variables: %s", strings.Join(missing, ", "))
+}
+
+// EnvInt returns the environment variable named by key parsed as an int,
+// or fallback if it is unset. An unparseable value logs a warning and
+// also returns fallback.
+func EnvInt(key string, fallback int) int {
+	v := Env(key, "")
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		log.Printf("pickle: invalid integer %s=%q, using default %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+// EnvBool returns the environment variable named by key as a bool, or
+// fallback if it is unset. Accepts 1/0, true/false and yes/no in any case;
+// anything else logs a warning and returns fallback.
+func EnvBool(key string, fallback bool) bool {
+	v := Env(key, "")
+	if v == "" {
+		return fallback
+	}
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	log.Printf("pickle: invalid boolean %s=%q, using default %t", key, v, fallback)
+	return fallback
+}
+
+// EnvDuration returns the environment variable named by key parsed with
+// time.ParseDuration (e.g. "30s", "5m"), or fallback if it is unset. An
+// unparseable value logs a warning and returns fallback.
+func EnvDuration(key string, fallback time.Duration) time.Duration {
+	v := Env(key, "")
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		log.Printf("pickle: invalid duration %s=%q, using default %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
 // loadEnv reads a .env file from the current directory if it exists.
 // Lines are KEY=VALUE pairs. Comments (#) and blank lines are ignored.
 // Quoted values (single or double) are unquoted. Existing environment
 // variables take precedence over .env values.
 func loadEnv() {
-	envMap = make(map[string]string)
+	vars := make(map[string]string)
+	defer func() {
+		envMu.Lock()
+		envMap = vars
+		envMu.Unlock()
+	}()
 
 	f, err := os.Open(".env")
 	if err != nil {
@@ -68,11 +153,122 @@ func loadEnv() {
 
 		// Environment variables take precedence over .env
 		if os.Getenv(key) == "" {
-			envMap[key] = value
+			vars[key] = value
 		}
 	}
 }
 
+// reloadEnv re-reads .env, so edits made since startup are seen by Env.
+func reloadEnv() {
+	envOnce.Do(func() {})
+	loadEnv()
+}
+
+var configMu sync.RWMutex
+var configVars map[string]any
+
+// setConfigVars records the config vars Get resolves paths against, keyed by
+// their config function's name ("database" for Database). The generated Init
+// and Reload call it.
+func setConfigVars(vars map[string]any) {
+	configMu.Lock()
+	configVars = vars
+	configMu.Unlock()
+}
+
+// Get resolves a dotted path like "database.connections.pgsql.host" against
+// the loaded config. The first segment names a config function; later
+// segments name struct fields (by Go name, snake_case name or json tag, in
+// any case), map keys, or slice indexes. It reports false when the path
+// doesn't exist or Init hasn't run. Get is safe to call during Reload.
+func Get(path string) (any, bool) {
+	segments := strings.Split(path, ".")
+	configMu.RLock()
+	root, ok := configVars[segments[0]]
+	configMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	v, ok := resolveConfigPath(reflect.ValueOf(root), segments[1:])
+	if !ok {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+func resolveConfigPath(v reflect.Value, segments []string) (reflect.Value, bool) {
+	for _, segment := range segments {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := configField(v.Type(), segment)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v = v.FieldByIndex(field.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			v = v.MapIndex(reflect.ValueOf(segment).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return reflect.Value{}, false
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(i)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return v, v.IsValid() && v.CanInterface()
+}
+
+// configField finds the exported field of t a path segment names.
+func configField(t reflect.Type, segment string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if strings.EqualFold(f.Name, segment) || strings.EqualFold(configSnakeCase(f.Name), segment) || (tag != "" && tag != "-" && tag == segment) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// configSnakeCase converts a Go field name to snake_case: MaxOpenConns →
+// max_open_conns, SSLMode → ssl_mode.
+func configSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		upper := r >= 'A' && r <= 'Z'
+		if upper && i > 0 {
+			prevLower := s[i-1] >= 'a' && s[i-1] <= 'z'
+			nextLower := i+1 < len(s) && s[i+1] >= 'a' && s[i+1] <= 'z'
+			prevUpper := s[i-1] >= 'A' && s[i-1] <= 'Z'
+			if prevLower || (prevUpper && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		if upper {
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // ConnectionConfig describes a single database connection.
 type ConnectionConfig struct {
 	Driver   string
@@ -83,6 +279,56 @@ type ConnectionConfig struct {
 	Password string
 	Region   string            // AWS region (DynamoDB), GCP region, etc.
 	Options  map[string]string // Driver-specific options (sslmode, charset, etc.)
+
+	// Pool settings. Zero uses the driver default; a negative value means no
+	// limit (or, for MaxIdleConns, no idle connections).
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// connectionPool holds the pool settings applied to a *sql.DB.
+type connectionPool struct {
+	maxOpen, maxIdle int
+	maxLifetime      time.Duration
+}
+
+// pool returns the connection's pool settings with driver defaults filled in.
+// Postgres and MySQL cap the pool well under typical server limits and recycle
+// connections so failovers and proxy timeouts don't leave dead ones behind;
+// MySQL recycles sooner because servers and proxies drop idle connections
+// aggressively. SQLite serializes writes on the file, so its pool is left
+// unbounded to avoid blocking a transaction on its own queries.
+func (c ConnectionConfig) pool() connectionPool {
+	var p connectionPool
+	switch c.Driver {
+	case "pgsql":
+		p = connectionPool{maxOpen: 25, maxIdle: 10, maxLifetime: 30 * time.Minute}
+	case "mysql":
+		p = connectionPool{maxOpen: 25, maxIdle: 10, maxLifetime: 5 * time.Minute}
+	case "sqlite":
+		p = connectionPool{maxIdle: 2}
+	}
+	if c.MaxOpenConns != 0 {
+		p.maxOpen = c.MaxOpenConns
+	}
+	if c.MaxIdleConns != 0 {
+		p.maxIdle = c.MaxIdleConns
+	}
+	if c.ConnMaxLifetime != 0 {
+		p.maxLifetime = c.ConnMaxLifetime
+	}
+	return p
+}
+
+// apply configures db with the pool settings. database/sql treats zero and
+// negative limits as "no limit", except SetMaxIdleConns where they mean none.
+func (p connectionPool) apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.maxOpen)
+	db.SetMaxIdleConns(p.maxIdle)
+	if p.maxLifetime > 0 {
+		db.SetConnMaxLifetime(p.maxLifetime)
+	}
 }
 
 // DSN returns the driver-specific data source name.
@@ -126,16 +372,28 @@ func (c ConnectionConfig) driverName() string {
 }
 
 // OpenDB opens a database connection using the given ConnectionConfig,
-// pings it, and returns *sql.DB. Fatals on failure — call at startup.
+// applies its pool settings, pings it, and returns *sql.DB. Fatals on
+// failure — call at startup.
 func OpenDB(conn ConnectionConfig) *sql.DB {
+	db, err := openDB(conn)
+	if err != nil {
+		log.Fatalf("pickle: %v", err)
+	}
+	return db
+}
+
+// openDB opens and pings a connection, returning the error OpenDB exits on.
+func openDB(conn ConnectionConfig) (*sql.DB, error) {
 	db, err := sql.Open(conn.driverName(), conn.DSN())
 	if err != nil {
-		log.Fatalf("pickle: failed to open database: %v", err)
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	conn.pool().apply(db)
 	if err := db.Ping(); err != nil {
-		log.Fatalf("pickle: failed to ping database: %v", err)
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
-	return db
+	return db, nil
 }
 
 // RuntimeConfig holds configuration values that can be hot-reloaded without
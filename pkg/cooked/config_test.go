@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // resetEnv resets the package-level env state so tests don't interfere.
@@ -85,6 +86,39 @@ func TestEnvOSTakesPrecedenceOverDotEnv(t *testing.T) {
 
 // --- ConnectionConfig.DSN ---
 
+func TestRequireEnvReportsAllMissing(t *testing.T) {
+	resetEnv()
+	os.Setenv("PICKLE_TEST_PRESENT", "yes")
+	defer os.Unsetenv("PICKLE_TEST_PRESENT")
+
+	err := RequireEnv("PICKLE_TEST_MISSING_A", "PICKLE_TEST_PRESENT", "PICKLE_TEST_MISSING_B")
+	if err == nil {
+		t.Fatal("expected error for missing vars")
+	}
+	want := "pickle: missing required environment variables: PICKLE_TEST_MISSING_A, PICKLE_TEST_MISSING_B"
+	if err.Error() != want {
+		t.Errorf("RequireEnv = %q, want %q", err, want)
+	}
+	if err := RequireEnv("PICKLE_TEST_PRESENT"); err != nil {
+		t.Errorf("RequireEnv(present) = %v, want nil", err)
+	}
+}
+
+func TestRequireEnvAlternatives(t *testing.T) {
+	resetEnv()
+	os.Setenv("PICKLE_TEST_PRESENT", "yes")
+	defer os.Unsetenv("PICKLE_TEST_PRESENT")
+
+	if err := RequireEnv("PICKLE_TEST_MISSING_A|PICKLE_TEST_PRESENT"); err != nil {
+		t.Errorf("RequireEnv with one alternative set = %v, want nil", err)
+	}
+	err := RequireEnv("PICKLE_TEST_MISSING_A|PICKLE_TEST_MISSING_B")
+	want := "pickle: missing required environment variables: PICKLE_TEST_MISSING_A or PICKLE_TEST_MISSING_B"
+	if err == nil || err.Error() != want {
+		t.Errorf("RequireEnv = %v, want %q", err, want)
+	}
+}
+
 func TestDSNPgsql(t *testing.T) {
 	c := ConnectionConfig{
 		Driver: "pgsql", Host: "localhost", Port: "5432",
@@ -146,6 +180,31 @@ func TestDSNUnknownDriverPanics(t *testing.T) {
 	c.DSN()
 }
 
+func TestConnectionPoolDefaults(t *testing.T) {
+	tests := []struct {
+		conn ConnectionConfig
+		want connectionPool
+	}{
+		{ConnectionConfig{Driver: "pgsql"}, connectionPool{maxOpen: 25, maxIdle: 10, maxLifetime: 30 * time.Minute}},
+		{ConnectionConfig{Driver: "mysql"}, connectionPool{maxOpen: 25, maxIdle: 10, maxLifetime: 5 * time.Minute}},
+		{ConnectionConfig{Driver: "sqlite"}, connectionPool{maxIdle: 2}},
+		{ConnectionConfig{Driver: "pgsql", MaxOpenConns: 50, MaxIdleConns: -1, ConnMaxLifetime: time.Hour}, connectionPool{maxOpen: 50, maxIdle: -1, maxLifetime: time.Hour}},
+	}
+	for _, tt := range tests {
+		if got := tt.conn.pool(); got != tt.want {
+			t.Errorf("pool(%+v) = %+v, want %+v", tt.conn, got, tt.want)
+		}
+	}
+}
+
+func TestOpenDBAppliesPoolSettings(t *testing.T) {
+	db := OpenDB(ConnectionConfig{Driver: "sqlite", Name: ":memory:", MaxOpenConns: 3})
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", got)
+	}
+}
+
 // --- driverName ---
 
 func TestDriverName(t *testing.T) {
@@ -174,3 +233,118 @@ func TestDriverNameUnknownPanics(t *testing.T) {
 	c := ConnectionConfig{Driver: "oracle"}
 	c.driverName()
 }
+
+func TestEnvInt(t *testing.T) {
+	resetEnv()
+	t.Setenv("PICKLE_TEST_INT", " 42 ")
+	t.Setenv("PICKLE_TEST_INT_BAD", "forty")
+	if got := EnvInt("PICKLE_TEST_INT", 7); got != 42 {
+		t.Errorf("EnvInt = %d, want 42", got)
+	}
+	if got := EnvInt("PICKLE_TEST_INT_BAD", 7); got != 7 {
+		t.Errorf("EnvInt invalid = %d, want fallback 7", got)
+	}
+	if got := EnvInt("PICKLE_TEST_INT_UNSET", 7); got != 7 {
+		t.Errorf("EnvInt unset = %d, want fallback 7", got)
+	}
+}
+
+func TestEnvBool(t *testing.T) {
+	cases := []struct {
+		value    string
+		fallback bool
+		want     bool
+	}{
+		{"1", false, true},
+		{"true", false, true},
+		{"YES", false, true},
+		{"0", true, false},
+		{"False", true, false},
+		{"no", true, false},
+		{"maybe", true, true},
+		{"maybe", false, false},
+		{"", true, true},
+	}
+	for _, tc := range cases {
+		resetEnv()
+		t.Setenv("PICKLE_TEST_BOOL", tc.value)
+		if got := EnvBool("PICKLE_TEST_BOOL", tc.fallback); got != tc.want {
+			t.Errorf("EnvBool(%q, %t) = %t, want %t", tc.value, tc.fallback, got, tc.want)
+		}
+	}
+}
+
+func TestEnvDuration(t *testing.T) {
+	resetEnv()
+	t.Setenv("PICKLE_TEST_DURATION", "1m30s")
+	t.Setenv("PICKLE_TEST_DURATION_BAD", "90")
+	if got := EnvDuration("PICKLE_TEST_DURATION", time.Second); got != 90*time.Second {
+		t.Errorf("EnvDuration = %s, want 1m30s", got)
+	}
+	if got := EnvDuration("PICKLE_TEST_DURATION_BAD", time.Second); got != time.Second {
+		t.Errorf("EnvDuration invalid = %s, want fallback 1s", got)
+	}
+	if got := EnvDuration("PICKLE_TEST_DURATION_UNSET", 5*time.Minute); got != 5*time.Minute {
+		t.Errorf("EnvDuration unset = %s, want fallback 5m", got)
+	}
+}
+
+func TestGetResolvesDottedPaths(t *testing.T) {
+	type connection struct {
+		Host         string
+		MaxOpenConns int
+		Replicas     []string `json:"read_replicas"`
+	}
+	type database struct {
+		Default     string
+		Connections map[string]connection
+	}
+	setConfigVars(map[string]any{
+		"database": database{
+			Default: "pgsql",
+			Connections: map[string]connection{
+				"pgsql": {Host: "localhost", MaxOpenConns: 10, Replicas: []string{"r1", "r2"}},
+			},
+		},
+	})
+	defer setConfigVars(nil)
+
+	tests := []struct {
+		path string
+		want any
+		ok   bool
+	}{
+		{"database.default", "pgsql", true},
+		{"database.Default", "pgsql", true},
+		{"database.connections.pgsql.host", "localhost", true},
+		{"database.connections.pgsql.max_open_conns", 10, true},
+		{"database.connections.pgsql.read_replicas.1", "r2", true},
+		{"database.connections.pgsql.read_replicas.2", nil, false},
+		{"database.connections.mysql.host", nil, false},
+		{"database.connections.pgsql.host.extra", nil, false},
+		{"app.name", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := Get(tt.path)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("Get(%q) = %v, %v; want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := Get("database"); !ok {
+		t.Error("Get of a whole config var should succeed")
+	}
+}
+
+func TestConfigSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"Host":            "host",
+		"MaxOpenConns":    "max_open_conns",
+		"SSLMode":         "ssl_mode",
+		"ConnMaxLifetime": "conn_max_lifetime",
+		"APIKey":          "api_key",
+	} {
+		if got := configSnakeCase(in); got != want {
+			t.Errorf("configSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
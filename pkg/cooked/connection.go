@@ -94,6 +94,50 @@ func WrapConnection(name string, db *sql.DB) {
 	ManagedConnections.Store(name, mc)
 }
 
+var (
+	openerMu         sync.Mutex
+	connectionOpener func(name string) (*sql.DB, error)
+)
+
+// SetConnectionOpener installs the function Connection uses to open a named
+// connection it hasn't seen yet. The generated app wires it to the
+// connections in config/database.go at startup.
+func SetConnectionOpener(open func(name string) (*sql.DB, error)) {
+	openerMu.Lock()
+	defer openerMu.Unlock()
+	connectionOpener = open
+}
+
+// Connection returns the pool for a named connection from
+// config/database.go. Connections are opened on first use and kept in
+// ManagedConnections, so a replica that no request asks for is never dialled.
+// An empty name returns the default DB.
+func Connection(name string) (*sql.DB, error) {
+	if name == "" {
+		return DB, nil
+	}
+	if mc := ManagedConnections.Load(name); mc != nil {
+		return mc.DB, nil
+	}
+	if conn, ok := Connections[name]; ok {
+		return conn, nil
+	}
+	openerMu.Lock()
+	defer openerMu.Unlock()
+	if mc := ManagedConnections.Load(name); mc != nil {
+		return mc.DB, nil
+	}
+	if connectionOpener == nil {
+		return nil, fmt.Errorf("pickle: unknown database connection %q", name)
+	}
+	db, err := connectionOpener(name)
+	if err != nil {
+		return nil, fmt.Errorf("pickle: opening database connection %q: %w", name, err)
+	}
+	WrapConnection(name, db)
+	return db, nil
+}
+
 // acquireConnection resolves a named connection from ManagedConnections,
 // retrying if the connection was retired between Load and Acquire.
 func acquireConnection(name string) *ManagedConnection {
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -34,6 +36,16 @@ type Context struct {
 	router        *Router
 	routeName     string
 	csrfToken     string
+	requestID     string
+
+	// Headers and cookies set from handlers or middleware, applied to the
+	// route's final Response when it is written. responseMu guards them
+	// because Timeout can leave a handler running after the response is
+	// built; responseDone drops whatever it sets from then on.
+	responseMu      sync.Mutex
+	responseDone    bool
+	responseHeaders map[string]string
+	responseCookies []*http.Cookie
 }
 
 // SetCSRFToken makes the verified session token available to compiled views.
@@ -119,6 +131,18 @@ func (c *Context) ParamUUID(name string) (uuid.UUID, error) {
 	return uuid.Parse(c.Param(name))
 }
 
+// ParamInt returns a URL path parameter parsed as a base-10 int.
+// Returns an error if the param is not a valid integer.
+func (c *Context) ParamInt(name string) (int, error) {
+	return strconv.Atoi(c.Param(name))
+}
+
+// ParamInt64 returns a URL path parameter parsed as a base-10 int64.
+// Returns an error if the param is not a valid integer.
+func (c *Context) ParamInt64(name string) (int64, error) {
+	return strconv.ParseInt(c.Param(name), 10, 64)
+}
+
 // ParamResourceID returns a URL path parameter parsed as a strict ResourceID.
 func (c *Context) ParamResourceID(name string) (ResourceID, error) {
 	return ParseResourceID(c.Param(name))
@@ -143,11 +167,105 @@ func (c *Context) Cookie(name string) (string, error) {
 	return cookie.Value, nil
 }
 
+// RequestCookie returns the named request cookie with all of its attributes,
+// or http.ErrNoCookie if not present.
+func (c *Context) RequestCookie(name string) (*http.Cookie, error) {
+	return c.request.Cookie(name)
+}
+
+// SetResponseHeader sets a header on the response this request returns,
+// whichever handler or middleware builds it. A header set on the Response
+// itself takes precedence.
+func (c *Context) SetResponseHeader(key, value string) {
+	c.responseMu.Lock()
+	defer c.responseMu.Unlock()
+	if c.responseDone {
+		return
+	}
+	if c.responseHeaders == nil {
+		c.responseHeaders = make(map[string]string)
+	}
+	c.responseHeaders[http.CanonicalHeaderKey(key)] = value
+}
+
+// SetCookie adds a cookie to the response this request returns, whichever
+// handler or middleware builds it.
+func (c *Context) SetCookie(cookie *http.Cookie) {
+	c.responseMu.Lock()
+	defer c.responseMu.Unlock()
+	if c.responseDone {
+		return
+	}
+	c.responseCookies = append(c.responseCookies, cookie)
+}
+
+// finishResponse drops response headers and cookies set from now on, and
+// those already set, so a timed-out response isn't built from a handler
+// that is still running.
+func (c *Context) finishResponse() {
+	c.responseMu.Lock()
+	defer c.responseMu.Unlock()
+	c.responseDone = true
+	c.responseHeaders, c.responseCookies = nil, nil
+}
+
+// applyResponseState returns resp with the headers and cookies set through
+// SetResponseHeader and SetCookie added. Headers already on resp win, and
+// cookies set on the context are written before the response's own.
+func (c *Context) applyResponseState(resp Response) Response {
+	c.responseMu.Lock()
+	defer c.responseMu.Unlock()
+	if len(c.responseHeaders) > 0 {
+		headers := make(map[string]string, len(c.responseHeaders)+len(resp.Headers))
+		for k, v := range c.responseHeaders {
+			headers[k] = v
+		}
+		for k, v := range resp.Headers {
+			delete(headers, http.CanonicalHeaderKey(k))
+			headers[k] = v
+		}
+		resp.Headers = headers
+	}
+	if len(c.responseCookies) > 0 {
+		resp.Cookies = append(append([]*http.Cookie{}, c.responseCookies...), resp.Cookies...)
+	}
+	return resp
+}
+
 // Query returns a query string parameter by name.
 func (c *Context) Query(name string) string {
 	return c.request.URL.Query().Get(name)
 }
 
+// Pagination reads ?page= and ?per_page= from the query string. Missing or
+// invalid values fall back to page 1 and defaultPerPage; perPage is clamped
+// to [1, maxPerPage]. page is capped so (page-1)*perPage never overflows,
+// so the result is always safe to feed into Limit/Offset.
+func (c *Context) Pagination(defaultPerPage, maxPerPage int) (page, perPage int) {
+	if maxPerPage < 1 {
+		maxPerPage = 1
+	}
+	perPage = defaultPerPage
+	if n, err := strconv.Atoi(c.Query("per_page")); err == nil {
+		perPage = n
+	}
+	if perPage < 1 {
+		perPage = 1
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	page = 1
+	if n, err := strconv.Atoi(c.Query("page")); err == nil && n > 1 {
+		page = n
+	}
+	if maxPage := math.MaxInt32 / perPage; page > maxPage {
+		page = maxPage
+	}
+	return page, perPage
+}
+
 // BearerToken extracts the token from the Authorization: Bearer header.
 func (c *Context) BearerToken() string {
 	h := c.request.Header.Get("Authorization")
@@ -157,6 +275,22 @@ func (c *Context) BearerToken() string {
 	return h[7:]
 }
 
+// ClientIP returns the IP address of the client that made the request.
+// X-Forwarded-For and X-Real-IP are honored only when the connection comes
+// from one of trustedProxies, given as IPs or CIDR ranges ("10.0.0.0/8").
+// With no trusted proxies the headers are ignored and the connection's
+// remote address is returned, since any client can set them. Behind a
+// proxy, the X-Forwarded-For chain is read right to left and the first
+// address that isn't a trusted proxy wins.
+//
+//	ip := ctx.ClientIP([]string{"10.0.0.0/8"})
+func (c *Context) ClientIP(trustedProxies []string) string {
+	nets, all := parseTrustedProxies(trustedProxies)
+	return resolveClientIP(c.request, func(ip string) bool {
+		return ipInNets(ip, nets, all)
+	})
+}
+
 // SetAuth stores authentication info (called by auth middleware).
 // Panics if claims is not *AuthInfo — use &AuthInfo{UserID: ..., Claims: ...} instead of a raw type.
 func (c *Context) SetAuth(claims any) {
@@ -189,19 +323,57 @@ type ResourceListQuery interface {
 	FetchResources(ownerID string) (any, error)
 }
 
+// ResourceOptions configures ctx.ResourceWith() and ctx.ResourcesWith().
+type ResourceOptions struct {
+	// OwnerOnly rejects records that belong to another user instead of
+	// serializing their public fields, responding 404 so the record's
+	// existence isn't leaked.
+	OwnerOnly bool
+	// ForbidNotOwned implies OwnerOnly but responds 403 instead of 404.
+	ForbidNotOwned bool
+}
+
+// OwnedResourceQuery is implemented by generated queries on owned tables.
+// ctx.ResourceWith() calls FetchOwnedResource instead of FetchResource when
+// ResourceOptions asks for owner-only access.
+type OwnedResourceQuery interface {
+	FetchOwnedResource(ownerID string) (any, error)
+}
+
+// OwnedResourceListQuery is the collection counterpart of OwnedResourceQuery.
+type OwnedResourceListQuery interface {
+	FetchOwnedResources(ownerID string) (any, error)
+}
+
+// notOwnerError is implemented by errors returned from FetchOwnedResource
+// when the record exists but the caller doesn't own it.
+type notOwnerError interface {
+	NotOwner() bool
+}
+
 // Resource executes a query that returns a single record, serialized based on
 // the authenticated user's ownership. Returns 404 if the record is not found.
 func (c *Context) Resource(q ResourceQuery) Response {
+	return c.ResourceWith(q, ResourceOptions{})
+}
+
+// ResourceWith is Resource with options controlling access to records owned
+// by other users.
+func (c *Context) ResourceWith(q ResourceQuery, opts ResourceOptions) Response {
 	ownerID := ""
 	if c.auth != nil {
 		ownerID = c.auth.UserID
 	}
-	result, err := q.FetchResource(ownerID)
+	fetch := q.FetchResource
+	if owned, ok := q.(OwnedResourceQuery); ok && opts.ownerOnly() {
+		fetch = owned.FetchOwnedResource
+	}
+	result, err := fetch(ownerID)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return c.NotFound("not found")
 		}
-		return c.Error(err)
+		return c.resourceError(err, opts)
 	}
 	return c.JSON(http.StatusOK, result)
 }
@@ -209,17 +381,42 @@ func (c *Context) Resource(q ResourceQuery) Response {
 // Resources executes a query that returns a collection of records, serialized
 // based on the authenticated user's ownership.
 func (c *Context) Resources(q ResourceListQuery) Response {
+	return c.ResourcesWith(q, ResourceOptions{})
+}
+
+// ResourcesWith is Resources with options controlling access to records
+// owned by other users. With OwnerOnly, one such record fails the request.
+func (c *Context) ResourcesWith(q ResourceListQuery, opts ResourceOptions) Response {
 	ownerID := ""
 	if c.auth != nil {
 		ownerID = c.auth.UserID
 	}
-	result, err := q.FetchResources(ownerID)
+	fetch := q.FetchResources
+	if owned, ok := q.(OwnedResourceListQuery); ok && opts.ownerOnly() {
+		fetch = owned.FetchOwnedResources
+	}
+	result, err := fetch(ownerID)
 	if err != nil {
-		return c.Error(err)
+		return c.resourceError(err, opts)
 	}
 	return c.JSON(http.StatusOK, result)
 }
 
+func (o ResourceOptions) ownerOnly() bool {
+	return o.OwnerOnly || o.ForbidNotOwned
+}
+
+func (c *Context) resourceError(err error, opts ResourceOptions) Response {
+	var notOwner notOwnerError
+	if errors.As(err, &notOwner) && notOwner.NotOwner() {
+		if opts.ForbidNotOwned {
+			return c.Forbidden("forbidden")
+		}
+		return c.NotFound("not found")
+	}
+	return c.Error(err)
+}
+
 // JSON returns a JSON response with the given status code and data.
 func (c *Context) JSON(status int, data any) Response {
 	return Response{
@@ -251,25 +448,59 @@ type httpStatusError interface {
 	HTTPStatus() int
 }
 
+// clientMessageError is implemented by errors that carry a message safe to
+// show clients, like UniqueViolationError's "email already exists".
+type clientMessageError interface {
+	ClientMessage() string
+}
+
 // Error maps an error to an appropriate HTTP response. Errors that implement
 // httpStatusError produce their own status code; unknown errors return 500.
+// The full error is logged through ctx.Logger(). Clients see the text of
+// 4xx errors (or their ClientMessage), but 5xx errors only say "internal
+// server error" unless APP_DEBUG is true, since their text often names
+// tables, queries or other internals.
 func (c *Context) Error(err error) Response {
+	status := http.StatusInternalServerError
 	var httpErr httpStatusError
 	if errors.As(err, &httpErr) {
-		status := httpErr.HTTPStatus()
-		if status >= 500 {
-			log.Printf("internal error: %v", err)
-			return c.JSON(status, map[string]string{"error": "internal server error"})
-		}
-		return c.JSON(status, map[string]string{"error": err.Error()})
+		status = httpErr.HTTPStatus()
 	}
 
-	log.Printf("internal error: %v", err)
-	return Response{
-		StatusCode: http.StatusInternalServerError,
-		Body:       map[string]string{"error": "internal server error"},
-		Headers:    map[string]string{"Content-Type": "application/json"},
+	logArgs := []any{"error", err, "status", status}
+	if c.request != nil {
+		logArgs = append(logArgs, "method", c.request.Method, "path", c.request.URL.Path)
+	}
+	if status >= 500 {
+		c.Logger().Error("internal error", logArgs...)
+	} else {
+		c.Logger().Warn("request error", logArgs...)
 	}
+
+	msg := "internal server error"
+	var clientErr clientMessageError
+	switch {
+	case status < 500 && errors.As(err, &clientErr):
+		msg = clientErr.ClientMessage()
+	case status < 500 && err != nil:
+		msg = err.Error()
+	case status < 500:
+		msg = strings.ToLower(http.StatusText(status))
+	case err != nil && appDebug():
+		msg = err.Error()
+	}
+	return c.JSON(status, map[string]string{"error": msg})
+}
+
+// appDebug reports whether APP_DEBUG is true, defaulting to true like the
+// scaffolded config's EnvBool("APP_DEBUG", true). It reads through env so
+// a value in .env counts without the HTTP package depending on app config.
+func appDebug() bool {
+	switch strings.ToLower(strings.TrimSpace(env("APP_DEBUG", "true"))) {
+	case "0", "false", "no":
+		return false
+	}
+	return true
 }
 
 // NotFound returns a 404 response with a message.
@@ -299,6 +530,19 @@ func (c *Context) Forbidden(msg string) Response {
 	}
 }
 
+// Authorize turns a policy decision into a response. When allowed is false it
+// returns a 403 response and false; otherwise a zero Response and true:
+//
+//	if resp, ok := ctx.Authorize(policies.PostPolicy{}.Update(ctx.Auth(), post)); !ok {
+//		return resp
+//	}
+func (c *Context) Authorize(allowed bool) (Response, bool) {
+	if !allowed {
+		return c.Forbidden("forbidden"), false
+	}
+	return Response{}, true
+}
+
 // BadRequest returns a 400 response with a message.
 func (c *Context) BadRequest(msg string) Response {
 	return Response{
@@ -36,6 +36,32 @@ func TestContextQuery(t *testing.T) {
 	}
 }
 
+func TestContextPagination(t *testing.T) {
+	tests := []struct {
+		query         string
+		page, perPage int
+	}{
+		{"", 1, 20},
+		{"?page=3&per_page=50", 3, 50},
+		{"?page=0&per_page=0", 1, 1},
+		{"?page=-4&per_page=-10", 1, 1},
+		{"?page=abc&per_page=xyz", 1, 20},
+		{"?per_page=1000", 1, 100},
+		{"?page=99999999999999999999", 1, 20},
+		{"?page=9223372036854775807&per_page=100", 21474836, 100},
+	}
+	for _, tt := range tests {
+		ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/posts"+tt.query, nil))
+		page, perPage := ctx.Pagination(20, 100)
+		if page != tt.page || perPage != tt.perPage {
+			t.Errorf("Pagination(%q) = (%d, %d), want (%d, %d)", tt.query, page, perPage, tt.page, tt.perPage)
+		}
+		if (page-1)*perPage < 0 {
+			t.Errorf("Pagination(%q) produces negative offset", tt.query)
+		}
+	}
+}
+
 func TestContextBearerToken(t *testing.T) {
 	tests := []struct {
 		header string
@@ -58,6 +84,40 @@ func TestContextBearerToken(t *testing.T) {
 	}
 }
 
+func TestContextClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		xff     string
+		xri     string
+		trusted []string
+		want    string
+	}{
+		{"no trusted proxies ignores headers", "203.0.113.5:4000", "198.51.100.1", "198.51.100.2", nil, "203.0.113.5"},
+		{"untrusted remote ignores headers", "203.0.113.5:4000", "198.51.100.1", "", []string{"10.0.0.0/8"}, "203.0.113.5"},
+		{"trusted remote uses forwarded for", "10.0.0.2:4000", "198.51.100.1", "", []string{"10.0.0.0/8"}, "198.51.100.1"},
+		{"skips trusted hops right to left", "10.0.0.2:4000", "1.1.1.1, 198.51.100.1, 10.0.0.3", "", []string{"10.0.0.0/8"}, "198.51.100.1"},
+		{"bare IP entry", "10.0.0.2:4000", "198.51.100.1", "", []string{"10.0.0.2"}, "198.51.100.1"},
+		{"falls back to real IP", "10.0.0.2:4000", "", "198.51.100.2", []string{"10.0.0.0/8"}, "198.51.100.2"},
+		{"trusted remote without headers", "10.0.0.2:4000", "", "", []string{"10.0.0.0/8"}, "10.0.0.2"},
+		{"invalid entries are skipped", "10.0.0.2:4000", "198.51.100.1", "", []string{"bogus", "10.0.0.0/99"}, "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remote
+		if tt.xff != "" {
+			r.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if tt.xri != "" {
+			r.Header.Set("X-Real-IP", tt.xri)
+		}
+		ctx := NewContext(httptest.NewRecorder(), r)
+		if got := ctx.ClientIP(tt.trusted); got != tt.want {
+			t.Errorf("%s: ClientIP() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestContextAuth(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
 
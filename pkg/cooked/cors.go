@@ -0,0 +1,99 @@
+package cooked
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the CORS middleware.
+type CORSOptions struct {
+	// AllowOrigins lists origins allowed to make cross-origin requests.
+	// "*" allows any origin.
+	AllowOrigins []string
+	// AllowMethods is returned on preflight responses. Defaults to
+	// GET, POST, PUT, PATCH, DELETE.
+	AllowMethods []string
+	// AllowHeaders is returned on preflight responses. When empty, the
+	// headers named in Access-Control-Request-Headers are echoed back.
+	AllowHeaders []string
+	// AllowCredentials permits cookies and Authorization headers. The
+	// request origin is echoed instead of "*" since browsers reject a
+	// wildcard with credentials.
+	AllowCredentials bool
+	// MaxAge tells browsers how long to cache a preflight result.
+	MaxAge time.Duration
+}
+
+// CORS returns a middleware that answers preflight requests and adds
+// Access-Control-Allow-* headers to responses for allowed origins. Attach it
+// before Auth so unauthenticated preflights are answered:
+//
+//	r.Group("/api", func(r *pickle.Router) {
+//	    r.Get("/posts", controllers.PostController{}.Index)
+//	}, pickle.CORS(pickle.CORSOptions{AllowOrigins: []string{"https://app.example.com"}}), middleware.Auth)
+func CORS(opts CORSOptions) MiddlewareFunc {
+	methods := opts.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(opts.AllowHeaders, ", ")
+
+	anyOrigin := false
+	allowed := make(map[string]bool, len(opts.AllowOrigins))
+	for _, origin := range opts.AllowOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+		allowed[strings.TrimRight(origin, "/")] = true
+	}
+
+	return func(ctx *Context, next func() Response) Response {
+		req := ctx.Request()
+		origin := req.Header.Get("Origin")
+		preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+
+		if origin == "" || (!anyOrigin && !allowed[origin]) {
+			if preflight {
+				// Disallowed preflight: answer without CORS headers so the
+				// browser blocks the actual request.
+				return Response{StatusCode: http.StatusNoContent, Headers: map[string]string{"Vary": "Origin"}}
+			}
+			return next()
+		}
+
+		headers := map[string]string{"Vary": "Origin"}
+		if anyOrigin && !opts.AllowCredentials {
+			headers["Access-Control-Allow-Origin"] = "*"
+		} else {
+			headers["Access-Control-Allow-Origin"] = origin
+		}
+		if opts.AllowCredentials {
+			headers["Access-Control-Allow-Credentials"] = "true"
+		}
+
+		if preflight {
+			headers["Access-Control-Allow-Methods"] = allowMethods
+			if allowHeaders != "" {
+				headers["Access-Control-Allow-Headers"] = allowHeaders
+			} else if requested := req.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				headers["Access-Control-Allow-Headers"] = requested
+			}
+			if opts.MaxAge > 0 {
+				headers["Access-Control-Max-Age"] = strconv.Itoa(int(opts.MaxAge.Seconds()))
+			}
+			return Response{StatusCode: http.StatusNoContent, Headers: headers}
+		}
+
+		resp := next()
+		if vary := resp.Headers["Vary"]; vary != "" && !strings.Contains(vary, "Origin") {
+			headers["Vary"] = vary + ", Origin"
+		}
+		for k, v := range headers {
+			resp = resp.Header(k, v)
+		}
+		return resp
+	}
+}
@@ -0,0 +1,139 @@
+package cooked
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func corsRequest(method, origin string) *Context {
+	req := httptest.NewRequest(method, "/api/posts", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	return NewContext(httptest.NewRecorder(), req)
+}
+
+func TestCORSPreflightShortCircuits(t *testing.T) {
+	mw := CORS(CORSOptions{
+		AllowOrigins: []string{"https://app.example.com"},
+		AllowHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:       10 * time.Minute,
+	})
+	ctx := corsRequest("OPTIONS", "https://app.example.com")
+	ctx.Request().Header.Set("Access-Control-Request-Method", "POST")
+
+	resp := mw(ctx, func() Response {
+		t.Fatal("preflight should not reach the handler")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", resp.StatusCode)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE",
+		"Access-Control-Allow-Headers": "Authorization, Content-Type",
+		"Access-Control-Max-Age":       "600",
+		"Vary":                         "Origin",
+	}
+	for k, v := range want {
+		if resp.Headers[k] != v {
+			t.Errorf("%s = %q, want %q", k, resp.Headers[k], v)
+		}
+	}
+}
+
+func TestCORSAddsHeadersToDownstreamResponse(t *testing.T) {
+	shared := map[string]string{"Content-Type": "application/json", "Vary": "Accept-Encoding"}
+	mw := CORS(CORSOptions{AllowOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+
+	resp := mw(corsRequest("GET", "https://app.example.com"), func() Response {
+		return Response{StatusCode: 200, Headers: shared}
+	})
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if resp.Headers["Access-Control-Allow-Origin"] != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q", resp.Headers["Access-Control-Allow-Origin"])
+	}
+	if resp.Headers["Access-Control-Allow-Credentials"] != "true" {
+		t.Errorf("Allow-Credentials = %q", resp.Headers["Access-Control-Allow-Credentials"])
+	}
+	if resp.Headers["Content-Type"] != "application/json" {
+		t.Errorf("downstream Content-Type lost: %v", resp.Headers)
+	}
+	if resp.Headers["Vary"] != "Accept-Encoding, Origin" {
+		t.Errorf("Vary = %q, want merged value", resp.Headers["Vary"])
+	}
+	if _, leaked := shared["Access-Control-Allow-Origin"]; leaked {
+		t.Error("CORS headers were written into the handler's shared headers map")
+	}
+}
+
+func TestCORSWildcardWithCredentialsEchoesOrigin(t *testing.T) {
+	mw := CORS(CORSOptions{AllowOrigins: []string{"*"}})
+	resp := mw(corsRequest("GET", "https://any.example.com"), func() Response { return Response{StatusCode: 200} })
+	if resp.Headers["Access-Control-Allow-Origin"] != "*" {
+		t.Errorf("Allow-Origin = %q, want *", resp.Headers["Access-Control-Allow-Origin"])
+	}
+
+	mw = CORS(CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	resp = mw(corsRequest("GET", "https://any.example.com"), func() Response { return Response{StatusCode: 200} })
+	if resp.Headers["Access-Control-Allow-Origin"] != "https://any.example.com" {
+		t.Errorf("Allow-Origin = %q, want echoed origin", resp.Headers["Access-Control-Allow-Origin"])
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	mw := CORS(CORSOptions{AllowOrigins: []string{"https://app.example.com"}})
+
+	resp := mw(corsRequest("GET", "https://evil.example.com"), func() Response { return Response{StatusCode: 200} })
+	if resp.StatusCode != 200 {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if _, ok := resp.Headers["Access-Control-Allow-Origin"]; ok {
+		t.Error("disallowed origin should not get Allow-Origin")
+	}
+
+	ctx := corsRequest("OPTIONS", "https://evil.example.com")
+	ctx.Request().Header.Set("Access-Control-Request-Method", "DELETE")
+	resp = mw(ctx, func() Response {
+		t.Fatal("disallowed preflight should not reach the handler")
+		return Response{}
+	})
+	if _, ok := resp.Headers["Access-Control-Allow-Methods"]; ok {
+		t.Error("disallowed preflight should not get Allow-Methods")
+	}
+}
+
+func TestCORSPreflightThroughRouter(t *testing.T) {
+	t.Setenv("RATE_LIMIT", "false")
+	cors := CORS(CORSOptions{AllowOrigins: []string{"https://app.example.com"}})
+	auth := MiddlewareFunc(func(ctx *Context, next func() Response) Response {
+		return ctx.Unauthorized("no token")
+	})
+	r := Routes(func(r *Router) {
+		r.Group("/api", func(r *Router) {
+			r.Post("/posts", noop)
+		}, cors, auth)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	req := httptest.NewRequest("OPTIONS", "/api/posts", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
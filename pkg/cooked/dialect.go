@@ -0,0 +1,132 @@
+package cooked
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect renders the driver-specific parts of the SQL the QueryBuilder
+// writes: identifier quoting and bind placeholders.
+type Dialect interface {
+	// Quote quotes a single identifier, escaping any quote characters in it.
+	Quote(ident string) string
+	// Placeholder returns the bind placeholder for the nth argument, from 1.
+	Placeholder(n int) string
+}
+
+// DialectFor returns the dialect for a configured database driver: "mysql"
+// quotes with backticks and binds with ?, "sqlite" quotes with double quotes
+// and binds with ?, and anything else is treated as PostgreSQL.
+func DialectFor(driver string) Dialect {
+	switch driver {
+	case "mysql":
+		return mysqlDialect{}
+	case "sqlite", "sqlite3":
+		return sqliteDialect{}
+	default:
+		return postgresDialect{}
+	}
+}
+
+// currentDialect is the dialect of the configured DatabaseDriver.
+func currentDialect() Dialect {
+	return DialectFor(DatabaseDriver)
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Quote(ident string) string { return quoteWith(ident, `"`) }
+func (postgresDialect) Placeholder(n int) string  { return "$" + strconv.Itoa(n) }
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Quote(ident string) string { return quoteWith(ident, "`") }
+func (mysqlDialect) Placeholder(int) string    { return "?" }
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Quote(ident string) string { return quoteWith(ident, `"`) }
+func (sqliteDialect) Placeholder(int) string    { return "?" }
+
+// fragmentDialect quotes like the wrapped dialect but writes $n placeholders,
+// for subqueries that bindFragment later embeds in the outer statement.
+type fragmentDialect struct{ Dialect }
+
+func (fragmentDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func quoteWith(ident, q string) string {
+	return q + strings.ReplaceAll(ident, q, q+q) + q
+}
+
+// quoteQualified quotes each dot-separated part of a name like public.users.
+func quoteQualified(d Dialect, name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = d.Quote(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteList quotes each column and joins them with commas.
+func quoteList(d Dialect, cols []string) string {
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = quoteQualified(d, col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// bindClause replaces each ? in a compiled row-policy clause with the
+// dialect's placeholder, numbering from start.
+func bindClause(d Dialect, clause string, start int) string {
+	var b strings.Builder
+	n := start
+	for _, r := range clause {
+		if r == '?' {
+			b.WriteString(d.Placeholder(n))
+			n++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// bindFragment embeds a raw SQL fragment written with $1, $2, ... after the
+// arguments already in args. Each placeholder becomes the dialect's
+// placeholder for the next argument and binds the value it refers to, so
+// fragments work unchanged on drivers that bind with ?. Placeholders inside
+// single-quoted string literals are left alone. A fragment without
+// placeholders appends its values as given.
+func bindFragment(d Dialect, fragment string, values []any, args *[]any) string {
+	var b strings.Builder
+	bound := false
+	inString := false
+	for i := 0; i < len(fragment); i++ {
+		ch := fragment[i]
+		if ch == '\'' {
+			inString = !inString
+		}
+		if ch != '$' || inString {
+			b.WriteByte(ch)
+			continue
+		}
+		j := i + 1
+		for j < len(fragment) && fragment[j] >= '0' && fragment[j] <= '9' {
+			j++
+		}
+		n, _ := strconv.Atoi(fragment[i+1 : j])
+		if n < 1 || n > len(values) {
+			b.WriteByte(ch)
+			continue
+		}
+		*args = append(*args, values[n-1])
+		b.WriteString(d.Placeholder(len(*args)))
+		bound = true
+		i = j - 1
+	}
+	if !bound {
+		*args = append(*args, values...)
+	}
+	return b.String()
+}
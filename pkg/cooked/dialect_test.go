@@ -0,0 +1,171 @@
+package cooked
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func withDatabaseDriver(t *testing.T, driver string) {
+	t.Helper()
+	old := DatabaseDriver
+	DatabaseDriver = driver
+	t.Cleanup(func() { DatabaseDriver = old })
+}
+
+func TestDialectForDriver(t *testing.T) {
+	tests := []struct {
+		driver, quoted, placeholder string
+	}{
+		{"pgsql", `"or""der"`, "$3"},
+		{"", `"or""der"`, "$3"},
+		{"mysql", "`or\"der`", "?"},
+		{"sqlite", `"or""der"`, "?"},
+	}
+	for _, tt := range tests {
+		d := DialectFor(tt.driver)
+		if got := d.Quote(`or"der`); got != tt.quoted {
+			t.Errorf("%q Quote = %s, want %s", tt.driver, got, tt.quoted)
+		}
+		if got := d.Placeholder(3); got != tt.placeholder {
+			t.Errorf("%q Placeholder(3) = %s, want %s", tt.driver, got, tt.placeholder)
+		}
+	}
+	if got := DialectFor("mysql").Quote("a`b"); got != "`a``b`" {
+		t.Errorf("mysql Quote escaping = %s", got)
+	}
+}
+
+func TestBuildSelectUsesMySQLDialect(t *testing.T) {
+	withDatabaseDriver(t, "mysql")
+	q := Query[testModel]("users")
+	q.where("order", 3)
+	q.WhereRaw("lower(email) = lower($2) OR name = $1", "Ada", "ada@example.com")
+	q.whereIn("id", []string{"a", "b"})
+	q.OrderBy("order", "desc").Limit(5)
+
+	sql, args := q.buildSelect()
+	want := "SELECT `id`, `name`, `email` FROM `users` WHERE `order` = ? AND (lower(email) = lower(?) OR name = ?) AND `id` IN (?, ?) ORDER BY `order` DESC LIMIT 5"
+	if sql != want {
+		t.Fatalf("mysql select =\n%s\nwant\n%s", sql, want)
+	}
+	if len(args) != 5 || args[0] != 3 || args[1] != "ada@example.com" || args[2] != "Ada" || args[4] != "b" {
+		t.Fatalf("mysql args = %#v, want raw arguments in placeholder order", args)
+	}
+
+	update, args := buildUpdate("users", &testModel{ID: "u-1", Name: "Ada"}, nil, "tenant_id = ?", []any{"t-1"})
+	if update != "UPDATE `users` SET `name` = ?, `email` = ? WHERE tenant_id = ?" || len(args) != 3 {
+		t.Fatalf("mysql update = %q %#v", update, args)
+	}
+}
+
+func TestImmutableQueryUsesMySQLDialect(t *testing.T) {
+	withDatabaseDriver(t, "mysql")
+	q := ImmutableQuery[testModel]("order", true)
+	q.where("name", "Ada")
+	q.whereIn("email", []string{"a@example.com", "b@example.com"})
+	q.OrderBy("name", "ASC").Limit(5)
+
+	sql, args := q.buildSelect(0)
+	want := "SELECT t.`id`, t.`name`, t.`email` FROM `order` t WHERE t.version_id = (SELECT version_id FROM `order` WHERE id = t.id ORDER BY version_id DESC LIMIT 1)" +
+		" AND `name` = ? AND `email` IN (?, ?) AND t.deleted_at IS NULL ORDER BY `name` ASC LIMIT 5"
+	if sql != want {
+		t.Fatalf("mysql immutable select =\n%s\nwant\n%s", sql, want)
+	}
+	if len(args) != 3 || args[0] != "Ada" || args[2] != "b@example.com" {
+		t.Fatalf("mysql immutable args = %#v", args)
+	}
+
+	count, _ := q.buildCount()
+	if !strings.HasPrefix(count, "SELECT COUNT(*) FROM (SELECT t.id FROM `order` t") || strings.Contains(count, "$") {
+		t.Errorf("mysql immutable count = %s", count)
+	}
+	sum, _ := q.buildAggregate("SUM", "name")
+	if !strings.HasPrefix(sum, "SELECT SUM(_dedup.`name`) FROM (SELECT t.`name` FROM `order` t") || strings.Contains(sum, "$") {
+		t.Errorf("mysql immutable aggregate = %s", sum)
+	}
+}
+
+func TestWhereHasQuotesForDialect(t *testing.T) {
+	withDatabaseDriver(t, "sqlite")
+	withTestRelations(t)
+	q := Query[testModel]("users")
+	q.where("active", true)
+	q.WhereHas("posts", func(p *QueryBuilder[any]) { p.where("status", "published") })
+
+	sql, args := q.buildSelect()
+	want := `WHERE "active" = ? AND EXISTS (SELECT 1 FROM "posts" WHERE "posts"."user_id" = "users"."id" AND "status" = ?)`
+	if sql[len(sql)-len(want):] != want {
+		t.Fatalf("sqlite WhereHas = %q, want suffix %q", sql, want)
+	}
+	if len(args) != 2 || args[1] != "published" {
+		t.Fatalf("sqlite WhereHas args = %#v", args)
+	}
+}
+
+func TestBindFragmentRepeatsReusedPlaceholders(t *testing.T) {
+	args := []any{"first"}
+	got := bindFragment(DialectFor("mysql"), "a = $1 OR b = $1 OR c = '$1'", []any{"x"}, &args)
+	if got != "a = ? OR b = ? OR c = '$1'" || len(args) != 3 || args[1] != "x" || args[2] != "x" {
+		t.Fatalf("bindFragment = %q %#v", got, args)
+	}
+
+	args = nil
+	got = bindFragment(DialectFor("pgsql"), "deleted_at IS NULL", nil, &args)
+	if got != "deleted_at IS NULL" || len(args) != 0 {
+		t.Fatalf("bindFragment without placeholders = %q %#v", got, args)
+	}
+}
+
+type orderedItem struct {
+	ID    int64  `db:"id"`
+	Order int    `db:"order"`
+	Group string `db:"group"`
+}
+
+func TestReservedWordColumnsOnSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, "order" INTEGER NOT NULL, "group" TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldDriver := DB, DatabaseDriver
+	DB, DatabaseDriver = db, "sqlite"
+	t.Cleanup(func() { DB, DatabaseDriver = oldDB, oldDriver })
+
+	for i, group := range []string{"b", "a", "b"} {
+		item := &orderedItem{Order: i + 1, Group: group}
+		if err := Query[orderedItem]("items").Create(item); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if item.ID == 0 {
+			t.Fatal("Create did not scan the generated id back")
+		}
+	}
+
+	rows, err := Query[orderedItem]("items").where("group", "b").OrderBy("order", "DESC").All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Order != 3 || rows[1].Order != 1 {
+		t.Fatalf("All = %+v, want group b by order descending", rows)
+	}
+
+	first := rows[1]
+	first.Group = "c"
+	if err := Query[orderedItem]("items").UpdateColumns(&first, "group"); err != nil {
+		t.Fatalf("UpdateColumns: %v", err)
+	}
+	if n, err := Query[orderedItem]("items").where("group", "c").Count(); err != nil || n != 1 {
+		t.Fatalf("Count after update = %d, %v", n, err)
+	}
+	if err := Query[orderedItem]("items").whereOp("order", ">", 1).Delete(nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, err := Query[orderedItem]("items").Count(); err != nil || n != 1 {
+		t.Fatalf("Count after delete = %d, %v", n, err)
+	}
+}
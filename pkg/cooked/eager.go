@@ -0,0 +1,296 @@
+package cooked
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxEagerLoadDepth is the most relations one EagerLoad path may chain.
+// Paths are spelled out, so a self-referential relation can't loop
+// ("parent.parent" stops after two hops), but every hop is another query
+// and a deep path over a self-reference fans out quickly.
+const maxEagerLoadDepth = 3
+
+// eagerNode is one relation to load and the relations to load below it.
+// Paths sharing a prefix ("posts", "posts.comments") share nodes, so each
+// relation is queried once.
+type eagerNode struct {
+	relation queryRelation
+	children map[string]*eagerNode
+}
+
+// eagerTree resolves EagerLoad paths starting at table against the
+// registered relations. A path is split on dots, but a relation's own name
+// may contain one ("posts.author_id"), so at each hop the longest name
+// registered on the current table wins.
+func eagerTree(table string, paths []string) (*eagerNode, error) {
+	root := &eagerNode{children: map[string]*eagerNode{}}
+	for _, path := range paths {
+		node, current := root, table
+		parts := strings.Split(path, ".")
+		for depth := 1; len(parts) > 0; depth++ {
+			if depth > maxEagerLoadDepth {
+				return nil, fmt.Errorf("pickle: EagerLoad(%q) is more than %d relations deep", path, maxEagerLoadDepth)
+			}
+			name, rel, ok := "", queryRelation{}, false
+			for n := len(parts); n > 0 && !ok; n-- {
+				name = strings.Join(parts[:n], ".")
+				rel, ok = queryRelations[current][name]
+				if ok {
+					parts = parts[n:]
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("pickle: EagerLoad(%q): %s has no relation %q", path, current, parts[0])
+			}
+			child := node.children[name]
+			if child == nil {
+				child = &eagerNode{relation: rel, children: map[string]*eagerNode{}}
+				node.children[name] = child
+			}
+			node, current = child, rel.table
+		}
+	}
+	return root, nil
+}
+
+// loadEager fills the relation fields of records, which are addressable
+// structs of table, for every EagerLoad path. Each relation is one batched
+// IN query over the keys of all records at that level (two for a
+// many-to-many relation, which reads the pivot table first), and the
+// related table's row policy is applied with policy.
+func loadEager(db dbExecutor, table string, paths []string, policy *PolicyContext, records []reflect.Value) error {
+	if len(paths) == 0 || len(records) == 0 {
+		return nil
+	}
+	root, err := eagerTree(table, paths)
+	if err != nil {
+		return err
+	}
+	return loadEagerNode(db, root, policy, records)
+}
+
+func loadEagerNode(db dbExecutor, node *eagerNode, policy *PolicyContext, records []reflect.Value) error {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := node.children[name]
+		if err := loadRelation(db, name, child, policy, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRelation loads one relation for records, then the relations nested
+// under it, and assigns the results to the field tagged rel:"{name}": a
+// slice for to-many relations, a pointer for belongs-to.
+func loadRelation(db dbExecutor, name string, node *eagerNode, policy *PolicyContext, records []reflect.Value) error {
+	rel := node.relation
+	rt := records[0].Type()
+	field, ok := relationFieldIndex(rt, name)
+	if !ok {
+		return fmt.Errorf("pickle: EagerLoad: %s has no field tagged rel:%q to load %s into", rt.Name(), name, name)
+	}
+	fieldType := rt.Field(field).Type
+	if fieldType.Kind() != reflect.Slice && fieldType.Kind() != reflect.Pointer {
+		return fmt.Errorf("pickle: EagerLoad: %s.%s must be a slice or pointer", rt.Name(), rt.Field(field).Name)
+	}
+	elem := fieldType.Elem()
+	parentColumn, ok := scanFieldIndex(rt)[rel.parentColumn]
+	if !ok {
+		return fmt.Errorf("pickle: EagerLoad: %s has no field for column %s", rt.Name(), rel.parentColumn)
+	}
+	childColumn, ok := scanFieldIndex(elem)[rel.column]
+	if !ok {
+		return fmt.Errorf("pickle: EagerLoad: %s has no field for column %s", elem.Name(), rel.column)
+	}
+
+	keys := distinctKeys(records, parentColumn)
+	// owners maps a related row's key to the keys of the records it belongs
+	// to. Without a pivot the two are the same value.
+	owners := map[string][]string{}
+	var childKeys []any
+	if rel.pivot == "" {
+		childKeys = keys
+	} else if len(keys) > 0 {
+		pairs, err := queryEagerPivot(db, rel, policy, keys)
+		if err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		for _, pair := range pairs {
+			k := eagerKey(reflect.ValueOf(pair[1]))
+			owners[k] = append(owners[k], eagerKey(reflect.ValueOf(pair[0])))
+			if !seen[k] {
+				seen[k] = true
+				childKeys = append(childKeys, pair[1])
+			}
+		}
+	}
+
+	var children []reflect.Value
+	if len(childKeys) > 0 {
+		var err error
+		if children, err = queryEagerRows(db, rel, elem, policy, childKeys); err != nil {
+			return err
+		}
+		if err := loadEagerNode(db, node, policy, children); err != nil {
+			return err
+		}
+	}
+
+	related := map[string][]reflect.Value{}
+	for _, child := range children {
+		k := eagerKey(child.Field(childColumn))
+		parents := owners[k]
+		if rel.pivot == "" {
+			parents = []string{k}
+		}
+		for _, p := range parents {
+			related[p] = append(related[p], child)
+		}
+	}
+	for _, record := range records {
+		matches := related[eagerKey(record.Field(parentColumn))]
+		dest := record.Field(field)
+		if fieldType.Kind() == reflect.Pointer {
+			if len(matches) > 0 {
+				dest.Set(matches[0].Addr())
+			} else {
+				dest.Set(reflect.Zero(fieldType))
+			}
+			continue
+		}
+		list := reflect.MakeSlice(fieldType, 0, len(matches))
+		for _, m := range matches {
+			list = reflect.Append(list, m)
+		}
+		dest.Set(list)
+	}
+	return nil
+}
+
+// queryEagerRows selects the rows of rel.table whose rel.column is one of
+// keys, in primary key order, as addressable values of elem.
+func queryEagerRows(db dbExecutor, rel queryRelation, elem reflect.Type, policy *PolicyContext, keys []any) ([]reflect.Value, error) {
+	zero := reflect.New(elem).Interface()
+	q := &QueryBuilder[any]{table: rel.table, policyContext: policy, selectedCols: dbColumns(zero)}
+	q.whereIn(rel.column, keys)
+	for _, col := range primaryKeyColumns(zero) {
+		q.orderBy = append(q.orderBy, col+" ASC")
+	}
+	if err := q.preparePolicy("select"); err != nil {
+		return nil, err
+	}
+	query, args := q.buildSelect()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	plan := scanPlan(elem, cols)
+	var out []reflect.Value
+	for rows.Next() {
+		item := reflect.New(elem).Elem()
+		if err := rows.Scan(scanDestPlan(item, plan)...); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// queryEagerPivot reads the (parent key, related key) pairs of a
+// many-to-many relation for the given parent keys.
+func queryEagerPivot(db dbExecutor, rel queryRelation, policy *PolicyContext, keys []any) ([][2]any, error) {
+	q := &QueryBuilder[any]{table: rel.pivot, policyContext: policy, selectedCols: []string{rel.pivotParentColumn, rel.pivotColumn}}
+	q.whereIn(rel.pivotParentColumn, keys)
+	if err := q.preparePolicy("select"); err != nil {
+		return nil, err
+	}
+	query, args := q.buildSelect()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var pairs [][2]any
+	for rows.Next() {
+		var pair [2]any
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, rows.Err()
+}
+
+// eagerRecords returns the addressable elements of results for loadEager.
+func eagerRecords[T any](results []T) []reflect.Value {
+	records := make([]reflect.Value, len(results))
+	for i := range results {
+		records[i] = reflect.ValueOf(&results[i]).Elem()
+	}
+	return records
+}
+
+// relationFieldIndex returns the index of the field tagged rel:"{name}".
+func relationFieldIndex(rt reflect.Type, name string) (int, bool) {
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).Tag.Get("rel") == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// distinctKeys returns the non-NULL values of field across records, once
+// each, for an IN list.
+func distinctKeys(records []reflect.Value, field int) []any {
+	seen := map[string]bool{}
+	var keys []any
+	for _, record := range records {
+		v := record.Field(field)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		k := eagerKey(v)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, v.Interface())
+		}
+	}
+	return keys
+}
+
+// eagerKey is the string a key value is matched by. Keys are compared as
+// text so that a uuid.UUID field, a *uuid.UUID foreign key and the []byte
+// a driver returns for a pivot column all agree. NULL is "".
+func eagerKey(v reflect.Value) string {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+	if b, ok := v.Interface().([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,131 @@
+package cooked
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type eagerUser struct {
+	ID    string      `db:"id" pickle:"pk"`
+	Name  string      `db:"name"`
+	Posts []eagerPost `rel:"posts"`
+}
+
+type eagerPost struct {
+	ID       string         `db:"id" pickle:"pk"`
+	UserID   *string        `db:"user_id"`
+	User     *eagerUser     `rel:"user"`
+	Comments []eagerComment `rel:"comments"`
+	Tags     []eagerTag     `rel:"tags"`
+}
+
+type eagerComment struct {
+	ID     string `db:"id" pickle:"pk"`
+	PostID string `db:"post_id"`
+	Body   string `db:"body"`
+}
+
+type eagerTag struct {
+	ID   string `db:"id" pickle:"pk"`
+	Name string `db:"name"`
+}
+
+func TestEagerLoadNestedPath(t *testing.T) {
+	withTestRelations(t)
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name" FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("u1", "Ann").AddRow("u2", "Bob").AddRow("u3", "Cy"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "user_id" FROM "posts" WHERE "user_id" IN ($1, $2, $3) ORDER BY "id" ASC`)).
+		WithArgs("u1", "u2", "u3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("p1", "u1").AddRow("p2", "u1").AddRow("p3", "u2"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "post_id", "body" FROM "comments" WHERE "post_id" IN ($1, $2, $3) ORDER BY "id" ASC`)).
+		WithArgs("p1", "p2", "p3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "body"}).AddRow("c1", "p1", "first").AddRow("c2", "p3", "second").AddRow("c3", "p1", "third"))
+
+	users, err := Query[eagerUser]("users").EagerLoad("posts").EagerLoad("posts.comments").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 || len(users[0].Posts) != 2 || len(users[1].Posts) != 1 || len(users[2].Posts) != 0 {
+		t.Fatalf("posts not grouped by user: %+v", users)
+	}
+	if c := users[0].Posts[0].Comments; len(c) != 2 || c[0].Body != "first" || c[1].Body != "third" {
+		t.Errorf("p1 comments = %+v", c)
+	}
+	if len(users[0].Posts[1].Comments) != 0 || len(users[1].Posts[0].Comments) != 1 {
+		t.Errorf("comments not grouped by post: %+v", users)
+	}
+}
+
+func TestEagerLoadBelongsToAndPivot(t *testing.T) {
+	withTestRelations(t)
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "user_id" FROM "posts" WHERE "id" = $1 LIMIT 1`)).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("p1", "u1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "post_id", "tag_id" FROM "post_tag" WHERE "post_id" IN ($1)`)).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}).AddRow([]byte("p1"), []byte("t1")).AddRow([]byte("p1"), []byte("t2")))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name" FROM "tags" WHERE "id" IN ($1, $2) ORDER BY "id" ASC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("t1", "go").AddRow("t2", "sql"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name" FROM "users" WHERE "id" IN ($1) ORDER BY "id" ASC`)).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("u1", "Ann"))
+
+	post, err := Query[eagerPost]("posts").where("id", "p1").EagerLoad("user").EagerLoad("tags").First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if post.User == nil || post.User.Name != "Ann" {
+		t.Errorf("user = %+v", post.User)
+	}
+	if len(post.Tags) != 2 || post.Tags[0].Name != "go" || post.Tags[1].Name != "sql" {
+		t.Errorf("tags = %+v", post.Tags)
+	}
+}
+
+func TestEagerLoadSkipsNullForeignKeys(t *testing.T) {
+	withTestRelations(t)
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "user_id" FROM "posts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("p1", nil))
+
+	posts, err := Query[eagerPost]("posts").EagerLoad("user").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || posts[0].User != nil {
+		t.Errorf("posts = %+v, want no user and no users query", posts)
+	}
+}
+
+func TestEagerLoadErrors(t *testing.T) {
+	withTestRelations(t)
+	registerRelation("users", "manager", "users", "id", "manager_id")
+	for path, want := range map[string]string{
+		"followers":                       `users has no relation "followers"`,
+		"posts.likes":                     `posts has no relation "likes"`,
+		"manager.manager.manager.manager": "more than 3 relations deep",
+		"manager":                         `no field tagged rel:"manager"`,
+	} {
+		mock := withCountTestDB(t, "pgsql")
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name" FROM "users"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("u1", "Ann"))
+		_, err := Query[eagerUser]("users").EagerLoad(path).All()
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("EagerLoad(%q) err = %v, want %q", path, err, want)
+		}
+	}
+}
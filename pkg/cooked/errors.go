@@ -1,7 +1,10 @@
 package cooked
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -15,6 +18,19 @@ type UnauthorizedActionError struct{}
 func (e *UnauthorizedActionError) Error() string   { return "unauthorized: action denied by gate" }
 func (e *UnauthorizedActionError) HTTPStatus() int { return 403 }
 
+// NotOwnerError is returned by FetchOwnedResource when the record exists but
+// belongs to another user. ctx.ResourceWith() reports it as 404, or 403 with
+// ResourceOptions.ForbidNotOwned.
+type NotOwnerError struct {
+	Table string
+}
+
+func (e *NotOwnerError) HTTPStatus() int { return 404 }
+func (e *NotOwnerError) NotOwner() bool  { return true }
+func (e *NotOwnerError) Error() string {
+	return fmt.Sprintf("%s record belongs to another owner", e.Table)
+}
+
 // StaleVersionError is returned when an immutable table Update() detects that
 // the entity's version_id has changed since the caller read it. This means
 // another write occurred between the read and update — the caller must re-read
@@ -87,6 +103,196 @@ func (e *LockOutsideTransactionError) Error() string {
 	)
 }
 
+// UniqueViolationError is returned by Create, CreateMany, Update and
+// UpdateColumns when the write breaks a unique constraint. ctx.Error reports
+// it as 409 Conflict with a message naming the column ("email already
+// exists") but never the duplicated value.
+type UniqueViolationError struct {
+	Table      string
+	Column     string // empty when the driver error doesn't name a single column
+	Constraint string
+	Err        error
+}
+
+func (e *UniqueViolationError) HTTPStatus() int { return 409 }
+func (e *UniqueViolationError) Unwrap() error   { return e.Err }
+func (e *UniqueViolationError) Error() string {
+	return fmt.Sprintf("unique violation on %s: %v", e.Table, e.Err)
+}
+
+// ClientMessage is the message ctx.Error sends instead of the generic
+// "conflict". It is safe to show clients.
+func (e *UniqueViolationError) ClientMessage() string {
+	if e.Column == "" {
+		return "record already exists"
+	}
+	return e.Column + " already exists"
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation —
+// Postgres SQLSTATE 23505 (pgx or lib/pq), MySQL error 1062, or SQLite's
+// "UNIQUE constraint failed" — and, when the driver error or constraint name
+// identifies it, the conflicting column.
+func IsUniqueViolation(err error) (column string, ok bool) {
+	var uve *UniqueViolationError
+	if errors.As(err, &uve) {
+		return uve.Column, true
+	}
+	v, ok := detectUniqueViolation(err)
+	if ok && v.Column == "" && v.Detail == "" {
+		v.Column = constraintColumn(v.Table, v.Constraint)
+	}
+	return v.Column, ok
+}
+
+// mapUniqueError wraps a unique constraint violation from a write on table in
+// a UniqueViolationError. Other errors are returned unchanged.
+func mapUniqueError(table string, err error) error {
+	v, ok := detectUniqueViolation(err)
+	if !ok {
+		return err
+	}
+	if v.Table == "" {
+		v.Table = table
+	}
+	if v.Column == "" && v.Detail == "" {
+		v.Column = constraintColumn(v.Table, v.Constraint)
+	}
+	return &UniqueViolationError{Table: v.Table, Column: v.Column, Constraint: v.Constraint, Err: err}
+}
+
+// uniqueViolation is what a driver error says about a unique violation.
+type uniqueViolation struct {
+	Table      string
+	Column     string
+	Constraint string
+	Detail     string // Postgres "Key (email)=(...) already exists."
+}
+
+var (
+	pgDetailKey       = regexp.MustCompile(`^Key \(([^)]*)\)=`)
+	pgConstraintName  = regexp.MustCompile(`unique constraint "([^"]+)"`)
+	mysqlDuplicateKey = regexp.MustCompile(`Duplicate entry .* for key '([^']+)'`)
+	sqliteUniqueCols  = regexp.MustCompile(`UNIQUE constraint failed: (.+)$`)
+)
+
+// detectUniqueViolation recognises unique violations by driver interface
+// first, like mapLockError, then by message. pgx exposes the constraint,
+// table and detail as *pgconn.PgError fields, which are read by reflection so
+// the generated code doesn't import a driver.
+func detectUniqueViolation(err error) (uniqueViolation, bool) {
+	var v uniqueViolation
+	if err == nil {
+		return v, false
+	}
+
+	// lib/pq: .Get(byte) string with 'C' = code, 'n' = constraint, 't' = table, 'D' = detail
+	type pqErr interface {
+		Get(byte) string
+	}
+	// pgx: .SQLState() string, or .Code() string
+	type sqlStateErr interface {
+		SQLState() string
+	}
+	type pgxErr interface {
+		Code() string
+	}
+
+	var pq pqErr
+	var sqlState sqlStateErr
+	var pgx pgxErr
+	msg := err.Error()
+	switch {
+	case errors.As(err, &pq):
+		if pq.Get('C') != "23505" {
+			return v, false
+		}
+		v.Constraint, v.Table, v.Detail = pq.Get('n'), pq.Get('t'), pq.Get('D')
+	case errors.As(err, &sqlState) && sqlState.SQLState() == "23505",
+		errors.As(err, &pgx) && pgx.Code() == "23505":
+		v.Constraint = errorField(err, "ConstraintName")
+		v.Table = errorField(err, "TableName")
+		v.Detail = errorField(err, "Detail")
+	case errorField(err, "Number") == "1062" || strings.Contains(msg, "Error 1062"):
+		// MySQL names the key "table.key" (8.0+) or just "key".
+		if m := mysqlDuplicateKey.FindStringSubmatch(msg); m != nil {
+			v.Constraint = m[1]
+			if table, key, ok := strings.Cut(m[1], "."); ok {
+				v.Table, v.Constraint = table, key
+			}
+		}
+	case strings.Contains(msg, "UNIQUE constraint failed"):
+		// SQLite has no constraint name; it lists "table.column" for every
+		// column in the constraint.
+		if m := sqliteUniqueCols.FindStringSubmatch(msg); m != nil && !strings.Contains(m[1], ",") {
+			v.Table, v.Column, _ = strings.Cut(m[1], ".")
+		}
+		return v, true
+	case strings.Contains(msg, "duplicate key value violates unique constraint"):
+		// pgx or pq error flattened by fmt.Errorf without %w
+	default:
+		return v, false
+	}
+
+	if v.Constraint == "" {
+		if m := pgConstraintName.FindStringSubmatch(msg); m != nil {
+			v.Constraint = m[1]
+		}
+	}
+	if m := pgDetailKey.FindStringSubmatch(v.Detail); m != nil && !strings.Contains(m[1], ",") {
+		v.Column = strings.Trim(m[1], `"`)
+	}
+	return v, true
+}
+
+// errorField returns a string or integer field of a driver error struct, or
+// "" when err (or an error it wraps) has no such field.
+func errorField(err error, name string) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		rv := reflect.ValueOf(err)
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			continue
+		}
+		f := rv.FieldByName(name)
+		if !f.IsValid() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.String:
+			return f.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return fmt.Sprint(f.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return fmt.Sprint(f.Uint())
+		}
+	}
+	return ""
+}
+
+// constraintColumn derives a column from a constraint name that follows the
+// common naming schemes: Postgres's users_email_key, Pickle's unique index
+// users_email_idx, and MySQL's bare column key. Names for multi-column
+// constraints come back joined by underscores.
+func constraintColumn(table, constraint string) string {
+	if constraint == "" || constraint == "PRIMARY" {
+		return ""
+	}
+	name := constraint
+	for _, suffix := range []string{"_key", "_idx", "_unique", "_uniq"} {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok {
+			name = trimmed
+			break
+		}
+	}
+	if table != "" {
+		name = strings.TrimPrefix(name, table+"_")
+	}
+	return name
+}
+
 // mapLockError inspects a database error and wraps it in a typed lock error
 // if it matches a known Postgres error code. The detection is interface-based:
 // both pgx and lib/pq expose a method or field for the SQLSTATE code.
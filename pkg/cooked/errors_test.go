@@ -112,6 +112,92 @@ func TestMapLockErrorNoWaitFromCode(t *testing.T) {
 	}
 }
 
+// mockPqError implements the lib/pq Get interface for testing.
+type mockPqError map[byte]string
+
+func (e mockPqError) Error() string     { return "pq: " + e['M'] }
+func (e mockPqError) Get(k byte) string { return e[k] }
+
+// mockPgxError mirrors the fields of pgx's *pgconn.PgError.
+type mockPgxError struct {
+	Code           string
+	Message        string
+	Detail         string
+	TableName      string
+	ConstraintName string
+}
+
+func (e *mockPgxError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *mockPgxError) SQLState() string { return e.Code }
+
+// mockMySQLError mirrors go-sql-driver's *mysql.MySQLError.
+type mockMySQLError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mockMySQLError) Error() string {
+	return fmt.Sprintf("Error %d (23000): %s", e.Number, e.Message)
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		column string
+		ok     bool
+	}{
+		{"pq detail", mockPqError{'C': "23505", 'n': "users_email_key", 't': "users", 'D': "Key (email)=(a@b.c) already exists."}, "email", true},
+		{"pq constraint name", mockPqError{'C': "23505", 'n': "users_email_key", 't': "users"}, "email", true},
+		{"pq other code", mockPqError{'C': "23503", 'n': "posts_user_id_fkey"}, "", false},
+		{"pgx", &mockPgxError{Code: "23505", TableName: "users", ConstraintName: "users_handle_idx"}, "handle", true},
+		{"pgx multi-column detail", &mockPgxError{Code: "23505", ConstraintName: "members_org_id_user_id_idx", Detail: "Key (org_id, user_id)=(1, 2) already exists."}, "", true},
+		{"pgx code method", &mockPgError{code: "23505", msg: `duplicate key value violates unique constraint "users_email_key"`}, "users_email", true},
+		{"wrapped pgx", fmt.Errorf("insert: %w", &mockPgxError{Code: "23505", TableName: "users", ConstraintName: "users_email_key"}), "email", true},
+		{"mysql", &mockMySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email'"}, "email", true},
+		{"mysql other", &mockMySQLError{Number: 1452, Message: "Cannot add or update a child row"}, "", false},
+		{"sqlite", errors.New("UNIQUE constraint failed: users.email"), "email", true},
+		{"sqlite multi-column", errors.New("UNIQUE constraint failed: members.org_id, members.user_id"), "", true},
+		{"other", errors.New("connection refused"), "", false},
+		{"nil", nil, "", false},
+	}
+	for _, tt := range tests {
+		column, ok := IsUniqueViolation(tt.err)
+		if column != tt.column || ok != tt.ok {
+			t.Errorf("%s: IsUniqueViolation() = %q, %v; want %q, %v", tt.name, column, ok, tt.column, tt.ok)
+		}
+	}
+}
+
+func TestMapUniqueError(t *testing.T) {
+	driverErr := &mockPgxError{Code: "23505", ConstraintName: "users_email_key", Message: `duplicate key value violates unique constraint "users_email_key"`}
+	result := mapUniqueError("users", driverErr)
+	var uve *UniqueViolationError
+	if !errors.As(result, &uve) {
+		t.Fatalf("expected UniqueViolationError, got %T", result)
+	}
+	if uve.Table != "users" || uve.Column != "email" || uve.Constraint != "users_email_key" {
+		t.Errorf("UniqueViolationError = %+v", uve)
+	}
+	if uve.HTTPStatus() != 409 || uve.ClientMessage() != "email already exists" {
+		t.Errorf("status/message = %d %q", uve.HTTPStatus(), uve.ClientMessage())
+	}
+	if !errors.Is(result, driverErr) {
+		t.Error("UniqueViolationError should unwrap to the driver error")
+	}
+	if column, ok := IsUniqueViolation(result); !ok || column != "email" {
+		t.Errorf("IsUniqueViolation(mapped) = %q, %v", column, ok)
+	}
+
+	other := errors.New("connection refused")
+	if mapUniqueError("users", other) != other {
+		t.Error("non-unique errors should be returned unchanged")
+	}
+	if mapUniqueError("users", nil) != nil {
+		t.Error("nil should stay nil")
+	}
+}
+
 // mockPgError implements the pgx error interface for testing.
 type mockPgError struct {
 	code string
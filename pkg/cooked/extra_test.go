@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
@@ -51,6 +53,26 @@ func TestContextParamUUIDInvalid(t *testing.T) {
 	}
 }
 
+func TestContextParamInt(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetParam("id", "42")
+	ctx.SetParam("big", "9007199254740993")
+	ctx.SetParam("bad", "42abc")
+
+	if got, err := ctx.ParamInt("id"); err != nil || got != 42 {
+		t.Errorf("ParamInt = %d, %v; want 42, nil", got, err)
+	}
+	if got, err := ctx.ParamInt64("big"); err != nil || got != 9007199254740993 {
+		t.Errorf("ParamInt64 = %d, %v; want 9007199254740993, nil", got, err)
+	}
+	if _, err := ctx.ParamInt("bad"); err == nil {
+		t.Error("ParamInt with non-numeric value should return error")
+	}
+	if _, err := ctx.ParamInt64("bad"); err == nil {
+		t.Error("ParamInt64 with non-numeric value should return error")
+	}
+}
+
 func TestContextCookiePresent(t *testing.T) {
 	r := httptest.NewRequest("GET", "/", nil)
 	r.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
@@ -72,6 +94,19 @@ func TestContextCookieMissing(t *testing.T) {
 	}
 }
 
+func TestContextRequestCookie(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
+	ctx := NewContext(httptest.NewRecorder(), r)
+	cookie, err := ctx.RequestCookie("session")
+	if err != nil || cookie.Name != "session" || cookie.Value != "abc123" {
+		t.Errorf("RequestCookie() = %+v, %v", cookie, err)
+	}
+	if _, err := ctx.RequestCookie("missing"); err != http.ErrNoCookie {
+		t.Errorf("RequestCookie() missing error = %v, want http.ErrNoCookie", err)
+	}
+}
+
 func TestContextError(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
 	resp := ctx.Error(errors.New("something went wrong"))
@@ -80,6 +115,29 @@ func TestContextError(t *testing.T) {
 	}
 }
 
+func TestContextErrorUniqueViolation(t *testing.T) {
+	t.Setenv("APP_DEBUG", "")
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/users", nil))
+	resp := ctx.Error(&UniqueViolationError{Table: "users", Column: "email", Constraint: "users_email_key", Err: errors.New("pq: duplicate key")})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Error status = %d, want 409", resp.StatusCode)
+	}
+	body, _ := resp.Body.(map[string]string)
+	if body["error"] != "email already exists" {
+		t.Errorf("Error body = %v, want email already exists", resp.Body)
+	}
+}
+
+func TestContextAuthorize(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if resp, ok := ctx.Authorize(false); ok || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Authorize(false) = %d, %v; want 403, false", resp.StatusCode, ok)
+	}
+	if resp, ok := ctx.Authorize(true); !ok || resp.StatusCode != 0 {
+		t.Errorf("Authorize(true) = %d, %v; want zero response, true", resp.StatusCode, ok)
+	}
+}
+
 func TestContextBadRequest(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
 	resp := ctx.BadRequest("invalid input")
@@ -135,6 +193,61 @@ func TestContextResourceError(t *testing.T) {
 	}
 }
 
+func TestContextResourceServesNonOwnersByDefault(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "user-42"})
+	q := &mockOwnedResourceQuery{mockResourceQuery: mockResourceQuery{result: map[string]string{"id": "1"}}}
+	resp := ctx.Resource(q)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Resource status = %d, want 200", resp.StatusCode)
+	}
+	if q.ownedCalled {
+		t.Error("Resource should serialize for non-owners, not call FetchOwnedResource")
+	}
+}
+
+func TestContextResourceWithOwnerOnly(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "user-42"})
+	q := &mockOwnedResourceQuery{ownedErr: &NotOwnerError{Table: "posts"}}
+	resp := ctx.ResourceWith(q, ResourceOptions{OwnerOnly: true})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ResourceWith not owned status = %d, want 404", resp.StatusCode)
+	}
+	if body, _ := resp.Body.(map[string]string); body["error"] != "not found" {
+		t.Errorf("ResourceWith not owned body = %v, want not found", resp.Body)
+	}
+	if !q.ownedCalled || q.gotOwnerID != "user-42" {
+		t.Errorf("FetchOwnedResource called = %v with %q, want user-42", q.ownedCalled, q.gotOwnerID)
+	}
+}
+
+func TestContextResourceWithForbidNotOwned(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "user-42"})
+	opts := ResourceOptions{ForbidNotOwned: true}
+
+	resp := ctx.ResourceWith(&mockOwnedResourceQuery{ownedErr: fmt.Errorf("fetch: %w", &NotOwnerError{Table: "posts"})}, opts)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("ResourceWith not owned status = %d, want 403", resp.StatusCode)
+	}
+
+	resp = ctx.ResourceWith(&mockOwnedResourceQuery{ownedErr: fmt.Errorf("sql: no rows in result set")}, opts)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ResourceWith missing record status = %d, want 404", resp.StatusCode)
+	}
+
+	resp = ctx.ResourcesWith(&mockOwnedResourceListQuery{ownedErr: &NotOwnerError{Table: "posts"}}, opts)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("ResourcesWith not owned status = %d, want 403", resp.StatusCode)
+	}
+
+	resp = ctx.Resources(&mockOwnedResourceListQuery{mockResourceListQuery: mockResourceListQuery{result: []string{"a"}}, ownedErr: &NotOwnerError{Table: "posts"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Resources status = %d, want 200 without owner-only options", resp.StatusCode)
+	}
+}
+
 func TestContextResourcesNoAuth(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
 	q := &mockResourceListQuery{result: []string{"a", "b"}}
@@ -175,6 +288,27 @@ func (m *mockResourceListQuery) FetchResources(ownerID string) (any, error) {
 	return m.result, m.err
 }
 
+type mockOwnedResourceQuery struct {
+	mockResourceQuery
+	ownedErr    error
+	ownedCalled bool
+}
+
+func (m *mockOwnedResourceQuery) FetchOwnedResource(ownerID string) (any, error) {
+	m.ownedCalled, m.gotOwnerID = true, ownerID
+	return m.result, m.ownedErr
+}
+
+type mockOwnedResourceListQuery struct {
+	mockResourceListQuery
+	ownedErr error
+}
+
+func (m *mockOwnedResourceListQuery) FetchOwnedResources(ownerID string) (any, error) {
+	m.gotOwnerID = ownerID
+	return m.result, m.ownedErr
+}
+
 // --- Response additional coverage ---
 
 func TestResponseWithCookie(t *testing.T) {
@@ -361,11 +495,139 @@ func TestRouterRegisterRoutesTrailingSlash(t *testing.T) {
 		req := httptest.NewRequest("GET", path, nil)
 		req.Header.Set("Authorization", "Bearer token")
 		w := httptest.NewRecorder()
-		mux.ServeHTTP(w, req)
+		CanonicalPaths(mux).ServeHTTP(w, req)
 		// Should not redirect (301 would strip headers)
 		if w.Code == http.StatusMovedPermanently {
 			t.Errorf("GET %s returned 301, should not redirect", path)
 		}
+		if w.Code != 200 {
+			t.Errorf("GET %s = %d, want 200", path, w.Code)
+		}
+	}
+}
+
+func TestCanonicalPathsServesBothSlashVariants(t *testing.T) {
+	var seen []string
+	r := Routes(func(r *Router) {
+		r.Get("/users", func(ctx *Context) Response {
+			seen = append(seen, ctx.Request().Header.Get("Authorization"))
+			return ctx.JSON(200, nil)
+		})
+		r.Get("/users/:id/", func(ctx *Context) Response {
+			return ctx.JSON(200, map[string]string{"id": ctx.Param("id")})
+		})
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+	handler := CanonicalPaths(mux)
+
+	for _, path := range []string{"/users", "/users/", "/users//"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != 200 {
+			t.Errorf("GET %s = %d, want 200", path, w.Code)
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("handler ran %d times, want 3", len(seen))
+	}
+	for _, auth := range seen {
+		if auth != "Bearer token" {
+			t.Errorf("Authorization header = %q, want it preserved", auth)
+		}
+	}
+
+	for _, path := range []string{"/users/42", "/users/42/"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != 200 || !strings.Contains(w.Body.String(), "42") {
+			t.Errorf("GET %s = %d %q, want the show route", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRouterRegisterRoutesSlashVariantsAreDuplicates(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("/users and /users/ should collide as one canonical route")
+		}
+	}()
+	r := Routes(func(r *Router) {
+		r.Get("/users", noop)
+		r.Get("/users/", noop)
+	})
+	r.RegisterRoutes(http.NewServeMux())
+}
+
+func TestRegisterRoutesServesSlashVariantWithoutCanonicalPaths(t *testing.T) {
+	var seen []string
+	r := Routes(func(r *Router) {
+		r.Get("/users", func(ctx *Context) Response {
+			seen = append(seen, ctx.Request().Header.Get("Authorization"))
+			return ctx.JSON(200, nil)
+		})
+		r.Get("/users/:id", noop)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	for _, path := range []string{"/users", "/users/"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != 200 {
+			t.Errorf("GET %s = %d, want 200", path, w.Code)
+		}
+	}
+	if len(seen) != 2 || seen[1] != "Bearer token" {
+		t.Errorf("Authorization seen = %v, want it on both requests", seen)
+	}
+
+	for path, want := range map[string]int{"/users/5/": 204, "/nope/": 404} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/users/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /users/ = %d, want 405", w.Code)
+	}
+}
+
+func BenchmarkRegisterRoutes(b *testing.B) {
+	r := Routes(func(r *Router) {
+		for _, name := range []string{"users", "posts", "comments", "tags", "orders"} {
+			r.Resource("/"+name, &mockResourceController{})
+		}
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.RegisterRoutes(http.NewServeMux())
+	}
+}
+
+func BenchmarkCanonicalPaths(b *testing.B) {
+	r := Routes(func(r *Router) {
+		r.Get("/users", noop)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+	handler := CanonicalPaths(mux)
+
+	for _, path := range []string{"/users", "/users/"} {
+		b.Run(path, func(b *testing.B) {
+			req := httptest.NewRequest("GET", path, nil)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+		})
 	}
 }
 
@@ -465,6 +727,53 @@ func TestErrorUnknownReturns500(t *testing.T) {
 	}
 }
 
+func TestErrorHidesDetailsUnlessDebug(t *testing.T) {
+	buf := captureLogger(t)
+	err := &StaleVersionError{Table: "users", EntityID: "1", ExpectedVersion: "a", ActualVersion: "b"}
+
+	t.Setenv("APP_DEBUG", "false")
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("PUT", "/users/1", nil))
+	resp := ctx.Error(err)
+	if body := resp.Body.(map[string]string); resp.StatusCode != http.StatusConflict || !strings.Contains(body["error"], "stale version on users") {
+		t.Errorf("non-debug 4xx: status %d, body %v", resp.StatusCode, body)
+	}
+	if resp = ctx.Error(errors.New(`pq: relation "users" does not exist`)); resp.Body.(map[string]string)["error"] != "internal server error" {
+		t.Errorf("non-debug 500 body = %v", resp.Body)
+	}
+	if !strings.Contains(buf.String(), "stale version on users") || !strings.Contains(buf.String(), `"path":"/users/1"`) || !strings.Contains(buf.String(), `relation \"users\"`) {
+		t.Errorf("log output %q should carry the full errors", buf.String())
+	}
+
+	t.Setenv("APP_DEBUG", "true")
+	resp = ctx.Error(err)
+	if body := resp.Body.(map[string]string); resp.StatusCode != http.StatusConflict || !strings.Contains(body["error"], "stale version on users") {
+		t.Errorf("debug: status %d, body %v", resp.StatusCode, body)
+	}
+	if resp = ctx.Error(errors.New(`pq: relation "users" does not exist`)); !strings.Contains(resp.Body.(map[string]string)["error"], "relation") {
+		t.Errorf("debug 500 body = %v", resp.Body)
+	}
+}
+
+func TestAppDebugDefaultsTrueAndReadsDotEnv(t *testing.T) {
+	t.Setenv("APP_DEBUG", "")
+	dotEnvOnce, dotEnvVars = sync.Once{}, nil
+	t.Cleanup(func() { dotEnvOnce, dotEnvVars = sync.Once{}, nil })
+	t.Chdir(t.TempDir())
+	if !appDebug() {
+		t.Error("appDebug() = false with APP_DEBUG unset, want the scaffold default true")
+	}
+
+	os.WriteFile(".env", []byte("# local\nAPP_DEBUG=\"false\"\n"), 0o644)
+	dotEnvOnce, dotEnvVars = sync.Once{}, nil
+	if appDebug() {
+		t.Error("appDebug() = true, want APP_DEBUG=false from .env")
+	}
+	t.Setenv("APP_DEBUG", "yes")
+	if !appDebug() {
+		t.Error("appDebug() = false, want the environment to win over .env")
+	}
+}
+
 func TestPanicRecovery(t *testing.T) {
 	r := Routes(func(r *Router) {
 		r.Get("/boom", func(ctx *Context) Response {
@@ -9,7 +9,7 @@ type CreateGraphqlExposuresTable_2026_03_25_000002 struct {
 
 func (m *CreateGraphqlExposuresTable_2026_03_25_000002) Up() {
 	m.CreateTable("graphql_exposures", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+		t.UUID("id").PrimaryKey().DefaultRaw("uuid_generate_v7()")
 		t.String("model", 100).NotNull()
 		t.String("operation", 20).NotNull()
 		t.Timestamps()
@@ -9,7 +9,7 @@ type CreateGraphqlActionsTable_2026_03_25_000003 struct {
 
 func (m *CreateGraphqlActionsTable_2026_03_25_000003) Up() {
 	m.CreateTable("graphql_actions", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+		t.UUID("id").PrimaryKey().DefaultRaw("uuid_generate_v7()")
 		t.String("name", 100).NotNull().Unique()
 		t.Timestamps()
 	})
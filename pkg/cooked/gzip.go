@@ -0,0 +1,133 @@
+package cooked
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GzipOptions configures the Gzip middleware.
+type GzipOptions struct {
+	// MinSize is the smallest body, in bytes, worth compressing. Smaller
+	// bodies are sent as-is since the gzip framing would outweigh the
+	// savings. Defaults to 1024.
+	MinSize int
+	// Level is the compress/gzip level. Defaults to gzip.DefaultCompression.
+	Level int
+}
+
+// responseCompression is attached to a Response by the Gzip middleware and
+// applied in Response.Write once the body has been serialized.
+type responseCompression struct {
+	minSize int
+	writers sync.Pool
+}
+
+// Gzip compresses response bodies of 1 KiB or more for clients that accept
+// gzip. See GzipWith.
+func Gzip() MiddlewareFunc {
+	return GzipWith(GzipOptions{})
+}
+
+// GzipWith returns a Gzip middleware configured by opts. Compression happens
+// when the response is written, after the body is serialized, so the size
+// threshold applies to the bytes on the wire and Content-Length is set to
+// the compressed length. Responses that already carry a Content-Encoding are
+// left alone.
+//
+//	r.Group("/api", func(r *pickle.Router) {
+//	    r.Get("/posts", controllers.PostController{}.Index)
+//	}, pickle.GzipWith(pickle.GzipOptions{MinSize: 512}))
+func GzipWith(opts GzipOptions) MiddlewareFunc {
+	minSize := opts.MinSize
+	if minSize <= 0 {
+		minSize = 1024
+	}
+	level := opts.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	if _, err := gzip.NewWriterLevel(nil, level); err != nil {
+		panic(fmt.Sprintf("pickle: GzipWith: %v", err))
+	}
+
+	c := &responseCompression{minSize: minSize}
+	c.writers.New = func() any {
+		zw, _ := gzip.NewWriterLevel(nil, level)
+		return zw
+	}
+
+	return func(ctx *Context, next func() Response) Response {
+		resp := next()
+		vary := "Accept-Encoding"
+		if existing := resp.Headers["Vary"]; existing != "" {
+			vary = existing
+			if !strings.Contains(existing, "Accept-Encoding") {
+				vary += ", Accept-Encoding"
+			}
+		}
+		resp = resp.Header("Vary", vary)
+		if acceptsGzip(ctx.Request().Header.Get("Accept-Encoding")) {
+			resp.compression = c
+		}
+		return resp
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip with a
+// non-zero quality. An explicit gzip entry takes precedence over "*", so
+// "gzip;q=0, *" refuses gzip.
+func acceptsGzip(header string) bool {
+	gzipQ, starQ := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if coding == "gzip" {
+			gzipQ = q
+		} else {
+			starQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return starQ > 0
+}
+
+// apply gzips data when it meets the size threshold, updating the encoding
+// and length headers to match. It returns the bytes to write.
+func (c *responseCompression) apply(h http.Header, data []byte) []byte {
+	if len(data) < c.minSize || h.Get("Content-Encoding") != "" {
+		return data
+	}
+
+	var buf bytes.Buffer
+	zw := c.writers.Get().(*gzip.Writer)
+	defer c.writers.Put(zw)
+	zw.Reset(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return data
+	}
+	if err := zw.Close(); err != nil {
+		return data
+	}
+
+	h.Set("Content-Encoding", "gzip")
+	h.Set("Content-Length", strconv.Itoa(buf.Len()))
+	return buf.Bytes()
+}
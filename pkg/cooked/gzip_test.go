@@ -0,0 +1,102 @@
+package cooked
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func gzipRoundTrip(t *testing.T, mw MiddlewareFunc, acceptEncoding string, resp Response) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, req)
+	mw(ctx, func() Response { return resp }).Write(rec)
+	return rec
+}
+
+func TestGzipCompressesLargeJSON(t *testing.T) {
+	body := map[string]string{"text": strings.Repeat("pickle ", 500)}
+	rec := gzipRoundTrip(t, Gzip(), "br, gzip;q=0.8", Response{StatusCode: 200, Body: body})
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+		t.Fatalf("Content-Length = %s, body is %d bytes", got, rec.Body.Len())
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(plain), `"text":"pickle pickle`) {
+		t.Fatalf("decompressed body = %.60s", plain)
+	}
+}
+
+func TestGzipSkipsSmallBodies(t *testing.T) {
+	rec := gzipRoundTrip(t, Gzip(), "gzip", Response{StatusCode: 200, Body: map[string]string{"ok": "yes"}})
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Fatal("small body should not be compressed")
+	}
+	if rec.Body.String() != `{"ok":"yes"}` {
+		t.Fatalf("body = %q", rec.Body.String())
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+	}
+}
+
+func TestGzipRespectsAcceptEncoding(t *testing.T) {
+	large := renderedAsset(strings.Repeat("a", 4096))
+	for _, header := range []string{"", "identity", "gzip;q=0", "br", "gzip;q=0, *", "*, gzip;q=0", "*;q=0"} {
+		rec := gzipRoundTrip(t, Gzip(), header, Response{StatusCode: 200, Body: large})
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 4096 {
+			t.Errorf("Accept-Encoding %q: body should be sent uncompressed", header)
+		}
+	}
+	for _, header := range []string{"*", "gzip;q=0.5, *;q=0"} {
+		rec := gzipRoundTrip(t, Gzip(), header, Response{StatusCode: 200, Body: large})
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding %q should allow gzip", header)
+		}
+	}
+}
+
+func TestGzipLeavesEncodedResponsesAlone(t *testing.T) {
+	resp := Response{
+		StatusCode: 200,
+		Body:       renderedAsset(strings.Repeat("a", 4096)),
+		Headers:    map[string]string{"Content-Encoding": "br", "Vary": "Origin"},
+	}
+	rec := gzipRoundTrip(t, GzipWith(GzipOptions{MinSize: 10}), "gzip", resp)
+	if rec.Header().Get("Content-Encoding") != "br" || rec.Body.Len() != 4096 {
+		t.Fatal("pre-encoded body should be written unchanged")
+	}
+	if rec.Header().Get("Vary") != "Origin, Accept-Encoding" {
+		t.Fatalf("Vary = %q, want Origin, Accept-Encoding", rec.Header().Get("Vary"))
+	}
+}
+
+func TestGzipWithInvalidLevelPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for invalid level")
+		}
+	}()
+	GzipWith(GzipOptions{Level: 42})
+}
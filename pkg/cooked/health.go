@@ -0,0 +1,27 @@
+package cooked
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+// healthPingTimeout bounds the database ping made by HealthHandler, so a
+// hung connection fails the probe instead of stalling it.
+const healthPingTimeout = 2 * time.Second
+
+// HealthHandler returns a readiness handler that pings db. It responds 200
+// with {"status":"ok","db":"up"} when the ping succeeds within a short
+// timeout, and 503 with {"status":"error","db":"down"} otherwise, including
+// when db is nil.
+func HealthHandler(db *sql.DB) HandlerFunc {
+	return func(ctx *Context) Response {
+		pingCtx, cancel := context.WithTimeout(ctx.Request().Context(), healthPingTimeout)
+		defer cancel()
+		if db == nil || db.PingContext(pingCtx) != nil {
+			return ctx.JSON(http.StatusServiceUnavailable, map[string]string{"status": "error", "db": "down"})
+		}
+		return ctx.JSON(http.StatusOK, map[string]string{"status": "ok", "db": "up"})
+	}
+}
@@ -0,0 +1,37 @@
+package cooked
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestHealthHandlerReportsDatabaseState(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := Routes(func(r *Router) {
+		r.Get("/health", HealthHandler(db))
+		r.Get("/health/none", HealthHandler(nil))
+	}).Test()
+
+	resp := client.Get("/health", nil, nil)
+	if resp.Status != http.StatusOK || !strings.Contains(resp.String(), `"db":"up"`) {
+		t.Errorf("up: status %d, body %s", resp.Status, resp.String())
+	}
+
+	db.Close()
+	resp = client.Get("/health", nil, nil)
+	if resp.Status != http.StatusServiceUnavailable || !strings.Contains(resp.String(), `"db":"down"`) {
+		t.Errorf("closed: status %d, body %s", resp.Status, resp.String())
+	}
+
+	resp = client.Get("/health/none", nil, nil)
+	if resp.Status != http.StatusServiceUnavailable {
+		t.Errorf("nil db: status %d, want 503", resp.Status)
+	}
+}
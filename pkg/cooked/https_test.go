@@ -0,0 +1,123 @@
+package cooked
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func httpsRequest(method, target, remoteAddr, proto string) *Context {
+	req := httptest.NewRequest(method, target, nil)
+	req.RemoteAddr = remoteAddr
+	if proto != "" {
+		req.Header.Set("X-Forwarded-Proto", proto)
+	}
+	return NewContext(httptest.NewRecorder(), req)
+}
+
+func TestForceHTTPSRedirectsForwardedHTTP(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+	ctx := httpsRequest("GET", "http://example.com/posts?page=2", "10.0.0.1:1234", "http")
+	resp := ForceHTTPS()(ctx, func() Response {
+		t.Fatal("plain-http request should not reach the handler")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusMovedPermanently {
+		t.Fatalf("status = %d, want 301", resp.StatusCode)
+	}
+	if got := resp.Headers["Location"]; got != "https://example.com/posts?page=2" {
+		t.Errorf("Location = %q", got)
+	}
+	if _, ok := resp.Headers["Strict-Transport-Security"]; ok {
+		t.Error("HSTS must not be sent over plain http")
+	}
+}
+
+func TestForceHTTPSRedirectsPostWithPermanentRedirect(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	ctx := httpsRequest("POST", "http://example.com/posts", "5.6.7.8:1234", "")
+	resp := ForceHTTPS()(ctx, func() Response { return Response{StatusCode: http.StatusCreated} })
+
+	if resp.StatusCode != http.StatusPermanentRedirect {
+		t.Fatalf("status = %d, want 308", resp.StatusCode)
+	}
+}
+
+func TestForceHTTPSIgnoresUntrustedForwardedProto(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+	ctx := httpsRequest("GET", "http://example.com/", "5.6.7.8:1234", "https")
+	resp := ForceHTTPS()(ctx, func() Response {
+		t.Fatal("spoofed X-Forwarded-Proto should not be trusted")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusMovedPermanently {
+		t.Fatalf("status = %d, want 301", resp.StatusCode)
+	}
+}
+
+func TestForceHTTPSSetsHSTSOnForwardedHTTPS(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+	ctx := httpsRequest("GET", "http://example.com/", "10.0.0.1:1234", "https")
+	resp := ForceHTTPS()(ctx, func() Response { return Response{StatusCode: http.StatusOK} })
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Headers["Strict-Transport-Security"]; got != "max-age=31536000" {
+		t.Errorf("Strict-Transport-Security = %q", got)
+	}
+}
+
+func TestForceHTTPSWithOptions(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	mw := ForceHTTPSWith(HTTPSOptions{
+		HSTSMaxAge:        time.Hour,
+		IncludeSubDomains: true,
+		Preload:           true,
+	})
+	ctx := httpsRequest("GET", "https://example.com/", "5.6.7.8:1234", "")
+	ctx.Request().TLS = &tls.ConnectionState{}
+	resp := mw(ctx, func() Response { return Response{StatusCode: http.StatusOK} })
+
+	if got := resp.Headers["Strict-Transport-Security"]; got != "max-age=3600; includeSubDomains; preload" {
+		t.Errorf("Strict-Transport-Security = %q", got)
+	}
+
+	noHSTS := ForceHTTPSWith(HTTPSOptions{HSTSMaxAge: -1})
+	resp = noHSTS(ctx, func() Response { return Response{StatusCode: http.StatusOK} })
+	if _, ok := resp.Headers["Strict-Transport-Security"]; ok {
+		t.Error("negative HSTSMaxAge should disable the header")
+	}
+}
+
+func TestForceHTTPSReject(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	ctx := httpsRequest("GET", "http://example.com/", "5.6.7.8:1234", "")
+	resp := ForceHTTPSWith(HTTPSOptions{Reject: true})(ctx, func() Response {
+		t.Fatal("rejected request should not reach the handler")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", resp.StatusCode)
+	}
+	if _, ok := resp.Headers["Location"]; ok {
+		t.Error("reject mode should not redirect")
+	}
+}
@@ -359,8 +359,15 @@ func serializeRawValue(buf *bytes.Buffer, val any, typeTag byte) {
 		buf.Write(b[:])
 
 	case typeTagDecimal:
-		// Normalize decimal representation to match shopspring/decimal's String() output
-		s := fmt.Sprintf("%v", val)
+		// Normalize decimal representation to match shopspring/decimal's String() output.
+		// lib/pq returns NUMERIC as []byte, which %v would print as a byte list.
+		var s string
+		switch v := val.(type) {
+		case []byte:
+			s = string(v)
+		default:
+			s = fmt.Sprintf("%v", v)
+		}
 		// Parse through the decimal library if available, otherwise trim trailing zeros
 		// Remove trailing zeros after decimal point to match decimal.String() behavior
 		if dotIdx := stringIndex(s, '.'); dotIdx >= 0 {
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/shortontech/pickle/pkg/schema"
 )
 
@@ -193,6 +194,18 @@ func TestSerializeFieldBoolean(t *testing.T) {
 	}
 }
 
+func TestDecimalHashMatchesPostgresNumericBytes(t *testing.T) {
+	// lib/pq returns NUMERIC(18,2) as text bytes with the column's scale.
+	var fromModel, fromRow bytes.Buffer
+	serializeField(&fromModel, reflectField(decimal.RequireFromString("1250.50")), typeTagDecimal)
+	fromRow.WriteByte(typeTagDecimal)
+	serializeRawValue(&fromRow, []byte("1250.50"), typeTagDecimal)
+	fromRow.WriteByte(0x00)
+	if !bytes.Equal(fromModel.Bytes(), fromRow.Bytes()) {
+		t.Errorf("decimal hash bytes differ: model %q, row %q", fromModel.Bytes(), fromRow.Bytes())
+	}
+}
+
 func TestSerializeFieldTimestamp(t *testing.T) {
 	var buf bytes.Buffer
 	ts := time.Date(2026, 3, 19, 12, 0, 0, 0, time.UTC)
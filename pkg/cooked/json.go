@@ -0,0 +1,323 @@
+package cooked
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time formats for JSONOptions.TimeFormat besides a time.Format layout.
+const (
+	TimeFormatUnix      = "unix"      // seconds since the epoch, as a number
+	TimeFormatUnixMilli = "unixmilli" // milliseconds since the epoch, as a number
+)
+
+// JSONOptions controls how Pickle encodes JSON responses and decodes JSON
+// request bodies. The zero value matches encoding/json.
+type JSONOptions struct {
+	// TimeFormat sets how time.Time values in responses are written:
+	// TimeFormatUnix, TimeFormatUnixMilli, or a layout for time.Format such
+	// as time.RFC3339. Empty keeps encoding/json's RFC 3339 with
+	// nanoseconds. Times inside values with their own MarshalJSON are left
+	// to that method unless the value is a JSONValuer.
+	TimeFormat string
+	// DisableHTMLEscape writes <, > and & as-is instead of as \u003c,
+	// \u003e and \u0026.
+	DisableHTMLEscape bool
+	// UseNumber decodes numbers in request bodies into json.Number instead
+	// of float64 when the target is an interface, so large IDs and exact
+	// decimals in map[string]any fields survive.
+	UseNumber bool
+}
+
+// JSONSettings is the JSON configuration used by JSONEncoder and
+// DecodeJSON. Set it once at startup:
+//
+//	pickle.JSONSettings = pickle.JSONOptions{TimeFormat: time.RFC3339, DisableHTMLEscape: true}
+var JSONSettings JSONOptions
+
+// JSONValuer is implemented by types whose MarshalJSON encodes another
+// value, such as a generated model with hidden columns, which encodes its
+// Public projection. JSONEncoder encodes JSONValue in its place, so
+// JSONSettings apply to it as they would to any other value.
+type JSONValuer interface {
+	JSONValue() any
+}
+
+// JSONEncoder encodes every JSON response body. Replace it to use a
+// different encoder altogether; the default honors JSONSettings.
+var JSONEncoder = func(v any) ([]byte, error) {
+	return encodeJSON(v, JSONSettings)
+}
+
+// DecodeJSON decodes a JSON request body into v, honoring
+// JSONSettings.UseNumber. Generated request bindings decode through it.
+func DecodeJSON(data []byte, v any) error {
+	if !JSONSettings.UseNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	// Match json.Unmarshal, which rejects anything after the value.
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+func encodeJSON(v any, opts JSONOptions) ([]byte, error) {
+	if opts.TimeFormat == "" && !opts.DisableHTMLEscape {
+		return json.Marshal(v)
+	}
+	return marshalJSON(formatTimes(reflect.ValueOf(v), opts), !opts.DisableHTMLEscape)
+}
+
+// marshalJSON is json.Marshal with HTML escaping switchable.
+func marshalJSON(v any, escapeHTML bool) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(escapeHTML)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonValuerType    = reflect.TypeOf((*JSONValuer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// formatTimes returns a copy of v that encodes the same way, except that
+// each time.Time is replaced by its value in opts.TimeFormat (if set) and
+// each JSONValuer by its JSONValue. Structs become jsonObjects so field
+// order, json tags and omitempty carry over.
+func formatTimes(v reflect.Value, opts JSONOptions) any {
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	if opts.TimeFormat != "" && t == timeType {
+		return formatTime(v.Interface().(time.Time), opts.TimeFormat)
+	}
+	if opts.TimeFormat != "" && t.Kind() == reflect.Pointer && t.Elem() == timeType {
+		if v.IsNil() {
+			return nil
+		}
+		return formatTime(v.Elem().Interface().(time.Time), opts.TimeFormat)
+	}
+	if t.Implements(jsonValuerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil
+		}
+		return formatTimes(reflect.ValueOf(v.Interface().(JSONValuer).JSONValue()), opts)
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return formatTimes(v.Elem(), opts)
+	case reflect.Struct:
+		obj := jsonObject{escapeHTML: !opts.DisableHTMLEscape}
+		appendStructFields(&obj, v, opts, 0)
+		return obj
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, ok := jsonMapKey(iter.Key())
+			if !ok {
+				return v.Interface()
+			}
+			out[key] = formatTimes(iter.Value(), opts)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || t.Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = formatTimes(v.Index(i), opts)
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+func formatTime(t time.Time, format string) any {
+	switch format {
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	}
+	return t.Format(format)
+}
+
+// appendStructFields adds v's fields to obj following encoding/json's rules
+// for tags, omitempty, ",string" and untagged embedded structs. depth is
+// how deeply v is embedded; a shallower field shadows a deeper one.
+func appendStructFields(obj *jsonObject, v reflect.Value, opts JSONOptions, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				appendStructFields(obj, fv, opts, depth+1)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if hasJSONOption(options, "omitempty") && isEmptyJSONValue(fv) {
+			continue
+		}
+		value := formatTimes(fv, opts)
+		if hasJSONOption(options, "string") {
+			value = quotedScalar(fv, value)
+		}
+		obj.set(name, value, depth)
+	}
+}
+
+func hasJSONOption(options, want string) bool {
+	for _, opt := range strings.Split(options, ",") {
+		if opt == want {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedScalar applies the ",string" option: scalar values are encoded as
+// JSON strings holding their JSON text.
+func quotedScalar(v reflect.Value, value any) any {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return value
+		}
+		return string(data)
+	}
+	return value
+}
+
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// jsonMapKey returns the object key encoding/json would use for k.
+func jsonMapKey(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err == nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), true
+	}
+	return "", false
+}
+
+// jsonObject is a JSON object that keeps its keys in insertion order.
+type jsonObject struct {
+	keys       []string
+	values     map[string]any
+	depths     map[string]int
+	escapeHTML bool
+}
+
+// set adds key, or replaces it in place when the existing value came from
+// a more deeply embedded field.
+func (o *jsonObject) set(key string, value any, depth int) {
+	if o.values == nil {
+		o.values, o.depths = map[string]any{}, map[string]int{}
+	}
+	if existing, ok := o.depths[key]; !ok {
+		o.keys = append(o.keys, key)
+	} else if existing <= depth {
+		return
+	}
+	o.values[key], o.depths[key] = value, depth
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := marshalJSON(key, o.escapeHTML)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := marshalJSON(o.values[key], o.escapeHTML)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
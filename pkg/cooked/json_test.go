@@ -0,0 +1,164 @@
+package cooked
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type jsonBase struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type jsonEvent struct {
+	jsonBase
+	ID       string            `json:"event_id"`
+	Name     string            `json:"name"`
+	Note     string            `json:"note,omitempty"`
+	Count    int               `json:"count,string"`
+	At       *time.Time        `json:"at"`
+	Deadline *time.Time        `json:"deadline,omitempty"`
+	History  []time.Time       `json:"history"`
+	ByKey    map[int]time.Time `json:"by_key"`
+	Raw      json.RawMessage   `json:"raw"`
+	hidden   string
+}
+
+func withJSONSettings(t *testing.T, opts JSONOptions) {
+	t.Helper()
+	old := JSONSettings
+	JSONSettings = opts
+	t.Cleanup(func() { JSONSettings = old })
+}
+
+func TestJSONEncoderDefaultMatchesEncodingJSON(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	v := jsonEvent{jsonBase: jsonBase{ID: "b", CreatedAt: at}, Name: "<a&b>", At: &at}
+	got, err := JSONEncoder(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(v)
+	if string(got) != string(want) {
+		t.Errorf("default encoding = %s, want %s", got, want)
+	}
+}
+
+func TestJSONEncoderTimeFormat(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := jsonEvent{
+		jsonBase: jsonBase{ID: "b", CreatedAt: at},
+		ID:       "e",
+		Name:     "<a&b>",
+		Count:    3,
+		At:       &at,
+		History:  []time.Time{at},
+		ByKey:    map[int]time.Time{1: at},
+		Raw:      json.RawMessage(`{"x":1}`),
+	}
+
+	withJSONSettings(t, JSONOptions{TimeFormat: TimeFormatUnix})
+	got, err := JSONEncoder(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"b","created_at":1767323045,"event_id":"e","name":"\u003ca\u0026b\u003e","count":"3","at":1767323045,"history":[1767323045],"by_key":{"1":1767323045},"raw":{"x":1}}`
+	if string(got) != want {
+		t.Errorf("unix encoding =\n%s\nwant\n%s", got, want)
+	}
+
+	withJSONSettings(t, JSONOptions{TimeFormat: time.DateOnly, DisableHTMLEscape: true})
+	got, err = JSONEncoder(map[string]any{"name": "<a&b>", "on": at, "ms": []any{at}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"ms":["2026-01-02"],"name":"<a&b>","on":"2026-01-02"}`; string(got) != want {
+		t.Errorf("layout encoding = %s, want %s", got, want)
+	}
+}
+
+func TestResponseWriteUsesJSONEncoder(t *testing.T) {
+	withJSONSettings(t, JSONOptions{DisableHTMLEscape: true, TimeFormat: TimeFormatUnixMilli})
+	w := httptest.NewRecorder()
+	Response{StatusCode: 200, Body: map[string]any{"q": "a<b", "t": time.UnixMilli(1500).UTC()}}.Write(w)
+	if got := w.Body.String(); got != `{"q":"a<b","t":1500}` {
+		t.Errorf("body = %s", got)
+	}
+}
+
+func TestDecodeJSONUseNumber(t *testing.T) {
+	var v map[string]any
+	if err := DecodeJSON([]byte(`{"id": 9007199254740993}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v["id"].(float64); !ok {
+		t.Errorf("default decoding should give float64, got %T", v["id"])
+	}
+
+	withJSONSettings(t, JSONOptions{UseNumber: true})
+	if err := DecodeJSON([]byte(`{"id": 9007199254740993}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := v["id"].(json.Number); !ok || n.String() != "9007199254740993" {
+		t.Errorf("UseNumber decoding = %#v", v["id"])
+	}
+	if err := DecodeJSON([]byte(`{} {}`), &v); err == nil {
+		t.Error("trailing data should be rejected")
+	}
+}
+
+func TestJSONEncoderOuterFieldShadowsEmbedded(t *testing.T) {
+	type shadowed struct {
+		ID string `json:"id"`
+		jsonBase
+	}
+	withJSONSettings(t, JSONOptions{TimeFormat: TimeFormatUnix})
+	got, err := JSONEncoder(shadowed{ID: "outer", jsonBase: jsonBase{ID: "inner", CreatedAt: time.Unix(60, 0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"outer","created_at":60}`; string(got) != want {
+		t.Errorf("encoding = %s, want %s", got, want)
+	}
+}
+
+// jsonUser mirrors a generated model with a Hidden() password column.
+type jsonUser struct {
+	Name      string    `json:"name"`
+	Password  string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type jsonUserPublic struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (m *jsonUser) Public() jsonUserPublic {
+	return jsonUserPublic{Name: m.Name, CreatedAt: m.CreatedAt}
+}
+
+func (m jsonUser) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Public())
+}
+
+func (m jsonUser) JSONValue() any {
+	return m.Public()
+}
+
+func TestJSONEncoderAppliesSettingsToHiddenColumnModels(t *testing.T) {
+	withJSONSettings(t, JSONOptions{TimeFormat: TimeFormatUnix, DisableHTMLEscape: true})
+	user := jsonUser{Name: "<ann>", Password: "secret", CreatedAt: time.Unix(60, 0)}
+	var missing *jsonUser
+
+	got, err := JSONEncoder(map[string]any{"user": user, "users": []jsonUser{user}, "missing": missing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"missing":null,"user":{"name":"<ann>","created_at":60},"users":[{"name":"<ann>","created_at":60}]}`
+	if string(got) != want {
+		t.Errorf("encoding = %s, want %s", got, want)
+	}
+}
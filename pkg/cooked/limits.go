@@ -0,0 +1,126 @@
+package cooked
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// BodyLimit rejects request bodies larger than maxBytes with 413 Request
+// Entity Too Large. A Content-Length over the limit is refused before the
+// handler runs. A body without one is cut off at the limit: the handler's
+// read fails, and whatever response it builds from that failure is replaced
+// with the 413.
+//
+//	r.Group("/uploads", func(r *pickle.Router) {
+//	    r.Post("/", controllers.UploadController{}.Store)
+//	}, pickle.BodyLimit(10<<20))
+func BodyLimit(maxBytes int64) MiddlewareFunc {
+	return func(ctx *Context, next func() Response) Response {
+		r := ctx.Request()
+		if r.ContentLength > maxBytes {
+			return bodyTooLarge(ctx)
+		}
+		if r.Body == nil || r.Body == http.NoBody {
+			return next()
+		}
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(ctx.ResponseWriter(), r.Body, maxBytes)}
+		r.Body = body
+		resp := next()
+		if body.exceeded {
+			return bodyTooLarge(ctx)
+		}
+		return resp
+	}
+}
+
+// limitedBody records whether a read ran past BodyLimit's limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+func bodyTooLarge(ctx *Context) Response {
+	return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "request body too large"})
+}
+
+// Timeout gives the rest of the chain d to return a response, or answers 503
+// Service Unavailable. The deadline is set on the request's context, so
+// handlers that pass ctx.Request().Context() to database or HTTP calls have
+// that work cancelled when time runs out.
+//
+// Handlers return a Response rather than writing one, so the chain runs in
+// its own goroutine and Timeout returns whichever comes first. A handler
+// still running at the deadline is abandoned, not stopped: it must not write
+// to ctx.ResponseWriter() itself, and anything it does after the deadline
+// without checking the context still happens, though response headers and
+// cookies it sets are dropped. A panic in the chain is re-raised on the
+// request's goroutine with the stack it was raised from, so recovery
+// middleware still sees it and logs where it happened.
+func Timeout(d time.Duration) MiddlewareFunc {
+	return func(ctx *Context, next func() Response) Response {
+		deadline, cancel := context.WithTimeout(ctx.request.Context(), d)
+		defer cancel()
+		ctx.request = ctx.request.WithContext(deadline)
+
+		done := make(chan Response, 1)
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- &stackPanic{value: p, stack: debug.Stack()}
+				}
+			}()
+			done <- next()
+		}()
+
+		select {
+		case resp := <-done:
+			return resp
+		case p := <-panicked:
+			panic(p)
+		case <-deadline.Done():
+			ctx.finishResponse()
+			ctx.Logger().Warn("request timed out", "timeout", d, "method", ctx.request.Method, "path", ctx.request.URL.Path)
+			return ctx.JSON(http.StatusServiceUnavailable, map[string]string{"error": "request timed out"})
+		}
+	}
+}
+
+// stackPanic is a panic re-raised on another goroutine, carrying the stack
+// of the goroutine it was first raised on.
+type stackPanic struct {
+	value any
+	stack []byte
+}
+
+func (p *stackPanic) Error() string { return fmt.Sprint(p.value) }
+
+// Unwrap returns the original value if it was an error.
+func (p *stackPanic) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// panicValue returns the value a recovered panic was raised with and the
+// stack to report for it: the original stack for a re-raised stackPanic,
+// otherwise the current one.
+func panicValue(p any) (any, []byte) {
+	if sp, ok := p.(*stackPanic); ok {
+		return sp.value, sp.stack
+	}
+	return p, debug.Stack()
+}
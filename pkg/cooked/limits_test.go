@@ -0,0 +1,134 @@
+package cooked
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readBodyHandler(ctx *Context) func() Response {
+	return func() Response {
+		body, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return ctx.BadRequest("invalid request body")
+		}
+		return ctx.JSON(http.StatusOK, map[string]int{"bytes": len(body)})
+	}
+}
+
+func TestBodyLimitRejectsLargeContentLength(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(strings.Repeat("x", 11))))
+	called := false
+	resp := BodyLimit(10)(ctx, func() Response {
+		called = true
+		return ctx.NoContent()
+	})
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+	if called {
+		t.Error("handler should not run when Content-Length is over the limit")
+	}
+}
+
+func TestBodyLimitCutsOffStreamedBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(strings.Repeat("x", 11)))
+	req.ContentLength = -1
+	ctx := NewContext(httptest.NewRecorder(), req)
+	resp := BodyLimit(10)(ctx, readBodyHandler(ctx))
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+}
+
+func TestBodyLimitAllowsBodyWithinLimit(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(strings.Repeat("x", 10)))
+	req.ContentLength = -1
+	ctx := NewContext(httptest.NewRecorder(), req)
+	resp := BodyLimit(10)(ctx, readBodyHandler(ctx))
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if body, _ := resp.Body.(map[string]int); body["bytes"] != 10 {
+		t.Errorf("body = %v, want 10 bytes read", resp.Body)
+	}
+}
+
+func TestTimeoutReturnsHandlerResponse(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	resp := Timeout(time.Second)(ctx, func() Response {
+		if _, ok := ctx.Request().Context().Deadline(); !ok {
+			t.Error("request context should carry the deadline")
+		}
+		return ctx.NoContent()
+	})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+}
+
+func TestTimeoutAbandonsSlowHandler(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	cancelled := make(chan struct{})
+	resp := Timeout(10*time.Millisecond)(ctx, func() Response {
+		<-ctx.Request().Context().Done()
+		close(cancelled)
+		time.Sleep(10 * time.Millisecond)
+		return ctx.NoContent()
+	})
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("handler's request context was not cancelled")
+	}
+}
+
+func TestTimeoutRepanicsOnRequestGoroutine(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	defer func() {
+		p, stack := panicValue(recover())
+		if p != "boom" {
+			t.Errorf("recovered %v, want boom", p)
+		}
+		if !strings.Contains(string(stack), "panicInHandler") {
+			t.Errorf("stack should be the handler goroutine's, got:\n%s", stack)
+		}
+	}()
+	Timeout(time.Second)(ctx, panicInHandler)
+	t.Error("expected a panic")
+}
+
+func panicInHandler() Response { panic("boom") }
+
+func TestTimeoutDropsResponseStateFromAbandonedHandler(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetResponseHeader("X-Early", "1")
+	finished := make(chan struct{})
+	resp := Timeout(10*time.Millisecond)(ctx, func() Response {
+		<-ctx.Request().Context().Done()
+		defer close(finished)
+		for i := 0; i < 100; i++ {
+			ctx.SetResponseHeader("X-Late", strconv.Itoa(i))
+			ctx.SetCookie(&http.Cookie{Name: "late", Value: "1"})
+		}
+		return ctx.NoContent()
+	})
+	for i := 0; i < 100; i++ {
+		resp = ctx.applyResponseState(resp)
+	}
+	<-finished
+	resp = ctx.applyResponseState(resp)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if resp.Headers["X-Late"] != "" || resp.Headers["X-Early"] != "" || len(resp.Cookies) != 0 {
+		t.Errorf("timed-out response picked up handler state: headers %v cookies %v", resp.Headers, resp.Cookies)
+	}
+}
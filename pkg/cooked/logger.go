@@ -0,0 +1,92 @@
+package cooked
+
+import (
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// Logger is the structured logger used by controllers and middleware.
+// Arguments after the message are alternating key/value pairs, as in slog.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	With(args ...any) Logger
+}
+
+// DefaultLogger is the application-wide logger. It writes through
+// slog.Default(); replace it at startup to change the handler or level.
+var DefaultLogger Logger = NewSlogLogger(slog.Default())
+
+// NewSlogLogger adapts a *slog.Logger to Logger.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, args ...any) { s.l.Debug(msg, args...) }
+func (s slogLogger) Info(msg string, args ...any)  { s.l.Info(msg, args...) }
+func (s slogLogger) Warn(msg string, args ...any)  { s.l.Warn(msg, args...) }
+func (s slogLogger) Error(msg string, args ...any) { s.l.Error(msg, args...) }
+func (s slogLogger) With(args ...any) Logger       { return slogLogger{l: s.l.With(args...)} }
+
+// Logger returns DefaultLogger tagged with this request's ID, so every line
+// logged while handling the request can be correlated.
+func (c *Context) Logger() Logger {
+	if id := c.RequestID(); id != "" {
+		return DefaultLogger.With("request_id", id)
+	}
+	return DefaultLogger
+}
+
+// RequestID returns the request's correlation ID: the one set by the
+// RequestID middleware, or the incoming X-Request-ID header.
+func (c *Context) RequestID() string {
+	if c.requestID != "" {
+		return c.requestID
+	}
+	if c.request == nil {
+		return ""
+	}
+	return c.request.Header.Get("X-Request-ID")
+}
+
+// SetRequestID stores the request's correlation ID (called by RequestID).
+func (c *Context) SetRequestID(id string) { c.requestID = id }
+
+// RequestID is middleware that assigns every request a correlation ID. A
+// well-formed incoming X-Request-ID (from a load balancer or upstream
+// service) is kept; otherwise a new UUID is generated. The ID is stored on
+// the Context for ctx.Logger() and audit records, and echoed back in the
+// X-Request-ID response header.
+func RequestID(ctx *Context, next func() Response) Response {
+	id := ctx.request.Header.Get("X-Request-ID")
+	if !validRequestID(id) {
+		id = uuid.NewString()
+	}
+	ctx.SetRequestID(id)
+	ctx.request.Header.Set("X-Request-ID", id)
+	return next().Header("X-Request-ID", id)
+}
+
+// validRequestID accepts IDs of up to 128 URL-safe characters, so a client
+// can't inject log-forging newlines or unbounded data through the header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
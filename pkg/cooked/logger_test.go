@@ -0,0 +1,81 @@
+package cooked
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func captureLogger(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	old := DefaultLogger
+	DefaultLogger = NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { DefaultLogger = old })
+	return &buf
+}
+
+func TestRequestIDGeneratesAndLogsID(t *testing.T) {
+	buf := captureLogger(t)
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	var seen string
+	resp := RequestID(ctx, func() Response {
+		seen = ctx.RequestID()
+		ctx.Logger().Info("loaded posts", "count", 3)
+		return ctx.NoContent()
+	})
+
+	if len(seen) != 36 {
+		t.Fatalf("RequestID = %q, want a generated UUID", seen)
+	}
+	if resp.Headers["X-Request-ID"] != seen {
+		t.Errorf("response X-Request-ID = %q, want %q", resp.Headers["X-Request-ID"], seen)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	if line["request_id"] != seen || line["msg"] != "loaded posts" || line["count"] != float64(3) {
+		t.Errorf("log line = %v", line)
+	}
+}
+
+func TestRequestIDPropagatesIncomingHeader(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Request-ID", "lb-7f3a.2")
+	ctx := NewContext(httptest.NewRecorder(), r)
+
+	resp := RequestID(ctx, func() Response { return ctx.NoContent() })
+	if ctx.RequestID() != "lb-7f3a.2" || resp.Headers["X-Request-ID"] != "lb-7f3a.2" {
+		t.Errorf("RequestID = %q, header = %q, want lb-7f3a.2", ctx.RequestID(), resp.Headers["X-Request-ID"])
+	}
+	if got := auditRequestID(ctx); got != "lb-7f3a.2" {
+		t.Errorf("auditRequestID = %q, want lb-7f3a.2", got)
+	}
+}
+
+func TestRequestIDReplacesMalformedHeader(t *testing.T) {
+	for _, bad := range []string{"bad id\nforged=1", strings.Repeat("a", 129)} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header["X-Request-Id"] = []string{bad}
+		ctx := NewContext(httptest.NewRecorder(), r)
+
+		RequestID(ctx, func() Response { return ctx.NoContent() })
+		if ctx.RequestID() == bad || len(ctx.RequestID()) != 36 {
+			t.Errorf("RequestID kept malformed header %q", bad)
+		}
+	}
+}
+
+func TestContextLoggerWithoutRequestID(t *testing.T) {
+	buf := captureLogger(t)
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.Logger().Warn("no id")
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("expected no request_id attribute, got %s", buf.String())
+	}
+}
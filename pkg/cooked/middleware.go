@@ -1,5 +1,12 @@
 package cooked
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // RunMiddleware executes a middleware stack around a handler.
 // Middleware functions are called in order, each wrapping the next.
 func RunMiddleware(ctx *Context, middleware []MiddlewareFunc, handler func() Response) Response {
@@ -19,3 +26,92 @@ func RunMiddleware(ctx *Context, middleware []MiddlewareFunc, handler func() Res
 
 	return next()
 }
+
+// MiddlewareStack registers middleware under names, so shared middleware such
+// as auth, CORS and rate limiting is declared once and routes refer to it by
+// name: r.Group("/api", body, "auth", "throttle").
+type MiddlewareStack struct {
+	mu    sync.RWMutex
+	named map[string]MiddlewareFunc
+}
+
+// NewMiddlewareStack returns an empty MiddlewareStack.
+func NewMiddlewareStack() *MiddlewareStack {
+	return &MiddlewareStack{named: map[string]MiddlewareFunc{}}
+}
+
+// Register stores mw under name. Several middleware run in the order given,
+// as one unit, and may name other middleware in the stack. It panics on an
+// empty or already registered name.
+func (s *MiddlewareStack) Register(name string, mw ...any) *MiddlewareStack {
+	if strings.TrimSpace(name) == "" {
+		panic("pickle: middleware name must not be empty")
+	}
+	if len(mw) == 0 {
+		panic(fmt.Sprintf("pickle: middleware %q registered without a middleware", name))
+	}
+	chain := make([]MiddlewareFunc, 0, len(mw))
+	for _, m := range mw {
+		if ref, ok := m.(string); ok {
+			chain = append(chain, s.byName(ref))
+			continue
+		}
+		chain = append(chain, resolveMiddleware([]any{m})...)
+	}
+	fn := chain[0]
+	if len(chain) > 1 {
+		fn = func(ctx *Context, next func() Response) Response {
+			return RunMiddleware(ctx, chain, next)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.named[name]; exists {
+		panic(fmt.Sprintf("pickle: middleware %q registered twice", name))
+	}
+	s.named[name] = fn
+	return s
+}
+
+// Lookup returns the middleware registered under name.
+func (s *MiddlewareStack) Lookup(name string) (MiddlewareFunc, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn, ok := s.named[name]
+	return fn, ok
+}
+
+// byName defers the lookup of a named middleware to request time.
+func (s *MiddlewareStack) byName(name string) MiddlewareFunc {
+	return func(ctx *Context, next func() Response) Response {
+		fn, ok := s.Lookup(name)
+		if !ok {
+			panic(fmt.Sprintf("pickle: middleware %q is not registered", name))
+		}
+		return fn(ctx, next)
+	}
+}
+
+// Names returns the registered names in sorted order.
+func (s *MiddlewareStack) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.named))
+	for name := range s.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedMiddleware is the application's registry, used when a route or group
+// names its middleware with a string.
+var namedMiddleware = NewMiddlewareStack()
+
+// RegisterMiddleware registers middleware under name for use in routes. Names
+// are resolved when a request is served, so registration may happen in init
+// functions that run after the route variables are built.
+func RegisterMiddleware(name string, mw ...any) {
+	namedMiddleware.Register(name, mw...)
+}
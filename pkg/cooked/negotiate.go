@@ -0,0 +1,145 @@
+package cooked
+
+import (
+	"bytes"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// negotiableTypes are the media types Negotiate can produce, in order of
+// preference when the client accepts several equally.
+var negotiableTypes = []string{"application/json", "application/xml", "text/xml"}
+
+// XML returns an XML response with the given status code and data. data is
+// encoded with encoding/xml, so give it a single root element: a struct,
+// ideally with an XMLName field. A map[string]string, such as the error
+// bodies of NotFound and BadRequest, is written as <response> with one child
+// element per key.
+func (c *Context) XML(status int, data any) Response {
+	return Response{
+		StatusCode: status,
+		Body:       data,
+		Headers:    map[string]string{"Content-Type": "application/xml; charset=utf-8"},
+	}
+}
+
+// Negotiate returns data as JSON or XML, whichever the request's Accept
+// header prefers. JSON is used when Accept is missing, */*, or ranks both
+// equally. When Accept names only types Pickle can't produce, the response
+// is 406 Not Acceptable.
+//
+//	return ctx.Negotiate(http.StatusOK, invoice)
+func (c *Context) Negotiate(status int, data any) Response {
+	accept := ""
+	if c.request != nil {
+		accept = c.request.Header.Get("Accept")
+	}
+	var resp Response
+	switch negotiateType(accept) {
+	case "application/json":
+		resp = c.JSON(status, data)
+	case "application/xml":
+		resp = c.XML(status, data)
+	case "text/xml":
+		resp = c.XML(status, data).Header("Content-Type", "text/xml; charset=utf-8")
+	default:
+		resp = c.JSON(http.StatusNotAcceptable, map[string]string{"error": "not acceptable"})
+	}
+	return resp.Header("Vary", "Accept")
+}
+
+// negotiateType returns the entry of negotiableTypes with the highest
+// quality in an Accept header, or "" when none is acceptable. Each type's
+// quality comes from the most specific range matching it.
+func negotiateType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return negotiableTypes[0]
+	}
+	best, bestQ := "", 0.0
+	for _, offer := range negotiableTypes {
+		q, specificity := 0.0, -1
+		for _, part := range strings.Split(accept, ",") {
+			mediaRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+			s := rangeSpecificity(mediaRange, offer)
+			if s <= specificity {
+				continue
+			}
+			specificity, q = s, 1.0
+			for _, param := range strings.Split(params, ";") {
+				if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						q = f
+					}
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// rangeSpecificity reports how specifically an Accept media range matches
+// offer: 2 for an exact match, 1 for type/*, 0 for */*, and -1 for none.
+func rangeSpecificity(mediaRange, offer string) int {
+	switch {
+	case mediaRange == offer:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
+
+// isXMLContentType reports whether a Content-Type header names an XML media
+// type, so Response.Write encodes the body as XML.
+func isXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// encodeXML encodes a response body as an XML document.
+func encodeXML(body any) ([]byte, error) {
+	if m, ok := body.(map[string]string); ok {
+		body = xmlMap(m)
+	}
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	if err := xml.NewEncoder(&b).Encode(body); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// xmlMap writes a map[string]string as <response><key>value</key>...</response>
+// with keys in sorted order.
+type xmlMap map[string]string
+
+func (m xmlMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	root := xml.StartElement{Name: xml.Name{Local: "response"}}
+	if err := e.EncodeToken(root); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := e.EncodeElement(m[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(root.End())
+}
@@ -0,0 +1,88 @@
+package cooked
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type negotiatedInvoice struct {
+	XMLName xml.Name `json:"-" xml:"invoice"`
+	Number  string   `json:"number" xml:"number"`
+	Total   int      `json:"total" xml:"total"`
+}
+
+func negotiate(t *testing.T, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/invoices/1", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	ctx := NewContext(httptest.NewRecorder(), req)
+	w := httptest.NewRecorder()
+	ctx.Negotiate(http.StatusOK, negotiatedInvoice{Number: "INV-1", Total: 42}).Write(w)
+	return w
+}
+
+func TestNegotiatePicksTypeFromAccept(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", "application/json", `{"number":"INV-1","total":42}`},
+		{"*/*", "application/json", `{"number":"INV-1","total":42}`},
+		{"application/json, application/xml", "application/json", `{"number":"INV-1","total":42}`},
+		{"application/xml", "application/xml; charset=utf-8", `<invoice><number>INV-1</number><total>42</total></invoice>`},
+		{"text/xml", "text/xml; charset=utf-8", `<invoice><number>INV-1</number><total>42</total></invoice>`},
+		{"application/json;q=0.5, application/xml", "application/xml; charset=utf-8", `<invoice>`},
+		{"*/*;q=0.1, application/xml;q=0.9", "application/xml; charset=utf-8", `<invoice>`},
+		{"application/*, application/json;q=0", "application/xml; charset=utf-8", `<invoice>`},
+	}
+	for _, tt := range tests {
+		w := negotiate(t, tt.accept)
+		if w.Code != http.StatusOK {
+			t.Errorf("Accept %q: status = %d, want 200", tt.accept, w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("Accept %q: body = %s, want it to contain %s", tt.accept, w.Body.String(), tt.body)
+		}
+		if w.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: Vary = %q, want Accept", tt.accept, w.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestNegotiateRejectsUnsupportedType(t *testing.T) {
+	for _, accept := range []string{"text/csv", "application/json;q=0, application/xml;q=0, text/xml;q=0"} {
+		w := negotiate(t, accept)
+		if w.Code != http.StatusNotAcceptable {
+			t.Errorf("Accept %q: status = %d, want 406", accept, w.Code)
+		}
+	}
+}
+
+func TestXMLWritesDocumentAndMapBodies(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	w := httptest.NewRecorder()
+	ctx.XML(http.StatusNotFound, map[string]string{"error": "not found", "code": "missing"}).Write(w)
+	want := xml.Header + `<response><code>missing</code><error>not found</error></response>`
+	if w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body.String(), want)
+	}
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+
+	// A body encoding/xml can't handle is a 500, as for JSON.
+	w = httptest.NewRecorder()
+	ctx.XML(http.StatusOK, map[string]int{"n": 1}).Write(w)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("unencodable body: status = %d, want 500", w.Code)
+	}
+}
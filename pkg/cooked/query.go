@@ -2,11 +2,15 @@ package cooked
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,6 +35,12 @@ func Query[T any](table string, connection ...string) *QueryBuilder[T] {
 	return q
 }
 
+// QueryOn starts a new query for the given model type that runs on conn
+// instead of the package DB. See QueryBuilder.On.
+func QueryOn[T any](conn *sql.DB, table string) *QueryBuilder[T] {
+	return Query[T](table).On(conn)
+}
+
 // visibilityMode controls which columns a query may return.
 type visibilityMode int
 
@@ -44,13 +54,18 @@ const (
 // QueryBuilder is the generic query builder for all models.
 type QueryBuilder[T any] struct {
 	table         string
-	connection    string // named connection ("" = default DB)
+	connection    string  // named connection ("" = default DB)
+	conn          *sql.DB // explicit connection set by On (nil = use connection)
 	conditions    []condition
 	orderBy       []string
+	groupBy       []string
+	having        []condition
 	limit         int
 	offset        int
 	eagerLoads    []string
 	selectedCols  []string
+	counts        []string // relations selected as {relation}_count by WithCount
+	distinct      bool
 	visibility    visibilityMode
 	tx            *sql.Tx            // transaction connection (nil = use global DB)
 	lockMode      string             // "", "FOR UPDATE", "FOR SHARE"
@@ -61,6 +76,8 @@ type QueryBuilder[T any] struct {
 	policyClause  string
 	policyArgs    []any
 	policyErr     error
+	alias         string // set on WhereHas subqueries that join a table to itself
+	depth         int    // WhereHas nesting depth, for unique self-join aliases
 }
 
 func (q *QueryBuilder[T]) WithPolicyContext(context PolicyContext) *QueryBuilder[T] {
@@ -103,6 +120,262 @@ func (q *QueryBuilder[T]) whereNotIn(column string, values any) *QueryBuilder[T]
 	return q
 }
 
+// WhereExists adds an EXISTS (subquery) condition for existence checks that
+// don't map onto a relationship. Placeholders in the subquery are written
+// from $1 and renumbered to follow the outer query's arguments.
+//
+// The subquery is raw SQL: correlating it to the outer row (e.g.
+// "comments.post_id = posts.id") is the caller's job, and identifiers must
+// never come from user input. Pass values through args.
+func (q *QueryBuilder[T]) WhereExists(subquery string, args ...any) *QueryBuilder[T] {
+	q.conditions = append(q.conditions, condition{column: subquery, op: "EXISTS", value: args})
+	return q
+}
+
+// WhereNotExists adds a NOT EXISTS (subquery) condition. See WhereExists.
+func (q *QueryBuilder[T]) WhereNotExists(subquery string, args ...any) *QueryBuilder[T] {
+	q.conditions = append(q.conditions, condition{column: subquery, op: "NOT EXISTS", value: args})
+	return q
+}
+
+// queryRelation describes how a related table joins to its parent: rows of
+// table where table.column = parent.parentColumn.
+type queryRelation struct {
+	table        string
+	column       string
+	parentColumn string
+
+	// Set for many-to-many relations: the related table joins pivot on
+	// pivot.pivotColumn = table.column, and pivot.pivotParentColumn matches
+	// the parent's parentColumn.
+	pivot             string
+	pivotColumn       string
+	pivotParentColumn string
+}
+
+// queryRelations maps parent table → relation name → join, registered by the
+// generated relations_gen.go from the schema's foreign keys.
+var queryRelations = map[string]map[string]queryRelation{}
+
+func registerRelation(parent, name, table, column, parentColumn string) {
+	if queryRelations[parent] == nil {
+		queryRelations[parent] = map[string]queryRelation{}
+	}
+	queryRelations[parent][name] = queryRelation{table: table, column: column, parentColumn: parentColumn}
+}
+
+// registerPivotRelation registers a many-to-many relation from parent to
+// table through the pivot table's two foreign keys.
+func registerPivotRelation(parent, name, pivot, pivotParentColumn, parentColumn, pivotColumn, table, column string) {
+	if queryRelations[parent] == nil {
+		queryRelations[parent] = map[string]queryRelation{}
+	}
+	queryRelations[parent][name] = queryRelation{
+		table: table, column: column, parentColumn: parentColumn,
+		pivot: pivot, pivotColumn: pivotColumn, pivotParentColumn: pivotParentColumn,
+	}
+}
+
+// WhereHas keeps only rows with at least one related row matching fn, via a
+// correlated EXISTS subquery. Relations come from foreign keys: a table has
+// its children by table name ("posts") and its parent by the foreign key
+// column without _id ("user" for posts.user_id). fn may be nil, and may
+// call WhereHas again to follow a further relation.
+//
+// Conditions inside fn are unqualified column names of the related table.
+// Row policies and soft deletes of the related table are not applied. An
+// unknown relation panics — this is a programming error, not user input.
+func (q *QueryBuilder[T]) WhereHas(relation string, fn func(*QueryBuilder[any])) *QueryBuilder[T] {
+	return q.whereHas("EXISTS", relation, fn)
+}
+
+// WhereDoesntHave keeps only rows with no related row matching fn. See WhereHas.
+func (q *QueryBuilder[T]) WhereDoesntHave(relation string, fn func(*QueryBuilder[any])) *QueryBuilder[T] {
+	return q.whereHas("NOT EXISTS", relation, fn)
+}
+
+func (q *QueryBuilder[T]) whereHas(op, relation string, fn func(*QueryBuilder[any])) *QueryBuilder[T] {
+	rel, ok := queryRelations[q.table][relation]
+	if !ok {
+		panic(fmt.Sprintf("pickle: WhereHas: %s has no relation %q", q.table, relation))
+	}
+	sub := &QueryBuilder[any]{table: rel.table, depth: q.depth + 1}
+	if rel.table == q.table {
+		sub.alias = fmt.Sprintf("%s_%d", rel.table, sub.depth)
+	}
+	if fn != nil {
+		fn(sub)
+	}
+	subquery, args := sub.buildRelated("1", q.ref(), rel)
+	q.conditions = append(q.conditions, condition{column: subquery, op: op, value: args})
+	return q
+}
+
+// WithCount selects the number of related rows alongside each record, as a
+// correlated subquery aliased {relation}_count ("posts.author_id" becomes
+// posts_author_id_count). It is scanned into the model field tagged
+// count:"{relation}", which the generator adds for every has-many and
+// many-to-many relation — so a list of users can show how many posts each
+// has without loading them. Relations are those of WhereHas; an unknown
+// relation panics.
+func (q *QueryBuilder[T]) WithCount(relation string) *QueryBuilder[T] {
+	if _, ok := queryRelations[q.table][relation]; !ok {
+		panic(fmt.Sprintf("pickle: WithCount: %s has no relation %q", q.table, relation))
+	}
+	q.counts = append(q.counts, relation)
+	return q
+}
+
+// countColumn is the select alias WithCount gives a relation's count.
+func countColumn(relation string) string {
+	return strings.ReplaceAll(relation, ".", "_") + "_count"
+}
+
+// appendCounts writes the WithCount subqueries after the select list.
+func (q *QueryBuilder[T]) appendCounts(d Dialect, b *strings.Builder) {
+	for _, relation := range q.counts {
+		rel := queryRelations[q.table][relation]
+		sub := &QueryBuilder[any]{table: rel.table, depth: q.depth + 1}
+		if rel.table == q.table {
+			sub.alias = fmt.Sprintf("%s_%d", rel.table, sub.depth)
+		}
+		subquery, _ := sub.buildRelated("COUNT(*)", q.ref(), rel)
+		b.WriteString(", (" + subquery + ") AS " + d.Quote(countColumn(relation)))
+	}
+}
+
+// ref is the name the outer query's columns are qualified with.
+func (q *QueryBuilder[T]) ref() string {
+	if q.alias != "" {
+		return q.alias
+	}
+	return q.table
+}
+
+// buildRelated renders SELECT expr FROM the related table, correlated to the
+// parent row (through the pivot table for a many-to-many relation), followed
+// by fn's conditions. Placeholders start at $1; the EXISTS condition
+// renumbers them into the outer query.
+func (q *QueryBuilder[T]) buildRelated(expr, parent string, rel queryRelation) (string, []any) {
+	d := fragmentDialect{currentDialect()}
+	var b strings.Builder
+	b.WriteString("SELECT " + expr + " FROM " + quoteQualified(d, q.table))
+	if q.alias != "" {
+		b.WriteString(" AS " + d.Quote(q.alias))
+	}
+	if rel.pivot != "" {
+		// An IN subquery rather than a join keeps fn's unqualified columns
+		// unambiguous when the pivot shares column names with the table.
+		pivot := quoteQualified(d, rel.pivot)
+		b.WriteString(" WHERE " + quoteQualified(d, q.ref()) + "." + d.Quote(rel.column) + " IN (SELECT " + pivot + "." + d.Quote(rel.pivotColumn) +
+			" FROM " + pivot + " WHERE " + pivot + "." + d.Quote(rel.pivotParentColumn) + " = " + quoteQualified(d, parent) + "." + d.Quote(rel.parentColumn) + ")")
+	} else {
+		b.WriteString(" WHERE " + quoteQualified(d, q.ref()) + "." + d.Quote(rel.column) + " = " + quoteQualified(d, parent) + "." + d.Quote(rel.parentColumn))
+	}
+	var args []any
+	for _, c := range q.conditions {
+		b.WriteString(" AND ")
+		appendCondition(d, &b, &args, c)
+	}
+	return b.String(), args
+}
+
+// WhereRaw adds a raw SQL condition for predicates the column/operator
+// scopes can't express, such as lower(email) = lower($1). Placeholders are
+// written from $1 and renumbered to follow the surrounding conditions; the
+// fragment is wrapped in parentheses so an OR inside it stays contained.
+//
+// The expression is trusted SQL and is not escaped. Never build it from user
+// input — pass values through args.
+func (q *QueryBuilder[T]) WhereRaw(expr string, args ...any) *QueryBuilder[T] {
+	q.conditions = append(q.conditions, condition{column: expr, op: "RAW", value: args})
+	return q
+}
+
+// WhereJSON matches rows where the value at path inside a JSONB column equals
+// value, compared as text: WhereJSON("settings", "theme.color", "dark")
+// becomes settings #>> '{theme,color}' = $1. Path segments are object keys or
+// array indexes separated by dots, and may only contain letters, digits,
+// underscores and hyphens.
+//
+// JSON operators are PostgreSQL-only. Calling WhereJSON on another driver, or
+// with a malformed path, panics — both are programming errors.
+func (q *QueryBuilder[T]) WhereJSON(column, path string, value any) *QueryBuilder[T] {
+	q.conditions = append(q.conditions, jsonPathCondition(column, path, value))
+	return q
+}
+
+// WhereJSONContains matches rows whose JSONB column contains value, using
+// column @> $1::jsonb. Strings, []byte and json.RawMessage are sent as JSON
+// text as-is; anything else is encoded with encoding/json when the query
+// runs. PostgreSQL-only, like WhereJSON.
+func (q *QueryBuilder[T]) WhereJSONContains(column string, value any) *QueryBuilder[T] {
+	q.conditions = append(q.conditions, jsonContainsCondition(column, value))
+	return q
+}
+
+// jsonPathCondition builds the condition for WhereJSON. The path is written
+// into the SQL as a literal, so it is validated rather than escaped.
+func jsonPathCondition(column, path string, value any) condition {
+	requirePostgres("WhereJSON")
+	segments := strings.Split(path, ".")
+	for _, s := range segments {
+		if !validJSONPathSegment(s) {
+			panic("pickle: WhereJSON path must be dot-separated keys of letters, digits, '_' or '-', got: " + path)
+		}
+	}
+	return condition{column: column, op: "JSON PATH", value: jsonPathValue{path: segments, value: value}}
+}
+
+// jsonContainsCondition builds the condition for WhereJSONContains.
+func jsonContainsCondition(column string, value any) condition {
+	requirePostgres("WhereJSONContains")
+	switch value.(type) {
+	case string, []byte, json.RawMessage:
+	default:
+		value = jsonValue{value}
+	}
+	return condition{column: column, op: "@>", value: value}
+}
+
+// requirePostgres panics when the configured driver isn't PostgreSQL.
+func requirePostgres(method string) {
+	if _, ok := currentDialect().(postgresDialect); !ok {
+		panic("pickle: " + method + " requires PostgreSQL, database driver is " + strconv.Quote(DatabaseDriver))
+	}
+}
+
+func validJSONPathSegment(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// jsonPathValue carries a WhereJSON path alongside the compared value.
+type jsonPathValue struct {
+	path  []string
+	value any
+}
+
+// jsonValue encodes a WhereJSONContains value as JSON when it is sent to the
+// driver, so encoding errors surface from the query instead of the builder.
+type jsonValue struct{ v any }
+
+func (j jsonValue) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, fmt.Errorf("pickle: encoding JSON query argument: %w", err)
+	}
+	return string(data), nil
+}
+
 // OrderBy adds an ORDER BY clause. The column name must be a valid SQL
 // identifier (letters, digits, underscores only). Direction must be ASC or DESC.
 // Invalid values panic — this is a programming error, not user input.
@@ -122,6 +395,139 @@ func (q *QueryBuilder[T]) OrderBy(column, direction string) *QueryBuilder[T] {
 	return q
 }
 
+// OrderByAllowed orders by column only if it is one of allowed, so
+// controllers can sort by a query-string parameter without validating it
+// themselves. direction is matched case-insensitively; "" means ASC. An
+// unlisted column or a direction other than asc/desc adds no ordering, so
+// chain an OrderBy after it as the fallback:
+//
+//	q.OrderByAllowed(ctx.Query("sort"), ctx.Query("dir"), "title", "created_at").
+//		OrderBy("id", "ASC")
+//
+// Entries of allowed must be valid identifiers; anything else panics, as
+// with OrderBy.
+func (q *QueryBuilder[T]) OrderByAllowed(column, direction string, allowed ...string) *QueryBuilder[T] {
+	if clause, ok := allowedOrderBy(column, direction, allowed); ok {
+		q.orderBy = append(q.orderBy, clause)
+	}
+	return q
+}
+
+// allowedOrderBy returns the ORDER BY term for OrderByAllowed, and false
+// when column or direction isn't acceptable.
+func allowedOrderBy(column, direction string, allowed []string) (string, bool) {
+	found := false
+	for _, a := range allowed {
+		if !validSQLIdentifier(a) {
+			panic("pickle: OrderByAllowed column must be a valid identifier, got: " + a)
+		}
+		if a == column {
+			found = true
+		}
+	}
+	dir := strings.ToUpper(strings.TrimSpace(direction))
+	if dir == "" {
+		dir = "ASC"
+	}
+	if !found || (dir != "ASC" && dir != "DESC") {
+		return "", false
+	}
+	return column + " " + dir, true
+}
+
+// DefaultOrderByPrimaryKey makes All() order by the model's primary key when
+// no OrderBy is set, so results are stable across runs and pages. Off by
+// default: set it once at startup.
+var DefaultOrderByPrimaryKey = false
+
+// OrderByPrimaryKey orders by the model's primary key ascending. Primary key
+// fields are tagged pickle:"pk" by the model generator; models without the
+// tag fall back to the "id" column.
+func (q *QueryBuilder[T]) OrderByPrimaryKey() *QueryBuilder[T] {
+	q.orderBy = append(q.orderBy, q.primaryKeyOrder()...)
+	return q
+}
+
+func (q *QueryBuilder[T]) primaryKeyOrder() []string {
+	var zero T
+	var order []string
+	for _, col := range primaryKeyColumns(&zero) {
+		order = append(order, col+" ASC")
+	}
+	return order
+}
+
+// defaultOrder is the ORDER BY All() uses: the query's own, or the primary
+// key under DefaultOrderByPrimaryKey. The key is skipped for grouped queries
+// and for SELECT DISTINCT without the key in its select list, where the
+// database would reject or change the meaning of the ordering.
+func (q *QueryBuilder[T]) defaultOrder() []string {
+	if !DefaultOrderByPrimaryKey || len(q.orderBy) > 0 || len(q.groupBy) > 0 {
+		return q.orderBy
+	}
+	if q.distinct {
+		selected := map[string]bool{}
+		for _, col := range q.columns() {
+			selected[col] = true
+		}
+		var zero T
+		for _, col := range primaryKeyColumns(&zero) {
+			if !selected[col] {
+				return q.orderBy
+			}
+		}
+	}
+	return q.primaryKeyOrder()
+}
+
+// primaryKeyColumns returns the db columns of fields tagged pickle:"pk", or
+// "id" when the struct has no tagged fields but does have an id column.
+func primaryKeyColumns(v any) []string {
+	rt := reflect.TypeOf(v)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	var cols []string
+	hasID := false
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if field.Tag.Get("pickle") == "pk" {
+			cols = append(cols, tag)
+		}
+		if tag == "id" {
+			hasID = true
+		}
+	}
+	if len(cols) == 0 && hasID {
+		cols = []string{"id"}
+	}
+	return cols
+}
+
+// GroupBy adds a GROUP BY clause, usually paired with Aggregate. Column names
+// must be valid identifiers — invalid values panic, like OrderBy.
+func (q *QueryBuilder[T]) GroupBy(columns ...string) *QueryBuilder[T] {
+	for _, col := range columns {
+		if !validSQLIdentifier(col) {
+			panic("pickle: GroupBy column must be a valid identifier, got: " + col)
+		}
+		q.groupBy = append(q.groupBy, col)
+	}
+	return q
+}
+
+// Having adds a HAVING condition, e.g. Having("COUNT(*) > $1", 10).
+// Placeholders are written from $1 and renumbered to follow the WHERE
+// arguments. Like WhereRaw, the expression is trusted SQL and is not escaped.
+func (q *QueryBuilder[T]) Having(expr string, args ...any) *QueryBuilder[T] {
+	q.having = append(q.having, condition{column: expr, op: "RAW", value: args})
+	return q
+}
+
 // validSQLIdentifier returns true if s is a simple SQL identifier:
 // non-empty, starts with a letter or underscore, contains only [a-zA-Z0-9_].
 func validSQLIdentifier(s string) bool {
@@ -158,6 +564,26 @@ func (q *QueryBuilder[T]) AnyOwner() *QueryBuilder[T] {
 	return q
 }
 
+// Select restricts the query to the given columns. Only the struct fields
+// whose db tags match are populated; the rest keep their zero values. Column
+// names must be valid identifiers — invalid values panic, like OrderBy.
+func (q *QueryBuilder[T]) Select(columns ...string) *QueryBuilder[T] {
+	for _, col := range columns {
+		if !validSQLIdentifier(col) {
+			panic("pickle: Select column must be a valid identifier, got: " + col)
+		}
+		q.addSelect(col)
+	}
+	return q
+}
+
+// Distinct drops duplicate rows: the query selects DISTINCT over the selected
+// columns (every column without Select), and Count counts distinct rows.
+func (q *QueryBuilder[T]) Distinct() *QueryBuilder[T] {
+	q.distinct = true
+	return q
+}
+
 // addSelect adds a column to the explicit select list.
 func (q *QueryBuilder[T]) addSelect(col string) {
 	q.selectedCols = append(q.selectedCols, col)
@@ -232,6 +658,16 @@ func (sb *ScopeBuilder[T]) whereNotIn(column string, values any) *ScopeBuilder[T
 	return sb
 }
 
+func (sb *ScopeBuilder[T]) whereJSON(column, path string, value any) *ScopeBuilder[T] {
+	sb.conditions = append(sb.conditions, jsonPathCondition(column, path, value))
+	return sb
+}
+
+func (sb *ScopeBuilder[T]) whereJSONContains(column string, value any) *ScopeBuilder[T] {
+	sb.conditions = append(sb.conditions, jsonContainsCondition(column, value))
+	return sb
+}
+
 // OrderBy adds an ORDER BY clause to the scope builder.
 func (sb *ScopeBuilder[T]) OrderBy(column, direction string) *ScopeBuilder[T] {
 	if !validSQLIdentifier(column) {
@@ -274,6 +710,9 @@ func (q *QueryBuilder[T]) db() dbExecutor {
 	if q.tx != nil {
 		return q.tx
 	}
+	if q.conn != nil {
+		return q.conn
+	}
 	if q.connection != "" {
 		// Try ManagedConnections first (hot-reloadable)
 		if mc := acquireConnection(q.connection); mc != nil {
@@ -306,6 +745,20 @@ func (q *QueryBuilder[T]) setTx(tx *sql.Tx) {
 	q.tx = tx
 }
 
+// On runs the query on conn, such as a read replica from Connection, instead
+// of the model's connection. A transaction set with UseTransaction still
+// takes precedence, and a nil conn restores the default.
+//
+//	replica, err := models.Connection("replica")
+//	if err != nil {
+//	    return ctx.Error(err)
+//	}
+//	posts, err := models.QueryPost().On(replica).All()
+func (q *QueryBuilder[T]) On(conn *sql.DB) *QueryBuilder[T] {
+	q.conn = conn
+	return q
+}
+
 // UseTransaction associates this query with an existing transaction. This is
 // intended for application-managed transactions that install request-local
 // database state (for example PostgreSQL RLS settings) before building queries.
@@ -372,7 +825,18 @@ func (q *QueryBuilder[T]) applyLockTimeout() error {
 	return nil
 }
 
-// EagerLoad marks a relationship for eager loading.
+// EagerLoad loads related records along with the results of All and First,
+// into the model field tagged rel:"{relation}" that the generator adds for
+// each relation: a slice for has-many and many-to-many, a pointer for
+// belongs-to. Relations are those of WhereHas, and a dotted path follows
+// them further:
+//
+//	teams, err := models.QueryTeam().EagerLoad("transfers.team").SelectAll().All()
+//
+// Each relation is loaded for all results at once with a single IN query,
+// so the cost is one query per relation in the path, not per row. Paths
+// are limited to three relations. An unknown relation, or a model without
+// the field, makes All or First return an error.
 func (q *QueryBuilder[T]) EagerLoad(relation string) *QueryBuilder[T] {
 	q.eagerLoads = append(q.eagerLoads, relation)
 	return q
@@ -397,13 +861,21 @@ func (q *QueryBuilder[T]) First() (*T, error) {
 	query, args := q.buildSelect()
 	db := q.db()
 	defer q.releaseConn()
-	row := db.QueryRow(query, args...)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, mapLockError(q.table, err)
+	}
+	defer rows.Close()
 
-	var result T
-	if err := scanRow(row, &result); err != nil {
+	result, err := scanFirst[T](rows)
+	if err != nil {
 		return nil, mapLockError(q.table, err)
 	}
-	return &result, nil
+	rows.Close()
+	if err := loadEager(db, q.table, q.eagerLoads, q.policyContext, []reflect.Value{reflect.ValueOf(result).Elem()}); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // All returns all matching records.
@@ -418,7 +890,7 @@ func (q *QueryBuilder[T]) All() ([]T, error) {
 		return nil, mapLockError(q.table, err)
 	}
 
-	query, args := q.buildSelect()
+	query, args := q.buildSelectOrderedBy(q.defaultOrder())
 	db := q.db()
 	defer q.releaseConn()
 	rows, err := db.Query(query, args...)
@@ -427,7 +899,15 @@ func (q *QueryBuilder[T]) All() ([]T, error) {
 	}
 	defer rows.Close()
 
-	return scanRows[T](rows)
+	results, err := scanRows[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := loadEager(db, q.table, q.eagerLoads, q.policyContext, eagerRecords(results)); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
 // Count returns the number of matching records.
@@ -443,42 +923,273 @@ func (q *QueryBuilder[T]) Count() (int64, error) {
 	return count, err
 }
 
+// CountDistinct returns the number of distinct non-NULL values of column
+// among the matching records. The column name must be a valid identifier —
+// invalid values panic, like Select.
+func (q *QueryBuilder[T]) CountDistinct(column string) (int64, error) {
+	if !validSQLIdentifier(column) {
+		panic("pickle: CountDistinct column must be a valid identifier, got: " + column)
+	}
+	if err := q.preparePolicy("select"); err != nil {
+		return 0, err
+	}
+	query, args := q.buildCountDistinct(column)
+	db := q.db()
+	defer q.releaseConn()
+	var count int64
+	err := db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// CountEstimate returns a fast approximate row count for very large tables.
+// On Postgres, an unfiltered query reads the planner's estimate from
+// pg_class.reltuples instead of scanning the table. The estimate is only as
+// fresh as the last VACUUM/ANALYZE and can drift noticeably between them —
+// use it for "about N results" UIs, never for anything that must be exact.
+//
+// Filtered or Distinct queries, row-policy-protected reads, other dialects, and tables
+// Postgres hasn't analyzed yet fall back to an exact Count().
+func (q *QueryBuilder[T]) CountEstimate() (int64, error) {
+	if err := q.preparePolicy("select"); err != nil {
+		return 0, err
+	}
+	d := currentDialect()
+	if _, ok := d.(postgresDialect); !ok || len(q.conditions) > 0 || q.distinct || q.policyClause != "" {
+		return q.Count()
+	}
+	db := q.db()
+	var estimate int64
+	// to_regclass parses its argument as SQL, so the name is quoted the way
+	// the queries quote it: "Events" stays case-sensitive and "audit.log"
+	// resolves in the audit schema.
+	err := db.QueryRow("SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)", quoteQualified(d, q.table)).Scan(&estimate)
+	q.releaseConn()
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	if err == nil && estimate >= 0 {
+		return estimate, nil
+	}
+	return q.Count()
+}
+
 // aggregate runs a SQL aggregate function (SUM, AVG, etc.) on a column.
 func (q *QueryBuilder[T]) aggregate(fn, column string) (*float64, error) {
+	var result *float64
+	err := q.aggregateInto(&result, fn, column)
+	return result, err
+}
+
+// aggregateInto runs a SQL aggregate function on a column and scans the
+// result into dest. The generated Sum/Avg methods of DECIMAL columns scan
+// into a decimal.NullDecimal, so money totals never pass through float64.
+func (q *QueryBuilder[T]) aggregateInto(dest any, fn, column string) error {
 	if err := q.preparePolicy("select"); err != nil {
-		return nil, err
+		return err
 	}
 	query, args := q.buildAggregate(fn, column)
 	db := q.db()
 	defer q.releaseConn()
-	var result *float64
-	err := db.QueryRow(query, args...).Scan(&result)
-	return result, err
+	return db.QueryRow(query, args...).Scan(dest)
 }
 
 func (q *QueryBuilder[T]) buildAggregate(fn, column string) (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("SELECT %s(%s) FROM %s", fn, column, q.table))
-	args := q.appendWhere(&b)
+	b.WriteString(fmt.Sprintf("SELECT %s(%s) FROM %s", fn, quoteQualified(d, column), quoteQualified(d, q.table)))
+	args := q.appendWhere(d, &b)
 	return b.String(), args
 }
 
+// Aggregate runs SELECT selectExpr with the query's conditions, GROUP BY,
+// HAVING, ORDER BY, and LIMIT, and scans the result into dest. dest is a
+// pointer to a struct (first row, sql.ErrNoRows if none) or to a slice of
+// structs (every row). Result columns are matched to fields by db tag, so
+// alias computed columns:
+//
+//	var counts []struct {
+//		UserID string `db:"user_id"`
+//		Posts  int64  `db:"posts"`
+//	}
+//	err := models.QueryPost().GroupBy("user_id").
+//		Aggregate(&counts, "user_id, COUNT(*) AS posts")
+//
+// selectExpr is trusted SQL and is not escaped.
+func (q *QueryBuilder[T]) Aggregate(dest any, selectExpr string) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("pickle: Aggregate dest must be a non-nil pointer, got %T", dest)
+	}
+	target := rv.Elem()
+	isSlice := target.Kind() == reflect.Slice
+	if isSlice && target.Type().Elem().Kind() != reflect.Struct || !isSlice && target.Kind() != reflect.Struct {
+		return fmt.Errorf("pickle: Aggregate dest must point to a struct or slice of structs, got %T", dest)
+	}
+	if err := q.preparePolicy("select"); err != nil {
+		return err
+	}
+
+	query, args := q.buildGroupedSelect(selectExpr)
+	db := q.db()
+	defer q.releaseConn()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if !isSlice {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+		return rows.Scan(dbScanDestFor(dest, cols)...)
+	}
+
+	results := reflect.MakeSlice(target.Type(), 0, 0)
+	for rows.Next() {
+		item := reflect.New(target.Type().Elem())
+		if err := rows.Scan(dbScanDestFor(item.Interface(), cols)...); err != nil {
+			return err
+		}
+		results = reflect.Append(results, item.Elem())
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	target.Set(results)
+	return nil
+}
+
+func (q *QueryBuilder[T]) buildGroupedSelect(selectExpr string) (string, []any) {
+	d := currentDialect()
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	b.WriteString(selectExpr)
+	b.WriteString(" FROM ")
+	b.WriteString(quoteQualified(d, q.table))
+
+	args := q.appendWhere(d, &b)
+	args = q.appendGroupBy(d, &b, args)
+	q.appendOrderLimit(d, &b, q.orderBy)
+	return b.String(), args
+}
+
+// appendGroupBy writes GROUP BY and HAVING, numbering HAVING placeholders
+// after the args already bound.
+func (q *QueryBuilder[T]) appendGroupBy(d Dialect, b *strings.Builder, args []any) []any {
+	if len(q.groupBy) > 0 {
+		b.WriteString(" GROUP BY ")
+		b.WriteString(quoteList(d, q.groupBy))
+	}
+	for i, c := range q.having {
+		if i == 0 {
+			b.WriteString(" HAVING ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		appendCondition(d, b, &args, c)
+	}
+	return args
+}
+
+func (q *QueryBuilder[T]) appendOrderLimit(d Dialect, b *strings.Builder, orderBy []string) {
+	if len(orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		for i, order := range orderBy {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			column, dir, _ := strings.Cut(order, " ")
+			b.WriteString(quoteQualified(d, column) + " " + dir)
+		}
+	}
+	if q.limit > 0 {
+		b.WriteString(fmt.Sprintf(" LIMIT %d", q.limit))
+	}
+	if q.offset > 0 {
+		b.WriteString(fmt.Sprintf(" OFFSET %d", q.offset))
+	}
+}
+
 // Create inserts a new record and scans the returned row back into the struct,
 // populating DB-generated values (UUIDs, timestamps, defaults).
 func (q *QueryBuilder[T]) Create(record *T) error {
 	if err := evaluateRowPolicyRecord(q.table, "insert", q.policyContext, record); err != nil {
 		return err
 	}
+	stampTimestamps(record, true)
 	query, args := buildInsert(q.table, record)
 	cols := dbColumns(record)
-	query += " RETURNING " + strings.Join(cols, ", ")
+	query += " RETURNING " + quoteList(currentDialect(), cols)
 	db := q.db()
 	defer q.releaseConn()
 	row := db.QueryRow(query, args...)
-	return row.Scan(dbScanDest(record)...)
+	return mapUniqueError(q.table, row.Scan(dbScanDest(record)...))
 }
 
-// Update updates an existing record.
+// CreateMany inserts records with multi-row INSERT statements. Consecutive
+// records that set the same columns share a statement, split to stay under
+// the driver's placeholder limit. Unlike Create, records are not read back,
+// so database-generated ids and defaults are not written to them.
+//
+// Statements run in order; if one fails, the error reports how many records
+// were already inserted. Run CreateMany in a transaction for all-or-nothing.
+func (q *QueryBuilder[T]) CreateMany(records []*T) error {
+	for _, record := range records {
+		if err := evaluateRowPolicyRecord(q.table, "insert", q.policyContext, record); err != nil {
+			return err
+		}
+	}
+	db := q.db()
+	defer q.releaseConn()
+
+	limit := maxInsertParams()
+	inserted := 0
+	var cols []string
+	var rows [][]any
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		query, args := buildInsertRows(q.table, cols, rows)
+		if _, err := db.Exec(query, args...); err != nil {
+			return fmt.Errorf("pickle: CreateMany into %s inserted %d of %d records: %w", q.table, inserted, len(records), mapUniqueError(q.table, err))
+		}
+		inserted += len(rows)
+		rows = rows[:0]
+		return nil
+	}
+	for _, record := range records {
+		stampTimestamps(record, true)
+		recordCols, vals := insertColumns(record)
+		if len(rows) > 0 && (strings.Join(recordCols, ",") != strings.Join(cols, ",") || (len(rows)+1)*len(cols) > limit) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		cols = recordCols
+		rows = append(rows, vals)
+	}
+	return flush()
+}
+
+// maxInsertParams is the most placeholders one INSERT may bind: 65535 on
+// Postgres and MySQL, 32766 on SQLite.
+func maxInsertParams() int {
+	if DatabaseDriver == "sqlite" {
+		return 32766
+	}
+	return 65535
+}
+
+// Update updates an existing record and refreshes its updated_at.
 func (q *QueryBuilder[T]) Update(record *T) error {
 	if err := q.preparePolicy("update_old"); err != nil {
 		return err
@@ -486,11 +1197,58 @@ func (q *QueryBuilder[T]) Update(record *T) error {
 	if err := evaluateRowPolicyRecord(q.table, "update_new", q.policyContext, record); err != nil {
 		return err
 	}
+	stampTimestamps(record, false)
 	query, args := buildUpdate(q.table, record, q.conditions, q.policyClause, q.policyArgs)
 	db := q.db()
 	defer q.releaseConn()
 	_, err := db.Exec(query, args...)
-	return err
+	return mapUniqueError(q.table, err)
+}
+
+// UpdateColumns updates only the named columns of an existing record and
+// leaves the rest of the row untouched. Use it for PATCH-style updates, where
+// a field the client did not send must keep its stored value rather than be
+// overwritten with the struct's zero value. Rows are matched like Update: by
+// the builder's conditions, or by primary key when there are none. updated_at is
+// refreshed and written along with the named columns. Calling it with no
+// columns is a no-op.
+func (q *QueryBuilder[T]) UpdateColumns(record *T, columns ...string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, col := range dbColumns(record) {
+		known[col] = true
+	}
+	pk := make(map[string]bool)
+	for _, col := range primaryKeyColumns(record) {
+		pk[col] = true
+	}
+	touchesUpdatedAt := false
+	for _, col := range columns {
+		if pk[col] {
+			return fmt.Errorf("pickle: UpdateColumns on %s: primary key %s cannot be updated", q.table, col)
+		}
+		touchesUpdatedAt = touchesUpdatedAt || col == "updated_at"
+		if !known[col] {
+			return fmt.Errorf("pickle: UpdateColumns on %s: unknown column %q", q.table, col)
+		}
+	}
+	if err := q.preparePolicy("update_old"); err != nil {
+		return err
+	}
+	if err := evaluateRowPolicyRecord(q.table, "update_new", q.policyContext, record); err != nil {
+		return err
+	}
+	// An explicitly listed updated_at keeps the caller's value.
+	if !touchesUpdatedAt && stampTimestamps(record, false) {
+		columns = append(columns[:len(columns):len(columns)], "updated_at")
+	}
+	query, args := buildUpdateColumns(q.table, record, columns, q.conditions, q.policyClause, q.policyArgs)
+	db := q.db()
+	defer q.releaseConn()
+	_, err := db.Exec(query, args...)
+	return mapUniqueError(q.table, err)
 }
 
 // Delete removes matching records.
@@ -506,32 +1264,26 @@ func (q *QueryBuilder[T]) Delete(record *T) error {
 }
 
 func (q *QueryBuilder[T]) buildSelect() (string, []any) {
-	var cols []string
-	if len(q.selectedCols) > 0 {
-		cols = q.selectedCols
-	} else {
-		var zero T
-		cols = dbColumns(&zero)
-	}
+	return q.buildSelectOrderedBy(q.orderBy)
+}
 
+// buildSelectOrderedBy builds the SELECT with orderBy in place of q.orderBy,
+// leaving the builder unchanged.
+func (q *QueryBuilder[T]) buildSelectOrderedBy(orderBy []string) (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
 	b.WriteString("SELECT ")
-	b.WriteString(strings.Join(cols, ", "))
+	if q.distinct {
+		b.WriteString("DISTINCT ")
+	}
+	b.WriteString(quoteList(d, q.columns()))
+	q.appendCounts(d, &b)
 	b.WriteString(" FROM ")
-	b.WriteString(q.table)
+	b.WriteString(quoteQualified(d, q.table))
 
-	args := q.appendWhere(&b)
-
-	if len(q.orderBy) > 0 {
-		b.WriteString(" ORDER BY ")
-		b.WriteString(strings.Join(q.orderBy, ", "))
-	}
-	if q.limit > 0 {
-		b.WriteString(fmt.Sprintf(" LIMIT %d", q.limit))
-	}
-	if q.offset > 0 {
-		b.WriteString(fmt.Sprintf(" OFFSET %d", q.offset))
-	}
+	args := q.appendWhere(d, &b)
+	args = q.appendGroupBy(d, &b, args)
+	q.appendOrderLimit(d, &b, orderBy)
 
 	if q.lockMode != "" {
 		b.WriteString(" ")
@@ -545,25 +1297,59 @@ func (q *QueryBuilder[T]) buildSelect() (string, []any) {
 	return b.String(), args
 }
 
+// columns returns the explicit select list, or every db column of T.
+func (q *QueryBuilder[T]) columns() []string {
+	if len(q.selectedCols) > 0 {
+		return q.selectedCols
+	}
+	var zero T
+	return dbColumns(&zero)
+}
+
 func (q *QueryBuilder[T]) buildCount() (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
+	if q.distinct {
+		// Duplicate rows only collapse over the selected columns, so count
+		// the rows of the DISTINCT select rather than the table.
+		b.WriteString("SELECT COUNT(*) FROM (SELECT DISTINCT ")
+		b.WriteString(quoteList(d, q.columns()))
+		b.WriteString(" FROM ")
+		b.WriteString(quoteQualified(d, q.table))
+		args := q.appendWhere(d, &b)
+		b.WriteString(") AS distinct_rows")
+		return b.String(), args
+	}
 	b.WriteString("SELECT COUNT(*) FROM ")
-	b.WriteString(q.table)
+	b.WriteString(quoteQualified(d, q.table))
 
-	args := q.appendWhere(&b)
+	args := q.appendWhere(d, &b)
+	return b.String(), args
+}
+
+func (q *QueryBuilder[T]) buildCountDistinct(column string) (string, []any) {
+	d := currentDialect()
+	var b strings.Builder
+	b.WriteString("SELECT COUNT(DISTINCT ")
+	b.WriteString(quoteQualified(d, column))
+	b.WriteString(") FROM ")
+	b.WriteString(quoteQualified(d, q.table))
+
+	args := q.appendWhere(d, &b)
 	return b.String(), args
 }
 
 func (q *QueryBuilder[T]) buildDelete() (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
 	b.WriteString("DELETE FROM ")
-	b.WriteString(q.table)
+	b.WriteString(quoteQualified(d, q.table))
 
-	args := q.appendWhere(&b)
+	args := q.appendWhere(d, &b)
 	return b.String(), args
 }
 
-func (q *QueryBuilder[T]) appendWhere(b *strings.Builder) []any {
+func (q *QueryBuilder[T]) appendWhere(d Dialect, b *strings.Builder) []any {
 	if len(q.conditions) == 0 && q.policyClause == "" {
 		return nil
 	}
@@ -571,29 +1357,49 @@ func (q *QueryBuilder[T]) appendWhere(b *strings.Builder) []any {
 	var args []any
 	b.WriteString(" WHERE ")
 	if q.policyClause != "" {
-		b.WriteString(bindRuntimeClause(q.policyClause, 1))
+		b.WriteString(bindClause(d, q.policyClause, 1))
 		args = append(args, q.policyArgs...)
 	}
 	for i, c := range q.conditions {
 		if i > 0 || q.policyClause != "" {
 			b.WriteString(" AND ")
 		}
-		appendCondition(b, &args, c)
+		appendCondition(d, b, &args, c)
 	}
 	return args
 }
 
-func appendCondition(b *strings.Builder, args *[]any, c condition) {
+func appendCondition(d Dialect, b *strings.Builder, args *[]any, c condition) {
+	if c.op == "EXISTS" || c.op == "NOT EXISTS" {
+		b.WriteString(c.op + " (" + bindFragment(d, c.column, c.value.([]any), args) + ")")
+		return
+	}
+	if c.op == "RAW" {
+		b.WriteString("(" + bindFragment(d, c.column, c.value.([]any), args) + ")")
+		return
+	}
+	column := quoteQualified(d, c.column)
+	if c.op == "JSON PATH" {
+		jp := c.value.(jsonPathValue)
+		*args = append(*args, jp.value)
+		b.WriteString(fmt.Sprintf("%s #>> '{%s}' = %s", column, strings.Join(jp.path, ","), d.Placeholder(len(*args))))
+		return
+	}
+	if c.op == "@>" {
+		*args = append(*args, c.value)
+		b.WriteString(fmt.Sprintf("%s @> %s::jsonb", column, d.Placeholder(len(*args))))
+		return
+	}
 	if c.op != "IN" && c.op != "NOT IN" {
-		b.WriteString(fmt.Sprintf("%s %s $%d", c.column, c.op, len(*args)+1))
 		*args = append(*args, c.value)
+		b.WriteString(fmt.Sprintf("%s %s %s", column, c.op, d.Placeholder(len(*args))))
 		return
 	}
 
 	values := reflect.ValueOf(c.value)
 	if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
-		b.WriteString(fmt.Sprintf("%s %s ($%d)", c.column, c.op, len(*args)+1))
 		*args = append(*args, c.value)
+		b.WriteString(fmt.Sprintf("%s %s (%s)", column, c.op, d.Placeholder(len(*args))))
 		return
 	}
 	if values.Len() == 0 {
@@ -605,17 +1411,63 @@ func appendCondition(b *strings.Builder, args *[]any, c condition) {
 		return
 	}
 
-	b.WriteString(c.column + " " + c.op + " (")
+	b.WriteString(column + " " + c.op + " (")
 	for i := 0; i < values.Len(); i++ {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		b.WriteString(fmt.Sprintf("$%d", len(*args)+1))
 		*args = append(*args, values.Index(i).Interface())
+		b.WriteString(d.Placeholder(len(*args)))
 	}
 	b.WriteString(")")
 }
 
+// SelfTimestamping is implemented by models that maintain created_at and
+// updated_at themselves. Create and Update leave those fields untouched for
+// such models instead of stamping them with the current time.
+type SelfTimestamping interface {
+	SelfTimestamping()
+}
+
+// stampTimestamps sets the record's updated_at field, and on create a zero
+// created_at field, to the current UTC time. Fields are found by db tag and
+// must be time.Time or *time.Time. It reports whether updated_at was set.
+func stampTimestamps(record any, create bool) bool {
+	if _, ok := record.(SelfTimestamping); ok {
+		return false
+	}
+	rv := reflect.ValueOf(record)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	rt := rv.Type()
+	now := time.Now().UTC()
+	stamped := false
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("db")
+		if tag != "updated_at" && !(create && tag == "created_at") {
+			continue
+		}
+		field := rv.Field(i)
+		if tag == "created_at" && !field.IsZero() {
+			continue
+		}
+		switch field.Interface().(type) {
+		case time.Time:
+			field.Set(reflect.ValueOf(now))
+		case *time.Time:
+			t := now
+			field.Set(reflect.ValueOf(&t))
+		default:
+			continue
+		}
+		if tag == "updated_at" {
+			stamped = true
+		}
+	}
+	return stamped
+}
+
 // dbColumns returns the db-tagged column names from a struct in field order.
 func dbColumns(v any) []string {
 	rv := reflect.ValueOf(v)
@@ -650,7 +1502,9 @@ func dbValues(v any) []any {
 	return vals
 }
 
-// dbScanDest returns a slice of field pointers from a struct in db tag field order.
+// dbScanDest returns a slice of field pointers from a struct in db tag field
+// order. It is only safe for statements whose column list was built from the
+// same struct, like Create's RETURNING clause; reads use scanRows.
 func dbScanDest(v any) []any {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Ptr {
@@ -667,18 +1521,87 @@ func dbScanDest(v any) []any {
 	return ptrs
 }
 
-// scanRow scans a single row into a struct using db tag field order.
-// buildSelect emits explicit column names in the same order, so positions align.
-func scanRow[T any](row *sql.Row, dest *T) error {
-	return row.Scan(dbScanDest(dest)...)
+// scanFields caches, per struct type, the field index each column name
+// scans into: the db tag, or for a WithCount alias the count tag.
+var scanFields sync.Map // reflect.Type → map[string]int
+
+func scanFieldIndex(rt reflect.Type) map[string]int {
+	if cached, ok := scanFields.Load(rt); ok {
+		return cached.(map[string]int)
+	}
+	fields := make(map[string]int, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("db")
+		if tag != "" && tag != "-" {
+			fields[tag] = i
+		}
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if relation := rt.Field(i).Tag.Get("count"); relation != "" {
+			if _, taken := fields[countColumn(relation)]; !taken {
+				fields[countColumn(relation)] = i
+			}
+		}
+	}
+	scanFields.Store(rt, fields)
+	return fields
+}
+
+// scanPlan returns, for each of cols, the index of the field of rt it scans
+// into, or -1 for a column no field matches.
+func scanPlan(rt reflect.Type, cols []string) []int {
+	fields := scanFieldIndex(rt)
+	plan := make([]int, len(cols))
+	for i, col := range cols {
+		if idx, ok := fields[col]; ok {
+			plan[i] = idx
+		} else {
+			plan[i] = -1
+		}
+	}
+	return plan
+}
+
+// scanDestPlan returns the scan destinations for one row following plan.
+// Unmatched columns scan into a throwaway value.
+func scanDestPlan(rv reflect.Value, plan []int) []any {
+	ptrs := make([]any, len(plan))
+	for i, idx := range plan {
+		if idx >= 0 {
+			ptrs[i] = rv.Field(idx).Addr().Interface()
+		} else {
+			ptrs[i] = new(any)
+		}
+	}
+	return ptrs
+}
+
+// dbScanDestFor returns field pointers for cols in order, matched by db tag,
+// or for a WithCount alias by count tag. Columns without a matching field
+// scan into a throwaway value.
+func dbScanDestFor(v any, cols []string) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	return scanDestPlan(rv, scanPlan(rv.Type(), cols))
 }
 
-// scanRows scans multiple rows into structs using db tag field order.
+// scanRows scans every row into a T, matching the columns the query
+// returned to fields by name rather than position, so a column subset or an
+// order that differs from the struct still lands in the right fields. The
+// column-to-field plan is worked out once per result set.
 func scanRows[T any](rows *sql.Rows) ([]T, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var zero T
+	plan := scanPlan(reflect.TypeOf(zero), cols)
 	var results []T
 	for rows.Next() {
 		var item T
-		if err := rows.Scan(dbScanDest(&item)...); err != nil {
+		if err := rows.Scan(scanDestPlan(reflect.ValueOf(&item).Elem(), plan)...); err != nil {
 			return nil, err
 		}
 		results = append(results, item)
@@ -686,15 +1609,44 @@ func scanRows[T any](rows *sql.Rows) ([]T, error) {
 	return results, rows.Err()
 }
 
+// scanFirst scans the first row into a T as scanRows does, or returns
+// sql.ErrNoRows when there is none.
+func scanFirst[T any](rows *sql.Rows) (*T, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+	var result T
+	if err := rows.Scan(dbScanDestFor(&result, cols)...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // buildInsert builds a parameterized INSERT statement from a struct's db tags.
-// Zero-value "id", "created_at", and "updated_at" fields are omitted so that
-// database defaults (gen_random_uuid(), NOW(), etc.) fire.
+// Zero-value primary key, "created_at", and "updated_at" fields are omitted so
+// that database defaults (gen_random_uuid(), NOW(), etc.) fire.
 func buildInsert[T any](table string, record *T) (string, []any) {
+	cols, vals := insertColumns(record)
+	return buildInsertRows(table, cols, [][]any{vals})
+}
+
+// insertColumns returns the columns and values Create writes for record.
+func insertColumns[T any](record *T) ([]string, []any) {
 	rv := reflect.ValueOf(record).Elem()
 	rt := rv.Type()
 
 	// Fields where a zero value means "let the DB default handle it"
-	dbDefaultFields := map[string]bool{"id": true, "created_at": true, "updated_at": true}
+	dbDefaultFields := map[string]bool{"created_at": true, "updated_at": true}
+	for _, col := range primaryKeyColumns(record) {
+		dbDefaultFields[col] = true
+	}
 
 	var cols []string
 	var vals []any
@@ -710,28 +1662,62 @@ func buildInsert[T any](table string, record *T) (string, []any) {
 		cols = append(cols, tag)
 		vals = append(vals, field.Interface())
 	}
+	return cols, vals
+}
 
-	placeholders := make([]string, len(cols))
-	for i := range cols {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+// buildInsertRows builds one INSERT with a VALUES tuple per row, numbering
+// placeholders across all of them.
+func buildInsertRows(table string, cols []string, rows [][]any) (string, []any) {
+	d := currentDialect()
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("INSERT INTO %s (%s) VALUES ", quoteQualified(d, table), quoteList(d, cols)))
+	args := make([]any, 0, len(cols)*len(rows))
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for j, v := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			args = append(args, v)
+			b.WriteString(d.Placeholder(len(args)))
+		}
+		b.WriteString(")")
 	}
-	return fmt.Sprintf(
-		"INSERT INTO %s (%s) VALUES (%s)",
-		table,
-		strings.Join(cols, ", "),
-		strings.Join(placeholders, ", "),
-	), vals
+	return b.String(), args
 }
 
 // buildUpdate builds a parameterized UPDATE statement from a struct's db tags.
-// The "id" column is excluded from SET and used in WHERE if no conditions are set.
+// The primary key columns (see primaryKeyColumns) are excluded from SET and
+// used in WHERE if no conditions are set.
 func buildUpdate[T any](table string, record *T, conditions []condition, policyClause string, policyArgs []any) (string, []any) {
+	return buildUpdateColumns(table, record, nil, conditions, policyClause, policyArgs)
+}
+
+// buildUpdateColumns is buildUpdate restricted to the columns in only, in
+// struct order. A nil only sets every column.
+func buildUpdateColumns[T any](table string, record *T, only []string, conditions []condition, policyClause string, policyArgs []any) (string, []any) {
 	rv := reflect.ValueOf(record).Elem()
 	rt := rv.Type()
 
+	var wanted map[string]bool
+	if only != nil {
+		wanted = make(map[string]bool, len(only))
+		for _, col := range only {
+			wanted[col] = true
+		}
+	}
+
+	pkCols := primaryKeyColumns(record)
+	pk := make(map[string]bool, len(pkCols))
+	for _, col := range pkCols {
+		pk[col] = true
+	}
 	var setCols []string
 	var setVals []any
-	var idVal any
+	pkVals := make(map[string]any, len(pkCols))
 
 	for i := 0; i < rt.NumField(); i++ {
 		tag := rt.Field(i).Tag.Get("db")
@@ -739,19 +1725,23 @@ func buildUpdate[T any](table string, record *T, conditions []condition, policyC
 			continue
 		}
 		val := rv.Field(i).Interface()
-		if tag == "id" {
-			idVal = val
+		if pk[tag] {
+			pkVals[tag] = val
+			continue
+		}
+		if wanted != nil && !wanted[tag] {
 			continue
 		}
 		setCols = append(setCols, tag)
 		setVals = append(setVals, val)
 	}
 
+	d := currentDialect()
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("UPDATE %s SET ", table))
+	b.WriteString(fmt.Sprintf("UPDATE %s SET ", quoteQualified(d, table)))
 	setParts := make([]string, len(setCols))
 	for i, col := range setCols {
-		setParts[i] = fmt.Sprintf("%s = $%d", col, i+1)
+		setParts[i] = fmt.Sprintf("%s = %s", d.Quote(col), d.Placeholder(i+1))
 	}
 	b.WriteString(strings.Join(setParts, ", "))
 
@@ -760,18 +1750,25 @@ func buildUpdate[T any](table string, record *T, conditions []condition, policyC
 	if len(conditions) > 0 || policyClause != "" {
 		b.WriteString(" WHERE ")
 		if policyClause != "" {
-			b.WriteString(bindRuntimeClause(policyClause, len(args)+1))
+			b.WriteString(bindClause(d, policyClause, len(args)+1))
 			args = append(args, policyArgs...)
 		}
 		for i, c := range conditions {
 			if i > 0 || policyClause != "" {
 				b.WriteString(" AND ")
 			}
-			appendCondition(&b, &args, c)
+			appendCondition(d, &b, &args, c)
+		}
+	} else if len(pkCols) > 0 {
+		for i, col := range pkCols {
+			if i == 0 {
+				b.WriteString(" WHERE ")
+			} else {
+				b.WriteString(" AND ")
+			}
+			args = append(args, pkVals[col])
+			b.WriteString(fmt.Sprintf("%s = %s", d.Quote(col), d.Placeholder(len(args))))
 		}
-	} else if idVal != nil {
-		b.WriteString(fmt.Sprintf(" WHERE id = $%d", len(args)+1))
-		args = append(args, idVal)
 	}
 
 	return b.String(), args
@@ -39,10 +39,22 @@ func (q *AppendOnlyQueryBuilder[T]) whereNotIn(column string, values any) *Appen
 	q.base().whereNotIn(column, values)
 	return q
 }
+func (q *AppendOnlyQueryBuilder[T]) WhereJSON(column, path string, value any) *AppendOnlyQueryBuilder[T] {
+	q.base().WhereJSON(column, path, value)
+	return q
+}
+func (q *AppendOnlyQueryBuilder[T]) WhereJSONContains(column string, value any) *AppendOnlyQueryBuilder[T] {
+	q.base().WhereJSONContains(column, value)
+	return q
+}
 func (q *AppendOnlyQueryBuilder[T]) OrderBy(column, direction string) *AppendOnlyQueryBuilder[T] {
 	q.base().OrderBy(column, direction)
 	return q
 }
+func (q *AppendOnlyQueryBuilder[T]) OrderByAllowed(column, direction string, allowed ...string) *AppendOnlyQueryBuilder[T] {
+	q.base().OrderByAllowed(column, direction, allowed...)
+	return q
+}
 func (q *AppendOnlyQueryBuilder[T]) Limit(n int) *AppendOnlyQueryBuilder[T] {
 	q.base().Limit(n)
 	return q
@@ -56,6 +68,10 @@ func (q *AppendOnlyQueryBuilder[T]) addSelect(column string)              { q.ba
 func (q *AppendOnlyQueryBuilder[T]) setVisibility(visibility visibilityMode) {
 	q.base().setVisibility(visibility)
 }
+func (q *AppendOnlyQueryBuilder[T]) Distinct() *AppendOnlyQueryBuilder[T] {
+	q.base().Distinct()
+	return q
+}
 func (q *AppendOnlyQueryBuilder[T]) db() dbExecutor   { return q.base().db() }
 func (q *AppendOnlyQueryBuilder[T]) setTx(tx *sql.Tx) { q.base().setTx(tx) }
 func (q *AppendOnlyQueryBuilder[T]) UseTransaction(tx *sql.Tx) *AppendOnlyQueryBuilder[T] {
@@ -90,10 +106,22 @@ func (q *AppendOnlyQueryBuilder[T]) EagerLoad(relation string) *AppendOnlyQueryB
 func (q *AppendOnlyQueryBuilder[T]) First() (*T, error)    { return q.base().First() }
 func (q *AppendOnlyQueryBuilder[T]) All() ([]T, error)     { return q.base().All() }
 func (q *AppendOnlyQueryBuilder[T]) Count() (int64, error) { return q.base().Count() }
+func (q *AppendOnlyQueryBuilder[T]) CountDistinct(column string) (int64, error) {
+	return q.base().CountDistinct(column)
+}
+func (q *AppendOnlyQueryBuilder[T]) CountEstimate() (int64, error) {
+	return q.base().CountEstimate()
+}
 func (q *AppendOnlyQueryBuilder[T]) aggregate(fn, column string) (*float64, error) {
 	return q.base().aggregate(fn, column)
 }
+func (q *AppendOnlyQueryBuilder[T]) aggregateInto(dest any, fn, column string) error {
+	return q.base().aggregateInto(dest, fn, column)
+}
 func (q *AppendOnlyQueryBuilder[T]) Create(record *T) error { return q.base().Create(record) }
+func (q *AppendOnlyQueryBuilder[T]) CreateMany(records []*T) error {
+	return q.base().CreateMany(records)
+}
 
 func NewAppendOnlyScopeBuilder[T any](q *AppendOnlyQueryBuilder[T]) *ScopeBuilder[T] {
 	return NewScopeBuilder(q.base())
@@ -3,6 +3,7 @@ package cooked
 import (
 	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -130,6 +131,7 @@ func ApplyImmutableScopeBuilder[T any](q *ImmutableQueryBuilder[T], sb *ScopeBui
 	return q
 }
 
+// EagerLoad loads related records into the results. See QueryBuilder.EagerLoad.
 func (q *ImmutableQueryBuilder[T]) EagerLoad(relation string) *ImmutableQueryBuilder[T] {
 	q.eagerLoads = append(q.eagerLoads, relation)
 	return q
@@ -234,12 +236,20 @@ func (q *ImmutableQueryBuilder[T]) First() (*T, error) {
 	}
 
 	query, args := q.buildSelect(1)
-	row := q.db().QueryRow(query, args...)
-	var result T
-	if err := scanRow(row, &result); err != nil {
+	rows, err := q.db().Query(query, args...)
+	if err != nil {
+		return nil, mapLockError(q.table, err)
+	}
+	defer rows.Close()
+	result, err := scanFirst[T](rows)
+	if err != nil {
 		return nil, mapLockError(q.table, err)
 	}
-	return &result, nil
+	rows.Close()
+	if err := loadEager(q.db(), q.table, q.eagerLoads, q.policyContext, []reflect.Value{reflect.ValueOf(result).Elem()}); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // All returns the latest version of all matching records.
@@ -260,7 +270,15 @@ func (q *ImmutableQueryBuilder[T]) All() ([]T, error) {
 		return nil, mapLockError(q.table, err)
 	}
 	defer rows.Close()
-	return scanRows[T](rows)
+	results, err := scanRows[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := loadEager(q.db(), q.table, q.eagerLoads, q.policyContext, eagerRecords(results)); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
 // Count returns the number of distinct records matching conditions.
@@ -276,13 +294,19 @@ func (q *ImmutableQueryBuilder[T]) Count() (int64, error) {
 
 // aggregate runs a SQL aggregate function on the latest version of each record.
 func (q *ImmutableQueryBuilder[T]) aggregate(fn, column string) (*float64, error) {
+	var result *float64
+	err := q.aggregateInto(&result, fn, column)
+	return result, err
+}
+
+// aggregateInto runs a SQL aggregate function on the latest version of each
+// record and scans the result into dest.
+func (q *ImmutableQueryBuilder[T]) aggregateInto(dest any, fn, column string) error {
 	if err := q.preparePolicy("select"); err != nil {
-		return nil, err
+		return err
 	}
 	query, args := q.buildAggregate(fn, column)
-	var result *float64
-	err := q.db().QueryRow(query, args...).Scan(&result)
-	return result, err
+	return q.db().QueryRow(query, args...).Scan(dest)
 }
 
 // Create inserts a new record.
@@ -300,7 +324,7 @@ func (q *ImmutableQueryBuilder[T]) createWithPolicyOperation(record *T, operatio
 	cols := dbColumns(record)
 	query += " RETURNING " + strings.Join(cols, ", ")
 	row := q.db().QueryRow(query, args...)
-	return row.Scan(dbScanDest(record)...)
+	return mapUniqueError(q.table, row.Scan(dbScanDest(record)...))
 }
 
 // checkExistingPolicy evaluates an immutable logical operation against the
@@ -314,7 +338,8 @@ func (q *ImmutableQueryBuilder[T]) checkExistingPolicy(operation string, id any)
 	if clause == "" {
 		return nil
 	}
-	query := "SELECT 1 FROM " + q.table + " t WHERE t.id = $1 AND t.version_id = (SELECT version_id FROM " + q.table + " WHERE id = t.id ORDER BY version_id DESC LIMIT 1) AND " + bindRuntimeClause(clause, 2)
+	d := currentDialect()
+	query := "SELECT 1" + q.latestVersionFrom(d) + " AND t.id = " + d.Placeholder(1) + " AND " + bindClause(d, clause, 2)
 	if q.tx != nil {
 		query += " FOR UPDATE"
 	}
@@ -340,72 +365,41 @@ func (q *ImmutableQueryBuilder[T]) cols() []string {
 }
 
 func (q *ImmutableQueryBuilder[T]) buildSelect(limit int) (string, []any) {
+	d := currentDialect()
 	cols := q.cols()
 	prefixed := make([]string, len(cols))
 	for i, c := range cols {
-		prefixed[i] = "t." + c
+		prefixed[i] = "t." + d.Quote(c)
 	}
 
 	var b strings.Builder
-	args := make([]any, 0, len(q.conditions))
-	argIdx := 1
+	var args []any
+	b.WriteString("SELECT ")
+	b.WriteString(strings.Join(prefixed, ", "))
 
 	if q.allVersions {
 		// Bypass dedup — return all version rows
-		b.WriteString("SELECT ")
-		b.WriteString(strings.Join(prefixed, ", "))
 		b.WriteString(" FROM ")
-		b.WriteString(q.table)
+		b.WriteString(quoteQualified(d, q.table))
 		b.WriteString(" t")
-		if len(q.conditions) > 0 || q.policyClause != "" {
+		if terms := q.whereTerms(d, &args); len(terms) > 0 {
 			b.WriteString(" WHERE ")
-			if q.policyClause != "" {
-				b.WriteString(bindRuntimeClause(q.policyClause, argIdx))
-				args = append(args, q.policyArgs...)
-				argIdx += len(q.policyArgs)
-			}
-			for i, c := range q.conditions {
-				if i > 0 || q.policyClause != "" {
-					b.WriteString(" AND ")
-				}
-				b.WriteString(fmt.Sprintf("t.%s %s $%d", c.column, c.op, argIdx))
-				args = append(args, c.value)
-				argIdx++
-			}
+			b.WriteString(strings.Join(terms, " AND "))
 		}
 		b.WriteString(" ORDER BY t.id, t.version_id ASC")
 	} else {
 		// Dedup to latest version per id
-		b.WriteString("SELECT ")
-		b.WriteString(strings.Join(prefixed, ", "))
-		b.WriteString(" FROM ")
-		b.WriteString(q.table)
-		b.WriteString(" t WHERE t.version_id = (SELECT version_id FROM ")
-		b.WriteString(q.table)
-		b.WriteString(" WHERE id = t.id")
-		b.WriteString(" ORDER BY version_id DESC LIMIT 1)")
-
-		var extra []string
-		if q.policyClause != "" {
-			extra = append(extra, bindRuntimeClause(q.policyClause, argIdx))
-			args = append(args, q.policyArgs...)
-			argIdx += len(q.policyArgs)
-		}
-		for _, c := range q.conditions {
-			extra = append(extra, fmt.Sprintf("t.%s %s $%d", c.column, c.op, argIdx))
-			args = append(args, c.value)
-			argIdx++
-		}
-		if q.softDeletes {
-			extra = append(extra, "t.deleted_at IS NULL")
-		}
-		if len(extra) > 0 {
-			b.WriteString(" AND ")
-			b.WriteString(strings.Join(extra, " AND "))
-		}
+		b.WriteString(q.latestVersionFrom(d))
+		q.appendLatestWhere(d, &b, &args)
 		if len(q.orderBy) > 0 {
 			b.WriteString(" ORDER BY ")
-			b.WriteString(strings.Join(q.orderBy, ", "))
+			for i, order := range q.orderBy {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				column, dir, _ := strings.Cut(order, " ")
+				b.WriteString(quoteQualified(d, column) + " " + dir)
+			}
 		} else {
 			b.WriteString(" ORDER BY t.id")
 		}
@@ -433,87 +427,61 @@ func (q *ImmutableQueryBuilder[T]) buildSelect(limit int) (string, []any) {
 }
 
 func (q *ImmutableQueryBuilder[T]) buildCount() (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
-	args := make([]any, 0, len(q.conditions))
-	argIdx := 1
-
-	b.WriteString("SELECT COUNT(*) FROM (SELECT t.id FROM ")
-	b.WriteString(q.table)
-	b.WriteString(" t WHERE t.version_id = (SELECT version_id FROM ")
-	b.WriteString(q.table)
-	b.WriteString(" WHERE id = t.id")
-	b.WriteString(" ORDER BY version_id DESC LIMIT 1)")
-
-	var extra []string
-	if q.policyClause != "" {
-		extra = append(extra, bindRuntimeClause(q.policyClause, argIdx))
-		args = append(args, q.policyArgs...)
-		argIdx += len(q.policyArgs)
-	}
-	for _, c := range q.conditions {
-		extra = append(extra, fmt.Sprintf("t.%s %s $%d", c.column, c.op, argIdx))
-		args = append(args, c.value)
-		argIdx++
-	}
-	if q.softDeletes {
-		extra = append(extra, "t.deleted_at IS NULL")
-	}
-	if len(extra) > 0 {
-		b.WriteString(" AND ")
-		b.WriteString(strings.Join(extra, " AND "))
-	}
+	var args []any
+	b.WriteString("SELECT COUNT(*) FROM (SELECT t.id")
+	b.WriteString(q.latestVersionFrom(d))
+	q.appendLatestWhere(d, &b, &args)
 	b.WriteString(") AS _dedup")
-
 	return b.String(), args
 }
 
 func (q *ImmutableQueryBuilder[T]) buildAggregate(fn, column string) (string, []any) {
+	d := currentDialect()
 	var b strings.Builder
-	args := make([]any, 0, len(q.conditions))
-	argIdx := 1
+	var args []any
+	b.WriteString(fmt.Sprintf("SELECT %s(_dedup.%s) FROM (SELECT t.%s", fn, d.Quote(column), d.Quote(column)))
+	b.WriteString(q.latestVersionFrom(d))
+	q.appendLatestWhere(d, &b, &args)
+	b.WriteString(") AS _dedup")
+	return b.String(), args
+}
 
-	b.WriteString(fmt.Sprintf("SELECT %s(_dedup.%s) FROM (SELECT t.%s FROM ", fn, column, column))
-	b.WriteString(q.table)
-	b.WriteString(" t WHERE t.version_id = (SELECT version_id FROM ")
-	b.WriteString(q.table)
-	b.WriteString(" WHERE id = t.id")
-	b.WriteString(" ORDER BY version_id DESC LIMIT 1)")
+// latestVersionFrom writes the FROM clause that keeps only the globally
+// latest version of each id, aliasing the table as t.
+func (q *ImmutableQueryBuilder[T]) latestVersionFrom(d Dialect) string {
+	table := quoteQualified(d, q.table)
+	return " FROM " + table + " t WHERE t.version_id = (SELECT version_id FROM " + table +
+		" WHERE id = t.id ORDER BY version_id DESC LIMIT 1)"
+}
 
-	var extra []string
-	if q.policyClause != "" {
-		extra = append(extra, bindRuntimeClause(q.policyClause, argIdx))
-		args = append(args, q.policyArgs...)
-		argIdx += len(q.policyArgs)
-	}
-	for _, c := range q.conditions {
-		extra = append(extra, fmt.Sprintf("t.%s %s $%d", c.column, c.op, argIdx))
-		args = append(args, c.value)
-		argIdx++
-	}
+// appendLatestWhere ANDs the row policy, the conditions and the soft-delete
+// filter onto the latest-version WHERE clause. Soft deletes are filtered
+// after the reduction, so a deleted latest version hides the whole id.
+func (q *ImmutableQueryBuilder[T]) appendLatestWhere(d Dialect, b *strings.Builder, args *[]any) {
+	terms := q.whereTerms(d, args)
 	if q.softDeletes {
-		extra = append(extra, "t.deleted_at IS NULL")
+		terms = append(terms, "t.deleted_at IS NULL")
 	}
-	if len(extra) > 0 {
+	for _, term := range terms {
 		b.WriteString(" AND ")
-		b.WriteString(strings.Join(extra, " AND "))
+		b.WriteString(term)
 	}
-	b.WriteString(") AS _dedup")
-
-	return b.String(), args
 }
 
-func (q *ImmutableQueryBuilder[T]) appendWhere(b *strings.Builder) []any {
-	if len(q.conditions) == 0 {
-		return nil
+// whereTerms renders the row policy and the conditions, binding their
+// arguments after those already in args.
+func (q *ImmutableQueryBuilder[T]) whereTerms(d Dialect, args *[]any) []string {
+	var terms []string
+	if q.policyClause != "" {
+		terms = append(terms, bindClause(d, q.policyClause, len(*args)+1))
+		*args = append(*args, q.policyArgs...)
 	}
-	var args []any
-	b.WriteString(" WHERE ")
-	for i, c := range q.conditions {
-		if i > 0 {
-			b.WriteString(" AND ")
-		}
-		b.WriteString(fmt.Sprintf("%s %s $%d", c.column, c.op, i+1))
-		args = append(args, c.value)
+	for _, c := range q.conditions {
+		var term strings.Builder
+		appendCondition(d, &term, args, c)
+		terms = append(terms, term.String())
 	}
-	return args
+	return terms
 }
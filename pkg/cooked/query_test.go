@@ -1,11 +1,19 @@
 package cooked
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shopspring/decimal"
 )
 
 // --- dbColumns / dbValues / dbScanDest ---
@@ -100,10 +108,10 @@ func TestBuildInsert(t *testing.T) {
 	// id is zero → omitted so DB default fires
 	r := &Rec{Name: "Bob", Email: "bob@example.com"}
 	q, args := buildInsert("users", r)
-	if !strings.Contains(q, "INSERT INTO users") {
-		t.Errorf("buildInsert query = %q, want INSERT INTO users", q)
+	if !strings.Contains(q, `INSERT INTO "users"`) {
+		t.Errorf("buildInsert query = %q, want INSERT INTO \"users\"", q)
 	}
-	if !strings.Contains(q, "name") || !strings.Contains(q, "email") {
+	if !strings.Contains(q, `"name"`) || !strings.Contains(q, `"email"`) {
 		t.Errorf("buildInsert query = %q, should contain name and email", q)
 	}
 	if strings.Contains(q, "\"id\"") {
@@ -121,7 +129,7 @@ func TestBuildInsertNonZeroID(t *testing.T) {
 	}
 	r := &Rec{ID: "explicit-id", Name: "Alice"}
 	q, args := buildInsert("users", r)
-	if !strings.Contains(q, "id") {
+	if !strings.Contains(q, `"id"`) {
 		t.Errorf("buildInsert should include non-zero id: %q", q)
 	}
 	if len(args) != 2 {
@@ -139,11 +147,11 @@ func TestBuildUpdateByID(t *testing.T) {
 	}
 	r := &Rec{ID: "42", Name: "New Name", Email: "new@example.com"}
 	q, args := buildUpdate("users", r, nil, "", nil)
-	if !strings.Contains(q, "UPDATE users SET") {
-		t.Errorf("buildUpdate query = %q, want UPDATE users SET", q)
+	if !strings.Contains(q, `UPDATE "users" SET`) {
+		t.Errorf("buildUpdate query = %q, want UPDATE \"users\" SET", q)
 	}
-	if !strings.Contains(q, "WHERE id = $3") {
-		t.Errorf("buildUpdate query = %q, want WHERE id = $3", q)
+	if !strings.Contains(q, `WHERE "id" = $3`) {
+		t.Errorf("buildUpdate query = %q, want WHERE \"id\" = $3", q)
 	}
 	// args: name, email, id
 	if len(args) != 3 {
@@ -159,20 +167,237 @@ func TestBuildUpdateWithConditions(t *testing.T) {
 	r := &Rec{ID: "1", Name: "Alice"}
 	conds := []condition{{column: "status", op: "=", value: "active"}}
 	q, args := buildUpdate("users", r, conds, "", nil)
-	if !strings.Contains(q, "WHERE status = $2") {
-		t.Errorf("buildUpdate with conditions = %q, want WHERE status = $2", q)
+	if !strings.Contains(q, `WHERE "status" = $2`) {
+		t.Errorf("buildUpdate with conditions = %q, want WHERE \"status\" = $2", q)
 	}
 	if len(args) != 2 {
 		t.Errorf("buildUpdate args = %v, want 2", args)
 	}
 }
 
+func TestBuildUpdateColumnsSetsOnlyNamedColumns(t *testing.T) {
+	r := &testModel{ID: "42", Name: "New Name"}
+	q, args := buildUpdateColumns("users", r, []string{"name"}, nil, "", nil)
+	if q != `UPDATE "users" SET "name" = $1 WHERE "id" = $2` {
+		t.Errorf("buildUpdateColumns = %q", q)
+	}
+	if len(args) != 2 || args[0] != "New Name" || args[1] != "42" {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+type customPKModel struct {
+	UUID  string `db:"uuid" pickle:"pk"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+func TestBuildUpdateByCustomPrimaryKey(t *testing.T) {
+	r := &customPKModel{UUID: "u-1", Name: "Ada", Email: "ada@example.com"}
+	q, args := buildUpdate("members", r, nil, "", nil)
+	if q != `UPDATE "members" SET "name" = $1, "email" = $2 WHERE "uuid" = $3` {
+		t.Errorf("buildUpdate = %q", q)
+	}
+	if len(args) != 3 || args[2] != "u-1" {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestBuildUpdateByCompositePrimaryKey(t *testing.T) {
+	type Rec struct {
+		TeamID string `db:"team_id" pickle:"pk"`
+		UserID string `db:"user_id" pickle:"pk"`
+		Role   string `db:"role"`
+	}
+	q, args := buildUpdate("team_members", &Rec{TeamID: "t", UserID: "u", Role: "admin"}, nil, "", nil)
+	if q != `UPDATE "team_members" SET "role" = $1 WHERE "team_id" = $2 AND "user_id" = $3` {
+		t.Errorf("buildUpdate = %q", q)
+	}
+	if len(args) != 3 || args[1] != "t" || args[2] != "u" {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestBuildInsertOmitsZeroCustomPrimaryKey(t *testing.T) {
+	q, _ := buildInsert("members", &customPKModel{Name: "Ada"})
+	if strings.Contains(q, `"uuid"`) {
+		t.Errorf("buildInsert should omit a zero-value primary key: %q", q)
+	}
+}
+
+func TestUpdateColumnsRejectsCustomPrimaryKey(t *testing.T) {
+	withCountTestDB(t, "pgsql")
+	if err := Query[customPKModel]("members").UpdateColumns(&customPKModel{UUID: "u-1"}, "uuid"); err == nil {
+		t.Error("the primary key should not be updatable")
+	}
+}
+
+func TestUpdateColumnsExecutesPartialUpdate(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "email" = $1 WHERE "id" = $2`)).
+		WithArgs("ada@example.com", "u-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := &testModel{ID: "u-1", Email: "ada@example.com"}
+	if err := Query[testModel]("users").UpdateColumns(r, "email"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateColumnsRejectsUnknownAndIDColumns(t *testing.T) {
+	withCountTestDB(t, "pgsql")
+	r := &testModel{ID: "u-1"}
+	if err := Query[testModel]("users").UpdateColumns(r, "name; DROP TABLE users"); err == nil {
+		t.Error("unknown column should be rejected")
+	}
+	if err := Query[testModel]("users").UpdateColumns(r, "id"); err == nil {
+		t.Error("id should not be updatable")
+	}
+	if err := Query[testModel]("users").UpdateColumns(r); err != nil {
+		t.Errorf("no columns should be a no-op, got %v", err)
+	}
+}
+
+type timestampedModel struct {
+	ID        string     `db:"id"`
+	Title     string     `db:"title"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type manualTimestampsModel struct {
+	ID        string    `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (*manualTimestampsModel) SelfTimestamping() {}
+
+func TestStampTimestampsOnCreateAndUpdate(t *testing.T) {
+	r := &timestampedModel{}
+	if !stampTimestamps(r, true) {
+		t.Fatal("create should report updated_at as stamped")
+	}
+	if r.CreatedAt.IsZero() || r.UpdatedAt == nil || !r.UpdatedAt.Equal(r.CreatedAt) {
+		t.Fatalf("create stamps = %v / %v, want equal non-zero times", r.CreatedAt, r.UpdatedAt)
+	}
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.CreatedAt = created
+	stampTimestamps(r, false)
+	if !r.CreatedAt.Equal(created) {
+		t.Errorf("update changed created_at to %v", r.CreatedAt)
+	}
+	if !r.UpdatedAt.After(created) {
+		t.Errorf("update did not refresh updated_at: %v", r.UpdatedAt)
+	}
+
+	r2 := &timestampedModel{CreatedAt: created}
+	stampTimestamps(r2, true)
+	if !r2.CreatedAt.Equal(created) {
+		t.Errorf("create overwrote an explicit created_at: %v", r2.CreatedAt)
+	}
+}
+
+func TestStampTimestampsSkipsOptOutAndUntimedModels(t *testing.T) {
+	m := &manualTimestampsModel{}
+	if stampTimestamps(m, true) || !m.CreatedAt.IsZero() || !m.UpdatedAt.IsZero() {
+		t.Errorf("SelfTimestamping model was stamped: %+v", m)
+	}
+	if stampTimestamps(&testModel{}, true) {
+		t.Error("model without timestamp fields reported a stamp")
+	}
+}
+
+func TestCreateInsertsTimestamps(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "posts" ("title", "created_at", "updated_at") VALUES ($1, $2, $3) RETURNING "id", "title", "created_at", "updated_at"`)).
+		WithArgs("Hello", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at", "updated_at"}).
+			AddRow("p-1", "Hello", time.Now(), time.Now()))
+
+	if err := Query[timestampedModel]("posts").Create(&timestampedModel{Title: "Hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateManyBatchesRowsWithTheSameColumns(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "posts" ("title", "created_at", "updated_at") VALUES ($1, $2, $3), ($4, $5, $6)`)).
+		WithArgs("A", sqlmock.AnyArg(), sqlmock.AnyArg(), "B", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "posts" ("id", "title", "created_at", "updated_at") VALUES ($1, $2, $3, $4)`)).
+		WithArgs("p-3", "C", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(errors.New("duplicate key"))
+
+	records := []*timestampedModel{{Title: "A"}, {Title: "B"}, {ID: "p-3", Title: "C"}}
+	err := Query[timestampedModel]("posts").CreateMany(records)
+	if err == nil || !strings.Contains(err.Error(), "inserted 2 of 3 records: duplicate key") {
+		t.Fatalf("CreateMany error = %v, want a partial-failure count", err)
+	}
+	if records[0].CreatedAt.IsZero() {
+		t.Error("CreateMany did not stamp created_at")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateManyChunksUnderParameterLimit(t *testing.T) {
+	mock := withCountTestDB(t, "sqlite")
+	perStatement := maxInsertParams() / 2 // name and email
+	records := make([]*testModel, perStatement+1)
+	for i := range records {
+		records[i] = &testModel{Name: "n", Email: "e"}
+	}
+	fullArgs := make([]driver.Value, 2*perStatement)
+	for i := range fullArgs {
+		fullArgs[i] = sqlmock.AnyArg()
+	}
+	mock.ExpectExec(`^INSERT INTO "users" \("name", "email"\) VALUES \(\?, \?\), .*\(\?, \?\)$`).
+		WithArgs(fullArgs...).
+		WillReturnResult(sqlmock.NewResult(0, int64(perStatement)))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "users" ("name", "email") VALUES (?, ?)`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Query[testModel]("users").CreateMany(records); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateColumnsRefreshesUpdatedAt(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "posts" SET "title" = $1, "updated_at" = $2 WHERE "id" = $3`)).
+		WithArgs("Renamed", sqlmock.AnyArg(), "p-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := &timestampedModel{ID: "p-1", Title: "Renamed"}
+	if err := Query[timestampedModel]("posts").UpdateColumns(r, "title"); err != nil {
+		t.Fatal(err)
+	}
+	if r.UpdatedAt == nil {
+		t.Error("UpdateColumns did not set updated_at on the record")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // --- buildSelect / buildCount / buildDelete / appendWhere ---
 
 func TestBuildSelectNoConditions(t *testing.T) {
 	q := Query[testModel]("users")
 	sql, args := q.buildSelect()
-	if !strings.HasPrefix(sql, "SELECT id, name, email FROM users") {
+	if !strings.HasPrefix(sql, `SELECT "id", "name", "email" FROM "users"`) {
 		t.Errorf("buildSelect = %q, unexpected", sql)
 	}
 	if args != nil {
@@ -184,8 +409,8 @@ func TestBuildSelectWithConditions(t *testing.T) {
 	q := Query[testModel]("users")
 	q.where("name", "Alice")
 	sql, args := q.buildSelect()
-	if !strings.Contains(sql, "WHERE name = $1") {
-		t.Errorf("buildSelect with conditions = %q, want WHERE name = $1", sql)
+	if !strings.Contains(sql, `WHERE "name" = $1`) {
+		t.Errorf("buildSelect with conditions = %q, want WHERE \"name\" = $1", sql)
 	}
 	if len(args) != 1 || args[0] != "Alice" {
 		t.Errorf("buildSelect args = %v, want [Alice]", args)
@@ -196,7 +421,7 @@ func TestBuildSelectOrderByLimitOffset(t *testing.T) {
 	q := Query[testModel]("users")
 	q.OrderBy("name", "ASC").Limit(10).Offset(5)
 	sql, _ := q.buildSelect()
-	if !strings.Contains(sql, "ORDER BY name ASC") {
+	if !strings.Contains(sql, `ORDER BY "name" ASC`) {
 		t.Errorf("buildSelect = %q, missing ORDER BY", sql)
 	}
 	if !strings.Contains(sql, "LIMIT 10") {
@@ -212,7 +437,7 @@ func TestBuildSelectSelectedCols(t *testing.T) {
 	q.addSelect("id")
 	q.addSelect("name")
 	sql, _ := q.buildSelect()
-	if !strings.HasPrefix(sql, "SELECT id, name FROM users") {
+	if !strings.HasPrefix(sql, `SELECT "id", "name" FROM "users"`) {
 		t.Errorf("buildSelect with selectedCols = %q, unexpected", sql)
 	}
 }
@@ -221,10 +446,10 @@ func TestBuildCount(t *testing.T) {
 	q := Query[testModel]("users")
 	q.where("email", "x@example.com")
 	sql, args := q.buildCount()
-	if !strings.HasPrefix(sql, "SELECT COUNT(*) FROM users") {
+	if !strings.HasPrefix(sql, `SELECT COUNT(*) FROM "users"`) {
 		t.Errorf("buildCount = %q, unexpected", sql)
 	}
-	if !strings.Contains(sql, "WHERE email = $1") {
+	if !strings.Contains(sql, `WHERE "email" = $1`) {
 		t.Errorf("buildCount = %q, missing WHERE", sql)
 	}
 	if len(args) != 1 {
@@ -232,11 +457,50 @@ func TestBuildCount(t *testing.T) {
 	}
 }
 
+func TestBuildSelectDistinct(t *testing.T) {
+	q := Query[testModel]("users")
+	q.Select("email").Distinct().where("name", "Alice")
+	sql, args := q.buildSelect()
+	if !strings.HasPrefix(sql, `SELECT DISTINCT "email" FROM "users" WHERE "name" = $1`) {
+		t.Errorf("buildSelect distinct = %q, unexpected", sql)
+	}
+	if len(args) != 1 {
+		t.Errorf("buildSelect args = %v, want 1", args)
+	}
+
+	sql, args = q.buildCount()
+	if sql != `SELECT COUNT(*) FROM (SELECT DISTINCT "email" FROM "users" WHERE "name" = $1) AS distinct_rows` {
+		t.Errorf("buildCount distinct = %q, unexpected", sql)
+	}
+	if len(args) != 1 {
+		t.Errorf("buildCount args = %v, want 1", args)
+	}
+}
+
+func TestBuildCountDistinct(t *testing.T) {
+	q := Query[testModel]("users")
+	q.where("name", "Alice")
+	sql, args := q.buildCountDistinct("email")
+	if sql != `SELECT COUNT(DISTINCT "email") FROM "users" WHERE "name" = $1` {
+		t.Errorf("buildCountDistinct = %q, unexpected", sql)
+	}
+	if len(args) != 1 {
+		t.Errorf("buildCountDistinct args = %v, want 1", args)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("CountDistinct with an invalid column should panic")
+		}
+	}()
+	Query[testModel]("users").CountDistinct("email); DROP TABLE users")
+}
+
 func TestBuildDelete(t *testing.T) {
 	q := Query[testModel]("users")
 	q.where("id", "99")
 	sql, args := q.buildDelete()
-	if !strings.HasPrefix(sql, "DELETE FROM users WHERE id = $1") {
+	if !strings.HasPrefix(sql, `DELETE FROM "users" WHERE "id" = $1`) {
 		t.Errorf("buildDelete = %q, unexpected", sql)
 	}
 	if len(args) != 1 {
@@ -261,7 +525,7 @@ func TestWhereInNotIn(t *testing.T) {
 	q := Query[testModel]("users")
 	q.whereIn("id", []string{"1", "2", "3"})
 	sql, args := q.buildSelect()
-	if !strings.Contains(sql, "id IN ($1, $2, $3)") {
+	if !strings.Contains(sql, `"id" IN ($1, $2, $3)`) {
 		t.Errorf("whereIn = %q, want expanded placeholders", sql)
 	}
 	if len(args) != 3 || args[0] != "1" || args[2] != "3" {
@@ -271,7 +535,7 @@ func TestWhereInNotIn(t *testing.T) {
 	q2 := Query[testModel]("users")
 	q2.whereNotIn("id", []string{"1"})
 	sql2, _ := q2.buildSelect()
-	if !strings.Contains(sql2, "id NOT IN ($1)") {
+	if !strings.Contains(sql2, `"id" NOT IN ($1)`) {
 		t.Errorf("whereNotIn = %q, want id NOT IN ($1)", sql2)
 	}
 }
@@ -297,7 +561,7 @@ func TestWhereInExpandsAfterExistingPolicyArguments(t *testing.T) {
 	q.whereIn("resource_local_id", []int64{7, 11})
 
 	sql, args := q.buildSelect()
-	if !strings.Contains(sql, "resource_local_id IN ($3, $4)") {
+	if !strings.Contains(sql, `"resource_local_id" IN ($3, $4)`) {
 		t.Fatalf("policy-scoped IN = %q, want placeholders after policy args", sql)
 	}
 	if len(args) != 4 || args[2] != int64(7) || args[3] != int64(11) {
@@ -305,6 +569,416 @@ func TestWhereInExpandsAfterExistingPolicyArguments(t *testing.T) {
 	}
 }
 
+func TestWhereExistsRenumbersAfterConditions(t *testing.T) {
+	q := Query[testModel]("posts")
+	q.where("status", "published")
+	q.WhereExists("SELECT 1 FROM comments WHERE comments.post_id = posts.id AND comments.author_id = $1 AND comments.body <> '$1'", "user-7")
+	q.whereOp("views", ">", 10)
+
+	sql, args := q.buildSelect()
+	want := `WHERE "status" = $1 AND EXISTS (SELECT 1 FROM comments WHERE comments.post_id = posts.id AND comments.author_id = $2 AND comments.body <> '$1') AND "views" > $3`
+	if !strings.Contains(sql, want) {
+		t.Fatalf("WhereExists = %q, want %q", sql, want)
+	}
+	if len(args) != 3 || args[0] != "published" || args[1] != "user-7" || args[2] != 10 {
+		t.Fatalf("WhereExists args = %#v", args)
+	}
+}
+
+func withTestRelations(t *testing.T) {
+	t.Helper()
+	saved := queryRelations
+	queryRelations = map[string]map[string]queryRelation{}
+	t.Cleanup(func() { queryRelations = saved })
+	registerRelation("users", "posts", "posts", "user_id", "id")
+	registerRelation("posts", "user", "users", "id", "user_id")
+	registerRelation("posts", "comments", "comments", "post_id", "id")
+	registerRelation("categories", "parent", "categories", "id", "parent_id")
+	registerPivotRelation("posts", "tags", "post_tag", "post_id", "id", "tag_id", "tags", "id")
+}
+
+func TestWhereHasCorrelatesAndRenumbers(t *testing.T) {
+	withTestRelations(t)
+	q := Query[testModel]("users")
+	q.where("active", true)
+	q.WhereHas("posts", func(p *QueryBuilder[any]) {
+		p.where("status", "published")
+		p.WhereHas("comments", func(c *QueryBuilder[any]) {
+			c.whereOp("score", ">", 5)
+		})
+	})
+	q.WhereDoesntHave("posts", nil)
+
+	sql, args := q.buildSelect()
+	want := `WHERE "active" = $1 AND EXISTS (SELECT 1 FROM "posts" WHERE "posts"."user_id" = "users"."id" AND "status" = $2` +
+		` AND EXISTS (SELECT 1 FROM "comments" WHERE "comments"."post_id" = "posts"."id" AND "score" > $3))` +
+		` AND NOT EXISTS (SELECT 1 FROM "posts" WHERE "posts"."user_id" = "users"."id")`
+	if !strings.Contains(sql, want) {
+		t.Fatalf("WhereHas =\n%s\nwant\n%s", sql, want)
+	}
+	if len(args) != 3 || args[0] != true || args[1] != "published" || args[2] != 5 {
+		t.Fatalf("WhereHas args = %#v", args)
+	}
+}
+
+func TestWhereHasThroughPivot(t *testing.T) {
+	withTestRelations(t)
+	q := Query[testModel]("posts")
+	q.WhereHas("tags", func(tag *QueryBuilder[any]) { tag.where("slug", "go") })
+	want := `EXISTS (SELECT 1 FROM "tags" WHERE "tags"."id" IN (SELECT "post_tag"."tag_id" FROM "post_tag" WHERE "post_tag"."post_id" = "posts"."id") AND "slug" = $1)`
+	if sql, _ := q.buildSelect(); !strings.Contains(sql, want) {
+		t.Errorf("many-to-many WhereHas =\n%s\nwant\n%s", sql, want)
+	}
+}
+
+func TestWhereHasBelongsToAndSelfReference(t *testing.T) {
+	withTestRelations(t)
+	q := Query[testModel]("posts")
+	q.WhereHas("user", func(u *QueryBuilder[any]) { u.where("role", "admin") })
+	if sql, _ := q.buildSelect(); !strings.Contains(sql, `EXISTS (SELECT 1 FROM "users" WHERE "users"."id" = "posts"."user_id" AND "role" = $1)`) {
+		t.Errorf("belongs-to WhereHas = %q", sql)
+	}
+
+	c := Query[testModel]("categories")
+	c.WhereHas("parent", func(p *QueryBuilder[any]) {
+		p.WhereHas("parent", nil)
+	})
+	want := `EXISTS (SELECT 1 FROM "categories" AS "categories_1" WHERE "categories_1"."id" = "categories"."parent_id"` +
+		` AND EXISTS (SELECT 1 FROM "categories" AS "categories_2" WHERE "categories_2"."id" = "categories_1"."parent_id"))`
+	if sql, _ := c.buildSelect(); !strings.Contains(sql, want) {
+		t.Errorf("self-referencing WhereHas =\n%s\nwant\n%s", sql, want)
+	}
+}
+
+func TestWhereHasUnknownRelationPanics(t *testing.T) {
+	withTestRelations(t)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown relation")
+		}
+	}()
+	Query[testModel]("users").WhereHas("invoices", nil)
+}
+
+type countedModel struct {
+	ID         string `db:"id"`
+	Name       string `db:"name"`
+	PostsCount int64  `json:"posts_count,omitempty" count:"posts"`
+}
+
+func TestWithCountSelectsCorrelatedSubqueries(t *testing.T) {
+	withTestRelations(t)
+	q := Query[countedModel]("users")
+	q.where("active", true)
+	q.WithCount("posts")
+
+	sql, args := q.buildSelect()
+	want := `SELECT "id", "name", (SELECT COUNT(*) FROM "posts" WHERE "posts"."user_id" = "users"."id") AS "posts_count" FROM "users" WHERE "active" = $1`
+	if sql != want {
+		t.Fatalf("WithCount =\n%s\nwant\n%s", sql, want)
+	}
+	if len(args) != 1 || args[0] != true {
+		t.Fatalf("WithCount args = %#v", args)
+	}
+	if cols := dbColumns(&countedModel{}); len(cols) != 2 {
+		t.Errorf("count field must not be a db column, got %v", cols)
+	}
+
+	var m countedModel
+	dest := dbScanDestFor(&m, []string{"id", "name", "posts_count"})
+	*dest[2].(*int64) = 12
+	if m.PostsCount != 12 {
+		t.Errorf("posts_count should scan into PostsCount, got %d", m.PostsCount)
+	}
+}
+
+func TestWithCountThroughPivotAndSelfReference(t *testing.T) {
+	withTestRelations(t)
+	q := Query[testModel]("posts").WithCount("tags")
+	want := `(SELECT COUNT(*) FROM "tags" WHERE "tags"."id" IN (SELECT "post_tag"."tag_id" FROM "post_tag" WHERE "post_tag"."post_id" = "posts"."id")) AS "tags_count"`
+	if sql, _ := q.buildSelect(); !strings.Contains(sql, want) {
+		t.Errorf("many-to-many WithCount =\n%s\nwant\n%s", sql, want)
+	}
+
+	c := Query[testModel]("categories").WithCount("parent")
+	want = `(SELECT COUNT(*) FROM "categories" AS "categories_1" WHERE "categories_1"."id" = "categories"."parent_id") AS "parent_count"`
+	if sql, _ := c.buildSelect(); !strings.Contains(sql, want) {
+		t.Errorf("self-referencing WithCount =\n%s\nwant\n%s", sql, want)
+	}
+	if sql, _ := c.buildCount(); strings.Contains(sql, "parent_count") {
+		t.Errorf("Count should ignore WithCount, got %q", sql)
+	}
+}
+
+func TestWithCountUnknownRelationPanics(t *testing.T) {
+	withTestRelations(t)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown relation")
+		}
+	}()
+	Query[testModel]("users").WithCount("invoices")
+}
+
+func TestWhereNotExistsAfterPolicyArguments(t *testing.T) {
+	q := Query[testModel]("posts")
+	q.policyClause = "tenant_id = ?"
+	q.policyArgs = []any{"tenant-1"}
+	q.WhereNotExists("SELECT 1 FROM flags WHERE flags.post_id = posts.id AND flags.kind = $1 AND flags.level > $2", "spam", 3)
+
+	sql, args := q.buildSelect()
+	if !strings.Contains(sql, "tenant_id = $1 AND NOT EXISTS (SELECT 1 FROM flags WHERE flags.post_id = posts.id AND flags.kind = $2 AND flags.level > $3)") {
+		t.Fatalf("WhereNotExists = %q", sql)
+	}
+	if len(args) != 3 || args[1] != "spam" || args[2] != 3 {
+		t.Fatalf("WhereNotExists args = %#v", args)
+	}
+}
+
+func TestWhereRawBetweenConditions(t *testing.T) {
+	q := Query[testModel]("users")
+	q.where("status", "active")
+	q.WhereRaw("lower(email) = lower($1) OR email_alias = $2", "Ada@Example.com", "ada")
+	q.whereIn("role", []string{"admin", "owner"})
+
+	sql, args := q.buildSelect()
+	want := `WHERE "status" = $1 AND (lower(email) = lower($2) OR email_alias = $3) AND "role" IN ($4, $5)`
+	if !strings.Contains(sql, want) {
+		t.Fatalf("WhereRaw = %q, want %q", sql, want)
+	}
+	if len(args) != 5 || args[0] != "active" || args[1] != "Ada@Example.com" || args[2] != "ada" || args[3] != "admin" || args[4] != "owner" {
+		t.Fatalf("WhereRaw args = %#v", args)
+	}
+}
+
+func TestWhereRawInUpdateFollowsSetArguments(t *testing.T) {
+	q := Query[testModel]("users")
+	q.WhereRaw("jsonb_path_exists(settings, '$.beta') AND tenant_id = $1", "tenant-1")
+	q.where("id", 9)
+
+	sql, args := buildUpdate(q.table, &testModel{Name: "Ada"}, q.conditions, "", nil)
+	if !strings.Contains(sql, `WHERE (jsonb_path_exists(settings, '$.beta') AND tenant_id = $3) AND "id" = $4`) {
+		t.Fatalf("WhereRaw update = %q", sql)
+	}
+	if len(args) != 4 || args[2] != "tenant-1" || args[3] != 9 {
+		t.Fatalf("WhereRaw update args = %#v", args)
+	}
+}
+
+func TestWhereJSONComparesTextAtPath(t *testing.T) {
+	q := Query[testModel]("users")
+	q.where("status", "active")
+	q.WhereJSON("settings", "theme.color", "dark")
+	q.WhereJSON("settings", "tags.0", "beta")
+
+	sql, args := q.buildSelect()
+	want := `WHERE "status" = $1 AND "settings" #>> '{theme,color}' = $2 AND "settings" #>> '{tags,0}' = $3`
+	if !strings.Contains(sql, want) {
+		t.Fatalf("WhereJSON = %q, want %q", sql, want)
+	}
+	if len(args) != 3 || args[1] != "dark" || args[2] != "beta" {
+		t.Fatalf("WhereJSON args = %#v", args)
+	}
+}
+
+func TestWhereJSONContainsCastsParameterToJSONB(t *testing.T) {
+	q := Query[testModel]("users")
+	q.WhereJSONContains("settings", `{"beta":true}`)
+	q.WhereJSONContains("settings", map[string]any{"roles": []string{"admin"}})
+
+	sql, args := q.buildSelect()
+	want := `WHERE "settings" @> $1::jsonb AND "settings" @> $2::jsonb`
+	if !strings.Contains(sql, want) {
+		t.Fatalf("WhereJSONContains = %q, want %q", sql, want)
+	}
+	if len(args) != 2 || args[0] != `{"beta":true}` {
+		t.Fatalf("WhereJSONContains args = %#v", args)
+	}
+	encoded, err := args[1].(driver.Valuer).Value()
+	if err != nil || encoded != `{"roles":["admin"]}` {
+		t.Fatalf("encoded arg = %#v, %v", encoded, err)
+	}
+	if _, err := (jsonValue{make(chan int)}).Value(); err == nil {
+		t.Error("expected an encoding error for an unsupported value")
+	}
+}
+
+func TestWhereJSONRequiresPostgresAndValidPath(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+	expectPanic("bad path", func() { Query[testModel]("users").WhereJSON("settings", "a'}; DROP", "x") })
+	expectPanic("empty segment", func() { Query[testModel]("users").WhereJSON("settings", "a..b", "x") })
+
+	withDatabaseDriver(t, "mysql")
+	expectPanic("mysql path", func() { Query[testModel]("users").WhereJSON("settings", "a", "x") })
+	expectPanic("mysql contains", func() { Query[testModel]("users").WhereJSONContains("settings", "{}") })
+}
+
+func withCountTestDB(t *testing.T, driver string) sqlmock.Sqlmock {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldDriver := DB, DatabaseDriver
+	DB, DatabaseDriver = db, driver
+	t.Cleanup(func() {
+		DB, DatabaseDriver = oldDB, oldDriver
+		db.Close()
+	})
+	return mock
+}
+
+// transferModel is the model generated for a transfers table with amount
+// NUMERIC(18,2) NOT NULL and a nullable fee NUMERIC(18,2).
+type transferModel struct {
+	ID     string           `db:"id" pickle:"pk"`
+	Amount decimal.Decimal  `db:"amount"`
+	Fee    *decimal.Decimal `db:"fee"`
+}
+
+func TestDecimalColumnsRoundTrip(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	amount := decimal.RequireFromString("9999999999999999.99") // beyond float64 precision
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "transfers" ("amount", "fee") VALUES ($1, $2) RETURNING "id", "amount", "fee"`)).
+		WithArgs("9999999999999999.99", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "fee"}).AddRow("t-1", []byte("9999999999999999.99"), nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "amount", "fee" FROM "transfers" WHERE "id" = $1 LIMIT 1`)).
+		WithArgs("t-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "fee"}).AddRow("t-1", []byte("9999999999999999.99"), []byte("0.25")))
+
+	created := &transferModel{Amount: amount}
+	if err := Query[transferModel]("transfers").Create(created); err != nil {
+		t.Fatal(err)
+	}
+	if !created.Amount.Equal(amount) || created.Fee != nil {
+		t.Errorf("Create scanned amount %s, fee %v", created.Amount, created.Fee)
+	}
+
+	found, err := Query[transferModel]("transfers").where("id", "t-1").First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Amount.String() != "9999999999999999.99" || found.Fee == nil || found.Fee.String() != "0.25" {
+		t.Errorf("First scanned amount %s, fee %v", found.Amount, found.Fee)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecimalAggregateKeepsPrecision(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT SUM("amount") FROM "transfers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow([]byte("19999999999999999.98")))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT SUM("amount") FROM "transfers" WHERE "id" = $1`)).
+		WithArgs("none").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(nil))
+
+	var total decimal.NullDecimal
+	if err := Query[transferModel]("transfers").aggregateInto(&total, "SUM", "amount"); err != nil {
+		t.Fatal(err)
+	}
+	if !total.Valid || total.Decimal.String() != "19999999999999999.98" {
+		t.Errorf("SUM = %v, want 19999999999999999.98", total)
+	}
+
+	var empty decimal.NullDecimal
+	if err := Query[transferModel]("transfers").where("id", "none").aggregateInto(&empty, "SUM", "amount"); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Valid {
+		t.Errorf("SUM over no rows should be NULL, got %v", empty.Decimal)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountEstimatePostgresReadsPgClass(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)")).
+		WithArgs(`"events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(int64(48213377)))
+
+	n, err := Query[testModel]("events").CountEstimate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 48213377 {
+		t.Errorf("CountEstimate = %d, want 48213377", n)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountEstimatePassesQuotedTableToRegclass(t *testing.T) {
+	mock := withCountTestDB(t, "postgres")
+	mock.ExpectQuery(regexp.QuoteMeta("to_regclass($1)")).
+		WithArgs(`"audit"."Events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(int64(5)))
+
+	if n, err := Query[testModel]("audit.Events").CountEstimate(); err != nil || n != 5 {
+		t.Fatalf("CountEstimate = %d, %v; want 5", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountEstimatePostgresUnanalyzedFallsBackToCount(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta("FROM pg_class")).
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(int64(-1)))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(12)))
+
+	n, err := Query[testModel]("events").CountEstimate()
+	if err != nil || n != 12 {
+		t.Fatalf("CountEstimate = %d, %v; want 12", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountEstimateFilteredQueryCountsExactly(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "events" WHERE "kind" = $1`)).
+		WithArgs("click").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(3)))
+
+	n, err := Query[testModel]("events").where("kind", "click").CountEstimate()
+	if err != nil || n != 3 {
+		t.Fatalf("CountEstimate = %d, %v; want 3", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountEstimateSQLiteCountsExactly(t *testing.T) {
+	mock := withCountTestDB(t, "sqlite")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(7)))
+
+	n, err := Query[testModel]("events").CountEstimate()
+	if err != nil || n != 7 {
+		t.Fatalf("CountEstimate = %d, %v; want 7", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // --- QueryBuilder builder methods (chainable, no DB) ---
 
 func TestQueryBuilderChaining(t *testing.T) {
@@ -474,3 +1148,315 @@ func TestParseQueryFiltersEmptyValue(t *testing.T) {
 	}
 	_ = filters
 }
+
+func TestSelectScansOnlySelectedColumns(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "email", "id" FROM "users" WHERE "name" = $1 LIMIT 1`)).
+		WithArgs("Ada").
+		WillReturnRows(sqlmock.NewRows([]string{"email", "id"}).AddRow("ada@example.com", "u-1"))
+
+	got, err := Query[testModel]("users").Select("email", "id").where("name", "Ada").First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "u-1" || got.Email != "ada@example.com" || got.Name != "" {
+		t.Errorf("First with Select = %+v, want only ID and Email set", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelectAllScansEachRowByColumn(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "name" FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Ada").AddRow("Grace"))
+
+	got, err := Query[testModel]("users").Select("name").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "Ada" || got[1].Name != "Grace" || got[0].ID != "" {
+		t.Errorf("All with Select = %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanMatchesReorderedColumnsByName(t *testing.T) {
+	// The database returns the table's columns in a different order than the
+	// struct declares them, as after a migration reorders or adds columns.
+	mock := withCountTestDB(t, "pgsql")
+	reordered := []string{"email", "added_later", "name", "id"}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name", "email" FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows(reordered).
+			AddRow("ada@example.com", 1, "Ada", "u-1").
+			AddRow("grace@example.com", 2, "Grace", "u-2"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name", "email" FROM "users" LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows(reordered).AddRow("ada@example.com", 1, "Ada", "u-1"))
+
+	all, err := Query[testModel]("users").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[1] != (testModel{ID: "u-2", Name: "Grace", Email: "grace@example.com"}) {
+		t.Errorf("All = %+v", all)
+	}
+	first, err := Query[testModel]("users").First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *first != (testModel{ID: "u-1", Name: "Ada", Email: "ada@example.com"}) {
+		t.Errorf("First = %+v", first)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFirstReturnsErrNoRows(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "name", "email" FROM "users" LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}))
+	if _, err := Query[testModel]("users").First(); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("First with no rows: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestSelectRejectsInvalidColumn(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Select to panic on an invalid column")
+		}
+	}()
+	Query[testModel]("users").Select("email; DROP TABLE users")
+}
+
+func TestGroupByHavingNumbersAfterWhereArgs(t *testing.T) {
+	q := Query[testModel]("posts")
+	q.where("status", "published")
+	q.WhereRaw("created_at > $1", "2026-01-01")
+	q.GroupBy("user_id").Having("COUNT(*) >= $1 AND MAX(views) < $2", 3, 1000).OrderBy("user_id", "asc")
+
+	sql, args := q.buildGroupedSelect("user_id, COUNT(*) AS posts")
+	want := `SELECT user_id, COUNT(*) AS posts FROM "posts" WHERE "status" = $1 AND (created_at > $2) GROUP BY "user_id" HAVING (COUNT(*) >= $3 AND MAX(views) < $4) ORDER BY "user_id" ASC`
+	if sql != want {
+		t.Fatalf("grouped select = %q, want %q", sql, want)
+	}
+	if len(args) != 4 || args[2] != 3 || args[3] != 1000 {
+		t.Fatalf("grouped select args = %#v", args)
+	}
+}
+
+func TestAggregateScansRowsIntoSlice(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id, COUNT(*) AS posts FROM "posts" WHERE "status" = $1 GROUP BY "user_id" HAVING (COUNT(*) > $2)`)).
+		WithArgs("published", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "posts"}).AddRow("u-1", int64(4)).AddRow("u-2", int64(2)))
+
+	var counts []struct {
+		UserID string `db:"user_id"`
+		Posts  int64  `db:"posts"`
+	}
+	err := Query[testModel]("posts").where("status", "published").
+		GroupBy("user_id").Having("COUNT(*) > $1", 1).
+		Aggregate(&counts, "user_id, COUNT(*) AS posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0].UserID != "u-1" || counts[0].Posts != 4 || counts[1].Posts != 2 {
+		t.Errorf("Aggregate = %+v", counts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAggregateScansFirstRowIntoStruct(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) AS total, MAX(name) AS last FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "last"}).AddRow(int64(12), "Zed"))
+
+	var stats struct {
+		Total int64  `db:"total"`
+		Last  string `db:"last"`
+	}
+	if err := Query[testModel]("users").Aggregate(&stats, "COUNT(*) AS total, MAX(name) AS last"); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 12 || stats.Last != "Zed" {
+		t.Errorf("Aggregate = %+v", stats)
+	}
+}
+
+func TestAggregateRejectsNonStructDest(t *testing.T) {
+	var n int64
+	if err := Query[testModel]("users").Aggregate(&n, "COUNT(*)"); err == nil {
+		t.Error("expected an error for a non-struct destination")
+	}
+}
+
+func TestOrderByAllowedAppliesListedColumns(t *testing.T) {
+	sql, _ := Query[testModel]("users").OrderByAllowed("name", "desc", "name", "email").buildSelect()
+	if !strings.HasSuffix(sql, `ORDER BY "name" DESC`) {
+		t.Errorf("OrderByAllowed = %q, want ORDER BY \"name\" DESC", sql)
+	}
+	sql, _ = Query[testModel]("users").OrderByAllowed("email", "", "name", "email").buildSelect()
+	if !strings.HasSuffix(sql, `ORDER BY "email" ASC`) {
+		t.Errorf("OrderByAllowed with empty direction = %q, want ASC", sql)
+	}
+}
+
+func TestOrderByAllowedIgnoresUnlistedInput(t *testing.T) {
+	for _, tc := range [][2]string{{"password", "asc"}, {"name; DROP TABLE users", "asc"}, {"name", "sideways"}} {
+		sql, _ := Query[testModel]("users").OrderByAllowed(tc[0], tc[1], "name").OrderBy("id", "ASC").buildSelect()
+		if !strings.HasSuffix(sql, `ORDER BY "id" ASC`) {
+			t.Errorf("OrderByAllowed(%q, %q) = %q, want only the fallback ordering", tc[0], tc[1], sql)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an invalid allowed column")
+		}
+	}()
+	Query[testModel]("users").OrderByAllowed("name", "asc", "name desc")
+}
+
+type pkOrderedModel struct {
+	Code string `db:"code" pickle:"pk"`
+	Name string `db:"name"`
+}
+
+func TestOrderByPrimaryKeyUsesTaggedColumn(t *testing.T) {
+	sql, _ := Query[pkOrderedModel]("countries").OrderByPrimaryKey().buildSelect()
+	if !strings.HasSuffix(sql, `ORDER BY "code" ASC`) {
+		t.Errorf("OrderByPrimaryKey = %q, want ORDER BY \"code\" ASC", sql)
+	}
+	sql, _ = Query[testModel]("users").OrderByPrimaryKey().buildSelect()
+	if !strings.HasSuffix(sql, `ORDER BY "id" ASC`) {
+		t.Errorf("OrderByPrimaryKey without pk tag = %q, want ORDER BY \"id\" ASC", sql)
+	}
+}
+
+func TestAllDefaultOrderByPrimaryKeyOnSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE countries (code TEXT PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO countries (code, name) VALUES ('se', 'Sweden'), ('at', 'Austria'), ('no', 'Norway')"); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldDriver, oldDefault := DB, DatabaseDriver, DefaultOrderByPrimaryKey
+	DB, DatabaseDriver, DefaultOrderByPrimaryKey = db, "sqlite", true
+	t.Cleanup(func() { DB, DatabaseDriver, DefaultOrderByPrimaryKey = oldDB, oldDriver, oldDefault })
+
+	rows, err := Query[pkOrderedModel]("countries").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	for _, r := range rows {
+		codes = append(codes, r.Code)
+	}
+	if got := strings.Join(codes, ","); got != "at,no,se" {
+		t.Errorf("All() = %s, want primary key order at,no,se", got)
+	}
+
+	rows, err = Query[pkOrderedModel]("countries").OrderBy("name", "DESC").All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Code != "se" {
+		t.Errorf("explicit OrderBy should win over the default, got %s first", rows[0].Code)
+	}
+
+	q := Query[pkOrderedModel]("countries")
+	if _, err := q.All(); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.orderBy) != 0 {
+		t.Errorf("All() left orderBy = %v on the builder, want it untouched", q.orderBy)
+	}
+	if sql, _ := q.OrderBy("name", "DESC").buildSelect(); !strings.HasSuffix(sql, `ORDER BY "name" DESC`) {
+		t.Errorf("reused builder = %q, want only the later OrderBy", sql)
+	}
+
+	distinct := Query[pkOrderedModel]("countries").Select("name").Distinct()
+	if order := distinct.defaultOrder(); len(order) != 0 {
+		t.Errorf("DISTINCT without the key selected ordered by %v, want no default", order)
+	}
+	if order := Query[pkOrderedModel]("countries").Select("code", "name").Distinct().defaultOrder(); len(order) != 1 || order[0] != "code ASC" {
+		t.Errorf("DISTINCT with the key selected ordered by %v, want code ASC", order)
+	}
+	if _, err := distinct.All(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnRunsQueryOnGivenConnection(t *testing.T) {
+	primary := withCountTestDB(t, "pgsql")
+	replica, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replica.Close()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(3)))
+
+	n, err := Query[testModel]("users").On(replica).Count()
+	if err != nil || n != 3 {
+		t.Fatalf("Count on replica = %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if QueryOn[testModel](replica, "users").db() != replica {
+		t.Error("QueryOn should run on the given connection")
+	}
+}
+
+func TestConnectionOpensNamedConnectionOnce(t *testing.T) {
+	replica, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replica.Close()
+	opened := 0
+	SetConnectionOpener(func(name string) (*sql.DB, error) {
+		if name != "replica" {
+			return nil, errors.New("no such connection")
+		}
+		opened++
+		return replica, nil
+	})
+	t.Cleanup(func() {
+		SetConnectionOpener(nil)
+		ManagedConnections.mu.Lock()
+		delete(ManagedConnections.m, "replica")
+		ManagedConnections.mu.Unlock()
+	})
+
+	for i := 0; i < 2; i++ {
+		db, err := Connection("replica")
+		if err != nil || db != replica {
+			t.Fatalf("Connection(replica) = %v, %v", db, err)
+		}
+	}
+	if opened != 1 {
+		t.Errorf("opened %d times, want 1", opened)
+	}
+	if _, err := Connection("analytics"); err == nil {
+		t.Error("expected an error for a connection the opener rejects")
+	}
+	if db, _ := Connection(""); db != DB {
+		t.Error("empty name should return the default DB")
+	}
+}
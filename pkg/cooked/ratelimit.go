@@ -11,17 +11,46 @@ import (
 	"time"
 )
 
-// env returns the value of the environment variable named by key,
-// or fallback if the variable is not set. This is a local helper so
-// the rate-limiter code is self-contained when embedded into generated
-// packages that don't include the config module.
+// env returns the value of the environment variable named by key, then the
+// .env file, or fallback if neither sets it — the same sources config's Env
+// reads. This is a local helper so the rate-limiter code is self-contained
+// when embedded into generated packages that don't include the config module.
 func env(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
 	}
+	dotEnvOnce.Do(loadDotEnv)
+	if v := dotEnvVars[key]; v != "" {
+		return v
+	}
 	return fallback
 }
 
+var dotEnvOnce sync.Once
+var dotEnvVars map[string]string
+
+// loadDotEnv reads KEY=value lines from .env into dotEnvVars, skipping
+// comments and stripping matching quotes like config's loader does.
+func loadDotEnv() {
+	dotEnvVars = map[string]string{}
+	data, err := os.ReadFile(".env")
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(line, "#") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		dotEnvVars[strings.TrimSpace(key)] = value
+	}
+}
+
 // MiddlewareProvider is implemented by types that can produce a MiddlewareFunc.
 // Route registration methods check for this interface and unwrap automatically,
 // so builders like AuthRateLimitConfig can be passed directly as middleware.
@@ -32,7 +61,7 @@ type MiddlewareProvider interface {
 // RateLimitEvent contains information about a rate limit check for observability.
 type RateLimitEvent struct {
 	Key       string  // identity key or IP
-	Layer     string  // "ip" or "auth"
+	Layer     string  // "ip", "auth" or "route" (RateLimitWithStore)
 	Path      string  // request path
 	RPS       float64 // configured limit
 	Burst     int     // configured burst
@@ -323,56 +352,64 @@ var (
 
 func initTrustedProxies() {
 	trustedProxiesOnce.Do(func() {
-		raw := env("TRUSTED_PROXIES", "")
-		raw = strings.TrimSpace(raw)
+		raw := strings.TrimSpace(env("TRUSTED_PROXIES", ""))
 		if raw == "" {
 			return
 		}
-		if raw == "all" {
-			trustedProxiesAll = true
-			return
+		trustedProxies, trustedProxiesAll = parseTrustedProxies(strings.Split(raw, ","))
+	})
+}
+
+// parseTrustedProxies turns a list of IPs and CIDR ranges into networks. A
+// bare IP becomes a /32 or /128, "all" trusts every address, and invalid
+// entries are skipped.
+func parseTrustedProxies(entries []string) (nets []net.IPNet, all bool) {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "all" {
+			return nil, true
 		}
-		for _, entry := range strings.Split(raw, ",") {
-			entry = strings.TrimSpace(entry)
-			if entry == "" {
+		// If it's a bare IP, make it a /32 or /128.
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
 				continue
 			}
-			// If it's a bare IP, make it a /32 or /128.
-			if !strings.Contains(entry, "/") {
-				ip := net.ParseIP(entry)
-				if ip == nil {
-					continue
-				}
-				if ip.To4() != nil {
-					entry += "/32"
-				} else {
-					entry += "/128"
-				}
-			}
-			_, cidr, err := net.ParseCIDR(entry)
-			if err != nil {
-				continue
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
 			}
-			trustedProxies = append(trustedProxies, *cidr)
 		}
-	})
+		_, cidr, err := net.ParseCIDR(entry)
+		if err != nil {
+			continue
+		}
+		nets = append(nets, *cidr)
+	}
+	return nets, false
 }
 
 // proxyHeadersTrusted returns true if the remote IP is in the TRUSTED_PROXIES list.
 func proxyHeadersTrusted(remoteIP string) bool {
 	initTrustedProxies()
-	if trustedProxiesAll {
+	return ipInNets(remoteIP, trustedProxies, trustedProxiesAll)
+}
+
+// ipInNets reports whether ip parses and falls in one of nets, or all is set.
+func ipInNets(ip string, nets []net.IPNet, all bool) bool {
+	if all {
 		return true
 	}
-	if len(trustedProxies) == 0 {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
 		return false
 	}
-	ip := net.ParseIP(remoteIP)
-	if ip == nil {
-		return false
-	}
-	for _, cidr := range trustedProxies {
-		if cidr.Contains(ip) {
+	for _, cidr := range nets {
+		if cidr.Contains(parsed) {
 			return true
 		}
 	}
@@ -380,15 +417,15 @@ func proxyHeadersTrusted(remoteIP string) bool {
 }
 
 // firstUntrustedIP walks an X-Forwarded-For chain right-to-left, skipping
-// IPs that are in TRUSTED_PROXIES, and returns the first untrusted IP.
-func firstUntrustedIP(xff string) string {
+// IPs that trusted accepts, and returns the first untrusted IP.
+func firstUntrustedIP(xff string, trusted func(string) bool) string {
 	parts := strings.Split(xff, ",")
 	for i := len(parts) - 1; i >= 0; i-- {
 		ip := strings.TrimSpace(parts[i])
 		if ip == "" {
 			continue
 		}
-		if !proxyHeadersTrusted(ip) {
+		if !trusted(ip) {
 			return ip
 		}
 	}
@@ -539,18 +576,170 @@ func RateLimit(rps float64, burst int) MiddlewareFunc {
 	}
 }
 
+// RateLimitStore decides whether the request identified by key may proceed.
+// It reports how many requests remain in the current window and, when the
+// request is denied, how long the caller should wait. The in-memory store is
+// the default; implement this interface to share limits across instances
+// (e.g. a Redis-backed limiter). Limit reports the allowance the store
+// enforces, which the middleware sends in the X-RateLimit-* headers.
+type RateLimitStore interface {
+	Allow(key string) (allowed bool, remaining int, retryAfter time.Duration)
+	Limit() (limit int, window time.Duration)
+}
+
+// MemoryRateLimitStore is an in-process token bucket store. Each key gets a
+// bucket holding limit tokens that refills continuously over window.
+type MemoryRateLimitStore struct {
+	store       *rateLimiterStore
+	window      time.Duration
+	cleanupOnce sync.Once
+	stopOnce    sync.Once
+	stop        chan struct{}
+}
+
+// NewMemoryRateLimitStore returns an in-memory store allowing limit requests
+// per window for each key. Idle buckets are evicted by a goroutine started on
+// the first Allow and ended by Stop.
+func NewMemoryRateLimitStore(limit int, window time.Duration) *MemoryRateLimitStore {
+	if limit < 1 {
+		limit = 1
+	}
+	rps := 0.0
+	if window > 0 {
+		rps = float64(limit) / window.Seconds()
+	}
+	return &MemoryRateLimitStore{
+		store: &rateLimiterStore{
+			rps:     rps,
+			burst:   limit,
+			enabled: rps > 0,
+		},
+		window: window,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Stop ends the store's cleanup goroutine. The store keeps limiting, but
+// idle buckets are no longer evicted.
+func (s *MemoryRateLimitStore) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *MemoryRateLimitStore) startCleanup() {
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.store.cleanup()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Limit implements RateLimitStore.
+func (s *MemoryRateLimitStore) Limit() (int, time.Duration) {
+	return s.store.burst, s.window
+}
+
+// Allow implements RateLimitStore.
+func (s *MemoryRateLimitStore) Allow(key string) (bool, int, time.Duration) {
+	if !s.store.enabled {
+		return true, s.store.burst, 0
+	}
+	s.cleanupOnce.Do(s.startCleanup)
+	bucket, ok := s.store.allow(key)
+	bucket.mu.Lock()
+	remaining := int(bucket.tokens)
+	deficit := 1.0 - bucket.tokens
+	bucket.mu.Unlock()
+	if ok {
+		return true, remaining, 0
+	}
+	wait := time.Duration(deficit / s.store.rps * float64(time.Second))
+	return false, 0, wait
+}
+
+// RateLimitWindow returns a middleware that allows limit requests per window
+// for each client IP, using an in-memory token bucket. X-Forwarded-For is
+// only honored when the remote address is listed in TRUSTED_PROXIES.
+//
+//	r.Post("/auth/login", controllers.AuthController{}.Login, pickle.RateLimitWindow(5, time.Minute))
+func RateLimitWindow(limit int, window time.Duration) MiddlewareFunc {
+	return RateLimitWithStore(NewMemoryRateLimitStore(limit, window))
+}
+
+// RateLimitWithStore returns a per-IP rate limiting middleware backed by the
+// given store. Responses carry X-RateLimit-* headers for the store's Limit,
+// denied requests get a 429 with a Retry-After header, and each check is
+// reported to the OnRateLimit callback.
+func RateLimitWithStore(store RateLimitStore) MiddlewareFunc {
+	return func(ctx *Context, next func() Response) Response {
+		key := clientIP(ctx.Request())
+		allowed, remaining, wait := store.Allow(key)
+
+		limit, window := store.Limit()
+		rps := 0.0
+		if window > 0 {
+			rps = float64(limit) / window.Seconds()
+		}
+		if rateLimitCallback != nil {
+			rateLimitCallback(ctx, RateLimitEvent{
+				Key:       key,
+				Layer:     "route",
+				Path:      ctx.Request().URL.Path,
+				RPS:       rps,
+				Burst:     limit,
+				Remaining: float64(remaining),
+				Allowed:   allowed,
+			})
+		}
+
+		var resp Response
+		if allowed {
+			resp = setRateLimitHeaders(next(), rps, limit, float64(remaining))
+		} else {
+			retry := int(math.Ceil(wait.Seconds()))
+			if retry < 1 {
+				retry = 1
+			}
+			resp = setRateLimitHeaders(Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       map[string]string{"error": "rate limit exceeded"},
+				Headers: map[string]string{
+					"Content-Type": "application/json",
+					"Retry-After":  strconv.Itoa(retry),
+				},
+			}, rps, limit, 0)
+		}
+		// The limit is a count per window; setRateLimitHeaders writes it per second.
+		resp.Headers["X-RateLimit-Limit"] = strconv.Itoa(limit)
+		return resp
+	}
+}
+
 // clientIP extracts the client IP from the request. Proxy headers
 // (X-Forwarded-For, X-Real-IP) are only trusted when the immediate
 // remote address is in the TRUSTED_PROXIES list.
 func clientIP(r *http.Request) string {
+	return resolveClientIP(r, proxyHeadersTrusted)
+}
+
+// resolveClientIP returns the address r came from. X-Forwarded-For and
+// X-Real-IP are read only when trusted accepts the direct remote address;
+// otherwise any client could claim to be someone else by sending them.
+func resolveClientIP(r *http.Request, trusted func(string) bool) string {
 	remote := stripPort(r.RemoteAddr)
 
-	if !proxyHeadersTrusted(remote) {
+	if !trusted(remote) {
 		return remote
 	}
 
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return firstUntrustedIP(xff)
+		return firstUntrustedIP(xff, trusted)
 	}
 
 	if xri := r.Header.Get("X-Real-IP"); xri != "" {
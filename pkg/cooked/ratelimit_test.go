@@ -4,6 +4,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -190,7 +192,7 @@ func TestFirstUntrustedIPAllTrusted(t *testing.T) {
 	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
 
 	// When all IPs in the chain are trusted, return leftmost.
-	got := firstUntrustedIP("10.0.0.1, 10.0.0.2, 10.0.0.3")
+	got := firstUntrustedIP("10.0.0.1, 10.0.0.2, 10.0.0.3", proxyHeadersTrusted)
 	if got != "10.0.0.1" {
 		t.Errorf("expected leftmost IP when all trusted, got %q", got)
 	}
@@ -277,6 +279,153 @@ func TestRateLimitMiddleware(t *testing.T) {
 	}
 }
 
+func TestRateLimitWindowResets(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	mw := RateLimitWindow(2, 100*time.Millisecond)
+	handler := func() Response { return Response{StatusCode: 200} }
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	ctx := NewContext(httptest.NewRecorder(), req)
+
+	for i := 0; i < 2; i++ {
+		if resp := mw(ctx, handler); resp.StatusCode != 200 {
+			t.Fatalf("request %d: expected 200, got %d", i+1, resp.StatusCode)
+		}
+	}
+	resp := mw(ctx, handler)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Headers["Retry-After"] != "1" {
+		t.Fatalf("expected Retry-After=1, got %q", resp.Headers["Retry-After"])
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	if resp := mw(ctx, handler); resp.StatusCode != 200 {
+		t.Fatalf("expected 200 after window reset, got %d", resp.StatusCode)
+	}
+}
+
+func TestRateLimitWindowConcurrent(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	const limit = 50
+	mw := RateLimitWindow(limit, time.Hour)
+	handler := func() Response { return Response{StatusCode: 200} }
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = "10.0.0.9:5555"
+			if mw(NewContext(httptest.NewRecorder(), req), handler).StatusCode == 200 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != limit {
+		t.Fatalf("expected exactly %d allowed requests, got %d", limit, allowed)
+	}
+}
+
+type fixedRateLimitStore struct{ keys []string }
+
+func (s *fixedRateLimitStore) Allow(key string) (bool, int, time.Duration) {
+	s.keys = append(s.keys, key)
+	return false, 0, 2500 * time.Millisecond
+}
+
+func (s *fixedRateLimitStore) Limit() (int, time.Duration) { return 5, time.Minute }
+
+func TestRateLimitWithCustomStore(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+
+	store := &fixedRateLimitStore{}
+	mw := RateLimitWithStore(store)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.7:5555"
+	resp := mw(NewContext(httptest.NewRecorder(), req), func() Response {
+		t.Fatal("handler should not run when the store denies")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Headers["Retry-After"] != "3" {
+		t.Fatalf("expected Retry-After rounded up to 3, got %q", resp.Headers["Retry-After"])
+	}
+	if len(store.keys) != 1 || store.keys[0] != "10.0.0.7" {
+		t.Fatalf("expected store keyed by client IP, got %v", store.keys)
+	}
+	if resp.Headers["X-RateLimit-Limit"] != "5" || resp.Headers["X-RateLimit-Remaining"] != "0" || resp.Headers["X-RateLimit-Reset"] == "" {
+		t.Fatalf("expected X-RateLimit-* headers for 5 per minute, got %v", resp.Headers)
+	}
+}
+
+func TestRateLimitWindowHeadersAndCallback(t *testing.T) {
+	resetTrustedProxies()
+	t.Setenv("TRUSTED_PROXIES", "")
+	var events []RateLimitEvent
+	rateLimitCallback = func(ctx *Context, event RateLimitEvent) {
+		events = append(events, event)
+	}
+	defer func() { rateLimitCallback = nil }()
+
+	mw := RateLimitWindow(3, time.Minute)
+	req := httptest.NewRequest("GET", "/login", nil)
+	req.RemoteAddr = "10.0.0.8:5555"
+	resp := mw(NewContext(httptest.NewRecorder(), req), func() Response { return Response{StatusCode: 200} })
+
+	if resp.Headers["X-RateLimit-Limit"] != "3" || resp.Headers["X-RateLimit-Remaining"] != "2" {
+		t.Errorf("headers = %v, want limit 3 and 2 remaining", resp.Headers)
+	}
+	reset, _ := strconv.ParseInt(resp.Headers["X-RateLimit-Reset"], 10, 64)
+	if wait := time.Until(time.Unix(reset, 0)); wait < 15*time.Second || wait > 25*time.Second {
+		t.Errorf("X-RateLimit-Reset is %v away, want about the 20s one token takes to refill", wait)
+	}
+	if len(events) != 1 || events[0].Layer != "route" || events[0].Key != "10.0.0.8" || events[0].Path != "/login" || !events[0].Allowed {
+		t.Errorf("OnRateLimit events = %+v", events)
+	}
+}
+
+func TestMemoryRateLimitStoreCleanupIsLazyAndStops(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stores := make([]*MemoryRateLimitStore, 20)
+	for i := range stores {
+		stores[i] = NewMemoryRateLimitStore(5, time.Minute)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("goroutines grew from %d to %d before any request", before, n)
+	}
+
+	stores[0].Allow("a")
+	stores[0].Allow("b")
+	stores[0].Stop()
+	stores[0].Stop()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("goroutines = %d after Stop, want %d", n, before)
+	}
+}
+
 // Verify net import is used (for compilation).
 var _ = net.ParseIP
 
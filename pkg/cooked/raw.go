@@ -0,0 +1,65 @@
+package cooked
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Raw runs query on the default connection and scans each row into a T.
+// It is the escape hatch for reports and other SQL the builder can't
+// express. Result columns are matched to T's fields by db tag, so the
+// SELECT list can be in any order; columns with no matching field are
+// dropped and fields with no matching column keep their zero value.
+//
+//	type revenueRow struct {
+//	    Month string          `db:"month"`
+//	    Total decimal.Decimal `db:"total"`
+//	}
+//	rows, err := models.Raw[revenueRow](`
+//	    SELECT date_trunc('month', created_at)::date::text AS month, SUM(amount) AS total
+//	    FROM transfers WHERE team_id = $1 GROUP BY 1 ORDER BY 1`, teamID)
+//
+// The query is sent as written: args are bound as parameters, but
+// placeholders use the driver's syntax and row policies and soft-delete
+// scopes are not applied.
+func Raw[T any](query string, args ...any) ([]T, error) {
+	if err := checkRawType[T]("Raw"); err != nil {
+		return nil, err
+	}
+	q := &QueryBuilder[T]{}
+	db := q.db()
+	defer q.releaseConn()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRows[T](rows)
+}
+
+// RawOne is Raw for a query expected to return one row. It returns
+// sql.ErrNoRows when there are none and ignores any rows after the first.
+func RawOne[T any](query string, args ...any) (*T, error) {
+	if err := checkRawType[T]("RawOne"); err != nil {
+		return nil, err
+	}
+	q := &QueryBuilder[T]{}
+	db := q.db()
+	defer q.releaseConn()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanFirst[T](rows)
+}
+
+// checkRawType rejects a T that isn't a struct, which has no db tags to
+// match columns against.
+func checkRawType[T any](fn string) error {
+	var zero T
+	if rt := reflect.TypeOf(zero); rt == nil || rt.Kind() != reflect.Struct {
+		return fmt.Errorf("pickle: %s needs a struct type with db tags, got %T", fn, zero)
+	}
+	return nil
+}
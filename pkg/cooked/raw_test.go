@@ -0,0 +1,71 @@
+package cooked
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+)
+
+type revenueRow struct {
+	Month string          `db:"month"`
+	Total decimal.Decimal `db:"total"`
+	Notes *string         `db:"notes"`
+}
+
+func TestRawMatchesColumnsByName(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	query := `SELECT SUM(amount) AS total, extra, month FROM transfers WHERE team_id = $1 GROUP BY month`
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs("team-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "extra", "month"}).
+			AddRow([]byte("10.50"), 1, "2026-01").
+			AddRow([]byte("3.25"), 2, "2026-02"))
+
+	rows, err := Raw[revenueRow](query, "team-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Month != "2026-01" || !rows[0].Total.Equal(decimal.RequireFromString("10.50")) || rows[1].Month != "2026-02" {
+		t.Errorf("Raw = %+v", rows)
+	}
+	if rows[0].Notes != nil {
+		t.Errorf("field with no column should stay zero, got %v", *rows[0].Notes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRawOne(t *testing.T) {
+	mock := withCountTestDB(t, "pgsql")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 'note' AS notes, '2026-03' AS month")).
+		WillReturnRows(sqlmock.NewRows([]string{"notes", "month"}).AddRow("note", "2026-03"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT month FROM empty")).
+		WillReturnRows(sqlmock.NewRows([]string{"month"}))
+
+	row, err := RawOne[revenueRow]("SELECT 'note' AS notes, '2026-03' AS month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.Month != "2026-03" || row.Notes == nil || *row.Notes != "note" {
+		t.Errorf("RawOne = %+v", row)
+	}
+
+	if _, err := RawOne[revenueRow]("SELECT month FROM empty"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("RawOne with no rows: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestRawRejectsNonStruct(t *testing.T) {
+	withCountTestDB(t, "pgsql")
+	if _, err := Raw[int]("SELECT 1"); err == nil {
+		t.Error("Raw[int] should return an error")
+	}
+	if _, err := RawOne[string]("SELECT 'a'"); err == nil {
+		t.Error("RawOne[string] should return an error")
+	}
+}
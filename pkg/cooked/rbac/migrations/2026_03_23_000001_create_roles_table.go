@@ -9,7 +9,7 @@ type CreateRolesTable_2026_03_23_000001 struct {
 
 func (m *CreateRolesTable_2026_03_23_000001) Up() {
 	m.CreateTable("roles", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+		t.UUID("id").PrimaryKey().DefaultRaw("uuid_generate_v7()")
 		t.String("slug", 50).NotNull().Unique()
 		t.String("name", 100).NotNull()
 		t.Boolean("manages").NotNull().Default("false")
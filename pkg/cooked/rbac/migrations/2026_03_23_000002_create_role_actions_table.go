@@ -9,7 +9,7 @@ type CreateRoleActionsTable_2026_03_23_000002 struct {
 
 func (m *CreateRoleActionsTable_2026_03_23_000002) Up() {
 	m.CreateTable("role_actions", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+		t.UUID("id").PrimaryKey().DefaultRaw("uuid_generate_v7()")
 		t.String("role_slug", 50).NotNull().ForeignKey("roles", "slug")
 		t.String("action", 100).NotNull()
 		t.Timestamps()
@@ -26,11 +26,26 @@ func LoadRoles(ctx *Context, next func() Response) Response {
 	return next()
 }
 
-// RequireRole returns middleware that checks if the user has any of the given roles.
-// Returns 403 Forbidden if the user lacks all specified roles.
+// RequireRole returns middleware that lets the request through if the user
+// has any of the given roles. Roles loaded by LoadRoles are checked when it
+// ran; otherwise the Role set by the auth driver is, so a role claim in a
+// token works without RBAC tables. Returns 401 Unauthorized when no auth
+// middleware ran and 403 Forbidden when no role matches.
 func RequireRole(roles ...string) MiddlewareFunc {
 	return func(ctx *Context, next func() Response) Response {
-		if !ctx.HasAnyRole(roles...) {
+		if ctx.auth == nil {
+			return ctx.Unauthorized("authentication required")
+		}
+		allowed := ctx.HasAnyRole(roles...)
+		if ctx.roles == nil {
+			allowed = false
+			for _, role := range roles {
+				if role != "" && role == ctx.auth.Role {
+					allowed = true
+				}
+			}
+		}
+		if !allowed {
 			return ctx.Forbidden("insufficient role")
 		}
 		return next()
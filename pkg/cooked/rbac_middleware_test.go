@@ -8,6 +8,7 @@ import (
 
 func TestRequireRolePass(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "u1"})
 	ctx.SetRoles([]RoleInfo{{Slug: "admin", Manages: true}})
 
 	mw := RequireRole("admin", "editor")
@@ -22,6 +23,7 @@ func TestRequireRolePass(t *testing.T) {
 
 func TestRequireRoleFail(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "u1"})
 	ctx.SetRoles([]RoleInfo{{Slug: "viewer"}})
 
 	mw := RequireRole("admin", "editor")
@@ -36,6 +38,7 @@ func TestRequireRoleFail(t *testing.T) {
 
 func TestRequireRoleMultipleAllowed(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "u1"})
 	ctx.SetRoles([]RoleInfo{{Slug: "editor"}})
 
 	mw := RequireRole("admin", "editor")
@@ -50,7 +53,8 @@ func TestRequireRoleMultipleAllowed(t *testing.T) {
 
 func TestRequireRoleNoRoles(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
-	// No SetRoles called
+	ctx.SetAuth(&AuthInfo{UserID: "u1"})
+	// No SetRoles called, and no role from the auth driver
 
 	mw := RequireRole("admin")
 	resp := mw(ctx, func() Response {
@@ -62,6 +66,52 @@ func TestRequireRoleNoRoles(t *testing.T) {
 	}
 }
 
+func TestRequireRoleUnauthenticated(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	resp := RequireRole("admin")(ctx, func() Response {
+		t.Fatal("next should not run without auth")
+		return Response{}
+	})
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without auth, got %d", resp.StatusCode)
+	}
+}
+
+func TestRequireRoleUsesAuthRoleWithoutLoadRoles(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "u1", Role: "finance"})
+
+	resp := RequireRole("admin", "finance")(ctx, func() Response {
+		return ctx.JSON(200, "ok")
+	})
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200 for matching auth role, got %d", resp.StatusCode)
+	}
+
+	resp = RequireRole("admin")(ctx, func() Response {
+		return ctx.JSON(200, "ok")
+	})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for wrong auth role, got %d", resp.StatusCode)
+	}
+}
+
+func TestRequireRoleIgnoresAuthRoleAfterLoadRoles(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.SetAuth(&AuthInfo{UserID: "u1", Role: "admin"})
+	ctx.SetRoles([]RoleInfo{})
+
+	resp := RequireRole("admin")(ctx, func() Response {
+		return ctx.JSON(200, "ok")
+	})
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 when loaded roles don't match, got %d", resp.StatusCode)
+	}
+}
+
 func TestRequireAdminPass(t *testing.T) {
 	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
 	ctx.SetRoles([]RoleInfo{{Slug: "admin", Manages: true}})
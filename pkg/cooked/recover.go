@@ -0,0 +1,45 @@
+package cooked
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Recover turns a panic in the rest of the chain into a 500 Internal Server
+// Error response. The panic and its stack are logged through ctx.Logger(),
+// and the router's OnError callback is invoked as for any recovered panic.
+//
+// Because the panic becomes an ordinary Response, middleware outside Recover
+// still runs: CORS and request ID headers are applied and access logs see the
+// 500. Put it outermost, right after RequestID, so the log line carries the ID:
+//
+//	r.Group("/api", func(r *pickle.Router) {
+//	    r.Get("/posts", controllers.PostController{}.Index)
+//	}, pickle.RequestID, pickle.Recover())
+//
+// http.ErrAbortHandler is re-raised, so a handler can still abort the
+// response deliberately.
+func Recover() MiddlewareFunc {
+	return func(ctx *Context, next func() Response) (resp Response) {
+		defer func() {
+			raised := recover()
+			if raised == nil {
+				return
+			}
+			p, stack := panicValue(raised)
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			err, ok := p.(error)
+			if !ok {
+				err = fmt.Errorf("%v", p)
+			}
+			ctx.Logger().Error("panic recovered", "error", err, "method", ctx.request.Method, "path", ctx.request.URL.Path, "stack", string(stack))
+			if ctx.router != nil && ctx.router.onError != nil {
+				ctx.router.onError(ctx, err)
+			}
+			resp = ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		}()
+		return next()
+	}
+}
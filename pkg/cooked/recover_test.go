@@ -0,0 +1,83 @@
+package cooked
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecoverConvertsPanicToResponse(t *testing.T) {
+	buf := captureLogger(t)
+	var reported error
+	r := Routes(func(r *Router) {
+		r.Group("/api", func(r *Router) {
+			r.Get("/boom", func(ctx *Context) Response { panic("kaboom") })
+		}, RequestID, Recover())
+	})
+	r.OnError(func(ctx *Context, err error) { reported = err })
+
+	resp := r.Test().Get("/api/boom", nil, nil)
+	if resp.Status != http.StatusInternalServerError || !strings.Contains(string(resp.Body), "internal server error") {
+		t.Fatalf("status = %d, body = %s, want a 500", resp.Status, resp.Body)
+	}
+	if resp.Headers.Get("X-Request-ID") == "" {
+		t.Error("middleware outside Recover should still set X-Request-ID")
+	}
+	if reported == nil || reported.Error() != "kaboom" {
+		t.Errorf("OnError got %v, want kaboom", reported)
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "panic recovered" || line["error"] != "kaboom" || line["path"] != "/api/boom" || line["request_id"] == nil {
+		t.Errorf("log line = %v", line)
+	}
+	if stack, _ := line["stack"].(string); !strings.Contains(stack, "recover_test.go") {
+		t.Errorf("log line should carry the stack, got %q", stack)
+	}
+}
+
+func TestRecoverPassesThroughResponses(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	resp := Recover()(ctx, func() Response { return ctx.NoContent() })
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+}
+
+func TestRecoverReraisesAbortHandler(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	defer func() {
+		if p := recover(); p == nil || !errors.Is(p.(error), http.ErrAbortHandler) {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", p)
+		}
+	}()
+	Recover()(ctx, func() Response { panic(http.ErrAbortHandler) })
+}
+
+func TestRecoverLogsStackOfPanicUnderTimeout(t *testing.T) {
+	buf := captureLogger(t)
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	resp := Recover()(ctx, func() Response {
+		return Timeout(time.Second)(ctx, panicInHandler)
+	})
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	if line["error"] != "boom" {
+		t.Errorf("error = %v, want boom", line["error"])
+	}
+	if stack, _ := line["stack"].(string); !strings.Contains(stack, "panicInHandler") {
+		t.Errorf("stack should be where the panic happened, got %q", stack)
+	}
+}
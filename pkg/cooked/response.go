@@ -1,9 +1,10 @@
 package cooked
 
 import (
-	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 )
 
 // Response represents an HTTP response to be written.
@@ -12,6 +13,9 @@ type Response struct {
 	Body       any
 	Headers    map[string]string
 	Cookies    []*http.Cookie
+
+	// compression is set by the Gzip middleware for clients that accept it.
+	compression *responseCompression
 }
 
 // renderedView is intentionally package-private. Only generated renderers in
@@ -33,12 +37,16 @@ func renderedAssetResponse(body []byte, headers map[string]string) Response {
 	return Response{StatusCode: http.StatusOK, Body: renderedAsset(body), Headers: headers}
 }
 
-// Header returns a copy of the response with an additional header set.
+// Header returns a copy of the response with an additional header set. The
+// headers map is copied so middleware adding headers after next() never
+// mutates a map the handler shares with other responses.
 func (r Response) Header(key, value string) Response {
-	if r.Headers == nil {
-		r.Headers = make(map[string]string)
+	headers := make(map[string]string, len(r.Headers)+1)
+	for k, v := range r.Headers {
+		headers[k] = v
 	}
-	r.Headers[key] = value
+	headers[key] = value
+	r.Headers = headers
 	return r
 }
 
@@ -48,7 +56,15 @@ func (r Response) WithCookie(c *http.Cookie) Response {
 	return r
 }
 
-// Write serializes the response to an http.ResponseWriter.
+// internalErrorBody is written in place of a body that fails to encode.
+const internalErrorBody = `{"error":"internal server error"}`
+
+// Write serializes the response to an http.ResponseWriter. The body is
+// encoded as XML when the Content-Type header is an XML type (see
+// Context.XML), and as JSON with JSONEncoder otherwise. It is encoded in full before anything
+// is sent, so Content-Length is always accurate and an encoding failure
+// becomes a generic 500 rather than a truncated response. A nil Body is sent
+// as an empty body, with 204 as the default status.
 func (r Response) Write(w http.ResponseWriter) {
 	for _, c := range r.Cookies {
 		http.SetCookie(w, c)
@@ -61,6 +77,11 @@ func (r Response) Write(w http.ResponseWriter) {
 		if r.StatusCode == 0 {
 			r.StatusCode = http.StatusNoContent
 		}
+		if bodyAllowed(r.StatusCode) {
+			w.Header().Set("Content-Length", "0")
+		} else {
+			w.Header().Del("Content-Length")
+		}
 		w.WriteHeader(r.StatusCode)
 		return
 	}
@@ -69,35 +90,48 @@ func (r Response) Write(w http.ResponseWriter) {
 		r.StatusCode = http.StatusOK
 	}
 
-	if body, ok := r.Body.(renderedView); ok {
-		w.WriteHeader(r.StatusCode)
-		if _, err := w.Write([]byte(body)); err != nil {
-			log.Printf("pickle: failed to write response: %v", err)
+	var data []byte
+	switch body := r.Body.(type) {
+	case renderedView:
+		data = []byte(body)
+	case renderedAsset:
+		data = body
+	default:
+		var err error
+		if isXMLContentType(w.Header().Get("Content-Type")) {
+			data, err = encodeXML(r.Body)
+		} else {
+			data, err = JSONEncoder(r.Body)
 		}
-		return
-	}
-	if body, ok := r.Body.(renderedAsset); ok {
-		w.WriteHeader(r.StatusCode)
-		if _, err := w.Write(body); err != nil {
-			log.Printf("pickle: failed to write asset response: %v", err)
+		if err != nil {
+			log.Printf("pickle: failed to encode %T response body: %v", r.Body, err)
+			h := w.Header()
+			h.Del("Content-Encoding")
+			h.Set("Content-Type", "application/json")
+			h.Set("Content-Length", strconv.Itoa(len(internalErrorBody)))
+			w.WriteHeader(http.StatusInternalServerError)
+			if _, writeErr := io.WriteString(w, internalErrorBody); writeErr != nil {
+				log.Printf("pickle: failed to write error response: %v", writeErr)
+			}
+			return
 		}
-		return
-	}
-
-	data, err := json.Marshal(r.Body)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		if _, writeErr := w.Write([]byte(`{"error":"internal server error"}`)); writeErr != nil {
-			log.Printf("pickle: failed to write error response: %v", writeErr)
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
 		}
-		return
 	}
 
-	if w.Header().Get("Content-Type") == "" {
-		w.Header().Set("Content-Type", "application/json")
+	if r.compression != nil {
+		data = r.compression.apply(w.Header(), data)
 	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
 	w.WriteHeader(r.StatusCode)
 	if _, err := w.Write(data); err != nil {
 		log.Printf("pickle: failed to write response: %v", err)
 	}
 }
+
+// bodyAllowed reports whether a response with the given status may carry a
+// body, and with it a Content-Length header.
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
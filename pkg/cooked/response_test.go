@@ -2,6 +2,7 @@ package cooked
 
 import (
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -34,6 +35,52 @@ func TestResponseWriteNoBody(t *testing.T) {
 	}
 }
 
+func TestResponseWriteBodyShapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		resp        Response
+		status      int
+		body        string
+		contentType string
+	}{
+		{"json", Response{StatusCode: 201, Body: map[string]int{"id": 1}}, 201, `{"id":1}`, "application/json"},
+		{"json default status", Response{Body: []string{"a"}}, 200, `["a"]`, "application/json"},
+		{"view", renderedViewResponse(nil, "<p>hi</p>"), 200, "<p>hi</p>", "text/html; charset=utf-8"},
+		{"asset", renderedAssetResponse([]byte("body{}"), map[string]string{"Content-Type": "text/css"}), 200, "body{}", "text/css"},
+		{"nil body with status", Response{StatusCode: 202}, 202, "", ""},
+		{"unencodable", Response{StatusCode: 200, Body: map[string]any{"ch": make(chan int)}, Headers: map[string]string{"Content-Type": "text/plain"}}, 500, `{"error":"internal server error"}`, "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.resp.Write(w)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+			if got := w.Header().Get("Content-Length"); got != strconv.Itoa(len(tt.body)) {
+				t.Errorf("Content-Length = %q, want %d", got, len(tt.body))
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestResponseWriteNoContentOmitsLength(t *testing.T) {
+	w := httptest.NewRecorder()
+	Response{}.Write(w)
+	if w.Code != 204 || w.Body.Len() != 0 {
+		t.Errorf("status = %d, body %q, want an empty 204", w.Code, w.Body.String())
+	}
+	if _, ok := w.Header()["Content-Length"]; ok {
+		t.Error("204 response should not carry Content-Length")
+	}
+}
+
 func TestResponseHeader(t *testing.T) {
 	r := Response{StatusCode: 200}
 	r2 := r.Header("X-Foo", "bar")
@@ -6,9 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
-	"runtime/debug"
 	"strings"
-	"time"
 )
 
 var authenticateHTTPPolicy func(*http.Request) (any, *AuthInfo, error)
@@ -32,6 +30,11 @@ type Route struct {
 	Path       string
 	Handler    HandlerFunc
 	Middleware []MiddlewareFunc
+
+	// middlewareSources identifies where each Middleware entry was
+	// declared, so routes sharing a group's middleware can be told apart
+	// from routes that declare their own.
+	middlewareSources []*MiddlewareFunc
 }
 
 // Name assigns a stable application name to a route.
@@ -101,6 +104,13 @@ type Router struct {
 	routes     []Route
 	groups     []*Router
 	onError    ErrorReporter
+
+	// manualMethods stops RegisterRoutes from adding HEAD and OPTIONS.
+	manualMethods bool
+
+	// middlewareNames lists the named middleware this router's routes and
+	// groups refer to, checked against the registry by RegisterRoutes.
+	middlewareNames []string
 }
 
 // OnError registers a callback that is invoked for panics recovered during
@@ -109,6 +119,14 @@ func (r *Router) OnError(fn ErrorReporter) {
 	r.onError = fn
 }
 
+// DisableAutoMethods stops RegisterRoutes from registering a HEAD handler for
+// every GET route and an OPTIONS handler for every path. Use it when the
+// application answers HEAD and OPTIONS itself, e.g. in a wrapping handler.
+// Note that a ServeMux still routes HEAD requests to GET patterns.
+func (r *Router) DisableAutoMethods() {
+	r.manualMethods = true
+}
+
 // OnRateLimit registers a callback that is invoked on every rate limit check
 // (both IP and auth layers). Use this for metrics and alerting.
 func (r *Router) OnRateLimit(fn func(ctx *Context, event RateLimitEvent)) {
@@ -130,13 +148,25 @@ func (r *Router) addRoute(method, path string, handler HandlerFunc, mw []any) *R
 		Method:     method,
 		Path:       path,
 		Handler:    handler,
-		Middleware: resolveMiddleware(mw),
+		Middleware: r.resolveMiddleware(mw),
 	})
 	return &r.routes[len(r.routes)-1]
 }
 
-// resolveMiddleware converts a slice of any (MiddlewareFunc or MiddlewareProvider)
-// into a slice of MiddlewareFunc. This runs at route registration time, not per-request.
+// resolveMiddleware resolves middleware like the package-level function and
+// records the names of registered middleware the router refers to.
+func (r *Router) resolveMiddleware(mw []any) []MiddlewareFunc {
+	for _, m := range mw {
+		if name, ok := m.(string); ok {
+			r.middlewareNames = append(r.middlewareNames, name)
+		}
+	}
+	return resolveMiddleware(mw)
+}
+
+// resolveMiddleware converts a slice of any (MiddlewareFunc, MiddlewareProvider
+// or the name of registered middleware) into a slice of MiddlewareFunc. This
+// runs at route registration time, not per-request.
 func resolveMiddleware(mw []any) []MiddlewareFunc {
 	resolved := make([]MiddlewareFunc, 0, len(mw))
 	for _, m := range mw {
@@ -147,8 +177,10 @@ func resolveMiddleware(mw []any) []MiddlewareFunc {
 			resolved = append(resolved, MiddlewareFunc(v))
 		case MiddlewareProvider:
 			resolved = append(resolved, v.Middleware())
+		case string:
+			resolved = append(resolved, namedMiddleware.byName(v))
 		default:
-			panic(fmt.Sprintf("pickle: invalid middleware type %T — must be MiddlewareFunc or MiddlewareProvider", m))
+			panic(fmt.Sprintf("pickle: invalid middleware type %T — must be MiddlewareFunc, MiddlewareProvider or a registered name", m))
 		}
 	}
 	return resolved
@@ -181,7 +213,7 @@ func (r *Router) Delete(path string, handler HandlerFunc, mw ...any) *Route {
 
 // Group creates a sub-router with a shared prefix and optional middleware.
 func (r *Router) Group(prefix string, body func(*Router), mw ...any) *RouteGroup {
-	g := &Router{prefix: prefix, middleware: resolveMiddleware(mw)}
+	g := &Router{prefix: prefix, middleware: r.resolveMiddleware(mw)}
 	body(g)
 	r.groups = append(r.groups, g)
 	return &RouteGroup{router: g}
@@ -209,32 +241,54 @@ func (r *Router) Resource(prefix string, c ResourceController, mw ...any) *Resou
 // AllRoutes returns a flattened list of all routes with prefixes and
 // middleware fully resolved.
 func (r *Router) AllRoutes() []Route {
-	return r.collectRoutes("", nil)
+	return r.collectRoutes("", nil, nil)
 }
 
-func (r *Router) collectRoutes(parentPrefix string, parentMW []MiddlewareFunc) []Route {
+func (r *Router) collectRoutes(parentPrefix string, parentMW []MiddlewareFunc, parentSources []*MiddlewareFunc) []Route {
 	fullPrefix := parentPrefix + r.prefix
 	combinedMW := append(append([]MiddlewareFunc{}, parentMW...), r.middleware...)
+	combinedSources := append([]*MiddlewareFunc{}, parentSources...)
+	for i := range r.middleware {
+		combinedSources = append(combinedSources, &r.middleware[i])
+	}
 
 	var routes []Route
-	for _, route := range r.routes {
+	for i := range r.routes {
+		route := &r.routes[i]
 		resolved := Route{
-			NameValue:  route.NameValue,
-			Method:     route.Method,
-			Path:       fullPrefix + route.Path,
-			Handler:    route.Handler,
-			Middleware: append(append([]MiddlewareFunc{}, combinedMW...), route.Middleware...),
+			NameValue:         route.NameValue,
+			Method:            route.Method,
+			Path:              fullPrefix + route.Path,
+			Handler:           route.Handler,
+			Middleware:        append(append([]MiddlewareFunc{}, combinedMW...), route.Middleware...),
+			middlewareSources: append([]*MiddlewareFunc{}, combinedSources...),
+		}
+		for j := range route.Middleware {
+			resolved.middlewareSources = append(resolved.middlewareSources, &route.Middleware[j])
 		}
 		routes = append(routes, resolved)
 	}
 
 	for _, g := range r.groups {
-		routes = append(routes, g.collectRoutes(fullPrefix, combinedMW)...)
+		routes = append(routes, g.collectRoutes(fullPrefix, combinedMW, combinedSources)...)
 	}
 
 	return routes
 }
 
+// checkMiddlewareNames panics if a route or group names middleware that was
+// never registered, so a typo fails at startup rather than on first request.
+func (r *Router) checkMiddlewareNames() {
+	for _, name := range r.middlewareNames {
+		if _, ok := namedMiddleware.Lookup(name); !ok {
+			panic(fmt.Sprintf("pickle: middleware %q is not registered", name))
+		}
+	}
+	for _, g := range r.groups {
+		g.checkMiddlewareNames()
+	}
+}
+
 func (r *Router) namedRoutes() map[string]Route {
 	named := map[string]Route{}
 	for _, route := range r.AllRoutes() {
@@ -277,114 +331,249 @@ var paramPattern = regexp.MustCompile(`:(\w+)`)
 
 // RegisterRoutes wires all routes onto the given ServeMux.
 // Also registers Pickle's internal operations endpoints (/pickle/*).
+// Each route is registered once, without a trailing slash. A request for
+// "/users/" is trimmed and dispatched again to the "/users" route, with its
+// headers intact; serving the mux through CanonicalPaths trims it before the
+// first lookup instead. Unless DisableAutoMethods was called, every GET route
+// also answers HEAD and every path answers OPTIONS with an Allow header.
 func (r *Router) RegisterRoutes(mux *http.ServeMux) {
 	// Register Pickle's internal operations endpoints
 	RegisterPickleEndpoints(mux)
 
 	_ = r.namedRoutes()
+	r.checkMiddlewareNames()
 	registered := map[string]bool{}
-	for _, route := range r.AllRoutes() {
-		route := route // capture
+	// The slash variant of each pattern ("GET /users/{$}") sends the request
+	// back through the mux trimmed. It is registered per method because a
+	// ServeMux redirects "/users" to "/users/" when only the latter matches.
+	trimSlash := CanonicalPaths(mux)
+	register := func(method, goPath string, handler http.HandlerFunc) {
+		pattern := method + " " + goPath
+		if registered[pattern] {
+			panic("pickle: duplicate route registered: " + pattern)
+		}
+		registered[pattern] = true
+		mux.HandleFunc(pattern, handler)
+		if goPath != "/{$}" && !strings.HasSuffix(goPath, "...}") {
+			mux.Handle(pattern+"/{$}", trimSlash)
+		}
+	}
 
+	// Paths in registration order, with the routes served at each, so HEAD
+	// and OPTIONS can be answered for paths that don't declare them.
+	var paths []string
+	routesByPath := map[string][]Route{}
+	for _, route := range r.AllRoutes() {
 		// Convert :param to Go 1.22+ {param}
-		goPath := paramPattern.ReplaceAllString(route.Path, "{${1}}")
+		goPath := canonicalPath(paramPattern.ReplaceAllString(route.Path, "{${1}}"))
+		if goPath == "/" {
+			// A bare "/" pattern matches every path, so the root route would
+			// answer (or 405) requests that should 404.
+			goPath = "/{$}"
+		}
+		if _, ok := routesByPath[goPath]; !ok {
+			paths = append(paths, goPath)
+		}
+		routesByPath[goPath] = append(routesByPath[goPath], route)
+		register(route.Method, goPath, r.routeHandler(route))
+	}
+
+	if r.manualMethods {
+		return
+	}
 
-		// Extract param names
-		var params []string
-		for _, match := range paramPattern.FindAllStringSubmatch(route.Path, -1) {
-			params = append(params, match[1])
+	for _, goPath := range paths {
+		routes := routesByPath[goPath]
+		methods := make([]string, 0, len(routes)+2)
+		for _, route := range routes {
+			methods = append(methods, route.Method)
 		}
 
-		pattern := route.Method + " " + goPath
+		// HEAD runs the GET handler, middleware included, so status and
+		// headers match; the body is discarded.
+		for _, route := range routes {
+			if route.Method != "GET" || registered["HEAD "+goPath] {
+				continue
+			}
+			get := r.routeHandler(route)
+			register("HEAD", goPath, func(w http.ResponseWriter, req *http.Request) {
+				get(headResponseWriter{w}, req)
+			})
+			methods = append(methods, "HEAD")
+		}
 
-		onError := r.onError
-		handler := func(w http.ResponseWriter, req *http.Request) {
-			// Framework-level rate limiting — runs before everything else.
-			resp, ipRLHeaders := checkRateLimit(req)
-			if resp != nil {
-				resp.Write(w)
+		// Preflight requests use OPTIONS, so without a route the CORS
+		// middleware would never see them. Answer with a 204 that advertises
+		// the allowed methods. A preflight runs the middleware of the route
+		// it asks about, so that route's CORS short-circuits before the 204;
+		// any other OPTIONS request runs only the middleware every route on
+		// the path shares, so one route's Auth doesn't guard the others.
+		if registered["OPTIONS "+goPath] {
+			continue
+		}
+		methods = append(methods, "OPTIONS")
+		allow := strings.Join(methods, ", ")
+		optionsRoute := func(mw []MiddlewareFunc) http.HandlerFunc {
+			return r.routeHandler(Route{
+				Method:     "OPTIONS",
+				Path:       routes[0].Path,
+				Middleware: mw,
+				Handler: func(ctx *Context) Response {
+					return Response{StatusCode: http.StatusNoContent, Headers: map[string]string{"Allow": allow}}
+				},
+			})
+		}
+		preflights := map[string]http.HandlerFunc{}
+		for _, route := range routes {
+			if preflights[route.Method] == nil {
+				preflights[route.Method] = optionsRoute(route.Middleware)
+			}
+		}
+		options := optionsRoute(sharedMiddleware(routes))
+		register("OPTIONS", goPath, func(w http.ResponseWriter, req *http.Request) {
+			if preflight := preflights[req.Header.Get("Access-Control-Request-Method")]; preflight != nil {
+				preflight(w, req)
 				return
 			}
+			options(w, req)
+		})
+	}
+}
 
-			ctx := NewContext(w, req)
-			ctx.router = r
-			ctx.routeName = route.NameValue
-			if authenticateHTTPPolicy != nil {
-				policyContext, authInfo, err := authenticateHTTPPolicy(req)
-				if err != nil {
-					ctx.Unauthorized("invalid credentials").Write(w)
-					return
-				}
-				ctx.SetPolicyContext(policyContext)
-				if authInfo != nil {
-					ctx.SetAuth(authInfo)
-				}
-			}
+// sharedMiddleware returns the leading middleware that every route in routes
+// got from the same declaration, such as the groups they all belong to.
+func sharedMiddleware(routes []Route) []MiddlewareFunc {
+	first := routes[0]
+	n := len(first.middlewareSources)
+	for _, route := range routes[1:] {
+		i := 0
+		for i < n && i < len(route.middlewareSources) && route.middlewareSources[i] == first.middlewareSources[i] {
+			i++
+		}
+		n = i
+	}
+	return first.Middleware[:n]
+}
 
-			defer func() {
-				if rv := recover(); rv != nil {
-					err, ok := rv.(error)
-					if !ok {
-						err = fmt.Errorf("%v", rv)
-					}
-					log.Printf("panic: %v\n%s", err, debug.Stack())
-					if onError != nil {
-						onError(ctx, err)
-					}
-					resp := Response{
-						StatusCode: http.StatusInternalServerError,
-						Body:       map[string]string{"error": "internal server error"},
-						Headers:    map[string]string{"Content-Type": "application/json"},
-					}
-					resp.Write(w)
-				}
-			}()
+// headResponseWriter answers a HEAD request with the GET handler's status and
+// headers, dropping the body.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
 
-			for _, name := range params {
-				ctx.SetParam(name, req.PathValue(name))
-			}
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
 
-			var mw []MiddlewareFunc
-			if len(route.Middleware) > 0 {
-				mw = route.Middleware
+// CanonicalPaths strips trailing slashes from the request path before next
+// matches it, so "/users/" is served by the "/users" route in place. A
+// ServeMux would otherwise 404 it, or answer with a 301 that clients follow
+// without the Authorization header. Paths without a trailing slash pass
+// through untouched.
+func CanonicalPaths(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		p := req.URL.Path
+		if len(p) <= 1 || p[len(p)-1] != '/' {
+			next.ServeHTTP(w, req)
+			return
+		}
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.URL = new(url.URL)
+		*r2.URL = *req.URL
+		r2.URL.Path = canonicalPath(p)
+		if req.URL.RawPath != "" {
+			r2.URL.RawPath = canonicalPath(req.URL.RawPath)
+		}
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// canonicalPath trims trailing slashes, keeping the root path "/".
+func canonicalPath(p string) string {
+	trimmed := strings.TrimRight(p, "/")
+	if trimmed == "" {
+		return "/"
+	}
+	return trimmed
+}
+
+// routeHandler builds the http.HandlerFunc that serves a single route:
+// framework rate limiting, auth bridge, panic recovery, params, middleware.
+func (r *Router) routeHandler(route Route) http.HandlerFunc {
+	// Extract param names
+	var params []string
+	for _, match := range paramPattern.FindAllStringSubmatch(route.Path, -1) {
+		params = append(params, match[1])
+	}
+
+	onError := r.onError
+	return func(w http.ResponseWriter, req *http.Request) {
+		// Framework-level rate limiting — runs before everything else.
+		resp, ipRLHeaders := checkRateLimit(req)
+		if resp != nil {
+			resp.Write(w)
+			return
+		}
+
+		ctx := NewContext(w, req)
+		ctx.router = r
+		ctx.routeName = route.NameValue
+		if authenticateHTTPPolicy != nil {
+			policyContext, authInfo, err := authenticateHTTPPolicy(req)
+			if err != nil {
+				ctx.Unauthorized("invalid credentials").Write(w)
+				return
+			}
+			ctx.SetPolicyContext(policyContext)
+			if authInfo != nil {
+				ctx.SetAuth(authInfo)
 			}
+		}
 
-			result := RunMiddleware(ctx, mw, func() Response {
-				return route.Handler(ctx)
-			})
-			// Attach IP-layer rate limit headers to the response.
-			for k, v := range ipRLHeaders {
-				if result.Headers == nil {
-					result.Headers = make(map[string]string)
+		defer func() {
+			if raised := recover(); raised != nil {
+				rv, stack := panicValue(raised)
+				err, ok := rv.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rv)
+				}
+				log.Printf("panic: %v\n%s", err, stack)
+				if onError != nil {
+					onError(ctx, err)
 				}
-				result.Headers[k] = v
+				resp := Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       map[string]string{"error": "internal server error"},
+					Headers:    map[string]string{"Content-Type": "application/json"},
+				}
+				resp.Write(w)
 			}
-			result.Write(w)
+		}()
+
+		for _, name := range params {
+			ctx.SetParam(name, req.PathValue(name))
 		}
 
-		if registered[pattern] {
-			panic("pickle: duplicate route registered: " + pattern)
+		var mw []MiddlewareFunc
+		if len(route.Middleware) > 0 {
+			mw = route.Middleware
 		}
-		registered[pattern] = true
-		mux.HandleFunc(pattern, handler)
 
-		// Register the opposite slash variant to prevent ServeMux 301 redirects
-		// that strip headers (e.g. Authorization). Skip paths ending in path params.
-		if !strings.HasSuffix(goPath, "}") {
-			var alt string
-			if strings.HasSuffix(goPath, "/") {
-				trimmed := strings.TrimRight(goPath, "/")
-				if trimmed != "" {
-					alt = route.Method + " " + trimmed
-				}
-			} else {
-				alt = route.Method + " " + goPath + "/"
-			}
-			if alt != "" && !registered[alt] {
-				registered[alt] = true
-				mux.HandleFunc(alt, handler)
+		result := RunMiddleware(ctx, mw, func() Response {
+			return route.Handler(ctx)
+		})
+		// Headers and cookies buffered on the context apply whichever
+		// handler or middleware produced the response.
+		result = ctx.applyResponseState(result)
+		// Attach IP-layer rate limit headers to the response.
+		for k, v := range ipRLHeaders {
+			if result.Headers == nil {
+				result.Headers = make(map[string]string)
 			}
+			result.Headers[k] = v
 		}
+		result.Write(w)
 	}
 }
 
@@ -393,12 +582,25 @@ func (r *Router) ListenAndServe(addr string) error {
 	mux := http.NewServeMux()
 	r.RegisterRoutes(mux)
 	srv := &http.Server{
-		Addr:              addr,
-		Handler:           mux,
-		ReadHeaderTimeout: 10 * time.Second,
-		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      60 * time.Second,
-		IdleTimeout:       120 * time.Second,
+		Addr:    addr,
+		Handler: CanonicalPaths(mux),
 	}
+	applyServerDefaults(srv)
 	return srv.ListenAndServe()
 }
+
+// ListenAndServeGraceful serves the router on addr with the default timeouts
+// and drains in-flight requests on SIGINT or SIGTERM. See ServeGraceful.
+func (r *Router) ListenAndServeGraceful(addr string) error {
+	return r.Serve(&http.Server{Addr: addr})
+}
+
+// Serve registers the router's routes as srv's handler and serves until
+// SIGINT or SIGTERM, then shuts down gracefully. Other fields of srv (TLS,
+// timeouts, logging) are kept; zero timeouts get the defaults.
+func (r *Router) Serve(srv *http.Server) error {
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+	srv.Handler = CanonicalPaths(mux)
+	return ServeGraceful(srv)
+}
@@ -1,6 +1,10 @@
 package cooked
 
-import "testing"
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
 
 func TestRouterAcceptsDeclaredMiddlewareFunction(t *testing.T) {
 	declared := func(_ *Context, next func() Response) Response { return next() }
@@ -11,3 +15,107 @@ func TestRouterAcceptsDeclaredMiddlewareFunction(t *testing.T) {
 		t.Fatalf("middleware count = %d", got)
 	}
 }
+
+func TestContextResponseStateSurvivesMiddleware(t *testing.T) {
+	stamp := func(ctx *Context, next func() Response) Response {
+		ctx.SetResponseHeader("x-request-tag", "mw")
+		resp := next()
+		ctx.SetCookie(&http.Cookie{Name: "after", Value: "1"})
+		return resp
+	}
+	block := func(ctx *Context, next func() Response) Response {
+		if ctx.Query("block") != "" {
+			return ctx.Forbidden("blocked")
+		}
+		return next()
+	}
+	client := Routes(func(r *Router) {
+		r.Get("/items", func(ctx *Context) Response {
+			ctx.SetCookie(&http.Cookie{Name: "seen", Value: "yes"})
+			ctx.SetResponseHeader("Cache-Control", "no-store")
+			return ctx.JSON(http.StatusOK, map[string]string{"ok": "1"}).Header("X-Request-Tag", "handler")
+		}, stamp, block)
+	}).Test()
+
+	resp := client.Get("/items", nil, nil)
+	if got := resp.Headers.Get("X-Request-Tag"); got != "handler" {
+		t.Errorf("X-Request-Tag = %q, want the response's own header to win", got)
+	}
+	if got := resp.Headers.Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	cookies := strings.Join(resp.Headers.Values("Set-Cookie"), "; ")
+	if !strings.Contains(cookies, "seen=yes") || !strings.Contains(cookies, "after=1") {
+		t.Errorf("Set-Cookie = %q, want cookies from handler and middleware", cookies)
+	}
+
+	// A middleware that short-circuits still carries what earlier middleware set.
+	resp = client.Get("/items?block=1", nil, nil)
+	if resp.Status != http.StatusForbidden || resp.Headers.Get("X-Request-Tag") != "mw" {
+		t.Errorf("blocked: status %d, X-Request-Tag %q", resp.Status, resp.Headers.Get("X-Request-Tag"))
+	}
+	if strings.Contains(strings.Join(resp.Headers.Values("Set-Cookie"), "; "), "seen=yes") {
+		t.Error("blocked request should not carry the handler's cookie")
+	}
+}
+
+func TestRouterResolvesNamedMiddleware(t *testing.T) {
+	defer func(prev *MiddlewareStack) { namedMiddleware = prev }(namedMiddleware)
+	namedMiddleware = NewMiddlewareStack()
+
+	var order []string
+	tag := func(name string) MiddlewareFunc {
+		return func(_ *Context, next func() Response) Response {
+			order = append(order, name)
+			return next()
+		}
+	}
+	router := Routes(func(r *Router) {
+		r.Group("/api", func(r *Router) {
+			r.Get("/items", func(*Context) Response { return Response{StatusCode: 204} }, "throttle")
+		}, "api")
+	})
+
+	// Registered after the routes are built, as an init function would.
+	RegisterMiddleware("auth", tag("auth"))
+	RegisterMiddleware("throttle", tag("throttle"))
+	RegisterMiddleware("api", tag("cors"), "auth")
+
+	resp := router.Test().Get("/api/items", nil, nil)
+	if resp.Status != 204 {
+		t.Fatalf("status = %d, want 204", resp.Status)
+	}
+	if got := strings.Join(order, ","); got != "cors,auth,throttle" {
+		t.Errorf("middleware order = %s, want cors,auth,throttle", got)
+	}
+}
+
+func TestRegisterRoutesPanicsOnUnregisteredMiddleware(t *testing.T) {
+	defer func(prev *MiddlewareStack) { namedMiddleware = prev }(namedMiddleware)
+	namedMiddleware = NewMiddlewareStack()
+
+	router := Routes(func(r *Router) {
+		r.Group("/api", func(r *Router) {
+			r.Get("/items", func(*Context) Response { return Response{StatusCode: 204} })
+		}, "auht")
+	})
+	defer func() {
+		if msg, _ := recover().(string); !strings.Contains(msg, `"auht" is not registered`) {
+			t.Errorf("panic = %q, want unregistered middleware", msg)
+		}
+	}()
+	router.RegisterRoutes(http.NewServeMux())
+}
+
+func TestMiddlewareStackRejectsDuplicateNames(t *testing.T) {
+	stack := NewMiddlewareStack().Register("auth", func(_ *Context, next func() Response) Response { return next() })
+	if names := stack.Names(); len(names) != 1 || names[0] != "auth" {
+		t.Fatalf("Names() = %v", names)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a duplicate name")
+		}
+	}()
+	stack.Register("auth", func(_ *Context, next func() Response) Response { return next() })
+}
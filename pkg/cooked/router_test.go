@@ -143,3 +143,121 @@ func TestRouteGroupPathAndNamePrefixes(t *testing.T) {
 		t.Fatalf("admin.reports.daily = %q", got)
 	}
 }
+
+func TestRegisterRoutesAutoHeadAndOptions(t *testing.T) {
+	t.Setenv("RATE_LIMIT", "false")
+	r := Routes(func(r *Router) {
+		r.Get("/posts", func(ctx *Context) Response {
+			return Response{StatusCode: http.StatusOK, Body: "hello", Headers: map[string]string{"X-Total": "3"}}
+		})
+		r.Post("/posts", noop)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("HEAD", "/posts", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("HEAD status = %d, want 200", w.Code)
+	}
+	if w.Header().Get("X-Total") != "3" {
+		t.Errorf("HEAD X-Total = %q, want the GET handler's header", w.Header().Get("X-Total"))
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("HEAD body = %q, want empty", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/posts", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("OPTIONS status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "GET, POST, HEAD, OPTIONS" {
+		t.Errorf("Allow = %q, want GET, POST, HEAD, OPTIONS", got)
+	}
+}
+
+func TestRegisterRoutesRootMatchesOnlyRoot(t *testing.T) {
+	t.Setenv("RATE_LIMIT", "false")
+	r := Routes(func(r *Router) {
+		r.Get("/", noop)
+		r.Get("/posts", noop)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	for _, tt := range []struct {
+		method, path string
+		want         int
+	}{
+		{"OPTIONS", "/", http.StatusNoContent},
+		{"HEAD", "/", http.StatusNoContent},
+		{"OPTIONS", "/nope", http.StatusNotFound},
+		{"HEAD", "/nope", http.StatusNotFound},
+		{"GET", "/nope", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRegisterRoutesAutoOptionsMixedMiddleware(t *testing.T) {
+	t.Setenv("RATE_LIMIT", "false")
+	requireAuth := MiddlewareFunc(func(ctx *Context, next func() Response) Response {
+		return Response{StatusCode: http.StatusUnauthorized}
+	})
+	var groupRuns int
+	counted := MiddlewareFunc(func(ctx *Context, next func() Response) Response {
+		groupRuns++
+		return next()
+	})
+	cors := CORS(CORSOptions{AllowOrigins: []string{"https://app.example.com"}, AllowMethods: []string{"POST"}})
+	r := Routes(func(r *Router) {
+		r.Group("/api", func(r *Router) {
+			r.Post("/posts", noop, cors, requireAuth)
+			r.Get("/posts", noop)
+		}, counted)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	// A plain OPTIONS runs only the group's middleware, not the POST
+	// route's Auth.
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/api/posts", nil))
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "POST, GET, HEAD, OPTIONS" {
+		t.Fatalf("OPTIONS = %d Allow %q, want 204 with every method", w.Code, w.Header().Get("Allow"))
+	}
+	if groupRuns != 1 {
+		t.Errorf("group middleware ran %d times, want 1", groupRuns)
+	}
+
+	// A preflight for POST is answered by the POST route's CORS.
+	req := httptest.NewRequest("OPTIONS", "/api/posts", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") != "POST" {
+		t.Errorf("preflight = %d Allow-Methods %q, want 204 from the POST route's CORS", w.Code, w.Header().Get("Access-Control-Allow-Methods"))
+	}
+}
+
+func TestRegisterRoutesDisableAutoMethods(t *testing.T) {
+	t.Setenv("RATE_LIMIT", "false")
+	r := Routes(func(r *Router) {
+		r.DisableAutoMethods()
+		r.Post("/posts", noop)
+	})
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/posts", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("OPTIONS status = %d, want 405 with auto methods disabled", w.Code)
+	}
+}
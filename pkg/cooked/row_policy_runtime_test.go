@@ -32,6 +32,14 @@ type policyTestMessage struct {
 	WorkspaceID string `db:"workspace_id"`
 }
 
+// policyTestThread is a message with the other messages of its workspace,
+// which EagerLoad must read under the same row policy.
+type policyTestThread struct {
+	ID                string              `db:"id"`
+	WorkspaceID       string              `db:"workspace_id"`
+	WorkspaceMessages []policyTestMessage `rel:"workspace_messages"`
+}
+
 type nullablePolicyMessage struct {
 	WorkspaceID *string `db:"workspace_id"`
 }
@@ -56,7 +64,7 @@ func TestProtectedReadAddsPolicyPredicate(t *testing.T) {
 	DB = db
 	t.Cleanup(func() { DB = oldDB })
 	ctx := NewVerifiedPolicyContext(map[string]string{"workspace_id": "workspace-1"}, []string{"member"})
-	query := `SELECT id, workspace_id FROM messages WHERE ((COALESCE(("workspace_id" = $1), FALSE)))`
+	query := `SELECT "id", "workspace_id" FROM "messages" WHERE ((COALESCE(("workspace_id" = $1), FALSE)))`
 	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1"))
 	rows, err := Query[policyTestMessage]("messages").WithPolicyContext(ctx).All()
 	if err != nil {
@@ -82,21 +90,26 @@ func TestProtectedReadTerminalMatrixAddsPolicyPredicate(t *testing.T) {
 	t.Cleanup(func() { DB = oldDB })
 	ctx := NewVerifiedPolicyContext(map[string]string{"workspace_id": "workspace-1"}, []string{"member"})
 	clause := `WHERE ((COALESCE(("workspace_id" = $1), FALSE)))`
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, workspace_id FROM messages ` + clause + ` LIMIT 1`)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "workspace_id" FROM "messages" ` + clause + ` LIMIT 1`)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1"))
 	if _, err := Query[policyTestMessage]("messages").WithPolicyContext(ctx).First(); err != nil {
 		t.Fatal(err)
 	}
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM messages ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "messages" ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 	if count, err := Query[policyTestMessage]("messages").WithPolicyContext(ctx).Count(); err != nil || count != 1 {
 		t.Fatalf("count=%d err=%v", count, err)
 	}
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT SUM(workspace_id) FROM messages ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1.0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT SUM("workspace_id") FROM "messages" ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1.0))
 	if value, err := Query[policyTestMessage]("messages").WithPolicyContext(ctx).aggregate("SUM", "workspace_id"); err != nil || value == nil || *value != 1 {
 		t.Fatalf("aggregate=%v err=%v", value, err)
 	}
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, workspace_id FROM messages ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1"))
-	if rows, err := Query[policyTestMessage]("messages").WithPolicyContext(ctx).EagerLoad("comments").All(); err != nil || len(rows) != 1 {
-		t.Fatalf("eager rows=%d err=%v", len(rows), err)
+	saved := queryRelations
+	queryRelations = map[string]map[string]queryRelation{}
+	t.Cleanup(func() { queryRelations = saved })
+	registerRelation("messages", "workspace_messages", "messages", "workspace_id", "workspace_id")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "workspace_id" FROM "messages" ` + clause)).WithArgs("workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id", "workspace_id" FROM "messages" `+clause+` AND "workspace_id" IN ($2) ORDER BY "id" ASC`)).WithArgs("workspace-1", "workspace-1").WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow("m1", "workspace-1").AddRow("m2", "workspace-1"))
+	if rows, err := Query[policyTestThread]("messages").WithPolicyContext(ctx).EagerLoad("workspace_messages").All(); err != nil || len(rows) != 1 || len(rows[0].WorkspaceMessages) != 2 {
+		t.Fatalf("eager rows=%+v err=%v", rows, err)
 	}
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatal(err)
@@ -95,6 +95,18 @@ func (q *QueryBuilder[T]) Where__Column__Between(start, end time.Time) *QueryBui
 	return q
 }
 
+// pickle:scope json
+func (q *QueryBuilder[T]) Where__Column__Path(path string, val any) *QueryBuilder[T] {
+	q.WhereJSON("__column__", path, val)
+	return q
+}
+
+// pickle:scope json
+func (q *QueryBuilder[T]) Where__Column__Contains(val any) *QueryBuilder[T] {
+	q.WhereJSONContains("__column__", val)
+	return q
+}
+
 // pickle:scope table
 // FetchResource fetches a single __Model__.
 func (q *QueryBuilder[T]) FetchResource(_ string) (any, error) {
@@ -126,6 +138,23 @@ func (q *QueryBuilder[T]) FetchResource(ownerID string) (any, error) {
 	return Serialize__Model__(record, ownerID), nil
 }
 
+// pickle:scope table_owned
+// FetchOwnedResource is FetchResource for owner-only access: a __Model__
+// owned by someone else returns *NotOwnerError instead of its public fields.
+func (q *QueryBuilder[T]) FetchOwnedResource(ownerID string) (any, error) {
+	if q.visibility == visibilityNone {
+		return nil, ErrNoVisibilityScope
+	}
+	record, err := q.First()
+	if err != nil {
+		return nil, err
+	}
+	if !__Model__OwnedBy(record, ownerID) {
+		return nil, &NotOwnerError{Table: q.table}
+	}
+	return Serialize__Model__(record, ownerID), nil
+}
+
 // pickle:scope table_owned
 // FetchResources fetches all matching __Model__ records and serializes them based on ownership.
 func (q *QueryBuilder[T]) FetchResources(ownerID string) (any, error) {
@@ -139,4 +168,23 @@ func (q *QueryBuilder[T]) FetchResources(ownerID string) (any, error) {
 	return Serialize__Model__s(records, ownerID), nil
 }
 
+// pickle:scope table_owned
+// FetchOwnedResources is FetchResources for owner-only access: if any
+// matching __Model__ is owned by someone else it returns *NotOwnerError.
+func (q *QueryBuilder[T]) FetchOwnedResources(ownerID string) (any, error) {
+	if q.visibility == visibilityNone {
+		return nil, ErrNoVisibilityScope
+	}
+	records, err := q.All()
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if !__Model__OwnedBy(&records[i], ownerID) {
+			return nil, &NotOwnerError{Table: q.table}
+		}
+	}
+	return Serialize__Model__s(records, ownerID), nil
+}
+
 // pickle:end
@@ -0,0 +1,83 @@
+package cooked
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownTimeout bounds how long a graceful shutdown waits for in-flight
+// requests to finish before the remaining connections are dropped.
+var ShutdownTimeout = 30 * time.Second
+
+// applyServerDefaults fills the timeouts left at zero. A server without
+// ReadHeaderTimeout is open to slowloris clients holding connections forever.
+func applyServerDefaults(srv *http.Server) {
+	if srv.ReadHeaderTimeout == 0 {
+		srv.ReadHeaderTimeout = 10 * time.Second
+	}
+	if srv.ReadTimeout == 0 {
+		srv.ReadTimeout = 30 * time.Second
+	}
+	if srv.WriteTimeout == 0 {
+		srv.WriteTimeout = 60 * time.Second
+	}
+	if srv.IdleTimeout == 0 {
+		srv.IdleTimeout = 120 * time.Second
+	}
+}
+
+// ServeGraceful listens on srv.Addr and serves until the process receives
+// SIGINT or SIGTERM. It then stops accepting connections and waits up to
+// ShutdownTimeout for in-flight requests to drain. Zero timeouts on srv get
+// the defaults. A clean shutdown returns nil.
+func ServeGraceful(srv *http.Server) error {
+	applyServerDefaults(srv)
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return serveUntil(ctx, srv, ln)
+}
+
+// serveUntil serves srv on ln until ctx is done, then shuts down gracefully.
+func serveUntil(ctx context.Context, srv *http.Server, ln net.Listener) error {
+	errc := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			errc <- srv.ServeTLS(ln, "", "")
+		} else {
+			errc <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
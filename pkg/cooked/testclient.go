@@ -0,0 +1,124 @@
+package cooked
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+// TestClient sends requests through a router's full handler chain —
+// rate limiting, auth, middleware and the controller — in memory, without
+// a TCP listener. Create one with Router.Test.
+type TestClient struct {
+	handler http.Handler
+
+	// Headers are sent with every request; per-request headers override them.
+	Headers map[string]string
+}
+
+// TestResponse is the recorded result of a TestClient request.
+type TestResponse struct {
+	Status  int
+	Headers http.Header
+	Body    []byte
+
+	// JSON holds the decoded body, or nil if the body is not valid JSON.
+	JSON any
+}
+
+// Test returns a TestClient that serves requests exactly as the router
+// would behind ListenAndServe, including trailing-slash canonicalization.
+func (r *Router) Test() *TestClient {
+	mux := http.NewServeMux()
+	r.RegisterRoutes(mux)
+	return &TestClient{handler: CanonicalPaths(mux), Headers: map[string]string{}}
+}
+
+// Get sends a GET request to path.
+func (c *TestClient) Get(path string, body any, headers map[string]string) *TestResponse {
+	return c.Do(http.MethodGet, path, body, headers)
+}
+
+// Post sends a POST request to path.
+func (c *TestClient) Post(path string, body any, headers map[string]string) *TestResponse {
+	return c.Do(http.MethodPost, path, body, headers)
+}
+
+// Put sends a PUT request to path.
+func (c *TestClient) Put(path string, body any, headers map[string]string) *TestResponse {
+	return c.Do(http.MethodPut, path, body, headers)
+}
+
+// Patch sends a PATCH request to path.
+func (c *TestClient) Patch(path string, body any, headers map[string]string) *TestResponse {
+	return c.Do(http.MethodPatch, path, body, headers)
+}
+
+// Delete sends a DELETE request to path.
+func (c *TestClient) Delete(path string, body any, headers map[string]string) *TestResponse {
+	return c.Do(http.MethodDelete, path, body, headers)
+}
+
+// Do sends a request with the given method. A string, []byte or io.Reader
+// body is sent as-is; any other non-nil body is encoded as JSON and the
+// Content-Type defaults to application/json.
+func (c *TestClient) Do(method, path string, body any, headers map[string]string) *TestResponse {
+	var reader io.Reader
+	isJSON := false
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	case []byte:
+		reader = bytes.NewReader(b)
+	case io.Reader:
+		reader = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			panic(fmt.Sprintf("pickle: test client: encoding %s %s body: %v", method, path, err))
+		}
+		reader = bytes.NewReader(encoded)
+		isJSON = true
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+
+	resp := &TestResponse{
+		Status:  rec.Code,
+		Headers: rec.Header(),
+		Body:    rec.Body.Bytes(),
+	}
+	if len(resp.Body) > 0 {
+		var decoded any
+		if json.Unmarshal(resp.Body, &decoded) == nil {
+			resp.JSON = decoded
+		}
+	}
+	return resp
+}
+
+// Decode unmarshals the response body into v.
+func (r *TestResponse) Decode(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// String returns the raw response body.
+func (r *TestResponse) String() string {
+	return string(r.Body)
+}
@@ -0,0 +1,101 @@
+package cooked
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func testClientRouter() *Router {
+	requireToken := func(ctx *Context, next func() Response) Response {
+		if ctx.BearerToken() != "secret" {
+			return ctx.Unauthorized("missing token")
+		}
+		ctx.SetAuth(&AuthInfo{UserID: "42"})
+		return next()
+	}
+	return Routes(func(r *Router) {
+		r.Get("/users/:id", func(ctx *Context) Response {
+			id, err := ctx.ParamInt("id")
+			if err != nil {
+				return ctx.BadRequest("bad id")
+			}
+			return ctx.JSON(http.StatusOK, map[string]any{"id": id})
+		})
+		r.Post("/users", func(ctx *Context) Response {
+			var in map[string]string
+			body, _ := io.ReadAll(ctx.Request().Body)
+			if err := json.Unmarshal(body, &in); err != nil {
+				return ctx.BadRequest("bad json")
+			}
+			return ctx.JSON(http.StatusCreated, map[string]string{
+				"name":         in["name"],
+				"content_type": ctx.Request().Header.Get("Content-Type"),
+			})
+		})
+		r.Delete("/users/:id", func(ctx *Context) Response {
+			return ctx.JSON(http.StatusOK, map[string]string{"deleted_by": ctx.Auth().UserID})
+		}, requireToken)
+	})
+}
+
+func TestTestClientGetDecodesJSON(t *testing.T) {
+	resp := testClientRouter().Test().Get("/users/7/", nil, nil)
+	if resp.Status != http.StatusOK {
+		t.Fatalf("status = %d, body %s", resp.Status, resp.String())
+	}
+	body, ok := resp.JSON.(map[string]any)
+	if !ok || body["id"] != float64(7) {
+		t.Errorf("JSON = %#v, want id 7", resp.JSON)
+	}
+	var out struct{ ID int }
+	if err := resp.Decode(&out); err != nil || out.ID != 7 {
+		t.Errorf("Decode = %+v, %v", out, err)
+	}
+}
+
+func TestTestClientPostEncodesJSONBody(t *testing.T) {
+	resp := testClientRouter().Test().Post("/users", map[string]string{"name": "ada"}, nil)
+	if resp.Status != http.StatusCreated {
+		t.Fatalf("status = %d, body %s", resp.Status, resp.String())
+	}
+	body := resp.JSON.(map[string]any)
+	if body["name"] != "ada" || body["content_type"] != "application/json" {
+		t.Errorf("JSON = %#v", body)
+	}
+}
+
+func TestTestClientRunsMiddleware(t *testing.T) {
+	client := testClientRouter().Test()
+	if resp := client.Delete("/users/1", nil, nil); resp.Status != http.StatusUnauthorized {
+		t.Errorf("without token status = %d, want 401", resp.Status)
+	}
+
+	resp := client.Delete("/users/1", nil, map[string]string{"Authorization": "Bearer secret"})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("with token status = %d, body %s", resp.Status, resp.String())
+	}
+	if got := resp.JSON.(map[string]any)["deleted_by"]; got != "42" {
+		t.Errorf("deleted_by = %v, want 42", got)
+	}
+
+	client.Headers["Authorization"] = "Bearer secret"
+	if resp := client.Delete("/users/1", nil, nil); resp.Status != http.StatusOK {
+		t.Errorf("default header status = %d, want 200", resp.Status)
+	}
+}
+
+func TestTestClientRawBodyAndNotFound(t *testing.T) {
+	client := testClientRouter().Test()
+	if resp := client.Post("/users", "not json", nil); resp.Status != http.StatusBadRequest {
+		t.Errorf("raw body status = %d, want 400", resp.Status)
+	}
+	resp := client.Get("/missing", nil, nil)
+	if resp.Status != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.Status)
+	}
+	if resp.JSON != nil {
+		t.Errorf("JSON = %#v, want nil for a non-JSON body", resp.JSON)
+	}
+}
@@ -0,0 +1,166 @@
+package cooked
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FieldViolation is a single field that failed request binding or
+// validation. Field is the JSON name of the field, or "_body" when the
+// request body itself could not be read.
+type FieldViolation struct {
+	Field   string
+	Message string
+}
+
+// ValidationErrorFormatter renders a request binding failure as a response.
+// Status is 400 for unreadable bodies and 422 for validation failures;
+// formatters must keep it as the response status.
+type ValidationErrorFormatter func(status int, violations []FieldViolation) Response
+
+// DefaultValidationErrorFormat is the format used when pickle.yaml doesn't
+// select one: {"errors":[{"field":...,"message":...}]}.
+const DefaultValidationErrorFormat = "errors"
+
+var validationErrorFormats = struct {
+	mu         sync.RWMutex
+	formatters map[string]ValidationErrorFormatter
+}{formatters: map[string]ValidationErrorFormatter{
+	"errors":  errorsValidationFormat,
+	"flat":    flatValidationFormat,
+	"jsonapi": jsonAPIValidationFormat,
+	"problem": problemValidationFormat,
+}}
+
+// RegisterValidationErrorFormat adds a named validation error format that
+// requests.error_format in pickle.yaml can select. It panics on an empty
+// name or a name that is already registered, including the built-in
+// "errors", "flat", "jsonapi" and "problem".
+func RegisterValidationErrorFormat(name string, formatter ValidationErrorFormatter) {
+	if name == "" {
+		panic("pickle: validation error format name must not be empty")
+	}
+	if formatter == nil {
+		panic(fmt.Sprintf("pickle: validation error format %q has a nil formatter", name))
+	}
+	validationErrorFormats.mu.Lock()
+	defer validationErrorFormats.mu.Unlock()
+	if _, exists := validationErrorFormats.formatters[name]; exists {
+		panic(fmt.Sprintf("pickle: validation error format %q is already registered", name))
+	}
+	validationErrorFormats.formatters[name] = formatter
+}
+
+// ValidationErrorResponse renders violations with the named format. Generated
+// request bindings call it from BindingError.Response. It panics if the format
+// was never registered, naming the formats that were.
+func ValidationErrorResponse(format string, status int, violations []FieldViolation) Response {
+	if format == "" {
+		format = DefaultValidationErrorFormat
+	}
+	validationErrorFormats.mu.RLock()
+	formatter, ok := validationErrorFormats.formatters[format]
+	var names []string
+	if !ok {
+		for name := range validationErrorFormats.formatters {
+			names = append(names, name)
+		}
+	}
+	validationErrorFormats.mu.RUnlock()
+	if !ok {
+		sort.Strings(names)
+		panic(fmt.Sprintf("pickle: unknown validation error format %q (registered: %s)", format, strings.Join(names, ", ")))
+	}
+	return formatter(status, violations)
+}
+
+// errorsValidationFormat is the default shape:
+// {"errors":[{"field":"email","message":"is required"}]}.
+func errorsValidationFormat(status int, violations []FieldViolation) Response {
+	type fieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+	errors := make([]fieldError, len(violations))
+	for i, v := range violations {
+		errors[i] = fieldError{Field: v.Field, Message: v.Message}
+	}
+	return Response{StatusCode: status, Body: map[string]any{"errors": errors}}
+}
+
+// flatValidationFormat maps each field to its message:
+// {"email":"is required"}. Several messages for one field are joined.
+func flatValidationFormat(status int, violations []FieldViolation) Response {
+	body := make(map[string]string, len(violations))
+	for _, v := range violations {
+		if existing, ok := body[v.Field]; ok {
+			body[v.Field] = existing + "; " + v.Message
+			continue
+		}
+		body[v.Field] = v.Message
+	}
+	return Response{StatusCode: status, Body: body}
+}
+
+// jsonAPIValidationFormat follows the JSON:API error object layout, pointing
+// each error at the offending attribute.
+func jsonAPIValidationFormat(status int, violations []FieldViolation) Response {
+	type source struct {
+		Pointer string `json:"pointer"`
+	}
+	type errorObject struct {
+		Status string  `json:"status"`
+		Title  string  `json:"title"`
+		Detail string  `json:"detail"`
+		Source *source `json:"source,omitempty"`
+	}
+	errors := make([]errorObject, len(violations))
+	for i, v := range violations {
+		obj := errorObject{
+			Status: fmt.Sprint(status),
+			Title:  http.StatusText(status),
+			Detail: v.Message,
+		}
+		if v.Field != "_body" {
+			obj.Detail = v.Field + " " + v.Message
+			obj.Source = &source{Pointer: "/data/attributes/" + v.Field}
+		}
+		errors[i] = obj
+	}
+	return Response{
+		StatusCode: status,
+		Body:       map[string]any{"errors": errors},
+		Headers:    map[string]string{"Content-Type": "application/vnd.api+json"},
+	}
+}
+
+// problemValidationFormat renders an RFC 7807 problem document, listing the
+// failing fields under the invalid-params extension member.
+func problemValidationFormat(status int, violations []FieldViolation) Response {
+	type invalidParam struct {
+		Name   string `json:"name"`
+		Reason string `json:"reason"`
+	}
+	params := make([]invalidParam, len(violations))
+	for i, v := range violations {
+		params[i] = invalidParam{Name: v.Field, Reason: v.Message}
+	}
+	detail := "The request failed validation."
+	if status != http.StatusUnprocessableEntity {
+		detail = "The request body could not be read."
+	}
+	return Response{
+		StatusCode: status,
+		Body: map[string]any{
+			"type":           "about:blank",
+			"title":          http.StatusText(status),
+			"status":         status,
+			"detail":         detail,
+			"invalid-params": params,
+		},
+		Headers: map[string]string{"Content-Type": "application/problem+json"},
+	}
+}
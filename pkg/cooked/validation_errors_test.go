@@ -0,0 +1,88 @@
+package cooked
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidationErrorResponseFormats(t *testing.T) {
+	violations := []FieldViolation{
+		{Field: "email", Message: "is required"},
+		{Field: "age", Message: "must be at least 18"},
+	}
+	tests := []struct {
+		format      string
+		contentType string
+		want        string
+	}{
+		{"", "application/json", `{"errors":[{"field":"email","message":"is required"},{"field":"age","message":"must be at least 18"}]}`},
+		{"flat", "application/json", `{"age":"must be at least 18","email":"is required"}`},
+		{"jsonapi", "application/vnd.api+json", `{"errors":[{"status":"422","title":"Unprocessable Entity","detail":"email is required","source":{"pointer":"/data/attributes/email"}},{"status":"422","title":"Unprocessable Entity","detail":"age must be at least 18","source":{"pointer":"/data/attributes/age"}}]}`},
+		{"problem", "application/problem+json", `{"detail":"The request failed validation.","invalid-params":[{"name":"email","reason":"is required"},{"name":"age","reason":"must be at least 18"}],"status":422,"title":"Unprocessable Entity","type":"about:blank"}`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		ValidationErrorResponse(tt.format, http.StatusUnprocessableEntity, violations).Write(rec)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%q: status %d, want 422", tt.format, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%q: Content-Type %q, want %q", tt.format, got, tt.contentType)
+		}
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("%q: body\n got %s\nwant %s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestValidationErrorResponseBodyErrors(t *testing.T) {
+	violations := []FieldViolation{{Field: "_body", Message: "invalid request body"}}
+
+	rec := httptest.NewRecorder()
+	ValidationErrorResponse("jsonapi", http.StatusBadRequest, violations).Write(rec)
+	var doc struct {
+		Errors []map[string]any `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || len(doc.Errors) != 1 || doc.Errors[0]["source"] != nil || doc.Errors[0]["status"] != "400" {
+		t.Errorf("jsonapi body error: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	ValidationErrorResponse("problem", http.StatusBadRequest, violations).Write(rec)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"status":400`) {
+		t.Errorf("problem body error: status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRegisterValidationErrorFormat(t *testing.T) {
+	RegisterValidationErrorFormat("test_count", func(status int, violations []FieldViolation) Response {
+		return Response{StatusCode: status, Body: map[string]int{"count": len(violations)}}
+	})
+	rec := httptest.NewRecorder()
+	ValidationErrorResponse("test_count", http.StatusUnprocessableEntity, []FieldViolation{{Field: "a", Message: "b"}}).Write(rec)
+	if rec.Code != http.StatusUnprocessableEntity || rec.Body.String() != `{"count":1}` {
+		t.Errorf("custom format: status %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	assertPanics := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+	assertPanics("duplicate", func() {
+		RegisterValidationErrorFormat("jsonapi", func(int, []FieldViolation) Response { return Response{} })
+	})
+	assertPanics("unknown", func() {
+		ValidationErrorResponse("nope", http.StatusUnprocessableEntity, nil)
+	})
+}
@@ -250,7 +250,7 @@ func configureMultiServiceProject(project *generator.Project, cfg *squeeze.Confi
 			Name:        name,
 			Dir:         absDir,
 			HTTPDir:     filepath.Join(absDir, "http"),
-			HTTPPkg:     "pickle",
+			HTTPPkg:     project.Layout.HTTPPkg,
 			RequestsDir: filepath.Join(absDir, "http", "requests"),
 			CommandsDir: filepath.Join(absDir, "commands"),
 		})
@@ -3449,7 +3449,7 @@ func isExcludedFromExportedGraphQL(tbl *schema.Table, col *schema.Column) bool {
 	if col.Type == schema.Binary {
 		return true
 	}
-	if col.Name == "password_hash" || col.Name == "password" || col.Name == "row_hash" || col.Name == "prev_hash" || col.Name == "version_id" {
+	if generator.IsHiddenColumn(col) || col.Name == "version_id" {
 		return true
 	}
 	if tbl != nil {
@@ -5998,7 +5998,7 @@ func auditContextIP(ctx *httpx.Context) string {
 	if ctx == nil {
 		return ""
 	}
-	return auditSafeMetadata(ctx.ClientIP(), maxAuditIPBytes)
+	return auditSafeMetadata(ctx.ClientIP(httpx.TrustedProxiesFromEnv()), maxAuditIPBytes)
 }
 
 func auditContextRequestID(ctx *httpx.Context) string {
@@ -8024,6 +8024,15 @@ func sqlForMigrationOp(op generator.MigrationOperation, tableByName map[string]*
 		return strings.Join(statements, ";\n"), nil
 	case "drop_column":
 		return "ALTER TABLE " + quoteIdent(op.Table) + " DROP COLUMN " + quoteIdent(op.ColumnName), nil
+	case "change_column":
+		if len(op.Columns) == 0 {
+			return "", fmt.Errorf("change_column on %s did not define a column", op.Table)
+		}
+		var statements []string
+		for _, col := range op.Columns {
+			statements = append(statements, changeColumnSQL(op.Table, col))
+		}
+		return strings.Join(statements, ";\n"), nil
 	case "rename_column":
 		return "ALTER TABLE " + quoteIdent(op.Table) + " RENAME COLUMN " + quoteIdent(op.OldName) + " TO " + quoteIdent(op.NewName), nil
 	case "add_index", "add_unique_index":
@@ -8243,11 +8252,14 @@ func createIndexSQL(idx *schema.Index) string {
 }
 
 func indexName(idx *schema.Index) string {
+	if idx.Name != "" {
+		return idx.Name
+	}
 	kind := "idx"
 	if idx.Unique {
 		kind = "uidx"
 	}
-	return kind + "_" + idx.Table + "_" + strings.Join(idx.Columns, "_")
+	return schema.ShortenIdentifier(kind+"_"+idx.Table+"_"+strings.Join(idx.Columns, "_"), schema.PostgresMaxIdentifier)
 }
 
 func createViewSQL(view *schema.View, tables ...*schema.Table) string {
@@ -8355,15 +8367,7 @@ func columnSQL(col *schema.Column, compositePrimaryKey bool) string {
 		b.WriteString(" UNIQUE")
 	}
 	if col.HasDefault {
-		if s, ok := col.DefaultValue.(string); ok {
-			if strings.Contains(s, "(") {
-				b.WriteString(" DEFAULT " + s)
-			} else {
-				b.WriteString(" DEFAULT '" + strings.ReplaceAll(s, "'", "''") + "'")
-			}
-		} else {
-			b.WriteString(fmt.Sprintf(" DEFAULT %v", col.DefaultValue))
-		}
+		b.WriteString(" DEFAULT " + defaultSQL(col))
 	}
 	if col.ForeignKeyTable != "" && !col.FKMetadataOnly {
 		b.WriteString(" REFERENCES " + quoteIdent(col.ForeignKeyTable) + "(" + quoteIdent(col.ForeignKeyColumn) + ")")
@@ -8374,6 +8378,39 @@ func columnSQL(col *schema.Column, compositePrimaryKey bool) string {
 	return b.String()
 }
 
+func defaultSQL(col *schema.Column) string {
+	if s, ok := col.DefaultValue.(string); ok {
+		if col.DefaultIsExpression() {
+			return s
+		}
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return fmt.Sprintf("%v", col.DefaultValue)
+}
+
+// changeColumnSQL sets a column's type, nullability and default, casting
+// existing values unless the column carries its own USING expression.
+func changeColumnSQL(table string, col *schema.Column) string {
+	typ := sqlType(col)
+	using := col.UsingExpr
+	if using == "" {
+		using = quoteIdent(col.Name) + "::" + typ
+	}
+	alter := "ALTER COLUMN " + quoteIdent(col.Name)
+	actions := []string{alter + " TYPE " + typ + " USING " + using}
+	if col.IsNullable {
+		actions = append(actions, alter+" DROP NOT NULL")
+	} else {
+		actions = append(actions, alter+" SET NOT NULL")
+	}
+	if col.HasDefault {
+		actions = append(actions, alter+" SET DEFAULT "+defaultSQL(col))
+	} else {
+		actions = append(actions, alter+" DROP DEFAULT")
+	}
+	return "ALTER TABLE " + quoteIdent(table) + " " + strings.Join(actions, ", ")
+}
+
 func sqlType(col *schema.Column) string {
 	switch col.Type {
 	case schema.UUID:
@@ -8397,7 +8434,14 @@ func sqlType(col *schema.Column) string {
 	case schema.Boolean:
 		return "BOOLEAN"
 	case schema.Timestamp:
-		return "TIMESTAMPTZ"
+		typ := "TIMESTAMPTZ"
+		if col.WithoutTimeZone {
+			typ = "TIMESTAMP"
+		}
+		if col.HasPrecision {
+			typ += fmt.Sprintf("(%d)", col.Precision)
+		}
+		return typ
 	case schema.JSONB:
 		return "JSONB"
 	case schema.Date:
@@ -9369,6 +9413,7 @@ func encryptedStorageColumn(src *schema.Column, name string, nullable bool) *sch
 	col.ForeignKeyColumn = ""
 	col.HasDefault = false
 	col.DefaultValue = nil
+	col.DefaultIsRaw = false
 	return &col
 }
 
@@ -9437,7 +9482,7 @@ func gormTag(col *schema.Column) string {
 }
 
 func jsonTag(col *schema.Column) string {
-	if col.Name == "password" || col.Name == "password_hash" || col.Name == "row_hash" || col.Name == "prev_hash" {
+	if generator.IsHiddenColumn(col) {
 		return "-"
 	}
 	if col.IsNullable {
@@ -9486,8 +9531,10 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -9506,6 +9553,33 @@ func Env(key, fallback string) string {
 	return fallback
 }
 
+func EnvInt(key string, fallback int) int {
+	v := Env(key, "")
+	if v == "" { return fallback }
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil { log.Printf("invalid integer %s=%q, using default %d", key, v, fallback); return fallback }
+	return n
+}
+
+func EnvBool(key string, fallback bool) bool {
+	v := Env(key, "")
+	if v == "" { return fallback }
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes": return true
+	case "0", "false", "no": return false
+	}
+	log.Printf("invalid boolean %s=%q, using default %t", key, v, fallback)
+	return fallback
+}
+
+func EnvDuration(key string, fallback time.Duration) time.Duration {
+	v := Env(key, "")
+	if v == "" { return fallback }
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil { log.Printf("invalid duration %s=%q, using default %s", key, v, fallback); return fallback }
+	return d
+}
+
 func loadEnv() {
 	envMap = map[string]string{}
 	f, err := os.Open(".env")
@@ -9533,6 +9607,23 @@ type ConnectionConfig struct {
 	Password string
 	Region string
 	Options map[string]string
+	MaxOpenConns int
+	MaxIdleConns int
+	ConnMaxLifetime time.Duration
+}
+
+func (c ConnectionConfig) applyPool(db *sql.DB) {
+	maxOpen, maxIdle, maxLifetime := 25, 10, 30*time.Minute
+	switch c.Driver {
+	case "mysql": maxLifetime = 5 * time.Minute
+	case "sqlite": maxOpen, maxIdle, maxLifetime = 0, 2, 0
+	}
+	if c.MaxOpenConns != 0 { maxOpen = c.MaxOpenConns }
+	if c.MaxIdleConns != 0 { maxIdle = c.MaxIdleConns }
+	if c.ConnMaxLifetime != 0 { maxLifetime = c.ConnMaxLifetime }
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	if maxLifetime > 0 { db.SetConnMaxLifetime(maxLifetime) }
 }
 
 func (c ConnectionConfig) DSN() string {
@@ -9585,6 +9676,7 @@ func TryOpenDB(conn ConnectionConfig) (*sql.DB, error) {
 	if err != nil { return nil, err }
 	db, err := sql.Open(driverName, dsn)
 	if err != nil { return nil, errors.New("open database") }
+	conn.applyPool(db)
 	if err := db.Ping(); err != nil { db.Close(); return nil, errors.New("ping database") }
 	return db, nil
 }
@@ -9828,6 +9920,7 @@ func shouldInsertSnakeBoundary(runes []rune, i int) bool {
 const httpxSource = `package httpx
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -9852,7 +9945,7 @@ type Controller struct{}
 type Response struct { Status int; StatusCode int; Body any; Headers map[string]string; Cookies []*http.Cookie }
 type AuthInfo struct { UserID string; Role string; Claims any }
 type RoleInfo struct { Slug string; Manages bool }
-type Context struct { request *http.Request; response http.ResponseWriter; auth *AuthInfo; params map[string]string; roles []string; rolesLoaded bool; isAdmin bool; router *Router; routeName string; csrfToken string }
+type Context struct { request *http.Request; response http.ResponseWriter; auth *AuthInfo; params map[string]string; roles []string; rolesLoaded bool; isAdmin bool; router *Router; routeName string; csrfToken string; responseHeaders map[string]string; responseCookies []*http.Cookie }
 
 const maxBearerTokenHeaderBytes = 12 << 10
 
@@ -9863,10 +9956,21 @@ func (c *Context) ResponseWriter() http.ResponseWriter { if c == nil { return ni
 func (c *Context) Param(name string) string { if c == nil { return "" }; value, ok := c.params[name]; if !ok { panic("route parameter is missing: " + name) }; return value }
 func (c *Context) SetParam(name, value string) { if c == nil { return }; if c.params == nil { c.params = map[string]string{} }; c.params[name] = value }
 func (c *Context) ParamUUID(name string) (uuid.UUID, error) { value, err := uuid.Parse(c.Param(name)); if err != nil { return uuid.Nil, fmt.Errorf("invalid uuid parameter") }; return value, nil }
+func (c *Context) ParamInt(name string) (int, error) { value, err := strconv.Atoi(c.Param(name)); if err != nil { return 0, fmt.Errorf("invalid integer parameter") }; return value, nil }
+func (c *Context) ParamInt64(name string) (int64, error) { value, err := strconv.ParseInt(c.Param(name), 10, 64); if err != nil { return 0, fmt.Errorf("invalid integer parameter") }; return value, nil }
 func (c *Context) Cookie(name string) (string, error) { if c == nil || c.request == nil { return "", http.ErrNoCookie }; cookie, err := c.request.Cookie(name); if err != nil { return "", err }; return cookie.Value, nil }
+func (c *Context) RequestCookie(name string) (*http.Cookie, error) { if c == nil || c.request == nil { return nil, http.ErrNoCookie }; return c.request.Cookie(name) }
+func (c *Context) SetResponseHeader(key, value string) { if c == nil { return }; if c.responseHeaders == nil { c.responseHeaders = map[string]string{} }; c.responseHeaders[http.CanonicalHeaderKey(key)] = value }
+func (c *Context) SetCookie(cookie *http.Cookie) { if c != nil && cookie != nil { c.responseCookies = append(c.responseCookies, cookie) } }
+func (c *Context) applyResponseState(resp Response) Response {
+	if c == nil { return resp }
+	if len(c.responseHeaders) > 0 { headers := make(map[string]string, len(c.responseHeaders)+len(resp.Headers)); for k, v := range c.responseHeaders { headers[k] = v }; for k, v := range resp.Headers { delete(headers, http.CanonicalHeaderKey(k)); headers[k] = v }; resp.Headers = headers }
+	if len(c.responseCookies) > 0 { resp.Cookies = append(append([]*http.Cookie{}, c.responseCookies...), resp.Cookies...) }
+	return resp
+}
 func (c *Context) Query(name string) string { if c == nil || c.request == nil || c.request.URL == nil { return "" }; return c.request.URL.Query().Get(name) }
 func (c *Context) BearerToken() string { if c == nil || c.request == nil { return "" }; h := c.request.Header.Get("Authorization"); if len(h) > maxBearerTokenHeaderBytes { return "" }; parts := strings.Fields(h); if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") && parts[1] != "" { return parts[1] }; return "" }
-func (c *Context) ClientIP() string { if c == nil || c.request == nil { return "" }; return clientIP(c.request) }
+func (c *Context) ClientIP(trustedProxies []string) string { if c == nil || c.request == nil { return "" }; nets, all := parseTrustedProxies(trustedProxies); return resolveClientIP(c.request, func(ip string) bool { return ipInNets(ip, nets, all) }) }
 func (c *Context) Auth() *AuthInfo { if c == nil || c.auth == nil { return &AuthInfo{} }; return c.auth }
 func (c *Context) SetAuth(claims any) { if c == nil { return }; switch v := claims.(type) { case nil: c.auth = nil; case *AuthInfo: c.auth = v; default: panic(fmt.Sprintf("SetAuth requires *AuthInfo, got %T", claims)) } }
 func (c *Context) IsAuthenticated() bool { return c != nil && c.auth != nil && c.auth.UserID != "" }
@@ -9877,10 +9981,20 @@ func (c *Context) HasRole(slug string) bool { if c == nil { return false }; for
 func (c *Context) HasAnyRole(roles ...string) bool { for _, role := range roles { if c.HasRole(role) { return true } }; return false }
 func (c *Context) IsAdmin() bool { return c != nil && (c.isAdmin || (!c.rolesLoaded && c.auth != nil && c.auth.Role == "admin")) }
 func (c *Context) JSON(status int, body any) Response { return Response{Status: status, StatusCode: status, Body: body} }
+func HealthHandler(db any) HandlerFunc {
+	return func(c *Context) Response {
+		var sqlDB *sql.DB
+		switch v := db.(type) { case *sql.DB: sqlDB = v; case *gorm.DB: if v != nil { sqlDB, _ = v.DB() } }
+		ctx, cancel := context.WithTimeout(c.Request().Context(), 2*time.Second); defer cancel()
+		if sqlDB == nil || sqlDB.PingContext(ctx) != nil { return c.JSON(http.StatusServiceUnavailable, map[string]string{"status": "error", "db": "down"}) }
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok", "db": "up"})
+	}
+}
 func (c *Context) Error(err error) Response { if err != nil { log.Printf("http error") }; return c.JSON(500, map[string]string{"error": "internal server error"}) }
 func (c *Context) BadRequest(msg string) Response { return c.JSON(400, map[string]string{"error": msg}) }
 func (c *Context) Unauthorized(msg string) Response { return c.JSON(401, map[string]string{"error": msg}) }
 func (c *Context) Forbidden(msg string) Response { return c.JSON(403, map[string]string{"error": msg}) }
+func (c *Context) Authorize(allowed bool) (Response, bool) { if !allowed { return c.Forbidden("forbidden"), false }; return Response{}, true }
 func (c *Context) NotFound(msg string) Response { return c.JSON(404, map[string]string{"error": msg}) }
 func (c *Context) NoContent() Response { return Response{Status: 204, StatusCode: 204} }
 func (c *Context) Redirect(location string) Response { if location == "" || strings.ContainsAny(location, "\r\n") { panic("redirect location must be safe") }; return Response{Status: http.StatusSeeOther, StatusCode: http.StatusSeeOther, Headers: map[string]string{"Location": location}} }
@@ -9894,12 +10008,22 @@ func (c *Context) CSRFToken() string { if c == nil || c.csrfToken == "" { panic(
 type ResourceQuery interface { FetchResource(ownerID string) (any, error) }
 type ResourceListQuery interface { FetchResources(ownerID string) (any, error) }
 func isResourceNotFound(err error) bool { return errors.Is(err, sql.ErrNoRows) || errors.Is(err, gorm.ErrRecordNotFound) }
-func (c *Context) Resource(q ResourceQuery) Response { ownerID := ""; if c != nil && c.auth != nil { ownerID = c.auth.UserID }; result, err := q.FetchResource(ownerID); if err != nil { if isResourceNotFound(err) { return c.NotFound("not found") }; return c.Error(err) }; return c.JSON(http.StatusOK, result) }
-func (c *Context) Resources(q ResourceListQuery) Response { ownerID := ""; if c != nil && c.auth != nil { ownerID = c.auth.UserID }; result, err := q.FetchResources(ownerID); if err != nil { return c.Error(err) }; return c.JSON(http.StatusOK, result) }
+type OwnedResourceQuery interface { FetchOwnedResource(ownerID string) (any, error) }
+type OwnedResourceListQuery interface { FetchOwnedResources(ownerID string) (any, error) }
+type ResourceOptions struct { OwnerOnly bool; ForbidNotOwned bool }
+type NotOwnerError struct { Table string }
+func (e *NotOwnerError) HTTPStatus() int { return 404 }
+func (e *NotOwnerError) NotOwner() bool { return true }
+func (e *NotOwnerError) Error() string { return fmt.Sprintf("%s record belongs to another owner", e.Table) }
+func (c *Context) Resource(q ResourceQuery) Response { return c.ResourceWith(q, ResourceOptions{}) }
+func (c *Context) ResourceWith(q ResourceQuery, opts ResourceOptions) Response { ownerID := ""; if c != nil && c.auth != nil { ownerID = c.auth.UserID }; fetch := q.FetchResource; if owned, ok := q.(OwnedResourceQuery); ok && (opts.OwnerOnly || opts.ForbidNotOwned) { fetch = owned.FetchOwnedResource }; result, err := fetch(ownerID); if err != nil { if isResourceNotFound(err) { return c.NotFound("not found") }; return c.resourceError(err, opts) }; return c.JSON(http.StatusOK, result) }
+func (c *Context) Resources(q ResourceListQuery) Response { return c.ResourcesWith(q, ResourceOptions{}) }
+func (c *Context) ResourcesWith(q ResourceListQuery, opts ResourceOptions) Response { ownerID := ""; if c != nil && c.auth != nil { ownerID = c.auth.UserID }; fetch := q.FetchResources; if owned, ok := q.(OwnedResourceListQuery); ok && (opts.OwnerOnly || opts.ForbidNotOwned) { fetch = owned.FetchOwnedResources }; result, err := fetch(ownerID); if err != nil { return c.resourceError(err, opts) }; return c.JSON(http.StatusOK, result) }
+func (c *Context) resourceError(err error, opts ResourceOptions) Response { var notOwner *NotOwnerError; if errors.As(err, &notOwner) { if opts.ForbidNotOwned { return c.Forbidden("forbidden") }; return c.NotFound("not found") }; return c.Error(err) }
 
 func (r Response) WithCookie(cookie *http.Cookie) Response { if cookie != nil { r.Cookies = append(r.Cookies, cookie) }; return r }
 
-func (r Response) Write(w http.ResponseWriter) { if w == nil { return }; for k, v := range r.Headers { w.Header().Set(k, v) }; status := r.Status; if status == 0 { status = r.StatusCode }; if status == 0 { status = 200 }; var payload []byte; if r.Body != nil { var err error; payload, err = json.Marshal(r.Body); if err != nil { log.Printf("http response encode failed"); status = http.StatusInternalServerError; payload = []byte(` + "`" + `{"error":"internal server error"}` + "`" + `) }; if w.Header().Get("Content-Type") == "" { w.Header().Set("Content-Type", "application/json") }; if w.Header().Get("X-Content-Type-Options") == "" { w.Header().Set("X-Content-Type-Options", "nosniff") } }; for _, cookie := range r.Cookies { if cookie != nil { http.SetCookie(w, cookie) } }; w.WriteHeader(status); if payload != nil { _, _ = w.Write(append(payload, '\n')) } }
+func (r Response) Write(w http.ResponseWriter) { if w == nil { return }; for k, v := range r.Headers { w.Header().Set(k, v) }; status := r.Status; if status == 0 { status = r.StatusCode }; if status == 0 { status = 200 }; var payload []byte; if r.Body != nil { var err error; payload, err = json.Marshal(r.Body); if err != nil { log.Printf("http response encode failed"); status = http.StatusInternalServerError; payload = []byte(` + "`" + `{"error":"internal server error"}` + "`" + `) }; if w.Header().Get("Content-Type") == "" { w.Header().Set("Content-Type", "application/json") }; if w.Header().Get("X-Content-Type-Options") == "" { w.Header().Set("X-Content-Type-Options", "nosniff") } }; for _, cookie := range r.Cookies { if cookie != nil { http.SetCookie(w, cookie) } }; if payload != nil { payload = append(payload, '\n'); w.Header().Set("Content-Length", strconv.Itoa(len(payload))) }; w.WriteHeader(status); if payload != nil { _, _ = w.Write(payload) } }
 
 type HandlerFunc func(*Context) Response
 type MiddlewareFunc func(*Context, func() Response) Response
@@ -9932,6 +10056,8 @@ func (r *Router) Group(path string, args ...any) *RouteGroup {
 			child.middleware = append(child.middleware, MiddlewareFunc(v))
 		case MiddlewareProvider:
 			child.middleware = append(child.middleware, v.Middleware())
+		case string:
+			child.middleware = append(child.middleware, middlewareByName(v))
 		case func(*Router):
 			bodies = append(bodies, v)
 		}
@@ -9947,7 +10073,10 @@ func (r *Router) Patch(path string, handler HandlerFunc, middleware ...any) *Rou
 func (r *Router) Delete(path string, handler HandlerFunc, middleware ...any) *Route { return r.add("DELETE", path, handler, middleware...) }
 func (r *Router) add(method, path string, handler HandlerFunc, middleware ...any) *Route { if r == nil { return nil }; r.routes = append(r.routes, Route{Method: method, Path: joinPath(r.prefix, path), Handler: handler, Middleware: append(append([]MiddlewareFunc{}, r.middleware...), resolveMiddleware(middleware)...)} ); return &r.routes[len(r.routes)-1] }
 func (r *Router) Resource(prefix string, c ResourceController, middleware ...any) *ResourceRoutes { start := len(r.routes); r.Get(prefix, c.Index, middleware...); r.Get(prefix + "/:id", c.Show, middleware...); r.Post(prefix, c.Store, middleware...); r.Put(prefix + "/:id", c.Update, middleware...); r.Delete(prefix + "/:id", c.Destroy, middleware...); return &ResourceRoutes{router: r, start: start} }
-func resolveMiddleware(middleware []any) []MiddlewareFunc { resolved := make([]MiddlewareFunc, 0, len(middleware)); for _, mw := range middleware { switch v := mw.(type) { case MiddlewareFunc: resolved = append(resolved, v); case func(*Context, func() Response) Response: resolved = append(resolved, MiddlewareFunc(v)); case MiddlewareProvider: resolved = append(resolved, v.Middleware()); default: panic("invalid middleware type") } }; return resolved }
+func resolveMiddleware(middleware []any) []MiddlewareFunc { resolved := make([]MiddlewareFunc, 0, len(middleware)); for _, mw := range middleware { switch v := mw.(type) { case MiddlewareFunc: resolved = append(resolved, v); case func(*Context, func() Response) Response: resolved = append(resolved, MiddlewareFunc(v)); case MiddlewareProvider: resolved = append(resolved, v.Middleware()); case string: resolved = append(resolved, middlewareByName(v)); default: panic("invalid middleware type") } }; return resolved }
+var namedMiddleware = struct { sync.RWMutex; m map[string]MiddlewareFunc }{m: map[string]MiddlewareFunc{}}
+func RegisterMiddleware(name string, middleware ...any) { chain := resolveMiddleware(middleware); if name == "" || len(chain) == 0 { panic("invalid middleware registration: " + name) }; namedMiddleware.Lock(); defer namedMiddleware.Unlock(); if _, exists := namedMiddleware.m[name]; exists { panic("middleware registered twice: " + name) }; namedMiddleware.m[name] = func(c *Context, next func() Response) Response { for i := len(chain) - 1; i >= 0; i-- { mw, inner := chain[i], next; next = func() Response { return mw(c, inner) } }; return next() } }
+func middlewareByName(name string) MiddlewareFunc { return func(c *Context, next func() Response) Response { namedMiddleware.RLock(); fn, ok := namedMiddleware.m[name]; namedMiddleware.RUnlock(); if !ok { panic("middleware is not registered: " + name) }; return fn(c, next) } }
 func (r *Router) AllRoutes() []Route { if r == nil { return nil }; routes := make([]Route, len(r.routes)); copy(routes, r.routes); return routes }
 func (r *Router) namedRoutes() map[string]Route { named := map[string]Route{}; for _, route := range r.AllRoutes() { if route.NameValue == "" { continue }; if _, exists := named[route.NameValue]; exists { panic("duplicate route name: " + route.NameValue) }; named[route.NameValue] = route }; return named }
 func (r *Router) URL(name string, params RouteParams) string { route, ok := r.namedRoutes()[name]; if !ok { panic("unknown route name: " + name) }; used := map[string]bool{}; path := paramPattern.ReplaceAllStringFunc(route.Path, func(token string) string { key := strings.TrimPrefix(token, ":"); value, exists := params[key]; if !exists { panic("missing route parameter: " + key) }; used[key] = true; return url.PathEscape(fmt.Sprint(value)) }); for key := range params { if !used[key] { panic("extra route parameter: " + key) } }; return path }
@@ -9991,7 +10120,7 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			inner := next
 			next = func() Response { return mw(ctx, inner) }
 		}
-		resp := next()
+		resp := ctx.applyResponseState(next())
 		for k, v := range rateLimitHeaders { if resp.Headers == nil { resp.Headers = map[string]string{} }; resp.Headers[k] = v }
 		resp.Write(w)
 		return
@@ -10137,13 +10266,17 @@ func bucketRemaining(bucket *rateBucket) float64 { if bucket == nil { return 0 }
 func rateLimitExceeded(bucket *rateBucket, rps float64, burst int) *Response { resp := Response{StatusCode: http.StatusTooManyRequests, Body: map[string]string{"error": "rate limit exceeded"}, Headers: map[string]string{"Content-Type": "application/json", "Retry-After": strconv.Itoa(bucket.retryAfter(rps))}}; for k, v := range rateLimitHeaders(rps, burst, 0) { resp.Headers[k] = v }; return &resp }
 func rateLimitHeaders(rps float64, burst int, remaining float64) map[string]string { if remaining < 0 { remaining = 0 }; reset := time.Now(); if rps > 0 { deficit := float64(burst) - remaining; if deficit > 0 { reset = reset.Add(time.Duration((deficit / rps) * float64(time.Second))) } }; return map[string]string{"X-RateLimit-Limit": strconv.Itoa(int(rps)), "X-RateLimit-Remaining": strconv.Itoa(int(remaining)), "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10)} }
 func env(key, fallback string) string { if value := os.Getenv(key); value != "" { return value }; return fallback }
-func initTrustedProxies() { trustedProxiesOnce.Do(func() { raw := strings.TrimSpace(env("TRUSTED_PROXIES", "")); if raw == "" { return }; if raw == "all" { trustedProxiesAll = true; return }; for _, entry := range strings.Split(raw, ",") { entry = strings.TrimSpace(entry); if entry == "" { continue }; if !strings.Contains(entry, "/") { ip := net.ParseIP(entry); if ip == nil { continue }; if ip.To4() != nil { entry += "/32" } else { entry += "/128" } }; _, cidr, err := net.ParseCIDR(entry); if err == nil { trustedProxies = append(trustedProxies, *cidr) } } }) }
-func proxyHeadersTrusted(remote string) bool { initTrustedProxies(); if trustedProxiesAll { return true }; ip := net.ParseIP(remote); if ip == nil { return false }; for _, cidr := range trustedProxies { if cidr.Contains(ip) { return true } }; return false }
-func firstUntrustedIP(xff string) string { parts := strings.Split(xff, ","); fallback := ""; for i := len(parts) - 1; i >= 0; i-- { ip := safeClientIP(parts[i]); if ip == "" { continue }; if fallback == "" { fallback = ip }; if !proxyHeadersTrusted(ip) { return ip } }; return fallback }
+func TrustedProxiesFromEnv() []string { raw := strings.TrimSpace(env("TRUSTED_PROXIES", "")); if raw == "" { return nil }; return strings.Split(raw, ",") }
+func initTrustedProxies() { trustedProxiesOnce.Do(func() { trustedProxies, trustedProxiesAll = parseTrustedProxies(TrustedProxiesFromEnv()) }) }
+func parseTrustedProxies(entries []string) ([]net.IPNet, bool) { var nets []net.IPNet; for _, entry := range entries { entry = strings.TrimSpace(entry); if entry == "" { continue }; if entry == "all" { return nil, true }; if !strings.Contains(entry, "/") { ip := net.ParseIP(entry); if ip == nil { continue }; if ip.To4() != nil { entry += "/32" } else { entry += "/128" } }; _, cidr, err := net.ParseCIDR(entry); if err == nil { nets = append(nets, *cidr) } }; return nets, false }
+func proxyHeadersTrusted(remote string) bool { initTrustedProxies(); return ipInNets(remote, trustedProxies, trustedProxiesAll) }
+func ipInNets(remote string, nets []net.IPNet, all bool) bool { if all { return true }; ip := net.ParseIP(remote); if ip == nil { return false }; for _, cidr := range nets { if cidr.Contains(ip) { return true } }; return false }
+func firstUntrustedIP(xff string, trusted func(string) bool) string { parts := strings.Split(xff, ","); fallback := ""; for i := len(parts) - 1; i >= 0; i-- { ip := safeClientIP(parts[i]); if ip == "" { continue }; if fallback == "" { fallback = ip }; if !trusted(ip) { return ip } }; return fallback }
 func stripPort(addr string) string { if host, _, err := net.SplitHostPort(addr); err == nil { return host }; return addr }
 func contextPath(ctx *Context) string { if ctx == nil { return "" }; return requestPath(ctx.Request()) }
 func requestPath(r *http.Request) string { if r == nil || r.URL == nil { return "" }; return r.URL.Path }
-func clientIP(r *http.Request) string { if r == nil { return "" }; remote := safeClientIP(stripPort(r.RemoteAddr)); if !proxyHeadersTrusted(remote) { return remote }; if xff := r.Header.Get("X-Forwarded-For"); xff != "" { if ip := firstUntrustedIP(xff); ip != "" { return ip } }; if xri := r.Header.Get("X-Real-IP"); xri != "" { if ip := safeClientIP(xri); ip != "" { return ip } }; return remote }
+func clientIP(r *http.Request) string { return resolveClientIP(r, proxyHeadersTrusted) }
+func resolveClientIP(r *http.Request, trusted func(string) bool) string { if r == nil { return "" }; remote := safeClientIP(stripPort(r.RemoteAddr)); if !trusted(remote) { return remote }; if xff := r.Header.Get("X-Forwarded-For"); xff != "" { if ip := firstUntrustedIP(xff, trusted); ip != "" { return ip } }; if xri := r.Header.Get("X-Real-IP"); xri != "" { if ip := safeClientIP(xri); ip != "" { return ip } }; return remote }
 func safeClientIP(value string) string { value = strings.TrimSpace(value); if value == "" || len(value) > maxClientIPBytes { return "" }; for _, r := range value { if r < 0x20 || r == 0x7f { return "" } }; ip := net.ParseIP(value); if ip == nil { return "" }; return ip.String() }
 `
 
@@ -4122,7 +4122,7 @@ func TestExportedFrameworkMisusePanicsDoNotMentionPickle(t *testing.T) {
 		httpx.Routes(func(r *httpx.Router) {
 			r.Get("/bad", func(ctx *httpx.Context) httpx.Response {
 				return ctx.NoContent()
-			}, "not middleware")
+			}, 42)
 		})
 	})
 }
@@ -4529,9 +4529,12 @@ func TestExportedContextClientIPIgnoresSpoofedProxyHeadersByDefault(t *testing.T
 	resetTrustedProxyStateForTest()
 	t.Setenv("TRUSTED_PROXIES", "")
 	ctx := NewContext(requestFrom("10.0.0.1:1234", "198.51.100.77"))
-	if got := ctx.ClientIP(); got != "10.0.0.1" {
+	if got := ctx.ClientIP(nil); got != "10.0.0.1" {
 		t.Fatalf("ClientIP = %q, want remote address without trusting X-Forwarded-For", got)
 	}
+	if got := ctx.ClientIP([]string{"10.0.0.0/8"}); got != "198.51.100.77" {
+		t.Fatalf("ClientIP behind trusted proxy = %q, want forwarded address", got)
+	}
 }
 
 func TestExportedContextHelpersHandleNilRequest(t *testing.T) {
@@ -4584,7 +4587,7 @@ func TestExportedContextHelpersHandleNilRequest(t *testing.T) {
 			t.Fatalf("BearerToken malformed header %q = %q, want empty", header, got)
 		}
 	}
-	if got := ctx.ClientIP(); got != "" {
+	if got := ctx.ClientIP(nil); got != "" {
 		t.Fatalf("ClientIP on nil request = %q, want empty", got)
 	}
 	if _, err := ctx.Cookie("session_id"); err != http.ErrNoCookie {
@@ -4598,7 +4601,7 @@ func TestExportedContextHelpersHandleNilRequest(t *testing.T) {
 	if got := nilCtx.BearerToken(); got != "" {
 		t.Fatalf("BearerToken on nil context = %q, want empty", got)
 	}
-	if got := nilCtx.ClientIP(); got != "" {
+	if got := nilCtx.ClientIP(nil); got != "" {
 		t.Fatalf("ClientIP on nil context = %q, want empty", got)
 	}
 	if _, err := nilCtx.Cookie("session_id"); err != http.ErrNoCookie {
@@ -9511,7 +9514,7 @@ func (c TransferController) Approve(ctx *pickle.Context) pickle.Response {
 	return ctx.JSON(http.StatusAccepted, map[string]any{
 		"ok": true,
 		"userId": ctx.Auth().UserID,
-		"clientIP": ctx.ClientIP(),
+		"clientIP": ctx.ClientIP(nil),
 		"requestID": ctx.Request().Header.Get("X-Request-ID"),
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"go/format"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -29,6 +30,58 @@ var builtinAuthDrivers = map[string]string{
 	"oauth":   embedAUTHOAUTH,
 }
 
+// builtinAuthDriverEnv lists the environment variables each built-in driver
+// cannot start without. The generated config Init validates the active
+// driver's entry before anything else runs.
+var builtinAuthDriverEnv = map[string]DriverEnv{
+	"jwt": {
+		// The JWT driver reads a shared secret for HS* and an RSA key for
+		// RS*; a verify-only service holds just the public key.
+		Switch:        "JWT_ALGORITHM",
+		SwitchDefault: "HS256",
+		Cases: []DriverEnvCase{
+			{Prefix: "RS", Vars: []string{"JWT_PRIVATE_KEY|JWT_PUBLIC_KEY"}},
+			{Vars: []string{"JWT_SECRET"}},
+		},
+	},
+	"oauth": {Vars: []string{"OAUTH_CLIENT_ID", "OAUTH_CLIENT_SECRET"}},
+}
+
+// DriverEnv is the set of environment variables required when Name is the
+// active auth driver. Vars are always required. When Switch is set, the
+// first of Cases whose Prefix starts the value of Switch (SwitchDefault if
+// unset) adds its Vars too; an empty Prefix matches anything. A var may
+// list alternatives separated by "|", as RequireEnv accepts.
+type DriverEnv struct {
+	Name          string
+	Vars          []string
+	Switch        string
+	SwitchDefault string
+	Cases         []DriverEnvCase
+}
+
+// DriverEnvCase is one branch of DriverEnv.Switch.
+type DriverEnvCase struct {
+	Prefix string
+	Vars   []string
+}
+
+// AuthDriverEnv returns the required environment variables for every
+// built-in driver the project hasn't overridden with its own driver.go.
+// Overridden drivers own their configuration, so nothing is assumed.
+func AuthDriverEnv(authDir string) []DriverEnv {
+	var out []DriverEnv
+	for name, env := range builtinAuthDriverEnv {
+		if _, overridden := statFile(filepath.Join(authDir, name, "driver.go")); overridden {
+			continue
+		}
+		env.Name = name
+		out = append(out, env)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
 // builtinAuthMigrations maps driver names to a list of (filename, embed) pairs.
 // Each entry corresponds to one migration file. Filenames use the standard
 // timestamp prefix convention.
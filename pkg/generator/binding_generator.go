@@ -27,10 +27,14 @@ type RequestField struct {
 	Name         string // Go field name
 	Type         string // Go type as source string
 	JSONTag      string // json struct tag value (e.g. "name")
+	FormTag      string // form struct tag value: the form/query key when it differs from the json name
 	Validate     string // validate struct tag value (e.g. "required,min=1,max=255")
+	Format       string // format struct tag value: a time layout (e.g. "2006-01-02")
 	IsResourceID bool   // ResourceID or *ResourceID, including qualified forms
 	ImportAlias  string // qualifier for a qualified ResourceID
 	ImportPath   string // import path providing the qualified ResourceID
+	Squeeze      string // squeeze struct tag value (e.g. "ignore=unused_request_field")
+	Line         int    // source line of the field (for diagnostics)
 }
 
 // ScanRequests parses all Go files in a directory and extracts request struct definitions.
@@ -103,11 +107,15 @@ func ScanRequests(dir string) ([]RequestDef, error) {
 					rf := RequestField{
 						Name: field.Names[0].Name,
 						Type: exprToTypeString(field.Type),
+						Line: fset.Position(field.Pos()).Line,
 					}
 
 					if field.Tag != nil {
 						rf.JSONTag = extractTag(field.Tag.Value, "json")
+						rf.FormTag = extractTag(field.Tag.Value, "form")
 						rf.Validate = extractTag(field.Tag.Value, "validate")
+						rf.Format = extractTag(field.Tag.Value, "format")
+						rf.Squeeze = extractTag(field.Tag.Value, "squeeze")
 					}
 					rf.IsResourceID = isResourceIDType(rf.Type)
 					if rf.IsResourceID {
@@ -136,6 +144,10 @@ func ScanRequests(dir string) ([]RequestDef, error) {
 	return requests, nil
 }
 
+func isTimeType(typeName string) bool {
+	return strings.TrimPrefix(typeName, "*") == "time.Time"
+}
+
 func isResourceIDType(typeName string) bool {
 	typeName = strings.TrimPrefix(typeName, "*")
 	return typeName == "ResourceID" || strings.HasSuffix(typeName, ".ResourceID")
@@ -159,8 +171,8 @@ func extractTag(rawTag, name string) string {
 	}
 	val = val[:end]
 
-	// For json tags, strip options like ",omitempty"
-	if name == "json" {
+	// For json and form tags, strip options like ",omitempty"
+	if name == "json" || name == "form" {
 		if comma := strings.Index(val, ","); comma >= 0 {
 			val = val[:comma]
 		}
@@ -196,8 +208,66 @@ var bindingTemplate = template.Must(template.New("bindings").Funcs(template.Func
 		}
 		return field.Name
 	},
+	"hasFormat": func(request RequestDef) bool {
+		for _, field := range request.Fields {
+			if field.Format != "" {
+				return true
+			}
+		}
+		return false
+	},
+	"isPointer": func(field RequestField) bool {
+		return strings.HasPrefix(field.Type, "*")
+	},
+	"formFields": func(request RequestDef) []RequestField {
+		var fields []RequestField
+		for _, field := range request.Fields {
+			if field.JSONTag != "-" && field.FormTag != "-" {
+				fields = append(fields, field)
+			}
+		}
+		return fields
+	},
+	"formKey": func(field RequestField) string {
+		if field.FormTag != "" {
+			return field.FormTag
+		}
+		if field.JSONTag != "" {
+			return field.JSONTag
+		}
+		return field.Name
+	},
+	"formKind":   formFieldKind,
+	"lowerFirst": toLowerFirst,
+	"usesRawFields": func(request RequestDef) bool {
+		for _, field := range request.Fields {
+			if field.Format != "" || (field.IsResourceID && field.JSONTag != "-") {
+				return true
+			}
+		}
+		return false
+	},
 }).Parse(bindingTemplateSource))
 
+// formFieldKind names the generated formFieldKind constant that converts a
+// form or query-string value for a field of the given Go type.
+func formFieldKind(field RequestField) string {
+	switch strings.TrimPrefix(field.Type, "*") {
+	case "string":
+		return "formString"
+	case "bool":
+		return "formBool"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "formNumber"
+	case "[]string":
+		return "formStrings"
+	case "[]int", "[]int8", "[]int16", "[]int32", "[]int64", "[]uint", "[]uint16", "[]uint32", "[]uint64", "[]float32", "[]float64":
+		return "formNumbers"
+	default:
+		return "formValue"
+	}
+}
+
 const bindingTemplateSource = `// Code generated by Pickle. DO NOT EDIT.
 package {{ .Package }}
 
@@ -205,9 +275,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+{{- if .NeedsTime }}
+	"time"
+{{- end }}
 
 	"github.com/go-playground/validator/v10"
 {{ range .ResourceIDImports }}
@@ -250,7 +326,26 @@ func (e *BindingError) Error() string {
 	}
 	return strings.Join(msgs, "; ")
 }
+{{ if .HTTPAlias }}
+// validationErrorFormat is requests.error_format from pickle.yaml.
+const validationErrorFormat = {{ printf "%q" .ErrorFormat }}
+
+// Response renders the error in the configured format, keeping e.Status as
+// the response status.
+func (e *BindingError) Response() {{ .HTTPAlias }}.Response {
+	violations := make([]{{ .HTTPAlias }}.FieldViolation, len(e.Errors))
+	for i, ve := range e.Errors {
+		violations[i] = {{ .HTTPAlias }}.FieldViolation{Field: ve.Field, Message: ve.Message}
+	}
+	return {{ .HTTPAlias }}.ValidationErrorResponse(validationErrorFormat, e.Status, violations)
+}
 
+// MarshalJSON encodes the error in the configured format, so
+// ctx.JSON(bindErr.Status, bindErr) sends the same body as Response.
+func (e *BindingError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Response().Body)
+}
+{{ end }}
 func formatValidationErrors(err error) *BindingError {
 	ve, ok := err.(validator.ValidationErrors)
 	if !ok {
@@ -308,20 +403,154 @@ func toSnakeCase(s string) string {
 	}
 	return string(result)
 }
-{{ range .Requests }}
-// Bind{{ .Name }} deserializes and validates a {{ .Name }} from the HTTP request body.
-func Bind{{ .Name }}(r *http.Request) ({{ .Name }}, *BindingError) {
-	var req {{ .Name }}
-	body, err := io.ReadAll(r.Body)
+
+// formFieldKind says how a form or query-string value becomes JSON.
+type formFieldKind int
+
+const (
+	formString  formFieldKind = iota // JSON string
+	formValue                        // JSON string, or null when empty (times, UUIDs, Resource IDs)
+	formBool                         // true/false/1/0/on/off
+	formNumber                       // JSON number, or null when empty
+	formStrings                      // every submitted value, as a string array
+	formNumbers                      // every submitted value, as a number array
+)
+
+// formField maps a form or query-string key onto a request's JSON field.
+type formField struct {
+	Key  string
+	JSON string
+	Kind formFieldKind
+}
+
+// maxFormMemory is how much of a multipart body is held in memory; larger
+// file parts spill to temporary files.
+const maxFormMemory = 32 << 20
+
+// readRequestFields reads the request input as JSON: the body and its
+// top-level fields. Form posts (urlencoded or multipart) bind from the form
+// body and GET or HEAD requests without a Content-Type bind from the query
+// string; both are converted to JSON using fields. Anything else is read as a
+// JSON body.
+func readRequestFields(r *http.Request, fields []formField) ([]byte, map[string]json.RawMessage, *BindingError) {
+	invalid := &BindingError{Status: 400, Errors: []ValidationError{{ "{{" }}Field: "_body", Message: "invalid request body"}}}
+	var values url.Values
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, nil, invalid
+		}
+		values = r.PostForm
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+			return nil, nil, invalid
+		}
+		values = r.MultipartForm.Value
+	case mediaType == "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		values = r.URL.Query()
+	default:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, invalid
+		}
+		var rawFields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &rawFields); err != nil {
+			return nil, nil, invalid
+		}
+		return body, rawFields, nil
+	}
+
+	rawFields := map[string]json.RawMessage{}
+	for _, field := range fields {
+		submitted := values[field.Key]
+		if len(submitted) == 0 {
+			continue
+		}
+		raw, err := formValueJSON(submitted, field.Kind)
+		if err != nil {
+			return nil, nil, &BindingError{Status: 422, Errors: []ValidationError{{ "{{" }}Field: field.Key, Message: err.Error()}}}
+		}
+		rawFields[field.JSON] = raw
+	}
+	body, err := json.Marshal(rawFields)
 	if err != nil {
-		return req, &BindingError{Status: 400, Errors: []ValidationError{{ "{{" }}Field: "_body", Message: "invalid request body"}}}
+		return nil, nil, invalid
 	}
-	var rawFields map[string]json.RawMessage
-	if err := json.Unmarshal(body, &rawFields); err != nil {
-		return req, &BindingError{
-			Status: 400,
-			Errors: []ValidationError{{ "{{" }}Field: "_body", Message: "invalid request body"}},
+	return body, rawFields, nil
+}
+
+// formValueJSON converts the values submitted for one field to JSON. Scalar
+// fields take the first value.
+func formValueJSON(values []string, kind formFieldKind) (json.RawMessage, error) {
+	switch kind {
+	case formStrings:
+		return json.Marshal(values)
+	case formNumbers:
+		items := make([]json.RawMessage, 0, len(values))
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if !isFormNumber(value) {
+				return nil, fmt.Errorf("must be a list of numbers")
+			}
+			items = append(items, json.RawMessage(value))
 		}
+		return json.Marshal(items)
+	}
+
+	value := values[0]
+	trimmed := strings.TrimSpace(value)
+	switch kind {
+	case formBool:
+		switch strings.ToLower(trimmed) {
+		case "":
+			return json.RawMessage("null"), nil
+		case "on":
+			return json.RawMessage("true"), nil
+		case "off":
+			return json.RawMessage("false"), nil
+		}
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return json.RawMessage(strconv.FormatBool(b)), nil
+	case formNumber:
+		if trimmed == "" {
+			return json.RawMessage("null"), nil
+		}
+		if !isFormNumber(trimmed) {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return json.RawMessage(trimmed), nil
+	case formValue:
+		if trimmed == "" {
+			return json.RawMessage("null"), nil
+		}
+	}
+	return json.Marshal(value)
+}
+
+// isFormNumber reports whether s is a JSON number literal.
+func isFormNumber(s string) bool {
+	var n float64
+	return s != "null" && json.Unmarshal([]byte(s), &n) == nil
+}
+{{ range .Requests }}
+// {{ lowerFirst .Name }}FormFields maps form and query-string keys onto {{ .Name }}.
+var {{ lowerFirst .Name }}FormFields = []formField{
+{{- range formFields . }}
+	{Key: {{ printf "%q" (formKey .) }}, JSON: {{ printf "%q" (jsonName .) }}, Kind: {{ formKind . }}},
+{{- end }}
+}
+
+// Bind{{ .Name }} deserializes and validates a {{ .Name }} from a JSON body,
+// a form post, or the query string of a GET request.
+func Bind{{ .Name }}(r *http.Request) ({{ .Name }}, *BindingError) {
+	var req {{ .Name }}
+	body, {{ if usesRawFields . }}rawFields{{ else }}_{{ end }}, bindErr := readRequestFields(r, {{ lowerFirst .Name }}FormFields)
+	if bindErr != nil {
+		return req, bindErr
 	}
 	{{ range .Fields }}{{ if .IsResourceID }}{{ if ne .JSONTag "-" }}
 	if raw, ok := rawFields[{{ printf "%q" (jsonName .) }}]; ok {
@@ -331,9 +560,37 @@ func Bind{{ .Name }}(r *http.Request) ({{ .Name }}, *BindingError) {
 		}
 	}
 	{{ end }}{{ end }}{{ end }}
-	if err := json.Unmarshal(body, &req); err != nil {
+	{{- range .Fields }}{{ if .Format }}
+	var formatted{{ .Name }} {{ .Type }}
+	if raw, ok := rawFields[{{ printf "%q" (jsonName .) }}]; ok {
+		delete(rawFields, {{ printf "%q" (jsonName .) }})
+		if string(raw) != "null" {
+			var text string
+			if err := json.Unmarshal(raw, &text); err != nil {
+				return req, &BindingError{Status: 422, Errors: []ValidationError{{ "{{" }}Field: {{ printf "%q" (jsonName .) }}, Message: {{ printf "%q" (printf "must be a string in format %s" .Format) }}}}}
+			}
+			parsed, err := time.Parse({{ printf "%q" .Format }}, text)
+			if err != nil {
+				return req, &BindingError{Status: 422, Errors: []ValidationError{{ "{{" }}Field: {{ printf "%q" (jsonName .) }}, Message: {{ printf "%q" (printf "must match format %s" .Format) }}}}}
+			}
+			formatted{{ .Name }} = {{ if isPointer . }}&{{ end }}parsed
+		}
+	}
+	{{- end }}{{ end }}
+	{{- if hasFormat . }}
+	// Formatted fields were parsed above; drop them so encoding/json doesn't
+	// insist on RFC 3339.
+	var err error
+	if body, err = json.Marshal(rawFields); err != nil {
 		return req, &BindingError{Status: 400, Errors: []ValidationError{{ "{{" }}Field: "_body", Message: "invalid request body"}}}
 	}
+	{{- end }}
+	if err := {{ if $.HTTPAlias }}{{ $.HTTPAlias }}.DecodeJSON{{ else }}json.Unmarshal{{ end }}(body, &req); err != nil {
+		return req, &BindingError{Status: 400, Errors: []ValidationError{{ "{{" }}Field: "_body", Message: "invalid request body"}}}
+	}
+	{{- range .Fields }}{{ if .Format }}
+	req.{{ .Name }} = formatted{{ .Name }}
+	{{- end }}{{ end }}
 	if err := validate.Struct(req); err != nil {
 		return req, formatValidationErrors(err)
 	}
@@ -346,6 +603,15 @@ type bindingTemplateData struct {
 	Package           string
 	Requests          []RequestDef
 	ResourceIDImports []requestImport
+	NeedsTime         bool
+	HTTPAlias         string
+	ErrorFormat       string
+}
+
+// BindingOptions configures how generated bindings report binding errors.
+type BindingOptions struct {
+	HTTPImport  string // import path of the app's HTTP package; empty omits BindingError.Response
+	ErrorFormat string // requests.error_format from pickle.yaml; empty means "errors"
 }
 
 type requestImport struct {
@@ -354,10 +620,17 @@ type requestImport struct {
 }
 
 // GenerateBindings produces a Go source file with Bind functions for each request struct.
-func GenerateBindings(requests []RequestDef, packageName string) ([]byte, error) {
+func GenerateBindings(requests []RequestDef, packageName string, opts BindingOptions) ([]byte, error) {
 	importPaths := map[string]string{}
+	needsTime := false
 	for _, request := range requests {
 		for _, field := range request.Fields {
+			if field.Format != "" {
+				if !isTimeType(field.Type) {
+					return nil, fmt.Errorf("%s.%s: format tag requires a time.Time or *time.Time field, got %s", request.Name, field.Name, field.Type)
+				}
+				needsTime = true
+			}
 			if field.IsResourceID && field.ImportAlias != "" && field.ImportPath != "" {
 				if existing := importPaths[field.ImportAlias]; existing != "" && existing != field.ImportPath {
 					return nil, fmt.Errorf("ResourceID import alias %q resolves to both %q and %q", field.ImportAlias, existing, field.ImportPath)
@@ -366,6 +639,23 @@ func GenerateBindings(requests []RequestDef, packageName string) ([]byte, error)
 			}
 		}
 	}
+	httpAlias := ""
+	if opts.HTTPImport != "" {
+		httpAlias = "pickle"
+		for alias, path := range importPaths {
+			if path == opts.HTTPImport {
+				httpAlias = alias
+			}
+		}
+		if existing := importPaths[httpAlias]; existing != "" && existing != opts.HTTPImport {
+			return nil, fmt.Errorf("ResourceID import alias %q resolves to %q, which the generated bindings need for %q", httpAlias, existing, opts.HTTPImport)
+		}
+		importPaths[httpAlias] = opts.HTTPImport
+	}
+	errorFormat := opts.ErrorFormat
+	if errorFormat == "" {
+		errorFormat = "errors"
+	}
 	aliases := make([]string, 0, len(importPaths))
 	for alias := range importPaths {
 		aliases = append(aliases, alias)
@@ -379,6 +669,9 @@ func GenerateBindings(requests []RequestDef, packageName string) ([]byte, error)
 		Package:           packageName,
 		Requests:          requests,
 		ResourceIDImports: resourceIDImports,
+		NeedsTime:         needsTime,
+		HTTPAlias:         httpAlias,
+		ErrorFormat:       errorFormat,
 	}
 
 	var buf bytes.Buffer
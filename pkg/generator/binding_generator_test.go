@@ -2,6 +2,7 @@ package generator
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -77,7 +78,7 @@ func TestGenerateBindings(t *testing.T) {
 		t.Fatalf("ScanRequests: %v", err)
 	}
 
-	out, err := GenerateBindings(requests, "requests")
+	out, err := GenerateBindings(requests, "requests", BindingOptions{})
 	if err != nil {
 		t.Fatalf("GenerateBindings: %v", err)
 	}
@@ -125,7 +126,7 @@ func TestGenerateBindingsResourceIDFields(t *testing.T) {
 			{Name: "ParentID", Type: "*pickle.ResourceID", JSONTag: "parent_id", Validate: "omitempty,resource_id", IsResourceID: true, ImportAlias: "pickle", ImportPath: "example.com/app/http"},
 		},
 	}}
-	out, err := GenerateBindings(requests, "requests")
+	out, err := GenerateBindings(requests, "requests", BindingOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -190,6 +191,8 @@ func TestExtractTag(t *testing.T) {
 		{"`json:\"email\" validate:\"required,email,max=255\"`", "validate", "required,email,max=255"},
 		{"`json:\"-\"`", "json", "-"},
 		{"`db:\"id\"`", "json", ""},
+		{"`json:\"tags\" form:\"tag,omitempty\"`", "form", "tag"},
+		{"`json:\"at\" format:\"2006-01-02\"`", "form", ""},
 	}
 
 	for _, tt := range tests {
@@ -199,3 +202,299 @@ func TestExtractTag(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateBindingsFormatTagParsesDates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs generated bindings")
+	}
+	requests := []RequestDef{{
+		Name: "CreateProfileRequest",
+		Fields: []RequestField{
+			{Name: "Name", Type: "string", JSONTag: "name", Validate: "required"},
+			{Name: "Birthdate", Type: "time.Time", JSONTag: "birthdate", Validate: "required", Format: "2006-01-02"},
+		},
+	}}
+	out, err := GenerateBindings(requests, "main", BindingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The generated file needs a module that provides validator, so build it
+	// inside this one.
+	dir, err := os.MkdirTemp(".", "_bindtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := `package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+type CreateProfileRequest struct {
+	Name      string    ` + "`" + `json:"name" validate:"required"` + "`" + `
+	Birthdate time.Time ` + "`" + `json:"birthdate" validate:"required" format:"2006-01-02"` + "`" + `
+}
+
+func main() {
+	for _, body := range []string{
+		` + "`" + `{"name":"Ada","birthdate":"1815-12-10"}` + "`" + `,
+		` + "`" + `{"name":"Ada","birthdate":"1815-13-45"}` + "`" + `,
+	} {
+		req, bindErr := BindCreateProfileRequest(httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		if bindErr != nil {
+			fmt.Printf("%d %s\n", bindErr.Status, bindErr.Error())
+			continue
+		}
+		fmt.Printf("ok %s\n", req.Birthdate.Format("2006-01-02"))
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bindings_gen.go"), out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	want := []string{
+		"ok 1815-12-10",
+		"422 birthdate: must match format 2006-01-02",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestGenerateBindingsBindsFormsAndQueryStrings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs generated bindings")
+	}
+	requests := []RequestDef{{
+		Name: "SearchRequest",
+		Fields: []RequestField{
+			{Name: "Query", Type: "string", JSONTag: "q", Validate: "required"},
+			{Name: "Page", Type: "int", JSONTag: "page"},
+			{Name: "Published", Type: "*bool", JSONTag: "published"},
+			{Name: "Tags", Type: "[]string", JSONTag: "tags", FormTag: "tag"},
+		},
+	}}
+	out, err := GenerateBindings(requests, "main", BindingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp(".", "_bindtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := `package main
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+type SearchRequest struct {
+	Query     string   ` + "`" + `json:"q" validate:"required"` + "`" + `
+	Page      int      ` + "`" + `json:"page"` + "`" + `
+	Published *bool    ` + "`" + `json:"published"` + "`" + `
+	Tags      []string ` + "`" + `json:"tags" form:"tag"` + "`" + `
+}
+
+func form(body string) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func main() {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("q", "pickle")
+	mw.WriteField("tag", "go")
+	mw.Close()
+	multi := httptest.NewRequest("POST", "/", &buf)
+	multi.Header.Set("Content-Type", mw.FormDataContentType())
+
+	for _, r := range []*http.Request{
+		form("q=gherkin&page=2&published=on&tag=a&tag=b"),
+		multi,
+		httptest.NewRequest("GET", "/?q=dill&page=&tag=x", nil),
+		httptest.NewRequest("GET", "/?page=1", nil),
+		form("q=brine&page=two"),
+		httptest.NewRequest("POST", "/", strings.NewReader(` + "`" + `{"q":"json","tags":["j"]}` + "`" + `)),
+	} {
+		req, bindErr := BindSearchRequest(r)
+		if bindErr != nil {
+			fmt.Printf("%d %s\n", bindErr.Status, bindErr.Error())
+			continue
+		}
+		published := "nil"
+		if req.Published != nil {
+			published = fmt.Sprint(*req.Published)
+		}
+		fmt.Printf("ok %s %d %s %v\n", req.Query, req.Page, published, req.Tags)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bindings_gen.go"), out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	want := []string{
+		"ok gherkin 2 true [a b]",
+		"ok pickle 0 nil [go]",
+		"ok dill 0 nil [x]",
+		"422 query: is required",
+		"422 page: must be a number",
+		"ok json 0 nil [j]",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestGenerateBindingsFormatTagRequiresTimeField(t *testing.T) {
+	requests := []RequestDef{{
+		Name:   "CreateProfileRequest",
+		Fields: []RequestField{{Name: "Nickname", Type: "string", JSONTag: "nickname", Format: "2006-01-02"}},
+	}}
+	if _, err := GenerateBindings(requests, "requests", BindingOptions{}); err == nil || !strings.Contains(err.Error(), "format tag requires a time.Time") {
+		t.Fatalf("expected format/type error, got %v", err)
+	}
+}
+
+func TestGenerateBindingsRendersConfiguredErrorFormat(t *testing.T) {
+	requests := []RequestDef{{
+		Name:   "CreateUserRequest",
+		Fields: []RequestField{{Name: "Email", Type: "string", JSONTag: "email", Validate: "required,email"}},
+	}}
+	out, err := GenerateBindings(requests, "requests", BindingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "func (e *BindingError) Response()") || !strings.Contains(string(out), "json.Unmarshal(body, &req)") {
+		t.Error("bindings without an HTTP import should not render or decode through the HTTP package")
+	}
+
+	out, err = GenerateBindings(requests, "requests", BindingOptions{HTTPImport: "example.com/app/app/http", ErrorFormat: "problem"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		`pickle "example.com/app/app/http"`,
+		`const validationErrorFormat = "problem"`,
+		`func (e *BindingError) Response() pickle.Response {`,
+		`pickle.ValidationErrorResponse(validationErrorFormat, e.Status, violations)`,
+		`func (e *BindingError) MarshalJSON() ([]byte, error) {`,
+		`if err := pickle.DecodeJSON(body, &req); err != nil {`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+
+	// A ResourceID import of the same package is reused rather than imported twice.
+	requests[0].Fields = append(requests[0].Fields, RequestField{Name: "TeamID", Type: "web.ResourceID", JSONTag: "team_id", Validate: "required,resource_id", IsResourceID: true, ImportAlias: "web", ImportPath: "example.com/app/app/http"})
+	out, err = GenerateBindings(requests, "requests", BindingOptions{HTTPImport: "example.com/app/app/http"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = string(out)
+	if strings.Count(src, `"example.com/app/app/http"`) != 1 || !strings.Contains(src, "func (e *BindingError) Response() web.Response {") || !strings.Contains(src, `const validationErrorFormat = "errors"`) {
+		t.Errorf("expected a single web import and the default format:\n%s", src)
+	}
+}
+
+func TestGenerateBindingsErrorFormatKeepsStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs generated bindings")
+	}
+	requests := []RequestDef{{
+		Name:   "CreateUserRequest",
+		Fields: []RequestField{{Name: "Email", Type: "string", JSONTag: "email", Validate: "required,email"}},
+	}}
+	out, err := GenerateBindings(requests, "main", BindingOptions{HTTPImport: "github.com/shortontech/pickle/pkg/cooked", ErrorFormat: "jsonapi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp(".", "_bindtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+)
+
+type CreateUserRequest struct {
+	Email string ` + "`" + `json:"email" validate:"required,email"` + "`" + `
+}
+
+func main() {
+	for _, body := range []string{` + "`" + `{"email":"nope"}` + "`" + `, ` + "`" + `{` + "`" + `} {
+		_, bindErr := BindCreateUserRequest(httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		rec := httptest.NewRecorder()
+		bindErr.Response().Write(rec)
+		encoded, _ := json.Marshal(bindErr)
+		fmt.Printf("%d %s %t\n", rec.Code, rec.Header().Get("Content-Type"), strings.TrimSpace(rec.Body.String()) == string(encoded))
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bindings_gen.go"), out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	want := "422 application/vnd.api+json true\n400 application/vnd.api+json true"
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BrokenMigration is a migration file that doesn't parse or compile.
+type BrokenMigration struct {
+	File   string   // path relative to the project directory
+	Errors []string // "line:col: message", in the order the compiler reported them
+}
+
+// BrokenMigrationsError is returned by the schema inspector when migration
+// files don't compile. It names each file instead of passing on the
+// compiler's output for the whole package.
+type BrokenMigrationsError struct {
+	Migrations []BrokenMigration
+}
+
+func (e *BrokenMigrationsError) Error() string {
+	var b strings.Builder
+	b.WriteString("migrations do not compile:")
+	for _, m := range e.Migrations {
+		b.WriteString("\n  " + m.File)
+		for _, msg := range m.Errors {
+			b.WriteString("\n    " + msg)
+		}
+	}
+	b.WriteString("\nfix them, or run pickle generate --skip-broken to generate from the remaining migrations")
+	return b.String()
+}
+
+// migrationDirs returns the project's migration directories.
+func migrationDirs(project *Project) []string {
+	if len(project.Layout.MigrationDirs) == 0 {
+		return []string{project.Layout.MigrationsDir}
+	}
+	var dirs []string
+	for _, md := range project.Layout.MigrationDirs {
+		dirs = append(dirs, md.Dir)
+	}
+	return dirs
+}
+
+// syntaxBrokenMigrations parses every Go file in dirs and returns those with
+// syntax errors.
+func syntaxBrokenMigrations(projectDir string, dirs []string) []BrokenMigration {
+	var broken []BrokenMigration
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			_, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err == nil {
+				continue
+			}
+			m := BrokenMigration{File: relToProject(projectDir, path)}
+			var list scanner.ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					m.Errors = append(m.Errors, fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Msg))
+				}
+			} else {
+				m.Errors = append(m.Errors, err.Error())
+			}
+			broken = append(broken, m)
+		}
+	}
+	return broken
+}
+
+// compilerError matches a go build error line: "path/file.go:12:3: message".
+var compilerError = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// compileBrokenMigrations picks the migration files out of go build output.
+// Errors in generated files (_gen.go) and files outside dirs are ignored:
+// skipping them wouldn't let the inspector compile.
+func compileBrokenMigrations(projectDir string, dirs []string, output []byte) []BrokenMigration {
+	inDir := make(map[string]bool)
+	for _, dir := range dirs {
+		inDir[filepath.Clean(dir)] = true
+	}
+	var broken []BrokenMigration
+	index := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		match := compilerError.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		path := match[1]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		if !inDir[filepath.Dir(path)] || strings.HasSuffix(path, "_gen.go") {
+			continue
+		}
+		pos := match[2]
+		if match[3] != "" {
+			pos += ":" + match[3]
+		}
+		file := relToProject(projectDir, path)
+		i, ok := index[file]
+		if !ok {
+			i = len(broken)
+			index[file] = i
+			broken = append(broken, BrokenMigration{File: file})
+		}
+		broken[i].Errors = append(broken[i].Errors, pos+": "+match[4])
+	}
+	return broken
+}
+
+// writeSkipOverlay writes a go build -overlay file that deletes the skipped
+// migrations, and the migration registries that reference them, from the
+// inspector's build. It returns the overlay's path.
+func writeSkipOverlay(tmpDir string, dirs []string, skipped map[string]bool) (string, error) {
+	replace := make(map[string]string)
+	for path := range skipped {
+		replace[path] = ""
+	}
+	for _, dir := range dirs {
+		registry := filepath.Join(dir, "registry_gen.go")
+		if _, err := os.Stat(registry); err == nil {
+			replace[registry] = ""
+		}
+	}
+	data, err := json.Marshal(map[string]any{"Replace": replace})
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(tmpDir, "overlay.json")
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// skipBrokenMigrations adds broken to skipped, warning about each file.
+func skipBrokenMigrations(projectDir string, skipped map[string]bool, broken []BrokenMigration) {
+	for _, m := range broken {
+		skipped[filepath.Join(projectDir, m.File)] = true
+		msg := ""
+		if len(m.Errors) > 0 {
+			msg = ": " + m.Errors[0]
+		}
+		fmt.Fprintf(os.Stderr, "  skipping broken migration %s%s\n", m.File, msg)
+	}
+}
+
+func relToProject(projectDir, path string) string {
+	if rel, err := filepath.Rel(projectDir, path); err == nil {
+		return rel
+	}
+	return path
+}
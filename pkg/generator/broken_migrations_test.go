@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileBrokenMigrationsGroupsErrorsByFile(t *testing.T) {
+	project := "/app"
+	dirs := []string{"/app/database/migrations"}
+	output := []byte(`# example.com/app/database/migrations
+database/migrations/2026_01_01_000000_create_posts_table.go:10:5: t.Strin undefined (type *Table has no field or method Strin)
+database/migrations/2026_01_02_000000_create_tags_table.go:8:2: undefined: Foo
+database/migrations/2026_01_01_000000_create_posts_table.go:12:3: undefined: Bar
+database/migrations/registry_gen.go:7:15: undefined: CreateUsersTable_2026_01_01_000000
+app/models/user.go:3:1: unrelated
+`)
+	got := compileBrokenMigrations(project, dirs, output)
+	want := []BrokenMigration{
+		{File: "database/migrations/2026_01_01_000000_create_posts_table.go", Errors: []string{
+			"10:5: t.Strin undefined (type *Table has no field or method Strin)",
+			"12:3: undefined: Bar",
+		}},
+		{File: "database/migrations/2026_01_02_000000_create_tags_table.go", Errors: []string{"8:2: undefined: Foo"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compileBrokenMigrations =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestSyntaxBrokenMigrationsReportsUnparseableFiles(t *testing.T) {
+	dir := t.TempDir()
+	migrations := filepath.Join(dir, "database", "migrations")
+	if err := os.MkdirAll(migrations, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"2026_01_01_000000_create_users_table.go": "package migrations\n\ntype CreateUsersTable_2026_01_01_000000 struct{ Migration }\n",
+		"2026_01_02_000000_create_tags_table.go":  "package migrations\n\nfunc (m *X) Up() {\n\tm.CreateTable(\"tags\", func(t *Table) {\n\t\tt.UUID(\"id\").PrimaryKey(\n\t})\n}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(migrations, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	broken := syntaxBrokenMigrations(dir, []string{migrations})
+	if len(broken) != 1 || broken[0].File != filepath.Join("database", "migrations", "2026_01_02_000000_create_tags_table.go") || len(broken[0].Errors) == 0 {
+		t.Fatalf("syntaxBrokenMigrations = %#v", broken)
+	}
+
+	// The registry scan leaves skipped files out instead of failing on them.
+	skip := map[string]bool{filepath.Join(migrations, "2026_01_02_000000_create_tags_table.go"): true}
+	entries, err := scanMigrationFiles(migrations, skip)
+	if err != nil {
+		t.Fatalf("scanMigrationFiles: %v", err)
+	}
+	if len(entries) != 1 || entries[0].StructName != "CreateUsersTable_2026_01_01_000000" {
+		t.Errorf("entries = %#v", entries)
+	}
+	if _, err := ScanMigrationFiles(migrations); err == nil {
+		t.Error("expected ScanMigrationFiles to fail on the broken file")
+	}
+}
+
+func TestBrokenMigrationsErrorNamesFilesAndSuggestsSkip(t *testing.T) {
+	err := &BrokenMigrationsError{Migrations: []BrokenMigration{
+		{File: "database/migrations/a.go", Errors: []string{"3:1: undefined: Foo"}},
+	}}
+	msg := err.Error()
+	for _, want := range []string{"database/migrations/a.go", "3:1: undefined: Foo", "--skip-broken"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q missing %q", msg, want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -93,6 +94,7 @@ func findCommandTypes(f *ast.File) []string {
 			result = append(result, name)
 		}
 	}
+	sort.Strings(result)
 	return result
 }
 
@@ -254,18 +256,20 @@ import (
 	{{ if .HasSeeders }}"crypto/rand"
 	"encoding/binary"
 	"flag"
-	"fmt"
 	"strings"
+	"time"
+	{{ end }}{{ if or .HasSeeders .HasSchedule }}"context"
+	{{ end }}{{ if .HasSchedule }}"os"
+	"os/signal"
+	"syscall"
 	{{ end }}
-	"context"
+	"database/sql"
+	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"time"
 
 	pickle "{{ .HTTPImport }}"
-	"{{ .ModelsImport }}"
+	models "{{ .ModelsImport }}"
 	"{{ .MigrationsImport }}"
 	"{{ .ConfigImport }}"
 	"{{ .RoutesImport }}"
@@ -284,6 +288,9 @@ func (c migrateCommand) Name() string        { return "migrate" }
 func (c migrateCommand) Description() string { return "Run pending migrations" }
 func (c migrateCommand) Run(args []string) error {
 	runner := migrations.NewRunner(models.DB, config.Database.Connection().Driver)
+	for _, argument := range args {
+		if argument == "--strict" { runner.StrictChecksums = true }
+	}
 	if err := runner.Migrate(migrations.Registry); err != nil { return err }
 {{ if .HasPolicies }}	policyRunner := policies.NewPolicyRunner(models.DB, config.Database.Connection().Driver)
 	return policyRunner.Migrate(policies.PolicyRegistry)
@@ -334,6 +341,9 @@ func (c migrateStatusCommand) Run(args []string) error {
 	if err != nil {
 		return err
 	}
+	for _, argument := range args {
+		if argument == "--json" { return migrations.PrintStatusJSON(statuses) }
+	}
 	migrations.PrintStatus(statuses)
 {{ if .HasPolicies }}	policyRunner := policies.NewPolicyRunner(models.DB, config.Database.Connection().Driver)
 	policyStatuses, err := policyRunner.Status(policies.PolicyRegistry)
@@ -346,6 +356,25 @@ func (c migrateStatusCommand) Run(args []string) error {
 	return nil
 }
 
+// migrateSQLCommand writes each migration's SQL to files for review.
+type migrateSQLCommand struct{}
+
+func (c migrateSQLCommand) Name() string        { return "migrate:sql" }
+func (c migrateSQLCommand) Description() string { return "Write each migration's up and down SQL to database/migrations/sql" }
+func (c migrateSQLCommand) Run(args []string) error {
+	runner := migrations.NewRunner(models.DB, config.Database.Connection().Driver)
+	rendered, err := runner.RenderSQL(migrations.Registry)
+	if err != nil {
+		return err
+	}
+	written, err := migrations.WriteSQLFiles("database/migrations/sql", rendered)
+	if err != nil {
+		return err
+	}
+	for _, path := range written { fmt.Println("  wrote " + path) }
+	return nil
+}
+
 {{ if .HasPolicies }}type policiesStatusCommand struct{}
 func (c policiesStatusCommand) Name() string { return "policies:status" }
 func (c policiesStatusCommand) Description() string { return "Show role policy and generated row-policy status" }
@@ -464,6 +493,7 @@ func BuiltinCommands() []pickle.Command {
 		migrateRollbackCommand{},
 		migrateFreshCommand{},
 		migrateStatusCommand{},
+		migrateSQLCommand{},
 {{ if .HasPolicies }}		policiesStatusCommand{},
 		policiesRollbackCommand{},
 		rlsStatusCommand{},
@@ -491,6 +521,10 @@ func NewApp() *pickle.App {
 			config.Init()
 			models.DB = config.Database.Open()
 			models.DatabaseDriver = config.Database.Connection().Driver
+			models.SetConnectionOpener(func(name string) (*sql.DB, error) {
+				if name == config.Database.Default { return models.DB, nil }
+				return config.Database.OpenConnection(name)
+			})
 {{ if .HasAuth }}			auth.Init(config.Env, models.DB)
 {{ if .HasPolicies }}			pickle.RegisterHTTPPolicyAuthenticator(func(r *http.Request) (any, *pickle.AuthInfo, error) {
 				source, present, err := auth.TryAuthenticatePolicySource(r)
@@ -505,27 +539,21 @@ func NewApp() *pickle.App {
 {{ end }}
 {{ end }}		},
 		func() {
-{{ if .HasSchedule }}			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+{{ if .HasSchedule }}			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 			defer stop()
 
 			// Start the scheduler in a background goroutine
 			go schedule.Schedule.Start(ctx)
-{{ else }}			_, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-			defer stop()
-
 {{ end }}
 			mux := http.NewServeMux()
 {{ range .RouteVars }}			routes.{{ . }}.RegisterRoutes(mux)
 {{ end }}			log.Printf("listening on :%s", config.App.Port)
 			srv := &http.Server{
-				Addr:              ":" + config.App.Port,
-				Handler:           mux,
-				ReadHeaderTimeout: 10 * time.Second,
-				ReadTimeout:       30 * time.Second,
-				WriteTimeout:      60 * time.Second,
-				IdleTimeout:       120 * time.Second,
+				Addr:    ":" + config.App.Port,
+				Handler: pickle.CanonicalPaths(mux),
 			}
-			if err := srv.ListenAndServe(); err != nil {
+			// Drains in-flight requests on SIGINT/SIGTERM.
+			if err := pickle.ServeGraceful(srv); err != nil {
 				log.Fatal(err)
 			}
 		},
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -17,18 +18,25 @@ import (
 // ConfigDef describes a config function discovered in config/*.go.
 type ConfigDef struct {
 	FuncName   string // unexported function name, e.g. "database"
-	ReturnType string // struct type name, e.g. "DatabaseConfig"
+	ReturnType string // Go type expression, e.g. "DatabaseConfig" or "map[string]QueueConfig"
 	VarName    string // exported var name, e.g. "Database"
 }
 
 // ConfigScanResult holds everything discovered from config/*.go.
 type ConfigScanResult struct {
 	Configs           []ConfigDef
-	HasDatabaseConfig bool // user defined DatabaseConfig struct
+	Imports           []string    // import specs needed by qualified return types, e.g. `"myapp/queue"`
+	HasDatabaseConfig bool        // user defined DatabaseConfig struct
+	RequiredEnv       []string    // Env keys read with an empty fallback by the database config
+	ConnectionEnv     []DriverEnv // per-connection requirements, chosen by ConnectionSwitch
+	ConnectionSwitch  string      // Env key naming the default connection, e.g. "DB_CONNECTION"
+	ConnectionDefault string      // ConnectionSwitch's fallback, e.g. "pgsql"
+	DriverEnv         []DriverEnv // per-auth-driver requirements, set by the caller
 }
 
 // ScanConfigs parses Go files in configDir and finds unexported functions
-// that return a struct type ending in "Config". Also detects known struct types.
+// that return a type ending in "Config" — a named or package-qualified type,
+// or a slice or map of one. Also detects known struct types.
 func ScanConfigs(configDir string) (*ConfigScanResult, error) {
 	entries, err := os.ReadDir(configDir)
 	if err != nil {
@@ -36,6 +44,7 @@ func ScanConfigs(configDir string) (*ConfigScanResult, error) {
 	}
 
 	result := &ConfigScanResult{}
+	imports := map[string]bool{}
 
 	for _, e := range entries {
 		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") ||
@@ -74,26 +83,200 @@ func ScanConfigs(configDir string) (*ConfigScanResult, error) {
 			if fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
 				continue
 			}
-			retType, ok := fn.Type.Results.List[0].Type.(*ast.Ident)
-			if !ok || !strings.HasSuffix(retType.Name, "Config") {
+			retType := fn.Type.Results.List[0].Type
+			named := configElemType(retType)
+			if named == nil {
 				continue
 			}
+			if sel, ok := named.(*ast.SelectorExpr); ok {
+				spec := importSpecFor(f, sel.X.(*ast.Ident).Name)
+				if spec == "" {
+					continue
+				}
+				imports[spec] = true
+			}
 
 			result.Configs = append(result.Configs, ConfigDef{
 				FuncName:   fn.Name.Name,
-				ReturnType: retType.Name,
+				ReturnType: exprString(retType),
 				VarName:    exportName(fn.Name.Name),
 			})
+			if ident, ok := retType.(*ast.Ident); ok && ident.Name == "DatabaseConfig" {
+				scanDatabaseEnv(fn.Body, result)
+			}
 		}
 	}
+	for spec := range imports {
+		result.Imports = append(result.Imports, spec)
+	}
+	sort.Strings(result.Imports)
 
 	sort.Slice(result.Configs, func(i, j int) bool {
 		return result.Configs[i].VarName < result.Configs[j].VarName
 	})
+	sort.Strings(result.RequiredEnv)
+	result.RequiredEnv = dedupeSorted(result.RequiredEnv)
 
 	return result, nil
 }
 
+// configElemType returns the named type a config function returns — directly,
+// as a slice element, or as a map value — when its name ends in "Config".
+// Qualified types are returned as the *ast.SelectorExpr. Anything else is nil.
+func configElemType(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if strings.HasSuffix(t.Name, "Config") {
+			return t
+		}
+	case *ast.SelectorExpr:
+		if _, ok := t.X.(*ast.Ident); ok && strings.HasSuffix(t.Sel.Name, "Config") {
+			return t
+		}
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return configElemType(t.Elt)
+		}
+	case *ast.MapType:
+		return configElemType(t.Value)
+	}
+	return nil
+}
+
+// importSpecFor returns the import spec in f that binds pkgName, keeping any
+// alias, or "" when the file imports no such package.
+func importSpecFor(f *ast.File, pkgName string) string {
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == pkgName {
+				return imp.Name.Name + " " + imp.Path.Value
+			}
+			continue
+		}
+		if path[strings.LastIndex(path, "/")+1:] == pkgName {
+			return imp.Path.Value
+		}
+	}
+	return ""
+}
+
+// scanDatabaseEnv records the env keys a DatabaseConfig function needs. When
+// Default reads the connection name as Env("KEY", "name"), keys inside a
+// Connections entry are only required while that connection is selected, so
+// an app on sqlite is not asked for DB_HOST. A literal Default keeps only its
+// own entry's keys; any other Default requires them all.
+func scanDatabaseEnv(body *ast.BlockStmt, result *ConfigScanResult) {
+	var always []string
+	conns := map[string][]string{}
+	literal, hasSwitch := "", false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			if ident, ok := n.Key.(*ast.Ident); ok && ident.Name == "Default" {
+				if call, ok := n.Value.(*ast.CallExpr); ok && isEnvCall(call) {
+					key, _ := stringArg(call.Args[0])
+					def, _ := stringArg(call.Args[1])
+					result.ConnectionSwitch, result.ConnectionDefault, hasSwitch = key, def, key != ""
+				} else if name, ok := stringArg(n.Value); ok {
+					literal = name
+				}
+			}
+			name, ok := stringArg(n.Key)
+			if _, isLit := n.Value.(*ast.CompositeLit); ok && isLit {
+				conns[name] = append(conns[name], requiredEnvKeys(n.Value)...)
+				return false
+			}
+		case *ast.CallExpr:
+			if key, ok := requiredEnvKey(n); ok {
+				always = append(always, key)
+			}
+		}
+		return true
+	})
+
+	result.RequiredEnv = append(result.RequiredEnv, always...)
+	for name, keys := range conns {
+		switch {
+		case hasSwitch:
+			if len(keys) > 0 {
+				sort.Strings(keys)
+				result.ConnectionEnv = append(result.ConnectionEnv, DriverEnv{Name: name, Vars: dedupeSorted(keys)})
+			}
+		case literal != "":
+			if name == literal {
+				result.RequiredEnv = append(result.RequiredEnv, keys...)
+			}
+		default:
+			result.RequiredEnv = append(result.RequiredEnv, keys...)
+		}
+	}
+	sort.Slice(result.ConnectionEnv, func(i, j int) bool {
+		return result.ConnectionEnv[i].Name < result.ConnectionEnv[j].Name
+	})
+}
+
+// requiredEnvKeys finds Env("KEY", "") calls — values the config reads with
+// no usable default. Passwords are skipped: an empty password is a valid
+// local setup (trust auth, sqlite), not a missing value.
+func requiredEnvKeys(node ast.Node) []string {
+	var keys []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if key, ok := requiredEnvKey(call); ok {
+				keys = append(keys, key)
+			}
+		}
+		return true
+	})
+	return keys
+}
+
+// requiredEnvKey returns KEY when call is Env("KEY", "") for a non-password key.
+func requiredEnvKey(call *ast.CallExpr) (string, bool) {
+	if !isEnvCall(call) {
+		return "", false
+	}
+	name, ok := stringArg(call.Args[0])
+	if !ok || strings.Contains(name, "PASSWORD") {
+		return "", false
+	}
+	fallback, ok := stringArg(call.Args[1])
+	if !ok || fallback != "" {
+		return "", false
+	}
+	return name, true
+}
+
+// isEnvCall reports whether call is a two-argument Env(...) call.
+func isEnvCall(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "Env" && len(call.Args) == 2
+}
+
+// stringArg unquotes expr when it is a string literal.
+func stringArg(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+func dedupeSorted(s []string) []string {
+	var out []string
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // exportName capitalizes the first letter of a name.
 func exportName(s string) string {
 	if s == "" {
@@ -105,12 +288,25 @@ func exportName(s string) string {
 // GenerateConfigGlue produces config/pickle_gen.go with Env(), public vars,
 // Init(), and database helper methods if applicable.
 func GenerateConfigGlue(scan *ConfigScanResult, packageName string) ([]byte, error) {
+	embed := strings.ReplaceAll(embedCONFIG, packagePlaceholder, packageName)
+	for _, spec := range scan.Imports {
+		if !strings.Contains(embed, "\t"+spec+"\n") {
+			embed = strings.Replace(embed, "import (\n", "import (\n\t"+spec+"\n", 1)
+		}
+	}
 	data := configTemplateData{
 		Package:      packageName,
 		Configs:      scan.Configs,
-		Embed:        strings.ReplaceAll(embedCONFIG, packagePlaceholder, packageName),
+		Embed:        embed,
 		HasDBMethods: scan.HasDatabaseConfig,
+		RequiredEnv:  scan.RequiredEnv,
+		DriverEnv:    scan.DriverEnv,
+
+		ConnectionEnv:     scan.ConnectionEnv,
+		ConnectionSwitch:  scan.ConnectionSwitch,
+		ConnectionDefault: scan.ConnectionDefault,
 	}
+	data.ValidatesEnv = len(data.RequiredEnv) > 0 || len(data.DriverEnv) > 0 || len(data.ConnectionEnv) > 0
 
 	var buf bytes.Buffer
 	if err := configTemplate.Execute(&buf, data); err != nil {
@@ -130,6 +326,13 @@ type configTemplateData struct {
 	Configs      []ConfigDef
 	Embed        string
 	HasDBMethods bool
+	RequiredEnv  []string
+	DriverEnv    []DriverEnv
+	ValidatesEnv bool
+
+	ConnectionEnv     []DriverEnv
+	ConnectionSwitch  string
+	ConnectionDefault string
 }
 
 var configTemplate = template.Must(template.New("config").Parse(configTemplateSource))
@@ -143,9 +346,62 @@ var {{ .VarName }} {{ .ReturnType }}
 // Init loads configuration by calling each config function.
 // Call this at the start of main().
 func Init() {
-{{ range .Configs }}	{{ .VarName }} = {{ .FuncName }}()
-{{ end }}}
-{{ if .HasDBMethods }}
+{{ if .ValidatesEnv }}	if err := ValidateEnv(); err != nil {
+		log.Fatal(err)
+	}
+{{ end }}{{ range .Configs }}	{{ .VarName }} = {{ .FuncName }}()
+{{ end }}	registerConfigVars()
+}
+
+// registerConfigVars makes the config vars reachable through Get.
+func registerConfigVars() {
+	setConfigVars(map[string]any{
+{{ range .Configs }}		"{{ .FuncName }}": {{ .VarName }},
+{{ end }}	})
+}
+
+// Reload re-reads .env and re-runs every config function, for example from a
+// SIGHUP handler, and returns the names of the vars whose value changed.
+{{- if .ValidatesEnv }} If
+// a required variable is now missing, nothing is reloaded.{{ end }} Get sees
+// the new values at once; code that copied a var before the reload keeps the
+// old value, and reading the vars directly while Reload runs is a data race.
+func Reload() ([]string, error) {
+	reloadEnv()
+{{ if .ValidatesEnv }}	if err := ValidateEnv(); err != nil {
+		return nil, err
+	}
+{{ end }}	var changed []string
+{{ range .Configs }}	if next := {{ .FuncName }}(); !reflect.DeepEqual(next, {{ .VarName }}) {
+		{{ .VarName }} = next
+		changed = append(changed, "{{ .VarName }}")
+	}
+{{ end }}	registerConfigVars()
+	return changed, nil
+}
+{{ if .ValidatesEnv }}
+// ValidateEnv reports every required environment variable that is unset:
+// those the database config reads without a default{{ if .ConnectionEnv }} (for the selected
+// connection, {{ .ConnectionSwitch }}){{ end }}, plus the ones the active auth driver
+// (AUTH_DRIVER, default "jwt") cannot start without.
+func ValidateEnv() error {
+	required := []string{ {{ range .RequiredEnv }}"{{ . }}", {{ end }} }
+{{ if .ConnectionEnv }}	switch Env("{{ .ConnectionSwitch }}", "{{ .ConnectionDefault }}") {
+{{ range .ConnectionEnv }}	case "{{ .Name }}":
+		required = append(required, {{ range .Vars }}"{{ . }}", {{ end }})
+{{ end }}	}
+{{ end }}{{ if .DriverEnv }}	switch Env("AUTH_DRIVER", "jwt") {
+{{ range .DriverEnv }}	case "{{ .Name }}":
+{{ if .Vars }}		required = append(required, {{ range .Vars }}"{{ . }}", {{ end }})
+{{ end }}{{ if .Switch }}		switch value := Env("{{ .Switch }}", "{{ .SwitchDefault }}"); {
+{{ range .Cases }}{{ if .Prefix }}		case strings.HasPrefix(value, "{{ .Prefix }}"):
+{{ else }}		default:
+{{ end }}			required = append(required, {{ range .Vars }}"{{ . }}", {{ end }})
+{{ end }}		}
+{{ end }}{{ end }}	}
+{{ end }}	return RequireEnv(required...)
+}
+{{ end }}{{ if .HasDBMethods }}
 // Connection returns the named connection config, or the default.
 func (d DatabaseConfig) Connection(name ...string) ConnectionConfig {
 	key := d.Default
@@ -164,4 +420,15 @@ func (d DatabaseConfig) Connection(name ...string) ConnectionConfig {
 func (d DatabaseConfig) Open(name ...string) *sql.DB {
 	return OpenDB(d.Connection(name...))
 }
+
+// OpenConnection opens and pings the named connection, returning an error
+// instead of exiting. The app hands it to models.SetConnectionOpener so
+// models.Connection can open connections on first use.
+func (d DatabaseConfig) OpenConnection(name string) (*sql.DB, error) {
+	conn, ok := d.Connections[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database connection %q", name)
+	}
+	return openDB(conn)
+}
 {{ end }}`
@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanConfigsRequiredEnv(t *testing.T) {
+	dir := t.TempDir()
+	src := `package config
+
+type DatabaseConfig struct {
+	Host     string
+	Name     string
+	Password string
+	Port     string
+}
+
+func database() DatabaseConfig {
+	return DatabaseConfig{
+		Host:     Env("DB_HOST", ""),
+		Name:     Env("DB_DATABASE", ""),
+		Password: Env("DB_PASSWORD", ""),
+		Port:     Env("DB_PORT", "5432"),
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "database.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScanConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.RequiredEnv, ","); got != "DB_DATABASE,DB_HOST" {
+		t.Errorf("RequiredEnv = %q, want DB_DATABASE,DB_HOST", got)
+	}
+}
+
+func TestScanConfigsRequiredEnvPerConnection(t *testing.T) {
+	dir := t.TempDir()
+	src := `package config
+
+type ConnectionConfig struct{ Driver, Host, Name, Region string }
+
+type DatabaseConfig struct {
+	Default     string
+	Connections map[string]ConnectionConfig
+}
+
+func database() DatabaseConfig {
+	return DatabaseConfig{
+		Default: Env("DB_CONNECTION", "pgsql"),
+		Connections: map[string]ConnectionConfig{
+			"pgsql": {
+				Driver: "pgsql",
+				Host:   Env("DB_HOST", ""),
+				Name:   Env("DB_DATABASE", ""),
+				Region: Env("DB_REGION", ""),
+			},
+			"sqlite": {
+				Driver: "sqlite",
+				Name:   Env("DB_DATABASE", ""),
+			},
+			"memory": {Driver: "sqlite", Name: ":memory:"},
+		},
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "database.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScanConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.RequiredEnv) != 0 {
+		t.Errorf("RequiredEnv = %v, want none outside the connections", result.RequiredEnv)
+	}
+	if result.ConnectionSwitch != "DB_CONNECTION" || result.ConnectionDefault != "pgsql" {
+		t.Errorf("switch = %q/%q, want DB_CONNECTION/pgsql", result.ConnectionSwitch, result.ConnectionDefault)
+	}
+	var got []string
+	for _, c := range result.ConnectionEnv {
+		got = append(got, c.Name+"="+strings.Join(c.Vars, ","))
+	}
+	if want := "pgsql=DB_DATABASE,DB_HOST,DB_REGION sqlite=DB_DATABASE"; strings.Join(got, " ") != want {
+		t.Errorf("ConnectionEnv = %q, want %q", strings.Join(got, " "), want)
+	}
+
+	result.Configs = nil
+	out, err := GenerateConfigGlue(result, "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`switch Env("DB_CONNECTION", "pgsql") {`,
+		`case "sqlite":
+		required = append(required, "DB_DATABASE")`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("generated ValidateEnv missing %q:\n%s", want, out)
+		}
+	}
+
+	// A literal Default keeps only that connection's keys.
+	src = strings.Replace(src, `Env("DB_CONNECTION", "pgsql")`, `"sqlite"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "database.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = ScanConfigs(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.RequiredEnv, ","); got != "DB_DATABASE" || len(result.ConnectionEnv) != 0 {
+		t.Errorf("literal default: RequiredEnv = %q, ConnectionEnv = %v, want DB_DATABASE only", got, result.ConnectionEnv)
+	}
+}
+
+func TestAuthDriverEnvSkipsOverriddenDrivers(t *testing.T) {
+	authDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(authDir, "oauth"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(authDir, "oauth", "driver.go"), []byte("package oauth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := AuthDriverEnv(authDir)
+	if len(got) != 1 || got[0].Name != "jwt" || got[0].Switch != "JWT_ALGORITHM" {
+		t.Errorf("AuthDriverEnv = %+v, want only jwt switching on JWT_ALGORITHM", got)
+	}
+}
+
+func TestGenerateConfigGlueInitReportsMissingEnv(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs generated config")
+	}
+	scan := &ConfigScanResult{
+		Configs:     []ConfigDef{{FuncName: "app", ReturnType: "AppConfig", VarName: "App"}},
+		RequiredEnv: []string{"PICKLE_TEST_DB_HOST"},
+		DriverEnv:   AuthDriverEnv(t.TempDir()),
+	}
+	out, err := GenerateConfigGlue(scan, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp(".", "_configtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := `package main
+
+type AppConfig struct{ Name string }
+
+func app() AppConfig { return AppConfig{Name: Env("APP_NAME", "test")} }
+
+func main() {
+	Init()
+	println("started")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pickle_gen.go"), out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Env = append(os.Environ(), "JWT_SECRET=", "PICKLE_TEST_DB_HOST=", "AUTH_DRIVER=")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected Init to fail, got:\n%s", output)
+	}
+	if !strings.Contains(string(output), "missing required environment variables: PICKLE_TEST_DB_HOST, JWT_SECRET") {
+		t.Errorf("output = %s, want both missing vars reported together", output)
+	}
+	if strings.Contains(string(output), "started") {
+		t.Error("Init should fail before the program continues")
+	}
+
+	cmd = exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Env = append(os.Environ(), "JWT_SECRET=secret", "PICKLE_TEST_DB_HOST=localhost")
+	if output, err := cmd.CombinedOutput(); err != nil || !strings.Contains(string(output), "started") {
+		t.Errorf("expected Init to succeed with env set: %v\n%s", err, output)
+	}
+
+	// RS* signs with an RSA key, so JWT_SECRET is not needed but one of the
+	// keys is.
+	cmd = exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Env = append(os.Environ(), "JWT_ALGORITHM=RS256", "JWT_SECRET=", "JWT_PRIVATE_KEY=", "JWT_PUBLIC_KEY=", "PICKLE_TEST_DB_HOST=localhost")
+	output, err = cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "missing required environment variables: JWT_PRIVATE_KEY or JWT_PUBLIC_KEY") {
+		t.Errorf("expected RS256 without keys to fail naming both keys: %v\n%s", err, output)
+	}
+
+	cmd = exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Env = append(os.Environ(), "JWT_ALGORITHM=RS256", "JWT_SECRET=", "JWT_PRIVATE_KEY=", "JWT_PUBLIC_KEY=pem", "PICKLE_TEST_DB_HOST=localhost")
+	if output, err := cmd.CombinedOutput(); err != nil || !strings.Contains(string(output), "started") {
+		t.Errorf("expected RS256 with only JWT_PUBLIC_KEY to start: %v\n%s", err, output)
+	}
+}
+
+func TestGenerateConfigGlueGetAndReload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs generated config")
+	}
+	scan := &ConfigScanResult{
+		Configs: []ConfigDef{
+			{FuncName: "app", ReturnType: "AppConfig", VarName: "App"},
+			{FuncName: "database", ReturnType: "DatabaseConfig", VarName: "Database"},
+		},
+		HasDatabaseConfig: true,
+	}
+	out, err := GenerateConfigGlue(scan, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := os.MkdirTemp(".", "_configtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type AppConfig struct{ Name string }
+
+type DatabaseConfig struct {
+	Default     string
+	Connections map[string]ConnectionConfig
+}
+
+func app() AppConfig { return AppConfig{Name: Env("PICKLE_TEST_APP_NAME", "test")} }
+
+func database() DatabaseConfig {
+	return DatabaseConfig{
+		Default: "pgsql",
+		Connections: map[string]ConnectionConfig{
+			"pgsql": {Driver: "pgsql", Host: Env("PICKLE_TEST_DB_HOST", "localhost"), MaxOpenConns: 5},
+		},
+	}
+}
+
+func main() {
+	Init()
+	host, ok := Get("database.connections.pgsql.host")
+	conns, _ := Get("database.connections.pgsql.max_open_conns")
+	_, missing := Get("database.connections.mysql.host")
+	fmt.Println(host, ok, conns, missing)
+
+	os.Setenv("PICKLE_TEST_DB_HOST", "db.internal")
+	changed, err := Reload()
+	host, _ = Get("database.connections.pgsql.host")
+	name, _ := Get("app.Name")
+	fmt.Println(changed, err, host, Database.Connection().Host, name)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pickle_gen.go"), out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Env = append(os.Environ(), "PICKLE_TEST_DB_HOST=", "PICKLE_TEST_APP_NAME=")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	want := "localhost true 5 false\n[Database] <nil> db.internal db.internal test"
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestScanConfigsCollectionAndQualifiedTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package config
+
+import (
+	"net"
+
+	q "example.com/app/queue"
+)
+
+type QueueConfig struct{ Driver string }
+
+func queues() map[string]QueueConfig { return nil }
+
+func mirrors() []QueueConfig { return nil }
+
+func listener() net.ListenConfig { return net.ListenConfig{} }
+
+func workers() map[string]q.WorkerConfig { return nil }
+
+func names() []string { return nil }
+
+func unknown() other.Config { return other.Config{} }
+`
+	if err := os.WriteFile(filepath.Join(dir, "queues.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScanConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, c := range result.Configs {
+		got[c.VarName] = c.ReturnType
+	}
+	want := map[string]string{
+		"Queues":   "map[string]QueueConfig",
+		"Mirrors":  "[]QueueConfig",
+		"Listener": "net.ListenConfig",
+		"Workers":  "map[string]q.WorkerConfig",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("configs = %v, want %v", got, want)
+	}
+	for name, typ := range want {
+		if got[name] != typ {
+			t.Errorf("%s return type = %q, want %q", name, got[name], typ)
+		}
+	}
+	if imports := strings.Join(result.Imports, ";"); imports != `"net";q "example.com/app/queue"` {
+		t.Errorf("Imports = %s", imports)
+	}
+}
+
+func TestGenerateConfigGlueQualifiedTypes(t *testing.T) {
+	scan := &ConfigScanResult{
+		Configs: []ConfigDef{
+			{FuncName: "listener", ReturnType: "net.ListenConfig", VarName: "Listener"},
+			{FuncName: "queues", ReturnType: "map[string]QueueConfig", VarName: "Queues"},
+		},
+		Imports: []string{`"net"`, `"os"`},
+	}
+	out, err := GenerateConfigGlue(scan, "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(out)
+	for _, want := range []string{"var Listener net.ListenConfig", "var Queues map[string]QueueConfig", "\t\"net\"\n", "Queues = queues()"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated config missing %q", want)
+		}
+	}
+	if n := strings.Count(src, "\t\"os\"\n"); n != 1 {
+		t.Errorf("\"os\" imported %d times, want once", n)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/shortontech/pickle/pkg/scaffold"
 	"github.com/shortontech/pickle/pkg/schema"
 )
 
@@ -187,6 +188,45 @@ func TestDetectProject(t *testing.T) {
 	}
 }
 
+func TestDetectProjectLayoutPackages(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module github.com/example/app\n\ngo 1.22\n"), 0o644)
+
+	proj, err := DetectProject(tmp)
+	if err != nil {
+		t.Fatalf("DetectProject: %v", err)
+	}
+	if proj.Layout.ModelsPkg != "models" || proj.Layout.HTTPPkg != "pickle" {
+		t.Errorf("default packages = %q, %q", proj.Layout.ModelsPkg, proj.Layout.HTTPPkg)
+	}
+
+	os.WriteFile(filepath.Join(tmp, "pickle.yaml"), []byte("layout:\n  models_package: db\n  http_package: web\n"), 0o644)
+	proj, err = DetectProject(tmp)
+	if err != nil {
+		t.Fatalf("DetectProject: %v", err)
+	}
+	if proj.Layout.ModelsPkg != "db" || proj.Layout.HTTPPkg != "web" {
+		t.Errorf("configured packages = %q, %q, want db, web", proj.Layout.ModelsPkg, proj.Layout.HTTPPkg)
+	}
+
+	os.WriteFile(filepath.Join(tmp, "pickle.yaml"), []byte("requests:\n  error_format: jsonapi\n"), 0o644)
+	proj, err = DetectProject(tmp)
+	if err != nil {
+		t.Fatalf("DetectProject: %v", err)
+	}
+	if proj.Requests.ErrorFormat != "jsonapi" {
+		t.Errorf("requests.error_format = %q, want jsonapi", proj.Requests.ErrorFormat)
+	}
+	if opts := proj.bindingOptions(proj.Layout.HTTPDir); opts.HTTPImport != "github.com/example/app/app/http" || opts.ErrorFormat != "jsonapi" {
+		t.Errorf("bindingOptions = %+v", opts)
+	}
+
+	os.WriteFile(filepath.Join(tmp, "pickle.yaml"), []byte("layout:\n  models_package: my-models\n"), 0o644)
+	if _, err := DetectProject(tmp); err == nil {
+		t.Error("expected error for invalid models_package")
+	}
+}
+
 func TestDetectProjectRelative(t *testing.T) {
 	// Just ensure the test data project resolves
 	proj, err := DetectProject(filepath.Join("..", "..", "testdata", "basic-crud"))
@@ -230,7 +270,7 @@ func TestResolveModelDir(t *testing.T) {
 	modelsDir := "/app/models"
 
 	// Top-level table (not in nesting map)
-	dir, pkg := resolveModelDir(modelsDir, "users", nil)
+	dir, pkg := resolveModelDir(modelsDir, "models", "users", nil)
 	if dir != modelsDir {
 		t.Errorf("top-level dir = %q, want %q", dir, modelsDir)
 	}
@@ -242,7 +282,7 @@ func TestResolveModelDir(t *testing.T) {
 	nestingMap := map[string]SchemaRelationship{
 		"posts": {ParentTable: "users", ChildTable: "posts"},
 	}
-	dir2, pkg2 := resolveModelDir(modelsDir, "posts", nestingMap)
+	dir2, pkg2 := resolveModelDir(modelsDir, "models", "posts", nestingMap)
 	if dir2 == modelsDir {
 		t.Error("nested table should not map to models root")
 	}
@@ -254,7 +294,7 @@ func TestResolveModelDir(t *testing.T) {
 	nestingMapTopLevel := map[string]SchemaRelationship{
 		"posts": {ParentTable: "users", ChildTable: "posts", TopLevel: true},
 	}
-	dir3, pkg3 := resolveModelDir(modelsDir, "posts", nestingMapTopLevel)
+	dir3, pkg3 := resolveModelDir(modelsDir, "models", "posts", nestingMapTopLevel)
 	if dir3 != modelsDir {
 		t.Errorf("TopLevel table dir = %q, want %q", dir3, modelsDir)
 	}
@@ -704,6 +744,28 @@ func (c SendEmailCommand) Run(args []string) error { return nil }
 	}
 }
 
+func TestScaffoldedCommandIsRegistered(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := scaffold.MakeCommand("report:daily", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmds, err := ScanCommands(filepath.Join(dir, filepath.Dir(relPath)))
+	if err != nil {
+		t.Fatalf("ScanCommands: %v", err)
+	}
+	if len(cmds) != 1 || cmds[0] != "ReportDailyCommand" {
+		t.Fatalf("got %v, want [ReportDailyCommand]", cmds)
+	}
+	out, err := GenerateCommandsGlue("github.com/example/myapp", "database/migrations", cmds, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "ReportDailyCommand{},") {
+		t.Errorf("UserCommands should include ReportDailyCommand:\n%s", out)
+	}
+}
+
 func TestScanCommandsEmpty(t *testing.T) {
 	tmp := t.TempDir()
 	cmds, err := ScanCommands(tmp)
@@ -763,6 +825,28 @@ func TestGenerateCommandsGlue(t *testing.T) {
 	}
 }
 
+func TestGenerateCommandsGlueServesGracefully(t *testing.T) {
+	for _, hasSchedule := range []bool{false, true} {
+		out, err := GenerateCommandsGlue("github.com/example/myapp", "database/migrations", nil, []string{"API"}, false, hasSchedule)
+		if err != nil {
+			t.Fatalf("GenerateCommandsGlue: %v", err)
+		}
+		src := string(out)
+		if !strings.Contains(src, "pickle.ServeGraceful(srv)") {
+			t.Errorf("hasSchedule=%v: server should shut down gracefully:\n%s", hasSchedule, src)
+		}
+		if strings.Contains(src, "srv.ListenAndServe()") {
+			t.Errorf("hasSchedule=%v: server still uses ListenAndServe", hasSchedule)
+		}
+		if got := strings.Contains(src, `"os/signal"`); got != hasSchedule {
+			t.Errorf("hasSchedule=%v: os/signal imported = %v", hasSchedule, got)
+		}
+		if hasSchedule && !strings.Contains(src, "os.Interrupt, syscall.SIGTERM") {
+			t.Error("scheduler should stop on SIGTERM as well as SIGINT")
+		}
+	}
+}
+
 func TestGenerateCommandsGlueWithAuth(t *testing.T) {
 	out, err := GenerateCommandsGlue(
 		"github.com/example/myapp",
@@ -825,6 +909,24 @@ func TestGenerateCommandsGlueWithSeeders(t *testing.T) {
 	}
 }
 
+func TestGenerateCommandsGlueMigrateFlags(t *testing.T) {
+	out, err := GenerateCommandsGlue("github.com/example/myapp", "database/migrations", nil, []string{"API"}, false, false, false)
+	if err != nil {
+		t.Fatalf("GenerateCommandsGlue: %v", err)
+	}
+	text := string(out)
+	for _, want := range []string{
+		`if argument == "--strict"`,
+		"runner.StrictChecksums = true",
+		`if argument == "--json"`,
+		"return migrations.PrintStatusJSON(statuses)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("generated commands missing %q", want)
+		}
+	}
+}
+
 func TestGenerateCommandsGlueWithRowPolicyStatus(t *testing.T) {
 	out, err := GenerateCommandsGlue("github.com/example/myapp", "database/migrations", nil, []string{"API"}, false, false, false, true)
 	if err != nil {
@@ -0,0 +1,279 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shortontech/pickle/pkg/names"
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// enumFileHeader marks enum files written by WriteRequestEnums and
+// WriteModelEnums so stale ones can be removed without touching hand-written
+// files.
+const enumFileHeader = "// Code generated by Pickle. DO NOT EDIT.\n"
+
+// RequestEnum is a set of allowed values taken from a request field's
+// oneof= validation rule.
+type RequestEnum struct {
+	Field    string   // Go field name the values come from, e.g. Status
+	TypeName string   // generated string type, e.g. Status or CreatePostStatus
+	Values   []string // allowed values in declaration order
+	Consts   []string // constant name for each value, e.g. StatusDraft
+	Requests []string // request structs whose field accepts exactly these values
+}
+
+// CollectRequestEnums extracts one enum per distinct oneof= value set. Fields
+// sharing a name and value set across requests (CreatePostRequest.Status and
+// UpdatePostRequest.Status) produce a single enum named after the field; when
+// the same field name has different value sets in different requests, each
+// enum is prefixed with its first request's name instead.
+func CollectRequestEnums(requests []RequestDef) []RequestEnum {
+	type group struct {
+		field string
+		sets  []*RequestEnum
+	}
+	var groups []*group
+	byField := map[string]*group{}
+
+	for _, req := range requests {
+		for _, field := range req.Fields {
+			values := parseOneOf(field.Validate)
+			if len(values) == 0 {
+				continue
+			}
+			g := byField[field.Name]
+			if g == nil {
+				g = &group{field: field.Name}
+				byField[field.Name] = g
+				groups = append(groups, g)
+			}
+			var match *RequestEnum
+			for _, set := range g.sets {
+				if sameValueSet(set.Values, values) {
+					match = set
+					break
+				}
+			}
+			if match == nil {
+				match = &RequestEnum{Field: field.Name, Values: values}
+				g.sets = append(g.sets, match)
+			}
+			match.Requests = append(match.Requests, req.Name)
+		}
+	}
+
+	var enums []RequestEnum
+	for _, g := range groups {
+		for _, set := range g.sets {
+			set.TypeName = g.field
+			if len(g.sets) > 1 {
+				set.TypeName = strings.TrimSuffix(set.Requests[0], "Request") + g.field
+			}
+			set.Consts = enumConstNames(set.TypeName, set.Values)
+			enums = append(enums, *set)
+		}
+	}
+	return enums
+}
+
+// parseOneOf returns the values of a oneof= rule in a validate tag. Values
+// are space-separated; single quotes group values containing spaces.
+func parseOneOf(validate string) []string {
+	for _, rule := range strings.Split(validate, ",") {
+		param, ok := strings.CutPrefix(strings.TrimSpace(rule), "oneof=")
+		if !ok {
+			continue
+		}
+		var values []string
+		for param != "" {
+			param = strings.TrimLeft(param, " ")
+			if param == "" {
+				break
+			}
+			if param[0] == '\'' {
+				if end := strings.IndexByte(param[1:], '\''); end >= 0 {
+					values = append(values, param[1:end+1])
+					param = param[end+2:]
+					continue
+				}
+			}
+			value, rest, _ := strings.Cut(param, " ")
+			values = append(values, value)
+			param = rest
+		}
+		return values
+	}
+	return nil
+}
+
+func sameValueSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string(nil), a...)
+	sb := append([]string(nil), b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// enumConstNames derives TypeName+Value identifiers, e.g. Status + in_review
+// → StatusInReview. Values that collapse to the same identifier get a
+// numeric suffix so the generated file always compiles.
+func enumConstNames(typeName string, values []string) []string {
+	seen := map[string]int{}
+	consts := make([]string, len(values))
+	for i, value := range values {
+		words := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		suffix := "Empty"
+		if len(words) > 0 {
+			suffix = names.SnakeToPascal(strings.Join(words, "_"))
+		}
+		name := typeName + suffix
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s%d", name, n)
+		}
+		consts[i] = name
+	}
+	return consts
+}
+
+// GenerateRequestEnums renders the enums derived from one field name as a Go
+// file in the requests package: a string type per value set, a typed
+// constant per value, and a slice of every valid value.
+func GenerateRequestEnums(enums []RequestEnum, packageName string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(enumFileHeader)
+	fmt.Fprintf(&b, "package %s\n", packageName)
+
+	for _, enum := range enums {
+		fmt.Fprintf(&b, "\n// %s is a valid value for %s.%s.\ntype %s string\n\n",
+			enum.TypeName, strings.Join(enum.Requests, ", "), enum.Field, enum.TypeName)
+		b.WriteString("const (\n")
+		for i, value := range enum.Values {
+			fmt.Fprintf(&b, "\t%s %s = %q\n", enum.Consts[i], enum.TypeName, value)
+		}
+		b.WriteString(")\n\n")
+		fmt.Fprintf(&b, "// %sValues lists every valid %s, in oneof= order.\n", enum.TypeName, enum.TypeName)
+		fmt.Fprintf(&b, "var %sValues = []%s{%s}\n", enum.TypeName, enum.TypeName, strings.Join(enum.Consts, ", "))
+	}
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), fmt.Errorf("go format request enums: %w\n%s", err, b.String())
+	}
+	return formatted, nil
+}
+
+// WriteRequestEnums writes {field}_enum.go into dir for every field with a
+// oneof= rule, and removes generated enum files whose field no longer has
+// one.
+func WriteRequestEnums(dir string, requests []RequestDef, packageName string) error {
+	byField := map[string][]RequestEnum{}
+	for _, enum := range CollectRequestEnums(requests) {
+		byField[enum.Field] = append(byField[enum.Field], enum)
+	}
+
+	want := map[string]bool{}
+	for field, enums := range byField {
+		fileName := names.PascalToSnake(field) + "_enum.go"
+		want[fileName] = true
+		src, err := GenerateRequestEnums(enums, packageName)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dir, fileName), src); err != nil {
+			return err
+		}
+	}
+
+	stale, _ := filepath.Glob(filepath.Join(dir, "*_enum.go"))
+	for _, path := range stale {
+		if want[filepath.Base(path)] {
+			continue
+		}
+		if data, err := os.ReadFile(path); err == nil && bytes.HasPrefix(data, []byte(enumFileHeader)) {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("removing stale %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateModelEnums renders a named string type for every column of table
+// whose check constraint lists its allowed values, such as
+// Check("status IN ('draft', 'published')"): typed constants, a slice of
+// the values, and String, Valid and Parse<Type>. The model's field uses the
+// type. It returns nil when the table has no such column.
+func GenerateModelEnums(table *schema.Table, packageName string) ([]byte, error) {
+	var b bytes.Buffer
+	for _, col := range table.Columns {
+		typeName := names.EnumTypeName(table, col)
+		if typeName == "" {
+			continue
+		}
+		values := names.EnumValues(col)
+		consts := enumConstNames(typeName, values)
+
+		fmt.Fprintf(&b, "\n// %s is a value %s.%s allows, from its check constraint.\ntype %s string\n\n", typeName, table.Name, col.Name, typeName)
+		b.WriteString("const (\n")
+		for i, value := range values {
+			fmt.Fprintf(&b, "\t%s %s = %q\n", consts[i], typeName, value)
+		}
+		b.WriteString(")\n\n")
+		fmt.Fprintf(&b, "// %sValues lists every valid %s, in constraint order.\n", typeName, typeName)
+		fmt.Fprintf(&b, "var %sValues = []%s{%s}\n\n", typeName, typeName, strings.Join(consts, ", "))
+		fmt.Fprintf(&b, "// String returns the stored value.\nfunc (v %s) String() string {\n\treturn string(v)\n}\n\n", typeName)
+		fmt.Fprintf(&b, "// Valid reports whether v is one of %sValues.\nfunc (v %s) Valid() bool {\n", typeName, typeName)
+		fmt.Fprintf(&b, "\tswitch v {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n\n", strings.Join(consts, ", "))
+		fmt.Fprintf(&b, "// Parse%s returns s as a %s, or an error if %s doesn't allow it.\n", typeName, typeName, col.Name)
+		fmt.Fprintf(&b, "func Parse%s(s string) (%s, error) {\n\tif v := %s(s); v.Valid() {\n\t\treturn v, nil\n\t}\n", typeName, typeName, typeName)
+		fmt.Fprintf(&b, "\treturn \"\", fmt.Errorf(\"%%q is not a valid %s\", s)\n}\n", strings.ReplaceAll(col.Name, "_", " "))
+	}
+	if b.Len() == 0 {
+		return nil, nil
+	}
+
+	src := append([]byte(enumFileHeader+"\npackage "+packageName+"\n\nimport \"fmt\"\n"), b.Bytes()...)
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, fmt.Errorf("go format enums for %s: %w\n%s", table.Name, err, src)
+	}
+	return formatted, nil
+}
+
+// WriteModelEnums writes {model}_enums_gen.go next to table's model, or
+// removes a previously generated one when the table no longer has enum
+// columns.
+func WriteModelEnums(dir string, table *schema.Table, packageName string) error {
+	path := filepath.Join(dir, toLowerFirst(tableToStructName(table.Name))+"_enums_gen.go")
+	src, err := GenerateModelEnums(table, packageName)
+	if err != nil {
+		return err
+	}
+	if src != nil {
+		return writeFile(path, src)
+	}
+	if data, err := os.ReadFile(path); err == nil && bytes.HasPrefix(data, []byte(enumFileHeader)) {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("removing stale %s: %w", path, err)
+		}
+	}
+	return nil
+}
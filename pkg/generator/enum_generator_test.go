@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestParseOneOf(t *testing.T) {
+	tests := map[string][]string{
+		"required,oneof=draft published archived": {"draft", "published", "archived"},
+		"omitempty,oneof='on hold' open":          {"on hold", "open"},
+		"required,min=1":                          nil,
+	}
+	for validate, want := range tests {
+		if got := parseOneOf(validate); !reflect.DeepEqual(got, want) {
+			t.Errorf("parseOneOf(%q) = %v, want %v", validate, got, want)
+		}
+	}
+}
+
+func TestCollectRequestEnumsSharesIdenticalSets(t *testing.T) {
+	requests := []RequestDef{
+		{Name: "CreatePostRequest", Fields: []RequestField{{Name: "Status", Validate: "required,oneof=draft published"}}},
+		{Name: "UpdatePostRequest", Fields: []RequestField{{Name: "Status", Validate: "omitempty,oneof=published draft"}}},
+	}
+	enums := CollectRequestEnums(requests)
+	if len(enums) != 1 {
+		t.Fatalf("got %d enums, want one shared enum: %+v", len(enums), enums)
+	}
+	e := enums[0]
+	if e.TypeName != "Status" || !reflect.DeepEqual(e.Consts, []string{"StatusDraft", "StatusPublished"}) {
+		t.Errorf("enum = %+v", e)
+	}
+	if !reflect.DeepEqual(e.Requests, []string{"CreatePostRequest", "UpdatePostRequest"}) {
+		t.Errorf("requests = %v", e.Requests)
+	}
+}
+
+func TestCollectRequestEnumsPrefixesConflictingSets(t *testing.T) {
+	requests := []RequestDef{
+		{Name: "CreateOrderRequest", Fields: []RequestField{{Name: "Status", Validate: "oneof=pending paid"}}},
+		{Name: "CreatePostRequest", Fields: []RequestField{{Name: "Status", Validate: "oneof=draft in-review"}}},
+	}
+	enums := CollectRequestEnums(requests)
+	if len(enums) != 2 {
+		t.Fatalf("got %d enums, want 2", len(enums))
+	}
+	if enums[0].TypeName != "CreateOrderStatus" || enums[1].TypeName != "CreatePostStatus" {
+		t.Errorf("type names = %s, %s", enums[0].TypeName, enums[1].TypeName)
+	}
+	if enums[1].Consts[1] != "CreatePostStatusInReview" {
+		t.Errorf("const = %s", enums[1].Consts[1])
+	}
+}
+
+func TestEnumConstNamesDisambiguatesCollisions(t *testing.T) {
+	got := enumConstNames("Kind", []string{"a-b", "a_b", ""})
+	want := []string{"KindAB", "KindAB2", "KindEmpty"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enumConstNames = %v, want %v", got, want)
+	}
+}
+
+func TestWriteRequestEnums(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "role_enum.go")
+	os.WriteFile(stale, []byte(enumFileHeader+"package requests\n"), 0o644)
+	handWritten := filepath.Join(dir, "custom_enum.go")
+	os.WriteFile(handWritten, []byte("package requests\n"), 0o644)
+
+	requests := []RequestDef{
+		{Name: "UpdatePostRequest", Fields: []RequestField{{Name: "Status", Validate: "omitempty,oneof=draft published archived"}}},
+	}
+	if err := WriteRequestEnums(dir, requests, "requests"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "status_enum.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	if _, err := parser.ParseFile(token.NewFileSet(), "status_enum.go", src, 0); err != nil {
+		t.Fatalf("enum file does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"type Status string",
+		`StatusPublished Status = "published"`,
+		"var StatusValues = []Status{StatusDraft, StatusPublished, StatusArchived}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale generated enum file should be removed")
+	}
+	if _, err := os.Stat(handWritten); err != nil {
+		t.Error("hand-written *_enum.go must be kept")
+	}
+}
+
+func TestGenerateModelEnums(t *testing.T) {
+	table := &schema.Table{Name: "transfers"}
+	table.UUID("id").PrimaryKey()
+	table.String("status").Default("pending").Check("status IN ('pending', 'settled')")
+	table.String("channel").Nullable().Check("channel IN ('ach', 'wire')")
+	table.String("memo").Nullable()
+
+	data, err := GenerateModelEnums(table, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	if _, err := parser.ParseFile(token.NewFileSet(), "transfer_enums_gen.go", src, 0); err != nil {
+		t.Fatalf("enum file does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"type TransferStatus string",
+		`TransferStatusSettled TransferStatus = "settled"`,
+		"var TransferChannelValues = []TransferChannel{TransferChannelAch, TransferChannelWire}",
+		"func (v TransferStatus) Valid() bool",
+		"func ParseTransferChannel(s string) (TransferChannel, error)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "TransferMemo") {
+		t.Error("columns without an IN check should not get an enum")
+	}
+
+	model, err := GenerateModel(table, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Status  TransferStatus", "Channel *TransferChannel", "Memo    *string"} {
+		if !strings.Contains(string(model), want) {
+			t.Errorf("model missing %q:\n%s", want, model)
+		}
+	}
+}
+
+func TestWriteModelEnumsRemovesStaleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "transfer_enums_gen.go")
+	os.WriteFile(path, []byte(enumFileHeader+"package models\n"), 0o644)
+	table := &schema.Table{Name: "transfers"}
+	table.String("status")
+	if err := WriteModelEnums(dir, table, "models"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("stale model enum file should be removed")
+	}
+}
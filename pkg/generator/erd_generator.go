@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// GenerateERDiagram renders tables as a Mermaid erDiagram: one entity per
+// table with its columns, and one relationship per foreign key, labelled with
+// its columns. Self-references draw an edge back to the same entity, and a
+// composite foreign key draws a single edge labelled "a, b".
+func GenerateERDiagram(tables []*schema.Table) string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for _, tbl := range tables {
+		compositeFK := map[string]bool{}
+		for _, fk := range tbl.ForeignKeys {
+			for _, name := range fk.Columns {
+				compositeFK[name] = true
+			}
+		}
+
+		fmt.Fprintf(&b, "    %s {\n", tbl.Name)
+		for _, col := range tbl.Columns {
+			fmt.Fprintf(&b, "        %s %s", col.Type, col.Name)
+			var keys []string
+			if col.IsPrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if col.ForeignKeyTable != "" || compositeFK[col.Name] {
+				keys = append(keys, "FK")
+			}
+			if col.IsUnique && !col.IsPrimaryKey {
+				keys = append(keys, "UK")
+			}
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			if col.CommentText != "" {
+				fmt.Fprintf(&b, " %q", strings.ReplaceAll(col.CommentText, `"`, "'"))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, tbl := range tables {
+		nullable := map[string]bool{}
+		pkColumns := 0
+		for _, col := range tbl.Columns {
+			nullable[col.Name] = col.IsNullable
+			if col.IsPrimaryKey {
+				pkColumns++
+			}
+		}
+
+		for _, col := range tbl.Columns {
+			if col.ForeignKeyTable == "" {
+				continue
+			}
+			// A child may have no parent when the key is nullable, and a
+			// parent has at most one child when the key alone is unique.
+			parent := "||"
+			if col.IsNullable {
+				parent = "|o"
+			}
+			child := "o{"
+			if col.IsUnique || col.IsPrimaryKey && pkColumns == 1 {
+				child = "o|"
+			}
+			fmt.Fprintf(&b, "    %s %s--%s %s : %q\n", col.ForeignKeyTable, parent, child, tbl.Name, col.Name)
+		}
+
+		for _, fk := range tbl.ForeignKeys {
+			parent := "||"
+			for _, name := range fk.Columns {
+				if nullable[name] {
+					parent = "|o"
+				}
+			}
+			fmt.Fprintf(&b, "    %s %s--o{ %s : %q\n", fk.ReferencedTable, parent, tbl.Name, strings.Join(fk.Columns, ", "))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestGenerateERDiagram(t *testing.T) {
+	users := &schema.Table{Name: "users"}
+	users.UUID("id").PrimaryKey()
+	users.String("email").NotNull().Unique().Comment(`login "handle"`)
+
+	profiles := &schema.Table{Name: "profiles"}
+	profiles.UUID("id").PrimaryKey()
+	profiles.UUID("user_id").NotNull().Unique().ForeignKey("users", "id")
+
+	categories := &schema.Table{Name: "categories"}
+	categories.UUID("id").PrimaryKey()
+	categories.UUID("parent_id").Nullable().ForeignKey("categories", "id")
+
+	parties := &schema.Table{Name: "parties"}
+	parties.BigInteger("organization_id").NotNull()
+	parties.BigInteger("party_id").NotNull()
+	parties.PrimaryKey("organization_id", "party_id")
+
+	notes := &schema.Table{Name: "notes"}
+	notes.BigInteger("organization_id").NotNull()
+	notes.BigInteger("party_id").NotNull()
+	notes.BigInteger("note_id").NotNull()
+	notes.PrimaryKey("organization_id", "note_id")
+	notes.ForeignKey([]string{"organization_id", "party_id"}, "parties", []string{"organization_id", "party_id"})
+
+	out := GenerateERDiagram([]*schema.Table{users, profiles, categories, parties, notes})
+
+	if !strings.HasPrefix(out, "erDiagram\n") {
+		t.Fatalf("diagram must start with erDiagram:\n%s", out)
+	}
+	for _, want := range []string{
+		"    users {\n        uuid id PK\n        string email UK \"login 'handle'\"\n    }\n",
+		"        uuid user_id FK, UK\n",
+		"        bigint organization_id PK, FK\n",
+		"        bigint party_id FK\n",
+		`    users ||--o| profiles : "user_id"`,
+		`    categories |o--o{ categories : "parent_id"`,
+		`    parties ||--o{ notes : "organization_id, party_id"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagram missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// GenerateFinders produces Find and FindOrFail methods on a table's query
+// type, looking a record up by its primary key. httpImportPath is the import
+// path of the HTTP package providing Context and Response. Immutable tables
+// are looked up by id alone, which finds the current version. It returns nil
+// when the table has no primary key or a composite one.
+func GenerateFinders(table *schema.Table, packageName, httpImportPath string) ([]byte, error) {
+	var pk *schema.Column
+	for _, col := range table.Columns {
+		if !col.IsPrimaryKey || (table.IsImmutable && col.Name == "version_id") {
+			continue
+		}
+		if pk != nil {
+			return nil, nil
+		}
+		pk = col
+	}
+	if pk == nil {
+		return nil, nil
+	}
+
+	structName := tableToStructName(table.Name)
+	queryType := structName + "Query"
+	goType := columnGoType(pk)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by Pickle. DO NOT EDIT.\n")
+	b.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	b.WriteString("import (\n\t\"database/sql\"\n\t\"errors\"\n\n")
+	if imp := columnImport(pk); imp != "" {
+		b.WriteString(fmt.Sprintf("\t%q\n", imp))
+	}
+	b.WriteString(fmt.Sprintf("\tpickle %q\n", httpImportPath))
+	b.WriteString(")\n\n")
+
+	b.WriteString(fmt.Sprintf("// Find returns the %s with the given %s, honouring any scopes already\n", structName, pk.Name))
+	b.WriteString("// on the query. It returns sql.ErrNoRows when there is no match.\n")
+	b.WriteString(fmt.Sprintf("func (q *%s) Find(id %s) (*%s, error) {\n", queryType, goType, structName))
+	b.WriteString(fmt.Sprintf("\tq.where(%q, id)\n", pk.Name))
+	b.WriteString("\treturn q.First()\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(fmt.Sprintf("// FindOrFail is Find for controllers. When no %s matches it returns nil\n", structName))
+	b.WriteString("// and a 404 response; any other error becomes ctx.Error(err).\n")
+	b.WriteString("//\n")
+	b.WriteString(fmt.Sprintf("//\trecord, resp := models.Query%s().FindOrFail(ctx, id)\n", structName))
+	b.WriteString("//\tif record == nil {\n")
+	b.WriteString("//\t\treturn resp\n")
+	b.WriteString("//\t}\n")
+	b.WriteString(fmt.Sprintf("func (q *%s) FindOrFail(ctx *pickle.Context, id %s) (*%s, pickle.Response) {\n", queryType, goType, structName))
+	b.WriteString("\trecord, err := q.Find(id)\n")
+	b.WriteString("\tif errors.Is(err, sql.ErrNoRows) {\n")
+	b.WriteString("\t\treturn nil, ctx.NotFound(\"not found\")\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\tif err != nil {\n")
+	b.WriteString("\t\treturn nil, ctx.Error(err)\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\treturn record, pickle.Response{}\n")
+	b.WriteString("}\n")
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), fmt.Errorf("formatting finders for %s: %w\n%s", structName, err, b.String())
+	}
+	return formatted, nil
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestGenerateFindersUsesPrimaryKey(t *testing.T) {
+	tbl := &schema.Table{Name: "posts"}
+	tbl.UUID("id").PrimaryKey().Default("uuid_generate_v7()")
+	tbl.String("title", 255).NotNull()
+
+	out, err := GenerateFinders(tbl, "models", "myapp/app/http")
+	if err != nil {
+		t.Fatalf("GenerateFinders: %v", err)
+	}
+	src := string(out)
+	if _, err := parser.ParseFile(token.NewFileSet(), "post_finders_gen.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		`"github.com/google/uuid"`,
+		`pickle "myapp/app/http"`,
+		"func (q *PostQuery) Find(id uuid.UUID) (*Post, error) {",
+		`q.where("id", id)`,
+		"func (q *PostQuery) FindOrFail(ctx *pickle.Context, id uuid.UUID) (*Post, pickle.Response) {",
+		"if errors.Is(err, sql.ErrNoRows) {",
+		`return nil, ctx.NotFound("not found")`,
+		"return nil, ctx.Error(err)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateFindersIntegerKey(t *testing.T) {
+	tbl := &schema.Table{Name: "events"}
+	tbl.BigInteger("event_id").PrimaryKey()
+
+	out, err := GenerateFinders(tbl, "models", "myapp/app/http")
+	if err != nil {
+		t.Fatalf("GenerateFinders: %v", err)
+	}
+	src := string(out)
+	if !strings.Contains(src, "func (q *EventQuery) Find(id int64) (*Event, error) {") || !strings.Contains(src, `q.where("event_id", id)`) {
+		t.Errorf("expected int64 Find on event_id\n%s", src)
+	}
+	if strings.Contains(src, "uuid") {
+		t.Errorf("unexpected uuid import\n%s", src)
+	}
+}
+
+func TestGenerateFindersImmutableTableUsesID(t *testing.T) {
+	tbl := &schema.Table{Name: "transfers"}
+	tbl.Immutable()
+	tbl.Decimal("amount", 18, 2)
+
+	out, err := GenerateFinders(tbl, "models", "myapp/app/http")
+	if err != nil || out == nil {
+		t.Fatalf("GenerateFinders = %v; want finders keyed by id", err)
+	}
+	if !strings.Contains(string(out), "func (q *TransferQuery) Find(id uuid.UUID) (*Transfer, error) {") {
+		t.Errorf("expected Find by id\n%s", out)
+	}
+}
+
+func TestGenerateFindersSkipsTablesWithoutSingleKey(t *testing.T) {
+	noKey := &schema.Table{Name: "logs"}
+	noKey.String("line", 255)
+
+	composite := &schema.Table{Name: "role_users"}
+	composite.UUID("role_id").PrimaryKey()
+	composite.UUID("user_id").PrimaryKey()
+
+	for _, tbl := range []*schema.Table{noKey, composite} {
+		out, err := GenerateFinders(tbl, "models", "myapp/app/http")
+		if err != nil || out != nil {
+			t.Errorf("GenerateFinders(%s) = %q, %v; want nil", tbl.Name, out, err)
+		}
+	}
+}
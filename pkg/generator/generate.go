@@ -3,8 +3,6 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
-	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
 	"os/exec"
@@ -16,6 +14,7 @@ import (
 	"github.com/shortontech/pickle/pkg/blade"
 	"github.com/shortontech/pickle/pkg/schema"
 	"github.com/shortontech/pickle/pkg/tickle"
+	"gopkg.in/yaml.v3"
 )
 
 // Layout describes where generated and user-written files live.
@@ -25,6 +24,7 @@ type Layout struct {
 	HTTPPkg       string         // package name for HTTPDir ("pickle")
 	RequestsDir   string         // absolute path: where request structs + bindings_gen.go live
 	ModelsDir     string         // absolute path: where generated models live
+	ModelsPkg     string         // package name for ModelsDir ("models")
 	MigrationsDir string         // absolute path: where migration files live
 	MigrationsRel string         // relative to module root (e.g. "database/migrations")
 	ConfigDir     string         // absolute path: where config files live
@@ -50,6 +50,66 @@ type Project struct {
 	ModulePath string // Go module path from go.mod
 	Layout     Layout
 	Services   []ServiceLayout // populated in multi-service mode; empty = single-service
+	Requests   RequestsConfig  // requests: section of pickle.yaml
+
+	// SkipBrokenMigrations leaves migrations that don't compile out of schema
+	// inspection, with a warning, instead of failing (pickle generate --skip-broken).
+	SkipBrokenMigrations bool
+}
+
+// LayoutConfig is the layout: section of pickle.yaml. It renames the
+// generated packages for teams that don't import them as models/pickle.
+type LayoutConfig struct {
+	ModelsPackage string `yaml:"models_package,omitempty"` // default: "models"
+	HTTPPackage   string `yaml:"http_package,omitempty"`   // default: "pickle"
+}
+
+// Apply overrides the package names in layout with any configured values.
+func (lc LayoutConfig) Apply(layout *Layout) error {
+	if lc.ModelsPackage != "" {
+		if !token.IsIdentifier(lc.ModelsPackage) {
+			return fmt.Errorf("layout.models_package: %q is not a valid Go package name", lc.ModelsPackage)
+		}
+		layout.ModelsPkg = lc.ModelsPackage
+	}
+	if lc.HTTPPackage != "" {
+		if !token.IsIdentifier(lc.HTTPPackage) {
+			return fmt.Errorf("layout.http_package: %q is not a valid Go package name", lc.HTTPPackage)
+		}
+		layout.HTTPPkg = lc.HTTPPackage
+	}
+	return nil
+}
+
+// RequestsConfig is the requests: section of pickle.yaml.
+type RequestsConfig struct {
+	// ErrorFormat names the ValidationErrorFormatter generated bindings
+	// render BindingError with: "errors" (default), "flat", "jsonapi",
+	// "problem", or a name passed to RegisterValidationErrorFormat.
+	ErrorFormat string `yaml:"error_format,omitempty"`
+}
+
+// pickleConfig is the subset of pickle.yaml the generator reads.
+type pickleConfig struct {
+	Layout   LayoutConfig   `yaml:"layout"`
+	Requests RequestsConfig `yaml:"requests"`
+}
+
+// readPickleConfig reads dir/pickle.yaml, if any.
+func readPickleConfig(dir string) (pickleConfig, error) {
+	var file pickleConfig
+	data, err := os.ReadFile(filepath.Join(dir, "pickle.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return file, err
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("parsing pickle.yaml: %w", err)
+	}
+	file.Requests.ErrorFormat = strings.TrimSpace(file.Requests.ErrorFormat)
+	return file, nil
 }
 
 // DetectProject finds the project layout from the given directory.
@@ -64,7 +124,7 @@ func DetectProject(dir string) (*Project, error) {
 		return nil, fmt.Errorf("reading go.mod: %w", err)
 	}
 
-	return &Project{
+	project := &Project{
 		Dir:        absDir,
 		ModulePath: modPath,
 		Layout: Layout{
@@ -72,13 +132,24 @@ func DetectProject(dir string) (*Project, error) {
 			HTTPPkg:       "pickle",
 			RequestsDir:   filepath.Join(absDir, "app", "http", "requests"),
 			ModelsDir:     filepath.Join(absDir, "app", "models"),
+			ModelsPkg:     "models",
 			MigrationsDir: filepath.Join(absDir, "database", "migrations"),
 			MigrationsRel: "database/migrations",
 			ConfigDir:     filepath.Join(absDir, "config"),
 			CommandsDir:   filepath.Join(absDir, "app", "commands"),
 			AuthDir:       filepath.Join(absDir, "app", "http", "auth"),
 		},
-	}, nil
+	}
+
+	cfg, err := readPickleConfig(absDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Layout.Apply(&project.Layout); err != nil {
+		return nil, err
+	}
+	project.Requests = cfg.Requests
+	return project, nil
 }
 
 func readModulePath(goModPath string) (string, error) {
@@ -97,53 +168,44 @@ func readModulePath(goModPath string) (string, error) {
 // ScanMigrationStructs parses Go files in the migrations/ directory and
 // returns the struct names that embed Migration (sorted alphabetically).
 func ScanMigrationStructs(migrationsDir string) ([]string, error) {
-	entries, err := os.ReadDir(migrationsDir)
+	byFile, err := scanMigrationStructs(migrationsDir, nil)
 	if err != nil {
 		return nil, err
 	}
-
 	var structs []string
+	for _, names := range byFile {
+		structs = append(structs, names...)
+	}
+	sort.Strings(structs)
+	return structs, nil
+}
+
+// scanMigrationStructs returns the Migration-embedding struct names in each
+// Go file of migrationsDir, keyed by path. Paths in skip are not read.
+func scanMigrationStructs(migrationsDir string, skip map[string]bool) (map[string][]string, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
 
+	byFile := make(map[string][]string)
 	for _, e := range entries {
 		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
 			continue
 		}
-
 		path := filepath.Join(migrationsDir, e.Name())
-		fset := token.NewFileSet()
-		f, err := parser.ParseFile(fset, path, nil, 0)
+		if skip[path] {
+			continue
+		}
+		names, err := findMigrationStructs(migrationsDir, e.Name())
 		if err != nil {
 			return nil, fmt.Errorf("parsing %s: %w", path, err)
 		}
-
-		for _, decl := range f.Decls {
-			gen, ok := decl.(*ast.GenDecl)
-			if !ok {
-				continue
-			}
-			for _, spec := range gen.Specs {
-				ts, ok := spec.(*ast.TypeSpec)
-				if !ok {
-					continue
-				}
-				st, ok := ts.Type.(*ast.StructType)
-				if !ok {
-					continue
-				}
-				// Check if it embeds Migration
-				for _, field := range st.Fields.List {
-					if len(field.Names) == 0 {
-						if ident, ok := field.Type.(*ast.Ident); ok && ident.Name == "Migration" {
-							structs = append(structs, ts.Name.Name)
-						}
-					}
-				}
-			}
+		if len(names) > 0 {
+			byFile[path] = names
 		}
 	}
-
-	sort.Strings(structs)
-	return structs, nil
+	return byFile, nil
 }
 
 // inspectorTableInfo mirrors the JSON output from the schema inspector program.
@@ -167,27 +229,35 @@ type inspectorForeignKeyInfo struct {
 }
 
 type inspectorColumnInfo struct {
-	Name             string             `json:"name"`
-	Type             string             `json:"type"`
-	GoType           string             `json:"go_type"`
-	Nullable         bool               `json:"nullable"`
-	PrimaryKey       bool               `json:"primary_key,omitempty"`
-	Unique           bool               `json:"unique,omitempty"`
-	Default          any                `json:"default,omitempty"`
-	HasDefault       bool               `json:"has_default,omitempty"`
-	ForeignKeyTable  string             `json:"foreign_key_table,omitempty"`
-	ForeignKeyColumn string             `json:"foreign_key_column,omitempty"`
-	Length           int                `json:"length,omitempty"`
-	Precision        int                `json:"precision,omitempty"`
-	Scale            int                `json:"scale,omitempty"`
-	Public           bool               `json:"public,omitempty"`
-	OwnerSees        bool               `json:"owner_sees,omitempty"`
-	OwnerColumn      bool               `json:"owner_column,omitempty"`
-	VisibleTo        map[string]bool    `json:"visible_to,omitempty"`
-	Encrypted        bool               `json:"encrypted,omitempty"`
-	Sealed           bool               `json:"sealed,omitempty"`
-	UnsafePublic     bool               `json:"unsafe_public,omitempty"`
-	Seeder           *inspectorSeedInfo `json:"seeder,omitempty"`
+	Name             string              `json:"name"`
+	Type             string              `json:"type"`
+	GoType           string              `json:"go_type"`
+	Nullable         bool                `json:"nullable"`
+	PrimaryKey       bool                `json:"primary_key,omitempty"`
+	Unique           bool                `json:"unique,omitempty"`
+	Default          any                 `json:"default,omitempty"`
+	HasDefault       bool                `json:"has_default,omitempty"`
+	DefaultRaw       bool                `json:"default_raw,omitempty"`
+	ForeignKeyTable  string              `json:"foreign_key_table,omitempty"`
+	ForeignKeyColumn string              `json:"foreign_key_column,omitempty"`
+	Length           int                 `json:"length,omitempty"`
+	Precision        int                 `json:"precision,omitempty"`
+	Scale            int                 `json:"scale,omitempty"`
+	HasPrecision     bool                `json:"has_precision,omitempty"`
+	WithoutTimeZone  bool                `json:"without_time_zone,omitempty"`
+	Public           bool                `json:"public,omitempty"`
+	OwnerSees        bool                `json:"owner_sees,omitempty"`
+	OwnerColumn      bool                `json:"owner_column,omitempty"`
+	VisibleTo        map[string]bool     `json:"visible_to,omitempty"`
+	Encrypted        bool                `json:"encrypted,omitempty"`
+	Sealed           bool                `json:"sealed,omitempty"`
+	UnsafePublic     bool                `json:"unsafe_public,omitempty"`
+	Hidden           bool                `json:"hidden,omitempty"`
+	Seeder           *inspectorSeedInfo  `json:"seeder,omitempty"`
+	Check            string              `json:"check,omitempty"`
+	Comment          string              `json:"comment,omitempty"`
+	Transitions      map[string][]string `json:"transitions,omitempty"`
+	Using            string              `json:"using,omitempty"`
 }
 
 type inspectorSeedInfo struct {
@@ -201,6 +271,7 @@ type inspectorSeedInfo struct {
 type inspectorIndexInfo struct {
 	Columns []string `json:"columns"`
 	Unique  bool     `json:"unique"`
+	Name    string   `json:"name,omitempty"`
 }
 
 type inspectorViewInfo struct {
@@ -332,58 +403,75 @@ func RunSchemaInspector(project *Project) ([]*schema.Table, []*schema.View, []Sc
 // RunSchemaInspectorWithMigrations returns final schema state plus the recorded
 // per-migration operations used by tools that need lossless migration lowering.
 func RunSchemaInspectorWithMigrations(project *Project) ([]*schema.Table, []*schema.View, []SchemaRelationship, []MigrationOps, error) {
-	var entries []MigrationEntry
-
-	if len(project.Layout.MigrationDirs) > 0 {
-		// Monorepo: scan each configured migration directory
-		for _, md := range project.Layout.MigrationDirs {
-			structNames, err := ScanMigrationStructs(md.Dir)
-			if err != nil {
-				return nil, nil, nil, nil, fmt.Errorf("scanning migrations in %s: %w", md.Dir, err)
-			}
-			for _, name := range structNames {
-				entries = append(entries, MigrationEntry{StructName: name, ImportPath: md.ImportPath})
-			}
+	dirs := migrationDirs(project)
+	skipped := make(map[string]bool)
+	if broken := syntaxBrokenMigrations(project.Dir, dirs); len(broken) > 0 {
+		if !project.SkipBrokenMigrations {
+			return nil, nil, nil, nil, &BrokenMigrationsError{Migrations: broken}
 		}
-	} else {
-		// Single-app: scan the default migrations directory
-		migrationsDir := project.Layout.MigrationsDir
-		structNames, err := ScanMigrationStructs(migrationsDir)
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("scanning migrations: %w", err)
-		}
-		migrationsImport := project.ModulePath + "/" + project.Layout.MigrationsRel
-		for _, name := range structNames {
-			entries = append(entries, MigrationEntry{StructName: name, ImportPath: migrationsImport})
-		}
-	}
-
-	if len(entries) == 0 {
-		return nil, nil, nil, nil, nil
-	}
-
-	inspectorSrc, err := GenerateSchemaInspector(entries)
-	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("generating inspector: %w", err)
+		skipBrokenMigrations(project.Dir, skipped, broken)
 	}
 
-	// Write to a temp directory inside the project so it can resolve local imports
+	// Write to a temp directory inside the project so it can resolve local
+	// imports. A directory left by an interrupted run is replaced.
 	tmpDir := filepath.Join(project.Dir, ".pickle-tmp")
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("removing stale temp directory: %w", err)
+	}
 	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("creating temp directory: %w", err)
 	}
 	defer os.RemoveAll(tmpDir)
 
-	inspectorPath := filepath.Join(tmpDir, "main.go")
-	if err := os.WriteFile(inspectorPath, inspectorSrc, 0o644); err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("writing inspector: %w", err)
-	}
+	// With SkipBrokenMigrations, each compile failure drops the migrations it
+	// names and tries again, until the rest compile or nothing new is found.
+	var output []byte
+	for {
+		entries, err := inspectorEntries(project, skipped)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if len(entries) == 0 {
+			return nil, nil, nil, nil, nil
+		}
 
-	cmd := exec.Command("go", "run", inspectorPath, "--json")
-	cmd.Dir = project.Dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("running inspector: %w\n%s", err, output)
+		inspectorSrc, err := GenerateSchemaInspector(entries)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("generating inspector: %w", err)
+		}
+		inspectorPath := filepath.Join(tmpDir, "main.go")
+		if err := os.WriteFile(inspectorPath, inspectorSrc, 0o644); err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("writing inspector: %w", err)
+		}
+
+		args := []string{"run"}
+		if len(skipped) > 0 {
+			overlay, err := writeSkipOverlay(tmpDir, dirs, skipped)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("writing inspector overlay: %w", err)
+			}
+			args = append(args, "-overlay", overlay)
+		}
+		cmd := exec.Command("go", append(args, inspectorPath, "--json")...)
+		cmd.Dir = project.Dir
+		output, err = cmd.CombinedOutput()
+		if err == nil {
+			break
+		}
+
+		var fresh []BrokenMigration
+		for _, m := range compileBrokenMigrations(project.Dir, dirs, output) {
+			if !skipped[filepath.Join(project.Dir, m.File)] {
+				fresh = append(fresh, m)
+			}
+		}
+		if len(fresh) == 0 {
+			return nil, nil, nil, nil, fmt.Errorf("running inspector: %w\n%s", err, output)
+		}
+		if !project.SkipBrokenMigrations {
+			return nil, nil, nil, nil, &BrokenMigrationsError{Migrations: fresh}
+		}
+		skipBrokenMigrations(project.Dir, skipped, fresh)
 	}
 
 	var result inspectorOutput
@@ -443,6 +531,39 @@ func RunSchemaInspectorWithMigrations(project *Project) ([]*schema.Table, []*sch
 	return tables, views, rels, migrations, nil
 }
 
+// inspectorEntries lists the migration structs the inspector runs, leaving
+// out those declared in skipped files.
+func inspectorEntries(project *Project, skipped map[string]bool) ([]MigrationEntry, error) {
+	type source struct{ dir, importPath string }
+	var sources []source
+	if len(project.Layout.MigrationDirs) > 0 {
+		// Monorepo: scan each configured migration directory
+		for _, md := range project.Layout.MigrationDirs {
+			sources = append(sources, source{md.Dir, md.ImportPath})
+		}
+	} else {
+		// Single-app: scan the default migrations directory
+		sources = append(sources, source{project.Layout.MigrationsDir, project.ModulePath + "/" + project.Layout.MigrationsRel})
+	}
+
+	var entries []MigrationEntry
+	for _, src := range sources {
+		byFile, err := scanMigrationStructs(src.dir, skipped)
+		if err != nil {
+			return nil, fmt.Errorf("scanning migrations in %s: %w", src.dir, err)
+		}
+		var structNames []string
+		for _, names := range byFile {
+			structNames = append(structNames, names...)
+		}
+		sort.Strings(structNames)
+		for _, name := range structNames {
+			entries = append(entries, MigrationEntry{StructName: name, ImportPath: src.importPath})
+		}
+	}
+	return entries, nil
+}
+
 func convertInspectorColumn(ci inspectorColumnInfo, owner string) (*schema.Column, error) {
 	colType, ok := typeNameToColumnType[ci.Type]
 	if !ok {
@@ -459,6 +580,8 @@ func convertInspectorColumn(ci inspectorColumnInfo, owner string) (*schema.Colum
 		Length:           ci.Length,
 		Precision:        ci.Precision,
 		Scale:            ci.Scale,
+		HasPrecision:     ci.HasPrecision,
+		WithoutTimeZone:  ci.WithoutTimeZone,
 		IsPublic:         ci.Public,
 		IsOwnerSees:      ci.OwnerSees,
 		IsOwnerColumn:    ci.OwnerColumn,
@@ -466,7 +589,13 @@ func convertInspectorColumn(ci inspectorColumnInfo, owner string) (*schema.Colum
 		IsEncrypted:      ci.Encrypted,
 		IsSealed:         ci.Sealed,
 		IsUnsafePublic:   ci.UnsafePublic,
+		IsHidden:         ci.Hidden,
 		HasDefault:       ci.HasDefault,
+		DefaultIsRaw:     ci.DefaultRaw,
+		CheckExpr:        ci.Check,
+		CommentText:      ci.Comment,
+		TransitionMap:    ci.Transitions,
+		UsingExpr:        ci.Using,
 	}
 	if ci.HasDefault || ci.Default != nil {
 		col.DefaultValue = ci.Default
@@ -487,7 +616,7 @@ func convertInspectorTable(ti inspectorTableInfo) (*schema.Table, error) {
 		t.Columns = append(t.Columns, col)
 	}
 	for _, ii := range ti.Indexes {
-		t.Indexes = append(t.Indexes, &schema.Index{Table: ti.Name, Columns: ii.Columns, Unique: ii.Unique})
+		t.Indexes = append(t.Indexes, &schema.Index{Table: ti.Name, Columns: ii.Columns, Unique: ii.Unique, Name: ii.Name})
 	}
 	for _, fi := range ti.ForeignKeys {
 		t.ForeignKeys = append(t.ForeignKeys, &schema.ForeignKey{
@@ -542,7 +671,7 @@ func convertInspectorOperations(in []inspectorOperationInfo) ([]MigrationOperati
 			op.Columns = append(op.Columns, col)
 		}
 		if oi.Index != nil {
-			op.Index = &schema.Index{Table: oi.Table, Columns: oi.Index.Columns, Unique: oi.Index.Unique}
+			op.Index = &schema.Index{Table: oi.Table, Columns: oi.Index.Columns, Unique: oi.Index.Unique, Name: oi.Index.Name}
 		}
 		if oi.TableDef != nil {
 			table, err := convertInspectorTable(*oi.TableDef)
@@ -568,13 +697,17 @@ func convertInspectorOperations(in []inspectorOperationInfo) ([]MigrationOperati
 // Layout (Laravel-style):
 //   - {root}/app/http/pickle_gen.go         — HTTP types (Context, Response, Router, etc.)
 //   - {root}/app/http/requests/bindings_gen.go — Request deserialization + validation
+//   - {root}/app/http/requests/*_enum.go     — Typed constants from oneof= rules
 //   - {root}/app/models/pickle_gen.go       — QueryBuilder[T]
 //   - {root}/app/models/*.go                — Model structs and query scopes
+//   - {root}/app/models/*_enums_gen.go      — Typed values of CHECK (column IN (...)) columns
+//   - {root}/app/models/*_repository_gen.go — Repository interfaces for faking the database in tests
 //   - {root}/database/migrations/types_gen.go — Schema DSL types (Migration, Table, etc.)
 //   - {root}/config/pickle_gen.go           — Config glue
 func Generate(project *Project, picklePkgDir string) error {
 	layout := project.Layout
 	modelsDir := layout.ModelsDir
+	modelsPkg := layout.ModelsPkg
 	migrationsDir := layout.MigrationsDir
 	configDir := layout.ConfigDir
 	requestsDir := layout.RequestsDir
@@ -588,6 +721,7 @@ func Generate(project *Project, picklePkgDir string) error {
 		}
 
 		if len(scan.Configs) > 0 {
+			scan.DriverEnv = AuthDriverEnv(layout.AuthDir)
 			fmt.Println("  generating config/pickle_gen.go")
 			configSrc, err := GenerateConfigGlue(scan, "config")
 			if err != nil {
@@ -690,7 +824,7 @@ func Generate(project *Project, picklePkgDir string) error {
 	}
 
 	fmt.Println("  generating models/pickle_gen.go")
-	if err := writeFile(filepath.Join(modelsDir, "pickle_gen.go"), GenerateCoreQuery("models")); err != nil {
+	if err := writeFile(filepath.Join(modelsDir, "pickle_gen.go"), GenerateCoreQuery(modelsPkg)); err != nil {
 		return err
 	}
 
@@ -780,12 +914,25 @@ func Generate(project *Project, picklePkgDir string) error {
 			return err
 		}
 
+		// Migrations with syntax errors can't be scanned. Report them by
+		// file, or with --skip-broken leave them out; the schema inspector
+		// warns about each one.
+		skip := make(map[string]bool)
+		if broken := syntaxBrokenMigrations(project.Dir, migrationDirs(project)); len(broken) > 0 {
+			if !project.SkipBrokenMigrations {
+				return &BrokenMigrationsError{Migrations: broken}
+			}
+			for _, m := range broken {
+				skip[filepath.Join(project.Dir, m.File)] = true
+			}
+		}
+
 		fmt.Println("  generating migrations/registry_gen.go")
 		var localMigEntries []MigrationFileEntry
 		if len(layout.MigrationDirs) > 0 {
-			localMigEntries, err = ScanAllMigrationFiles(layout.MigrationDirs)
+			localMigEntries, err = scanAllMigrationFiles(layout.MigrationDirs, skip)
 		} else {
-			localMigEntries, err = ScanMigrationFiles(migrationsDir)
+			localMigEntries, err = scanMigrationFiles(migrationsDir, skip)
 		}
 		if err != nil {
 			return fmt.Errorf("scanning migration files: %w", err)
@@ -1015,7 +1162,7 @@ func Generate(project *Project, picklePkgDir string) error {
 		if err := writeFile(filepath.Join(policiesDir, "row_policies_gen.go"), rowPolicySrc); err != nil {
 			return err
 		}
-		runtimePolicySrc, err := GenerateRowPolicyRuntimeRegistry("models", resolvedRows, project.ModulePath+"/app/http/auth")
+		runtimePolicySrc, err := GenerateRowPolicyRuntimeRegistry(modelsPkg, resolvedRows, project.ModulePath+"/app/http/auth")
 		if err != nil {
 			return err
 		}
@@ -1023,11 +1170,11 @@ func Generate(project *Project, picklePkgDir string) error {
 		if err := writeFile(filepath.Join(modelsDir, "row_policies_gen.go"), runtimePolicySrc); err != nil {
 			return err
 		}
-		if err := writeFile(filepath.Join(modelsDir, "row_policy_test_adapter_gen_test.go"), GenerateRowPolicyTestAdapter("models")); err != nil {
+		if err := writeFile(filepath.Join(modelsDir, "row_policy_test_adapter_gen_test.go"), GenerateRowPolicyTestAdapter(modelsPkg)); err != nil {
 			return err
 		}
 	} else {
-		runtimePolicySrc, err := GenerateRowPolicyRuntimeRegistry("models", nil, project.ModulePath+"/app/http/auth")
+		runtimePolicySrc, err := GenerateRowPolicyRuntimeRegistry(modelsPkg, nil, project.ModulePath+"/app/http/auth")
 		if err != nil {
 			return err
 		}
@@ -1061,7 +1208,7 @@ func Generate(project *Project, picklePkgDir string) error {
 			if err := writeFile(filepath.Join(seedersDir, "pickle_gen.go"), source); err != nil {
 				return err
 			}
-			modelSource, err := GenerateSeederModelGlue("models", tables, false)
+			modelSource, err := GenerateSeederModelGlue(modelsPkg, tables, false)
 			if err != nil {
 				return fmt.Errorf("generating seeder model glue: %w", err)
 			}
@@ -1081,7 +1228,7 @@ func Generate(project *Project, picklePkgDir string) error {
 	if len(nestingMap) > 0 {
 		nestedDirs := map[string]string{} // dir → pkgName
 		for _, tbl := range tables {
-			dir, pkg := resolveModelDir(modelsDir, tbl.Name, nestingMap)
+			dir, pkg := resolveModelDir(modelsDir, modelsPkg, tbl.Name, nestingMap)
 			if dir != modelsDir {
 				nestedDirs[dir] = pkg
 			}
@@ -1096,10 +1243,16 @@ func Generate(project *Project, picklePkgDir string) error {
 
 	// 4. Generate models into models/ (or nested subdirectories)
 	if len(tables) > 0 {
+		counted := countedRelations(tables)
+		loaded := loadedRelations(tables, func(a, b string) bool {
+			dirA, _ := resolveModelDir(modelsDir, modelsPkg, a, nestingMap)
+			dirB, _ := resolveModelDir(modelsDir, modelsPkg, b, nestingMap)
+			return dirA == dirB
+		})
 		for _, tbl := range tables {
-			targetDir, pkgName := resolveModelDir(modelsDir, tbl.Name, nestingMap)
+			targetDir, pkgName := resolveModelDir(modelsDir, modelsPkg, tbl.Name, nestingMap)
 			fmt.Printf("  generating model: %s → %s\n", tbl.Name, pkgName)
-			src, err := GenerateModel(tbl, pkgName)
+			src, err := generateModel(tbl, pkgName, counted[tbl.Name], loaded[tbl.Name])
 			if err != nil {
 				return fmt.Errorf("generating model for %s: %w", tbl.Name, err)
 			}
@@ -1107,6 +1260,9 @@ func Generate(project *Project, picklePkgDir string) error {
 			if err := writeFile(filepath.Join(targetDir, filename), src); err != nil {
 				return err
 			}
+			if err := WriteModelEnums(targetDir, tbl, pkgName); err != nil {
+				return fmt.Errorf("generating enums for %s: %w", tbl.Name, err)
+			}
 		}
 	}
 
@@ -1114,7 +1270,7 @@ func Generate(project *Project, picklePkgDir string) error {
 	if len(tables) > 0 {
 		for _, tbl := range tables {
 			if HasOwnership(tbl) {
-				targetDir, pkgName := resolveModelDir(modelsDir, tbl.Name, nestingMap)
+				targetDir, pkgName := resolveModelDir(modelsDir, modelsPkg, tbl.Name, nestingMap)
 				fmt.Printf("  generating responses: %s\n", tbl.Name)
 				src, err := GenerateResponses(tbl, pkgName)
 				if err != nil {
@@ -1136,9 +1292,14 @@ func Generate(project *Project, picklePkgDir string) error {
 			if err != nil {
 				return fmt.Errorf("parsing scope blocks: %w", err)
 			}
+			customBlocks, err := readCustomScopeBlocks(project.Dir)
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, customBlocks...)
 
 			for _, tbl := range tables {
-				targetDir, pkgName := resolveModelDir(modelsDir, tbl.Name, nestingMap)
+				targetDir, pkgName := resolveModelDir(modelsDir, modelsPkg, tbl.Name, nestingMap)
 				fmt.Printf("  generating queries: %s\n", tbl.Name)
 				src, err := GenerateQueryScopes(tbl, blocks, pkgName)
 				if err != nil {
@@ -1148,17 +1309,53 @@ func Generate(project *Project, picklePkgDir string) error {
 				if err := writeFile(filepath.Join(targetDir, filename), src); err != nil {
 					return err
 				}
+
+				finderSrc, err := GenerateFinders(tbl, pkgName, project.ModulePath+"/app/http")
+				if err != nil {
+					return fmt.Errorf("generating finders for %s: %w", tbl.Name, err)
+				}
+				if finderSrc != nil {
+					filename := toLowerFirst(tableToStructName(tbl.Name)) + "_finders_gen.go"
+					if err := writeFile(filepath.Join(targetDir, filename), finderSrc); err != nil {
+						return err
+					}
+				}
+
+				repoSrc, err := GenerateRepository(tbl, pkgName)
+				if err != nil {
+					return fmt.Errorf("generating repository for %s: %w", tbl.Name, err)
+				}
+				filename = toLowerFirst(tableToStructName(tbl.Name)) + "_repository_gen.go"
+				if err := writeFile(filepath.Join(targetDir, filename), repoSrc); err != nil {
+					return err
+				}
 			}
 
 			// Generate Tx.Query<Model>() methods
 			fmt.Println("  generating transaction query methods")
-			txSrc, err := GenerateTxMethods(tables, nestingMap, modelsDir, "models")
+			txSrc, err := GenerateTxMethods(tables, nestingMap, modelsDir, modelsPkg)
 			if err != nil {
 				return fmt.Errorf("generating tx methods: %w", err)
 			}
 			if err := writeFile(filepath.Join(modelsDir, "tx_gen.go"), txSrc); err != nil {
 				return err
 			}
+
+			// Register WhereHas relations with every package's query builder
+			relDirs := map[string]string{modelsDir: modelsPkg}
+			for _, tbl := range tables {
+				dir, pkg := resolveModelDir(modelsDir, modelsPkg, tbl.Name, nestingMap)
+				relDirs[dir] = pkg
+			}
+			for dir, pkg := range relDirs {
+				relSrc, err := GenerateRelations(tables, pkg)
+				if err != nil {
+					return fmt.Errorf("generating relations: %w", err)
+				}
+				if err := writeFile(filepath.Join(dir, "relations_gen.go"), relSrc); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -1166,7 +1363,7 @@ func Generate(project *Project, picklePkgDir string) error {
 	if len(views) > 0 {
 		for _, view := range views {
 			fmt.Printf("  generating view model: %s\n", view.Name)
-			src, err := GenerateViewModel(view, "models")
+			src, err := GenerateViewModel(view, modelsPkg)
 			if err != nil {
 				return fmt.Errorf("generating view model for %s: %w", view.Name, err)
 			}
@@ -1185,10 +1382,15 @@ func Generate(project *Project, picklePkgDir string) error {
 			if err != nil {
 				return fmt.Errorf("parsing scope blocks: %w", err)
 			}
+			customBlocks, err := readCustomScopeBlocks(project.Dir)
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, customBlocks...)
 
 			for _, view := range views {
 				fmt.Printf("  generating view queries: %s\n", view.Name)
-				src, err := GenerateViewQueryScopes(view, blocks, "models")
+				src, err := GenerateViewQueryScopes(view, blocks, modelsPkg)
 				if err != nil {
 					return fmt.Errorf("generating view scopes for %s: %w", view.Name, err)
 				}
@@ -1237,7 +1439,7 @@ func Generate(project *Project, picklePkgDir string) error {
 
 			actionImportPath := project.ModulePath + "/database/actions/" + modelName
 			httpImportPath := project.ModulePath + "/app/http"
-			targetDir, pkgName := resolveModelDir(modelsDir, modelName+"s", nestingMap)
+			targetDir, pkgName := resolveModelDir(modelsDir, modelsPkg, modelName+"s", nestingMap)
 			fmt.Printf("  generating action wiring: %s\n", modelName)
 			src, err := GenerateActionWiringWithAudit(set, pkgName, actionImportPath, httpImportPath, auditImportPath)
 			if err != nil {
@@ -1274,7 +1476,7 @@ func Generate(project *Project, picklePkgDir string) error {
 			// modelDir is e.g. "user" → table name is "users"
 			tableName := modelDir + "s"
 			scopeImportPath := project.ModulePath + "/database/scopes/" + modelDir
-			targetDir, pkgName := resolveModelDir(modelsDir, tableName, nestingMap)
+			targetDir, pkgName := resolveModelDir(modelsDir, modelsPkg, tableName, nestingMap)
 			fmt.Printf("  generating scope wiring: %s\n", modelDir)
 			src, err := GenerateScopeWiring(tableName, scopes, pkgName, scopeImportPath)
 			if err != nil {
@@ -1299,21 +1501,9 @@ func Generate(project *Project, picklePkgDir string) error {
 	} else {
 		// Single-service mode: existing behavior
 		// 6. Generate bindings
-		requests, err := ScanRequests(requestsDir)
+		requests, err := generateRequestBindings(project, requestsDir, layout.HTTPDir)
 		if err != nil {
-			return fmt.Errorf("scanning requests: %w", err)
-		}
-
-		if len(requests) > 0 {
-			fmt.Println("  generating bindings")
-			bindingSrc, err := GenerateBindings(requests, "requests")
-			if err != nil {
-				return fmt.Errorf("generating bindings: %w", err)
-			}
-
-			if err := writeFile(filepath.Join(requestsDir, "bindings_gen.go"), bindingSrc); err != nil {
-				return err
-			}
+			return err
 		}
 
 		// 6b. Generate scheduler core if app/jobs/ exists
@@ -1347,50 +1537,94 @@ func Generate(project *Project, picklePkgDir string) error {
 		}
 
 		// 8. Generate commands glue if app/commands/ exists
-		commandsDir := layout.CommandsDir
-		if _, err := os.Stat(commandsDir); err == nil {
-			fmt.Println("  generating commands/pickle_gen.go")
-			userCmds, err := ScanCommands(commandsDir)
-			if err != nil {
-				return fmt.Errorf("scanning commands: %w", err)
-			}
+		if err := generateCommandsGlue(project, hasSeeders, hasRolePolicies); err != nil {
+			return err
+		}
+	}
 
-			// Scan routes/ for route vars (e.g. "API")
-			routesDir := filepath.Join(project.Dir, "routes")
-			var routeVars []string
-			if _, err := os.Stat(routesDir); err == nil {
-				var scanErr error
-				routeVars, scanErr = ScanRouteVars(routesDir)
-				if scanErr != nil {
-					return fmt.Errorf("scanning route vars: %w", scanErr)
-				}
-				// Advisory: warn about handlers from non-controllers packages
-				warnNonControllerHandlers(routesDir)
-			}
+	return nil
+}
 
-			// Check if auth directory exists
-			hasAuth := false
-			if _, err := os.Stat(layout.AuthDir); err == nil {
-				hasAuth = true
-			}
+// generateRequestBindings writes bindings_gen.go and the oneof= enums for the
+// requests in requestsDir, returning the scanned requests. httpDir is the HTTP
+// package BindingError.Response renders through.
+func generateRequestBindings(project *Project, requestsDir, httpDir string) ([]RequestDef, error) {
+	requests, err := ScanRequests(requestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("scanning requests: %w", err)
+	}
 
-			// Check if schedule/jobs.go exists
-			hasSchedule := false
-			if _, err := os.Stat(filepath.Join(project.Dir, "schedule", "jobs.go")); err == nil {
-				hasSchedule = true
-			}
+	if len(requests) > 0 {
+		fmt.Println("  generating bindings")
+		bindingSrc, err := GenerateBindings(requests, "requests", project.bindingOptions(httpDir))
+		if err != nil {
+			return nil, fmt.Errorf("generating bindings: %w", err)
+		}
 
-			cmdSrc, err := GenerateCommandsGlue(project.ModulePath, layout.MigrationsRel, userCmds, routeVars, hasAuth, hasSchedule, hasSeeders, hasRolePolicies)
-			if err != nil {
-				return fmt.Errorf("generating commands glue: %w", err)
-			}
-			if err := writeFile(filepath.Join(commandsDir, "pickle_gen.go"), cmdSrc); err != nil {
-				return err
-			}
+		if err := writeFile(filepath.Join(requestsDir, "bindings_gen.go"), bindingSrc); err != nil {
+			return nil, err
 		}
 	}
+	if err := WriteRequestEnums(requestsDir, requests, "requests"); err != nil {
+		return nil, fmt.Errorf("generating request enums: %w", err)
+	}
+	return requests, nil
+}
 
-	return nil
+// bindingOptions returns the BindingOptions for requests rendered through
+// the HTTP package in httpDir.
+func (p *Project) bindingOptions(httpDir string) BindingOptions {
+	opts := BindingOptions{ErrorFormat: p.Requests.ErrorFormat}
+	if rel, err := filepath.Rel(p.Dir, httpDir); err == nil && !strings.HasPrefix(rel, "..") {
+		opts.HTTPImport = p.ModulePath + "/" + filepath.ToSlash(rel)
+	}
+	return opts
+}
+
+// generateCommandsGlue writes commands/pickle_gen.go if app/commands/ exists,
+// wiring in the route vars declared under routes/.
+func generateCommandsGlue(project *Project, hasSeeders, hasRolePolicies bool) error {
+	layout := project.Layout
+	commandsDir := layout.CommandsDir
+	if _, err := os.Stat(commandsDir); err != nil {
+		return nil
+	}
+	fmt.Println("  generating commands/pickle_gen.go")
+	userCmds, err := ScanCommands(commandsDir)
+	if err != nil {
+		return fmt.Errorf("scanning commands: %w", err)
+	}
+
+	// Scan routes/ for route vars (e.g. "API")
+	routesDir := filepath.Join(project.Dir, "routes")
+	var routeVars []string
+	if _, err := os.Stat(routesDir); err == nil {
+		var scanErr error
+		routeVars, scanErr = ScanRouteVars(routesDir)
+		if scanErr != nil {
+			return fmt.Errorf("scanning route vars: %w", scanErr)
+		}
+		// Advisory: warn about handlers from non-controllers packages
+		warnNonControllerHandlers(routesDir)
+	}
+
+	// Check if auth directory exists
+	hasAuth := false
+	if _, err := os.Stat(layout.AuthDir); err == nil {
+		hasAuth = true
+	}
+
+	// Check if schedule/jobs.go exists
+	hasSchedule := false
+	if _, err := os.Stat(filepath.Join(project.Dir, "schedule", "jobs.go")); err == nil {
+		hasSchedule = true
+	}
+
+	cmdSrc, err := GenerateCommandsGlue(project.ModulePath, layout.MigrationsRel, userCmds, routeVars, hasAuth, hasSchedule, hasSeeders, hasRolePolicies)
+	if err != nil {
+		return fmt.Errorf("generating commands glue: %w", err)
+	}
+	return writeFile(filepath.Join(commandsDir, "pickle_gen.go"), cmdSrc)
 }
 
 // generateService generates per-service files: HTTP core, request bindings, commands.
@@ -1412,7 +1646,7 @@ func generateService(project *Project, svc ServiceLayout, picklePkgDir string) e
 		}
 		if len(reqs) > 0 {
 			fmt.Printf("    generating %s/http/requests/bindings_gen.go\n", svc.Name)
-			bindingSrc, err := GenerateBindings(reqs, "requests")
+			bindingSrc, err := GenerateBindings(reqs, "requests", project.bindingOptions(svc.HTTPDir))
 			if err != nil {
 				return fmt.Errorf("generating bindings: %w", err)
 			}
@@ -1420,6 +1654,9 @@ func generateService(project *Project, svc ServiceLayout, picklePkgDir string) e
 				return err
 			}
 		}
+		if err := WriteRequestEnums(svc.RequestsDir, reqs, "requests"); err != nil {
+			return fmt.Errorf("generating request enums: %w", err)
+		}
 	}
 
 	// Commands glue
@@ -1466,16 +1703,36 @@ func generateService(project *Project, svc ServiceLayout, picklePkgDir string) e
 	return nil
 }
 
+// readCustomScopeBlocks parses the project's compound scopes from
+// database/scopes/custom.go. The file is optional and may only contain
+// "pickle:scope custom" blocks; per-column templates belong to Pickle.
+func readCustomScopeBlocks(projectDir string) ([]tickle.ScopeBlock, error) {
+	path := filepath.Join(projectDir, "database", "scopes", "custom.go")
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	blocks, err := tickle.ParseScopeBlocks(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing custom scopes: %w", err)
+	}
+	for _, block := range blocks {
+		if !tickle.IsCustomScope(block.Scope) {
+			return nil, fmt.Errorf("%s: only pickle:scope custom blocks are allowed, got %q", path, block.Scope)
+		}
+	}
+	return blocks, nil
+}
+
 // resolveModelDir determines the output directory and package name for a table,
 // based on its position in the relationship nesting hierarchy.
-// - Top-level tables → models/ (package "models")
+// - Top-level tables → models/ (package modelsPkg, usually "models")
 // - Nested tables → models/parent/ (package "parent_singular")
-// - .TopLevelModel() → models/ (package "models")
+// - .TopLevelModel() → models/ (package modelsPkg)
 // - Deep nesting → models/parent/child/ etc.
-func resolveModelDir(modelsDir, tableName string, nestingMap map[string]SchemaRelationship) (string, string) {
+func resolveModelDir(modelsDir, modelsPkg, tableName string, nestingMap map[string]SchemaRelationship) (string, string) {
 	rel, isNested := nestingMap[tableName]
 	if !isNested || rel.TopLevel {
-		return modelsDir, "models"
+		return modelsDir, modelsPkg
 	}
 
 	// Build the path chain from child → parent
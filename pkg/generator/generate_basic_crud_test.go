@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -75,8 +76,11 @@ func TestGenerateBasicCrudScopes(t *testing.T) {
 		}
 
 		// Posts should have WithUser from foreign key
-		if tbl.Name == "posts" && !strings.Contains(src, "WithUser()") {
-			t.Error("missing WithUser eager loading for posts")
+		if tbl.Name == "posts" && (!strings.Contains(src, "WithUser()") || !strings.Contains(src, `q.EagerLoad("user")`)) {
+			t.Error("missing WithUser eager loading the user relation for posts")
+		}
+		if !strings.Contains(src, "WithNested(path string)") {
+			t.Errorf("missing WithNested for %s", tbl.Name)
 		}
 
 		t.Logf("generated %s_query.go (%d bytes)", tbl.Name, len(out))
@@ -107,3 +111,107 @@ func TestGenerateScopesDisambiguatesRepeatedForeignKeyTargets(t *testing.T) {
 		t.Errorf("repeated FK target retained ambiguous method name:\n%s", src)
 	}
 }
+
+func TestGenerateQueryScopesDecimalAggregates(t *testing.T) {
+	var m schema.Migration
+	m.CreateTable("transfers", func(tbl *schema.Table) {
+		tbl.UUID("id").PrimaryKey()
+		tbl.Decimal("amount", 18, 2).NotNull()
+		tbl.Integer("attempts").NotNull().Default(0)
+	})
+	src, err := GenerateQueryScopes(m.Operations[0].TableDef, loadScopeBlocks(t), "models")
+	if err != nil {
+		t.Fatalf("GenerateQueryScopes: %v", err)
+	}
+	for _, want := range []string{
+		"func (q *TransferQuery) SumAmount() (*decimal.Decimal, error) {",
+		"var result decimal.NullDecimal",
+		`if err := q.aggregateInto(&result, "SUM", "amount"); err != nil || !result.Valid {`,
+		"func (q *TransferQuery) AvgAmount() (*decimal.Decimal, error) {",
+		"func (q *TransferQuery) SumAttempts() (*float64, error) {",
+		`"github.com/shopspring/decimal"`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated query missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateQueryScopesJSONBColumns(t *testing.T) {
+	columns := []*schema.Column{
+		{Name: "id", Type: schema.UUID, IsPrimaryKey: true},
+		{Name: "settings", Type: schema.JSONB},
+	}
+	src, err := GenerateQueryScopes(&schema.Table{Name: "profiles", Columns: columns}, loadScopeBlocks(t), "models")
+	if err != nil {
+		t.Fatalf("GenerateQueryScopes: %v", err)
+	}
+	for _, want := range []string{
+		"func (q *ProfileQuery) WhereSettingsPath(path string, val any) *ProfileQuery {",
+		`q.WhereJSON("settings", path, val)`,
+		"func (q *ProfileQuery) WhereSettingsContains(val any) *ProfileQuery {",
+		`q.WhereJSONContains("settings", val)`,
+		"func (sb *ProfileScopeBuilder) WhereSettingsPath(path string, val any) *ProfileScopeBuilder {",
+		`sb.whereJSONContains("settings", val)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated query missing %q", want)
+		}
+	}
+
+	// Immutable queries don't render JSON operators, so they get no JSON scopes.
+	src, err = GenerateQueryScopes(&schema.Table{Name: "profiles", Columns: columns, IsImmutable: true}, loadScopeBlocks(t), "models")
+	if err != nil {
+		t.Fatalf("GenerateQueryScopes immutable: %v", err)
+	}
+	if strings.Contains(string(src), "WhereSettingsPath") || strings.Contains(string(src), "WhereSettingsContains") {
+		t.Errorf("immutable query should not have JSON scopes:\n%s", src)
+	}
+}
+
+func TestGenerateQueryScopesIncludesProjectCustomScopes(t *testing.T) {
+	projectDir := t.TempDir()
+	scopesDir := filepath.Join(projectDir, "database", "scopes")
+	if err := os.MkdirAll(scopesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	custom := `//go:build pickle_template
+
+package scopes
+
+// pickle:scope custom Published published_at
+func (q *QueryBuilder[T]) Published() *QueryBuilder[T] {
+	q.WhereRaw("published_at IS NOT NULL")
+	return q
+}
+
+// pickle:end
+`
+	if err := os.WriteFile(filepath.Join(scopesDir, "custom.go"), []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	customBlocks, err := readCustomScopeBlocks(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := append(loadScopeBlocks(t), customBlocks...)
+
+	tbl := &schema.Table{Name: "articles"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.Timestamp("published_at").Nullable()
+	out, err := GenerateQueryScopes(tbl, blocks, "models")
+	if err != nil {
+		t.Fatalf("GenerateQueryScopes: %v", err)
+	}
+	if !strings.Contains(string(out), "func (q *ArticleQuery) Published() *ArticleQuery {") {
+		t.Errorf("missing custom Published scope:\n%s", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(scopesDir, "custom.go"), []byte("// pickle:scope all\nfunc x() {}\n// pickle:end\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readCustomScopeBlocks(projectDir); err == nil {
+		t.Error("expected per-column blocks in the project scopes file to be rejected")
+	}
+}
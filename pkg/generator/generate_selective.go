@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// generationStep is a part of Generate that a changed path can require.
+type generationStep int
+
+const (
+	stepNone     generationStep = iota // nothing is generated from the path
+	stepBindings                       // request bindings and enums
+	stepCommands                       // commands glue (route vars)
+	stepFull                           // everything, via Generate
+)
+
+// GenerateSelective regenerates only what the changed paths affect, falling
+// back to Generate when the change set is ambiguous:
+//
+//   - app/http/requests: request bindings and enums
+//   - routes: commands glue
+//   - app/http/controllers: nothing, controllers are compiled as written
+//   - anything else, including database/migrations: everything, since models,
+//     queries, GraphQL, policies and seeders all derive from the schema
+//
+// Multi-service projects, and projects whose GraphQL layer is built from the
+// request structs, always regenerate everything.
+func GenerateSelective(project *Project, picklePkgDir string, changedDirs []string) error {
+	steps := map[generationStep]bool{}
+	for _, path := range changedDirs {
+		steps[classifyChange(project, path)] = true
+	}
+	if steps[stepFull] || len(project.Services) > 0 {
+		return Generate(project, picklePkgDir)
+	}
+	if steps[stepBindings] {
+		if _, err := os.Stat(filepath.Join(project.Dir, "app", "graphql")); err == nil {
+			return Generate(project, picklePkgDir)
+		}
+		if _, err := generateRequestBindings(project, project.Layout.RequestsDir, project.Layout.HTTPDir); err != nil {
+			return err
+		}
+	}
+	if steps[stepCommands] {
+		hasSeeders, err := hasScenarioSeeders(project)
+		if err != nil {
+			return err
+		}
+		hasRolePolicies, err := hasRolePolicyFiles(project)
+		if err != nil {
+			return err
+		}
+		if err := generateCommandsGlue(project, hasSeeders, hasRolePolicies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// classifyChange maps a changed path, absolute or relative to the project,
+// to the generation step it requires.
+func classifyChange(project *Project, path string) generationStep {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(project.Dir, path)
+	}
+	within := func(dir string) bool {
+		return dir != "" && (path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)))
+	}
+	switch {
+	case within(project.Layout.RequestsDir):
+		return stepBindings
+	case within(filepath.Join(project.Dir, "routes")):
+		return stepCommands
+	case within(filepath.Join(project.Dir, "app", "http", "controllers")):
+		return stepNone
+	}
+	return stepFull
+}
+
+// hasScenarioSeeders reports whether database/seeders declares a scenario,
+// which adds the db:seed commands to the commands glue.
+func hasScenarioSeeders(project *Project) (bool, error) {
+	seedersDir := filepath.Join(project.Dir, "database", "seeders")
+	if _, err := os.Stat(seedersDir); err != nil {
+		return false, nil
+	}
+	definitions, err := ScanSeeders(seedersDir)
+	if err != nil {
+		return false, fmt.Errorf("scanning seeders: %w", err)
+	}
+	for _, definition := range definitions {
+		if definition.Kind == "scenario" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasRolePolicyFiles reports whether database/policies declares role policies.
+func hasRolePolicyFiles(project *Project) (bool, error) {
+	policiesDir := filepath.Join(project.Dir, "database", "policies")
+	if _, err := os.Stat(policiesDir); err != nil {
+		return false, nil
+	}
+	entries, err := ScanPolicyFiles(policiesDir)
+	if err != nil {
+		return false, fmt.Errorf("scanning policy files: %w", err)
+	}
+	return len(entries) > 0, nil
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func selectiveTestProject(t *testing.T) *Project {
+	t.Helper()
+	dir := t.TempDir()
+	project := &Project{
+		Dir:        dir,
+		ModulePath: "example.com/app",
+		Layout: Layout{
+			HTTPDir:       filepath.Join(dir, "app", "http"),
+			HTTPPkg:       "pickle",
+			RequestsDir:   filepath.Join(dir, "app", "http", "requests"),
+			ModelsDir:     filepath.Join(dir, "app", "models"),
+			ModelsPkg:     "models",
+			MigrationsDir: filepath.Join(dir, "database", "migrations"),
+			MigrationsRel: "database/migrations",
+			ConfigDir:     filepath.Join(dir, "config"),
+			CommandsDir:   filepath.Join(dir, "app", "commands"),
+			AuthDir:       filepath.Join(dir, "app", "http", "auth"),
+		},
+	}
+	files := map[string]string{
+		"app/http/requests/login.go": "package requests\n\ntype LoginRequest struct {\n\tEmail string `json:\"email\" validate:\"required,email\"`\n}\n",
+		"routes/web.go":              "package routes\n\nvar API = pickle.Routes(func(r *pickle.Router) {})\n",
+	}
+	for rel, src := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(project.Layout.CommandsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func TestClassifyChange(t *testing.T) {
+	project := selectiveTestProject(t)
+	tests := []struct {
+		path string
+		want generationStep
+	}{
+		{"app/http/requests/login.go", stepBindings},
+		{filepath.Join(project.Dir, "app", "http", "requests"), stepBindings},
+		{"routes/web.go", stepCommands},
+		{"app/http/controllers/post_controller.go", stepNone},
+		{"database/migrations/2026_01_01_create_posts.go", stepFull},
+		{"config/app.go", stepFull},
+		{"app/http/middleware/auth.go", stepFull},
+		{"routes_extra/web.go", stepFull},
+	}
+	for _, tt := range tests {
+		if got := classifyChange(project, tt.path); got != tt.want {
+			t.Errorf("classifyChange(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSelectiveRunsOnlyAffectedSteps(t *testing.T) {
+	project := selectiveTestProject(t)
+	bindings := filepath.Join(project.Layout.RequestsDir, "bindings_gen.go")
+	commands := filepath.Join(project.Layout.CommandsDir, "pickle_gen.go")
+	core := filepath.Join(project.Layout.HTTPDir, "pickle_gen.go")
+
+	if err := GenerateSelective(project, "", []string{filepath.Join(project.Dir, "app", "http", "requests", "login.go")}); err != nil {
+		t.Fatalf("requests change: %v", err)
+	}
+	if _, err := os.Stat(bindings); err != nil {
+		t.Errorf("requests change should write bindings: %v", err)
+	}
+	if _, err := os.Stat(commands); err == nil {
+		t.Error("requests change should not write the commands glue")
+	}
+
+	if err := GenerateSelective(project, "", []string{"routes/web.go", "app/http/controllers/post_controller.go"}); err != nil {
+		t.Fatalf("routes change: %v", err)
+	}
+	if _, err := os.Stat(commands); err != nil {
+		t.Errorf("routes change should write the commands glue: %v", err)
+	}
+	if _, err := os.Stat(core); err == nil {
+		t.Error("selective generation should not rewrite the HTTP core")
+	}
+}
@@ -46,7 +46,7 @@ func GenerateGraphQLCRUDResolvers(cfg CRUDConfig) ([]byte, error) {
 	b.WriteString("\t\"strconv\"\n")
 	b.WriteString("\t\"strings\"\n")
 	b.WriteString("\t\"time\"\n\n")
-	b.WriteString(fmt.Sprintf("\t\"%s\"\n", cfg.ModelsImport))
+	b.WriteString(fmt.Sprintf("\tmodels \"%s\"\n", cfg.ModelsImport))
 	b.WriteString("\t\"github.com/google/uuid\"\n")
 	b.WriteString(")\n\n")
 	b.WriteString("var _ = json.RawMessage{}\n")
@@ -159,11 +159,11 @@ func writeCRUDCreateMutation(b *bytes.Buffer, tbl *schema.Table, childToParent m
 			continue
 		}
 		goFieldName := snakeToPascal(col.Name)
-		conv := inputToModelConversion(col, fmt.Sprintf("input.%s", goFieldName), false)
+		conv := inputToModelConversion(tbl, col, fmt.Sprintf("input.%s", goFieldName), false)
 
 		if col.IsNullable || col.DefaultValue != nil {
 			b.WriteString(fmt.Sprintf("\tif input.%s != nil {\n", goFieldName))
-			conv = inputToModelConversion(col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
+			conv = inputToModelConversion(tbl, col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
 			b.WriteString(fmt.Sprintf("\t\trecord.%s = %s\n", goFieldName, conv))
 			b.WriteString("\t}\n")
 		} else {
@@ -235,7 +235,7 @@ func writeCRUDUpdateMutation(b *bytes.Buffer, tbl *schema.Table) {
 			continue // never allow changing owner
 		}
 		goFieldName := snakeToPascal(col.Name)
-		conv := inputToModelConversion(col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
+		conv := inputToModelConversion(tbl, col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
 		b.WriteString(fmt.Sprintf("\tif input.%s != nil {\n\t\trecord.%s = %s\n\t}\n", goFieldName, goFieldName, conv))
 	}
 
@@ -325,10 +325,14 @@ func writeNestedCreateMutation(b *bytes.Buffer, tbl *schema.Table, rel SchemaRel
 	}
 
 	// Verify parent exists
+	parentPKMethod := "WhereID"
+	if pk := pkColumn(parentTable); pk != nil {
+		parentPKMethod = "Where" + snakeToPascal(pk.Name)
+	}
 	if needsError {
-		b.WriteString(fmt.Sprintf("\t_, err = models.Query%s().WhereID(parentID).First()\n", parentStructName))
+		b.WriteString(fmt.Sprintf("\t_, err = models.Query%s().%s(parentID).First()\n", parentStructName, parentPKMethod))
 	} else {
-		b.WriteString(fmt.Sprintf("\t_, err := models.Query%s().WhereID(parentID).First()\n", parentStructName))
+		b.WriteString(fmt.Sprintf("\t_, err := models.Query%s().%s(parentID).First()\n", parentStructName, parentPKMethod))
 	}
 	b.WriteString("\tif err != nil {\n")
 	b.WriteString(fmt.Sprintf("\t\treturn nil, NotFound(\"%s\")\n", parentSingular))
@@ -373,11 +377,11 @@ func writeNestedCreateMutation(b *bytes.Buffer, tbl *schema.Table, rel SchemaRel
 			continue // already set from auth
 		}
 		goFieldName := snakeToPascal(col.Name)
-		conv := inputToModelConversion(col, fmt.Sprintf("input.%s", goFieldName), false)
+		conv := inputToModelConversion(tbl, col, fmt.Sprintf("input.%s", goFieldName), false)
 
 		if col.IsNullable || col.DefaultValue != nil {
 			b.WriteString(fmt.Sprintf("\tif input.%s != nil {\n", goFieldName))
-			conv = inputToModelConversion(col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
+			conv = inputToModelConversion(tbl, col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
 			b.WriteString(fmt.Sprintf("\t\trecord.%s = %s\n", goFieldName, conv))
 			b.WriteString("\t}\n")
 		} else {
@@ -155,6 +155,23 @@ func TestNestedCreateMutationWithStringParentPrimaryKey(t *testing.T) {
 	}
 }
 
+func TestNestedCreateMutationWithCustomParentPrimaryKey(t *testing.T) {
+	tables := []*schema.Table{
+		{Name: "accounts", Columns: []*schema.Column{{Name: "code", Type: schema.String, IsPrimaryKey: true}}},
+		{Name: "entries", Columns: []*schema.Column{{Name: "id", Type: schema.UUID, IsPrimaryKey: true}, {Name: "account_id", Type: schema.String}}},
+	}
+	src, err := GenerateGraphQLCRUDResolvers(CRUDConfig{
+		Tables: tables, Relationships: []SchemaRelationship{{ParentTable: "accounts", ChildTable: "entries", Type: "has_many"}},
+		ModelsImport: "myapp/app/models", PackageName: "graphql",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := string(src); !strings.Contains(text, ".WhereCode(parentID).First()") {
+		t.Fatalf("nested create should look the parent up by its primary key column:\n%s", text)
+	}
+}
+
 func TestConstraintValidation(t *testing.T) {
 	tables := []*schema.Table{
 		{
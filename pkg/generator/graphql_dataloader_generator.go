@@ -31,7 +31,7 @@ func GenerateGraphQLDataloaders(tables []*schema.Table, relationships []SchemaRe
 
 	b.WriteString("import (\n")
 	b.WriteString("\t\"fmt\"\n")
-	b.WriteString(fmt.Sprintf("\t\"%s\"\n", modelsImport))
+	b.WriteString(fmt.Sprintf("\tmodels \"%s\"\n", modelsImport))
 	b.WriteString("\t\"github.com/google/uuid\"\n")
 	b.WriteString(")\n\n")
 	b.WriteString("var _ = uuid.Nil\n\n")
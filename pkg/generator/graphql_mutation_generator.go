@@ -72,7 +72,7 @@ func GenerateGraphQLMutationsWithPlans(plans []GraphQLModelPlan, modelsImport, p
 		b.WriteString("\n")
 	}
 	if needsModels {
-		b.WriteString(fmt.Sprintf("\t\"%s\"\n", modelsImport))
+		b.WriteString(fmt.Sprintf("\tmodels \"%s\"\n", modelsImport))
 	}
 	b.WriteString("\t\"github.com/google/uuid\"\n")
 	if needsDecimal {
@@ -175,7 +175,13 @@ func GenerateGraphQLMutationsWithPlans(plans []GraphQLModelPlan, modelsImport, p
 
 // inputToModelConversion returns the Go code to convert a GQL input field (string)
 // to the model field's actual Go type. If asPointer is true, returns a pointer expression.
-func inputToModelConversion(col *schema.Column, inputExpr string, asPointer bool) string {
+func inputToModelConversion(tbl *schema.Table, col *schema.Column, inputExpr string, asPointer bool) string {
+	if enum := enumTypeName(tbl, col); enum != "" {
+		if asPointer {
+			return fmt.Sprintf("func() *models.%s { v := models.%s(%s); return &v }()", enum, enum, inputExpr)
+		}
+		return fmt.Sprintf("models.%s(%s)", enum, inputExpr)
+	}
 	switch col.Type {
 	case schema.UUID:
 		if asPointer {
@@ -233,12 +239,12 @@ func writeCreateMutation(b *bytes.Buffer, tbl *schema.Table) {
 			continue
 		}
 		goFieldName := snakeToPascal(col.Name)
-		conv := inputToModelConversion(col, fmt.Sprintf("input.%s", goFieldName), false)
+		conv := inputToModelConversion(tbl, col, fmt.Sprintf("input.%s", goFieldName), false)
 
 		if col.IsNullable || col.DefaultValue != nil {
 			// Pointer field - check if provided
 			b.WriteString(fmt.Sprintf("\tif input.%s != nil {\n", goFieldName))
-			conv = inputToModelConversion(col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
+			conv = inputToModelConversion(tbl, col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
 			b.WriteString(fmt.Sprintf("\t\trecord.%s = %s\n", goFieldName, conv))
 			b.WriteString("\t}\n")
 		} else {
@@ -291,7 +297,7 @@ func writeUpdateMutation(b *bytes.Buffer, tbl *schema.Table) {
 			continue
 		}
 		goFieldName := snakeToPascal(col.Name)
-		conv := inputToModelConversion(col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
+		conv := inputToModelConversion(tbl, col, fmt.Sprintf("*input.%s", goFieldName), col.IsNullable)
 		b.WriteString(fmt.Sprintf("\tif input.%s != nil {\n\t\trecord.%s = %s\n\t}\n", goFieldName, goFieldName, conv))
 	}
 
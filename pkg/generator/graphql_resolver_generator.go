@@ -42,7 +42,7 @@ func GenerateGraphQLResolversWithPlans(plans []GraphQLModelPlan, relationships [
 	b.WriteString("\t\"strconv\"\n")
 	b.WriteString("\t\"strings\"\n")
 	b.WriteString("\t\"time\"\n\n")
-	b.WriteString(fmt.Sprintf("\t\"%s\"\n", modelsImport))
+	b.WriteString(fmt.Sprintf("\tmodels \"%s\"\n", modelsImport))
 	b.WriteString("\t\"github.com/google/uuid\"\n")
 	b.WriteString(")\n\n")
 
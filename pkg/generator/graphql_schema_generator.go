@@ -498,7 +498,7 @@ func isExcludedFromInput(col *schema.Column) bool {
 	if col.Type == schema.Binary {
 		return true
 	}
-	if col.Name == "password_hash" || col.Name == "password" {
+	if IsHiddenColumn(col) {
 		return true
 	}
 	return false
@@ -65,8 +65,8 @@ func isExcludedFromGraphQL(tbl *schema.Table, col *schema.Column) bool {
 	if col.Type == schema.Binary {
 		return true
 	}
-	// Password fields are never exposed
-	if col.Name == "password_hash" || col.Name == "password" {
+	// Password and Hidden() fields are never exposed
+	if IsHiddenColumn(col) {
 		return true
 	}
 	// Hash chain internal columns
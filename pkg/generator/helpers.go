@@ -20,6 +20,14 @@ func columnGoType(col *schema.Column) string {
 	return names.ColumnGoType(col)
 }
 
+func enumTypeName(table *schema.Table, col *schema.Column) string {
+	return names.EnumTypeName(table, col)
+}
+
+func modelFieldType(table *schema.Table, col *schema.Column) string {
+	return names.ModelFieldType(table, col)
+}
+
 func columnImport(col *schema.Column) string {
 	return names.ColumnImport(col)
 }
@@ -22,7 +22,10 @@ import (
 {{ end }}
 type {{ .StructName }} struct {
 {{- range .Fields }}
-	{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .JSONTag }}" db:"{{ .DBTag }}"` + "`" + `
+{{- range .Comment }}
+	// {{ . }}
+{{- end }}
+	{{ .Name }} {{ .Type }} ` + "`" + `json:"{{ .JSONTag }}" {{ if .CountOf }}count:"{{ .CountOf }}"{{ else if .RelationOf }}rel:"{{ .RelationOf }}"{{ else }}db:"{{ .DBTag }}"{{ end }}{{ if .PrimaryKey }} pickle:"pk"{{ end }}` + "`" + `
 {{- end }}
 }
 {{ if .IsImmutable }}
@@ -75,6 +78,18 @@ func Public{{ .StructName }}s(records []{{ .StructName }}) []{{ .StructName }}Pu
 	}
 	return result
 }
+{{ end }}{{ if .HasHidden }}
+// MarshalJSON encodes the {{ .StructName }}Public projection, so hidden fields
+// never reach a response even when the model is returned directly.
+func (m {{ .StructName }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Public())
+}
+
+// JSONValue returns what MarshalJSON encodes, so pickle.JSONEncoder applies
+// JSONSettings to the projection instead of passing this through as-is.
+func (m {{ .StructName }}) JSONValue() any {
+	return m.Public()
+}
 {{ end }}
 `))
 
@@ -87,17 +102,54 @@ type modelData struct {
 	IsImmutable  bool
 	IsAppendOnly bool
 	OwnerField   string // Go field name of the IsOwner column, if any
+	HasHidden    bool   // some column is hidden; emits MarshalJSON via Public()
 }
 
 type fieldData struct {
-	Name    string
-	Type    string
-	JSONTag string
-	DBTag   string
+	Name       string
+	Type       string
+	JSONTag    string
+	DBTag      string
+	PrimaryKey bool
+	Comment    []string // doc comment lines from the column's Comment()
+	CountOf    string   // relation WithCount fills this field from; not a column
+	RelationOf string   // relation EagerLoad fills this field from; not a column
+}
+
+// IsHiddenColumn reports whether a column is never serialized: either it is
+// marked Hidden() in its migration, or it is a password or hash-chain column,
+// which are always hidden.
+func IsHiddenColumn(col *schema.Column) bool {
+	if col.IsHidden {
+		return true
+	}
+	switch col.Name {
+	case "password", "password_hash", "row_hash", "prev_hash":
+		return true
+	}
+	return false
+}
+
+// commentLines splits a column comment into lines for a Go doc comment.
+func commentLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// GenerateModel produces a Go source file containing the model struct for a
+// table. Each relation in counted gets an int64 {Relation}Count field, filled
+// when the query asks for it with WithCount.
+func GenerateModel(table *schema.Table, packageName string, counted ...string) ([]byte, error) {
+	return generateModel(table, packageName, counted, nil)
 }
 
-// GenerateModel produces a Go source file containing the model struct for a table.
-func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
+// generateModel is GenerateModel with the relations EagerLoad can fill:
+// each gets a field tagged rel:"{relation}", a slice of the related model
+// for to-many relations and a pointer to it for belongs-to.
+func generateModel(table *schema.Table, packageName string, counted []string, loaded []relation) ([]byte, error) {
 	imports := map[string]bool{}
 	var fields []fieldData
 
@@ -117,8 +169,7 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 		}
 
 		jsonTag := col.Name
-		if col.Name == "password" || col.Name == "password_hash" ||
-			col.Name == "row_hash" || col.Name == "prev_hash" {
+		if IsHiddenColumn(col) {
 			jsonTag = "-"
 		} else if col.IsNullable {
 			jsonTag += ",omitempty"
@@ -131,6 +182,7 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 				Type:    goType,
 				JSONTag: jsonTag,
 				DBTag:   "-",
+				Comment: commentLines(col.CommentText),
 			})
 			// Add _encrypted column (TEXT, same nullability)
 			encColName := col.Name + "_encrypted"
@@ -162,14 +214,66 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 			})
 		} else {
 			fields = append(fields, fieldData{
-				Name:    snakeToPascal(col.Name),
-				Type:    goType,
-				JSONTag: jsonTag,
-				DBTag:   col.Name,
+				Name:       snakeToPascal(col.Name),
+				Type:       modelFieldType(table, col),
+				JSONTag:    jsonTag,
+				DBTag:      col.Name,
+				PrimaryKey: col.IsPrimaryKey,
+				Comment:    commentLines(col.CommentText),
 			})
 		}
 	}
 
+	taken := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		taken[f.Name] = true
+	}
+	for _, name := range counted {
+		alias := strings.ReplaceAll(name, ".", "_") + "_count"
+		field := snakeToPascal(alias)
+		if taken[field] {
+			// A real column (a cached counter) already has the name.
+			continue
+		}
+		taken[field] = true
+		fields = append(fields, fieldData{
+			Name:    field,
+			Type:    "int64",
+			JSONTag: alias + ",omitempty",
+			CountOf: name,
+			Comment: []string{fmt.Sprintf("%s is the number of related %s, set by WithCount(%q).", field, name, name)},
+		})
+	}
+
+	for _, r := range loaded {
+		field := snakeToPascal(strings.ReplaceAll(r.Name, ".", "_"))
+		if taken[field] {
+			continue
+		}
+		taken[field] = true
+		typ := "*" + tableToStructName(r.Table)
+		if r.ToMany {
+			typ = "[]" + tableToStructName(r.Table)
+		}
+		fields = append(fields, fieldData{
+			Name:       field,
+			Type:       typ,
+			JSONTag:    strings.ReplaceAll(r.Name, ".", "_") + ",omitempty",
+			RelationOf: r.Name,
+			Comment:    []string{fmt.Sprintf("%s holds the related %s, loaded by EagerLoad(%q).", field, r.Table, r.Name)},
+		})
+	}
+
+	// Hidden columns get a MarshalJSON that encodes the Public projection
+	hasHiddenColumn := false
+	for _, col := range table.Columns {
+		if IsHiddenColumn(col) {
+			hasHiddenColumn = true
+			imports["encoding/json"] = true
+			break
+		}
+	}
+
 	// Immutable/append-only tables need time for CreatedAt()/UpdatedAt() methods
 	if table.IsImmutable || table.IsAppendOnly {
 		imports["time"] = true
@@ -181,6 +285,14 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 		imports["fmt"] = true
 	}
 
+	// Transition validators format their errors with fmt.
+	for _, col := range table.Columns {
+		if len(col.TransitionMap) > 0 {
+			imports["fmt"] = true
+			break
+		}
+	}
+
 	var sortedImports []string
 	for imp := range imports {
 		sortedImports = append(sortedImports, imp)
@@ -229,6 +341,7 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 		IsImmutable:  table.IsImmutable,
 		IsAppendOnly: table.IsAppendOnly,
 		OwnerField:   ownerField,
+		HasHidden:    hasHiddenColumn,
 	}
 	if hasHidden {
 		data.PublicFields = publicFields
@@ -272,6 +385,8 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 		buf.WriteString(fmt.Sprintf("}\n"))
 	}
 
+	generateModelTransitions(&buf, table, tableToStructName(table.Name))
+
 	// Generate ForRoles and ForOwner serialization methods if table has role visibility
 	generateModelRoleViews(&buf, table, tableToStructName(table.Name))
 
@@ -283,6 +398,58 @@ func GenerateModel(table *schema.Table, packageName string) ([]byte, error) {
 	return formatted, nil
 }
 
+// generateModelTransitions emits, for each column declared with
+// Transitions(), a {Struct}{Column}Transitions adjacency map and a
+// Validate{Column}Transition(from, to) method checking a change against it.
+// States that appear only as targets are added as final states.
+func generateModelTransitions(buf *bytes.Buffer, table *schema.Table, structName string) {
+	for _, col := range table.Columns {
+		if len(col.TransitionMap) == 0 {
+			continue
+		}
+		field := snakeToPascal(col.Name)
+		mapName := structName + field + "Transitions"
+		label := strings.ReplaceAll(col.Name, "_", " ")
+
+		states := map[string]bool{}
+		for from, targets := range col.TransitionMap {
+			states[from] = true
+			for _, to := range targets {
+				states[to] = true
+			}
+		}
+		sorted := make([]string, 0, len(states))
+		for state := range states {
+			sorted = append(sorted, state)
+		}
+		sort.Strings(sorted)
+
+		fmt.Fprintf(buf, "\n// %s maps each %s to the values it may change to.\n", mapName, label)
+		fmt.Fprintf(buf, "// Declared with Transitions() in the migration; final states map to nothing.\n")
+		fmt.Fprintf(buf, "var %s = map[string][]string{\n", mapName)
+		for _, state := range sorted {
+			targets := make([]string, len(col.TransitionMap[state]))
+			for i, to := range col.TransitionMap[state] {
+				targets[i] = fmt.Sprintf("%q", to)
+			}
+			fmt.Fprintf(buf, "\t%q: {%s},\n", state, strings.Join(targets, ", "))
+		}
+		buf.WriteString("}\n\n")
+
+		fmt.Fprintf(buf, "// Validate%sTransition returns an error unless %s may change from\n", field, label)
+		fmt.Fprintf(buf, "// from to to. Keeping the same value is always allowed.\n")
+		fmt.Fprintf(buf, "func (%s) Validate%sTransition(from, to string) error {\n", structName, field)
+		fmt.Fprintf(buf, "\tif _, ok := %s[to]; !ok {\n", mapName)
+		fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"%%q is not a valid %s\", to)\n", label)
+		buf.WriteString("\t}\n")
+		buf.WriteString("\tif from == to {\n\t\treturn nil\n\t}\n")
+		fmt.Fprintf(buf, "\tfor _, next := range %s[from] {\n", mapName)
+		buf.WriteString("\t\tif next == to {\n\t\t\treturn nil\n\t\t}\n\t}\n")
+		fmt.Fprintf(buf, "\treturn fmt.Errorf(\"%s cannot change from %%q to %%q\", from, to)\n", label)
+		buf.WriteString("}\n")
+	}
+}
+
 // generateModelRoleViews emits ForRoles(roles) and ForOwner(roles) serialization
 // methods on the model struct. These return a map[string]any containing only the
 // fields visible to the given role set.
@@ -309,8 +476,7 @@ func generateModelRoleViews(buf *bytes.Buffer, table *schema.Table, structName s
 	}
 	var cols []colVis
 	for _, col := range table.Columns {
-		if col.Name == "password" || col.Name == "password_hash" ||
-			col.Name == "row_hash" || col.Name == "prev_hash" {
+		if IsHiddenColumn(col) {
 			continue // never serialized
 		}
 		if col.IsEncrypted || col.IsSealed {
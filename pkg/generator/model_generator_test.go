@@ -51,6 +51,11 @@ func TestGenerateModelUsers(t *testing.T) {
 	if !strings.Contains(src, "Code generated by Pickle. DO NOT EDIT.") {
 		t.Errorf("missing generated header\n%s", src)
 	}
+
+	// Only the primary key is tagged for OrderByPrimaryKey
+	if !strings.Contains(src, `db:"id" pickle:"pk"`) || strings.Count(src, `pickle:"pk"`) != 1 {
+		t.Errorf("expected only the id field to be tagged pickle:\"pk\"\n%s", src)
+	}
 }
 
 func TestGenerateModelNullableFields(t *testing.T) {
@@ -149,6 +154,72 @@ func TestGenerateModelNullableEncryptedFields(t *testing.T) {
 	}
 }
 
+func TestGenerateModelHiddenColumn(t *testing.T) {
+	tbl := &schema.Table{Name: "webhooks"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("name", 255).NotNull()
+	tbl.String("client_secret", 255).NotNull().Hidden()
+
+	out, err := GenerateModel(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+
+	src := string(out)
+	if _, err := parser.ParseFile(token.NewFileSet(), "webhook.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	if !strings.Contains(src, `json:"-" db:"client_secret"`) {
+		t.Errorf("hidden column should be tagged json:\"-\"\n%s", src)
+	}
+	start := strings.Index(src, "type WebhookPublic struct")
+	if start < 0 {
+		t.Fatalf("missing WebhookPublic projection\n%s", src)
+	}
+	projection := src[start : start+strings.Index(src[start:], "}")]
+	if strings.Contains(projection, "ClientSecret") || !strings.Contains(projection, "Name") {
+		t.Errorf("projection should keep Name and drop ClientSecret\n%s", projection)
+	}
+	if !strings.Contains(src, "func (m Webhook) MarshalJSON() ([]byte, error)") || !strings.Contains(src, "json.Marshal(m.Public())") {
+		t.Errorf("missing MarshalJSON via Public()\n%s", src)
+	}
+	if !strings.Contains(src, "func (m Webhook) JSONValue() any {\n\treturn m.Public()") {
+		t.Errorf("missing JSONValue returning Public()\n%s", src)
+	}
+	if !strings.Contains(src, `"encoding/json"`) {
+		t.Errorf("missing encoding/json import\n%s", src)
+	}
+}
+
+func TestGenerateModelWithoutHiddenColumnsHasNoMarshalJSON(t *testing.T) {
+	tbl := &schema.Table{Name: "tags"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("label", 50).NotNull()
+
+	out, err := GenerateModel(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+	if src := string(out); strings.Contains(src, "MarshalJSON") || strings.Contains(src, "TagPublic") {
+		t.Errorf("model without hidden columns should not get a projection\n%s", src)
+	}
+}
+
+func TestGenerateModelColumnComment(t *testing.T) {
+	tbl := &schema.Table{Name: "orders"}
+	tbl.Integer("quantity").NotNull().Check("quantity > 0").Comment("Units ordered.\nAlways positive.")
+
+	out, err := GenerateModel(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+
+	src := string(out)
+	if !strings.Contains(src, "\t// Units ordered.\n\t// Always positive.\n\tQuantity int") {
+		t.Errorf("expected doc comment above Quantity\n%s", src)
+	}
+}
+
 func TestGenerateModelDecimal(t *testing.T) {
 	tbl := &schema.Table{Name: "transfers"}
 	tbl.Decimal("amount", 18, 2).NotNull()
@@ -167,3 +238,99 @@ func TestGenerateModelDecimal(t *testing.T) {
 		t.Errorf("expected decimal import\n%s", src)
 	}
 }
+
+func TestGenerateModelRelationCounts(t *testing.T) {
+	tbl := &schema.Table{Name: "users"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.Integer("posts_count").Default(0)
+
+	out, err := GenerateModel(tbl, "models", "posts", "posts", "reviews.author_id")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+	src := string(out)
+	if !strings.Contains(src, "ReviewsAuthorIDCount int64 `json:\"reviews_author_id_count,omitempty\" count:\"reviews.author_id\"`") {
+		t.Errorf("missing count field:\n%s", src)
+	}
+	if strings.Count(src, "PostsCount ") != 1 || strings.Contains(src, `count:"posts"`) {
+		t.Errorf("a posts_count column should take precedence over the count field:\n%s", src)
+	}
+}
+
+func TestGenerateModelRelationFields(t *testing.T) {
+	users := &schema.Table{Name: "users"}
+	users.UUID("id").PrimaryKey()
+	posts := &schema.Table{Name: "posts"}
+	posts.UUID("id").PrimaryKey()
+	posts.UUID("user_id").ForeignKey("users", "id")
+	tables := []*schema.Table{users, posts}
+	loaded := loadedRelations(tables, func(a, b string) bool { return true })
+
+	out, err := generateModel(posts, "models", nil, loaded["posts"])
+	if err != nil {
+		t.Fatalf("generateModel: %v", err)
+	}
+	if !strings.Contains(string(out), "User *User `json:\"user,omitempty\" rel:\"user\"`") {
+		t.Errorf("missing belongs-to field:\n%s", out)
+	}
+	out, err = generateModel(users, "models", nil, loaded["users"])
+	if err != nil {
+		t.Fatalf("generateModel: %v", err)
+	}
+	if !strings.Contains(string(out), "Posts []Post `json:\"posts,omitempty\" rel:\"posts\"`") {
+		t.Errorf("missing has-many field:\n%s", out)
+	}
+
+	if got := loadedRelations(tables, func(a, b string) bool { return a == b }); len(got) != 0 {
+		t.Errorf("relations across packages should get no field, got %v", got)
+	}
+	posts.IsImmutable = true
+	if got := loadedRelations(tables, func(a, b string) bool { return true }); len(got["users"]) != 0 || len(got["posts"]) != 1 {
+		t.Errorf("only relations to immutable tables should be dropped, got %v", got)
+	}
+}
+
+func TestGenerateModelStatusTransitions(t *testing.T) {
+	tbl := &schema.Table{Name: "posts"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("status", 20).NotNull().Default("draft").Transitions(map[string][]string{
+		"draft":     {"review", "archived"},
+		"review":    {"draft", "published"},
+		"published": {"archived"},
+	})
+
+	out, err := GenerateModel(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+	src := string(out)
+	if _, err := parser.ParseFile(token.NewFileSet(), "post.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		`"fmt"`,
+		"var PostStatusTransitions = map[string][]string{",
+		`"archived":  {},`,
+		`"draft":     {"review", "archived"},`,
+		"func (Post) ValidateStatusTransition(from, to string) error {",
+		`return fmt.Errorf("status cannot change from %q to %q", from, to)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateModelWithoutTransitionsHasNoValidator(t *testing.T) {
+	tbl := &schema.Table{Name: "posts"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("status", 20).NotNull()
+
+	out, err := GenerateModel(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateModel: %v", err)
+	}
+	if strings.Contains(string(out), "Transition") || strings.Contains(string(out), `"fmt"`) {
+		t.Errorf("unexpected transition validator\n%s", out)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -53,6 +54,11 @@ func structNameToMigrationID(structName string) string {
 // ScanMigrationFiles scans the migrations directory and returns entries sorted
 // by timestamp (filename order), pairing each filename stem with its struct name.
 func ScanMigrationFiles(migrationsDir string) ([]MigrationFileEntry, error) {
+	return scanMigrationFiles(migrationsDir, nil)
+}
+
+// scanMigrationFiles is ScanMigrationFiles, leaving out the paths in skip.
+func scanMigrationFiles(migrationsDir string, skip map[string]bool) ([]MigrationFileEntry, error) {
 	entries, err := os.ReadDir(migrationsDir)
 	if err != nil {
 		return nil, err
@@ -74,6 +80,9 @@ func ScanMigrationFiles(migrationsDir string) ([]MigrationFileEntry, error) {
 		if !migrationTimestamp.MatchString(stem) && !strings.HasSuffix(e.Name(), "_gen.go") {
 			continue
 		}
+		if skip[filepath.Join(migrationsDir, e.Name())] {
+			continue
+		}
 
 		structNames, err := findMigrationStructs(migrationsDir, e.Name())
 		if err != nil {
@@ -107,9 +116,14 @@ func ScanMigrationFiles(migrationsDir string) ([]MigrationFileEntry, error) {
 // ScanAllMigrationFiles scans multiple migration directories and returns a
 // merged, timestamp-sorted list of migration entries.
 func ScanAllMigrationFiles(dirs []MigrationDir) ([]MigrationFileEntry, error) {
+	return scanAllMigrationFiles(dirs, nil)
+}
+
+// scanAllMigrationFiles is ScanAllMigrationFiles, leaving out the paths in skip.
+func scanAllMigrationFiles(dirs []MigrationDir, skip map[string]bool) ([]MigrationFileEntry, error) {
 	var all []MigrationFileEntry
 	for _, d := range dirs {
-		entries, err := ScanMigrationFiles(d.Dir)
+		entries, err := scanMigrationFiles(d.Dir, skip)
 		if err != nil {
 			return nil, fmt.Errorf("scanning %s: %w", d.Dir, err)
 		}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// relation is one WhereHas relation: rows of Table joined by
+// Table.Column = Parent.ParentColumn.
+type relation struct {
+	Parent, Name  string
+	Table, Column string
+	ParentColumn  string
+
+	// Many-to-many relations go through Pivot, whose PivotColumn references
+	// Table.Column and whose PivotParentColumn references Parent.ParentColumn.
+	Pivot, PivotColumn, PivotParentColumn string
+
+	// ToMany is set for has-many and many-to-many relations, which WithCount
+	// can count onto the parent model.
+	ToMany bool
+}
+
+// pivotColumns returns the two foreign key columns of a pivot table: one
+// that references two different tables and has no other columns besides an
+// id and timestamps.
+func pivotColumns(tbl *schema.Table) (a, b *schema.Column, ok bool) {
+	var fks []*schema.Column
+	for _, col := range tbl.Columns {
+		switch {
+		case col.ForeignKeyTable != "" && col.ForeignKeyColumn != "":
+			fks = append(fks, col)
+		case col.Name == "id" || col.Name == "created_at" || col.Name == "updated_at":
+		default:
+			return nil, nil, false
+		}
+	}
+	if len(fks) != 2 || fks[0].ForeignKeyTable == fks[1].ForeignKeyTable {
+		return nil, nil, false
+	}
+	return fks[0], fks[1], true
+}
+
+// belongsToName is the relation a foreign key column gives its table: the
+// column name without _id ("author" for author_id).
+func belongsToName(col *schema.Column) string {
+	if name := strings.TrimSuffix(col.Name, "_id"); name != "" {
+		return name
+	}
+	return col.Name
+}
+
+// collectRelations derives WhereHas relations from column foreign keys, in
+// both directions. A parent reaches its children by the child table name
+// ("posts"), or "posts.author_id" when the child references it more than once;
+// a child reaches its parent by the foreign key column without _id ("author").
+// Through a pivot table, each side reaches the other by its table name
+// ("tags" from posts via post_tag).
+func collectRelations(tables []*schema.Table) []relation {
+	sorted := append([]*schema.Table(nil), tables...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var rels []relation
+	used := map[string]map[string]bool{}
+	add := func(r relation, fallback string) {
+		if used[r.Parent] == nil {
+			used[r.Parent] = map[string]bool{}
+		}
+		if used[r.Parent][r.Name] {
+			r.Name = fallback
+		}
+		if used[r.Parent][r.Name] {
+			return
+		}
+		used[r.Parent][r.Name] = true
+		rels = append(rels, r)
+	}
+
+	for _, tbl := range sorted {
+		refs := map[string]int{}
+		for _, col := range tbl.Columns {
+			if col.ForeignKeyTable != "" {
+				refs[col.ForeignKeyTable]++
+			}
+		}
+		for _, col := range tbl.Columns {
+			if col.ForeignKeyTable == "" || col.ForeignKeyColumn == "" {
+				continue
+			}
+			qualified := tbl.Name + "." + col.Name
+
+			add(relation{Parent: tbl.Name, Name: belongsToName(col), Table: col.ForeignKeyTable, Column: col.ForeignKeyColumn, ParentColumn: col.Name}, col.Name)
+
+			hasMany := tbl.Name
+			if refs[col.ForeignKeyTable] > 1 {
+				hasMany = qualified
+			}
+			add(relation{Parent: col.ForeignKeyTable, Name: hasMany, Table: tbl.Name, Column: col.Name, ParentColumn: col.ForeignKeyColumn, ToMany: true}, qualified)
+		}
+	}
+
+	for _, tbl := range sorted {
+		a, b, ok := pivotColumns(tbl)
+		if !ok {
+			continue
+		}
+		for _, side := range [][2]*schema.Column{{a, b}, {b, a}} {
+			parent, other := side[0], side[1]
+			add(relation{
+				Parent: parent.ForeignKeyTable, Name: other.ForeignKeyTable,
+				Table: other.ForeignKeyTable, Column: other.ForeignKeyColumn, ParentColumn: parent.ForeignKeyColumn,
+				Pivot: tbl.Name, PivotColumn: other.Name, PivotParentColumn: parent.Name,
+				ToMany: true,
+			}, tbl.Name+"."+other.ForeignKeyTable)
+		}
+	}
+	return rels
+}
+
+// countedRelations returns, by parent table, the names of the to-many
+// relations GenerateModel adds a WithCount field for. Immutable tables are
+// left out on either side: their query builder has no WithCount, and a
+// count over them would include every version.
+func countedRelations(tables []*schema.Table) map[string][]string {
+	immutable := map[string]bool{}
+	for _, tbl := range tables {
+		immutable[tbl.Name] = tbl.IsImmutable
+	}
+	counted := map[string][]string{}
+	for _, r := range collectRelations(tables) {
+		if r.ToMany && !immutable[r.Parent] && !immutable[r.Table] {
+			counted[r.Parent] = append(counted[r.Parent], r.Name)
+		}
+	}
+	return counted
+}
+
+// loadedRelations returns, by parent table, the relations GenerateModel adds
+// an EagerLoad field for. A relation needs both models in the same package,
+// since the field's type is the related model; relations to immutable
+// tables are left out, as loading them would return every version.
+func loadedRelations(tables []*schema.Table, samePackage func(a, b string) bool) map[string][]relation {
+	immutable := map[string]bool{}
+	for _, tbl := range tables {
+		immutable[tbl.Name] = tbl.IsImmutable
+	}
+	loaded := map[string][]relation{}
+	for _, r := range collectRelations(tables) {
+		if !immutable[r.Table] && samePackage(r.Parent, r.Table) {
+			loaded[r.Parent] = append(loaded[r.Parent], r)
+		}
+	}
+	return loaded
+}
+
+// GenerateRelations produces relations_gen.go, which registers the WhereHas
+// relations of every table with the package's query builder.
+func GenerateRelations(tables []*schema.Table, packageName string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by Pickle. DO NOT EDIT.\n")
+	b.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+
+	rels := collectRelations(tables)
+	if len(rels) == 0 {
+		b.WriteString("// No relations: no column declares a foreign key.\n")
+		return format.Source(b.Bytes())
+	}
+
+	b.WriteString("// Relations for WhereHas and WhereDoesntHave, derived from foreign keys.\n")
+	b.WriteString("func init() {\n")
+	for _, r := range rels {
+		if r.Pivot != "" {
+			b.WriteString(fmt.Sprintf("\tregisterPivotRelation(%q, %q, %q, %q, %q, %q, %q, %q)\n", r.Parent, r.Name, r.Pivot, r.PivotParentColumn, r.ParentColumn, r.PivotColumn, r.Table, r.Column))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\tregisterRelation(%q, %q, %q, %q, %q)\n", r.Parent, r.Name, r.Table, r.Column, r.ParentColumn))
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
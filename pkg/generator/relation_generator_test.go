@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestGenerateRelationsBothDirections(t *testing.T) {
+	users := &schema.Table{Name: "users"}
+	users.UUID("id").PrimaryKey()
+
+	posts := &schema.Table{Name: "posts"}
+	posts.UUID("id").PrimaryKey()
+	posts.UUID("user_id").NotNull().ForeignKey("users", "id")
+
+	reviews := &schema.Table{Name: "reviews"}
+	reviews.UUID("id").PrimaryKey()
+	reviews.UUID("author_id").NotNull().ForeignKey("users", "id")
+	reviews.UUID("reviewer_id").NotNull().ForeignKey("users", "id")
+
+	categories := &schema.Table{Name: "categories"}
+	categories.UUID("id").PrimaryKey()
+	categories.UUID("parent_id").Nullable().ForeignKey("categories", "id")
+
+	out, err := GenerateRelations([]*schema.Table{users, posts, reviews, categories}, "models")
+	if err != nil {
+		t.Fatalf("GenerateRelations: %v", err)
+	}
+	src := string(out)
+	if _, err := parser.ParseFile(token.NewFileSet(), "relations_gen.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		`registerRelation("posts", "user", "users", "id", "user_id")`,
+		`registerRelation("users", "posts", "posts", "user_id", "id")`,
+		`registerRelation("reviews", "author", "users", "id", "author_id")`,
+		`registerRelation("users", "reviews.author_id", "reviews", "author_id", "id")`,
+		`registerRelation("users", "reviews.reviewer_id", "reviews", "reviewer_id", "id")`,
+		`registerRelation("categories", "parent", "categories", "id", "parent_id")`,
+		`registerRelation("categories", "categories", "categories", "parent_id", "id")`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %s\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateRelationsThroughPivot(t *testing.T) {
+	var m schema.Migration
+	m.CreateTable("posts", func(t *schema.Table) { t.UUID("id").PrimaryKey() })
+	m.CreateTable("tags", func(t *schema.Table) { t.UUID("id").PrimaryKey() })
+	m.CreatePivot("posts", "tags", func(t *schema.Table) { t.Timestamps() })
+	var tables []*schema.Table
+	for _, op := range m.GetOperations() {
+		if op.Type == schema.OpCreateTable {
+			tables = append(tables, op.TableDef)
+		}
+	}
+
+	out, err := GenerateRelations(tables, "models")
+	if err != nil {
+		t.Fatalf("GenerateRelations: %v", err)
+	}
+	src := string(out)
+	for _, want := range []string{
+		`registerPivotRelation("posts", "tags", "post_tag", "post_id", "id", "tag_id", "tags", "id")`,
+		`registerPivotRelation("tags", "posts", "post_tag", "tag_id", "id", "post_id", "posts", "id")`,
+		`registerRelation("posts", "post_tag", "post_tag", "post_id", "id")`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %s\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateRelationsSkipsJoinTablesWithPayload(t *testing.T) {
+	memberships := &schema.Table{Name: "memberships"}
+	memberships.UUID("user_id").ForeignKey("users", "id")
+	memberships.UUID("team_id").ForeignKey("teams", "id")
+	memberships.String("role")
+
+	out, err := GenerateRelations([]*schema.Table{memberships}, "models")
+	if err != nil {
+		t.Fatalf("GenerateRelations: %v", err)
+	}
+	if strings.Contains(string(out), "registerPivotRelation") {
+		t.Errorf("a join table with its own columns is not a pivot:\n%s", out)
+	}
+}
+
+func TestGenerateRelationsWithoutForeignKeys(t *testing.T) {
+	tags := &schema.Table{Name: "tags"}
+	tags.UUID("id").PrimaryKey()
+
+	out, err := GenerateRelations([]*schema.Table{tags}, "models")
+	if err != nil {
+		t.Fatalf("GenerateRelations: %v", err)
+	}
+	if strings.Contains(string(out), "registerRelation") {
+		t.Errorf("expected no registrations\n%s", out)
+	}
+}
+
+func TestCountedRelationsAreToMany(t *testing.T) {
+	users := &schema.Table{Name: "users"}
+	users.UUID("id").PrimaryKey()
+
+	posts := &schema.Table{Name: "posts"}
+	posts.UUID("id").PrimaryKey()
+	posts.UUID("user_id").NotNull().ForeignKey("users", "id")
+
+	revisions := &schema.Table{Name: "revisions", IsImmutable: true}
+	revisions.UUID("id").PrimaryKey()
+	revisions.UUID("post_id").NotNull().ForeignKey("posts", "id")
+
+	counted := countedRelations([]*schema.Table{users, posts, revisions})
+	if got := strings.Join(counted["users"], ","); got != "posts" {
+		t.Errorf("users counts = %q, want posts", got)
+	}
+	if len(counted["posts"]) != 0 {
+		t.Errorf("belongs-to and immutable relations should not be counted, got %v", counted["posts"])
+	}
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// GenerateRepository produces a {Model}Repository interface covering the
+// reads and writes a controller typically makes — Find, FindBy{Column} for
+// each unique column, All, Count, Create and, on mutable tables, Update and
+// Delete — along with New{Model}Repository, which returns the implementation
+// backed by Query{Model}(). Controllers that take the interface can be unit
+// tested with a fake instead of a database.
+//
+// Tables with an owner column get a constructor taking the owner's ID: every
+// read, update and delete is scoped to that owner, and Create assigns it.
+func GenerateRepository(table *schema.Table, packageName string) ([]byte, error) {
+	structName := tableToStructName(table.Name)
+	iface := structName + "Repository"
+	impl := toLowerFirst(iface)
+	query := "Query" + structName + "()"
+	imports := map[string]bool{}
+
+	var pk, owner *schema.Column
+	var unique []*schema.Column
+	compositeKey := false
+	for _, col := range table.Columns {
+		switch {
+		case col.IsPrimaryKey:
+			if table.IsImmutable && col.Name == "version_id" {
+				continue
+			}
+			if pk != nil {
+				compositeKey = true
+			}
+			pk = col
+		case col.IsOwnerColumn && owner == nil:
+			owner = col
+		case col.IsUnique && !col.IsNullable && !col.IsSealed:
+			unique = append(unique, col)
+		}
+	}
+	if compositeKey {
+		pk = nil
+	}
+	for _, col := range append([]*schema.Column{pk, owner}, unique...) {
+		if col == nil {
+			continue
+		}
+		if imp := columnImport(col); imp != "" {
+			imports[imp] = true
+		}
+	}
+	mutable := !table.IsImmutable && !table.IsAppendOnly && pk != nil
+
+	// scoped is the query every method starts from.
+	scoped := query
+	if owner != nil {
+		scoped = query + ".WhereOwnedBy(r.ownerID)"
+	}
+
+	type method struct {
+		doc, sig, body string
+	}
+	var methods []method
+	if pk != nil {
+		methods = append(methods, method{
+			doc:  fmt.Sprintf("Find returns the %s with the given %s, or sql.ErrNoRows.", structName, pk.Name),
+			sig:  fmt.Sprintf("Find(id %s) (*%s, error)", columnGoType(pk), structName),
+			body: fmt.Sprintf("return %s.Find(id)", scoped),
+		})
+	}
+	for _, col := range unique {
+		field := snakeToPascal(col.Name)
+		param := safeParamName(toLowerFirst(field))
+		methods = append(methods, method{
+			doc:  fmt.Sprintf("FindBy%s returns the %s with the given %s, or sql.ErrNoRows.", field, structName, col.Name),
+			sig:  fmt.Sprintf("FindBy%s(%s %s) (*%s, error)", field, param, columnGoType(col), structName),
+			body: fmt.Sprintf("return %s.Where%s(%s).First()", scoped, field, param),
+		})
+	}
+	methods = append(methods,
+		method{
+			doc:  fmt.Sprintf("All returns every %s.", structName),
+			sig:  fmt.Sprintf("All() ([]%s, error)", structName),
+			body: fmt.Sprintf("return %s.All()", scoped),
+		},
+		method{
+			doc:  fmt.Sprintf("Count returns the number of %s records.", structName),
+			sig:  "Count() (int64, error)",
+			body: fmt.Sprintf("return %s.Count()", scoped),
+		},
+	)
+	create := fmt.Sprintf("return %s.Create(record)", query)
+	if owner != nil {
+		create = fmt.Sprintf("record.%s = r.ownerID\n\t%s", snakeToPascal(owner.Name), create)
+	}
+	methods = append(methods, method{
+		doc:  "Create inserts record, filling in its generated columns.",
+		sig:  fmt.Sprintf("Create(record *%s) error", structName),
+		body: create,
+	})
+	if mutable {
+		// Update and Delete match the record's primary key; Delete with no
+		// conditions would otherwise remove every row.
+		byKey := fmt.Sprintf("%s.Where%s(record.%s)", scoped, snakeToPascal(pk.Name), snakeToPascal(pk.Name))
+		methods = append(methods,
+			method{
+				doc:  "Update saves every column of record.",
+				sig:  fmt.Sprintf("Update(record *%s) error", structName),
+				body: fmt.Sprintf("return %s.Update(record)", byKey),
+			},
+			method{
+				doc:  "Delete removes record.",
+				sig:  fmt.Sprintf("Delete(record *%s) error", structName),
+				body: fmt.Sprintf("return %s.Delete(record)", byKey),
+			},
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by Pickle. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	if len(imports) > 0 {
+		sorted := make([]string, 0, len(imports))
+		for imp := range imports {
+			sorted = append(sorted, imp)
+		}
+		sort.Strings(sorted)
+		b.WriteString("import (\n")
+		for _, imp := range sorted {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(&b, "// %s is the data access controllers need for %s. Depend on it\n", iface, structName)
+	b.WriteString("// instead of the query builder to substitute a fake in tests.\n")
+	fmt.Fprintf(&b, "type %s interface {\n", iface)
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t// %s\n\t%s\n", m.doc, m.sig)
+	}
+	b.WriteString("}\n\n")
+
+	if owner != nil {
+		fmt.Fprintf(&b, "// New%s returns the database-backed %s, scoped to the\n", iface, iface)
+		b.WriteString("// records ownerID owns.\n")
+		fmt.Fprintf(&b, "func New%s(ownerID %s) %s {\n\treturn %s{ownerID: ownerID}\n}\n\n", iface, columnGoType(owner), iface, impl)
+		fmt.Fprintf(&b, "type %s struct {\n\townerID %s\n}\n\n", impl, columnGoType(owner))
+	} else {
+		fmt.Fprintf(&b, "// New%s returns the database-backed %s.\n", iface, iface)
+		fmt.Fprintf(&b, "func New%s() %s {\n\treturn %s{}\n}\n\n", iface, iface, impl)
+		fmt.Fprintf(&b, "type %s struct{}\n\n", impl)
+	}
+
+	for _, m := range methods {
+		fmt.Fprintf(&b, "func (r %s) %s {\n\t%s\n}\n\n", impl, m.sig, m.body)
+	}
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), fmt.Errorf("formatting repository for %s: %w\n%s", structName, err, b.String())
+	}
+	return formatted, nil
+}
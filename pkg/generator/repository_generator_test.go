@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func generateRepository(t *testing.T, tbl *schema.Table) string {
+	t.Helper()
+	out, err := GenerateRepository(tbl, "models")
+	if err != nil {
+		t.Fatalf("GenerateRepository: %v", err)
+	}
+	src := string(out)
+	if _, err := parser.ParseFile(token.NewFileSet(), "repository.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	return src
+}
+
+func TestGenerateRepositoryUsers(t *testing.T) {
+	tbl := &schema.Table{Name: "users"}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("email", 255).NotNull().Unique()
+	tbl.String("nickname", 50).Nullable().Unique()
+	tbl.Timestamps()
+
+	src := generateRepository(t, tbl)
+	for _, want := range []string{
+		`"github.com/google/uuid"`,
+		"type UserRepository interface {",
+		"Find(id uuid.UUID) (*User, error)",
+		"FindByEmail(email string) (*User, error)",
+		"func NewUserRepository() UserRepository {\n\treturn userRepository{}\n}",
+		"return QueryUser().WhereEmail(email).First()",
+		"return QueryUser().Create(record)",
+		"return QueryUser().WhereID(record.ID).Update(record)",
+		"return QueryUser().WhereID(record.ID).Delete(record)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "FindByNickname") {
+		t.Errorf("nullable unique columns should not get a finder\n%s", src)
+	}
+}
+
+func TestGenerateRepositoryScopesToOwner(t *testing.T) {
+	tbl := &schema.Table{Name: "posts"}
+	tbl.BigInteger("id").PrimaryKey()
+	tbl.UUID("user_id").NotNull().IsOwner()
+	tbl.String("title", 255).NotNull()
+
+	src := generateRepository(t, tbl)
+	for _, want := range []string{
+		"func NewPostRepository(ownerID uuid.UUID) PostRepository {",
+		"Find(id int64) (*Post, error)",
+		"return QueryPost().WhereOwnedBy(r.ownerID).Find(id)",
+		"return QueryPost().WhereOwnedBy(r.ownerID).All()",
+		"record.UserID = r.ownerID\n\treturn QueryPost().Create(record)",
+		"return QueryPost().WhereOwnedBy(r.ownerID).WhereID(record.ID).Delete(record)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateRepositoryAppendOnlyHasNoUpdateOrDelete(t *testing.T) {
+	tbl := &schema.Table{Name: "events", IsAppendOnly: true}
+	tbl.UUID("id").PrimaryKey()
+	tbl.String("kind", 50).NotNull()
+
+	src := generateRepository(t, tbl)
+	if !strings.Contains(src, "Create(record *Event) error") {
+		t.Errorf("missing Create\n%s", src)
+	}
+	if strings.Contains(src, "Update(") || strings.Contains(src, "Delete(") {
+		t.Errorf("append-only repository should not update or delete\n%s", src)
+	}
+}
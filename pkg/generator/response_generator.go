@@ -28,11 +28,16 @@ type {{ .Name }} struct {
 {{- end }}
 }
 {{ end }}
+// {{ .ModelName }}OwnedBy reports whether ownerID matches the row's owner column.
+func {{ .ModelName }}OwnedBy(record *{{ .ModelName }}, ownerID string) bool {
+	return ownerID != "" && {{ .OwnerMatch }}
+}
+
 // Serialize{{ .ModelName }} picks the appropriate response struct based on ownership.
 // If ownerID matches the row's owner column, returns {{ .ModelName }}OwnerResponse.
 // Otherwise returns {{ .ModelName }}PublicResponse.
 func Serialize{{ .ModelName }}(record *{{ .ModelName }}, ownerID string) any {
-	if ownerID != "" && {{ .OwnerMatch }} {
+	if {{ .ModelName }}OwnedBy(record, ownerID) {
 		return {{ .ModelName }}OwnerResponse{
 {{- range .OwnerFields }}
 			{{ .Name }}: record.{{ .Name }},
@@ -103,7 +108,7 @@ func GenerateResponses(table *schema.Table, packageName string) ([]byte, error)
 			ownerCol = col
 		}
 
-		goType := columnGoType(col)
+		goType := modelFieldType(table, col)
 		if imp := columnImport(col); imp != "" {
 			// Only add import if the field appears in a response struct
 			if col.IsPublic || col.IsOwnerSees {
@@ -33,11 +33,14 @@ type columnInfo struct {
 	Unique           bool   ` + "`" + `json:"unique,omitempty"` + "`" + `
 	Default          any    ` + "`" + `json:"default,omitempty"` + "`" + `
 	HasDefault       bool   ` + "`" + `json:"has_default,omitempty"` + "`" + `
+	DefaultRaw       bool   ` + "`" + `json:"default_raw,omitempty"` + "`" + `
 	ForeignKeyTable  string ` + "`" + `json:"foreign_key_table,omitempty"` + "`" + `
 	ForeignKeyColumn string ` + "`" + `json:"foreign_key_column,omitempty"` + "`" + `
 	Length           int    ` + "`" + `json:"length,omitempty"` + "`" + `
 	Precision        int    ` + "`" + `json:"precision,omitempty"` + "`" + `
 	Scale            int    ` + "`" + `json:"scale,omitempty"` + "`" + `
+	HasPrecision     bool   ` + "`" + `json:"has_precision,omitempty"` + "`" + `
+	WithoutTimeZone  bool   ` + "`" + `json:"without_time_zone,omitempty"` + "`" + `
 	Public           bool            ` + "`" + `json:"public,omitempty"` + "`" + `
 	OwnerSees        bool            ` + "`" + `json:"owner_sees,omitempty"` + "`" + `
 	OwnerColumn      bool            ` + "`" + `json:"owner_column,omitempty"` + "`" + `
@@ -45,7 +48,12 @@ type columnInfo struct {
 	Encrypted        bool            ` + "`" + `json:"encrypted,omitempty"` + "`" + `
 	Sealed           bool            ` + "`" + `json:"sealed,omitempty"` + "`" + `
 	UnsafePublic     bool            ` + "`" + `json:"unsafe_public,omitempty"` + "`" + `
+	Hidden           bool            ` + "`" + `json:"hidden,omitempty"` + "`" + `
 	Seeder           *seedInfo       ` + "`" + `json:"seeder,omitempty"` + "`" + `
+	Check            string          ` + "`" + `json:"check,omitempty"` + "`" + `
+	Comment          string          ` + "`" + `json:"comment,omitempty"` + "`" + `
+	Transitions      map[string][]string ` + "`" + `json:"transitions,omitempty"` + "`" + `
+	Using            string          ` + "`" + `json:"using,omitempty"` + "`" + `
 }
 
 type seedInfo struct {
@@ -78,6 +86,7 @@ type foreignKeyInfo struct {
 type indexInfo struct {
 	Columns []string ` + "`" + `json:"columns"` + "`" + `
 	Unique  bool     ` + "`" + `json:"unique"` + "`" + `
+	Name    string   ` + "`" + `json:"name,omitempty"` + "`" + `
 }
 
 type viewColumnInfo struct {
@@ -219,11 +228,14 @@ func columnToInfo(col *{{ .TypesPkg }}.Column) columnInfo {
 		Unique:           col.IsUnique,
 		Default:          col.DefaultValue,
 		HasDefault:       col.HasDefault,
+		DefaultRaw:       col.DefaultIsRaw,
 		ForeignKeyTable:  col.ForeignKeyTable,
 		ForeignKeyColumn: col.ForeignKeyColumn,
 		Length:           col.Length,
 		Precision:        col.Precision,
 		Scale:            col.Scale,
+		HasPrecision:     col.HasPrecision,
+		WithoutTimeZone:  col.WithoutTimeZone,
 		Public:           col.IsPublic,
 		OwnerSees:        col.IsOwnerSees,
 		OwnerColumn:      col.IsOwnerColumn,
@@ -231,6 +243,11 @@ func columnToInfo(col *{{ .TypesPkg }}.Column) columnInfo {
 		Encrypted:        col.IsEncrypted,
 		Sealed:           col.IsSealed,
 		UnsafePublic:     col.IsUnsafePublic,
+		Hidden:           col.IsHidden,
+		Check:            col.CheckExpr,
+		Comment:          col.CommentText,
+		Transitions:      col.TransitionMap,
+		Using:            col.UsingExpr,
 	}
 	if col.Seeder != nil {
 		info.Seeder = &seedInfo{Kind: col.Seeder.Kind, Arguments: col.Seeder.Arguments, Fields: col.Seeder.Fields, Reference: col.Seeder.Reference, NullWeight: col.Seeder.NullWeight}
@@ -330,6 +347,15 @@ func operationsToInfo(ops []{{ .TypesPkg }}.Operation, tables map[string]*tableI
 					info.Columns = append(info.Columns, columnToInfo(col))
 				}
 			}
+		case {{ .TypesPkg }}.OpChangeColumn:
+			info.Type = "change_column"
+			if op.ColumnDef != nil {
+				t := &{{ .TypesPkg }}.Table{Name: op.Table}
+				op.ColumnDef(t)
+				for _, col := range t.Columns {
+					info.Columns = append(info.Columns, columnToInfo(col))
+				}
+			}
 		case {{ .TypesPkg }}.OpDropColumn:
 			info.Type = "drop_column"
 		case {{ .TypesPkg }}.OpRenameColumn:
@@ -337,12 +363,12 @@ func operationsToInfo(ops []{{ .TypesPkg }}.Operation, tables map[string]*tableI
 		case {{ .TypesPkg }}.OpAddIndex:
 			info.Type = "add_index"
 			if op.Index != nil {
-				info.Index = &indexInfo{Columns: op.Index.Columns, Unique: op.Index.Unique}
+				info.Index = &indexInfo{Columns: op.Index.Columns, Unique: op.Index.Unique, Name: op.Index.Name}
 			}
 		case {{ .TypesPkg }}.OpAddUniqueIndex:
 			info.Type = "add_unique_index"
 			if op.Index != nil {
-				info.Index = &indexInfo{Columns: op.Index.Columns, Unique: true}
+				info.Index = &indexInfo{Columns: op.Index.Columns, Unique: true, Name: op.Index.Name}
 			}
 		case {{ .TypesPkg }}.OpCreateView:
 			info.Type = "create_view"
@@ -398,11 +424,20 @@ func processOps(ops []{{ .TypesPkg }}.Operation, tables map[string]*tableInfo, o
 					ti.Columns = append(ti.Columns, columnToInfo(col))
 				}
 			}
+		case {{ .TypesPkg }}.OpChangeColumn:
+			if ti, ok := tables[op.Table]; ok && op.ColumnDef != nil {
+				t := &{{ .TypesPkg }}.Table{Name: op.Table}
+				op.ColumnDef(t)
+				for _, col := range t.Columns {
+					changeColumnInfo(ti, columnToInfo(col))
+				}
+			}
 		case {{ .TypesPkg }}.OpAddIndex, {{ .TypesPkg }}.OpAddUniqueIndex:
 			if ti, ok := tables[op.Table]; ok {
 				ti.Indexes = append(ti.Indexes, indexInfo{
 					Columns: op.Index.Columns,
 					Unique:  op.Index.Unique,
+					Name:    op.Index.Name,
 				})
 			}
 		case {{ .TypesPkg }}.OpCreateView:
@@ -420,6 +455,9 @@ func processOps(ops []{{ .TypesPkg }}.Operation, tables map[string]*tableInfo, o
 						} else if op.MetadataColumn.Seeder != nil {
 							ti.Columns[i].Seeder = &seedInfo{Kind: op.MetadataColumn.Seeder.Kind, Arguments: op.MetadataColumn.Seeder.Arguments, Fields: op.MetadataColumn.Seeder.Fields, Reference: op.MetadataColumn.Seeder.Reference, NullWeight: op.MetadataColumn.Seeder.NullWeight}
 						}
+						if op.MetadataColumn.TransitionMap != nil {
+							ti.Columns[i].Transitions = op.MetadataColumn.TransitionMap
+						}
 						break
 					}
 				}
@@ -428,6 +466,25 @@ func processOps(ops []{{ .TypesPkg }}.Operation, tables map[string]*tableInfo, o
 	}
 }
 
+// changeColumnInfo applies a ChangeColumn to the matching column: its type,
+// nullability and default are replaced, everything else is kept.
+func changeColumnInfo(ti *tableInfo, changed columnInfo) {
+	for i := range ti.Columns {
+		col := &ti.Columns[i]
+		if col.Name != changed.Name {
+			continue
+		}
+		col.Type, col.GoType, col.Nullable = changed.Type, changed.GoType, changed.Nullable
+		col.Default, col.HasDefault, col.DefaultRaw = changed.Default, changed.HasDefault, changed.DefaultRaw
+		col.Length, col.Precision, col.Scale = changed.Length, changed.Precision, changed.Scale
+		col.HasPrecision, col.WithoutTimeZone = changed.HasPrecision, changed.WithoutTimeZone
+		if changed.Comment != "" {
+			col.Comment = changed.Comment
+		}
+		return
+	}
+}
+
 func printTable(ti tableInfo) {
 	fmt.Printf("\n  %s\n", ti.Name)
 	fmt.Println("  " + repeat("─", 70))
@@ -444,11 +501,29 @@ func printTable(ti tableInfo) {
 		if col.Unique {
 			mods += "UQ "
 		}
+		// Primary keys and unique columns are indexed implicitly; IDX marks
+		// an index added by the migrations that leads on the column.
+		explicit := leadsIndex(ti.Indexes, col.Name)
+		if explicit && !col.PrimaryKey && !col.Unique {
+			mods += "IDX "
+		}
 		if col.Default != nil {
 			mods += fmt.Sprintf("default=%v ", col.Default)
 		}
 		if col.ForeignKeyTable != "" {
 			mods += fmt.Sprintf("FK→%s.%s ", col.ForeignKeyTable, col.ForeignKeyColumn)
+			if !explicit && !col.PrimaryKey && !col.Unique {
+				mods += "(no index) "
+			}
+		}
+		if col.Hidden {
+			mods += "hidden "
+		}
+		if col.Check != "" {
+			mods += fmt.Sprintf("CHECK(%s) ", col.Check)
+		}
+		if col.Comment != "" {
+			mods += fmt.Sprintf("-- %s", col.Comment)
 		}
 
 		nullable := "NO"
@@ -488,6 +563,16 @@ func printTable(ti tableInfo) {
 	fmt.Println()
 }
 
+// leadsIndex reports whether one of indexes has column as its first column.
+func leadsIndex(indexes []indexInfo, column string) bool {
+	for _, idx := range indexes {
+		if len(idx.Columns) > 0 && idx.Columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
 func printView(vi viewInfo) {
 	fmt.Printf("\n  %s (view)\n", vi.Name)
 	fmt.Println("  " + repeat("─", 70))
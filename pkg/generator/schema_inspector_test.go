@@ -44,6 +44,9 @@ func TestGenerateSchemaInspector(t *testing.T) {
 	if !strings.Contains(src, `VisibleTo        map[string]bool`) {
 		t.Errorf("missing visible-to role column metadata\n%s", src)
 	}
+	if !strings.Contains(src, `HasPrecision:     col.HasPrecision`) || !strings.Contains(src, `WithoutTimeZone:  col.WithoutTimeZone`) {
+		t.Errorf("missing timestamp precision serialization\n%s", src)
+	}
 	if !strings.Contains(src, `VisibleTo:        col.VisibleTo`) {
 		t.Errorf("missing visible-to role column serialization\n%s", src)
 	}
@@ -70,6 +73,30 @@ func TestConvertInspectorColumnPreservesSeeder(t *testing.T) {
 	}
 }
 
+func TestConvertInspectorColumnPreservesCheckCommentAndHidden(t *testing.T) {
+	column, err := convertInspectorColumn(inspectorColumnInfo{
+		Name: "quantity", Type: "integer", Check: "quantity > 0", Comment: "Units ordered", Hidden: true,
+	}, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if column.CheckExpr != "quantity > 0" || column.CommentText != "Units ordered" || !column.IsHidden {
+		t.Fatalf("column = %#v", column)
+	}
+}
+
+func TestConvertInspectorColumnPreservesTimestampPrecision(t *testing.T) {
+	column, err := convertInspectorColumn(inspectorColumnInfo{
+		Name: "local_at", Type: "timestamp", HasPrecision: true, WithoutTimeZone: true,
+	}, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !column.HasPrecision || column.Precision != 0 || !column.WithoutTimeZone {
+		t.Fatalf("column = %#v", column)
+	}
+}
+
 func TestConvertInspectorMetadataOperationPreservesSeeder(t *testing.T) {
 	ops, err := convertInspectorOperations([]inspectorOperationInfo{{
 		Type: "alter_column_metadata", Table: "contacts", ColumnName: "phone",
@@ -14,6 +14,14 @@ import (
 // for a given table, using scope templates parsed from the cooked scopes file.
 func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packageName string) ([]byte, error) {
 	columns := tickle.ColumnsFromTable(table)
+	if table.IsImmutable {
+		// Immutable queries render their own SQL without JSON operators.
+		for i := range columns {
+			if columns[i].Scope == "json" {
+				columns[i].Scope = "other"
+			}
+		}
+	}
 	structName := tableToStructName(table.Name)
 	scopeBody, err := tickle.GenerateScopes(blocks, columns, structName)
 	if err != nil {
@@ -88,11 +96,13 @@ func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packag
 				relName = tableToStructName(col.Name)
 			}
 			b.WriteString(fmt.Sprintf("func (q *%s) With%s() *%s {\n", queryType, relName, queryType))
-			b.WriteString(fmt.Sprintf("\tq.EagerLoad(%q)\n", col.ForeignKeyTable))
+			b.WriteString(fmt.Sprintf("\tq.EagerLoad(%q)\n", belongsToName(col)))
 			b.WriteString(fmt.Sprintf("\treturn q\n"))
 			b.WriteString("}\n\n")
 		}
 	}
+	b.WriteString("// WithNested eager-loads a dotted path of relations, such as \"posts.comments\".\n")
+	b.WriteString(fmt.Sprintf("func (q *%s) WithNested(path string) *%s {\n\tq.EagerLoad(path)\n\treturn q\n}\n\n", queryType, queryType))
 
 	// Generate ownership scope if table has an owner column
 	for _, col := range table.Columns {
@@ -129,6 +139,10 @@ func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packag
 			b.WriteString(fmt.Sprintf("\treturn q\n"))
 			b.WriteString("}\n\n")
 		}
+		if !table.IsAppendOnly {
+			b.WriteString("// On runs the query on conn, such as a read replica from Connection.\n")
+			b.WriteString(fmt.Sprintf("func (q *%s) On(conn *sql.DB) *%s {\n\tq.QueryBuilder.On(conn)\n\treturn q\n}\n\n", queryType, queryType))
+		}
 
 		// Generate typed OrderBy methods per column
 		generateOrderByMethods(&b, table, queryType, baseBuilder)
@@ -217,7 +231,8 @@ func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packag
 
 	// Generate per-column Sum/Avg methods for numeric columns.
 	// Both QueryBuilder and ImmutableQueryBuilder have their own aggregate()
-	// method that handles dedup correctly for their table type.
+	// method that handles dedup correctly for their table type. DECIMAL
+	// columns return decimal.Decimal so totals keep their exact value.
 	for _, col := range table.Columns {
 		if col.IsEncrypted || col.IsSealed {
 			continue // aggregates on ciphertext are meaningless
@@ -228,6 +243,15 @@ func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packag
 		}
 		pascal := snakeToPascal(col.Name)
 		for _, fn := range []string{"Sum", "Avg"} {
+			if col.Type == schema.Decimal {
+				b.WriteString(fmt.Sprintf("func (q *%s) %s%s() (*decimal.Decimal, error) {\n", queryType, fn, pascal))
+				b.WriteString("\tvar result decimal.NullDecimal\n")
+				b.WriteString(fmt.Sprintf("\tif err := q.aggregateInto(&result, %q, %q); err != nil || !result.Valid {\n", strings.ToUpper(fn), col.Name))
+				b.WriteString("\t\treturn nil, err\n\t}\n")
+				b.WriteString("\treturn &result.Decimal, nil\n")
+				b.WriteString("}\n\n")
+				continue
+			}
 			b.WriteString(fmt.Sprintf("func (q *%s) %s%s() (*float64, error) {\n", queryType, fn, pascal))
 			b.WriteString(fmt.Sprintf("\treturn q.aggregate(%q, %q)\n", strings.ToUpper(fn), col.Name))
 			b.WriteString("}\n\n")
@@ -289,6 +313,7 @@ func GenerateQueryScopes(table *schema.Table, blocks []tickle.ScopeBlock, packag
 	b.WriteString(tableScopeBody)
 
 	b.WriteString(scopeBody)
+	b.WriteString(tickle.GenerateCustomScopes(blocks, columns, structName))
 
 	// Generate Search methods
 	generateSearchMethods(&b, table, queryType, structName)
@@ -485,6 +510,7 @@ func GenerateViewQueryScopes(view *schema.View, blocks []tickle.ScopeBlock, pack
 	for _, m := range []struct{ name, sig, call string }{
 		{"Limit", "n int", "Limit(n)"},
 		{"Offset", "n int", "Offset(n)"},
+		{"On", "conn *sql.DB", "On(conn)"},
 	} {
 		b.WriteString(fmt.Sprintf("func (q *%s) %s(%s) *%s {\n", queryType, m.name, m.sig, queryType))
 		b.WriteString(fmt.Sprintf("\tq.QueryBuilder.%s\n", m.call))
@@ -508,6 +534,7 @@ func GenerateViewQueryScopes(view *schema.View, blocks []tickle.ScopeBlock, pack
 	}
 
 	b.WriteString(scopeBody)
+	b.WriteString(tickle.GenerateCustomScopes(blocks, columns, structName))
 
 	formatted, err := format.Source(b.Bytes())
 	if err != nil {
@@ -518,7 +545,7 @@ func GenerateViewQueryScopes(view *schema.View, blocks []tickle.ScopeBlock, pack
 }
 
 func collectViewScopeImports(view *schema.View, blocks []tickle.ScopeBlock) []string {
-	imports := map[string]bool{}
+	imports := map[string]bool{"database/sql": true}
 
 	hasTimestamp := false
 	hasUUID := false
@@ -603,6 +630,11 @@ func collectScopeImports(table *schema.Table, blocks []tickle.ScopeBlock) []stri
 		imports["database/sql"] = true
 	}
 
+	// Mutable tables take a *sql.DB in On.
+	if !table.IsImmutable && !table.IsAppendOnly {
+		imports["database/sql"] = true
+	}
+
 	// Only need strconv if there are numeric or boolean columns
 	for _, col := range table.Columns {
 		switch col.Type {
@@ -944,6 +976,17 @@ func generateScopeBuilderWhereMethods(b *bytes.Buffer, table *schema.Table, scop
 			}
 		}
 
+		// JSONB columns: Path, Contains (mutable and append-only tables)
+		if scope == "json" && !table.IsImmutable {
+			b.WriteString(fmt.Sprintf("func (sb *%s) Where%sPath(path string, val any) *%s {\n", scopeBuilderType, pascal, scopeBuilderType))
+			b.WriteString(fmt.Sprintf("\tsb.whereJSON(%q, path, val)\n", col.Name))
+			b.WriteString("\treturn sb\n}\n\n")
+
+			b.WriteString(fmt.Sprintf("func (sb *%s) Where%sContains(val any) *%s {\n", scopeBuilderType, pascal, scopeBuilderType))
+			b.WriteString(fmt.Sprintf("\tsb.whereJSONContains(%q, val)\n", col.Name))
+			b.WriteString("\treturn sb\n}\n\n")
+		}
+
 		// Timestamp columns: Before, After, inclusive bounds, Between
 		if scope == "timestamp" {
 			b.WriteString(fmt.Sprintf("func (sb *%s) Where%sBefore(val time.Time) *%s {\n", scopeBuilderType, pascal, scopeBuilderType))
@@ -1152,7 +1195,7 @@ func GenerateTxMethods(tables []*schema.Table, nestingMap map[string]SchemaRelat
 	// Collect only top-level tables (those that resolve to the models package)
 	var topLevel []*schema.Table
 	for _, tbl := range tables {
-		_, pkg := resolveModelDir(modelsDir, tbl.Name, nestingMap)
+		_, pkg := resolveModelDir(modelsDir, packageName, tbl.Name, nestingMap)
 		if pkg == packageName {
 			topLevel = append(topLevel, tbl)
 		}
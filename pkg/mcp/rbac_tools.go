@@ -504,12 +504,21 @@ func enhanceSchemaWithVisibility(tbl *schema.Table, graphqlModels []GraphQLModel
 		if c.ForeignKeyTable != "" {
 			attrs = append(attrs, fmt.Sprintf("FK→%s.%s", c.ForeignKeyTable, c.ForeignKeyColumn))
 		}
+		if idx := indexAttr(tbl, c); idx != "" {
+			attrs = append(attrs, idx)
+		}
+		if c.CheckExpr != "" {
+			attrs = append(attrs, fmt.Sprintf("CHECK(%s)", c.CheckExpr))
+		}
 		if c.IsPublic {
 			attrs = append(attrs, "PUBLIC")
 		}
 		if c.IsOwnerSees {
 			attrs = append(attrs, "OWNER_SEES")
 		}
+		if c.IsHidden {
+			attrs = append(attrs, "HIDDEN")
+		}
 		if c.IsOwnerColumn {
 			attrs = append(attrs, "OWNER")
 		}
@@ -531,6 +540,9 @@ func enhanceSchemaWithVisibility(tbl *schema.Table, graphqlModels []GraphQLModel
 		if len(attrs) > 0 {
 			attrStr = " [" + strings.Join(attrs, ", ") + "]"
 		}
+		if c.CommentText != "" {
+			attrStr += " -- " + c.CommentText
+		}
 		fmt.Fprintf(&b, "  %s %s%s\n", c.Name, c.Type, attrStr)
 	}
 	writeCompositeKeyTuples(&b, tbl)
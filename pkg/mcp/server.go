@@ -14,6 +14,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/shortontech/pickle/pkg/generator"
+	"github.com/shortontech/pickle/pkg/names"
 	"github.com/shortontech/pickle/pkg/scaffold"
 	"github.com/shortontech/pickle/pkg/schema"
 	"github.com/shortontech/pickle/pkg/squeeze"
@@ -59,16 +60,31 @@ func (s *Server) RunHTTP(addr string) error {
 }
 
 func (s *Server) registerTools() {
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "project_overview",
+		Description: "Summarize the whole project in one document: tables and views, routes, request classes, auth drivers, config, and migration count. Call this first to get the shape of the app.",
+	}, s.projectOverview)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "schema_show",
 		Description: "Show database schema. Pass a table name to show a specific table, or omit for all tables.",
 	}, s.schemaShow)
 
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "schema_erd",
+		Description: "Show the database schema as a Mermaid erDiagram: tables with their columns, and relationships drawn from foreign keys.",
+	}, s.schemaERD)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "routes_list",
 		Description: "Show all API routes defined in routes/web.go.",
 	}, s.routesList)
 
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "controller_show",
+		Description: "Show one controller (e.g. 'Post' or 'PostController'): each method with its file and line range and the routes that call it. Pass source=true to include each method's code with line numbers.",
+	}, s.controllerShow)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "requests_list",
 		Description: "List all request classes with their fields and validation rules. Pass a name to show a specific request.",
@@ -114,6 +130,16 @@ func (s *Server) registerTools() {
 		Description: "Scaffold a new controller. Pass a name like 'User' or 'UserController'.",
 	}, s.makeController)
 
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "make_resource_controller",
+		Description: "Scaffold a CRUD controller for a table or model (e.g. 'posts' or 'Post'). Store and Update bind the table's actual columns, Index and Show use the generated Query<Model>() builder, and a r.Resource route is added to routes/web.go.",
+	}, s.makeResourceController)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "add_route",
+		Description: "Add a route to routes/web.go. Pass method, full path (e.g. /api/posts/:id), controller, action, and optional middleware (e.g. Auth). The route goes into the deepest r.Group matching the path, or into group (e.g. /api/posts), which is created if missing. Adding a route that already exists is a no-op.",
+	}, s.addRoute)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "make_migration",
 		Description: "Scaffold a new migration. Pass a name like 'create_posts_table'.",
@@ -188,6 +214,14 @@ func (s *Server) schemaShow(_ context.Context, _ *mcp.CallToolRequest, input tab
 	return textResult(b.String()), nil, nil
 }
 
+func (s *Server) schemaERD(_ context.Context, _ *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+	tables, _, _, err := generator.RunSchemaInspector(s.project)
+	if err != nil {
+		return errResult("schema inspection failed: " + err.Error()), nil, nil
+	}
+	return textResult(generator.GenerateERDiagram(tables)), nil, nil
+}
+
 func (s *Server) routesList(_ context.Context, _ *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
 	analysis, err := squeeze.Analyze(s.project.Dir)
 	if err != nil {
@@ -196,6 +230,48 @@ func (s *Server) routesList(_ context.Context, _ *mcp.CallToolRequest, _ any) (*
 	return textResult(formatRoutes(analysis.Routes, analysis.Methods, analysis.Requests)), nil, nil
 }
 
+type controllerInput struct {
+	Name   string `json:"name"`
+	Source bool   `json:"source,omitempty"`
+}
+
+func (s *Server) controllerShow(_ context.Context, _ *mcp.CallToolRequest, input controllerInput) (*mcp.CallToolResult, any, error) {
+	if input.Name == "" {
+		return errResult("name is required"), nil, nil
+	}
+	controller := input.Name
+	if !strings.HasSuffix(controller, "Controller") {
+		controller += "Controller"
+	}
+
+	methods, err := squeeze.ParseControllers(filepath.Join(s.project.Dir, "app", "http", "controllers"))
+	if err != nil {
+		return errResult("parsing controllers: " + err.Error()), nil, nil
+	}
+	var found []*squeeze.ControllerMethod
+	known := map[string]bool{}
+	for _, m := range methods {
+		known[m.ControllerType] = true
+		if m.ControllerType == controller {
+			found = append(found, m)
+		}
+	}
+	if len(found) == 0 {
+		var available []string
+		for name := range known {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		return errResult(fmt.Sprintf("controller %q not found; available: %s", controller, strings.Join(available, ", "))), nil, nil
+	}
+
+	routes, err := squeeze.ParseRoutes(filepath.Join(s.project.Dir, "routes"))
+	if err != nil {
+		return errResult("parsing routes: " + err.Error()), nil, nil
+	}
+	return textResult(formatController(controller, found, routes, s.project.Dir, input.Source)), nil, nil
+}
+
 type requestInput struct {
 	Name string `json:"name,omitempty"`
 }
@@ -438,6 +514,84 @@ func (s *Server) makeController(_ context.Context, _ *mcp.CallToolRequest, input
 	return textResult("Created " + relPath), nil, nil
 }
 
+func (s *Server) makeResourceController(_ context.Context, _ *mcp.CallToolRequest, input makeInput) (*mcp.CallToolResult, any, error) {
+	if input.Name == "" {
+		return errResult("name is required"), nil, nil
+	}
+	tables, _, _, err := generator.RunSchemaInspector(s.project)
+	if err != nil {
+		return errResult("schema inspection failed: " + err.Error()), nil, nil
+	}
+	table := findTableByName(tables, strings.TrimSuffix(input.Name, "Controller"))
+	if table == nil {
+		return errResult(fmt.Sprintf("table or model %q not found", input.Name)), nil, nil
+	}
+
+	relPath, controller, err := scaffold.MakeResourceController(table, s.project.Dir, s.project.ModulePath)
+	if err != nil {
+		return errResult(err.Error()), nil, nil
+	}
+	msg := "Created " + relPath
+
+	path := scaffold.ResourcePath(table.Name)
+	added, err := scaffold.AddResourceRoute(s.project.Dir, path, controller)
+	switch {
+	case err != nil:
+		msg += fmt.Sprintf("\nCould not update routes/web.go: %v\nAdd r.Resource(%q, controllers.%s{}) yourself.", err, path, controller)
+	case added:
+		msg += fmt.Sprintf("\nAdded r.Resource(%q, controllers.%s{}) to routes/web.go", path, controller)
+	default:
+		msg += "\nroutes/web.go already registers " + controller
+	}
+	return textResult(msg), nil, nil
+}
+
+type addRouteInput struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Controller string   `json:"controller"`
+	Action     string   `json:"action"`
+	Middleware []string `json:"middleware,omitempty"`
+	Group      string   `json:"group,omitempty"`
+}
+
+func (s *Server) addRoute(_ context.Context, _ *mcp.CallToolRequest, input addRouteInput) (*mcp.CallToolResult, any, error) {
+	if input.Method == "" || input.Path == "" || input.Controller == "" || input.Action == "" {
+		return errResult("method, path, controller and action are required"), nil, nil
+	}
+	added, err := scaffold.AddRoute(s.project.Dir, scaffold.RouteSpec{
+		Method:     input.Method,
+		Path:       input.Path,
+		Controller: input.Controller,
+		Action:     input.Action,
+		Middleware: input.Middleware,
+		Group:      input.Group,
+	})
+	if err != nil {
+		return errResult("could not update routes/web.go: " + err.Error()), nil, nil
+	}
+	route := strings.ToUpper(input.Method) + " " + input.Path
+	if !added {
+		return textResult("routes/web.go already registers " + route), nil, nil
+	}
+	return textResult("Added " + route + " to routes/web.go"), nil, nil
+}
+
+// findTableByName matches a table name ("posts") or model name ("Post").
+func findTableByName(tables []*schema.Table, name string) *schema.Table {
+	for _, t := range tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	for _, t := range tables {
+		if names.TableToStructName(t.Name) == name {
+			return t
+		}
+	}
+	return nil
+}
+
 func (s *Server) makeMigration(_ context.Context, _ *mcp.CallToolRequest, input makeInput) (*mcp.CallToolResult, any, error) {
 	if input.Name == "" {
 		return errResult("name is required"), nil, nil
@@ -529,6 +683,48 @@ func (s *Server) squeeze(_ context.Context, _ *mcp.CallToolRequest, _ any) (*mcp
 	return textResult(b.String()), nil, nil
 }
 
+// projectOverview gathers the inputs of the individual list tools. A scan that
+// fails is reported in its section instead of failing the whole overview.
+type projectOverview struct {
+	Module      string
+	Tables      []*schema.Table
+	Views       []*schema.View
+	Routes      []squeeze.AnalyzedRoute
+	Requests    []generator.RequestDef
+	AuthDrivers []generator.AuthDriverInfo
+	Configs     []generator.ConfigDef
+	Migrations  int
+	Errors      map[string]string // section name → scan error
+}
+
+func (s *Server) projectOverview(_ context.Context, _ *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+	o := projectOverview{Module: s.project.ModulePath, Errors: map[string]string{}}
+	var err error
+	if o.Tables, o.Views, _, err = generator.RunSchemaInspector(s.project); err != nil {
+		o.Errors["schema"] = err.Error()
+	}
+	if o.Routes, err = squeeze.ParseRoutes(filepath.Join(s.project.Dir, "routes")); err != nil {
+		o.Errors["routes"] = err.Error()
+	}
+	if o.Requests, err = generator.ScanRequests(s.project.Layout.RequestsDir); err != nil {
+		o.Errors["requests"] = err.Error()
+	}
+	if o.AuthDrivers, err = generator.ScanAuthDrivers(s.project.Layout.AuthDir); err != nil {
+		o.Errors["auth"] = err.Error()
+	}
+	if configs, err := generator.ScanConfigs(s.project.Layout.ConfigDir); err != nil {
+		o.Errors["config"] = err.Error()
+	} else {
+		o.Configs = configs.Configs
+	}
+	if migrations, err := generator.ScanMigrationFiles(s.project.Layout.MigrationsDir); err != nil {
+		o.Errors["migrations"] = err.Error()
+	} else {
+		o.Migrations = len(migrations)
+	}
+	return textResult(formatProjectOverview(o)), nil, nil
+}
+
 // --- formatting helpers ---
 
 func formatTable(t *schema.Table) string {
@@ -551,12 +747,21 @@ func formatTable(t *schema.Table) string {
 		if c.ForeignKeyTable != "" {
 			attrs = append(attrs, fmt.Sprintf("FK→%s.%s", c.ForeignKeyTable, c.ForeignKeyColumn))
 		}
+		if idx := indexAttr(t, c); idx != "" {
+			attrs = append(attrs, idx)
+		}
+		if c.CheckExpr != "" {
+			attrs = append(attrs, fmt.Sprintf("CHECK(%s)", c.CheckExpr))
+		}
 		if c.IsPublic {
 			attrs = append(attrs, "PUBLIC")
 		}
 		if c.IsOwnerSees {
 			attrs = append(attrs, "OWNER_SEES")
 		}
+		if c.IsHidden {
+			attrs = append(attrs, "HIDDEN")
+		}
 		if c.IsOwnerColumn {
 			attrs = append(attrs, "OWNER")
 		}
@@ -568,12 +773,30 @@ func formatTable(t *schema.Table) string {
 		if len(attrs) > 0 {
 			attrStr = " [" + strings.Join(attrs, ", ") + "]"
 		}
+		if c.CommentText != "" {
+			attrStr += " -- " + c.CommentText
+		}
 		fmt.Fprintf(&b, "  %s %s%s\n", c.Name, c.Type, attrStr)
 	}
 	writeCompositeKeyTuples(&b, t)
 	return b.String()
 }
 
+// indexAttr labels columns with an explicit index leading on them, and
+// foreign keys with no index at all. Primary key and UNIQUE columns are
+// indexed implicitly and already labeled.
+func indexAttr(t *schema.Table, c *schema.Column) string {
+	switch t.IndexFor(c.Name) {
+	case schema.IndexExplicit:
+		return "INDEXED"
+	case schema.IndexNone:
+		if c.ForeignKeyTable != "" {
+			return "NO INDEX"
+		}
+	}
+	return ""
+}
+
 func formatSeedSpec(seed *schema.SeedSpec) string {
 	if seed == nil {
 		return ""
@@ -679,7 +902,11 @@ func formatSeederPlan(definition generator.SeederDefinition, tables []*schema.Ta
 func formatRoutes(routes []squeeze.AnalyzedRoute, methods map[string]*squeeze.ControllerMethod, requests []generator.RequestDef) string {
 	var b strings.Builder
 	for _, route := range routes {
-		fmt.Fprintf(&b, "%s %s -> %s.%s\n", route.Method, route.Path, route.ControllerType, route.MethodName)
+		fmt.Fprintf(&b, "%s %s -> %s.%s", route.Method, route.Path, route.ControllerType, route.MethodName)
+		if route.Name != "" {
+			fmt.Fprintf(&b, " (%s)", route.Name)
+		}
+		b.WriteString("\n")
 		method := methods[route.ControllerType+"."+route.MethodName]
 		if method == nil {
 			continue
@@ -714,6 +941,152 @@ func formatRoutes(routes []squeeze.AnalyzedRoute, methods map[string]*squeeze.Co
 	return b.String()
 }
 
+// formatController lists a controller's methods in file order, each with its
+// location and the routes dispatching to it, and with source the method's
+// code prefixed by line numbers.
+func formatController(controller string, methods []*squeeze.ControllerMethod, routes []squeeze.AnalyzedRoute, projectDir string, source bool) string {
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].File != methods[j].File {
+			return methods[i].File < methods[j].File
+		}
+		return methods[i].Line < methods[j].Line
+	})
+	files := map[string][]string{}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", controller)
+	for _, m := range methods {
+		file := m.File
+		if relative, err := filepath.Rel(projectDir, m.File); err == nil {
+			file = relative
+		}
+		end := m.Fset.Position(m.Body.Rbrace).Line
+		fmt.Fprintf(&b, "\n## %s  %s:%d-%d\n", m.MethodName, file, m.Line, end)
+
+		wired := false
+		for _, route := range routes {
+			if route.ControllerType != controller || route.MethodName != m.MethodName {
+				continue
+			}
+			wired = true
+			fmt.Fprintf(&b, "  %s %s", route.Method, route.Path)
+			if route.Name != "" {
+				fmt.Fprintf(&b, " (%s)", route.Name)
+			}
+			if len(route.Middleware) > 0 {
+				fmt.Fprintf(&b, " [%s]", strings.Join(route.Middleware, ", "))
+			}
+			b.WriteString("\n")
+		}
+		if !wired {
+			b.WriteString("  (no routes)\n")
+		}
+
+		if !source {
+			continue
+		}
+		lines, ok := files[m.File]
+		if !ok {
+			data, err := os.ReadFile(m.File)
+			if err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+			files[m.File] = lines
+		}
+		if end > len(lines) {
+			continue
+		}
+		b.WriteString("\n")
+		for n := m.Line; n <= end; n++ {
+			fmt.Fprintf(&b, "%5d  %s\n", n, lines[n-1])
+		}
+	}
+	return b.String()
+}
+
+func formatProjectOverview(o projectOverview) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", o.Module)
+	// Only the first line of a scan error fits a one-screen summary; the
+	// dedicated tool for that section shows the full output.
+	unavailable := func(key string) bool {
+		msg, _, _ := strings.Cut(o.Errors[key], "\n")
+		if msg == "" {
+			return false
+		}
+		fmt.Fprintf(&b, "unavailable: %s\n", msg)
+		return true
+	}
+	section := func(title, key string, n int) bool {
+		fmt.Fprintf(&b, "\n## %s (%d)\n", title, n)
+		return !unavailable(key)
+	}
+
+	if section("Tables", "schema", len(o.Tables)+len(o.Views)) {
+		for _, t := range o.Tables {
+			var refs []string
+			for _, c := range t.Columns {
+				if c.ForeignKeyTable != "" {
+					refs = append(refs, c.ForeignKeyTable)
+				}
+			}
+			fmt.Fprintf(&b, "- %s: %d columns", t.Name, len(t.Columns))
+			if len(refs) > 0 {
+				fmt.Fprintf(&b, ", references %s", strings.Join(refs, ", "))
+			}
+			b.WriteString("\n")
+		}
+		for _, v := range o.Views {
+			fmt.Fprintf(&b, "- %s: view\n", v.Name)
+		}
+	}
+
+	if section("Routes", "routes", len(o.Routes)) {
+		for _, r := range o.Routes {
+			fmt.Fprintf(&b, "- %s %s → %s.%s", r.Method, r.Path, r.ControllerType, r.MethodName)
+			if r.Name != "" {
+				fmt.Fprintf(&b, " (%s)", r.Name)
+			}
+			if len(r.Middleware) > 0 {
+				fmt.Fprintf(&b, " [%s]", strings.Join(r.Middleware, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if section("Requests", "requests", len(o.Requests)) {
+		for _, r := range o.Requests {
+			fields := make([]string, len(r.Fields))
+			for i, f := range r.Fields {
+				fields[i] = f.Name
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", r.Name, strings.Join(fields, ", "))
+		}
+	}
+
+	if section("Auth drivers", "auth", len(o.AuthDrivers)) {
+		for _, d := range o.AuthDrivers {
+			kind := "custom"
+			if d.IsBuiltin {
+				kind = "builtin"
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", d.Name, kind)
+		}
+	}
+
+	if section("Config", "config", len(o.Configs)) {
+		for _, c := range o.Configs {
+			fmt.Fprintf(&b, "- %s → %s\n", c.VarName, c.ReturnType)
+		}
+	}
+
+	b.WriteString("\n## Migrations\n")
+	if !unavailable("migrations") {
+		fmt.Fprintf(&b, "%d migration files\n", o.Migrations)
+	}
+	return b.String()
+}
+
 func formatView(v *schema.View) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "## %s (view)\n", v.Name)
@@ -6,9 +6,11 @@ import (
 	"go/token"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/shortontech/pickle/pkg/generator"
 	"github.com/shortontech/pickle/pkg/schema"
 	"github.com/shortontech/pickle/pkg/squeeze"
@@ -113,6 +115,26 @@ func TestFormatTable_Basic(t *testing.T) {
 		}
 	})
 
+	t.Run("foreign key index", func(t *testing.T) {
+		tbl := &schema.Table{Name: "t", Columns: []*schema.Column{
+			{Name: "user_id", ForeignKeyTable: "users", ForeignKeyColumn: "id"},
+			{Name: "team_id", ForeignKeyTable: "teams", ForeignKeyColumn: "id"},
+		}, Indexes: []*schema.Index{{Columns: []string{"team_id"}}}}
+		out := formatTable(tbl)
+		if !strings.Contains(out, "[NOT NULL, FK→users.id, NO INDEX]") || !strings.Contains(out, "[NOT NULL, FK→teams.id, INDEXED]") {
+			t.Errorf("expected NO INDEX on user_id and INDEXED on team_id, got: %s", out)
+		}
+	})
+
+	t.Run("check and comment", func(t *testing.T) {
+		col := &schema.Column{Name: "quantity", CheckExpr: "quantity > 0", CommentText: "Units ordered"}
+		tbl := &schema.Table{Name: "t", Columns: []*schema.Column{col}}
+		out := formatTable(tbl)
+		if !strings.Contains(out, "CHECK(quantity > 0)") || !strings.Contains(out, "-- Units ordered") {
+			t.Errorf("expected CHECK and comment in output, got: %s", out)
+		}
+	})
+
 	t.Run("composite keys", func(t *testing.T) {
 		tbl := &schema.Table{Name: "notes", Columns: []*schema.Column{
 			{Name: "organization_id", Type: schema.BigInteger, IsPrimaryKey: true},
@@ -444,6 +466,28 @@ func TestMakeHandlers_EmptyName(t *testing.T) {
 	if !result.IsError {
 		t.Error("makeMiddleware with empty name should return error result")
 	}
+
+	// Test makeResourceController
+	result, _, err = s.makeResourceController(nil, nil, input)
+	if err != nil {
+		t.Fatalf("makeResourceController: unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("makeResourceController with empty name should return error result")
+	}
+}
+
+func TestFindTableByName(t *testing.T) {
+	tables := []*schema.Table{{Name: "users"}, {Name: "blog_posts"}}
+	if got := findTableByName(tables, "blog_posts"); got != tables[1] {
+		t.Errorf("table name lookup = %v", got)
+	}
+	if got := findTableByName(tables, "BlogPost"); got != tables[1] {
+		t.Errorf("model name lookup = %v", got)
+	}
+	if got := findTableByName(tables, "Comment"); got != nil {
+		t.Errorf("unknown name = %v, want nil", got)
+	}
 }
 
 // --- Server with real project ---
@@ -488,6 +532,46 @@ func TestRoutesListHandler(t *testing.T) {
 	}
 }
 
+func TestControllerShowHandler(t *testing.T) {
+	s, err := NewServer("../../testdata/basic-crud")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	result, _, err := s.controllerShow(nil, nil, controllerInput{Name: "Post", Source: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{
+		"# PostController\n",
+		"## Index  " + filepath.Join("app", "http", "controllers", "post_controller.go") + ":16-",
+		"  GET /api/posts/ [Auth]\n",
+		"  DELETE /api/posts/:id [Auth]\n",
+		"   16  func (c PostController) Index(ctx *pickle.Context) pickle.Response {\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "## Index") > strings.Index(text, "## Show") {
+		t.Errorf("methods should be in file order:\n%s", text)
+	}
+
+	result, _, _ = s.controllerShow(nil, nil, controllerInput{Name: "PostController"})
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "WhereOwnedBy") {
+		t.Errorf("source should be omitted unless requested:\n%s", text)
+	}
+
+	result, _, _ = s.controllerShow(nil, nil, controllerInput{Name: "Missing"})
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "PostController") {
+		t.Errorf("unknown controller should list the available ones: %+v", result.Content)
+	}
+}
+
 func TestFormatRoutesLabelsProvenResourceIDs(t *testing.T) {
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, "party_controller.go", `package controllers
@@ -500,11 +584,11 @@ func (c PartyController) Show(ctx *Context) {
 		t.Fatal(err)
 	}
 	method := file.Decls[0].(*ast.FuncDecl)
-	routes := []squeeze.AnalyzedRoute{{Method: "GET", Path: "/parties/:party_id", ControllerType: "PartyController", MethodName: "Show"}}
+	routes := []squeeze.AnalyzedRoute{{Method: "GET", Path: "/parties/:party_id", ControllerType: "PartyController", MethodName: "Show", Name: "parties.show"}}
 	methods := map[string]*squeeze.ControllerMethod{"PartyController.Show": {Body: method.Body, Fset: fset}}
 	requests := []generator.RequestDef{{Name: "MovePartyRequest", Fields: []generator.RequestField{{Name: "TargetID", IsResourceID: true}}}}
 	out := formatRoutes(routes, methods, requests)
-	for _, want := range []string{"param party_id ResourceID", "request req.TargetID ResourceID"} {
+	for _, want := range []string{"GET /parties/:party_id -> PartyController.Show (parties.show)\n", "param party_id ResourceID", "request req.TargetID ResourceID"} {
 		if !strings.Contains(out, want) {
 			t.Fatalf("missing %q in:\n%s", want, out)
 		}
@@ -514,6 +598,72 @@ func (c PartyController) Show(ctx *Context) {
 	}
 }
 
+func TestFormatProjectOverview(t *testing.T) {
+	posts := &schema.Table{Name: "posts"}
+	posts.UUID("id").PrimaryKey()
+	posts.UUID("user_id").ForeignKey("users", "id")
+	out := formatProjectOverview(projectOverview{
+		Module: "myapp",
+		Tables: []*schema.Table{posts},
+		Routes: []squeeze.AnalyzedRoute{
+			{Method: "GET", Path: "/posts", ControllerType: "PostController", MethodName: "Index", Name: "posts.index", Middleware: []string{"Auth"}},
+		},
+		Requests:    []generator.RequestDef{{Name: "CreatePostRequest", Fields: []generator.RequestField{{Name: "Title"}, {Name: "Body"}}}},
+		AuthDrivers: []generator.AuthDriverInfo{{Name: "jwt", IsBuiltin: true}},
+		Configs:     []generator.ConfigDef{{VarName: "Database", ReturnType: "DatabaseConfig"}},
+		Migrations:  3,
+		Errors:      map[string]string{},
+	})
+	for _, want := range []string{
+		"# myapp",
+		"## Tables (1)\n- posts: 2 columns, references users",
+		"- GET /posts → PostController.Index (posts.index) [Auth]",
+		"- CreatePostRequest: Title, Body",
+		"- jwt (builtin)",
+		"- Database → DatabaseConfig",
+		"3 migration files",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatProjectOverview_ReportsFailedScans(t *testing.T) {
+	out := formatProjectOverview(projectOverview{
+		Module: "myapp",
+		Errors: map[string]string{"schema": "inspector exploded\nmigrations.go:4: undefined: Migration", "migrations": "no dir"},
+	})
+	for _, want := range []string{"unavailable: inspector exploded\n", "## Migrations\nunavailable: no dir"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "undefined: Migration") {
+		t.Errorf("overview should keep only the first line of a scan error:\n%s", out)
+	}
+}
+
+func TestProjectOverviewHandler(t *testing.T) {
+	s, err := NewServer("../../testdata/basic-crud")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	result, _, err := s.projectOverview(nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("projectOverview failed: %+v", result.Content)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"## Routes", "## Requests", "## Migrations"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
 func TestRequestsListHandler(t *testing.T) {
 	projectDir := "../../testdata/basic-crud"
 	s, err := NewServer(projectDir)
@@ -639,6 +789,37 @@ func TestMakeControllerHandler_WithProject(t *testing.T) {
 	}
 }
 
+func TestAddRouteHandler_WithProject(t *testing.T) {
+	projectDir := copyTestProject(t)
+	s, err := NewServer(projectDir)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	input := addRouteInput{Method: "POST", Path: "/api/posts/:id/publish", Controller: "PostController", Action: "Publish", Middleware: []string{"Auth"}}
+	result, _, err := s.addRoute(nil, nil, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("addRoute failed: %+v", result.Content)
+	}
+	data, _ := os.ReadFile(filepath.Join(projectDir, "routes", "web.go"))
+	if !strings.Contains(string(data), `r.Post("/:id/publish", controllers.PostController{}.Publish, middleware.Auth)`) {
+		t.Fatalf("route not added to the /posts group:\n%s", data)
+	}
+
+	result, _, _ = s.addRoute(nil, nil, input)
+	if result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "already registers") {
+		t.Errorf("second addRoute should be a no-op: %+v", result.Content)
+	}
+
+	result, _, _ = s.addRoute(nil, nil, addRouteInput{Method: "GET", Path: "/api/posts"})
+	if !result.IsError {
+		t.Error("addRoute without controller and action should return error result")
+	}
+}
+
 func TestMakeMigrationHandler_WithProject(t *testing.T) {
 	projectDir := copyTestProject(t)
 	s, err := NewServer(projectDir)
@@ -3,8 +3,20 @@
 package migration
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // MigrationIface is implemented by all migration structs via embedded Migration.
@@ -25,9 +37,10 @@ type MigrationEntry struct {
 
 // MigrationStatus describes a migration's current state.
 type MigrationStatus struct {
-	ID      string
-	Batch   int
-	Applied bool
+	ID      string `json:"id"`
+	Batch   int    `json:"batch"`
+	Applied bool   `json:"applied"`
+	Changed bool   `json:"changed"` // applied, but its generated SQL no longer matches the recorded checksum
 }
 
 // SQLGenerator converts schema operations to SQL for a specific driver.
@@ -41,11 +54,89 @@ type SQLGenerator interface {
 	RenameTable(oldName, newName string) string
 }
 
+// columnCommenter is implemented by generators that store column comments
+// with a statement of their own rather than inline in the column definition.
+type columnCommenter interface {
+	CommentOnColumn(table string, col *Column) string
+}
+
+// columnChanger is implemented by generators that can alter an existing
+// column's type, nullability and default. SQLite can't, short of rebuilding
+// the table.
+type columnChanger interface {
+	ChangeColumn(table string, col *Column) string
+}
+
 // Runner executes migrations against a database.
 type Runner struct {
 	DB        *sql.DB
 	Driver    string
 	Generator SQLGenerator
+
+	// ddlAutoCommits is set for drivers that implicitly commit around every
+	// DDL statement (MySQL), where a transaction cannot undo schema changes.
+	ddlAutoCommits bool
+	warnedDDL      bool
+
+	// StrictChecksums makes Migrate fail, instead of warn, when an applied
+	// migration's generated SQL no longer matches the checksum recorded
+	// when it ran.
+	StrictChecksums bool
+
+	// LockKey is the Postgres advisory lock key held while migrations run,
+	// so concurrent deploys don't migrate at once. NewRunner derives it from
+	// the app's module path, or takes MIGRATION_LOCK_KEY when set, so apps
+	// sharing a database cluster don't wait on each other.
+	LockKey int64
+
+	// LockTimeout is how long to wait for another process holding LockKey
+	// before giving up. Zero waits forever.
+	LockTimeout time.Duration
+
+	lockConn *sql.Conn
+}
+
+// legacyLockKey is the advisory lock key used before keys were derived per
+// app, and the fallback when the module path is unavailable.
+const legacyLockKey = 20260101
+
+// DefaultLockTimeout is the LockTimeout NewRunner sets unless
+// MIGRATION_LOCK_TIMEOUT overrides it.
+const DefaultLockTimeout = time.Minute
+
+// lockRetryInterval is how often acquireLock retries a held lock.
+const lockRetryInterval = 250 * time.Millisecond
+
+// LockKeyFor derives an advisory lock key from a name, typically the app's
+// module path.
+func LockKeyFor(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name)) //nolint:errcheck // hash writes never fail
+	return int64(h.Sum64())
+}
+
+// defaultLockKey returns MIGRATION_LOCK_KEY when set, otherwise a key
+// derived from the main module's path.
+func defaultLockKey() int64 {
+	if v := os.Getenv("MIGRATION_LOCK_KEY"); v != "" {
+		if key, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return key
+		}
+		return LockKeyFor(v)
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" {
+		return LockKeyFor(info.Main.Path)
+	}
+	return legacyLockKey
+}
+
+// defaultLockTimeout returns MIGRATION_LOCK_TIMEOUT, a Go duration such as
+// "30s", when set and valid, and DefaultLockTimeout otherwise.
+func defaultLockTimeout() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("MIGRATION_LOCK_TIMEOUT")); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultLockTimeout
 }
 
 // NewRunner creates a Runner configured for the given driver.
@@ -59,7 +150,14 @@ func NewRunner(db *sql.DB, driver string) *Runner {
 	default:
 		gen = &sqliteGenerator{}
 	}
-	return &Runner{DB: db, Driver: driver, Generator: gen}
+	return &Runner{
+		DB:             db,
+		Driver:         driver,
+		Generator:      gen,
+		ddlAutoCommits: driver == "mysql",
+		LockKey:        defaultLockKey(),
+		LockTimeout:    defaultLockTimeout(),
+	}
 }
 
 func (r *Runner) ensureMigrationsTable() error {
@@ -69,31 +167,92 @@ func (r *Runner) ensureMigrationsTable() error {
 		q = `CREATE TABLE IF NOT EXISTS migrations (
 			id        SERIAL PRIMARY KEY,
 			migration VARCHAR(255) NOT NULL,
-			batch     INTEGER NOT NULL
+			batch     INTEGER NOT NULL,
+			checksum  VARCHAR(64),
+			checksum_version INTEGER
 		)`
 	default:
 		q = `CREATE TABLE IF NOT EXISTS migrations (
 			id        INTEGER PRIMARY KEY AUTOINCREMENT,
 			migration VARCHAR(255) NOT NULL,
-			batch     INTEGER NOT NULL
+			batch     INTEGER NOT NULL,
+			checksum  VARCHAR(64),
+			checksum_version INTEGER
 		)`
 	}
-	_, err := r.DB.Exec(q)
-	return err
+	if _, err := r.DB.Exec(q); err != nil {
+		return err
+	}
+	return r.ensureChecksumColumns()
+}
+
+// ensureChecksumColumns adds the checksum columns to migrations tables
+// created before checksums were recorded. Rows applied before then have a
+// NULL checksum until Migrate backfills it.
+func (r *Runner) ensureChecksumColumns() error {
+	for _, col := range []struct{ name, def string }{
+		{"checksum", "VARCHAR(64)"},
+		{"checksum_version", "INTEGER"},
+	} {
+		probe := "SELECT " + col.name + " FROM migrations WHERE 1 = 0"
+		if _, err := r.DB.Exec(probe); err == nil {
+			continue
+		}
+		if _, err := r.DB.Exec("ALTER TABLE migrations ADD COLUMN " + col.name + " " + col.def); err != nil {
+			// Another process may have added it between the probe and the ALTER.
+			if _, probeErr := r.DB.Exec(probe); probeErr == nil {
+				continue
+			}
+			return fmt.Errorf("adding %s column: %w", col.name, err)
+		}
+	}
+	return nil
 }
 
+// acquireLock takes the Postgres advisory lock LockKey on a dedicated
+// connection, so releaseLock unlocks the same session. It polls with
+// pg_try_advisory_lock rather than blocking, and fails once LockTimeout has
+// passed instead of hanging behind a stuck migration.
 func (r *Runner) acquireLock() error {
-	if r.Driver == "pgsql" || r.Driver == "postgres" {
-		_, err := r.DB.Exec("SELECT pg_advisory_lock(20260101)")
+	if r.Driver != "pgsql" && r.Driver != "postgres" {
+		return nil
+	}
+	ctx := context.Background()
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
 		return err
 	}
-	return nil
+	var deadline time.Time
+	if r.LockTimeout > 0 {
+		deadline = time.Now().Add(r.LockTimeout)
+	}
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", r.LockKey).Scan(&locked); err != nil {
+			conn.Close()
+			return err
+		}
+		if locked {
+			r.lockConn = conn
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			conn.Close()
+			return fmt.Errorf("advisory lock %d still held by another process after %s; if no migration is running, find the session with SELECT pid FROM pg_locks WHERE locktype = 'advisory' AND objid = %d, or set MIGRATION_LOCK_TIMEOUT to wait longer", r.LockKey, r.LockTimeout, uint32(r.LockKey))
+		}
+		time.Sleep(lockRetryInterval)
+	}
 }
 
+// releaseLock releases the lock taken by acquireLock. It is safe to call
+// when no lock is held.
 func (r *Runner) releaseLock() {
-	if r.Driver == "pgsql" || r.Driver == "postgres" {
-		r.DB.Exec("SELECT pg_advisory_unlock(20260101)") //nolint:errcheck
+	if r.lockConn == nil {
+		return
 	}
+	r.lockConn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", r.LockKey) //nolint:errcheck
+	r.lockConn.Close()
+	r.lockConn = nil
 }
 
 func (r *Runner) applied() (map[string]int, error) {
@@ -114,6 +273,96 @@ func (r *Runner) applied() (map[string]int, error) {
 	return m, rows.Err()
 }
 
+// checksumVersion identifies the SQL the generators render. Bump it when a
+// generator change alters the DDL an unchanged migration produces, so
+// checksums recorded under the old output are re-recorded rather than
+// reported as edits.
+const checksumVersion = 1
+
+// appliedChecksums returns the recorded checksum of each applied migration,
+// "" for rows applied before checksums were recorded or whose checksum was
+// taken under another checksumVersion.
+func (r *Runner) appliedChecksums() (map[string]string, error) {
+	rows, err := r.DB.Query("SELECT migration, checksum, checksum_version FROM migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	m := map[string]string{}
+	for rows.Next() {
+		var id string
+		var sum sql.NullString
+		var version sql.NullInt64
+		if err := rows.Scan(&id, &sum, &version); err != nil {
+			return nil, err
+		}
+		if version.Int64 == checksumVersion {
+			m[id] = sum.String
+		} else {
+			m[id] = ""
+		}
+	}
+	return m, rows.Err()
+}
+
+// checksum hashes the SQL a migration's Up generates for this driver, so
+// editing an applied migration changes it. The hash is only comparable
+// between runs with the same checksumVersion.
+func (r *Runner) checksum(m MigrationIface, immutableTables map[string]bool) (string, error) {
+	m.Reset()
+	m.Up()
+	ops := m.GetOperations()
+	if immutableTables != nil {
+		markFKMetadataOnly(ops, immutableTables)
+	}
+	sqls, err := r.renderOps(ops)
+	m.Reset()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, q := range sqls {
+		h.Write([]byte(q))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// changedMigrations compares the current checksum of every applied migration
+// with the recorded one and returns the IDs that differ. With backfill set,
+// applied rows without a comparable checksum record the current one.
+func (r *Runner) changedMigrations(entries []MigrationEntry, immutableTables map[string]bool, backfill bool) ([]string, error) {
+	recorded, err := r.appliedChecksums()
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, entry := range entries {
+		stored, ok := recorded[entry.ID]
+		if !ok || (stored == "" && !backfill) {
+			continue
+		}
+		current, err := r.checksum(entry.Migration, immutableTables)
+		if err != nil {
+			return nil, fmt.Errorf("checksumming %s: %w", entry.ID, err)
+		}
+		if stored == "" {
+			q := fmt.Sprintf( //nolint:gosec // G201: placeholders ($1/$2/$3 or ?), not user data
+				"UPDATE migrations SET checksum = %s, checksum_version = %s WHERE migration = %s",
+				r.placeholder(1), r.placeholder(2), r.placeholder(3),
+			)
+			if _, err := r.DB.Exec(q, current, checksumVersion, entry.ID); err != nil {
+				return nil, fmt.Errorf("recording checksum for %s: %w", entry.ID, err)
+			}
+			continue
+		}
+		if stored != current {
+			changed = append(changed, entry.ID)
+		}
+	}
+	return changed, nil
+}
+
 func (r *Runner) nextBatch(applied map[string]int) int {
 	max := 0
 	for _, b := range applied {
@@ -132,33 +381,57 @@ func (r *Runner) placeholder(n int) string {
 }
 
 func (r *Runner) execOps(ops []Operation, tx *sql.Tx) error {
+	_, err := r.execOpsCounted(ops, tx)
+	return err
+}
+
+// execOpsCounted is execOps that also reports how many statements succeeded
+// before the first failure.
+func (r *Runner) execOpsCounted(ops []Operation, tx *sql.Tx) (int, error) {
+	sqls, err := r.renderOps(ops)
+	if err != nil {
+		return 0, err
+	}
+	executed := 0
+	for _, q := range sqls {
+		if tx != nil {
+			if _, err := tx.Exec(q); err != nil {
+				return executed, fmt.Errorf("executing %q: %w", q, err)
+			}
+		} else {
+			if _, err := r.DB.Exec(q); err != nil {
+				return executed, fmt.Errorf("executing %q: %w", q, err)
+			}
+		}
+		executed++
+	}
+	return executed, nil
+}
+
+// renderOps converts operations to the statements the runner executes for
+// its driver, in order, without touching the database. Migrate, the
+// checksums and RenderSQL all go through it, so what a DBA reviews is what
+// runs.
+func (r *Runner) renderOps(ops []Operation) ([]string, error) {
+	var out []string
 	for _, op := range ops {
 		sqls, err := r.opsToSQL(op)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, q := range sqls {
-			if q == "" {
-				continue
-			}
-			if tx != nil {
-				if _, err := tx.Exec(q); err != nil {
-					return fmt.Errorf("executing %q: %w", q, err)
-				}
-			} else {
-				if _, err := r.DB.Exec(q); err != nil {
-					return fmt.Errorf("executing %q: %w", q, err)
-				}
+			if q != "" {
+				out = append(out, q)
 			}
 		}
 	}
-	return nil
+	return out, nil
 }
 
 func (r *Runner) opsToSQL(op Operation) ([]string, error) {
 	switch op.Type {
 	case OpCreateTable:
-		return []string{r.Generator.CreateTable(op.TableDef)}, nil
+		return append([]string{r.Generator.CreateTable(op.TableDef)}, r.columnComments(op.TableDef.Name, op.TableDef.Columns)...), nil
 	case OpDropTableIfExists:
 		return []string{r.Generator.DropTableIfExists(op.Table)}, nil
 	case OpRenameTable:
@@ -170,9 +443,21 @@ func (r *Runner) opsToSQL(op Operation) ([]string, error) {
 		for _, col := range expandColumns(tmp.Columns) {
 			out = append(out, r.Generator.AddColumn(op.Table, col))
 		}
-		return out, nil
+		return append(out, r.columnComments(op.Table, tmp.Columns)...), nil
 	case OpDropColumn:
 		return []string{r.Generator.DropColumn(op.Table, op.ColumnName)}, nil
+	case OpChangeColumn:
+		changer, ok := r.Generator.(columnChanger)
+		if !ok {
+			return nil, fmt.Errorf("ChangeColumn %s.%s: driver %q cannot alter a column; rebuild the table with CreateTable and RawSQL instead", op.Table, op.ColumnName, r.Driver)
+		}
+		tmp := &Table{}
+		op.ColumnDef(tmp)
+		var out []string
+		for _, col := range tmp.Columns {
+			out = append(out, changer.ChangeColumn(op.Table, col))
+		}
+		return append(out, r.columnComments(op.Table, tmp.Columns)...), nil
 	case OpRenameColumn:
 		return []string{r.Generator.RenameColumn(op.Table, op.OldName, op.NewName)}, nil
 	case OpAddIndex, OpAddUniqueIndex:
@@ -192,6 +477,22 @@ func (r *Runner) opsToSQL(op Operation) ([]string, error) {
 	return nil, nil
 }
 
+// columnComments returns the statements that attach column comments for
+// generators that cannot declare them inline.
+func (r *Runner) columnComments(table string, cols []*Column) []string {
+	commenter, ok := r.Generator.(columnCommenter)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, col := range cols {
+		if col.CommentText != "" {
+			out = append(out, commenter.CommentOnColumn(table, col))
+		}
+	}
+	return out
+}
+
 // markFKMetadataOnly scans all operations for CreateTable and marks any FK
 // column whose target table is immutable or append-only as metadata-only
 // (no SQL REFERENCES constraint). Immutable tables have non-unique id columns
@@ -238,25 +539,32 @@ func (r *Runner) runMigrationWithContext(m MigrationIface, immutableTables map[s
 	if immutableTables != nil {
 		markFKMetadataOnly(ops, immutableTables)
 	}
-	if m.Transactional() {
-		tx, err := r.DB.Begin()
-		if err != nil {
-			return err
-		}
-		if err := r.execOps(ops, tx); err != nil {
-			tx.Rollback() //nolint:errcheck
-			return err
-		}
-		return tx.Commit()
-	}
-	return r.execOps(ops, nil)
+	return r.applyOps(ops, m.Transactional())
 }
 
 func (r *Runner) rollbackMigration(m MigrationIface) error {
 	m.Reset()
 	m.Down()
-	ops := m.GetOperations()
-	if m.Transactional() {
+	return r.applyOps(m.GetOperations(), m.Transactional())
+}
+
+// applyOps executes a migration's operations, inside a transaction when the
+// migration asks for one and the driver can actually roll DDL back. On MySQL
+// the statements run one by one and a failure reports how many were already
+// committed, since nothing before it can be undone.
+func (r *Runner) applyOps(ops []Operation, transactional bool) error {
+	if transactional && r.ddlAutoCommits {
+		if !r.warnedDDL {
+			fmt.Printf("  warning: %s commits DDL implicitly; migrations are not atomic and a failure leaves earlier statements applied\n", r.Driver)
+			r.warnedDDL = true
+		}
+		executed, err := r.execOpsCounted(ops, nil)
+		if err != nil && executed > 0 {
+			return fmt.Errorf("%w (%d earlier statement(s) of this migration were already committed and must be reverted by hand)", err, executed)
+		}
+		return err
+	}
+	if transactional {
 		tx, err := r.DB.Begin()
 		if err != nil {
 			return err
@@ -289,20 +597,39 @@ func (r *Runner) Migrate(entries []MigrationEntry) error {
 	// Collect immutable tables so FK constraints to them are suppressed
 	immutableTables := collectImmutableTables(entries)
 
+	changed, err := r.changedMigrations(entries, immutableTables, true)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		fmt.Println("  WARNING: applied migrations were edited after they ran; the database does not reflect these changes:")
+		for _, id := range changed {
+			fmt.Printf("    %s\n", id)
+		}
+		fmt.Println("  Revert the edits and add a new migration instead.")
+		if r.StrictChecksums {
+			return fmt.Errorf("%d applied migration(s) changed since they ran: %s", len(changed), strings.Join(changed, ", "))
+		}
+	}
+
 	ran := 0
 	for _, entry := range entries {
 		if _, ok := applied[entry.ID]; ok {
 			continue
 		}
+		sum, err := r.checksum(entry.Migration, immutableTables)
+		if err != nil {
+			return fmt.Errorf("migrating %s: %w", entry.ID, err)
+		}
 		fmt.Printf("  migrating: %s\n", entry.ID)
 		if err := r.runMigrationWithContext(entry.Migration, immutableTables); err != nil {
 			return fmt.Errorf("migrating %s: %w", entry.ID, err)
 		}
-		q := fmt.Sprintf( //nolint:gosec // G201: placeholders ($1/$2 or ?), not user data
-			"INSERT INTO migrations (migration, batch) VALUES (%s, %s)",
-			r.placeholder(1), r.placeholder(2),
+		q := fmt.Sprintf( //nolint:gosec // G201: placeholders ($1/$2/$3/$4 or ?), not user data
+			"INSERT INTO migrations (migration, batch, checksum, checksum_version) VALUES (%s, %s, %s, %s)",
+			r.placeholder(1), r.placeholder(2), r.placeholder(3), r.placeholder(4),
 		)
-		if _, err := r.DB.Exec(q, entry.ID, batch); err != nil {
+		if _, err := r.DB.Exec(q, entry.ID, batch, sum, checksumVersion); err != nil {
 			return fmt.Errorf("recording %s: %w", entry.ID, err)
 		}
 		fmt.Printf("  migrated:  %s\n", entry.ID)
@@ -394,12 +721,21 @@ func (r *Runner) Status(entries []MigrationEntry) ([]MigrationStatus, error) {
 	if err != nil {
 		return nil, err
 	}
+	changedIDs, err := r.changedMigrations(entries, collectImmutableTables(entries), false)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]bool{}
+	for _, id := range changedIDs {
+		changed[id] = true
+	}
 	var result []MigrationStatus
 	for _, entry := range entries {
 		s := MigrationStatus{ID: entry.ID}
 		if batch, ok := applied[entry.ID]; ok {
 			s.Applied = true
 			s.Batch = batch
+			s.Changed = changed[entry.ID]
 		}
 		result = append(result, s)
 	}
@@ -421,6 +757,135 @@ func PrintStatus(statuses []MigrationStatus) {
 			state = "Applied"
 			batch = fmt.Sprintf(" (batch %d)", s.Batch)
 		}
+		if s.Changed {
+			batch += " — CHANGED since applied"
+		}
 		fmt.Printf("  %-*s  %s%s\n", maxLen, s.ID, state, batch)
 	}
 }
+
+// StatusReport is the machine-readable form of migrate:status.
+type StatusReport struct {
+	Migrations []MigrationStatus `json:"migrations"`
+	Total      int               `json:"total"`
+	Applied    int               `json:"applied"`
+	Pending    int               `json:"pending"`
+	Changed    int               `json:"changed"`
+	LastBatch  int               `json:"last_batch"`
+}
+
+// NewStatusReport sorts statuses by ID and counts them.
+func NewStatusReport(statuses []MigrationStatus) StatusReport {
+	sorted := append([]MigrationStatus{}, statuses...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	report := StatusReport{Migrations: sorted, Total: len(sorted)}
+	for _, s := range sorted {
+		if !s.Applied {
+			report.Pending++
+			continue
+		}
+		report.Applied++
+		if s.Changed {
+			report.Changed++
+		}
+		if s.Batch > report.LastBatch {
+			report.LastBatch = s.Batch
+		}
+	}
+	return report
+}
+
+// PrintStatusJSON writes the status report as indented JSON to stdout.
+func PrintStatusJSON(statuses []MigrationStatus) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewStatusReport(statuses))
+}
+
+// MigrationSQL is the SQL one migration generates for the runner's driver.
+type MigrationSQL struct {
+	ID   string
+	Up   []string
+	Down []string
+}
+
+// RenderSQL renders every migration's Up and Down SQL without connecting to
+// the database. Foreign keys to immutable tables are rendered metadata-only,
+// as Migrate applies them.
+func (r *Runner) RenderSQL(entries []MigrationEntry) ([]MigrationSQL, error) {
+	immutableTables := collectImmutableTables(entries)
+	rendered := make([]MigrationSQL, 0, len(entries))
+	for _, entry := range entries {
+		m := entry.Migration
+		m.Reset()
+		m.Up()
+		ops := m.GetOperations()
+		markFKMetadataOnly(ops, immutableTables)
+		up, err := r.renderOps(ops)
+		if err != nil {
+			m.Reset()
+			return nil, fmt.Errorf("rendering %s up: %w", entry.ID, err)
+		}
+		m.Reset()
+		m.Down()
+		down, err := r.renderOps(m.GetOperations())
+		m.Reset()
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s down: %w", entry.ID, err)
+		}
+		rendered = append(rendered, MigrationSQL{ID: entry.ID, Up: up, Down: down})
+	}
+	return rendered, nil
+}
+
+// WriteSQLFiles writes each rendered migration to dir as a numbered pair,
+// 0001_<id>.up.sql and 0001_<id>.down.sql, numbered in migration order.
+// Existing .up.sql and .down.sql files in dir are removed first so renamed
+// or deleted migrations don't leave stale files behind. It returns the paths
+// written.
+func WriteSQLFiles(dir string, rendered []MigrationSQL) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for _, pattern := range []string{"*.up.sql", "*.down.sql"} {
+		stale, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var written []string
+	for i, m := range rendered {
+		prefix := filepath.Join(dir, fmt.Sprintf("%04d_%s", i+1, m.ID))
+		for _, file := range []struct {
+			path  string
+			stmts []string
+		}{
+			{prefix + ".up.sql", m.Up},
+			{prefix + ".down.sql", m.Down},
+		} {
+			if err := os.WriteFile(file.path, []byte(sqlFileContent(m.ID, file.stmts)), 0o644); err != nil {
+				return nil, err
+			}
+			written = append(written, file.path)
+		}
+	}
+	return written, nil
+}
+
+// sqlFileContent terminates each statement with a semicolon and separates
+// them with a blank line.
+func sqlFileContent(id string, stmts []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Generated by Pickle from migration %s. Edit the migration, not this file.\n", id)
+	for _, q := range stmts {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(strings.TrimSpace(q), ";"))
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
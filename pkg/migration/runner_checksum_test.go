@@ -0,0 +1,183 @@
+//go:build ignore
+
+package migration
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type checksumProbeMigration struct {
+	Migration
+	column string
+}
+
+func (m *checksumProbeMigration) Up() {
+	m.RawSQL("CREATE TABLE checksum_probe (id INTEGER PRIMARY KEY, " + m.column + " TEXT)")
+}
+func (m *checksumProbeMigration) Down() { m.RawSQL("DROP TABLE checksum_probe") }
+
+type checksumUsersMigration struct{ Migration }
+
+func (m *checksumUsersMigration) Up() {
+	m.CreateTable("checksum_users", func(t *Table) {
+		t.UUID("id").PrimaryKey()
+		t.String("email").Unique()
+		t.Timestamps()
+	})
+}
+func (m *checksumUsersMigration) Down() { m.DropTableIfExists("checksum_users") }
+
+func openChecksumDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "checksum.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func recordedChecksum(t *testing.T, db *sql.DB, id string) sql.NullString {
+	t.Helper()
+	var sum sql.NullString
+	if err := db.QueryRow("SELECT checksum FROM migrations WHERE migration = ?", id).Scan(&sum); err != nil {
+		t.Fatalf("reading checksum for %s: %v", id, err)
+	}
+	return sum
+}
+
+func TestMigrateRecordsChecksum(t *testing.T) {
+	db := openChecksumDB(t)
+	runner := NewRunner(db, "sqlite")
+	entries := []MigrationEntry{
+		{ID: "2026_01_01_000000_create_checksum_users", Migration: &checksumUsersMigration{}},
+		{ID: "2026_01_02_000000_create_checksum_probe", Migration: &checksumProbeMigration{column: "name"}},
+	}
+	if err := runner.Migrate(entries); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	for _, entry := range entries {
+		sum := recordedChecksum(t, db, entry.ID)
+		if !sum.Valid || len(sum.String) != 64 {
+			t.Errorf("%s checksum = %+v, want a sha256 hex digest", entry.ID, sum)
+		}
+		again, err := runner.checksum(entry.Migration, nil)
+		if err != nil || again != sum.String {
+			t.Errorf("%s checksum is not stable: recorded %s, recomputed %s (%v)", entry.ID, sum.String, again, err)
+		}
+	}
+
+	// An unchanged rerun is clean even under strict checking.
+	runner.StrictChecksums = true
+	if err := runner.Migrate(entries); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+}
+
+func TestMigrateDetectsEditedAppliedMigration(t *testing.T) {
+	db := openChecksumDB(t)
+	id := "2026_01_02_000000_create_checksum_probe"
+	if err := NewRunner(db, "sqlite").Migrate([]MigrationEntry{{ID: id, Migration: &checksumProbeMigration{column: "name"}}}); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	edited := []MigrationEntry{{ID: id, Migration: &checksumProbeMigration{column: "title"}}}
+
+	// Default: warn and carry on.
+	if err := NewRunner(db, "sqlite").Migrate(edited); err != nil {
+		t.Fatalf("non-strict Migrate should only warn, got %v", err)
+	}
+
+	strict := NewRunner(db, "sqlite")
+	strict.StrictChecksums = true
+	err := strict.Migrate(edited)
+	if err == nil || !strings.Contains(err.Error(), id) {
+		t.Fatalf("strict Migrate error = %v, want it to name %s", err, id)
+	}
+
+	statuses, err := NewRunner(db, "sqlite").Status(edited)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Applied || !statuses[0].Changed {
+		t.Errorf("Status = %+v, want applied and changed", statuses)
+	}
+}
+
+func TestMigrateAddsChecksumColumnToLegacyTable(t *testing.T) {
+	db := openChecksumDB(t)
+	id := "2026_01_02_000000_create_checksum_probe"
+	for _, q := range []string{
+		`CREATE TABLE migrations (id INTEGER PRIMARY KEY AUTOINCREMENT, migration VARCHAR(255) NOT NULL, batch INTEGER NOT NULL)`,
+		`CREATE TABLE checksum_probe (id INTEGER PRIMARY KEY, name TEXT)`,
+		`INSERT INTO migrations (migration, batch) VALUES ('` + id + `', 1)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := []MigrationEntry{{ID: id, Migration: &checksumProbeMigration{column: "name"}}}
+	runner := NewRunner(db, "sqlite")
+
+	// Status reads the legacy row without recording anything.
+	statuses, err := runner.Status(entries)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if statuses[0].Changed {
+		t.Error("a row without a checksum should not be reported as changed")
+	}
+	if sum := recordedChecksum(t, db, id); sum.Valid {
+		t.Errorf("Status backfilled checksum %q", sum.String)
+	}
+
+	if err := runner.Migrate(entries); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	want, _ := runner.checksum(entries[0].Migration, nil)
+	if sum := recordedChecksum(t, db, id); sum.String != want {
+		t.Errorf("backfilled checksum = %+v, want %s", sum, want)
+	}
+}
+
+func TestMigrateRefreshesChecksumFromOtherGeneratorVersion(t *testing.T) {
+	db := openChecksumDB(t)
+	id := "2026_01_02_000000_create_checksum_probe"
+	entries := []MigrationEntry{{ID: id, Migration: &checksumProbeMigration{column: "name"}}}
+	if err := NewRunner(db, "sqlite").Migrate(entries); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	// As if an older generator rendered different SQL for the same migration.
+	if _, err := db.Exec("UPDATE migrations SET checksum = ?, checksum_version = ? WHERE migration = ?", strings.Repeat("0", 64), checksumVersion-1, id); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(db, "sqlite")
+	statuses, err := runner.Status(entries)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if statuses[0].Changed {
+		t.Error("a checksum from another generator version should not be reported as changed")
+	}
+
+	runner.StrictChecksums = true
+	if err := runner.Migrate(entries); err != nil {
+		t.Fatalf("strict Migrate: %v", err)
+	}
+	want, _ := runner.checksum(entries[0].Migration, nil)
+	var version int
+	if err := db.QueryRow("SELECT checksum_version FROM migrations WHERE migration = ?", id).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if sum := recordedChecksum(t, db, id); sum.String != want || version != checksumVersion {
+		t.Errorf("refreshed checksum = %s (version %d), want %s (version %d)", sum.String, version, want, checksumVersion)
+	}
+}
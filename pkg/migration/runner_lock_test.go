@@ -0,0 +1,42 @@
+//go:build ignore
+
+package migration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockKeyForIsStablePerName(t *testing.T) {
+	if LockKeyFor("github.com/acme/billing") != LockKeyFor("github.com/acme/billing") {
+		t.Fatal("expected the same key for the same module path")
+	}
+	if LockKeyFor("github.com/acme/billing") == LockKeyFor("github.com/acme/shop") {
+		t.Fatal("expected different keys for different module paths")
+	}
+}
+
+func TestNewRunnerLockSettingsFromEnv(t *testing.T) {
+	t.Setenv("MIGRATION_LOCK_KEY", "42")
+	t.Setenv("MIGRATION_LOCK_TIMEOUT", "5s")
+	r := NewRunner(nil, "pgsql")
+	if r.LockKey != 42 || r.LockTimeout != 5*time.Second {
+		t.Fatalf("LockKey = %d, LockTimeout = %s", r.LockKey, r.LockTimeout)
+	}
+
+	t.Setenv("MIGRATION_LOCK_KEY", "billing")
+	t.Setenv("MIGRATION_LOCK_TIMEOUT", "soon")
+	r = NewRunner(nil, "pgsql")
+	if r.LockKey != LockKeyFor("billing") || r.LockTimeout != DefaultLockTimeout {
+		t.Fatalf("LockKey = %d, LockTimeout = %s", r.LockKey, r.LockTimeout)
+	}
+}
+
+func TestReleaseLockWithoutLockIsNoop(t *testing.T) {
+	r := NewRunner(nil, "sqlite")
+	if err := r.acquireLock(); err != nil {
+		t.Fatal(err)
+	}
+	r.releaseLock()
+	r.releaseLock()
+}
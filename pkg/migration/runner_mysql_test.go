@@ -0,0 +1,116 @@
+//go:build ignore
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var ddlDriverState = &ddlState{}
+
+func init() { sql.Register("pickle-ddl-test", ddlDriver{state: ddlDriverState}) }
+
+// ddlState records executed statements and how many transactions were begun.
+// Statements containing "fail" return an error.
+type ddlState struct {
+	sync.Mutex
+	executed []string
+	begins   int
+}
+
+func (s *ddlState) reset() {
+	s.Lock()
+	defer s.Unlock()
+	s.executed = nil
+	s.begins = 0
+}
+
+type ddlDriver struct{ state *ddlState }
+
+func (d ddlDriver) Open(string) (driver.Conn, error) { return &ddlConn{state: d.state}, nil }
+
+type ddlConn struct{ state *ddlState }
+
+func (c *ddlConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+func (c *ddlConn) Close() error { return nil }
+func (c *ddlConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+func (c *ddlConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.state.Lock()
+	c.state.begins++
+	c.state.Unlock()
+	return rawSQLTx{}, nil
+}
+func (c *ddlConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("simulated failure")
+	}
+	c.state.Lock()
+	defer c.state.Unlock()
+	c.state.executed = append(c.state.executed, query)
+	return driver.RowsAffected(0), nil
+}
+
+type threeStatementMigration struct{ Migration }
+
+func (m *threeStatementMigration) Up() {
+	m.RawSQL("CREATE TABLE first_probe (id INTEGER)")
+	m.RawSQL("CREATE TABLE second_probe (id INTEGER)")
+	m.RawSQL("CREATE TABLE fail_probe (id INTEGER)")
+}
+func (m *threeStatementMigration) Down() {}
+
+func openDDLTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	ddlDriverState.reset()
+	db, err := sql.Open("pickle-ddl-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMySQLMigrationDoesNotClaimTransaction(t *testing.T) {
+	runner := NewRunner(openDDLTestDB(t), "mysql")
+
+	err := runner.runMigration(&threeStatementMigration{})
+	if err == nil {
+		t.Fatal("expected the third statement to fail")
+	}
+	if !strings.Contains(err.Error(), "2 earlier statement(s)") {
+		t.Fatalf("error should report the committed statements, got %v", err)
+	}
+
+	ddlDriverState.Lock()
+	defer ddlDriverState.Unlock()
+	if ddlDriverState.begins != 0 {
+		t.Fatalf("mysql migration opened %d transaction(s); DDL would commit them implicitly", ddlDriverState.begins)
+	}
+	if len(ddlDriverState.executed) != 2 {
+		t.Fatalf("executed = %v, want the two statements before the failure", ddlDriverState.executed)
+	}
+}
+
+func TestPostgresMigrationRunsInTransaction(t *testing.T) {
+	runner := NewRunner(openDDLTestDB(t), "pgsql")
+
+	if err := runner.runMigration(&threeStatementMigration{}); err == nil {
+		t.Fatal("expected the third statement to fail")
+	}
+
+	ddlDriverState.Lock()
+	defer ddlDriverState.Unlock()
+	if ddlDriverState.begins != 1 {
+		t.Fatalf("begins = %d, want the migration wrapped in one transaction", ddlDriverState.begins)
+	}
+}
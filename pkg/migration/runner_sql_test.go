@@ -0,0 +1,130 @@
+//go:build ignore
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type sqlFileUsersMigration struct{ Migration }
+
+func (m *sqlFileUsersMigration) Up() {
+	m.CreateTable("sql_file_users", func(t *Table) {
+		t.UUID("id").PrimaryKey()
+		t.String("email").Unique()
+	})
+}
+func (m *sqlFileUsersMigration) Down() { m.DropTableIfExists("sql_file_users") }
+
+type sqlFileRawMigration struct{ Migration }
+
+func (m *sqlFileRawMigration) Up() {
+	m.RawSQL("CREATE VIEW sql_file_emails AS SELECT email FROM sql_file_users;")
+}
+func (m *sqlFileRawMigration) Down() { m.RawSQL("DROP VIEW sql_file_emails") }
+
+func TestRenderSQLWithoutDatabase(t *testing.T) {
+	entries := []MigrationEntry{
+		{ID: "2026_01_01_000000_create_sql_file_users", Migration: &sqlFileUsersMigration{}},
+		{ID: "2026_01_02_000000_add_sql_file_emails", Migration: &sqlFileRawMigration{}},
+	}
+	rendered, err := NewRunner(nil, "pgsql").RenderSQL(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rendered) != 2 {
+		t.Fatalf("rendered %d migrations, want 2", len(rendered))
+	}
+	if len(rendered[0].Up) == 0 || !strings.HasPrefix(rendered[0].Up[0], `CREATE TABLE "sql_file_users"`) {
+		t.Errorf("up = %q", rendered[0].Up)
+	}
+	if len(rendered[0].Down) != 1 || !strings.Contains(rendered[0].Down[0], "DROP TABLE IF EXISTS") {
+		t.Errorf("down = %q", rendered[0].Down)
+	}
+
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "0009_removed.up.sql")
+	if err := os.WriteFile(stale, []byte("-- stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	written, err := WriteSQLFiles(dir, rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 4 || filepath.Base(written[2]) != "0002_2026_01_02_000000_add_sql_file_emails.up.sql" {
+		t.Errorf("written = %v", written)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale SQL file was not removed")
+	}
+	data, err := os.ReadFile(written[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "\nCREATE VIEW sql_file_emails AS SELECT email FROM sql_file_users;\n") {
+		t.Errorf("up file = %q", data)
+	}
+}
+
+func TestRenderSQLMatchesChecksum(t *testing.T) {
+	r := NewRunner(nil, "sqlite")
+	entry := MigrationEntry{ID: "users", Migration: &sqlFileUsersMigration{}}
+	rendered, err := r.RenderSQL([]MigrationEntry{entry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.New()
+	for _, q := range rendered[0].Up {
+		h.Write([]byte(q + "\n"))
+	}
+	sum, err := r.checksum(entry.Migration, map[string]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != hex.EncodeToString(h.Sum(nil)) {
+		t.Error("rendered Up SQL differs from the SQL the checksum covers")
+	}
+}
+
+type changeColumnMigration struct{ Migration }
+
+func (m *changeColumnMigration) Up() {
+	m.ChangeColumn("users", func(t *Table) {
+		t.String("bio", 1000).Nullable().Comment("Shown on the profile")
+		t.Decimal("balance", 12, 2).Default("0").Using(`"balance" / 100.0`)
+	})
+}
+func (m *changeColumnMigration) Down() {}
+
+func TestRenderChangeColumn(t *testing.T) {
+	entry := MigrationEntry{ID: "change_users", Migration: &changeColumnMigration{}}
+	for driver, want := range map[string][]string{
+		"pgsql": {
+			`ALTER TABLE "users" ALTER COLUMN "bio" TYPE VARCHAR(1000) USING "bio"::VARCHAR(1000), ALTER COLUMN "bio" DROP NOT NULL, ALTER COLUMN "bio" DROP DEFAULT`,
+			`COMMENT ON COLUMN "users"."bio" IS 'Shown on the profile'`,
+			`ALTER TABLE "users" ALTER COLUMN "balance" TYPE NUMERIC(12, 2) USING "balance" / 100.0, ALTER COLUMN "balance" SET NOT NULL, ALTER COLUMN "balance" SET DEFAULT '0'`,
+		},
+		"mysql": {
+			"ALTER TABLE `users` MODIFY COLUMN `bio` VARCHAR(1000) COMMENT 'Shown on the profile'",
+			"ALTER TABLE `users` MODIFY COLUMN `balance` DECIMAL(12, 2) NOT NULL DEFAULT '0'",
+		},
+	} {
+		rendered, err := NewRunner(nil, driver).RenderSQL([]MigrationEntry{entry})
+		if err != nil {
+			t.Fatalf("%s: %v", driver, err)
+		}
+		if strings.Join(rendered[0].Up, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s up =\n%s\nwant\n%s", driver, strings.Join(rendered[0].Up, "\n"), strings.Join(want, "\n"))
+		}
+	}
+
+	_, err := NewRunner(nil, "sqlite").RenderSQL([]MigrationEntry{entry})
+	if err == nil || !strings.Contains(err.Error(), "cannot alter a column") {
+		t.Errorf("sqlite err = %v, want an unsupported-operation error", err)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build ignore
+
+package migration
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewStatusReportSortsAndCounts(t *testing.T) {
+	report := NewStatusReport([]MigrationStatus{
+		{ID: "2026_03_01_000000_add_posts"},
+		{ID: "2026_01_01_000000_create_users", Batch: 1, Applied: true},
+		{ID: "2026_02_01_000000_create_teams", Batch: 2, Applied: true, Changed: true},
+	})
+
+	want := []string{"2026_01_01_000000_create_users", "2026_02_01_000000_create_teams", "2026_03_01_000000_add_posts"}
+	for i, id := range want {
+		if report.Migrations[i].ID != id {
+			t.Errorf("Migrations[%d] = %s, want %s", i, report.Migrations[i].ID, id)
+		}
+	}
+	if report.Total != 3 || report.Applied != 2 || report.Pending != 1 || report.Changed != 1 || report.LastBatch != 2 {
+		t.Errorf("report counts = %+v", report)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	first := decoded["migrations"].([]any)[0].(map[string]any)
+	if first["id"] != want[0] || first["batch"] != float64(1) || first["applied"] != true {
+		t.Errorf("first migration JSON = %v", first)
+	}
+	if decoded["pending"] != float64(1) {
+		t.Errorf("pending = %v, want 1", decoded["pending"])
+	}
+}
+
+func TestNewStatusReportEmpty(t *testing.T) {
+	data, err := json.Marshal(NewStatusReport(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"migrations":[],"total":0,"applied":0,"pending":0,"changed":0,"last_batch":0}` {
+		t.Errorf("empty report = %s", data)
+	}
+}
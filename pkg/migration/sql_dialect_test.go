@@ -0,0 +1,217 @@
+//go:build ignore
+
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+// dialectUsersTable builds the users table every driver is checked against.
+func dialectUsersTable() *Table {
+	var m Migration
+	m.CreateTable("users", func(t *Table) {
+		t.UUID("id").PrimaryKey()
+		t.String("name").NotNull()
+		t.String("email", 320).NotNull().Unique()
+		t.Boolean("is_admin").NotNull().Default(false)
+		t.UUID("team_id").Nullable().ForeignKey("teams", "id").OnDelete("CASCADE")
+		t.Timestamps()
+	})
+	return m.GetOperations()[0].TableDef
+}
+
+func TestCreateTableUsersAcrossDrivers(t *testing.T) {
+	cases := []struct {
+		driver string
+		gen    SQLGenerator
+		want   string
+	}{
+		{"pgsql", &postgresGenerator{}, `CREATE TABLE "users" (
+	"id" UUID PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"email" VARCHAR(320) NOT NULL UNIQUE,
+	"is_admin" BOOLEAN NOT NULL DEFAULT false,
+	"team_id" UUID REFERENCES "teams"("id") ON DELETE CASCADE,
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	"updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`},
+		{"mysql", &mysqlGenerator{}, "CREATE TABLE `users` (\n" +
+			"\t`id` CHAR(36) PRIMARY KEY,\n" +
+			"\t`name` VARCHAR(255) NOT NULL,\n" +
+			"\t`email` VARCHAR(320) NOT NULL UNIQUE,\n" +
+			"\t`is_admin` BOOLEAN NOT NULL DEFAULT FALSE,\n" +
+			"\t`team_id` CHAR(36) REFERENCES `teams`(`id`) ON DELETE CASCADE,\n" +
+			"\t`created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+			"\t`updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP\n" +
+			")"},
+		{"sqlite", &sqliteGenerator{}, `CREATE TABLE "users" (
+	"id" TEXT PRIMARY KEY,
+	"name" TEXT NOT NULL,
+	"email" TEXT NOT NULL UNIQUE,
+	"is_admin" INTEGER NOT NULL DEFAULT 0,
+	"team_id" TEXT REFERENCES "teams"("id") ON DELETE CASCADE,
+	"created_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"updated_at" TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.driver, func(t *testing.T) {
+			if got := tc.gen.CreateTable(dialectUsersTable()); got != tc.want {
+				t.Fatalf("CREATE TABLE mismatch\ngot:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntegerPrimaryKeyAutoIncrements(t *testing.T) {
+	var m Migration
+	m.CreateTable("counters", func(t *Table) {
+		t.BigInteger("id").PrimaryKey()
+		t.Text("label").NotNull().Default("it's")
+	})
+	table := m.GetOperations()[0].TableDef
+
+	cases := []struct {
+		gen  SQLGenerator
+		want []string
+	}{
+		{&postgresGenerator{}, []string{`"id" BIGINT PRIMARY KEY,`}},
+		{&mysqlGenerator{}, []string{"`id` BIGINT PRIMARY KEY AUTO_INCREMENT", "`label` TEXT NOT NULL DEFAULT ('it''s')"}},
+		{&sqliteGenerator{}, []string{`"id" INTEGER PRIMARY KEY AUTOINCREMENT`, `"label" TEXT NOT NULL DEFAULT 'it''s'`}},
+	}
+	for _, tc := range cases {
+		sql := tc.gen.CreateTable(table)
+		for _, want := range tc.want {
+			if !strings.Contains(sql, want) {
+				t.Errorf("%T: missing %q in\n%s", tc.gen, want, sql)
+			}
+		}
+	}
+}
+
+func TestAlterStatementsAcrossDrivers(t *testing.T) {
+	col := &Column{Name: "bio", Type: Text, IsNullable: true}
+	cases := []struct {
+		gen                      SQLGenerator
+		add, drop, rename, retbl string
+	}{
+		{&postgresGenerator{},
+			`ALTER TABLE "users" ADD COLUMN "bio" TEXT`,
+			`ALTER TABLE "users" DROP COLUMN "bio"`,
+			`ALTER TABLE "users" RENAME COLUMN "bio" TO "about"`,
+			`ALTER TABLE "users" RENAME TO "people"`},
+		{&mysqlGenerator{},
+			"ALTER TABLE `users` ADD COLUMN `bio` TEXT",
+			"ALTER TABLE `users` DROP COLUMN `bio`",
+			"ALTER TABLE `users` RENAME COLUMN `bio` TO `about`",
+			"RENAME TABLE `users` TO `people`"},
+		{&sqliteGenerator{},
+			`ALTER TABLE "users" ADD COLUMN "bio" TEXT`,
+			`ALTER TABLE "users" DROP COLUMN "bio"`,
+			`ALTER TABLE "users" RENAME COLUMN "bio" TO "about"`,
+			`ALTER TABLE "users" RENAME TO "people"`},
+	}
+	for _, tc := range cases {
+		if got := tc.gen.AddColumn("users", col); got != tc.add {
+			t.Errorf("%T AddColumn = %q, want %q", tc.gen, got, tc.add)
+		}
+		if got := tc.gen.DropColumn("users", "bio"); got != tc.drop {
+			t.Errorf("%T DropColumn = %q, want %q", tc.gen, got, tc.drop)
+		}
+		if got := tc.gen.RenameColumn("users", "bio", "about"); got != tc.rename {
+			t.Errorf("%T RenameColumn = %q, want %q", tc.gen, got, tc.rename)
+		}
+		if got := tc.gen.RenameTable("users", "people"); got != tc.retbl {
+			t.Errorf("%T RenameTable = %q, want %q", tc.gen, got, tc.retbl)
+		}
+	}
+}
+
+func TestCheckAndCommentAcrossDrivers(t *testing.T) {
+	var m Migration
+	m.CreateTable("orders", func(t *Table) {
+		t.Integer("id").PrimaryKey()
+		t.Integer("quantity").NotNull().Check("quantity > 0").Comment("Units ordered; the customer's count")
+	})
+	runner := &Runner{Generator: &postgresGenerator{}, Driver: "pgsql"}
+	stmts, err := runner.opsToSQL(m.GetOperations()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 2 {
+		t.Fatalf("postgres statements = %q, want CREATE TABLE plus COMMENT ON", stmts)
+	}
+	if !strings.Contains(stmts[0], `"quantity" INTEGER NOT NULL CHECK (quantity > 0)`) {
+		t.Errorf("postgres CREATE TABLE missing CHECK:\n%s", stmts[0])
+	}
+	if want := `COMMENT ON COLUMN "orders"."quantity" IS 'Units ordered; the customer''s count'`; stmts[1] != want {
+		t.Errorf("postgres comment = %q, want %q", stmts[1], want)
+	}
+
+	table := m.GetOperations()[0].TableDef
+	if got := (&mysqlGenerator{}).CreateTable(table); !strings.Contains(got, "CHECK (quantity > 0) COMMENT 'Units ordered; the customer''s count'") {
+		t.Errorf("mysql CREATE TABLE missing CHECK/COMMENT:\n%s", got)
+	}
+	sqlite := &Runner{Generator: &sqliteGenerator{}, Driver: "sqlite"}
+	stmts, err = sqlite.opsToSQL(m.GetOperations()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 1 || !strings.Contains(stmts[0], "CHECK (quantity > 0)") || strings.Contains(stmts[0], "Units ordered") {
+		t.Errorf("sqlite statements = %q, want CHECK and no comment", stmts)
+	}
+}
+
+func TestLiteralAndRawDefaultsAcrossDrivers(t *testing.T) {
+	var m Migration
+	m.CreateTable("posts", func(t *Table) {
+		t.UUID("id").PrimaryKey().DefaultRaw("gen_random_uuid()")
+		t.UUID("legacy_id").Default("gen_random_uuid()")
+		t.String("status").Default("draft (beta)")
+		t.String("title").Default("it's")
+	})
+	table := m.GetOperations()[0].TableDef
+
+	for _, tc := range []struct {
+		gen  interface{ CreateTable(*Table) string }
+		want []string
+	}{
+		{&postgresGenerator{}, []string{`"id" UUID PRIMARY KEY DEFAULT gen_random_uuid()`, `"legacy_id" UUID NOT NULL DEFAULT gen_random_uuid()`, `DEFAULT 'draft (beta)'`, `DEFAULT 'it''s'`}},
+		{&mysqlGenerator{}, []string{"DEFAULT (gen_random_uuid())", "DEFAULT 'draft (beta)'", "DEFAULT 'it''s'"}},
+		{&sqliteGenerator{}, []string{"DEFAULT (gen_random_uuid())", "DEFAULT 'draft (beta)'", "DEFAULT 'it''s'"}},
+	} {
+		got := tc.gen.CreateTable(table)
+		for _, want := range tc.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%T missing %q:\n%s", tc.gen, want, got)
+			}
+		}
+	}
+}
+
+func TestTimestampPrecisionAndZoneAcrossDrivers(t *testing.T) {
+	var m Migration
+	m.CreateTable("events", func(t *Table) {
+		t.Timestamp("logged_at")
+		t.TimestampTz("received_at", 6).NotNull().DefaultRaw("NOW()")
+		t.TimestampWithoutTimeZone("local_at", 0)
+		t.TimestampWithoutTimeZone("wall_at")
+	})
+	table := m.GetOperations()[0].TableDef
+
+	for _, tc := range []struct {
+		gen  interface{ CreateTable(*Table) string }
+		want []string
+	}{
+		{&postgresGenerator{}, []string{`"logged_at" TIMESTAMPTZ NOT NULL`, `"received_at" TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()`, `"local_at" TIMESTAMP(0) NOT NULL`, `"wall_at" TIMESTAMP NOT NULL`}},
+		{&mysqlGenerator{}, []string{"`logged_at` DATETIME NOT NULL", "`received_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)", "`local_at` DATETIME(0) NOT NULL"}},
+	} {
+		got := tc.gen.CreateTable(table)
+		for _, want := range tc.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%T missing %q:\n%s", tc.gen, want, got)
+			}
+		}
+	}
+}
@@ -56,5 +56,6 @@ func encryptedStorageColumn(src *Column, name string, nullable bool) *Column {
 	col.ForeignKeyColumn = ""
 	col.HasDefault = false
 	col.DefaultValue = nil
+	col.DefaultIsRaw = false
 	return &col
 }
@@ -0,0 +1,47 @@
+//go:build ignore
+
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddIndexNamesFitEachDialect(t *testing.T) {
+	idx := &Index{
+		Table:   "organization_membership_invitations",
+		Columns: []string{"organization_id", "invited_by_user_id", "accepted_at"},
+		Unique:  true,
+	}
+	cases := []struct {
+		gen    SQLGenerator
+		limit  int
+		prefix string
+	}{
+		{&postgresGenerator{}, PostgresMaxIdentifier, `CREATE UNIQUE INDEX IF NOT EXISTS "`},
+		{&mysqlGenerator{}, MySQLMaxIdentifier, "CREATE UNIQUE INDEX `"},
+		{&sqliteGenerator{}, 0, `CREATE UNIQUE INDEX IF NOT EXISTS "`},
+	}
+	for _, tc := range cases {
+		sql := tc.gen.AddIndex(idx)
+		if !strings.HasPrefix(sql, tc.prefix) {
+			t.Fatalf("%T: unexpected SQL %q", tc.gen, sql)
+		}
+		want := idx.NameWithin(tc.limit)
+		if !strings.Contains(sql, want) {
+			t.Errorf("%T: SQL %q missing index name %q", tc.gen, sql, want)
+		}
+		if tc.limit > 0 && len(want) > tc.limit {
+			t.Errorf("%T: name %q exceeds %d bytes", tc.gen, want, tc.limit)
+		}
+	}
+}
+
+func TestAddIndexUsesExplicitName(t *testing.T) {
+	var m Migration
+	m.AddIndexNamed("invites_by_org", "organization_membership_invitations", "organization_id")
+	sql := (&postgresGenerator{}).AddIndex(m.GetOperations()[0].Index)
+	if sql != `CREATE INDEX IF NOT EXISTS "invites_by_org" ON "organization_membership_invitations" ("organization_id")` {
+		t.Errorf("unexpected SQL %q", sql)
+	}
+}
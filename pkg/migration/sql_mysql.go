@@ -55,80 +55,157 @@ func (g *mysqlGenerator) columnDef(col *Column, suppressInlinePK bool) string {
 	var b strings.Builder
 	b.WriteString(mysqlQI(col.Name))
 	b.WriteByte(' ')
+	b.WriteString(mysqlColumnType(col))
+	if col.IsPrimaryKey && !suppressInlinePK {
+		b.WriteString(" PRIMARY KEY")
+		if isAutoIncrementPK(col) {
+			b.WriteString(" AUTO_INCREMENT")
+		}
+	}
+	if !col.IsNullable && !(col.IsPrimaryKey && !suppressInlinePK) {
+		b.WriteString(" NOT NULL")
+	}
+	if col.IsUnique {
+		b.WriteString(" UNIQUE")
+	}
+	if col.HasDefault {
+		b.WriteString(" DEFAULT " + mysqlDefault(col))
+	}
+	if col.ForeignKeyTable != "" && !col.FKMetadataOnly {
+		b.WriteString(" REFERENCES " + mysqlQI(col.ForeignKeyTable) + "(" + mysqlQI(col.ForeignKeyColumn) + ")")
+		if col.OnDeleteAction != "" {
+			b.WriteString(" ON DELETE " + col.OnDeleteAction)
+		}
+	}
+	if col.CheckExpr != "" {
+		b.WriteString(" CHECK (" + col.CheckExpr + ")")
+	}
+	if col.CommentText != "" {
+		b.WriteString(" COMMENT '" + strings.ReplaceAll(col.CommentText, "'", "''") + "'")
+	}
+	return b.String()
+}
+
+func mysqlColumnType(col *Column) string {
 	switch col.Type {
 	case UUID:
-		b.WriteString("CHAR(36)")
+		return "CHAR(36)"
 	case String:
 		if col.Length > 0 {
-			fmt.Fprintf(&b, "VARCHAR(%d)", col.Length)
-		} else {
-			b.WriteString("VARCHAR(255)")
+			return fmt.Sprintf("VARCHAR(%d)", col.Length)
 		}
+		return "VARCHAR(255)"
 	case Text:
-		b.WriteString("TEXT")
+		return "TEXT"
 	case Integer:
-		b.WriteString("INT")
+		return "INT"
 	case BigInteger:
-		b.WriteString("BIGINT")
+		return "BIGINT"
 	case Decimal:
-		fmt.Fprintf(&b, "DECIMAL(%d, %d)", col.Precision, col.Scale)
+		if col.Precision > 0 {
+			return fmt.Sprintf("DECIMAL(%d, %d)", col.Precision, col.Scale)
+		}
+		return "DECIMAL(65, 30)"
 	case Boolean:
-		b.WriteString("BOOLEAN")
+		return "BOOLEAN"
 	case Timestamp:
-		b.WriteString("DATETIME")
+		if col.HasPrecision {
+			return fmt.Sprintf("DATETIME(%d)", col.Precision)
+		}
+		return "DATETIME"
 	case JSONB:
-		b.WriteString("JSON")
+		return "JSON"
 	case Date:
-		b.WriteString("DATE")
+		return "DATE"
 	case Time:
-		b.WriteString("TIME")
+		return "TIME"
 	case Binary:
-		b.WriteString("BLOB")
+		return "BLOB"
 	case Float:
-		b.WriteString("FLOAT")
+		return "FLOAT"
 	case Double:
-		b.WriteString("DOUBLE")
-	default:
-		b.WriteString("TEXT")
+		return "DOUBLE"
 	}
-	if col.IsPrimaryKey && !suppressInlinePK {
-		b.WriteString(" PRIMARY KEY")
-	}
-	if !col.IsNullable && !(col.IsPrimaryKey && !suppressInlinePK) {
-		b.WriteString(" NOT NULL")
-	}
-	if col.IsUnique {
-		b.WriteString(" UNIQUE")
-	}
-	if col.ForeignKeyTable != "" && !col.FKMetadataOnly {
-		b.WriteString(" REFERENCES " + mysqlQI(col.ForeignKeyTable) + "(" + mysqlQI(col.ForeignKeyColumn) + ")")
-		if col.OnDeleteAction != "" {
-			b.WriteString(" ON DELETE " + col.OnDeleteAction)
+	return "TEXT"
+}
+
+// isAutoIncrementPK reports whether col is a lone integer primary key with no
+// explicit default, which MySQL and SQLite number automatically. Postgres
+// keeps a plain INTEGER so seeded ids never drift from a SERIAL sequence.
+func isAutoIncrementPK(col *Column) bool {
+	return col.IsPrimaryKey && !col.HasDefault && (col.Type == Integer || col.Type == BigInteger)
+}
+
+// mysqlDefault renders a DEFAULT clause value. NOW() becomes
+// CURRENT_TIMESTAMP, other expressions are wrapped as expression defaults,
+// and TEXT/JSON/BLOB literals are wrapped too since MySQL only accepts
+// expression defaults on those types.
+func mysqlDefault(col *Column) string {
+	switch v := col.DefaultValue.(type) {
+	case string:
+		if strings.EqualFold(v, "NOW()") || strings.EqualFold(v, "CURRENT_TIMESTAMP") {
+			// MySQL requires the default's precision to match DATETIME(n).
+			if col.Type == Timestamp && col.HasPrecision && col.Precision > 0 {
+				return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", col.Precision)
+			}
+			return "CURRENT_TIMESTAMP"
+		}
+		if col.DefaultIsExpression() {
+			return "(" + v + ")"
+		}
+		literal := "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		switch col.Type {
+		case Text, JSONB, Binary:
+			return "(" + literal + ")"
+		}
+		return literal
+	case bool:
+		if v {
+			return "TRUE"
 		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(v)
 	}
-	return b.String()
 }
 
 func (g *mysqlGenerator) DropTableIfExists(name string) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS `%s`", name)
+	return "DROP TABLE IF EXISTS " + mysqlQI(name)
 }
 
 func (g *mysqlGenerator) AddColumn(table string, col *Column) string {
-	panic("mysql migration generator not yet implemented")
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", mysqlQI(table), g.columnDef(col, false))
+}
+
+// ChangeColumn redefines the column with MODIFY COLUMN, which replaces the
+// whole definition, comment included.
+func (g *mysqlGenerator) ChangeColumn(table string, col *Column) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", mysqlQI(table), g.columnDef(col, false))
 }
 
 func (g *mysqlGenerator) DropColumn(table, column string) string {
-	return fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`", table, column)
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", mysqlQI(table), mysqlQI(column))
 }
 
 func (g *mysqlGenerator) RenameColumn(table, oldName, newName string) string {
-	return fmt.Sprintf("ALTER TABLE `%s` RENAME COLUMN `%s` TO `%s`", table, oldName, newName)
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", mysqlQI(table), mysqlQI(oldName), mysqlQI(newName))
 }
 
 func (g *mysqlGenerator) AddIndex(idx *Index) string {
-	panic("mysql migration generator not yet implemented")
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	var quotedCols []string
+	for _, c := range idx.Columns {
+		quotedCols = append(quotedCols, mysqlQI(c))
+	}
+	return fmt.Sprintf(
+		"CREATE %sINDEX %s ON %s (%s)",
+		unique, mysqlQI(idx.NameWithin(MySQLMaxIdentifier)), mysqlQI(idx.Table), strings.Join(quotedCols, ", "),
+	)
 }
 
 func (g *mysqlGenerator) RenameTable(oldName, newName string) string {
-	return fmt.Sprintf("RENAME TABLE `%s` TO `%s`", oldName, newName)
+	return fmt.Sprintf("RENAME TABLE %s TO %s", mysqlQI(oldName), mysqlQI(newName))
 }
@@ -83,17 +83,7 @@ func (g *postgresGenerator) columnDefWithPK(col *Column, suppressInlinePK bool)
 		b.WriteString(" UNIQUE")
 	}
 	if col.HasDefault {
-		switch v := col.DefaultValue.(type) {
-		case string:
-			// Function calls (contain parens) pass through unquoted; string literals are quoted
-			if strings.Contains(v, "(") {
-				b.WriteString(fmt.Sprintf(" DEFAULT %s", v))
-			} else {
-				b.WriteString(fmt.Sprintf(" DEFAULT '%s'", v))
-			}
-		default:
-			b.WriteString(fmt.Sprintf(" DEFAULT %v", v))
-		}
+		b.WriteString(" DEFAULT " + postgresDefault(col))
 	}
 	if col.ForeignKeyTable != "" && !col.FKMetadataOnly {
 		b.WriteString(fmt.Sprintf(" REFERENCES %s(%s)", qi(col.ForeignKeyTable), qi(col.ForeignKeyColumn)))
@@ -102,9 +92,32 @@ func (g *postgresGenerator) columnDefWithPK(col *Column, suppressInlinePK bool)
 			b.WriteString(col.OnDeleteAction)
 		}
 	}
+	if col.CheckExpr != "" {
+		b.WriteString(" CHECK (" + col.CheckExpr + ")")
+	}
 	return b.String()
 }
 
+// postgresDefault renders a column's default value. Expressions pass
+// through unquoted; string literals are quoted.
+func postgresDefault(col *Column) string {
+	switch v := col.DefaultValue.(type) {
+	case string:
+		if col.DefaultIsExpression() {
+			return v
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// CommentOnColumn records a column comment. Postgres has no inline column
+// comment syntax, so it is a separate statement run after the DDL.
+func (g *postgresGenerator) CommentOnColumn(table string, col *Column) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s'", qi(table), qi(col.Name), strings.ReplaceAll(col.CommentText, "'", "''"))
+}
+
 func (g *postgresGenerator) columnType(col *Column) string {
 	switch col.Type {
 	case UUID:
@@ -128,7 +141,14 @@ func (g *postgresGenerator) columnType(col *Column) string {
 	case Boolean:
 		return "BOOLEAN"
 	case Timestamp:
-		return "TIMESTAMPTZ"
+		typ := "TIMESTAMPTZ"
+		if col.WithoutTimeZone {
+			typ = "TIMESTAMP"
+		}
+		if col.HasPrecision {
+			typ += fmt.Sprintf("(%d)", col.Precision)
+		}
+		return typ
 	case JSONB:
 		return "JSONB"
 	case Date:
@@ -149,6 +169,30 @@ func (g *postgresGenerator) AddColumn(table string, col *Column) string {
 	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", qi(table), g.columnDef(col))
 }
 
+// ChangeColumn sets the column's type, nullability and default in one
+// statement. Existing values are cast to the new type unless the column
+// sets its own USING expression.
+func (g *postgresGenerator) ChangeColumn(table string, col *Column) string {
+	typ := g.columnType(col)
+	using := col.UsingExpr
+	if using == "" {
+		using = qi(col.Name) + "::" + typ
+	}
+	alter := "ALTER COLUMN " + qi(col.Name)
+	actions := []string{alter + " TYPE " + typ + " USING " + using}
+	if col.IsNullable {
+		actions = append(actions, alter+" DROP NOT NULL")
+	} else {
+		actions = append(actions, alter+" SET NOT NULL")
+	}
+	if col.HasDefault {
+		actions = append(actions, alter+" SET DEFAULT "+postgresDefault(col))
+	} else {
+		actions = append(actions, alter+" DROP DEFAULT")
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s", qi(table), strings.Join(actions, ", "))
+}
+
 func (g *postgresGenerator) DropColumn(table, column string) string {
 	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", qi(table), qi(column))
 }
@@ -162,7 +206,7 @@ func (g *postgresGenerator) AddIndex(idx *Index) string {
 	if idx.Unique {
 		unique = "UNIQUE "
 	}
-	idxName := fmt.Sprintf("%s_%s_idx", idx.Table, strings.Join(idx.Columns, "_"))
+	idxName := idx.NameWithin(PostgresMaxIdentifier)
 	var quotedCols []string
 	for _, c := range idx.Columns {
 		quotedCols = append(quotedCols, qi(c))
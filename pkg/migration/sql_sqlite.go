@@ -67,6 +67,11 @@ func sqliteColumnDef(col *Column, suppressInlinePK bool) string {
 	}
 	if col.IsPrimaryKey && !suppressInlinePK {
 		b.WriteString(" PRIMARY KEY")
+		// Only INTEGER PRIMARY KEY aliases the rowid, so BigInteger ids
+		// are declared INTEGER above to get the same numbering.
+		if isAutoIncrementPK(col) {
+			b.WriteString(" AUTOINCREMENT")
+		}
 	}
 	if !col.IsNullable && !(col.IsPrimaryKey && !suppressInlinePK) {
 		b.WriteString(" NOT NULL")
@@ -74,35 +79,79 @@ func sqliteColumnDef(col *Column, suppressInlinePK bool) string {
 	if col.IsUnique {
 		b.WriteString(" UNIQUE")
 	}
+	if col.HasDefault {
+		b.WriteString(" DEFAULT " + sqliteDefault(col))
+	}
 	if col.ForeignKeyTable != "" && !col.FKMetadataOnly {
 		b.WriteString(" REFERENCES " + sqliteQI(col.ForeignKeyTable) + "(" + sqliteQI(col.ForeignKeyColumn) + ")")
 		if col.OnDeleteAction != "" {
 			b.WriteString(" ON DELETE " + col.OnDeleteAction)
 		}
 	}
+	if col.CheckExpr != "" {
+		b.WriteString(" CHECK (" + col.CheckExpr + ")")
+	}
 	return b.String()
 }
 
+// sqliteDefault renders a DEFAULT clause value. NOW() becomes
+// CURRENT_TIMESTAMP and other expressions are parenthesised, which SQLite
+// requires for expression defaults. Booleans are stored as 1 and 0.
+func sqliteDefault(col *Column) string {
+	switch v := col.DefaultValue.(type) {
+	case string:
+		if strings.EqualFold(v, "NOW()") || strings.EqualFold(v, "CURRENT_TIMESTAMP") {
+			return "CURRENT_TIMESTAMP"
+		}
+		if col.DefaultIsExpression() {
+			return "(" + v + ")"
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func (g *sqliteGenerator) DropTableIfExists(name string) string {
-	return fmt.Sprintf(`DROP TABLE IF EXISTS "%s"`, name)
+	return "DROP TABLE IF EXISTS " + sqliteQI(name)
 }
 
+// AddColumn cannot add PRIMARY KEY or UNIQUE columns in SQLite; add those
+// through a new table or a separate unique index.
 func (g *sqliteGenerator) AddColumn(table string, col *Column) string {
-	panic("sqlite migration generator not yet implemented")
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", sqliteQI(table), sqliteColumnDef(col, false))
 }
 
+// DropColumn needs SQLite 3.35 or newer.
 func (g *sqliteGenerator) DropColumn(table, column string) string {
-	panic("sqlite: DROP COLUMN requires recreating the table")
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", sqliteQI(table), sqliteQI(column))
 }
 
 func (g *sqliteGenerator) RenameColumn(table, oldName, newName string) string {
-	return fmt.Sprintf(`ALTER TABLE "%s" RENAME COLUMN "%s" TO "%s"`, table, oldName, newName)
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", sqliteQI(table), sqliteQI(oldName), sqliteQI(newName))
 }
 
 func (g *sqliteGenerator) AddIndex(idx *Index) string {
-	panic("sqlite migration generator not yet implemented")
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	var quotedCols []string
+	for _, c := range idx.Columns {
+		quotedCols = append(quotedCols, sqliteQI(c))
+	}
+	// SQLite has no identifier limit; derived names stay unshortened.
+	return fmt.Sprintf(
+		"CREATE %sINDEX IF NOT EXISTS %s ON %s (%s)",
+		unique, sqliteQI(idx.NameWithin(0)), sqliteQI(idx.Table), strings.Join(quotedCols, ", "),
+	)
 }
 
 func (g *sqliteGenerator) RenameTable(oldName, newName string) string {
-	return fmt.Sprintf(`ALTER TABLE "%s" RENAME TO "%s"`, oldName, newName)
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", sqliteQI(oldName), sqliteQI(newName))
 }
@@ -173,3 +173,89 @@ func ColumnImport(col *schema.Column) string {
 		return ""
 	}
 }
+
+// EnumValues returns the values a String or Text column is limited to by a
+// check constraint of the form `column IN ('a', 'b')`, in declaration order.
+// It returns nil for any other column or constraint.
+func EnumValues(col *schema.Column) []string {
+	if col.Type != schema.String && col.Type != schema.Text {
+		return nil
+	}
+	expr := strings.TrimSpace(col.CheckExpr)
+	for strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	rest, ok := strings.CutPrefix(expr, col.Name)
+	if !ok {
+		rest, ok = strings.CutPrefix(expr, `"`+col.Name+`"`)
+	}
+	if !ok || rest == "" || rest[0] != ' ' {
+		return nil
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) < 2 || !strings.EqualFold(rest[:2], "IN") {
+		return nil
+	}
+	rest = strings.TrimSpace(rest[2:])
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return nil
+	}
+	list := rest[1 : len(rest)-1]
+
+	var values []string
+	for {
+		list = strings.TrimSpace(list)
+		if !strings.HasPrefix(list, "'") {
+			return nil
+		}
+		// Scan the quoted value; '' is an escaped quote.
+		var value strings.Builder
+		i := 1
+		for ; i < len(list); i++ {
+			if list[i] != '\'' {
+				value.WriteByte(list[i])
+				continue
+			}
+			if i+1 < len(list) && list[i+1] == '\'' {
+				value.WriteByte('\'')
+				i++
+				continue
+			}
+			break
+		}
+		if i >= len(list) {
+			return nil
+		}
+		values = append(values, value.String())
+		list = strings.TrimSpace(list[i+1:])
+		if list == "" {
+			return values
+		}
+		if list[0] != ',' {
+			return nil
+		}
+		list = list[1:]
+	}
+}
+
+// EnumTypeName returns the Go type generated for an enum column of table,
+// e.g. posts.status → PostStatus, or "" if the column isn't an enum.
+func EnumTypeName(table *schema.Table, col *schema.Column) string {
+	if col.IsEncrypted || col.IsSealed || len(EnumValues(col)) == 0 {
+		return ""
+	}
+	return TableToStructName(table.Name) + SnakeToPascal(col.Name)
+}
+
+// ModelFieldType returns the Go type of col's field on table's model: the
+// enum type for an enum column, otherwise ColumnGoType.
+func ModelFieldType(table *schema.Table, col *schema.Column) string {
+	enum := EnumTypeName(table, col)
+	if enum == "" {
+		return ColumnGoType(col)
+	}
+	if col.IsNullable {
+		return "*" + enum
+	}
+	return enum
+}
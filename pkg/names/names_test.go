@@ -1,6 +1,7 @@
 package names
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/shortontech/pickle/pkg/schema"
@@ -268,6 +269,52 @@ func TestColumnImport(t *testing.T) {
 	}
 }
 
+func TestEnumValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		colType  schema.ColumnType
+		check    string
+		expected []string
+	}{
+		{"simple", schema.String, "status IN ('draft', 'published')", []string{"draft", "published"}},
+		{"parenthesized", schema.String, "(status in ('a','b'))", []string{"a", "b"}},
+		{"quoted name", schema.Text, `"status" IN ('it''s')`, []string{"it's"}},
+		{"other column", schema.String, "state IN ('a')", nil},
+		{"comparison", schema.String, "status <> ''", nil},
+		{"unquoted value", schema.String, "status IN (a, 'b')", nil},
+		{"not a string", schema.Integer, "status IN ('1')", nil},
+		{"none", schema.String, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := &schema.Column{Name: "status", Type: tt.colType, CheckExpr: tt.check}
+			if got := EnumValues(col); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("EnumValues(%q) = %q, want %q", tt.check, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModelFieldType(t *testing.T) {
+	table := &schema.Table{Name: "blog_posts"}
+	status := &schema.Column{Name: "status", Type: schema.String, CheckExpr: "status IN ('draft')"}
+	if got := ModelFieldType(table, status); got != "BlogPostStatus" {
+		t.Errorf("enum column = %q, want BlogPostStatus", got)
+	}
+	status.IsNullable = true
+	if got := ModelFieldType(table, status); got != "*BlogPostStatus" {
+		t.Errorf("nullable enum column = %q, want *BlogPostStatus", got)
+	}
+	status.IsEncrypted = true
+	if got := ModelFieldType(table, status); got != "*string" {
+		t.Errorf("encrypted column = %q, want *string", got)
+	}
+	title := &schema.Column{Name: "title", Type: schema.String}
+	if got := ModelFieldType(table, title); got != "string" {
+		t.Errorf("plain column = %q, want string", got)
+	}
+}
+
 func TestIsVowel(t *testing.T) {
 	vowels := []byte{'a', 'e', 'i', 'o', 'u'}
 	for _, v := range vowels {
@@ -0,0 +1,132 @@
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// columnTypes maps --columns type names to Table builder methods.
+var columnTypes = map[string]string{
+	"uuid":       "UUID",
+	"string":     "String",
+	"text":       "Text",
+	"integer":    "Integer",
+	"int":        "Integer",
+	"biginteger": "BigInteger",
+	"bigint":     "BigInteger",
+	"decimal":    "Decimal",
+	"boolean":    "Boolean",
+	"bool":       "Boolean",
+	"timestamp":  "Timestamp",
+	"jsonb":      "JSONB",
+	"date":       "Date",
+	"time":       "Time",
+	"binary":     "Binary",
+	"float":      "Float",
+	"double":     "Double",
+}
+
+var columnIdentRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// parseColumnSpecs turns the --columns mini-DSL into Table builder calls.
+//
+// Each comma-separated entry is name:type followed by optional modifiers:
+//
+//	title:string              t.String("title").NotNull()
+//	slug:string=100:unique    t.String("slug", 100).NotNull().Unique()
+//	price:decimal=10.2        t.Decimal("price", 10, 2).NotNull()
+//	bio:text:nullable         t.Text("bio").Nullable()
+//	user_id:uuid:fk=users.id  t.UUID("user_id").NotNull().ForeignKey("users", "id")
+func parseColumnSpecs(spec string) ([]string, error) {
+	var out []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		line, err := parseColumnSpec(entry)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, line)
+	}
+	return out, nil
+}
+
+func parseColumnSpec(entry string) (string, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("column %q: expected name:type", entry)
+	}
+	name := parts[0]
+	if !columnIdentRe.MatchString(name) {
+		return "", fmt.Errorf("column %q: invalid column name %q", entry, name)
+	}
+
+	typeName, typeArg, _ := strings.Cut(strings.ToLower(parts[1]), "=")
+	method, ok := columnTypes[typeName]
+	if !ok {
+		return "", fmt.Errorf("column %q: unknown type %q", entry, typeName)
+	}
+
+	call := fmt.Sprintf("t.%s(%q", method, name)
+	switch {
+	case method == "Decimal":
+		precision, scale := 10, 2
+		if typeArg != "" {
+			p, s, _ := strings.Cut(typeArg, ".")
+			var err error
+			if precision, err = strconv.Atoi(p); err != nil || precision < 1 {
+				return "", fmt.Errorf("column %q: invalid decimal precision %q", entry, p)
+			}
+			scale = 0
+			if s != "" {
+				if scale, err = strconv.Atoi(s); err != nil || scale < 0 || scale > precision {
+					return "", fmt.Errorf("column %q: invalid decimal scale %q", entry, s)
+				}
+			}
+		}
+		call += fmt.Sprintf(", %d, %d", precision, scale)
+	case method == "String" && typeArg != "":
+		length, err := strconv.Atoi(typeArg)
+		if err != nil || length < 1 {
+			return "", fmt.Errorf("column %q: invalid string length %q", entry, typeArg)
+		}
+		call += fmt.Sprintf(", %d", length)
+	case typeArg != "":
+		return "", fmt.Errorf("column %q: type %s does not take an argument", entry, typeName)
+	}
+	call += ")"
+
+	nullable := false
+	var modifiers []string
+	for _, mod := range parts[2:] {
+		key, val, _ := strings.Cut(mod, "=")
+		switch strings.ToLower(key) {
+		case "nullable":
+			nullable = true
+		case "unique":
+			modifiers = append(modifiers, ".Unique()")
+		case "fk":
+			table, column, ok := strings.Cut(val, ".")
+			if !ok {
+				column = "id"
+			}
+			if !columnIdentRe.MatchString(table) || !columnIdentRe.MatchString(column) {
+				return "", fmt.Errorf("column %q: fk must be table.column, got %q", entry, val)
+			}
+			modifiers = append(modifiers, fmt.Sprintf(".ForeignKey(%q, %q)", table, column))
+		default:
+			return "", fmt.Errorf("column %q: unknown modifier %q", entry, mod)
+		}
+	}
+
+	if nullable {
+		call += ".Nullable()"
+	} else {
+		call += ".NotNull()"
+	}
+	return call + strings.Join(modifiers, ""), nil
+}
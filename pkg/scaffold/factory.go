@@ -0,0 +1,392 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shortontech/pickle/pkg/names"
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// fakerRelPath is the deterministic faker shared by every factory. It is
+// written alongside the first factory and left alone after that.
+var fakerRelPath = filepath.Join("database", "factories", "faker.go")
+
+// MakeFactory scaffolds database/factories/<model>_factory.go for table: a
+// Definition() filling each column with a fake value that fits its type, and
+// a Create(n) inserting records through the generated Query<Model>() builder.
+// Values come from a seeded Faker, so the same seed produces the same records.
+// Foreign keys are left for the caller to point at existing rows.
+func MakeFactory(table *schema.Table, projectDir, moduleName string) (string, error) {
+	model := names.TableToStructName(table.Name)
+	relPath := filepath.Join("database", "factories", names.PascalToSnake(model)+"_factory.go")
+
+	src, err := tmplFactory(table, model, moduleName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, fakerRelPath)); os.IsNotExist(err) {
+		if _, err := writeScaffold(projectDir, fakerRelPath, tmplFaker()); err != nil {
+			return "", err
+		}
+	}
+	return writeScaffold(projectDir, relPath, src)
+}
+
+func tmplFactory(table *schema.Table, model, moduleName string) (string, error) {
+	factory := model + "Factory"
+	imports := map[string]bool{}
+	var fields, foreignKeys []string
+
+	for _, col := range table.Columns {
+		field := names.SnakeToPascal(col.Name)
+		if col.ForeignKeyTable != "" || col.IsOwnerColumn {
+			if !col.IsNullable {
+				target := "the owning user"
+				if col.ForeignKeyTable != "" {
+					target = "a row in " + col.ForeignKeyTable
+				}
+				foreignKeys = append(foreignKeys, fmt.Sprintf("%s: %s", field, target))
+			}
+			continue
+		}
+		if timestampColumns[col.Name] {
+			continue
+		}
+		if col.IsPrimaryKey && col.Type != schema.UUID {
+			continue
+		}
+		value, imp := fakeValue(col)
+		if col.IsNullable && col.Type != schema.Binary {
+			value = "ptr(" + value + ")"
+		}
+		if imp != "" {
+			imports[imp] = true
+		}
+		fields = append(fields, fmt.Sprintf("\t\t%s: %s,\n", field, value))
+	}
+
+	var b strings.Builder
+	b.WriteString("package factories\n\nimport (\n")
+	var stdlib, external []string
+	for imp := range imports {
+		if strings.Contains(imp, ".") {
+			external = append(external, imp)
+		} else {
+			stdlib = append(stdlib, imp)
+		}
+	}
+	sort.Strings(stdlib)
+	sort.Strings(external)
+	for _, imp := range stdlib {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	if len(stdlib) > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\t%q\n", moduleName+"/app/models")
+	for _, imp := range external {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// %s builds %s records filled with fake values. Two factories\n// created with the same seed produce the same records.\n", factory, model)
+	fmt.Fprintf(&b, "type %s struct {\n\tfake *Faker\n}\n\n", factory)
+	fmt.Fprintf(&b, "// New%s returns a %s whose values are drawn from seed.\n", factory, factory)
+	fmt.Fprintf(&b, "func New%s(seed int64) *%s {\n\treturn &%s{fake: NewFaker(seed)}\n}\n\n", factory, factory, factory)
+
+	fmt.Fprintf(&b, "// Definition returns an unsaved %s. Adjust the values to fit your\n// data; timestamps are set by the query builder on Create.\n", model)
+	if len(foreignKeys) > 0 {
+		b.WriteString("//\n// Set these before Create, since fake values would break their foreign keys:\n")
+		for _, fk := range foreignKeys {
+			fmt.Fprintf(&b, "//   - %s\n", fk)
+		}
+	}
+	fmt.Fprintf(&b, "func (f *%s) Definition() models.%s {\n\treturn models.%s{\n", factory, model, model)
+	for _, field := range fields {
+		b.WriteString(field)
+	}
+	b.WriteString("\t}\n}\n\n")
+
+	fmt.Fprintf(&b, "// Create inserts n records built by Definition, each passed to the optional\n// customize funcs first, and returns them as stored. On error it returns the\n// records inserted so far.\n")
+	fmt.Fprintf(&b, "func (f *%s) Create(n int, customize ...func(*models.%s)) ([]models.%s, error) {\n", factory, model, model)
+	fmt.Fprintf(&b, "\trecords := make([]models.%s, 0, n)\n", model)
+	b.WriteString("\tfor i := 0; i < n; i++ {\n\t\trecord := f.Definition()\n")
+	b.WriteString("\t\tfor _, fn := range customize {\n\t\t\tfn(&record)\n\t\t}\n")
+	fmt.Fprintf(&b, "\t\tif err := models.Query%s().Create(&record); err != nil {\n\t\t\treturn records, err\n\t\t}\n", model)
+	b.WriteString("\t\trecords = append(records, record)\n\t}\n\treturn records, nil\n}\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting factory: %w\n%s", err, b.String())
+	}
+	return string(formatted), nil
+}
+
+// fakeValue returns a Go expression producing a fake value for col's base
+// type, and the import it needs. String columns are guessed from their name
+// (email, name, title, url, ...) and kept within their length.
+func fakeValue(col *schema.Column) (string, string) {
+	textual := col.Type == schema.String || col.Type == schema.Text
+	if textual && col.Seeder != nil && (col.Seeder.Kind == "values" || col.Seeder.Kind == "random_string_in") && len(col.Seeder.Arguments) > 0 {
+		quoted := make([]string, len(col.Seeder.Arguments))
+		for i, arg := range col.Seeder.Arguments {
+			quoted[i] = fmt.Sprintf("%q", arg)
+		}
+		return "f.fake.Pick(" + strings.Join(quoted, ", ") + ")", ""
+	}
+
+	switch col.Type {
+	case schema.UUID:
+		return "f.fake.UUID()", ""
+	case schema.String:
+		value := fakeString(col.Name)
+		unique := col.IsUnique && value != "f.fake.Email()"
+		if col.Length > 0 && col.Length < 64 {
+			// Leave room for the suffix Unique appends.
+			max := col.Length
+			if unique {
+				max = (col.Length + 1) / 2
+			}
+			value = fmt.Sprintf("f.fake.Fit(%s, %d)", value, max)
+		}
+		if unique {
+			value = "f.fake.Unique(" + value + ")"
+		}
+		return value, ""
+	case schema.Text:
+		if col.IsUnique {
+			return "f.fake.Unique(f.fake.Paragraph())", ""
+		}
+		return "f.fake.Paragraph()", ""
+	case schema.Time:
+		return "f.fake.Clock()", ""
+	case schema.Integer:
+		return "f.fake.Int(1, 1000)", ""
+	case schema.BigInteger:
+		return "int64(f.fake.Int(1, 1000000))", ""
+	case schema.Decimal:
+		scale := col.Scale
+		if scale == 0 {
+			scale = 2
+		}
+		return fmt.Sprintf("decimal.New(int64(f.fake.Int(100, 100000)), -%d)", scale), "github.com/shopspring/decimal"
+	case schema.Boolean:
+		return "f.fake.Bool()", ""
+	case schema.Timestamp:
+		return "f.fake.Time()", ""
+	case schema.Date:
+		return "f.fake.Date()", ""
+	case schema.JSONB:
+		return "json.RawMessage(`{}`)", "encoding/json"
+	case schema.Binary:
+		return "f.fake.Bytes(16)", ""
+	case schema.Float:
+		return "float32(f.fake.Float(0, 100))", ""
+	case schema.Double:
+		return "f.fake.Float(0, 1000)", ""
+	default:
+		return "nil", ""
+	}
+}
+
+// fakeString picks a Faker method for a string column by its name.
+func fakeString(column string) string {
+	switch {
+	case column == "email" || strings.HasSuffix(column, "_email"):
+		return "f.fake.Email()"
+	case column == "first_name":
+		return "f.fake.FirstName()"
+	case column == "last_name":
+		return "f.fake.LastName()"
+	case column == "name" || strings.HasSuffix(column, "_name"):
+		return "f.fake.Name()"
+	case column == "title" || column == "subject":
+		return "f.fake.Sentence()"
+	case column == "slug" || column == "username" || column == "handle":
+		return "f.fake.Slug()"
+	case column == "url" || strings.HasSuffix(column, "_url"):
+		return "f.fake.URL()"
+	case column == "phone" || strings.HasSuffix(column, "_phone"):
+		return "f.fake.Phone()"
+	case column == "password" || strings.HasSuffix(column, "_hash"):
+		return "f.fake.Hex(32)"
+	default:
+		return "f.fake.Words(2)"
+	}
+}
+
+func tmplFaker() string {
+	return `package factories
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Faker produces deterministic fake values: two Fakers with the same seed
+// return the same sequence, so tests that build data through factories are
+// reproducible. Times are drawn relative to a fixed anchor rather than now.
+type Faker struct {
+	rng    *rand.Rand
+	unique int
+}
+
+// FakeAnchor is the instant Time and Date count back from.
+var FakeAnchor = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NewFaker returns a Faker drawing from seed.
+func NewFaker(seed int64) *Faker {
+	return &Faker{rng: rand.New(rand.NewSource(seed))}
+}
+
+var (
+	fakeFirstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Ken", "Barbara", "Dennis", "Frances", "Edsger"}
+	fakeLastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Thompson", "Liskov", "Ritchie", "Allen", "Dijkstra"}
+	fakeWords      = []string{"brine", "dill", "gherkin", "jar", "vinegar", "crisp", "salt", "garlic", "spear", "relish", "sour", "cucumber", "mustard", "pepper", "crunch", "barrel"}
+)
+
+// Int returns a number in [min, max].
+func (f *Faker) Int(min, max int) int {
+	return min + f.rng.Intn(max-min+1)
+}
+
+// Float returns a number in [min, max).
+func (f *Faker) Float(min, max float64) float64 {
+	return min + f.rng.Float64()*(max-min)
+}
+
+// Bool returns true or false.
+func (f *Faker) Bool() bool {
+	return f.rng.Intn(2) == 1
+}
+
+// Pick returns one of values.
+func (f *Faker) Pick(values ...string) string {
+	return values[f.rng.Intn(len(values))]
+}
+
+// Word returns a single lowercase word.
+func (f *Faker) Word() string {
+	return f.Pick(fakeWords...)
+}
+
+// Words returns n words separated by spaces.
+func (f *Faker) Words(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.Word()
+	}
+	return strings.Join(words, " ")
+}
+
+// Sentence returns a capitalised sentence of four to eight words.
+func (f *Faker) Sentence() string {
+	s := f.Words(f.Int(4, 8))
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// Paragraph returns three to five sentences.
+func (f *Faker) Paragraph() string {
+	sentences := make([]string, f.Int(3, 5))
+	for i := range sentences {
+		sentences[i] = f.Sentence()
+	}
+	return strings.Join(sentences, " ")
+}
+
+// FirstName returns a given name.
+func (f *Faker) FirstName() string { return f.Pick(fakeFirstNames...) }
+
+// LastName returns a family name.
+func (f *Faker) LastName() string { return f.Pick(fakeLastNames...) }
+
+// Name returns a full name.
+func (f *Faker) Name() string { return f.FirstName() + " " + f.LastName() }
+
+// Email returns an address at example.com that no other call on this Faker
+// returns.
+func (f *Faker) Email() string {
+	return f.Unique(strings.ToLower(f.FirstName()+"."+f.LastName())) + "@example.com"
+}
+
+// Slug returns a lowercase, hyphenated identifier.
+func (f *Faker) Slug() string {
+	return f.Word() + "-" + f.Word()
+}
+
+// URL returns an https URL at example.com.
+func (f *Faker) URL() string {
+	return "https://example.com/" + f.Slug()
+}
+
+// Phone returns a phone number in the 555 range.
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("+1-555-%03d-%04d", f.Int(100, 999), f.Int(0, 9999))
+}
+
+// Hex returns n random bytes, hex encoded.
+func (f *Faker) Hex(n int) string {
+	return hex.EncodeToString(f.Bytes(n))
+}
+
+// Bytes returns n random bytes.
+func (f *Faker) Bytes(n int) []byte {
+	b := make([]byte, n)
+	f.rng.Read(b)
+	return b
+}
+
+// UUID returns a version 4 UUID built from the Faker's random stream.
+func (f *Faker) UUID() uuid.UUID {
+	var id uuid.UUID
+	f.rng.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return id
+}
+
+// Time returns an instant in the year before FakeAnchor, to the second.
+func (f *Faker) Time() time.Time {
+	return FakeAnchor.Add(-time.Duration(f.rng.Int63n(365*24*60*60)) * time.Second)
+}
+
+// Date returns midnight UTC on a day in the year before FakeAnchor.
+func (f *Faker) Date() time.Time {
+	return FakeAnchor.AddDate(0, 0, -f.Int(1, 365))
+}
+
+// Clock returns a time of day formatted as HH:MM:SS.
+func (f *Faker) Clock() string {
+	return fmt.Sprintf("%02d:%02d:%02d", f.Int(0, 23), f.Int(0, 59), f.Int(0, 59))
+}
+
+// Unique appends a counter to s, so values for unique columns don't collide.
+func (f *Faker) Unique(s string) string {
+	f.unique++
+	return fmt.Sprintf("%s-%d", s, f.unique)
+}
+
+// Fit shortens s to at most n bytes.
+func (f *Faker) Fit(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// ptr returns a pointer to v, for nullable model fields.
+func ptr[T any](v T) *T {
+	return &v
+}
+`
+}
@@ -0,0 +1,164 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func membersTable() *schema.Table {
+	t := &schema.Table{Name: "members"}
+	t.UUID("id").PrimaryKey()
+	t.UUID("team_id").ForeignKey("teams", "id")
+	t.String("name")
+	t.String("email").Unique()
+	t.String("handle", 20).Unique()
+	t.Text("bio").Nullable()
+	t.Integer("age").Nullable()
+	t.Decimal("balance", 10, 2)
+	t.Boolean("is_admin")
+	t.Date("born_on")
+	t.JSONB("settings").Nullable()
+	t.Timestamps()
+	return t
+}
+
+func TestMakeFactoryWritesFactoryAndFaker(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := MakeFactory(membersTable(), dir, "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relPath != filepath.Join("database", "factories", "member_factory.go") {
+		t.Fatalf("relPath = %s", relPath)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	for _, want := range []string{
+		"func NewMemberFactory(seed int64) *MemberFactory",
+		"func (f *MemberFactory) Definition() models.Member",
+		"ID:       f.fake.UUID(),",
+		"Email:    f.fake.Email(),",
+		"Handle:   f.fake.Unique(f.fake.Fit(f.fake.Slug(), 10)),",
+		"Bio:      ptr(f.fake.Paragraph()),",
+		"Balance:  decimal.New(int64(f.fake.Int(100, 100000)), -2),",
+		"Settings: ptr(json.RawMessage(`{}`)),",
+		"//   - TeamID: a row in teams",
+		"models.QueryMember().Create(&record)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("factory missing %q:\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"TeamID:", "CreatedAt:", "UpdatedAt:"} {
+		if strings.Contains(src, "\t"+unwanted) {
+			t.Errorf("factory should not fill %s:\n%s", unwanted, src)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, fakerRelPath)); err != nil {
+		t.Fatalf("faker.go not written: %v", err)
+	}
+
+	// A second factory reuses the existing faker.
+	if _, err := MakeFactory(postsTable(), dir, "example.com/app"); err != nil {
+		t.Fatalf("second factory: %v", err)
+	}
+	if _, err := MakeFactory(membersTable(), dir, "example.com/app"); err == nil {
+		t.Error("expected an error when the factory already exists")
+	}
+}
+
+func TestMakeFactoryCompilesAndIsDeterministic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles and runs a scaffolded factory")
+	}
+	dir, err := os.MkdirTemp(".", "_factorytest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	module := "github.com/shortontech/pickle/pkg/scaffold/" + filepath.Base(dir)
+	// Pickle's own go.mod doesn't require shopspring/decimal, so leave the
+	// decimal column out.
+	table := membersTable()
+	for i, col := range table.Columns {
+		if col.Name == "balance" {
+			table.Columns = append(table.Columns[:i], table.Columns[i+1:]...)
+			break
+		}
+	}
+	if _, err := MakeFactory(table, dir, module); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"app/models/member.go": `package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Member struct {
+	ID        uuid.UUID
+	TeamID    uuid.UUID
+	Name      string
+	Email     string
+	Handle    string
+	Bio       *string
+	Age       *int
+	IsAdmin   bool
+	BornOn    time.Time
+	Settings  *json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type MemberQuery struct{}
+
+func QueryMember() *MemberQuery { return &MemberQuery{} }
+
+func (q *MemberQuery) Create(m *Member) error { return nil }
+`,
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"` + module + `/database/factories"
+)
+
+func main() {
+	a, _ := factories.NewMemberFactory(7).Create(2)
+	b, _ := factories.NewMemberFactory(7).Create(2)
+	fmt.Println(a[0].Email == b[0].Email, a[1].ID == b[1].ID, a[0].Email != a[1].Email, len(a[0].Handle) <= 20)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := exec.Command("go", "run", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	if got := strings.TrimSpace(string(output)); got != "true true true true" {
+		t.Errorf("output = %q, want deterministic, unique, fitted values", got)
+	}
+}
@@ -0,0 +1,348 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shortontech/pickle/pkg/names"
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// timestampColumns are maintained by the query builder and never bound from
+// request bodies.
+var timestampColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// MakeResourceController scaffolds a CRUD controller for table whose Index
+// and Show use the generated Query<Model>() builder and whose Store and Update
+// bind the table's writable columns. Update writes only the columns present in
+// the request body. It returns the controller's relative
+// path and struct name.
+func MakeResourceController(table *schema.Table, projectDir, moduleName string) (string, string, error) {
+	model := names.TableToStructName(table.Name)
+	structName := model + "Controller"
+	relPath := filepath.Join("app", "http", "controllers", names.PascalToSnake(model)+"_controller.go")
+
+	src, err := tmplResourceController(table, model, structName, moduleName)
+	if err != nil {
+		return "", "", err
+	}
+	relPath, err = writeScaffold(projectDir, relPath, src)
+	return relPath, structName, err
+}
+
+// AddResourceRoute appends r.Resource(path, controllers.Controller{}) to the
+// pickle.Routes block in routes/web.go. It reports false without writing when
+// the controller is already registered as a resource.
+func AddResourceRoute(projectDir, path, controller string) (bool, error) {
+	routesPath := filepath.Join(projectDir, "routes", "web.go")
+	src, err := os.ReadFile(routesPath)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(src), "\n") {
+		if strings.Contains(line, "r.Resource(") && strings.Contains(line, "controllers."+controller+"{}") {
+			return false, nil
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, routesPath, src, 0)
+	if err != nil {
+		return false, fmt.Errorf("parsing routes/web.go: %w", err)
+	}
+	importsControllers := false
+	for _, imp := range file.Imports {
+		if strings.HasSuffix(strings.Trim(imp.Path.Value, `"`), "/app/http/controllers") {
+			importsControllers = true
+		}
+	}
+	if !importsControllers {
+		return false, fmt.Errorf("routes/web.go does not import the controllers package")
+	}
+
+	// Insert before the closing brace of the last pickle.Routes(func(r) {...}).
+	insertAt := -1
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Routes" {
+			return true
+		}
+		if fn, ok := call.Args[0].(*ast.FuncLit); ok {
+			insertAt = fset.Position(fn.Body.Rbrace).Offset
+		}
+		return true
+	})
+	if insertAt < 0 {
+		return false, fmt.Errorf("routes/web.go has no pickle.Routes block")
+	}
+
+	line := fmt.Sprintf("\tr.Resource(%q, controllers.%s{})\n", path, controller)
+	out := string(src[:insertAt]) + line + string(src[insertAt:])
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		return false, fmt.Errorf("formatting routes/web.go: %w", err)
+	}
+	return true, os.WriteFile(routesPath, formatted, 0o644)
+}
+
+// ResourcePath returns the URL prefix used for a table's resource routes:
+// blog_posts → /blog-posts.
+func ResourcePath(table string) string {
+	return "/" + strings.ReplaceAll(table, "_", "-")
+}
+
+type resourceField struct {
+	Column    string
+	Field     string
+	InputType string
+	Deref     bool // model field is a value, input is a pointer
+	Required  bool
+	// Transitions is set for columns declared with Transitions(); Update
+	// checks a change with the model's Validate<Field>Transition first.
+	Transitions bool
+	// Enum is set for columns with a generated enum type; Store and Update
+	// reject values outside it.
+	Enum bool
+}
+
+func tmplResourceController(table *schema.Table, model, structName, moduleName string) (string, error) {
+	var pk, owner *schema.Column
+	var fields []resourceField
+	imports := map[string]bool{"encoding/json": true}
+
+	for _, col := range table.Columns {
+		if col.IsPrimaryKey {
+			if pk != nil && !(table.IsImmutable && col.Name == "version_id") {
+				return "", fmt.Errorf("table %q has a composite primary key", table.Name)
+			}
+			pk = col
+			continue
+		}
+		if col.IsOwnerColumn && col.Type == schema.UUID {
+			owner = col
+			continue
+		}
+		if col.IsPrimaryKey || timestampColumns[col.Name] {
+			continue
+		}
+		modelType := names.ColumnGoType(col)
+		inputType := "*" + names.ColumnBaseGoType(col)
+		if modelType == "[]byte" {
+			inputType = modelType
+		}
+		enum := names.EnumTypeName(table, col)
+		if enum != "" {
+			inputType = "*models." + enum
+		}
+		fields = append(fields, resourceField{
+			Column:    col.Name,
+			Field:     names.SnakeToPascal(col.Name),
+			InputType: inputType,
+			Deref:     !col.IsNullable && modelType != "[]byte",
+			Required:  !col.IsNullable && !col.HasDefault,
+			// The validator takes plain strings, so nullable columns
+			// are left for the controller author to handle.
+			Transitions: len(col.TransitionMap) > 0 && !col.IsNullable,
+			Enum:        enum != "",
+		})
+		if imp := names.ColumnImport(col); imp != "" {
+			imports[imp] = true
+		}
+	}
+	if pk == nil {
+		return "", fmt.Errorf("table %q has no primary key", table.Name)
+	}
+
+	var parseID string
+	switch names.ColumnBaseGoType(pk) {
+	case "uuid.UUID":
+		parseID = `id, err := ctx.ParamUUID("id")`
+	case "int":
+		parseID = `id, err := ctx.ParamInt("id")`
+	case "int64":
+		parseID = `id, err := ctx.ParamInt64("id")`
+	default:
+		return "", fmt.Errorf("table %q: unsupported primary key type %s", table.Name, names.ColumnBaseGoType(pk))
+	}
+	if owner != nil {
+		imports["github.com/google/uuid"] = true
+	}
+
+	var b strings.Builder
+	recv := "c " + structName
+	query := "models.Query" + model + "()"
+	whereOwner, ownerField := "", ""
+	if owner != nil {
+		ownerField = names.SnakeToPascal(owner.Name)
+		whereOwner = ".\n\t\tWhereOwnedBy(authID)"
+	}
+	authID := `
+	authID, err := uuid.Parse(ctx.Auth().UserID)
+	if err != nil {
+		return ctx.Unauthorized("invalid auth")
+	}
+`
+	if owner == nil {
+		authID = ""
+	}
+
+	b.WriteString("package controllers\n\nimport (\n")
+	var stdlib, external []string
+	for imp := range imports {
+		if strings.Contains(imp, ".") {
+			external = append(external, imp)
+		} else {
+			stdlib = append(stdlib, imp)
+		}
+	}
+	sort.Strings(stdlib)
+	sort.Strings(external)
+	for _, imp := range stdlib {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "\tpickle %q\n\t%q\n", moduleName+"/app/http", moduleName+"/app/models")
+	if len(external) > 0 {
+		b.WriteString("\n")
+		for _, imp := range external {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "type %s struct {\n\tpickle.Controller\n}\n\n", structName)
+
+	input := strings.ToLower(model[:1]) + model[1:] + "Input"
+	fmt.Fprintf(&b, "// %s is the JSON body accepted by Store and Update. Fields left out of\n// an Update keep their current value.\ntype %s struct {\n", input, input)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", f.Field, f.InputType, f.Column)
+	}
+	b.WriteString("}\n\n")
+
+	// Index
+	fmt.Fprintf(&b, "func (%s) Index(ctx *pickle.Context) pickle.Response {%s\n", recv, authID)
+	fmt.Fprintf(&b, "\trecords, err := %s%s.\n\t\tLimit(100).\n\t\tAll()\n", query, whereOwner)
+	b.WriteString("\tif err != nil {\n\t\treturn ctx.Error(err)\n\t}\n\treturn ctx.JSON(200, records)\n}\n\n")
+
+	findRecord := func() {
+		fmt.Fprintf(&b, "\t%s\n\tif err != nil {\n\t\treturn ctx.BadRequest(\"invalid id\")\n\t}\n", parseID)
+		b.WriteString(authID)
+		fmt.Fprintf(&b, "\n\trecord, resp := %s%s.\n\t\tFindOrFail(ctx, id)\n", query, whereOwner)
+		b.WriteString("\tif record == nil {\n\t\treturn resp\n\t}\n")
+	}
+
+	// Show
+	fmt.Fprintf(&b, "func (%s) Show(ctx *pickle.Context) pickle.Response {\n", recv)
+	findRecord()
+	b.WriteString("\treturn ctx.JSON(200, record)\n}\n\n")
+
+	decode := fmt.Sprintf("\tvar in %s\n\tif err := json.NewDecoder(ctx.Request().Body).Decode(&in); err != nil {\n\t\treturn ctx.BadRequest(\"invalid JSON body\")\n\t}\n", input)
+
+	// Store
+	fmt.Fprintf(&b, "func (%s) Store(ctx *pickle.Context) pickle.Response {%s\n", recv, authID)
+	b.WriteString(decode)
+	for _, f := range fields {
+		if f.Required {
+			fmt.Fprintf(&b, "\tif in.%s == nil {\n\t\treturn ctx.JSON(422, map[string]string{\"error\": %q})\n\t}\n", f.Field, f.Column+" is required")
+		}
+	}
+	writeEnumChecks(&b, fields)
+	fmt.Fprintf(&b, "\n\trecord := &models.%s{}\n", model)
+	if owner != nil {
+		if owner.IsNullable {
+			fmt.Fprintf(&b, "\trecord.%s = &authID\n", ownerField)
+		} else {
+			fmt.Fprintf(&b, "\trecord.%s = authID\n", ownerField)
+		}
+	}
+	writeAssignments(&b, fields, false)
+	fmt.Fprintf(&b, "\tif err := %s.Create(record); err != nil {\n\t\treturn ctx.Error(err)\n\t}\n\treturn ctx.JSON(201, record)\n}\n\n", query)
+
+	// Update
+	fmt.Fprintf(&b, "func (%s) Update(ctx *pickle.Context) pickle.Response {\n", recv)
+	findRecord()
+	b.WriteString("\n")
+	b.WriteString(decode)
+	writeEnumChecks(&b, fields)
+	writeTransitionChecks(&b, fields)
+	if table.IsImmutable {
+		// Immutable tables write a whole new version, so there is no
+		// partial update.
+		writeAssignments(&b, fields, false)
+		fmt.Fprintf(&b, "\tif err := %s.Update(record); err != nil {\n\t\treturn ctx.Error(err)\n\t}\n\treturn ctx.JSON(200, record)\n}\n\n", query)
+	} else {
+		b.WriteString("\tvar columns []string\n")
+		writeAssignments(&b, fields, true)
+		fmt.Fprintf(&b, "\tif err := %s.UpdateColumns(record, columns...); err != nil {\n\t\treturn ctx.Error(err)\n\t}\n\treturn ctx.JSON(200, record)\n}\n\n", query)
+	}
+
+	// Destroy
+	fmt.Fprintf(&b, "func (%s) Destroy(ctx *pickle.Context) pickle.Response {\n", recv)
+	findRecord()
+	fmt.Fprintf(&b, "\tif err := %s.Delete(record); err != nil {\n\t\treturn ctx.Error(err)\n\t}\n\treturn ctx.NoContent()\n}\n", query)
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting controller: %w\n%s", err, b.String())
+	}
+	return string(formatted), nil
+}
+
+// writeTransitionChecks rejects an Update that moves a column declared with
+// Transitions() to a state it can't reach from the record's current one.
+func writeTransitionChecks(b *strings.Builder, fields []resourceField) {
+	for _, f := range fields {
+		if !f.Transitions {
+			continue
+		}
+		from, to := "record."+f.Field, "*in."+f.Field
+		if f.Enum {
+			from, to = from+".String()", "in."+f.Field+".String()"
+		}
+		fmt.Fprintf(b, "\tif in.%s != nil {\n\t\tif err := record.Validate%sTransition(%s, %s); err != nil {\n", f.Field, f.Field, from, to)
+		b.WriteString("\t\t\treturn ctx.JSON(422, map[string]string{\"error\": err.Error()})\n\t\t}\n\t}\n")
+	}
+}
+
+// writeEnumChecks rejects a request body that sets an enum column to a value
+// outside its CHECK constraint.
+func writeEnumChecks(b *strings.Builder, fields []resourceField) {
+	for _, f := range fields {
+		if !f.Enum {
+			continue
+		}
+		fmt.Fprintf(b, "\tif in.%s != nil && !in.%s.Valid() {\n\t\treturn ctx.JSON(422, map[string]string{\"error\": %q})\n\t}\n", f.Field, f.Field, "invalid "+f.Column)
+	}
+}
+
+// writeAssignments copies each field present in the request body onto the
+// record. With trackColumns, each assigned column is also appended to a
+// columns slice for UpdateColumns.
+func writeAssignments(b *strings.Builder, fields []resourceField, trackColumns bool) {
+	for _, f := range fields {
+		value := "in." + f.Field
+		if f.Deref {
+			value = "*" + value
+		}
+		fmt.Fprintf(b, "\tif in.%s != nil {\n\t\trecord.%s = %s\n", f.Field, f.Field, value)
+		if trackColumns {
+			fmt.Fprintf(b, "\t\tcolumns = append(columns, %q)\n", f.Column)
+		}
+		b.WriteString("\t}\n")
+	}
+}
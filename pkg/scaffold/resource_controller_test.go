@@ -0,0 +1,200 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func postsTable() *schema.Table {
+	t := &schema.Table{Name: "posts"}
+	t.UUID("id").PrimaryKey()
+	t.UUID("user_id").IsOwner()
+	t.String("title")
+	t.Text("body").Nullable()
+	t.String("status").Default("draft")
+	t.Timestamps()
+	return t
+}
+
+func TestMakeResourceControllerBindsModelFields(t *testing.T) {
+	dir := t.TempDir()
+	relPath, structName, err := MakeResourceController(postsTable(), dir, "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relPath != filepath.Join("app", "http", "controllers", "post_controller.go") || structName != "PostController" {
+		t.Fatalf("got %s %s", relPath, structName)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	if _, err := parser.ParseFile(token.NewFileSet(), relPath, src, 0); err != nil {
+		t.Fatalf("controller does not parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		"Title  *string `json:\"title\"`",
+		"Body   *string `json:\"body\"`",
+		"models.QueryPost().\n\t\tWhereOwnedBy(authID).\n\t\tLimit(100)",
+		"models.QueryPost().\n\t\tWhereOwnedBy(authID).\n\t\tFindOrFail(ctx, id)",
+		"if record == nil {\n\t\treturn resp\n\t}",
+		`"title is required"`,
+		"record.UserID = authID",
+		"record.Title = *in.Title",
+		"record.Body = in.Body",
+		"models.QueryPost().Create(record)",
+		"columns = append(columns, \"title\")",
+		"models.QueryPost().UpdateColumns(record, columns...)",
+		"models.QueryPost().Delete(record)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("controller missing %q:\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"TODO", "CreatedAt", `"status is required"`, "UserID *"} {
+		if strings.Contains(src, unwanted) {
+			t.Errorf("controller should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestMakeResourceControllerValidatesStatusTransitions(t *testing.T) {
+	table := postsTable()
+	for _, col := range table.Columns {
+		if col.Name == "status" {
+			col.Transitions(map[string][]string{"draft": {"published"}})
+		}
+	}
+	dir := t.TempDir()
+	relPath, _, err := MakeResourceController(table, dir, "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	want := "if in.Status != nil {\n\t\tif err := record.ValidateStatusTransition(record.Status, *in.Status); err != nil {\n\t\t\treturn ctx.JSON(422, map[string]string{\"error\": err.Error()})"
+	if !strings.Contains(src, want) {
+		t.Errorf("Update should validate the status transition:\n%s", src)
+	}
+	if strings.Index(src, "ValidateStatusTransition") > strings.Index(src, "record.Status = *in.Status\n\t\tcolumns") {
+		t.Errorf("transition must be checked before the assignment:\n%s", src)
+	}
+}
+
+func TestMakeResourceControllerValidatesEnumColumns(t *testing.T) {
+	table := postsTable()
+	for _, col := range table.Columns {
+		if col.Name == "status" {
+			col.Check("status IN ('draft', 'published')").Transitions(map[string][]string{"draft": {"published"}})
+		}
+	}
+	dir := t.TempDir()
+	relPath, _, err := MakeResourceController(table, dir, "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	for _, want := range []string{
+		"Status *models.PostStatus `json:\"status\"`",
+		"if in.Status != nil && !in.Status.Valid() {\n\t\treturn ctx.JSON(422, map[string]string{\"error\": \"invalid status\"})",
+		"record.ValidateStatusTransition(record.Status.String(), in.Status.String())",
+		"record.Status = *in.Status",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("controller missing %q:\n%s", want, src)
+		}
+	}
+	if strings.Count(src, "!in.Status.Valid()") != 2 {
+		t.Errorf("Store and Update should both validate status:\n%s", src)
+	}
+}
+
+func TestMakeResourceControllerIntegerKeyWithoutOwner(t *testing.T) {
+	tbl := &schema.Table{Name: "tags"}
+	tbl.Integer("id").PrimaryKey()
+	tbl.String("label")
+
+	src, err := tmplResourceController(tbl, "Tag", "TagController", "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, `id, err := ctx.ParamInt("id")`) || !strings.Contains(src, "record, resp := models.QueryTag().\n\t\tFindOrFail(ctx, id)") {
+		t.Errorf("integer key not parsed:\n%s", src)
+	}
+	if strings.Contains(src, "strconv") {
+		t.Errorf("integer key should use ctx.ParamInt, not strconv:\n%s", src)
+	}
+	if strings.Contains(src, "WhereOwnedBy") || strings.Contains(src, "uuid") {
+		t.Errorf("table without owner column should not scope by auth:\n%s", src)
+	}
+}
+
+func TestMakeResourceControllerRejectsCompositeKey(t *testing.T) {
+	tbl := &schema.Table{Name: "role_users"}
+	tbl.UUID("role_id").PrimaryKey()
+	tbl.UUID("user_id").PrimaryKey()
+
+	if _, err := tmplResourceController(tbl, "RoleUser", "RoleUserController", "example.com/app"); err == nil || !strings.Contains(err.Error(), "composite primary key") {
+		t.Fatalf("err = %v, want composite primary key error", err)
+	}
+}
+
+func TestMakeResourceControllerImmutableTableFindsByID(t *testing.T) {
+	tbl := &schema.Table{Name: "transfers"}
+	tbl.Immutable()
+	tbl.Decimal("amount", 18, 2)
+
+	src, err := tmplResourceController(tbl, "Transfer", "TransferController", "example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, "models.QueryTransfer().\n\t\tFindOrFail(ctx, id)") {
+		t.Errorf("immutable table should be found by id:\n%s", src)
+	}
+}
+
+func TestAddResourceRouteAppendsOnce(t *testing.T) {
+	dir := t.TempDir()
+	routesPath := filepath.Join(dir, "routes", "web.go")
+	os.MkdirAll(filepath.Dir(routesPath), 0o755)
+	os.WriteFile(routesPath, []byte(tmplRoutes("example.com/app")), 0o644)
+
+	added, err := AddResourceRoute(dir, "/posts", "PostController")
+	if err != nil || !added {
+		t.Fatalf("AddResourceRoute = %v, %v", added, err)
+	}
+	added, err = AddResourceRoute(dir, "/posts", "PostController")
+	if err != nil || added {
+		t.Fatalf("second AddResourceRoute = %v, %v; want no-op", added, err)
+	}
+
+	data, _ := os.ReadFile(routesPath)
+	src := string(data)
+	if strings.Count(src, `r.Resource("/posts", controllers.PostController{})`) != 1 {
+		t.Fatalf("route not appended exactly once:\n%s", src)
+	}
+	if !strings.Contains(src, "\t}, pickle.Recover())\n\tr.Resource(\"/posts\", controllers.PostController{})\n})\n") {
+		t.Errorf("route should be the last statement of pickle.Routes:\n%s", src)
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	if got := ResourcePath("blog_posts"); got != "/blog-posts" {
+		t.Errorf("ResourcePath = %q", got)
+	}
+}
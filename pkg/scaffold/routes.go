@@ -0,0 +1,319 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RouteSpec describes one route for AddRoute.
+type RouteSpec struct {
+	Method     string   // GET, POST, PUT, PATCH or DELETE
+	Path       string   // full path including group prefixes, e.g. /api/posts/:id
+	Controller string   // PostController or Post
+	Action     string   // controller method, e.g. Show
+	Middleware []string // per-route middleware: Auth, middleware.Auth
+	// Group is the group prefix to register the route under, e.g. /api/posts.
+	// It is created inside its nearest existing parent group when missing.
+	// Empty picks the deepest existing group whose prefix matches Path.
+	Group string
+}
+
+var routeMethods = map[string]string{
+	"GET":    "Get",
+	"POST":   "Post",
+	"PUT":    "Put",
+	"PATCH":  "Patch",
+	"DELETE": "Delete",
+}
+
+// routeGroup is a pickle.Routes block or r.Group call found in routes/web.go.
+type routeGroup struct {
+	prefix     string // full prefix, parent groups included
+	router     string // name of the *pickle.Router parameter
+	routerType string // source text of that parameter's type
+	body       *ast.BlockStmt
+}
+
+type declaredRoute struct {
+	method  string
+	path    string
+	handler string
+}
+
+// AddRoute registers spec in routes/web.go, placing it in the matching
+// r.Group and creating that group if spec.Group names one that doesn't exist.
+// The file is edited in place and gofmt'd, so existing comments and layout
+// survive. It reports false without writing when the same method, path and
+// handler are already registered, and fails if the method and path route to a
+// different handler.
+func AddRoute(projectDir string, spec RouteSpec) (bool, error) {
+	method := strings.ToUpper(strings.TrimSpace(spec.Method))
+	routerMethod, ok := routeMethods[method]
+	if !ok {
+		return false, fmt.Errorf("unsupported method %q (use GET, POST, PUT, PATCH or DELETE)", spec.Method)
+	}
+	path := cleanRoutePath(spec.Path)
+	if path == "" {
+		return false, fmt.Errorf("path is required")
+	}
+	controller := strings.TrimSuffix(spec.Controller, "Controller") + "Controller"
+	if !token.IsIdentifier(controller) || !token.IsIdentifier(spec.Action) {
+		return false, fmt.Errorf("controller and action must be Go identifiers")
+	}
+	handler := "controllers." + controller + "{}." + spec.Action
+
+	var middleware []string
+	needsMiddlewareImport := false
+	for _, mw := range spec.Middleware {
+		expr := strings.TrimSpace(mw)
+		pkg, name, qualified := strings.Cut(expr, ".")
+		if !qualified {
+			pkg, name = "middleware", expr
+			expr = "middleware." + name
+		}
+		if !token.IsIdentifier(pkg) || !token.IsIdentifier(name) {
+			return false, fmt.Errorf("invalid middleware %q", mw)
+		}
+		if pkg == "middleware" {
+			needsMiddlewareImport = true
+		}
+		middleware = append(middleware, expr)
+	}
+
+	routesPath := filepath.Join(projectDir, "routes", "web.go")
+	src, err := os.ReadFile(routesPath)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, routesPath, src, parser.ParseComments)
+	if err != nil {
+		return false, fmt.Errorf("parsing routes/web.go: %w", err)
+	}
+
+	controllersImport, middlewareImported := "", false
+	for _, imp := range file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		switch {
+		case strings.HasSuffix(p, "/app/http/controllers"):
+			controllersImport = p
+		case strings.HasSuffix(p, "/app/http/middleware"):
+			middlewareImported = true
+		}
+	}
+	if controllersImport == "" {
+		return false, fmt.Errorf("routes/web.go does not import the controllers package")
+	}
+
+	var groups []*routeGroup
+	var declared []declaredRoute
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Routes" {
+			return true
+		}
+		if fn, ok := call.Args[0].(*ast.FuncLit); ok {
+			if g := newRouteGroup("", fn, src, fset); g != nil {
+				groups, declared = collectRouteGroup(g, src, fset, groups, declared)
+			}
+		}
+		return false
+	})
+	if len(groups) == 0 {
+		return false, fmt.Errorf("routes/web.go has no pickle.Routes block")
+	}
+
+	for _, d := range declared {
+		if d.method != method || d.path != path {
+			continue
+		}
+		if d.handler == handler {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s %s is already routed to %s", method, path, d.handler)
+	}
+
+	target := spec.Group
+	if target != "" {
+		target = cleanRoutePath(target)
+		if target == "/" {
+			target = ""
+		}
+		if !hasPathPrefix(path, target) {
+			return false, fmt.Errorf("path %s is not inside group %s", path, target)
+		}
+	}
+	parent := deepestGroup(groups, path)
+	if target != "" {
+		parent = deepestGroup(groups, target)
+	}
+
+	routeLine := func(router, prefix string) string {
+		rel := strings.TrimPrefix(path, prefix)
+		if rel == "" {
+			rel = "/"
+		}
+		args := append([]string{strconv.Quote(rel), handler}, middleware...)
+		return fmt.Sprintf("%s.%s(%s)", router, routerMethod, strings.Join(args, ", "))
+	}
+	insert := "\t" + routeLine(parent.router, parent.prefix) + "\n"
+	if target != "" && parent.prefix != target {
+		insert = fmt.Sprintf("\n\t%s.Group(%q, func(%s %s) {\n%s\n})\n",
+			parent.router, strings.TrimPrefix(target, parent.prefix), parent.router, parent.routerType,
+			routeLine(parent.router, target))
+	}
+
+	type edit struct {
+		offset int
+		text   string
+	}
+	edits := []edit{{fset.Position(parent.body.Rbrace).Offset, insert}}
+	if needsMiddlewareImport && !middlewareImported {
+		mwImport := strings.TrimSuffix(controllersImport, "/controllers") + "/middleware"
+		for _, imp := range file.Imports {
+			if p, _ := strconv.Unquote(imp.Path.Value); p == controllersImport {
+				edits = append(edits, edit{fset.Position(imp.End()).Offset, "\n\t" + strconv.Quote(mwImport)})
+			}
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].offset > edits[j].offset })
+	out := string(src)
+	for _, e := range edits {
+		out = out[:e.offset] + e.text + out[e.offset:]
+	}
+
+	formatted, err := format.Source([]byte(out))
+	if err != nil {
+		return false, fmt.Errorf("formatting routes/web.go: %w", err)
+	}
+	return true, os.WriteFile(routesPath, formatted, 0o644)
+}
+
+// newRouteGroup wraps the func(r *pickle.Router) body of a Routes or Group
+// call. It returns nil for a literal without a single router parameter.
+func newRouteGroup(prefix string, fn *ast.FuncLit, src []byte, fset *token.FileSet) *routeGroup {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 {
+		return nil
+	}
+	typ := params[0].Type
+	return &routeGroup{
+		prefix:     prefix,
+		router:     params[0].Names[0].Name,
+		routerType: string(src[fset.Position(typ.Pos()).Offset:fset.Position(typ.End()).Offset]),
+		body:       fn.Body,
+	}
+}
+
+// collectRouteGroup records g, its nested groups and the routes each of them
+// declares, with full paths.
+func collectRouteGroup(g *routeGroup, src []byte, fset *token.FileSet, groups []*routeGroup, declared []declaredRoute) ([]*routeGroup, []declaredRoute) {
+	groups = append(groups, g)
+	for _, stmt := range g.body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		// Step through chained calls like r.Get(...).Name("posts.show").
+		for {
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				break
+			}
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == g.router {
+				groups, declared = collectRouterCall(g, sel.Sel.Name, call, src, fset, groups, declared)
+				break
+			}
+			inner, ok := sel.X.(*ast.CallExpr)
+			if !ok {
+				break
+			}
+			call = inner
+		}
+	}
+	return groups, declared
+}
+
+func collectRouterCall(g *routeGroup, name string, call *ast.CallExpr, src []byte, fset *token.FileSet, groups []*routeGroup, declared []declaredRoute) ([]*routeGroup, []declaredRoute) {
+	if len(call.Args) < 2 {
+		return groups, declared
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return groups, declared
+	}
+	prefix, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return groups, declared
+	}
+	full := g.prefix + prefix
+	switch name {
+	case "Group":
+		if fn, ok := call.Args[1].(*ast.FuncLit); ok {
+			if child := newRouteGroup(strings.TrimRight(full, "/"), fn, src, fset); child != nil {
+				return collectRouteGroup(child, src, fset, groups, declared)
+			}
+		}
+	case "Resource":
+		ctrl := types.ExprString(call.Args[1])
+		declared = append(declared,
+			declaredRoute{"GET", cleanRoutePath(full), ctrl + ".Index"},
+			declaredRoute{"GET", cleanRoutePath(full + "/:id"), ctrl + ".Show"},
+			declaredRoute{"POST", cleanRoutePath(full), ctrl + ".Store"},
+			declaredRoute{"PUT", cleanRoutePath(full + "/:id"), ctrl + ".Update"},
+			declaredRoute{"DELETE", cleanRoutePath(full + "/:id"), ctrl + ".Destroy"},
+		)
+	default:
+		for method, routerMethod := range routeMethods {
+			if routerMethod == name {
+				declared = append(declared, declaredRoute{method, cleanRoutePath(full), types.ExprString(call.Args[1])})
+			}
+		}
+	}
+	return groups, declared
+}
+
+// deepestGroup returns the group with the longest prefix that contains path.
+// Of two equal candidates the later wins, so routes land in the last
+// pickle.Routes block like AddResourceRoute's.
+func deepestGroup(groups []*routeGroup, path string) *routeGroup {
+	var best *routeGroup
+	for _, g := range groups {
+		if hasPathPrefix(path, g.prefix) && (best == nil || len(g.prefix) >= len(best.prefix)) {
+			best = g
+		}
+	}
+	return best
+}
+
+// hasPathPrefix reports whether prefix matches whole leading segments of path.
+func hasPathPrefix(path, prefix string) bool {
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// cleanRoutePath gives a route path a leading slash and drops any trailing
+// one, matching how the router registers paths.
+func cleanRoutePath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	return "/" + strings.Trim(p, "/")
+}
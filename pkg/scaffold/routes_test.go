@@ -0,0 +1,144 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRoutesSource = `package routes
+
+import (
+	pickle "example.com/app/app/http"
+	"example.com/app/app/http/controllers"
+)
+
+var API = pickle.Routes(func(r *pickle.Router) {
+	r.Get("/health", controllers.HealthController{}.Show)
+
+	// Public API.
+	r.Group("/api", func(r *pickle.Router) {
+		r.Get("/", controllers.WelcomeController{}.Index)
+
+		r.Group("/posts", func(r *pickle.Router) {
+			r.Get("/:id", controllers.PostController{}.Show).Name("show")
+		})
+		r.Resource("/users", controllers.UserController{})
+	})
+})
+`
+
+func writeTestRoutes(t *testing.T, src string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	routesPath := filepath.Join(dir, "routes", "web.go")
+	os.MkdirAll(filepath.Dir(routesPath), 0o755)
+	os.WriteFile(routesPath, []byte(src), 0o644)
+	return dir, routesPath
+}
+
+func TestAddRouteInsertsIntoDeepestGroup(t *testing.T) {
+	dir, routesPath := writeTestRoutes(t, testRoutesSource)
+
+	added, err := AddRoute(dir, RouteSpec{Method: "post", Path: "/api/posts", Controller: "Post", Action: "Store"})
+	if err != nil || !added {
+		t.Fatalf("AddRoute = %v, %v", added, err)
+	}
+	data, _ := os.ReadFile(routesPath)
+	src := string(data)
+	want := "\t\t\tr.Get(\"/:id\", controllers.PostController{}.Show).Name(\"show\")\n\t\t\tr.Post(\"/\", controllers.PostController{}.Store)\n\t\t})"
+	if !strings.Contains(src, want) {
+		t.Fatalf("route not added to the /api/posts group:\n%s", src)
+	}
+	if !strings.Contains(src, "\t// Public API.\n") {
+		t.Errorf("comments should survive the rewrite:\n%s", src)
+	}
+}
+
+func TestAddRouteIsIdempotent(t *testing.T) {
+	dir, routesPath := writeTestRoutes(t, testRoutesSource)
+
+	spec := RouteSpec{Method: "PUT", Path: "/api/posts/:id", Controller: "PostController", Action: "Update"}
+	if added, err := AddRoute(dir, spec); err != nil || !added {
+		t.Fatalf("AddRoute = %v, %v", added, err)
+	}
+	first, _ := os.ReadFile(routesPath)
+	if added, err := AddRoute(dir, spec); err != nil || added {
+		t.Fatalf("second AddRoute = %v, %v; want no-op", added, err)
+	}
+	second, _ := os.ReadFile(routesPath)
+	if string(first) != string(second) {
+		t.Errorf("no-op AddRoute rewrote the file:\n%s", second)
+	}
+
+	// Routes declared by r.Resource and nested groups count as registered.
+	for _, spec := range []RouteSpec{
+		{Method: "GET", Path: "/api/users/:id", Controller: "User", Action: "Show"},
+		{Method: "GET", Path: "/api/posts/:id/", Controller: "Post", Action: "Show"},
+		{Method: "GET", Path: "/api", Controller: "Welcome", Action: "Index"},
+	} {
+		if added, err := AddRoute(dir, spec); err != nil || added {
+			t.Errorf("AddRoute(%s %s) = %v, %v; want existing route", spec.Method, spec.Path, added, err)
+		}
+	}
+}
+
+func TestAddRouteRejectsConflictingHandler(t *testing.T) {
+	dir, _ := writeTestRoutes(t, testRoutesSource)
+
+	_, err := AddRoute(dir, RouteSpec{Method: "GET", Path: "/api/users", Controller: "Admin", Action: "Users"})
+	if err == nil || !strings.Contains(err.Error(), "controllers.UserController{}.Index") {
+		t.Fatalf("err = %v, want conflict naming the existing handler", err)
+	}
+}
+
+func TestAddRouteCreatesGroupAndMiddlewareImport(t *testing.T) {
+	dir, routesPath := writeTestRoutes(t, testRoutesSource)
+
+	added, err := AddRoute(dir, RouteSpec{
+		Method:     "DELETE",
+		Path:       "/api/comments/:id",
+		Group:      "/api/comments",
+		Controller: "CommentController",
+		Action:     "Destroy",
+		Middleware: []string{"Auth", "middleware.RateLimit"},
+	})
+	if err != nil || !added {
+		t.Fatalf("AddRoute = %v, %v", added, err)
+	}
+	data, _ := os.ReadFile(routesPath)
+	src := string(data)
+	want := "\t\tr.Group(\"/comments\", func(r *pickle.Router) {\n\t\t\tr.Delete(\"/:id\", controllers.CommentController{}.Destroy, middleware.Auth, middleware.RateLimit)\n\t\t})\n\t})\n})\n"
+	if !strings.Contains(src, want) {
+		t.Fatalf("group not created inside /api:\n%s", src)
+	}
+	if !strings.Contains(src, "\t\"example.com/app/app/http/middleware\"\n") {
+		t.Errorf("middleware import not added:\n%s", src)
+	}
+
+	// The new group is found on the next call rather than created again.
+	if added, err := AddRoute(dir, RouteSpec{Method: "GET", Path: "/api/comments/:id", Group: "/api/comments", Controller: "Comment", Action: "Show"}); err != nil || !added {
+		t.Fatalf("AddRoute into created group = %v, %v", added, err)
+	}
+	data, _ = os.ReadFile(routesPath)
+	if n := strings.Count(string(data), `r.Group("/comments"`); n != 1 {
+		t.Errorf("group /comments declared %d times:\n%s", n, data)
+	}
+}
+
+func TestAddRouteValidatesInput(t *testing.T) {
+	dir, _ := writeTestRoutes(t, testRoutesSource)
+
+	for _, spec := range []RouteSpec{
+		{Method: "TRACE", Path: "/x", Controller: "X", Action: "Y"},
+		{Method: "GET", Path: "", Controller: "X", Action: "Y"},
+		{Method: "GET", Path: "/x", Controller: "X-Y", Action: "Y"},
+		{Method: "GET", Path: "/x", Controller: "X", Action: "Y", Middleware: []string{"bad name"}},
+		{Method: "GET", Path: "/x", Group: "/api", Controller: "X", Action: "Y"},
+	} {
+		if _, err := AddRoute(dir, spec); err == nil {
+			t.Errorf("AddRoute(%+v) should fail", spec)
+		}
+	}
+}
@@ -4,29 +4,56 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/shortontech/pickle/pkg/names"
 )
 
+// Options selects optional parts of a new project.
+type Options struct {
+	// Docker adds a Dockerfile and a docker-compose.yml that runs the app
+	// against a Postgres service using the same DB_* settings as .env.
+	Docker bool
+}
+
 // Create scaffolds a new Pickle project in targetDir with the given module name.
 func Create(moduleName, targetDir string) error {
+	return CreateWithOptions(moduleName, targetDir, Options{})
+}
+
+// CreateWithOptions scaffolds a new Pickle project like Create, adding the
+// optional files selected by opts.
+func CreateWithOptions(moduleName, targetDir string, opts Options) error {
 	ts := time.Now().Format("2006_01_02_150405")
 
+	// The Postgres image refuses to start without a password, so Docker
+	// projects get one in .env that the compose file also reads.
+	dbPassword := ""
+	if opts.Docker {
+		dbPassword = "postgres"
+	}
+
 	files := map[string]string{
 		".gitignore":         tmplGitignore(),
 		"go.mod":             tmplGoMod(moduleName),
-		".env":               tmplDotEnv(),
+		".env":               tmplDotEnv(dbPassword),
 		"cmd/server/main.go": tmplMain(moduleName),
 		"config/app.go":      tmplConfigApp(),
 		"config/database.go": tmplConfigDatabase(),
 		"routes/web.go":      tmplRoutes(moduleName),
 		"app/http/controllers/welcome_controller.go":           tmplWelcomeController(moduleName),
+		"app/http/controllers/health_controller.go":            tmplHealthController(moduleName),
 		"app/http/middleware/auth.go":                          tmplAuthMiddleware(moduleName),
 		"app/http/requests/login.go":                           tmplLoginRequest(),
 		"database/migrations/" + ts + "_create_users_table.go": tmplMigration(ts),
 	}
+	if opts.Docker {
+		files["Dockerfile"] = tmplDockerfile()
+		files[".dockerignore"] = tmplDockerignore()
+		files["docker-compose.yml"] = tmplDockerCompose()
+	}
 
 	// Create app/commands/ directory so the generator emits commands/pickle_gen.go
 	if err := os.MkdirAll(filepath.Join(targetDir, "app", "commands"), 0o755); err != nil {
@@ -83,6 +110,25 @@ func MakeMiddleware(name, projectDir, moduleName string) (string, error) {
 	return writeScaffold(projectDir, relPath, tmplMakeMiddleware(pascal, moduleName))
 }
 
+// MakeAccessPolicy scaffolds an authorization policy in app/policies/. Unlike
+// the role policies MakePolicy writes to database/policies/, an access policy
+// is plain Go deciding whether a user may act on a resource.
+func MakeAccessPolicy(name, projectDir, moduleName string) (string, error) {
+	if err := sanitizeName(name); err != nil {
+		return "", err
+	}
+	name = strings.TrimSuffix(name, "Policy")
+	pascal := names.SnakeToPascal(name)
+	snake := names.PascalToSnake(pascal)
+	if strings.Contains(name, "_") {
+		snake = strings.ToLower(name)
+		pascal = names.SnakeToPascal(name)
+	}
+	fileName := snake + "_policy.go"
+	relPath := filepath.Join("app", "policies", fileName)
+	return writeScaffold(projectDir, relPath, tmplMakeAccessPolicy(pascal+"Policy", moduleName))
+}
+
 // MakeRequest scaffolds a new request file.
 func MakeRequest(name, projectDir, moduleName string) (string, error) {
 	if err := sanitizeName(name); err != nil {
@@ -102,9 +148,20 @@ func MakeRequest(name, projectDir, moduleName string) (string, error) {
 
 // MakeMigration scaffolds a new migration file.
 func MakeMigration(name, projectDir string) (string, error) {
+	return MakeMigrationWithColumns(name, projectDir, "")
+}
+
+// MakeMigrationWithColumns scaffolds a new migration file whose CreateTable
+// body pre-declares the columns described by the --columns mini-DSL, e.g.
+// "title:string,body:text,user_id:uuid:fk=users.id".
+func MakeMigrationWithColumns(name, projectDir, columns string) (string, error) {
 	if err := sanitizeName(name); err != nil {
 		return "", err
 	}
+	cols, err := parseColumnSpecs(columns)
+	if err != nil {
+		return "", err
+	}
 	snake := names.PascalToSnake(name)
 	if strings.Contains(name, "_") {
 		snake = strings.ToLower(name)
@@ -117,7 +174,7 @@ func MakeMigration(name, projectDir string) (string, error) {
 	// Infer table name from description like "create_posts_table" → "posts"
 	tableName := inferTableName(snake)
 
-	return writeScaffold(projectDir, relPath, tmplMakeMigration(structName, tableName))
+	return writeScaffold(projectDir, relPath, tmplMakeMigration(structName, tableName, cols...))
 }
 
 // MakeJob scaffolds a new job file.
@@ -137,6 +194,38 @@ func MakeJob(name, projectDir, moduleName string) (string, error) {
 	return writeScaffold(projectDir, relPath, tmplMakeJob(structName))
 }
 
+// commandNameRe matches a CLI command name: lowercase words joined by ':',
+// '-' or '_', such as report:daily.
+var commandNameRe = regexp.MustCompile(`^[a-z][a-z0-9]*([:_-][a-z0-9]+)*$`)
+
+// builtinCommands are the names the generated commands package already
+// registers. A user command with one of them would replace the built-in.
+var builtinCommands = map[string]bool{
+	"migrate": true, "migrate:rollback": true, "migrate:fresh": true, "migrate:status": true, "migrate:sql": true,
+	"db:seed": true, "policies:status": true, "policies:rollback": true, "rls:status": true,
+}
+
+// MakeCommand scaffolds a CLI command in app/commands. name is what users
+// type after the app binary, such as "report:daily"; a Go-style name like
+// DailyReport becomes "daily-report". The next pickle generate registers the
+// command in commands/pickle_gen.go.
+func MakeCommand(name, projectDir string) (string, error) {
+	cliName := name
+	if name != "" && name[0] >= 'A' && name[0] <= 'Z' {
+		cliName = strings.ReplaceAll(names.PascalToSnake(strings.TrimSuffix(name, "Command")), "_", "-")
+	}
+	if !commandNameRe.MatchString(cliName) {
+		return "", fmt.Errorf("invalid command name %q: use lowercase words separated by ':', '-' or '_', such as report:daily", name)
+	}
+	if builtinCommands[cliName] {
+		return "", fmt.Errorf("%q is a built-in command", cliName)
+	}
+	snake := strings.NewReplacer(":", "_", "-", "_").Replace(cliName)
+	structName := names.SnakeToPascal(snake) + "Command"
+	relPath := filepath.Join("app", "commands", snake+"_command.go")
+	return writeScaffold(projectDir, relPath, tmplMakeCommand(structName, cliName))
+}
+
 // MakeSeeder scaffolds a root scenario in database/seeders.
 func MakeSeeder(name, projectDir, moduleName string) (string, error) {
 	if err := sanitizeName(name); err != nil {
@@ -207,6 +296,27 @@ func (j ` + structName + `) Handle() error {
 `
 }
 
+func tmplMakeCommand(structName, cliName string) string {
+	return `package commands
+
+import "fmt"
+
+// ` + structName + ` runs as: ./app ` + cliName + ` [args...]
+type ` + structName + ` struct{}
+
+func (c ` + structName + `) Name() string        { return "` + cliName + `" }
+func (c ` + structName + `) Description() string { return "TODO: describe ` + cliName + `" }
+
+// Run receives the arguments after the command name. Config is loaded and
+// models.DB is open by the time it is called.
+func (c ` + structName + `) Run(args []string) error {
+	// TODO: implement ` + cliName + `
+	fmt.Println("` + cliName + `", args)
+	return nil
+}
+`
+}
+
 // sanitizeName rejects names containing path traversal sequences.
 // Forward slashes are allowed for subdirectory scaffolding (e.g. "admin/User").
 func sanitizeName(name string) error {
@@ -288,7 +398,7 @@ func (c `+structName+`) Index(ctx *pickle.Context) pickle.Response {
 }
 
 func (c `+structName+`) Show(ctx *pickle.Context) pickle.Response {
-	// TODO: show resource by ctx.Param("id")
+	// TODO: show resource by ctx.ParamUUID("id") or ctx.ParamInt64("id")
 	return ctx.JSON(200, nil)
 }
 
@@ -321,6 +431,43 @@ func `+funcName+`(ctx *pickle.Context, next func() pickle.Response) pickle.Respo
 `, moduleName)
 }
 
+func tmplMakeAccessPolicy(structName, moduleName string) string {
+	return r(`package policies
+
+import pickle "{{.ModuleName}}/app/http"
+
+// `+structName+` decides who may act on a resource. Controllers check it
+// with ctx.Authorize:
+//
+//	if resp, ok := ctx.Authorize(policies.`+structName+`{}.Update(ctx.Auth(), record)); !ok {
+//		return resp
+//	}
+//
+// Every method denies by default.
+type `+structName+` struct{}
+
+// View reports whether user may read resource.
+func (`+structName+`) View(user *pickle.AuthInfo, resource any) bool {
+	return false
+}
+
+// Create reports whether user may create a new resource.
+func (`+structName+`) Create(user *pickle.AuthInfo) bool {
+	return false
+}
+
+// Update reports whether user may modify resource.
+func (`+structName+`) Update(user *pickle.AuthInfo, resource any) bool {
+	return false
+}
+
+// Delete reports whether user may delete resource.
+func (`+structName+`) Delete(user *pickle.AuthInfo, resource any) bool {
+	return false
+}
+`, moduleName)
+}
+
 func tmplMakeRequest(structName string) string {
 	return `package requests
 
@@ -331,7 +478,11 @@ type ` + structName + ` struct {
 `
 }
 
-func tmplMakeMigration(structName, tableName string) string {
+func tmplMakeMigration(structName, tableName string, columns ...string) string {
+	var body strings.Builder
+	for _, c := range columns {
+		body.WriteString("\t\t" + c + "\n")
+	}
 	return fmt.Sprintf(`package migrations
 
 type %s struct {
@@ -340,15 +491,15 @@ type %s struct {
 
 func (m *%s) Up() {
 	m.CreateTable("%s", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("gen_random_uuid()")
-		t.Timestamps()
+		t.UUID("id").PrimaryKey().DefaultRaw("gen_random_uuid()")
+%s		t.Timestamps()
 	})
 }
 
 func (m *%s) Down() {
 	m.DropTableIfExists("%s")
 }
-`, structName, structName, tableName, structName, tableName)
+`, structName, structName, tableName, body.String(), structName, tableName)
 }
 
 func r(tmpl, moduleName string) string {
@@ -362,7 +513,7 @@ go 1.23
 `, mod)
 }
 
-func tmplDotEnv() string {
+func tmplDotEnv(dbPassword string) string {
 	return `APP_NAME=myapp
 APP_ENV=local
 APP_DEBUG=true
@@ -374,7 +525,84 @@ DB_HOST=127.0.0.1
 DB_PORT=5432
 DB_DATABASE=myapp
 DB_USERNAME=postgres
-DB_PASSWORD=
+DB_PASSWORD=` + dbPassword + `
+
+# Connection pool. Leave blank for the driver defaults (pgsql: 25 open,
+# 10 idle, 30m lifetime). Keep DB_MAX_OPEN_CONNS times the number of app
+# instances below the database's max_connections.
+DB_MAX_OPEN_CONNS=
+DB_MAX_IDLE_CONNS=
+DB_CONN_MAX_LIFETIME=
+`
+}
+
+func tmplDockerfile() string {
+	return `# Build the server binary, then copy it into a minimal runtime image.
+FROM golang:1.23-alpine AS build
+WORKDIR /src
+COPY go.mod go.sum ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" -o /out/server ./cmd/server
+
+FROM gcr.io/distroless/static-debian12:nonroot
+COPY --from=build /out/server /server
+EXPOSE 8080
+ENTRYPOINT ["/server"]
+`
+}
+
+func tmplDockerignore() string {
+	return `.env
+.git
+*.sqlite
+`
+}
+
+// tmplDockerCompose wires the app to Postgres. Compose reads .env for the
+// ${...} values, so the database it creates is the one config/database.go
+// connects to, and the published port lets pickle migrate reach it from the
+// host at DB_HOST=127.0.0.1.
+func tmplDockerCompose() string {
+	return `services:
+  app:
+    build: .
+    ports:
+      - "${APP_PORT:-8080}:${APP_PORT:-8080}"
+    environment:
+      APP_NAME: ${APP_NAME:-myapp}
+      APP_ENV: ${APP_ENV:-local}
+      APP_DEBUG: ${APP_DEBUG:-true}
+      APP_PORT: ${APP_PORT:-8080}
+      APP_URL: ${APP_URL:-http://localhost:8080}
+      DB_CONNECTION: pgsql
+      DB_HOST: db
+      DB_PORT: "5432"
+      DB_DATABASE: ${DB_DATABASE:-myapp}
+      DB_USERNAME: ${DB_USERNAME:-postgres}
+      DB_PASSWORD: ${DB_PASSWORD:-postgres}
+    depends_on:
+      db:
+        condition: service_healthy
+
+  db:
+    image: postgres:16-alpine
+    ports:
+      - "${DB_PORT:-5432}:5432"
+    environment:
+      POSTGRES_DB: ${DB_DATABASE:-myapp}
+      POSTGRES_USER: ${DB_USERNAME:-postgres}
+      POSTGRES_PASSWORD: ${DB_PASSWORD:-postgres}
+    volumes:
+      - pgdata:/var/lib/postgresql/data
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U $${POSTGRES_USER} -d $${POSTGRES_DB}"]
+      interval: 2s
+      timeout: 5s
+      retries: 15
+
+volumes:
+  pgdata:
 `
 }
 
@@ -411,7 +639,7 @@ func app() AppConfig {
 	return AppConfig{
 		Name:  Env("APP_NAME", "myapp"),
 		Env:   Env("APP_ENV", "local"),
-		Debug: Env("APP_DEBUG", "true") == "true",
+		Debug: EnvBool("APP_DEBUG", true),
 		Port:  Env("APP_PORT", "8080"),
 		URL:   Env("APP_URL", "http://localhost:8080"),
 	}
@@ -433,16 +661,22 @@ func database() DatabaseConfig {
 		Default: Env("DB_CONNECTION", "pgsql"),
 		Connections: map[string]ConnectionConfig{
 			"pgsql": {
-				Driver:   "pgsql",
-				Host:     Env("DB_HOST", "127.0.0.1"),
-				Port:     Env("DB_PORT", "5432"),
-				Name:     Env("DB_DATABASE", "myapp"),
-				User:     Env("DB_USERNAME", "postgres"),
-				Password: Env("DB_PASSWORD", ""),
+				Driver:          "pgsql",
+				Host:            Env("DB_HOST", "127.0.0.1"),
+				Port:            Env("DB_PORT", "5432"),
+				Name:            Env("DB_DATABASE", "myapp"),
+				User:            Env("DB_USERNAME", "postgres"),
+				Password:        Env("DB_PASSWORD", ""),
+				MaxOpenConns:    EnvInt("DB_MAX_OPEN_CONNS", 0),
+				MaxIdleConns:    EnvInt("DB_MAX_IDLE_CONNS", 0),
+				ConnMaxLifetime: EnvDuration("DB_CONN_MAX_LIFETIME", 0),
 			},
 			"sqlite": {
-				Driver: "sqlite",
-				Name:   Env("DB_DATABASE", "database.sqlite"),
+				Driver:          "sqlite",
+				Name:            Env("DB_DATABASE", "database.sqlite"),
+				MaxOpenConns:    EnvInt("DB_MAX_OPEN_CONNS", 0),
+				MaxIdleConns:    EnvInt("DB_MAX_IDLE_CONNS", 0),
+				ConnMaxLifetime: EnvDuration("DB_CONN_MAX_LIFETIME", 0),
 			},
 		},
 	}
@@ -459,9 +693,11 @@ import (
 )
 
 var API = pickle.Routes(func(r *pickle.Router) {
+	r.Get("/health", controllers.HealthController{}.Show)
+
 	r.Group("/api", func(r *pickle.Router) {
 		r.Get("/", controllers.WelcomeController{}.Index)
-	})
+	}, pickle.Recover())
 })
 `, mod)
 }
@@ -485,6 +721,28 @@ func (c WelcomeController) Index(ctx *pickle.Context) pickle.Response {
 `, mod)
 }
 
+func tmplHealthController(mod string) string {
+	return r(`package controllers
+
+import (
+	pickle "{{.ModuleName}}/app/http"
+	"{{.ModuleName}}/app/models"
+)
+
+// HealthController answers readiness probes from load balancers and
+// container orchestrators.
+type HealthController struct {
+	pickle.Controller
+}
+
+// Show responds 200 while the database answers a ping and 503 otherwise.
+// models.DB is read per request because it is opened after routes are built.
+func (c HealthController) Show(ctx *pickle.Context) pickle.Response {
+	return pickle.HealthHandler(models.DB)(ctx)
+}
+`, mod)
+}
+
 func tmplAuthMiddleware(mod string) string {
 	return r(`package middleware
 
@@ -524,10 +782,10 @@ type %s struct {
 
 func (m *%s) Up() {
 	m.CreateTable("users", func(t *Table) {
-		t.UUID("id").PrimaryKey().Default("gen_random_uuid()")
+		t.UUID("id").PrimaryKey().DefaultRaw("gen_random_uuid()")
 		t.String("name").NotNull()
 		t.String("email").NotNull().Unique()
-		t.String("password").NotNull()
+		t.String("password").NotNull().Hidden()
 		t.Timestamps()
 	})
 }
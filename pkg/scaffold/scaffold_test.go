@@ -1,6 +1,8 @@
 package scaffold
 
 import (
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -163,6 +165,37 @@ func TestMakeMiddlewareContent(t *testing.T) {
 	}
 }
 
+func TestMakeAccessPolicy(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := MakeAccessPolicy("PostPolicy", dir, "myapp.com/proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join("app", "policies", "post_policy.go"); relPath != want {
+		t.Errorf("got %q, want %q", relPath, want)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, relPath))
+	s := string(content)
+	for _, want := range []string{
+		`import pickle "myapp.com/proj/app/http"`,
+		"type PostPolicy struct{}",
+		"func (PostPolicy) View(user *pickle.AuthInfo, resource any) bool {",
+		"func (PostPolicy) Create(user *pickle.AuthInfo) bool {",
+		"func (PostPolicy) Update(user *pickle.AuthInfo, resource any) bool {",
+		"func (PostPolicy) Delete(user *pickle.AuthInfo, resource any) bool {",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("missing %q in:\n%s", want, s)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), relPath, content, 0); err != nil {
+		t.Errorf("scaffolded policy does not parse: %v", err)
+	}
+	if _, err := MakeAccessPolicy("../evil", dir, "myapp.com/proj"); err == nil {
+		t.Error("expected error for path traversal name")
+	}
+}
+
 func TestMakeMiddlewareInvalidName(t *testing.T) {
 	dir := t.TempDir()
 	_, err := MakeMiddleware("../evil", dir, "example.com/app")
@@ -257,6 +290,92 @@ func TestMakeMigrationValid(t *testing.T) {
 	}
 }
 
+func TestMakeMigrationWithColumns(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := MakeMigrationWithColumns("create_posts_table", dir, "title:string,body:text,user_id:uuid:fk=users.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		`t.String("title").NotNull()`,
+		`t.Text("body").NotNull()`,
+		`t.UUID("user_id").NotNull().ForeignKey("users", "id")`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Index(content, `t.String("title")`) > strings.Index(content, "t.Timestamps()") {
+		t.Error("expected parsed columns before t.Timestamps()")
+	}
+}
+
+func TestParseColumnSpecsModifiers(t *testing.T) {
+	got, err := parseColumnSpecs("slug:string=100:unique, price:decimal=12.4, bio:text:nullable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`t.String("slug", 100).NotNull().Unique()`,
+		`t.Decimal("price", 12, 4).NotNull()`,
+		`t.Text("bio").Nullable()`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseColumnSpecsRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"title", "title:varchar", "Title:string", "user_id:uuid:fk=users;drop", "bio:text:indexed", "flag:bool=1"} {
+		if _, err := parseColumnSpecs(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestMakeCommand(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := MakeCommand("report:daily", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relPath != filepath.Join("app", "commands", "report_daily_command.go") {
+		t.Errorf("got %q", relPath)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, relPath))
+	src := string(content)
+	if _, err := parser.ParseFile(token.NewFileSet(), relPath, src, 0); err != nil {
+		t.Fatalf("command does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{"type ReportDailyCommand struct{}", `return "report:daily"`, "Run(args []string) error"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestMakeCommandNames(t *testing.T) {
+	dir := t.TempDir()
+	relPath, err := MakeCommand("SendRemindersCommand", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, relPath))
+	if !strings.Contains(string(content), `return "send-reminders"`) || !strings.HasSuffix(relPath, "send_reminders_command.go") {
+		t.Errorf("got %s:\n%s", relPath, content)
+	}
+	for _, name := range []string{"migrate", "db:seed", "report daily", "report::daily", "../evil", ""} {
+		if _, err := MakeCommand(name, dir); err == nil {
+			t.Errorf("MakeCommand(%q) should fail", name)
+		}
+	}
+}
+
 func TestMakeSeeder(t *testing.T) {
 	dir := t.TempDir()
 	relPath, err := MakeSeeder("CRM", dir, "example.com/crm")
@@ -413,6 +532,7 @@ func TestCreate(t *testing.T) {
 		"config/database.go",
 		"routes/web.go",
 		"app/http/controllers/welcome_controller.go",
+		"app/http/controllers/health_controller.go",
 		"app/http/middleware/auth.go",
 		"app/http/requests/login.go",
 	}
@@ -508,6 +628,12 @@ func TestCreateRoutesContent(t *testing.T) {
 	if !strings.Contains(s, "WelcomeController") {
 		t.Errorf("expected WelcomeController in routes, got:\n%s", s)
 	}
+	if !strings.Contains(s, `r.Get("/health", controllers.HealthController{}.Show)`) {
+		t.Errorf("expected /health route, got:\n%s", s)
+	}
+	if !strings.Contains(s, "}, pickle.Recover())") {
+		t.Errorf("expected the /api group wrapped with Recover, got:\n%s", s)
+	}
 }
 
 func TestCreateDotEnvContent(t *testing.T) {
@@ -525,6 +651,95 @@ func TestCreateDotEnvContent(t *testing.T) {
 	}
 }
 
+func TestCreateWithoutDockerOmitsDockerFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := Create("myapp", dir); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	for _, rel := range []string{"Dockerfile", ".dockerignore", "docker-compose.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			t.Errorf("%s should only be created with Docker", rel)
+		}
+	}
+	env, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if !strings.Contains(string(env), "DB_PASSWORD=\n") {
+		t.Errorf("expected empty DB_PASSWORD, got:\n%s", env)
+	}
+}
+
+func TestCreateConfiguresConnectionPool(t *testing.T) {
+	dir := t.TempDir()
+	if err := Create("myapp", dir); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	env, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	dbConfig, _ := os.ReadFile(filepath.Join(dir, "config", "database.go"))
+	for _, key := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME"} {
+		if !strings.Contains(string(env), key+"=\n") {
+			t.Errorf(".env missing %s", key)
+		}
+		if !strings.Contains(string(dbConfig), `"`+key+`"`) {
+			t.Errorf("config/database.go does not read %s", key)
+		}
+	}
+}
+
+func TestCreateWithDocker(t *testing.T) {
+	dir := t.TempDir()
+	if err := CreateWithOptions("myapp", dir, Options{Docker: true}); err != nil {
+		t.Fatalf("CreateWithOptions failed: %v", err)
+	}
+
+	dockerfile, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatalf("Dockerfile: %v", err)
+	}
+	for _, want := range []string{"AS build", "./cmd/server", "COPY --from=build", "ENTRYPOINT"} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile missing %q:\n%s", want, dockerfile)
+		}
+	}
+
+	ignore, _ := os.ReadFile(filepath.Join(dir, ".dockerignore"))
+	if !strings.Contains(string(ignore), ".env") {
+		t.Errorf(".dockerignore should exclude .env, got:\n%s", ignore)
+	}
+
+	env, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if !strings.Contains(string(env), "DB_PASSWORD=postgres\n") {
+		t.Errorf("expected DB_PASSWORD=postgres for Docker, got:\n%s", env)
+	}
+
+	// Every DB_* setting config/database.go reads must reach the app and
+	// match what the Postgres service is initialized with.
+	compose, err := os.ReadFile(filepath.Join(dir, "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("docker-compose.yml: %v", err)
+	}
+	cs := string(compose)
+	dbConfig, _ := os.ReadFile(filepath.Join(dir, "config", "database.go"))
+	for _, key := range []string{"DB_CONNECTION", "DB_HOST", "DB_PORT", "DB_DATABASE", "DB_USERNAME", "DB_PASSWORD"} {
+		if !strings.Contains(string(dbConfig), `"`+key+`"`) {
+			t.Fatalf("config/database.go no longer reads %s", key)
+		}
+		if !strings.Contains(cs, "      "+key+":") {
+			t.Errorf("compose app service missing %s", key)
+		}
+	}
+	for _, pair := range [][2]string{
+		{"DB_DATABASE: ${DB_DATABASE:-myapp}", "POSTGRES_DB: ${DB_DATABASE:-myapp}"},
+		{"DB_USERNAME: ${DB_USERNAME:-postgres}", "POSTGRES_USER: ${DB_USERNAME:-postgres}"},
+		{"DB_PASSWORD: ${DB_PASSWORD:-postgres}", "POSTGRES_PASSWORD: ${DB_PASSWORD:-postgres}"},
+	} {
+		if !strings.Contains(cs, pair[0]) || !strings.Contains(cs, pair[1]) {
+			t.Errorf("compose app/db settings out of sync: want %q and %q", pair[0], pair[1])
+		}
+	}
+	if !strings.Contains(cs, `"${DB_PORT:-5432}:5432"`) {
+		t.Errorf("compose should publish Postgres on DB_PORT for host migrations:\n%s", cs)
+	}
+}
+
 func TestTmplMakeControllerContent(t *testing.T) {
 	out := tmplMakeController("FooController", "example.com/app")
 	if !strings.Contains(out, "package controllers") {
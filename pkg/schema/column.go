@@ -1,17 +1,23 @@
 package schema
 
+import "strings"
+
 // Column represents a database column definition.
 type Column struct {
 	Name             string
 	Type             ColumnType
 	Length           int
-	Precision        int
+	Precision        int // decimal digits, or fractional-second digits for a Timestamp with HasPrecision
 	Scale            int
+	HasPrecision     bool // Timestamp precision was set explicitly; 0 is a valid precision
+	WithoutTimeZone  bool // Timestamp is TIMESTAMP rather than TIMESTAMPTZ
 	IsPrimaryKey     bool
 	IsNullable       bool
 	IsUnique         bool
+	IsIndexed        bool // Indexed() — the migration also emits an AddIndex on this column
 	DefaultValue     any
 	HasDefault       bool
+	DefaultIsRaw     bool // DefaultValue is a SQL expression, set by DefaultRaw
 	ForeignKeyTable  string
 	ForeignKeyColumn string
 	IsPublic         bool
@@ -20,11 +26,16 @@ type Column struct {
 	IsEncrypted      bool
 	IsSealed         bool
 	IsUnsafePublic   bool
-	OnDeleteAction   string            // e.g. "CASCADE", "SET NULL" — appended to FK constraint
-	FKMetadataOnly   bool              // FK is for ORM relationship metadata only; no SQL REFERENCES constraint
-	VisibleTo        map[string]bool   // role slugs that can see this column
-	VisibleToSource  map[string]string // role slug → migration ID that added the annotation
-	Seeder           *SeedSpec         // fake-data metadata; never emitted as database DDL
+	IsHidden         bool
+	OnDeleteAction   string              // e.g. "CASCADE", "SET NULL" — appended to FK constraint
+	FKMetadataOnly   bool                // FK is for ORM relationship metadata only; no SQL REFERENCES constraint
+	VisibleTo        map[string]bool     // role slugs that can see this column
+	VisibleToSource  map[string]string   // role slug → migration ID that added the annotation
+	Seeder           *SeedSpec           // fake-data metadata; never emitted as database DDL
+	CheckExpr        string              // CHECK constraint expression, emitted inline
+	CommentText      string              // column comment stored in the database catalog
+	UsingExpr        string              // ChangeColumn conversion expression for Postgres' ALTER COLUMN ... USING
+	TransitionMap    map[string][]string // allowed value changes, state → next states; generates Validate<Column>Transition
 }
 
 func (c *Column) PrimaryKey() *Column {
@@ -47,12 +58,59 @@ func (c *Column) Unique() *Column {
 	return c
 }
 
+// Default sets a literal default value. Strings are quoted, so
+// Default("draft (beta)") stores exactly that text; use DefaultRaw for SQL
+// expressions.
 func (c *Column) Default(value any) *Column {
 	c.DefaultValue = value
 	c.HasDefault = true
+	c.DefaultIsRaw = false
+	return c
+}
+
+// DefaultRaw sets a default SQL expression, emitted unquoted — for example
+// DefaultRaw("gen_random_uuid()") or DefaultRaw("NOW()").
+func (c *Column) DefaultRaw(expr string) *Column {
+	c.DefaultValue = expr
+	c.HasDefault = true
+	c.DefaultIsRaw = true
 	return c
 }
 
+// DefaultIsExpression reports whether the default is emitted as SQL rather
+// than a quoted literal: DefaultRaw defaults, and Default strings that
+// LegacyExpressionDefault still recognizes.
+func (c *Column) DefaultIsExpression() bool {
+	if c.DefaultIsRaw {
+		return true
+	}
+	s, ok := c.DefaultValue.(string)
+	return ok && LegacyExpressionDefault(s)
+}
+
+// LegacyExpressionDefault reports whether a Default string is treated as a
+// SQL expression for backward compatibility with migrations written before
+// DefaultRaw: a function call at the start ("gen_random_uuid()",
+// "now() + interval '1 day'") or a parenthesised expression. Text with a
+// space before the parenthesis, like "draft (beta)", stays a literal. This
+// detection is deprecated; squeeze flags the defaults it applies to.
+func LegacyExpressionDefault(s string) bool {
+	if strings.HasPrefix(s, "(") {
+		return true
+	}
+	for i, r := range s {
+		switch {
+		case r == '(':
+			return i > 0
+		case r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 func (c *Column) ForeignKey(table, column string) *Column {
 	c.ForeignKeyTable = table
 	c.ForeignKeyColumn = column
@@ -91,6 +149,13 @@ func (c *Column) UnsafePublic() *Column {
 	return c
 }
 
+// Hidden marks this column as sensitive. It is never serialized: the generated
+// model omits it from JSON and from the Public() projection.
+func (c *Column) Hidden() *Column {
+	c.IsHidden = true
+	return c
+}
+
 // RoleSees marks this column as visible to the specified role slug.
 func (c *Column) RoleSees(slug string) *Column {
 	if c.VisibleTo == nil {
@@ -112,6 +177,59 @@ func (c *Column) RoleSeesFrom(slug, migrationID string) *Column {
 	return c
 }
 
+// Check adds a CHECK constraint to the column. The expression is emitted
+// verbatim, e.g. Check("amount >= 0").
+func (c *Column) Check(expr string) *Column {
+	c.CheckExpr = expr
+	return c
+}
+
+// Using sets how ChangeColumn converts existing values to the new type on
+// Postgres, e.g. Using("amount_cents / 100.0"). The default casts the old
+// value: "col"::<new type>. Other drivers ignore it.
+func (c *Column) Using(expr string) *Column {
+	c.UsingExpr = expr
+	return c
+}
+
+// Comment attaches a description to the column. It is stored in the
+// database catalog and shown in MCP schema_show and generated model docs.
+func (c *Column) Comment(text string) *Column {
+	c.CommentText = text
+	return c
+}
+
+// Transitions declares the column as a state machine: each key is a state
+// and its value lists the states it may change to. A state that appears
+// only as a target is final. The generated model gets a
+// Validate<Column>Transition(from, to) method that controllers call before
+// saving a change:
+//
+//	t.String("status").Default("draft").Transitions(map[string][]string{
+//		"draft":     {"review", "archived"},
+//		"review":    {"draft", "published"},
+//		"published": {"archived"},
+//	})
+//
+// The transitions are enforced in Go, not by the database.
+func (c *Column) Transitions(transitions map[string][]string) *Column {
+	if c.Type != String && c.Type != Text && c.Type != ColumnType(-1) {
+		panic("pickle: Transitions requires a String or Text column, got " + c.Name)
+	}
+	c.TransitionMap = transitions
+	return c
+}
+
+// Indexed adds an index on the column in the same migration, as if
+// followed by m.AddIndex(table, column). Postgres creates indexes for primary
+// keys and unique columns but not for the referencing side of a foreign key,
+// so chain it onto ForeignKey: t.UUID("user_id").ForeignKey("users", "id").Indexed().
+// Primary key and unique columns are already indexed and get no extra index.
+func (c *Column) Indexed() *Column {
+	c.IsIndexed = true
+	return c
+}
+
 // OnDelete sets the ON DELETE action for a foreign key column (e.g. "CASCADE", "SET NULL").
 func (c *Column) OnDelete(action string) *Column {
 	c.OnDeleteAction = action
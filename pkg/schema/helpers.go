@@ -1,6 +1,29 @@
 package schema
 
-import "strings"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Identifier length limits for the supported dialects. SQLite has none.
+const (
+	PostgresMaxIdentifier = 63
+	MySQLMaxIdentifier    = 64
+)
+
+// ShortenIdentifier returns name unchanged when it fits in maxLen bytes (or
+// maxLen <= 0). Longer names are cut and suffixed with "_" and 8 hex digits of
+// the full name's SHA-256, so two long names sharing a prefix stay distinct
+// instead of colliding after the database truncates them.
+func ShortenIdentifier(name string, maxLen int) string {
+	if maxLen <= 0 || len(name) <= maxLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := "_" + hex.EncodeToString(sum[:4])
+	return name[:maxLen-len(suffix)] + suffix
+}
 
 // singularize does a best-effort conversion of a plural table name to singular.
 // Handles common English plurals. Used internally by flattenRelationships to
@@ -1,10 +1,22 @@
 package schema
 
+import "strings"
+
 // Index represents a database index.
 type Index struct {
 	Table   string
 	Columns []string
 	Unique  bool
+	Name    string // explicit name; empty derives one from the table and columns
+}
+
+// NameWithin returns the index's explicit name, or derives
+// "{table}_{cols}_idx" shortened to fit maxLen (see ShortenIdentifier).
+func (idx *Index) NameWithin(maxLen int) string {
+	if idx.Name != "" {
+		return idx.Name
+	}
+	return ShortenIdentifier(idx.Table+"_"+strings.Join(idx.Columns, "_")+"_idx", maxLen)
 }
 
 // TableOperation represents a schema change recorded by a migration.
@@ -29,6 +41,7 @@ const (
 	OpNoForceRLS
 	OpCreateRLSPolicy
 	OpDropRLSPolicy
+	OpChangeColumn
 )
 
 // Operation records a single schema change.
@@ -40,7 +53,7 @@ type Operation struct {
 	Index          *Index
 	NewName        string // for rename operations
 	OldName        string // for rename operations
-	ColumnName     string // for drop/rename column
+	ColumnName     string // for drop/rename/change column
 	ColumnDef      func(*Table)
 	SQL            string  // for RawSQL operations
 	MetadataColumn *Column // metadata-only alteration; emits no DDL
@@ -65,10 +78,27 @@ func (m *Migration) CreateTable(name string, fn func(*Table)) {
 		Table:    name,
 		TableDef: t,
 	})
+	m.addDeclaredIndexes(name, t)
 	// Flatten nested relationships into additional create table operations
 	m.flattenRelationships(t)
 }
 
+// addDeclaredIndexes emits an AddIndex for each column and table-level
+// foreign key of t marked Indexed(). Primary key and unique columns are
+// indexed by their constraint already.
+func (m *Migration) addDeclaredIndexes(table string, t *Table) {
+	for _, col := range t.Columns {
+		if col.IsIndexed && !col.IsPrimaryKey && !col.IsUnique {
+			m.AddIndex(table, col.Name)
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if fk.IsIndexed {
+			m.AddIndex(table, fk.Columns...)
+		}
+	}
+}
+
 // flattenRelationships walks a table's Relationships and emits OpCreateTable
 // and OpAddIndex operations for each child table, with auto-injected FK columns.
 func (m *Migration) flattenRelationships(parent *Table) {
@@ -108,6 +138,52 @@ func (m *Migration) flattenRelationships(parent *Table) {
 	}
 }
 
+// CreatePivot creates the join table of a many-to-many relationship between
+// tableA and tableB. The table is named after both tables in singular form,
+// sorted ("post_tag" for posts and tags), and has a non-null foreign key
+// column to each ("post_id", "tag_id") that cascades on delete, plus a
+// unique index on the pair. Each key column takes the primary key type of
+// its table if that table is created earlier in this migration, and UUID
+// otherwise. fn, when given, adds further columns such as timestamps.
+func (m *Migration) CreatePivot(tableA, tableB string, fn ...func(*Table)) {
+	if tableA == "" || tableB == "" {
+		panic("pickle: CreatePivot requires two table names")
+	}
+	if tableA == tableB {
+		panic("pickle: CreatePivot needs two different tables; create a self-referencing join table with CreateTable")
+	}
+	if tableB < tableA {
+		tableA, tableB = tableB, tableA
+	}
+	singularA, singularB := singularize(tableA), singularize(tableB)
+	name := singularA + "_" + singularB
+	colA, colB := singularA+"_id", singularB+"_id"
+
+	m.CreateTable(name, func(t *Table) {
+		for _, side := range []struct{ column, table string }{{colA, tableA}, {colB, tableB}} {
+			pkType, pkName := UUID, "id"
+			if created := m.createdTable(side.table); created != nil {
+				pkType, pkName = findPKType(created), findPKName(created)
+			}
+			t.addColumn(side.column, pkType).ForeignKey(side.table, pkName).OnDelete("CASCADE")
+		}
+		for _, f := range fn {
+			f(t)
+		}
+	})
+	m.AddUniqueIndex(name, colA, colB)
+}
+
+// createdTable returns the definition of a table this migration creates.
+func (m *Migration) createdTable(name string) *Table {
+	for _, op := range m.Operations {
+		if op.Type == OpCreateTable && op.Table == name {
+			return op.TableDef
+		}
+	}
+	return nil
+}
+
 func (m *Migration) DropTableIfExists(name string) {
 	m.Operations = append(m.Operations, Operation{
 		Type:  OpDropTableIfExists,
@@ -133,6 +209,11 @@ func (m *Migration) AddColumn(table string, fn func(*Table)) {
 		Table:     table,
 		ColumnDef: fn,
 	})
+	// fn only declares columns, so running it here to find Indexed() ones
+	// doesn't affect the operation above.
+	declared := &Table{Name: table}
+	fn(declared)
+	m.addDeclaredIndexes(table, declared)
 }
 
 // AlterTable allows adding columns and nested relationships to an existing table.
@@ -149,6 +230,7 @@ func (m *Migration) AlterTable(name string, fn func(*Table)) {
 			},
 		})
 	}
+	m.addDeclaredIndexes(name, t)
 	for _, col := range t.AlteredColumns {
 		m.Operations = append(m.Operations, Operation{
 			Type:           OpAlterColumnMetadata,
@@ -161,6 +243,40 @@ func (m *Migration) AlterTable(name string, fn func(*Table)) {
 	m.flattenRelationships(t)
 }
 
+// ChangeColumn redefines existing columns in place: type, length or
+// precision, nullability and default. Each column is declared in full as it
+// should be after the change, so a default left off is dropped:
+//
+//	m.ChangeColumn("users", func(t *schema.Table) {
+//	    t.String("bio", 1000).Nullable()
+//	    t.BigInteger("login_count").NotNull().Default(0)
+//	})
+//
+// Postgres converts existing values with USING "col"::<new type>; set
+// Using for anything a plain cast can't do. Keys, uniqueness, foreign keys,
+// CHECK constraints and encryption can't be changed this way. SQLite can't
+// alter a column at all, so the runner returns an error there.
+func (m *Migration) ChangeColumn(table string, fn func(*Table)) {
+	t := &Table{Name: table}
+	fn(t)
+	for _, col := range t.Columns {
+		switch {
+		case col.IsPrimaryKey, col.IsUnique, col.IsIndexed, col.ForeignKeyTable != "", col.CheckExpr != "":
+			panic("pickle: ChangeColumn " + table + "." + col.Name + " can only change type, nullability and default; add keys, indexes and constraints with AddIndex or RawSQL")
+		case col.IsEncrypted, col.IsSealed:
+			panic("pickle: ChangeColumn " + table + "." + col.Name + " cannot change encryption; add a new column and copy the data")
+		}
+		m.Operations = append(m.Operations, Operation{
+			Type:       OpChangeColumn,
+			Table:      table,
+			ColumnName: col.Name,
+			ColumnDef: func(tmp *Table) {
+				tmp.Columns = append(tmp.Columns, col)
+			},
+		})
+	}
+}
+
 func (m *Migration) DropColumn(table, column string) {
 	m.Operations = append(m.Operations, Operation{
 		Type:       OpDropColumn,
@@ -197,6 +313,16 @@ func (m *Migration) AddIndex(table string, columns ...string) {
 	})
 }
 
+// AddIndexNamed adds an index with an explicit name instead of the derived
+// "{table}_{cols}_idx".
+func (m *Migration) AddIndexNamed(name, table string, columns ...string) {
+	if name == "" {
+		panic("pickle: AddIndexNamed requires a name")
+	}
+	m.AddIndex(table, columns...)
+	m.Operations[len(m.Operations)-1].Index.Name = name
+}
+
 func (m *Migration) AddUniqueIndex(table string, columns ...string) {
 	if len(columns) == 0 {
 		panic("pickle: AddUniqueIndex requires at least one column")
@@ -212,6 +338,15 @@ func (m *Migration) AddUniqueIndex(table string, columns ...string) {
 	})
 }
 
+// AddUniqueIndexNamed adds a unique index with an explicit name.
+func (m *Migration) AddUniqueIndexNamed(name, table string, columns ...string) {
+	if name == "" {
+		panic("pickle: AddUniqueIndexNamed requires a name")
+	}
+	m.AddUniqueIndex(table, columns...)
+	m.Operations[len(m.Operations)-1].Index.Name = name
+}
+
 func (m *Migration) CreateView(name string, fn func(*View)) {
 	v := &View{Name: name}
 	fn(v)
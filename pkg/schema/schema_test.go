@@ -90,6 +90,21 @@ func TestFieldSeederTypeValidation(t *testing.T) {
 	(&Column{Name: "age", Type: Integer}).SeedEmail()
 }
 
+func TestTransitions(t *testing.T) {
+	col := (&Column{Name: "status", Type: String}).Transitions(map[string][]string{"draft": {"published"}})
+	if got := col.TransitionMap["draft"]; len(got) != 1 || got[0] != "published" {
+		t.Fatalf("TransitionMap = %v", col.TransitionMap)
+	}
+	(&Table{}).AlterColumn("status").Transitions(map[string][]string{"draft": {"published"}})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Transitions on a non-string column to panic")
+		}
+	}()
+	(&Column{Name: "priority", Type: Integer}).Transitions(map[string][]string{"1": {"2"}})
+}
+
 func TestAlterTableRecordsSeederMetadataOnly(t *testing.T) {
 	migration := &Migration{}
 	migration.AlterTable("contacts", func(table *Table) {
@@ -117,6 +132,31 @@ func TestDecimalPrecision(t *testing.T) {
 	}
 }
 
+func TestTimestampPrecisionAndZone(t *testing.T) {
+	tbl := &Table{Name: "events"}
+	tbl.Timestamp("logged_at")
+	tbl.TimestampTz("received_at", 6)
+	tbl.TimestampWithoutTimeZone("local_at", 0)
+
+	logged, received, local := tbl.Columns[0], tbl.Columns[1], tbl.Columns[2]
+	if logged.HasPrecision || logged.WithoutTimeZone {
+		t.Errorf("Timestamp: got %+v, want default precision with time zone", logged)
+	}
+	if !received.HasPrecision || received.Precision != 6 || received.WithoutTimeZone || received.Type != Timestamp {
+		t.Errorf("TimestampTz(6): got %+v", received)
+	}
+	if !local.HasPrecision || local.Precision != 0 || !local.WithoutTimeZone || local.Type != Timestamp {
+		t.Errorf("TimestampWithoutTimeZone(0): got %+v", local)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for precision 7")
+		}
+	}()
+	tbl.Timestamp("bad", 7)
+}
+
 func TestStringDefaultLength(t *testing.T) {
 	tbl := &Table{Name: "test"}
 	tbl.String("name")
@@ -236,6 +276,175 @@ func TestMigrationAddIndex(t *testing.T) {
 	}
 }
 
+func TestIndexedEmitsAddIndex(t *testing.T) {
+	m := &Migration{}
+	m.CreateTable("memberships", func(t *Table) {
+		t.UUID("id").PrimaryKey().Indexed()
+		t.UUID("user_id").ForeignKey("users", "id").Indexed()
+		t.String("email").Unique().Indexed()
+		t.BigInteger("organization_id")
+		t.BigInteger("party_id")
+		t.ForeignKey([]string{"organization_id", "party_id"}, "parties", []string{"organization_id", "party_id"}).Indexed()
+	})
+	m.AddColumn("memberships", func(t *Table) {
+		t.UUID("team_id").ForeignKey("teams", "id").Indexed()
+	})
+	m.AlterTable("memberships", func(t *Table) {
+		t.UUID("role_id").ForeignKey("roles", "id").Indexed()
+		t.String("note").Nullable()
+	})
+
+	var indexed []string
+	for _, op := range m.Operations {
+		if op.Type == OpAddIndex {
+			indexed = append(indexed, strings.Join(op.Index.Columns, ","))
+		}
+	}
+	want := []string{"user_id", "organization_id,party_id", "team_id", "role_id"}
+	if strings.Join(indexed, " ") != strings.Join(want, " ") {
+		t.Errorf("indexes = %v, want %v (none for the primary key or unique column)", indexed, want)
+	}
+	if m.Operations[0].Type != OpCreateTable || m.Operations[3].Type != OpAddColumn {
+		t.Errorf("indexes should follow the operation creating their columns: %+v", m.Operations)
+	}
+}
+
+func TestTableIndexFor(t *testing.T) {
+	table := &Table{
+		Name: "memberships",
+		Columns: []*Column{
+			{Name: "id", IsPrimaryKey: true},
+			{Name: "email", IsUnique: true},
+			{Name: "user_id", ForeignKeyTable: "users"},
+			{Name: "team_id", ForeignKeyTable: "teams"},
+		},
+		CompositePrimaryKeys: []string{"organization_id", "party_id"},
+		Indexes:              []*Index{{Columns: []string{"team_id", "created_at"}}},
+	}
+	tests := []struct {
+		cols []string
+		want IndexKind
+	}{
+		{[]string{"id"}, IndexPrimaryKey},
+		{[]string{"email"}, IndexUnique},
+		{[]string{"organization_id"}, IndexPrimaryKey},
+		{[]string{"team_id"}, IndexExplicit},
+		{[]string{"team_id", "created_at"}, IndexExplicit},
+		{[]string{"user_id"}, IndexNone},
+		{[]string{"created_at"}, IndexNone},
+		{[]string{"party_id", "organization_id"}, IndexNone},
+	}
+	for _, tt := range tests {
+		if got := table.IndexFor(tt.cols...); got != tt.want {
+			t.Errorf("IndexFor(%v) = %q, want %q", tt.cols, got, tt.want)
+		}
+	}
+}
+
+func TestForeignKeySelf(t *testing.T) {
+	m := &Migration{}
+	m.CreateTable("categories", func(t *Table) {
+		t.BigInteger("id").PrimaryKey()
+		t.String("name", 100)
+		t.ForeignKeySelf("parent_id", "id").OnDelete("SET NULL")
+	})
+	col := m.Operations[0].TableDef.Columns[2]
+	if col.Name != "parent_id" || col.Type != BigInteger || !col.IsNullable {
+		t.Errorf("unexpected column: %+v", col)
+	}
+	if col.ForeignKeyTable != "categories" || col.ForeignKeyColumn != "id" || col.OnDeleteAction != "SET NULL" {
+		t.Errorf("expected FK to categories.id, got %s.%s", col.ForeignKeyTable, col.ForeignKeyColumn)
+	}
+
+	m = &Migration{}
+	m.CreateTable("employees", func(t *Table) {
+		t.UUID("id").PrimaryKey()
+		t.UUID("manager_id").NotNull()
+		t.ForeignKeySelf("manager_id", "id")
+	})
+	cols := m.Operations[0].TableDef.Columns
+	if len(cols) != 2 || cols[1].ForeignKeyTable != "employees" || cols[1].IsNullable {
+		t.Errorf("expected the declared column to gain the FK, got %+v", cols)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unknown referenced column")
+		}
+	}()
+	m.CreateTable("nodes", func(t *Table) { t.ForeignKeySelf("parent_id", "id") })
+}
+
+func TestCreatePivot(t *testing.T) {
+	m := &Migration{}
+	m.CreateTable("tags", func(t *Table) {
+		t.BigInteger("id").PrimaryKey()
+	})
+	m.CreatePivot("tags", "posts", func(t *Table) { t.Timestamps() })
+
+	if len(m.Operations) != 3 {
+		t.Fatalf("expected create tags, create pivot and unique index, got %d operations", len(m.Operations))
+	}
+	pivot := m.Operations[1]
+	if pivot.Type != OpCreateTable || pivot.Table != "post_tag" {
+		t.Fatalf("expected post_tag to be created, got %+v", pivot)
+	}
+	cols := pivot.TableDef.Columns
+	if cols[0].Name != "post_id" || cols[0].Type != UUID || cols[0].ForeignKeyTable != "posts" || cols[0].IsNullable || cols[0].OnDeleteAction != "CASCADE" {
+		t.Errorf("unexpected post_id column: %+v", cols[0])
+	}
+	if cols[1].Name != "tag_id" || cols[1].Type != BigInteger || cols[1].ForeignKeyTable != "tags" {
+		t.Errorf("tag_id should take the type of tags.id, got %+v", cols[1])
+	}
+	if len(cols) != 4 {
+		t.Errorf("expected fn to add timestamps, got %d columns", len(cols))
+	}
+	idx := m.Operations[2]
+	if idx.Type != OpAddUniqueIndex || idx.Table != "post_tag" || strings.Join(idx.Index.Columns, ",") != "post_id,tag_id" {
+		t.Errorf("unexpected index operation: %+v", idx)
+	}
+}
+
+func TestMigrationAddIndexNamed(t *testing.T) {
+	m := &Migration{}
+	m.AddIndexNamed("users_lookup", "users", "email")
+	m.AddUniqueIndexNamed("users_tenant_email", "users", "tenant_id", "email")
+
+	if got := m.Operations[0].Index.NameWithin(PostgresMaxIdentifier); got != "users_lookup" {
+		t.Errorf("explicit name = %q", got)
+	}
+	if idx := m.Operations[1].Index; !idx.Unique || idx.Name != "users_tenant_email" {
+		t.Errorf("unique named index = %+v", idx)
+	}
+}
+
+func TestIndexNameFitsIdentifierLimit(t *testing.T) {
+	table := "organization_membership_invitations"
+	a := &Index{Table: table, Columns: []string{"organization_id", "invited_by_user_id", "accepted_at"}}
+	b := &Index{Table: table, Columns: []string{"organization_id", "invited_by_user_id", "revoked_at"}}
+
+	nameA := a.NameWithin(PostgresMaxIdentifier)
+	nameB := b.NameWithin(PostgresMaxIdentifier)
+	for _, name := range []string{nameA, nameB} {
+		if len(name) > PostgresMaxIdentifier {
+			t.Errorf("%q is %d bytes, over the limit", name, len(name))
+		}
+	}
+	// Both derived names share their first 63 bytes, so plain truncation
+	// would collide.
+	if nameA == nameB {
+		t.Fatalf("long index names collided: %q", nameA)
+	}
+	if nameA != a.NameWithin(PostgresMaxIdentifier) {
+		t.Error("derived name is not stable")
+	}
+
+	short := &Index{Table: "users", Columns: []string{"email"}}
+	if got := short.NameWithin(PostgresMaxIdentifier); got != "users_email_idx" {
+		t.Errorf("short name = %q, want users_email_idx", got)
+	}
+}
+
 func TestMigrationDropAndRename(t *testing.T) {
 	m := &Migration{}
 	m.DropTableIfExists("old_table")
@@ -287,6 +496,37 @@ func TestColumnDefault(t *testing.T) {
 	}
 }
 
+func TestColumnDefaultRaw(t *testing.T) {
+	c := &Column{Name: "id", Type: UUID}
+	c.DefaultRaw("gen_random_uuid()")
+	if !c.HasDefault || !c.DefaultIsRaw || !c.DefaultIsExpression() {
+		t.Errorf("DefaultRaw: HasDefault=%v DefaultIsRaw=%v", c.HasDefault, c.DefaultIsRaw)
+	}
+	c.Default("plain")
+	if c.DefaultIsRaw || c.DefaultIsExpression() {
+		t.Error("Default after DefaultRaw should reset to a literal")
+	}
+}
+
+func TestLegacyExpressionDefault(t *testing.T) {
+	for value, want := range map[string]bool{
+		"gen_random_uuid()":        true,
+		"NOW()":                    true,
+		"now() + interval '1 day'": true,
+		"pg_catalog.now()":         true,
+		"(1 + 2)":                  true,
+		"draft (beta)":             false,
+		"active":                   false,
+		"()":                       true,
+		"":                         false,
+		"1(":                       false,
+	} {
+		if got := LegacyExpressionDefault(value); got != want {
+			t.Errorf("LegacyExpressionDefault(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
 func TestColumnForeignKeyMethod(t *testing.T) {
 	c := &Column{Name: "user_id", Type: UUID}
 	c.ForeignKey("users", "id")
@@ -534,6 +774,16 @@ func TestMigrationRenameColumnEmptyPanic(t *testing.T) {
 	}
 }
 
+func TestMigrationAddIndexNamedEmptyPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty index name")
+		}
+	}()
+	m := &Migration{}
+	m.AddIndexNamed("", "users", "email")
+}
+
 func TestMigrationAddIndexEmptyPanic(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -589,6 +839,51 @@ func TestMigrationAlterTable(t *testing.T) {
 	}
 }
 
+func TestMigrationChangeColumn(t *testing.T) {
+	m := &Migration{}
+	m.ChangeColumn("users", func(t *Table) {
+		t.String("bio", 1000).Nullable()
+		t.Decimal("balance", 12, 2).Using("balance_cents / 100.0")
+	})
+	if len(m.Operations) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(m.Operations))
+	}
+	for i, name := range []string{"bio", "balance"} {
+		op := m.Operations[i]
+		if op.Type != OpChangeColumn || op.Table != "users" || op.ColumnName != name {
+			t.Fatalf("unexpected op %d: %+v", i, op)
+		}
+		tmp := &Table{}
+		op.ColumnDef(tmp)
+		if len(tmp.Columns) != 1 || tmp.Columns[0].Name != name {
+			t.Errorf("op %d declares %+v, want only %s", i, tmp.Columns, name)
+		}
+	}
+	tmp := &Table{}
+	m.Operations[1].ColumnDef(tmp)
+	if tmp.Columns[0].UsingExpr != "balance_cents / 100.0" {
+		t.Errorf("UsingExpr = %q", tmp.Columns[0].UsingExpr)
+	}
+}
+
+func TestMigrationChangeColumnRejectsConstraints(t *testing.T) {
+	for name, fn := range map[string]func(*Table){
+		"unique":    func(t *Table) { t.String("email").Unique() },
+		"fk":        func(t *Table) { t.UUID("team_id").ForeignKey("teams", "id") },
+		"check":     func(t *Table) { t.Integer("age").Check("age >= 0") },
+		"encrypted": func(t *Table) { t.String("ssn").Encrypted() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			(&Migration{}).ChangeColumn("users", fn)
+		})
+	}
+}
+
 func TestMigrationCreateAndDropView(t *testing.T) {
 	m := &Migration{}
 	m.CreateView("active_users", func(v *View) {
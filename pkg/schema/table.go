@@ -29,6 +29,7 @@ type ForeignKey struct {
 	ReferencedColumns []string
 	OnDeleteAction    string
 	OnUpdateAction    string
+	IsIndexed         bool // Indexed() — the migration also emits an AddIndex on Columns
 }
 
 var validReferentialActions = map[string]bool{
@@ -59,6 +60,13 @@ func (f *ForeignKey) OnUpdate(action string) *ForeignKey {
 	return f
 }
 
+// Indexed adds an index on the foreign key's columns, in order, in the same
+// migration. See Column.Indexed.
+func (f *ForeignKey) Indexed() *ForeignKey {
+	f.IsIndexed = true
+	return f
+}
+
 func (r *Relationship) Collection() *Relationship {
 	r.IsCollection = true
 	return r
@@ -84,6 +92,62 @@ type Table struct {
 	AlteredColumns       []*Column // metadata-only column changes emitted by AlterTable
 }
 
+// IndexKind says where the index covering a set of columns comes from.
+type IndexKind string
+
+const (
+	IndexNone       IndexKind = ""
+	IndexPrimaryKey IndexKind = "primary key" // implicit, created with the primary key
+	IndexUnique     IndexKind = "unique"      // implicit, created with a Unique() column's constraint
+	IndexExplicit   IndexKind = "index"       // AddIndex, AddUniqueIndex or Indexed()
+)
+
+// IndexFor reports which index, if any, has cols as its leading columns:
+// the primary key or a unique column's constraint, which the database indexes
+// implicitly, or an index the migrations add explicitly. A foreign key is
+// not indexed by its constraint alone. Explicit indexes are only known once
+// the table's AddIndex operations have been applied to Indexes.
+func (t *Table) IndexFor(cols ...string) IndexKind {
+	if len(cols) == 0 {
+		return IndexNone
+	}
+	if len(cols) == 1 {
+		for _, col := range t.Columns {
+			if col.Name != cols[0] {
+				continue
+			}
+			if col.IsPrimaryKey {
+				return IndexPrimaryKey
+			}
+			if col.IsUnique {
+				return IndexUnique
+			}
+		}
+	}
+	if hasPrefix(t.CompositePrimaryKeys, cols) {
+		return IndexPrimaryKey
+	}
+	for _, idx := range t.Indexes {
+		if hasPrefix(idx.Columns, cols) {
+			return IndexExplicit
+		}
+	}
+	return IndexNone
+}
+
+// hasPrefix reports whether cols are the leading entries of list, in order.
+func hasPrefix(list, cols []string) bool {
+	if len(list) < len(cols) {
+		return false
+	}
+	for i, c := range cols {
+		if list[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
 // AlterColumn selects an existing column for a metadata-only alteration.
 // It is valid only inside Migration.AlterTable. Seed declarations currently
 // use this path; it never emits column DDL.
@@ -150,6 +214,35 @@ func (t *Table) ForeignKey(columns []string, referencedTable string, referencedC
 	return fk
 }
 
+// ForeignKeySelf makes column reference refColumn of this same table, as in
+// categories.parent_id → categories.id. A column declared earlier gets the
+// foreign key; otherwise one is added with refColumn's type. The column is
+// nullable, because the root of the hierarchy has no parent — chain
+// NotNull() to require one.
+func (t *Table) ForeignKeySelf(column, refColumn string) *Column {
+	if t.Name == "" {
+		panic("pickle: ForeignKeySelf requires a table name")
+	}
+	var ref *Column
+	for _, col := range t.Columns {
+		if col.Name == refColumn {
+			ref = col
+		}
+	}
+	if ref == nil {
+		panic("pickle: ForeignKeySelf references unknown column \"" + refColumn + "\" on table \"" + t.Name + "\"")
+	}
+	for _, col := range t.Columns {
+		if col.Name == column {
+			return col.ForeignKey(t.Name, refColumn)
+		}
+	}
+	c := t.addColumn(column, ref.Type)
+	c.Length, c.Precision, c.Scale = ref.Length, ref.Precision, ref.Scale
+	c.IsNullable = true
+	return c.ForeignKey(t.Name, refColumn)
+}
+
 func stringSlicesEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -223,8 +316,38 @@ func (t *Table) Boolean(name string) *Column {
 	return t.addColumn(name, Boolean)
 }
 
-func (t *Table) Timestamp(name string) *Column {
-	return t.addColumn(name, Timestamp)
+// Timestamp adds a timestamp with time zone (TIMESTAMPTZ in Postgres). An
+// optional precision of 0-6 sets the fractional-second digits kept, e.g.
+// Timestamp("at", 6) for microseconds; without it the database default applies.
+func (t *Table) Timestamp(name string, precision ...int) *Column {
+	c := t.addColumn(name, Timestamp)
+	setTimestampPrecision(c, precision)
+	return c
+}
+
+// TimestampTz is Timestamp, named for schemas that spell out the time zone
+// alongside TimestampWithoutTimeZone columns.
+func (t *Table) TimestampTz(name string, precision ...int) *Column {
+	return t.Timestamp(name, precision...)
+}
+
+// TimestampWithoutTimeZone adds a timestamp that stores wall-clock time with
+// no zone (TIMESTAMP in Postgres). The Go type is still time.Time.
+func (t *Table) TimestampWithoutTimeZone(name string, precision ...int) *Column {
+	c := t.Timestamp(name, precision...)
+	c.WithoutTimeZone = true
+	return c
+}
+
+func setTimestampPrecision(c *Column, precision []int) {
+	if len(precision) == 0 {
+		return
+	}
+	if len(precision) > 1 || precision[0] < 0 || precision[0] > 6 {
+		panic("pickle: timestamp precision must be a single value between 0 and 6")
+	}
+	c.Precision = precision[0]
+	c.HasPrecision = true
 }
 
 func (t *Table) JSONB(name string) *Column {
@@ -295,8 +418,8 @@ func (t *Table) Timestamps() {
 	if t.IsAppendOnly {
 		panic("pickle: Timestamps() must not be called on append-only table \"" + t.Name + "\" — CreatedAt is derived from the UUID v7 timestamp in id")
 	}
-	t.addColumn("created_at", Timestamp).NotNull().Default("NOW()")
-	t.addColumn("updated_at", Timestamp).NotNull().Default("NOW()")
+	t.addColumn("created_at", Timestamp).NotNull().DefaultRaw("NOW()")
+	t.addColumn("updated_at", Timestamp).NotNull().DefaultRaw("NOW()")
 }
 
 // Immutable marks this table as append-only. Pickle injects id and version_id
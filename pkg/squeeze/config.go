@@ -3,6 +3,7 @@ package squeeze
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +13,12 @@ type Config struct {
 	Squeeze  SqueezeConfig            `yaml:"squeeze"`
 	Apps     map[string]AppConfig     `yaml:"apps,omitempty"`
 	Services map[string]ServiceConfig `yaml:"services,omitempty"`
+	Watch    WatchConfig              `yaml:"watch,omitempty"`
+}
+
+// WatchConfig configures pickle --watch.
+type WatchConfig struct {
+	Dirs []string `yaml:"dirs,omitempty"` // extra directories to watch, relative to the project (or each app)
 }
 
 // AppConfig describes a single app in a monorepo layout.
@@ -39,8 +46,29 @@ func (c *Config) IsMultiService() bool {
 
 // SqueezeConfig holds all squeeze-related configuration.
 type SqueezeConfig struct {
-	Middleware MiddlewareConfig `yaml:"middleware"`
-	Rules      map[string]bool  `yaml:"rules"`
+	Middleware     MiddlewareConfig     `yaml:"middleware"`
+	Rules          map[string]bool      `yaml:"rules"`
+	UnboundedQuery UnboundedQueryConfig `yaml:"unbounded_query"`
+}
+
+// UnboundedQueryConfig excludes internal list endpoints from unbounded_query.
+type UnboundedQueryConfig struct {
+	SkipAdmin bool     `yaml:"skip_admin"` // skip routes behind admin middleware
+	SkipPaths []string `yaml:"skip_paths"` // skip routes under these path prefixes, e.g. /internal
+}
+
+// skips returns true if the route is excluded from unbounded_query.
+func (uc UnboundedQueryConfig) skips(route AnalyzedRoute, mc MiddlewareConfig) bool {
+	if uc.SkipAdmin && route.HasAdminMiddleware(mc) {
+		return true
+	}
+	for _, prefix := range uc.SkipPaths {
+		prefix = strings.TrimRight(prefix, "/")
+		if route.Path == prefix || strings.HasPrefix(route.Path, prefix+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 // MiddlewareConfig classifies middleware by role.
@@ -52,10 +80,10 @@ type MiddlewareConfig struct {
 }
 
 // IsAuthMiddleware returns true if the given middleware name is classified as auth.
-// Defaults to matching "Auth" if no auth middleware is configured.
+// Defaults to matching "Auth", or the registered name "auth", if no auth middleware is configured.
 func (mc MiddlewareConfig) IsAuthMiddleware(name string) bool {
 	if len(mc.Auth) == 0 && len(mc.Admin) == 0 {
-		return name == "Auth"
+		return name == "Auth" || name == "auth"
 	}
 	for _, m := range mc.Auth {
 		if m == name {
@@ -72,10 +100,10 @@ func (mc MiddlewareConfig) IsAuthMiddleware(name string) bool {
 }
 
 // IsAdminMiddleware returns true if the given middleware name is classified as admin.
-// Defaults to matching "RequireAdmin" if no admin middleware is configured.
+// Defaults to matching "RequireAdmin" and "RequireRole", or the registered name "admin", if no admin middleware is configured.
 func (mc MiddlewareConfig) IsAdminMiddleware(name string) bool {
 	if len(mc.Admin) == 0 {
-		return name == "RequireAdmin"
+		return name == "RequireAdmin" || name == "RequireRole" || name == "admin"
 	}
 	for _, m := range mc.Admin {
 		if m == name {
@@ -86,10 +114,11 @@ func (mc MiddlewareConfig) IsAdminMiddleware(name string) bool {
 }
 
 // IsRateLimitMiddleware returns true if the given middleware name is classified as rate limiting.
-// Defaults to matching "RateLimit" if no rate limit middleware is configured.
+// Defaults to matching "RateLimit", or the registered names "throttle" and "rate_limit",
+// if no rate limit middleware is configured.
 func (mc MiddlewareConfig) IsRateLimitMiddleware(name string) bool {
 	if len(mc.RateLimit) == 0 {
-		return name == "RateLimit"
+		return name == "RateLimit" || name == "throttle" || name == "rate_limit"
 	}
 	for _, m := range mc.RateLimit {
 		if m == name {
@@ -100,10 +129,10 @@ func (mc MiddlewareConfig) IsRateLimitMiddleware(name string) bool {
 }
 
 // IsCSRFMiddleware returns true if the given middleware name is classified as CSRF protection.
-// Defaults to matching "CSRF" if no CSRF middleware is configured.
+// Defaults to matching "CSRF", or the registered name "csrf", if no CSRF middleware is configured.
 func (mc MiddlewareConfig) IsCSRFMiddleware(name string) bool {
 	if len(mc.CSRF) == 0 {
-		return name == "CSRF"
+		return name == "CSRF" || name == "csrf"
 	}
 	for _, m := range mc.CSRF {
 		if m == name {
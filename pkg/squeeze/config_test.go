@@ -81,3 +81,24 @@ func TestLoadConfig_NoFile(t *testing.T) {
 		t.Fatal("expected IsMonorepo() to be false when no file exists")
 	}
 }
+
+func TestLoadConfig_UnboundedQuery(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `
+squeeze:
+  unbounded_query:
+    skip_admin: true
+    skip_paths: [/internal]
+`
+	if err := os.WriteFile(filepath.Join(dir, "pickle.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uq := cfg.Squeeze.UnboundedQuery
+	if !uq.SkipAdmin || len(uq.SkipPaths) != 1 || uq.SkipPaths[0] != "/internal" {
+		t.Errorf("UnboundedQuery = %+v", uq)
+	}
+}
@@ -189,6 +189,7 @@ type CompositeLitInfo struct {
 	PackageName string   // e.g. "models"
 	TypeName    string   // e.g. "Post"
 	FieldNames  []string // fields set in the literal
+	ZeroFields  []string // fields set to "" or uuid.Nil
 	Line        int
 }
 
@@ -444,12 +445,29 @@ func parseCompositeLit(cl *ast.CompositeLit, fset *token.FileSet) (CompositeLitI
 		}
 		if ident, ok := kv.Key.(*ast.Ident); ok {
 			info.FieldNames = append(info.FieldNames, ident.Name)
+			if isZeroLiteral(kv.Value) {
+				info.ZeroFields = append(info.ZeroFields, ident.Name)
+			}
 		}
 	}
 
 	return info, true
 }
 
+// isZeroLiteral reports whether expr is an empty string literal, uuid.Nil or
+// uuid.UUID{}: a value that is almost never meant to be stored.
+func isZeroLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.STRING && (e.Value == `""` || e.Value == "``")
+	case *ast.SelectorExpr:
+		return exprString(e) == "uuid.Nil"
+	case *ast.CompositeLit:
+		return len(e.Elts) == 0 && e.Type != nil && exprString(e.Type) == "uuid.UUID"
+	}
+	return false
+}
+
 // FindCtxJSONCalls finds ctx.JSON(status, payload) calls and returns info about the payload.
 type CtxJSONCall struct {
 	Line        int
@@ -554,7 +572,34 @@ func exprContainsAuthCall(expr ast.Expr) bool {
 	return found
 }
 
-// FindParamNames returns all string literal arguments to ctx.Param() and ctx.ParamUUID() calls in a method body.
+// bodyCallsAuthorize reports whether the body checks an access policy through
+// ctx.Authorize().
+func bodyCallsAuthorize(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Authorize" {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "ctx" {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// paramAccessors are the Context methods that read a route parameter by name.
+var paramAccessors = map[string]bool{"Param": true, "ParamUUID": true, "ParamInt": true, "ParamInt64": true}
+
+// FindParamNames returns all string literal arguments to ctx.Param() and its
+// typed variants (ctx.ParamUUID(), ctx.ParamInt(), ...) in a method body.
 func FindParamNames(body *ast.BlockStmt, fset *token.FileSet) []ParamCall {
 	var calls []ParamCall
 	ast.Inspect(body, func(n ast.Node) bool {
@@ -570,7 +615,7 @@ func FindParamNames(body *ast.BlockStmt, fset *token.FileSet) []ParamCall {
 		if !ok {
 			return true
 		}
-		if ident.Name != "ctx" || (sel.Sel.Name != "Param" && sel.Sel.Name != "ParamUUID") {
+		if ident.Name != "ctx" || !paramAccessors[sel.Sel.Name] {
 			return true
 		}
 		if len(call.Args) != 1 {
@@ -808,6 +853,11 @@ func PayloadIsModelWithoutPublic(expr ast.Expr, modelVars map[string]bool) bool
 //   - models.QueryX().First() or .All() (query results)
 //   - &models.X{} (struct literals)
 func FindModelVars(body *ast.BlockStmt) map[string]bool {
+	return FindModelVarsIn(body, "models")
+}
+
+// FindModelVarsIn is FindModelVars for a models package imported as modelsPkg.
+func FindModelVarsIn(body *ast.BlockStmt, modelsPkg string) map[string]bool {
 	vars := make(map[string]bool)
 	ast.Inspect(body, func(n ast.Node) bool {
 		assign, ok := n.(*ast.AssignStmt)
@@ -820,7 +870,7 @@ func FindModelVars(body *ast.BlockStmt) map[string]bool {
 		// Check for &models.X{...}
 		if unary, ok := rhs.(*ast.UnaryExpr); ok {
 			if cl, ok := unary.X.(*ast.CompositeLit); ok {
-				if isModelsType(cl.Type) {
+				if isModelsType(cl.Type, modelsPkg) {
 					addFirstIdent(assign.Lhs, vars)
 					return true
 				}
@@ -829,14 +879,14 @@ func FindModelVars(body *ast.BlockStmt) map[string]bool {
 
 		// Check for models.X{...} (without &)
 		if cl, ok := rhs.(*ast.CompositeLit); ok {
-			if isModelsType(cl.Type) {
+			if isModelsType(cl.Type, modelsPkg) {
 				addFirstIdent(assign.Lhs, vars)
 				return true
 			}
 		}
 
 		// Check for call chains ending in First()/All() on a models.Query*() chain
-		if exprIsModelQuery(rhs) {
+		if exprIsModelQuery(rhs, modelsPkg) {
 			addFirstIdent(assign.Lhs, vars)
 		}
 
@@ -846,17 +896,17 @@ func FindModelVars(body *ast.BlockStmt) map[string]bool {
 }
 
 // isModelsType checks if a type expression is models.Something.
-func isModelsType(expr ast.Expr) bool {
+func isModelsType(expr ast.Expr, modelsPkg string) bool {
 	sel, ok := expr.(*ast.SelectorExpr)
 	if !ok {
 		return false
 	}
 	ident, ok := sel.X.(*ast.Ident)
-	return ok && ident.Name == "models"
+	return ok && ident.Name == modelsPkg
 }
 
 // exprIsModelQuery checks if an expression is a call chain containing models.Query*().
-func exprIsModelQuery(expr ast.Expr) bool {
+func exprIsModelQuery(expr ast.Expr, modelsPkg string) bool {
 	found := false
 	ast.Inspect(expr, func(n ast.Node) bool {
 		if found {
@@ -871,7 +921,7 @@ func exprIsModelQuery(expr ast.Expr) bool {
 			return true
 		}
 		// Check for models.QueryX()
-		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "models" && strings.HasPrefix(sel.Sel.Name, "Query") {
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == modelsPkg && strings.HasPrefix(sel.Sel.Name, "Query") {
 			found = true
 		}
 		return true
@@ -888,3 +938,224 @@ func addFirstIdent(lhs []ast.Expr, vars map[string]bool) {
 		}
 	}
 }
+
+// FindModelVarTypes maps local variables to the model type they hold:
+//   - &models.Post{...} or models.Post{...} → "Post"
+//   - models.QueryPost()...First() → "Post"
+func FindModelVarTypes(body *ast.BlockStmt, modelsPkg string) map[string]string {
+	vars := make(map[string]string)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) == 0 || len(assign.Rhs) == 0 {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			return true
+		}
+
+		rhs := assign.Rhs[0]
+		if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			rhs = unary.X
+		}
+		if cl, ok := rhs.(*ast.CompositeLit); ok {
+			if isModelsType(cl.Type, modelsPkg) {
+				vars[ident.Name] = cl.Type.(*ast.SelectorExpr).Sel.Name
+			}
+			return true
+		}
+		if typeName := modelQueryType(rhs, modelsPkg); typeName != "" {
+			vars[ident.Name] = typeName
+		}
+		return true
+	})
+	return vars
+}
+
+// modelQueryType returns "Post" for an expression containing models.QueryPost().
+func modelQueryType(expr ast.Expr, modelsPkg string) string {
+	typeName := ""
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if typeName != "" {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == modelsPkg && strings.HasPrefix(sel.Sel.Name, "Query") {
+			typeName = strings.TrimPrefix(sel.Sel.Name, "Query")
+		}
+		return true
+	})
+	return typeName
+}
+
+// UnguardedDeref is an assignment like post.Title = *req.Title that is not
+// protected by a nil check on req.Title.
+type UnguardedDeref struct {
+	Target string // "post"
+	Field  string // "Title"
+	Source string // "req.Title"
+	Line   int
+}
+
+// FindUnguardedDerefs finds x.Field = *y.Field assignments that are not
+// nested inside `if y.Field != nil { ... }` (or the else branch of
+// `if y.Field == nil`), and not preceded in the same block by an early exit
+// `if y.Field == nil { ...; return }`.
+func FindUnguardedDerefs(body *ast.BlockStmt, fset *token.FileSet) []UnguardedDeref {
+	var derefs []UnguardedDeref
+	var walk func(stmts []ast.Stmt, guarded map[string]bool)
+	walk = func(stmts []ast.Stmt, guarded map[string]bool) {
+		// Early returns only guard the statements that follow them in the same
+		// block, so extend a copy as we go.
+		local := make(map[string]bool, len(guarded))
+		for k := range guarded {
+			local[k] = true
+		}
+		for _, stmt := range stmts {
+			switch s := stmt.(type) {
+			case *ast.AssignStmt:
+				if d, ok := unguardedDeref(s, local); ok {
+					d.Line = fset.Position(s.Pos()).Line
+					derefs = append(derefs, d)
+				}
+			case *ast.IfStmt:
+				inner := make(map[string]bool, len(local))
+				for k := range local {
+					inner[k] = true
+				}
+				for _, expr := range nilComparisons(s.Cond, token.NEQ) {
+					inner[expr] = true
+				}
+				walk(s.Body.List, inner)
+				// The else branch of `if y.Field == nil` only runs when it is set.
+				elseGuarded := make(map[string]bool, len(local))
+				for k := range local {
+					elseGuarded[k] = true
+				}
+				for _, expr := range nilComparisons(s.Cond, token.EQL) {
+					elseGuarded[expr] = true
+				}
+				switch e := s.Else.(type) {
+				case *ast.BlockStmt:
+					walk(e.List, elseGuarded)
+				case *ast.IfStmt:
+					walk([]ast.Stmt{e}, elseGuarded)
+				}
+				if blockExits(s.Body) {
+					for _, expr := range nilComparisons(s.Cond, token.EQL) {
+						local[expr] = true
+					}
+				}
+			case *ast.BlockStmt:
+				walk(s.List, local)
+			case *ast.ForStmt:
+				walk(s.Body.List, local)
+			case *ast.RangeStmt:
+				walk(s.Body.List, local)
+			case *ast.SwitchStmt:
+				walk(s.Body.List, local)
+			case *ast.TypeSwitchStmt:
+				walk(s.Body.List, local)
+			case *ast.SelectStmt:
+				walk(s.Body.List, local)
+			case *ast.CaseClause:
+				walk(s.Body, local)
+			case *ast.CommClause:
+				walk(s.Body, local)
+			}
+		}
+	}
+	walk(body.List, nil)
+	return derefs
+}
+
+func unguardedDeref(assign *ast.AssignStmt, guarded map[string]bool) (UnguardedDeref, bool) {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return UnguardedDeref{}, false
+	}
+	lhs, ok := assign.Lhs[0].(*ast.SelectorExpr)
+	if !ok {
+		return UnguardedDeref{}, false
+	}
+	target, ok := lhs.X.(*ast.Ident)
+	if !ok {
+		return UnguardedDeref{}, false
+	}
+	star, ok := assign.Rhs[0].(*ast.StarExpr)
+	if !ok {
+		return UnguardedDeref{}, false
+	}
+	if _, ok := star.X.(*ast.SelectorExpr); !ok {
+		return UnguardedDeref{}, false
+	}
+	source := exprString(star.X)
+	if source == "" || guarded[source] {
+		return UnguardedDeref{}, false
+	}
+	return UnguardedDeref{Target: target.Name, Field: lhs.Sel.Name, Source: source}, true
+}
+
+// nilComparisons returns the expressions compared against nil with op,
+// following && chains (for !=) or || chains (for ==).
+func nilComparisons(cond ast.Expr, op token.Token) []string {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+	if (op == token.NEQ && bin.Op == token.LAND) || (op == token.EQL && bin.Op == token.LOR) {
+		return append(nilComparisons(bin.X, op), nilComparisons(bin.Y, op)...)
+	}
+	if bin.Op != op {
+		return nil
+	}
+	if ident, ok := bin.Y.(*ast.Ident); ok && ident.Name == "nil" {
+		return []string{exprString(bin.X)}
+	}
+	if ident, ok := bin.X.(*ast.Ident); ok && ident.Name == "nil" {
+		return []string{exprString(bin.Y)}
+	}
+	return nil
+}
+
+// blockExits reports whether control cannot fall out of a block: its last
+// statement is a return, a panic(...), or a break/continue/goto. Earlier
+// statements (e.g. logging before the return) don't matter.
+func blockExits(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := last.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	}
+	return false
+}
+
+// exprString renders identifiers and selector chains like req.Title.
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		x := exprString(e.X)
+		if x == "" {
+			return ""
+		}
+		return x + "." + e.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package squeeze
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// ruleLiteralDefault flags Default strings whose meaning depends on guessing.
+// A string that looks like a function call is still emitted as SQL for
+// backward compatibility, and one that looks like SQL (a cast or a quoted
+// literal) is quoted again as text. Both should say what they mean with
+// DefaultRaw, or with a plain literal.
+func ruleLiteralDefault(ctx *AnalysisContext) []Finding {
+	var findings []Finding
+	for _, table := range ctx.Tables {
+		for _, col := range table.Columns {
+			value, ok := col.DefaultValue.(string)
+			if !ok || !col.HasDefault || col.DefaultIsRaw {
+				continue
+			}
+			var message string
+			switch {
+			case schema.LegacyExpressionDefault(value):
+				message = fmt.Sprintf("%s.%s: Default(%q) is emitted as a SQL expression only for backward compatibility — use DefaultRaw(%q)", table.Name, col.Name, value, value)
+			case strings.Contains(value, "::") || strings.HasPrefix(value, "'"):
+				message = fmt.Sprintf("%s.%s: Default(%q) is stored as quoted text, not evaluated — use DefaultRaw for SQL expressions", table.Name, col.Name, value)
+			default:
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:     "literal_default",
+				Severity: SeverityWarning,
+				File:     createTableFile(ctx, table.Name),
+				Message:  message,
+			})
+		}
+	}
+	return findings
+}
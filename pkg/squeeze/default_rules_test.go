@@ -0,0 +1,28 @@
+package squeeze
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestRuleLiteralDefault(t *testing.T) {
+	posts := &schema.Table{Name: "posts"}
+	posts.UUID("id").PrimaryKey().Default("gen_random_uuid()")
+	posts.UUID("version_id").DefaultRaw("gen_random_uuid()")
+	posts.String("status").Default("draft (beta)")
+	posts.JSONB("meta").Default("'{}'::jsonb")
+	posts.Integer("views").Default(0)
+
+	findings := ruleLiteralDefault(&AnalysisContext{Tables: []*schema.Table{posts}})
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %v", len(findings), findings)
+	}
+	if f := findings[0]; f.Rule != "literal_default" || f.Severity != SeverityWarning || !strings.Contains(f.Message, `posts.id: Default("gen_random_uuid()")`) || !strings.Contains(f.Message, `DefaultRaw("gen_random_uuid()")`) {
+		t.Errorf("legacy expression finding = %+v", f)
+	}
+	if f := findings[1]; !strings.Contains(f.Message, "posts.meta") || !strings.Contains(f.Message, "quoted text") {
+		t.Errorf("quoted SQL finding = %+v", f)
+	}
+}
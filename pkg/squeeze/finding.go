@@ -21,13 +21,18 @@ func (s Severity) String() string {
 	}
 }
 
+// MarshalText encodes the severity as "warning" or "error".
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // Finding represents a single issue detected by squeeze.
 type Finding struct {
-	Rule     string
-	Severity Severity
-	File     string
-	Line     int
-	Message  string
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
 }
 
 func (f Finding) String() string {
@@ -0,0 +1,65 @@
+package squeeze
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+// ruleFKIndex flags foreign-key columns with no index leading on them.
+// Postgres indexes the referenced side of a foreign key but not the
+// referencing column, so joins, cascading deletes, and WhereUserID scopes
+// on an unindexed FK fall back to sequential scans.
+func ruleFKIndex(ctx *AnalysisContext) []Finding {
+	var findings []Finding
+	for _, table := range ctx.Tables {
+		var fkColumns [][]string
+		for _, col := range table.Columns {
+			if col.ForeignKeyTable != "" {
+				fkColumns = append(fkColumns, []string{col.Name})
+			}
+		}
+		for _, fk := range table.ForeignKeys {
+			if len(fk.Columns) > 0 {
+				fkColumns = append(fkColumns, fk.Columns)
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, cols := range fkColumns {
+			if seen[cols[0]] || table.IndexFor(cols...) != schema.IndexNone {
+				continue
+			}
+			seen[cols[0]] = true
+			findings = append(findings, Finding{
+				Rule:     "fk_index",
+				Severity: SeverityWarning,
+				File:     createTableFile(ctx, table.Name),
+				Message:  fmt.Sprintf("%s.%s is a foreign key without an index — chain .Indexed() onto it or add %s", table.Name, cols[0], addIndexSnippet(table.Name, cols)),
+			})
+		}
+	}
+	return findings
+}
+
+func addIndexSnippet(table string, cols []string) string {
+	args := fmt.Sprintf("%q", table)
+	for _, c := range cols {
+		args += fmt.Sprintf(", %q", c)
+	}
+	return "m.AddIndex(" + args + ")"
+}
+
+// createTableFile returns the migration path that creates table, or "" if
+// it can't be found.
+func createTableFile(ctx *AnalysisContext, table string) string {
+	for _, migration := range ctx.Migrations {
+		for _, op := range migration.Up {
+			if op.Type == "create_table" && op.Table == table && migration.File != "" {
+				return filepath.Join("database", "migrations", migration.File)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package squeeze
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/generator"
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func TestRuleFKIndex_FlagsUnindexedForeignKey(t *testing.T) {
+	ctx := &AnalysisContext{
+		Tables: []*schema.Table{{
+			Name: "posts",
+			Columns: []*schema.Column{
+				{Name: "id", IsPrimaryKey: true},
+				{Name: "user_id", ForeignKeyTable: "users", ForeignKeyColumn: "id"},
+			},
+		}},
+		Migrations: []generator.MigrationOps{{
+			File: "2026_01_01_000000_create_posts_table.go",
+			Up:   []generator.MigrationOperation{{Type: "create_table", Table: "posts"}},
+		}},
+	}
+	findings := ruleFKIndex(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	f := findings[0]
+	if f.Rule != "fk_index" || f.Severity != SeverityWarning {
+		t.Errorf("finding = %+v", f)
+	}
+	if !strings.Contains(f.Message, `m.AddIndex("posts", "user_id")`) {
+		t.Errorf("message missing AddIndex suggestion: %s", f.Message)
+	}
+	if !strings.HasSuffix(f.File, "2026_01_01_000000_create_posts_table.go") {
+		t.Errorf("File = %q, want the create_posts_table migration", f.File)
+	}
+}
+
+func TestRuleFKIndex_PassesIndexedForeignKeys(t *testing.T) {
+	ctx := &AnalysisContext{
+		Tables: []*schema.Table{{
+			Name: "comments",
+			Columns: []*schema.Column{
+				{Name: "post_id", ForeignKeyTable: "posts", ForeignKeyColumn: "id"},
+				{Name: "author_id", ForeignKeyTable: "users", ForeignKeyColumn: "id"},
+				{Name: "thread_id", ForeignKeyTable: "threads", ForeignKeyColumn: "id", IsUnique: true},
+			},
+			Indexes: []*schema.Index{
+				{Table: "comments", Columns: []string{"post_id"}},
+				{Table: "comments", Columns: []string{"author_id", "created_at"}},
+			},
+		}},
+	}
+	if findings := ruleFKIndex(ctx); len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %v", findings)
+	}
+}
+
+func TestRuleFKIndex_CompositeIndexMustLeadWithColumn(t *testing.T) {
+	ctx := &AnalysisContext{
+		Tables: []*schema.Table{{
+			Name: "comments",
+			Columns: []*schema.Column{
+				{Name: "post_id", ForeignKeyTable: "posts", ForeignKeyColumn: "id"},
+			},
+			Indexes: []*schema.Index{
+				{Table: "comments", Columns: []string{"created_at", "post_id"}},
+			},
+		}},
+	}
+	if findings := ruleFKIndex(ctx); len(findings) != 1 {
+		t.Errorf("expected 1 finding for non-leading index column, got %v", findings)
+	}
+}
+
+func TestRuleFKIndex_TableLevelCompositeForeignKey(t *testing.T) {
+	ctx := &AnalysisContext{
+		Tables: []*schema.Table{{
+			Name: "line_items",
+			ForeignKeys: []*schema.ForeignKey{
+				{Columns: []string{"order_id", "order_version"}, ReferencedTable: "orders", ReferencedColumns: []string{"id", "version_id"}},
+			},
+		}},
+	}
+	findings := ruleFKIndex(ctx)
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, `m.AddIndex("line_items", "order_id", "order_version")`) {
+		t.Fatalf("expected composite AddIndex suggestion, got %v", findings)
+	}
+
+	ctx.Tables[0].Indexes = []*schema.Index{{Table: "line_items", Columns: []string{"order_id", "order_version"}}}
+	if findings := ruleFKIndex(ctx); len(findings) != 0 {
+		t.Errorf("expected 0 findings once indexed, got %v", findings)
+	}
+}
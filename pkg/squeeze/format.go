@@ -0,0 +1,309 @@
+package squeeze
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Output formats accepted by FormatFindings.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// FormatFindings renders findings in the given format:
+//
+//   - "text" (or "") is the ANSI-colored report grouped by file that
+//     pickle squeeze prints by default.
+//   - "json" is an array of findings with rule, severity, file, line and message.
+//   - "sarif" is a SARIF 2.1.0 log for GitHub code scanning and other CI
+//     annotation tools. File paths under the working directory are written
+//     relative to it, so run squeeze from the repository root.
+func FormatFindings(findings []Finding, format string) ([]byte, error) {
+	switch format {
+	case "", FormatText:
+		return formatText(findings), nil
+	case FormatJSON:
+		if findings == nil {
+			findings = []Finding{}
+		}
+		out, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case FormatSARIF:
+		return formatSARIF(findings)
+	default:
+		return nil, fmt.Errorf("unknown squeeze format %q (use text, json or sarif)", format)
+	}
+}
+
+func formatText(findings []Finding) []byte {
+	var b bytes.Buffer
+	currentFile := ""
+	for _, f := range findings {
+		if f.File != currentFile {
+			currentFile = f.File
+			fmt.Fprintf(&b, "\n  %s\n", currentFile)
+		}
+		color := "\033[33m" // yellow for warning
+		if f.Severity == SeverityError {
+			color = "\033[31m" // red for error
+		}
+		fmt.Fprintf(&b, "    %sline %d\033[0m [%s] %s\n", color, f.Line, f.Rule, f.Message)
+	}
+	return b.Bytes()
+}
+
+// sarifLog is the subset of the SARIF 2.1.0 schema squeeze writes.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func formatSARIF(findings []Finding) ([]byte, error) {
+	names := make([]string, 0, len(AllRules()))
+	for name := range AllRules() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]sarifRule, len(names))
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		info := ruleInfoFor(name)
+		rules[i] = sarifRule{
+			ID:                   name,
+			ShortDescription:     sarifMessage{Text: info.Description},
+			DefaultConfiguration: sarifConfiguration{Level: info.Severity.String()},
+		}
+		index[name] = i
+	}
+
+	cwd, _ := os.Getwd()
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		i, ok := index[f.Rule]
+		if !ok {
+			// Keep findings from rules without metadata rather than dropping them.
+			info := ruleInfoFor(f.Rule)
+			rules = append(rules, sarifRule{
+				ID:                   f.Rule,
+				ShortDescription:     sarifMessage{Text: info.Description},
+				DefaultConfiguration: sarifConfiguration{Level: f.Severity.String()},
+			})
+			i = len(rules) - 1
+			index[f.Rule] = i
+		}
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(f.File, cwd)}}
+		if f.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			RuleIndex: i,
+			Level:     f.Severity.String(),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	out, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "pickle squeeze",
+				InformationURI: "https://github.com/shortontech/pickle",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// sarifURI turns a finding's file path into a SARIF artifact URI: relative
+// to cwd when the file is under it, otherwise an absolute file:// URI.
+func sarifURI(path, cwd string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	if cwd != "" {
+		if rel, err := filepath.Rel(cwd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return "file://" + filepath.ToSlash(path)
+}
+
+// ruleInfo is the metadata reported for a rule in SARIF output.
+type ruleInfo struct {
+	Severity    Severity // severity the rule usually reports
+	Description string
+}
+
+func ruleInfoFor(name string) ruleInfo {
+	if info, ok := ruleInfos[name]; ok {
+		return info
+	}
+	return ruleInfo{Severity: SeverityWarning, Description: strings.ReplaceAll(name, "_", " ")}
+}
+
+// ruleInfos describes every rule in AllRules. Rules that escalate some
+// findings (float_column on monetary columns, for one) list their usual
+// severity; each result still carries its own level.
+var ruleInfos = map[string]ruleInfo{
+	"no_printf":                            {SeverityWarning, "fmt print calls in controllers instead of ctx.Logger()"},
+	"no_recover":                           {SeverityError, "recover() in controllers or helpers hides panics"},
+	"ownership_scoping":                    {SeverityError, "Update or delete route not scoped to the authenticated user"},
+	"read_scoping":                         {SeverityError, "Authenticated read route not scoped to the authenticated user"},
+	"enum_validation":                      {SeverityError, "Enum-like request field without oneof validation"},
+	"uuid_error_handling":                  {SeverityError, "Route parameter parsed with uuid.MustParse or without error handling"},
+	"resource_id_uuid_parser":              {SeverityError, "ResourceID value parsed as a plain UUID"},
+	"resource_id_unscoped":                 {SeverityError, "ResourceID record ID queried without its scope ID"},
+	"public_projection":                    {SeverityError, "Unauthenticated route returns model data without .Public()"},
+	"required_fields":                      {SeverityError, "Create() call writes a zero value into a NOT NULL column with no default"},
+	"nullable_update":                      {SeverityError, "Update dereferences an optional request field without a nil guard"},
+	"unused_request_field":                 {SeverityWarning, "Request field no binding controller reads"},
+	"unbounded_query":                      {SeverityWarning, "Index query returns all rows without Limit or Paginate"},
+	"rate_limit_auth":                      {SeverityError, "Authentication route without rate limiting"},
+	"auth_without_middleware":              {SeverityError, "ctx.Auth() on a route without auth middleware"},
+	"error_leak":                           {SeverityWarning, "Unauthenticated route returns raw errors through ctx.Error()"},
+	"param_mismatch":                       {SeverityError, "ctx.Param() name does not match a route parameter"},
+	"dangling_route":                       {SeverityError, "Route handler method is not defined on the controller"},
+	"csrf_missing":                         {SeverityError, "State-changing session route without CSRF middleware"},
+	"sensitive_field_encryption":           {SeverityWarning, "Sensitive column without .Encrypted()"},
+	"public_sensitive_conflict":            {SeverityError, "Sensitive column marked .Public() without .UnsafePublic()"},
+	"fk_index":                             {SeverityWarning, "Foreign-key column without an index"},
+	"literal_default":                      {SeverityWarning, "Default string whose SQL meaning is ambiguous"},
+	"immutable_raw_update":                 {SeverityError, "Raw UPDATE on an immutable table"},
+	"immutable_raw_insert_missing_version": {SeverityError, "Raw INSERT into an immutable table without version_id"},
+	"immutable_timestamps_call":            {SeverityError, "Timestamps() on an immutable table"},
+	"immutable_direct_delete":              {SeverityError, "Raw DELETE on an immutable table without soft deletes"},
+	"lock_outside_transaction":             {SeverityWarning, "Row lock taken outside a transaction"},
+	"version_field_in_request":             {SeverityError, "Request struct exposes version_id"},
+	"integrity_hash_override":              {SeverityError, "Raw SQL sets row_hash or prev_hash"},
+	"integrity_column_in_request":          {SeverityError, "Request struct exposes row_hash or prev_hash"},
+	"graphql_public_sensitive":             {SeverityError, "Sensitive column public in GraphQL"},
+	"graphql_owner_column_missing":         {SeverityError, "Owner-only GraphQL fields on a table without an owner column"},
+	"graphql_no_visibility_annotations":    {SeverityWarning, "GraphQL-exposed table without visibility annotations"},
+	"encrypted_column_range":               {SeverityError, "Range comparison on an encrypted column"},
+	"sealed_column_where":                  {SeverityError, "WHERE on a sealed column"},
+	"encrypted_column_order_by":            {SeverityError, "ORDER BY on an encrypted or sealed column"},
+	"encrypted_sealed_conflict":            {SeverityError, "Column marked both Encrypted() and Sealed()"},
+	"encrypted_missing_key_config":         {SeverityWarning, "Encrypted columns without encryption key config"},
+	"float_column":                         {SeverityWarning, "Float or Double column where Decimal avoids precision loss"},
+	"float_request_field":                  {SeverityError, "Float field in a request struct"},
+	"raw_sql":                              {SeverityError, "Direct database/sql call in a controller"},
+	"sql_injection_risk":                   {SeverityError, "Request input used as a column, direction or SQL fragment"},
+	"raw_query_builder_access":             {SeverityWarning, "Column method called on the embedded query builder"},
+	"rls_guidance":                         {SeverityWarning, "Migration opts into PostgreSQL row-level security"},
+	"row_policy_invalid":                   {SeverityError, "Row policy cannot be parsed or resolved"},
+	"row_policy_missing":                   {SeverityError, "Row policy leaves an operation uncovered"},
+	"row_policy_unknown_identity":          {SeverityError, "Row policy references an unknown or mistyped identity"},
+	"row_policy_unlowerable":               {SeverityError, "Row policy cannot be lowered equally to SQL and RLS"},
+	"row_policy_context_missing":           {SeverityError, "Protected query without a policy context"},
+	"row_policy_context_spoof":             {SeverityError, "Policy context built from untrusted values"},
+	"row_policy_bypass":                    {SeverityError, "Query path can bypass row-policy enforcement"},
+	"row_policy_projection_conflict":       {SeverityError, "Row policy conflicts with column visibility"},
+	"row_policy_application_only":          {SeverityWarning, "Row policy enforced in the application only"},
+	"rls_not_enabled":                      {SeverityError, "Live table does not have RLS enabled"},
+	"rls_not_forced":                       {SeverityError, "Live table does not force RLS"},
+	"rls_runtime_bypass":                   {SeverityError, "Runtime database role bypasses RLS"},
+	"rls_manual_broadening":                {SeverityError, "Manual permissive policy widens a Pickle-managed table"},
+	"rls_drift":                            {SeverityError, "Live RLS policies differ from the generated ones"},
+	"stale_role_annotation":                {SeverityWarning, "Visibility annotation for a removed role"},
+	"unknown_role_annotation":              {SeverityError, "Visibility annotation for an undefined role"},
+	"role_without_load":                    {SeverityError, "RequireRole middleware without LoadRoles"},
+	"default_role_missing":                 {SeverityError, "No default role, or more than one"},
+	"ungated_action":                       {SeverityError, "Action without a gate"},
+	"direct_execute_call":                  {SeverityError, "Action executed directly instead of through its gate"},
+	"scope_builder_leak":                   {SeverityError, "ScopeBuilder used outside database/scopes"},
+	"scope_side_effect":                    {SeverityError, "Scope calls a method outside the ScopeBuilder API"},
+	"query_builder_in_scope":               {SeverityError, "Model query builder used inside a scope"},
+	"pre_birth_annotation":                 {SeverityWarning, "Visibility annotation predates the role's creation"},
+	"missing_visibility_scope":             {SeverityError, "Role-aware query without a visibility selection"},
+	"hardcoded_role_select":                {SeverityError, "SelectFor called with a literal role"},
+	"graphql_exposed_no_auth":              {SeverityError, "GraphQL-exposed model without any access protection"},
+	"graphql_unexposed_mutation":           {SeverityWarning, "Routed controller action not exposed in GraphQL"},
+	"graphql_exposed_no_migration":         {SeverityError, "GraphQL policy exposes a model with no table"},
+	"graphql_action_no_controller":         {SeverityError, "GraphQL controller action does not exist"},
+	"graphql_stale_expose":                 {SeverityWarning, "GraphQL policy exposes a dropped table"},
+	"plaintext_password":                   {SeverityError, "Column named password suggests plaintext storage"},
+	"handler_package":                      {SeverityError, "Route handler outside the controllers package"},
+	"seeder_unstable_identity":             {SeverityError, "Seeder upsert without a stable conflict identity"},
+	"seeder_nondeterministic":              {SeverityError, "Seeder uses global randomness or the current time"},
+	"seeder_integrity_override":            {SeverityError, "Seeder sets row_hash or prev_hash"},
+	"seeder_missing_value":                 {SeverityError, "Row seeder leaves a required column without a value"},
+	"seeder_type_mismatch":                 {SeverityError, "Seeder value cannot convert to its column type"},
+	"seeder_ambiguous_relationship":        {SeverityError, "Seeder relationship matches more than one foreign key"},
+	"seeder_incomplete_composite_key":      {SeverityError, "Seeder sets only part of a composite foreign key"},
+	"seeder_sensitive_literal":             {SeverityError, "Literal value in a sensitive seed field"},
+	"seeder_production_unsafe":             {SeverityError, "Seeder can mutate a non-development environment unguarded"},
+}
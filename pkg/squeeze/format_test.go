@@ -0,0 +1,115 @@
+package squeeze
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var formatTestFindings = []Finding{
+	{Rule: "no_recover", Severity: SeverityError, File: "app/http/controllers/post_controller.go", Line: 12, Message: "recover() in controller"},
+	{Rule: "fk_index", Severity: SeverityWarning, File: "database/migrations/create_posts.go", Message: "posts.user_id has no index"},
+}
+
+func TestEveryRuleHasInfo(t *testing.T) {
+	for name := range AllRules() {
+		info, ok := ruleInfos[name]
+		if !ok || info.Description == "" {
+			t.Errorf("rule %s has no description in ruleInfos", name)
+		}
+	}
+	for name := range ruleInfos {
+		if _, ok := AllRules()[name]; !ok {
+			t.Errorf("ruleInfos describes unknown rule %s", name)
+		}
+	}
+}
+
+func TestFormatFindingsText(t *testing.T) {
+	out, err := FormatFindings(formatTestFindings, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n  app/http/controllers/post_controller.go\n    \033[31mline 12\033[0m [no_recover] recover() in controller\n"
+	if !strings.HasPrefix(string(out), want) {
+		t.Errorf("text output = %q, want prefix %q", out, want)
+	}
+	if !strings.Contains(string(out), "\033[33mline 0\033[0m [fk_index]") {
+		t.Errorf("warnings should be yellow: %q", out)
+	}
+}
+
+func TestFormatFindingsJSON(t *testing.T) {
+	out, err := FormatFindings(formatTestFindings, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got) != 2 || got[0]["rule"] != "no_recover" || got[0]["severity"] != "error" || got[0]["line"] != float64(12) || got[1]["severity"] != "warning" {
+		t.Errorf("JSON findings = %v", got)
+	}
+
+	out, err = FormatFindings(nil, FormatJSON)
+	if err != nil || strings.TrimSpace(string(out)) != "[]" {
+		t.Errorf("no findings = %q, %v; want []", out, err)
+	}
+}
+
+func TestFormatFindingsSARIF(t *testing.T) {
+	cwd, _ := os.Getwd()
+	findings := append([]Finding{}, formatTestFindings...)
+	findings[0].File = filepath.Join(cwd, "app", "http", "controllers", "post_controller.go")
+
+	out, err := FormatFindings(findings, FormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF header = %s %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(AllRules()) {
+		t.Errorf("rules = %d, want every rule (%d)", len(run.Tool.Driver.Rules), len(AllRules()))
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("results = %d, want 2", len(run.Results))
+	}
+
+	first := run.Results[0]
+	rule := run.Tool.Driver.Rules[first.RuleIndex]
+	if rule.ID != "no_recover" || rule.DefaultConfiguration.Level != "error" || rule.ShortDescription.Text == "" {
+		t.Errorf("ruleIndex points at %+v", rule)
+	}
+	loc := first.Locations[0].PhysicalLocation
+	if first.Level != "error" || loc.ArtifactLocation.URI != "app/http/controllers/post_controller.go" || loc.Region == nil || loc.Region.StartLine != 12 {
+		t.Errorf("first result = %+v at %+v", first, loc)
+	}
+	if second := run.Results[1]; second.Level != "warning" || second.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("finding without a line should have no region: %+v", second)
+	}
+}
+
+func TestSarifURI(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	if got := sarifURI(filepath.FromSlash("/repo/app/models/user.go"), root); got != "app/models/user.go" {
+		t.Errorf("inside cwd = %s", got)
+	}
+	if got := sarifURI(filepath.FromSlash("/other/user.go"), root); got != "file:///other/user.go" {
+		t.Errorf("outside cwd = %s", got)
+	}
+}
+
+func TestFormatFindingsRejectsUnknownFormat(t *testing.T) {
+	if _, err := FormatFindings(nil, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
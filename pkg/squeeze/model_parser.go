@@ -0,0 +1,62 @@
+package squeeze
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseModelFields reads the model structs in modelsDir and returns the
+// field names of each, keyed by type name. Embedded fields are listed under
+// their type name.
+func ParseModelFields(modelsDir string) (map[string]map[string]bool, error) {
+	entries, err := os.ReadDir(modelsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	models := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(modelsDir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			fields := make(map[string]bool)
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					fields[name.Name] = true
+				}
+				if len(field.Names) == 0 {
+					typ := field.Type
+					if star, ok := typ.(*ast.StarExpr); ok {
+						typ = star.X
+					}
+					if sel, ok := typ.(*ast.SelectorExpr); ok {
+						fields[sel.Sel.Name] = true
+					} else if ident, ok := typ.(*ast.Ident); ok {
+						fields[ident.Name] = true
+					}
+				}
+			}
+			models[spec.Name.Name] = fields
+			return false
+		})
+	}
+	return models, nil
+}
@@ -0,0 +1,192 @@
+package squeeze
+
+import (
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/schema"
+)
+
+func nullableUpdateCtx(t *testing.T, src string) *AnalysisContext {
+	t.Helper()
+	return &AnalysisContext{
+		Methods: map[string]*ControllerMethod{
+			"PostController.Update": method(t, src),
+		},
+		Tables: []*schema.Table{
+			{
+				Name: "posts",
+				Columns: []*schema.Column{
+					{Name: "id", IsPrimaryKey: true},
+					{Name: "title", IsNullable: false},
+					{Name: "summary", IsNullable: true},
+				},
+			},
+		},
+	}
+}
+
+func TestRuleNullableUpdate_FlagsUnguardedDeref(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, err := models.QueryPost().WhereID(id).First()
+	if err != nil {
+		return
+	}
+	post.Title = *req.Title
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	if findings[0].Rule != "nullable_update" || findings[0].Line != 8 {
+		t.Errorf("unexpected finding: %v", findings[0])
+	}
+}
+
+func TestRuleNullableUpdate_PassesGuardedDeref(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	if req.Title != nil {
+		post.Title = *req.Title
+	}
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_PassesEarlyReturnGuard(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	if req.Title == nil {
+		return
+	}
+	post.Title = *req.Title
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_GuardDoesNotLeakToSiblingField(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post := &models.Post{}
+	if req.Summary != nil {
+		post.Title = *req.Title
+	}
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 1 {
+		t.Errorf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_SkipsNullableColumn(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	post.Summary = *req.Summary
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_PassesMultiStatementEarlyReturn(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	if req.Title == nil {
+		log.Warn("title missing")
+		return ctx.JSON(422, nil)
+	}
+	post.Title = *req.Title
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_PassesElseOfNilCheck(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	if req.Title == nil {
+		post.Title = "untitled"
+	} else {
+		post.Title = *req.Title
+	}
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_FlagsDerefInsideSwitch(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	switch mode {
+	case "full":
+		post.Title = *req.Title
+	}
+	models.QueryPost().Update(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 1 {
+		t.Errorf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_SkipsMethodsWithoutUpdate(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post := &models.Post{}
+	post.Title = *req.Title
+	models.QueryPost().Create(post)
+}`
+	findings := ruleNullableUpdate(nullableUpdateCtx(t, src))
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleNullableUpdate_FlagsUpdateColumns(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, err := models.QueryPost().WhereID(id).First()
+	if err != nil {
+		return
+	}
+	post.Title = *req.Title
+	models.QueryPost().UpdateColumns(post, "title")
+}`
+	if findings := ruleNullableUpdate(nullableUpdateCtx(t, src)); len(findings) != 1 {
+		t.Fatalf("expected 1 finding for UpdateColumns write-back, got %d", len(findings))
+	}
+}
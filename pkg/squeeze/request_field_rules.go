@@ -0,0 +1,144 @@
+package squeeze
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// ruleUnusedRequestField flags request struct fields that no controller
+// binding the request ever reads. A field left behind after a feature changed
+// still accepts client input and still runs its validation, but nothing uses
+// the value.
+//
+// A field counts as used when any method that calls Bind<Request> selects it
+// (req.Title), or passes the bound value on whole (service.Create(req)),
+// since the callee may read any field. Fields whose validate tag compares
+// against another field (eqfield=Password) exist for validation and are
+// skipped. Requests no method binds are left to other rules. Fields read only
+// through reflection are reported too; silence them with a
+// `squeeze:"ignore=unused_request_field"` struct tag.
+func ruleUnusedRequestField(ctx *AnalysisContext) []Finding {
+	bound := make(map[string]bool)
+	used := make(map[string]map[string]bool)
+	escaped := make(map[string]bool)
+	for _, req := range ctx.Requests {
+		used[req.Name] = make(map[string]bool)
+	}
+
+	for _, m := range ctx.Methods {
+		if m.Body == nil {
+			continue
+		}
+		vars := boundRequestVars(m.Body, used)
+		if len(vars) == 0 {
+			continue
+		}
+		for _, reqName := range vars {
+			bound[reqName] = true
+		}
+		selected := make(map[*ast.Ident]bool)
+		ast.Inspect(m.Body, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if reqName, ok := vars[ident.Name]; ok {
+				used[reqName][sel.Sel.Name] = true
+				selected[ident] = true
+			}
+			return true
+		})
+		var walk func(n ast.Node) bool
+		walk = func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				// Assigning to the variable, the binding call included,
+				// doesn't read it.
+				for _, rhs := range node.Rhs {
+					ast.Inspect(rhs, walk)
+				}
+				return false
+			case *ast.SelectorExpr:
+				ast.Inspect(node.X, walk)
+				return false
+			case *ast.Ident:
+				if reqName, ok := vars[node.Name]; ok && !selected[node] {
+					escaped[reqName] = true
+				}
+			}
+			return true
+		}
+		ast.Inspect(m.Body, walk)
+	}
+
+	var findings []Finding
+	for _, req := range ctx.Requests {
+		if !bound[req.Name] || escaped[req.Name] {
+			continue
+		}
+		for _, field := range req.Fields {
+			if used[req.Name][field.Name] || strings.Contains(field.Validate, "field=") || squeezeTagIgnores(field.Squeeze, "unused_request_field") {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:     "unused_request_field",
+				Severity: SeverityWarning,
+				File:     req.File,
+				Line:     field.Line,
+				Message:  req.Name + "." + field.Name + " is bound but no controller that binds " + req.Name + " reads it — remove the field, or tag it squeeze:\"ignore=unused_request_field\" if it is read through reflection",
+			})
+		}
+	}
+	return findings
+}
+
+// boundRequestVars maps each variable assigned from a generated Bind<Request>
+// call in body to the request's name, for the request names in known.
+func boundRequestVars(body *ast.BlockStmt, known map[string]map[string]bool) map[string]string {
+	vars := make(map[string]string)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return true
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var fn string
+		switch f := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			fn = f.Sel.Name
+		case *ast.Ident:
+			fn = f.Name
+		}
+		reqName := strings.TrimPrefix(fn, "Bind")
+		if _, ok := known[reqName]; !ok || reqName == fn {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+			vars[ident.Name] = reqName
+		}
+		return true
+	})
+	return vars
+}
+
+// squeezeTagIgnores reports whether a squeeze struct tag value, such as
+// "ignore=unused_request_field,enum_validation", names rule.
+func squeezeTagIgnores(tag, rule string) bool {
+	names, ok := strings.CutPrefix(tag, "ignore=")
+	if !ok {
+		return false
+	}
+	for _, name := range strings.Split(names, ",") {
+		if strings.TrimSpace(name) == rule {
+			return true
+		}
+	}
+	return false
+}
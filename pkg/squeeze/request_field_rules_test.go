@@ -0,0 +1,84 @@
+package squeeze
+
+import (
+	"testing"
+
+	"github.com/shortontech/pickle/pkg/generator"
+)
+
+func unusedFieldCtx(t *testing.T, srcs ...string) *AnalysisContext {
+	t.Helper()
+	methods := make(map[string]*ControllerMethod)
+	for i, src := range srcs {
+		methods[string(rune('A'+i))] = method(t, src)
+	}
+	return &AnalysisContext{
+		Methods: methods,
+		Requests: []generator.RequestDef{{
+			Name: "CreatePostRequest",
+			File: "requests/create_post.go",
+			Fields: []generator.RequestField{
+				{Name: "Title", Validate: "required", Line: 4},
+				{Name: "Body", Line: 5},
+				{Name: "Legacy", Line: 6},
+				{Name: "TitleConfirmation", Validate: "eqfield=Title", Line: 7},
+				{Name: "Meta", Squeeze: "ignore=unused_request_field", Line: 8},
+			},
+		}},
+	}
+}
+
+func TestRuleUnusedRequestField_FlagsFieldNoBinderReads(t *testing.T) {
+	store := `package controllers
+func Store() {
+	req, bindErr := requests.BindCreatePostRequest(ctx.Request())
+	if bindErr != nil {
+		return
+	}
+	post := models.Post{Title: req.Title}
+	_ = post
+}`
+	draft := `package controllers
+func Draft() {
+	req, _ := requests.BindCreatePostRequest(ctx.Request())
+	log(req.Body)
+}`
+	findings := ruleUnusedRequestField(unusedFieldCtx(t, store, draft))
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	if findings[0].Rule != "unused_request_field" || findings[0].Line != 6 || findings[0].File != "requests/create_post.go" {
+		t.Errorf("unexpected finding: %v", findings[0])
+	}
+}
+
+func TestRuleUnusedRequestField_PassesWhenValuePassedOn(t *testing.T) {
+	src := `package controllers
+func Store() {
+	req, _ := requests.BindCreatePostRequest(ctx.Request())
+	services.CreatePost(req)
+}`
+	if findings := ruleUnusedRequestField(unusedFieldCtx(t, src)); len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestRuleUnusedRequestField_SkipsUnboundRequests(t *testing.T) {
+	src := `package controllers
+func Index() {
+	posts, _ := models.QueryPost().All()
+	_ = posts
+}`
+	if findings := ruleUnusedRequestField(unusedFieldCtx(t, src)); len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d: %v", len(findings), findings)
+	}
+}
+
+func TestSqueezeTagIgnores(t *testing.T) {
+	if !squeezeTagIgnores("ignore=enum_validation,unused_request_field", "unused_request_field") {
+		t.Error("expected the listed rule to be ignored")
+	}
+	if squeezeTagIgnores("ignore=enum_validation", "unused_request_field") || squeezeTagIgnores("", "unused_request_field") {
+		t.Error("unlisted rule should not be ignored")
+	}
+}
@@ -1,6 +1,9 @@
 package squeeze
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/shortontech/pickle/pkg/schema"
@@ -240,3 +243,164 @@ func Handler() {
 		t.Errorf("expected 0 findings without Create() call, got %d", len(findings))
 	}
 }
+
+func TestRuleRequiredFields_RenamedModelsPackage(t *testing.T) {
+	src := `package controllers
+import db "myapp/app/models"
+func Handler() {
+	post := &db.Post{
+		Title: "hello",
+	}
+	db.QueryPost().Create(post)
+}`
+	m := method(t, src)
+
+	ctx := &AnalysisContext{
+		ModelsPkg: "db",
+		Methods: map[string]*ControllerMethod{
+			"PostController.Store": m,
+		},
+		Tables: []*schema.Table{
+			{
+				Name: "posts",
+				Columns: []*schema.Column{
+					{Name: "title"},
+					{Name: "body"},
+				},
+			},
+		},
+	}
+
+	findings := ruleRequiredFields(ctx)
+	if len(findings) != 1 || findings[0].Rule != "required_fields" {
+		t.Fatalf("expected 1 required_fields finding for db.Post, got %+v", findings)
+	}
+}
+
+func TestRuleRequiredFields_UsesGeneratedModelNames(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	person := &models.People{Name: "Ada"}
+	models.QueryPeople().Create(person)
+}`
+	ctx := &AnalysisContext{
+		Methods: map[string]*ControllerMethod{"PeopleController.Store": method(t, src)},
+		Tables: []*schema.Table{{
+			Name: "people",
+			Columns: []*schema.Column{
+				{Name: "name", Type: schema.String},
+				{Name: "email", Type: schema.String},
+			},
+		}},
+	}
+
+	findings := ruleRequiredFields(ctx)
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "missing required field Email") {
+		t.Fatalf("expected 1 finding for People.Email, got %+v", findings)
+	}
+	if !strings.Contains(findings[0].Message, `inserts ""`) {
+		t.Errorf("message should name the zero value inserted: %s", findings[0].Message)
+	}
+}
+
+func TestRuleRequiredFields_FlagsZeroValues(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post := &models.Post{
+		Title:  "",
+		UserID: uuid.Nil,
+		TeamID: uuid.UUID{},
+		Body:   req.Body,
+	}
+	models.QueryPost().Create(post)
+}`
+	ctx := &AnalysisContext{
+		Methods: map[string]*ControllerMethod{"PostController.Store": method(t, src)},
+		Tables: []*schema.Table{{
+			Name: "posts",
+			Columns: []*schema.Column{
+				{Name: "title", Type: schema.String},
+				{Name: "user_id", Type: schema.UUID},
+				{Name: "team_id", Type: schema.UUID},
+				{Name: "body", Type: schema.Text},
+			},
+		}},
+	}
+
+	findings := ruleRequiredFields(ctx)
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d: %+v", len(findings), findings)
+	}
+	for i, want := range []string{`sets required field Title to ""`, "sets required field UserID to uuid.Nil", "sets required field TeamID to uuid.Nil"} {
+		if !strings.Contains(findings[i].Message, want) {
+			t.Errorf("finding %d = %q, want it to mention %q", i, findings[i].Message, want)
+		}
+	}
+}
+
+func TestRuleRequiredFields_FlagsColumnMissingFromModel(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post := &models.Post{Title: "hello", Metadata: raw}
+	models.QueryPost().Create(post)
+}`
+	ctx := &AnalysisContext{
+		Methods: map[string]*ControllerMethod{"PostController.Store": method(t, src)},
+		Tables: []*schema.Table{{
+			Name: "posts",
+			Columns: []*schema.Column{
+				{Name: "title", Type: schema.String},
+				{Name: "slug", Type: schema.String},
+				{Name: "payload", Type: schema.JSONB},
+			},
+		}},
+		ModelFields: map[string]map[string]bool{
+			"Post": {"Title": true, "Payload": true, "Metadata": true},
+		},
+	}
+
+	findings := ruleRequiredFields(ctx)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if !strings.Contains(findings[0].Message, "models.Post has no field Slug") || !strings.Contains(findings[0].Message, "pickle generate") {
+		t.Errorf("expected a stale-model finding for slug, got %q", findings[0].Message)
+	}
+	if !strings.Contains(findings[1].Message, "missing required field Payload") || !strings.Contains(findings[1].Message, "inserts NULL") {
+		t.Errorf("expected a NULL insert finding for payload, got %q", findings[1].Message)
+	}
+}
+
+func TestParseModelFields(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+type Post struct {
+	ID    uuid.UUID ` + "`db:\"id\"`" + `
+	Title string
+	*pickle.Timestamps
+}
+
+type PostQuery struct {
+	base
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "post.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	models, err := ParseModelFields(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := models["Post"]
+	if !post["ID"] || !post["Title"] || !post["Timestamps"] || post["Body"] {
+		t.Errorf("Post fields = %v", post)
+	}
+	if !models["PostQuery"]["base"] {
+		t.Errorf("PostQuery fields = %v", models["PostQuery"])
+	}
+}
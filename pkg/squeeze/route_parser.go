@@ -13,8 +13,10 @@ import (
 type AnalyzedRoute struct {
 	Method         string   // GET, POST, PUT, PATCH, DELETE
 	Path           string   // full path including group prefixes
+	Group          string   // combined prefix of the enclosing groups; "" outside any group
 	ControllerType string   // e.g. "PostController"
 	MethodName     string   // e.g. "Destroy"
+	Name           string   // route name from .Name()/.Names(), with group prefixes; "" if unnamed
 	HandlerPackage string   // package qualifier, e.g. "controllers" or "" if unqualified
 	Middleware     []string // accumulated middleware names from groups + per-route
 	File           string
@@ -70,6 +72,8 @@ var httpMethods = map[string]string{
 }
 
 // ParseRoutes parses all Go files in the routes directory and extracts route definitions.
+// Middleware referenced by name is replaced by what the RegisterMiddleware calls in
+// the same directory register under that name; unknown names are kept as written.
 func ParseRoutes(routesDir string) ([]AnalyzedRoute, error) {
 	entries, err := os.ReadDir(routesDir)
 	if err != nil {
@@ -80,6 +84,7 @@ func ParseRoutes(routesDir string) ([]AnalyzedRoute, error) {
 	}
 
 	var routes []AnalyzedRoute
+	registry := map[string][]string{}
 
 	for _, e := range entries {
 		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") || strings.HasSuffix(e.Name(), "_gen.go") {
@@ -93,6 +98,8 @@ func ParseRoutes(routesDir string) ([]AnalyzedRoute, error) {
 			return nil, err
 		}
 
+		collectMiddlewareRegistrations(f, registry)
+
 		for _, decl := range f.Decls {
 			gen, ok := decl.(*ast.GenDecl)
 			if !ok || gen.Tok != token.VAR {
@@ -131,9 +138,62 @@ func ParseRoutes(routesDir string) ([]AnalyzedRoute, error) {
 		}
 	}
 
+	for i := range routes {
+		routes[i].Middleware = expandMiddlewareNames(routes[i].Middleware, registry, nil)
+	}
 	return routes, nil
 }
 
+// collectMiddlewareRegistrations records pickle.RegisterMiddleware("name", mw...)
+// calls in f, mapping each name to the middleware it stands for.
+func collectMiddlewareRegistrations(f *ast.File, registry map[string][]string) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		var fn string
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			fn = fun.Name
+		case *ast.SelectorExpr:
+			fn = fun.Sel.Name
+		}
+		if fn != "RegisterMiddleware" {
+			return true
+		}
+		name := extractStringLit(call.Args[0])
+		if name == "" {
+			return true
+		}
+		for _, arg := range call.Args[1:] {
+			if mw := extractMiddlewareName(arg); mw != "" {
+				registry[name] = append(registry[name], mw)
+			}
+		}
+		return true
+	})
+}
+
+// expandMiddlewareNames replaces registered names with the middleware they
+// stand for, recursively, skipping names already being expanded.
+func expandMiddlewareNames(names []string, registry map[string][]string, seen map[string]bool) []string {
+	var out []string
+	for _, name := range names {
+		inner, ok := registry[name]
+		if !ok || seen[name] {
+			out = append(out, name)
+			continue
+		}
+		nested := map[string]bool{name: true}
+		for k := range seen {
+			nested[k] = true
+		}
+		out = append(out, expandMiddlewareNames(inner, registry, nested)...)
+	}
+	return out
+}
+
 // isRoutesCall checks if a call expression is pickle.Routes(...) or Routes(...)
 func isRoutesCall(call *ast.CallExpr) bool {
 	switch fn := call.Fun.(type) {
@@ -170,6 +230,7 @@ func walkRouterBody(body *ast.BlockStmt, routerName, prefix string, parentMW []s
 		if !ok {
 			continue
 		}
+		call, name := unwrapRouteName(call)
 
 		sel, ok := call.Fun.(*ast.SelectorExpr)
 		if !ok {
@@ -185,11 +246,24 @@ func walkRouterBody(body *ast.BlockStmt, routerName, prefix string, parentMW []s
 		methodName := sel.Sel.Name
 
 		if methodName == "Group" {
-			routes = append(routes, parseGroup(call, routerName, prefix, parentMW, fset, file)...)
+			group := parseGroup(call, routerName, prefix, parentMW, fset, file)
+			for i := range group {
+				if name != "" && group[i].Name != "" {
+					group[i].Name = name + group[i].Name
+				}
+			}
+			routes = append(routes, group...)
 		} else if methodName == "Resource" {
-			routes = append(routes, parseResource(call, prefix, parentMW, fset, file)...)
+			resource := parseResource(call, prefix, parentMW, fset, file)
+			if name != "" {
+				for i, suffix := range []string{"index", "show", "store", "update", "destroy"} {
+					resource[i].Name = name + "." + suffix
+				}
+			}
+			routes = append(routes, resource...)
 		} else if _, ok := httpMethods[methodName]; ok {
 			if r, ok := parseRoute(methodName, call, prefix, parentMW, fset, file); ok {
+				r.Name = name
 				routes = append(routes, r)
 			}
 		}
@@ -198,6 +272,27 @@ func walkRouterBody(body *ast.BlockStmt, routerName, prefix string, parentMW []s
 	return routes
 }
 
+// unwrapRouteName strips a trailing .Name("x") or .Names("x") from a route
+// registration, returning the registration call and the name argument. As at
+// runtime, the last call in a chain wins.
+func unwrapRouteName(call *ast.CallExpr) (*ast.CallExpr, string) {
+	name := ""
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Name" && sel.Sel.Name != "Names") || len(call.Args) != 1 {
+			return call, name
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return call, name
+		}
+		if name == "" {
+			name = strings.TrimSpace(extractStringLit(call.Args[0]))
+		}
+		call = inner
+	}
+}
+
 // parseGroup handles r.Group("/prefix", func(r *Router) { ... }, middleware...)
 // Signature: Group(prefix string, body func(*Router), mw ...MiddlewareFunc)
 func parseGroup(call *ast.CallExpr, routerName, parentPrefix string, parentMW []string, fset *token.FileSet, file string) []AnalyzedRoute {
@@ -261,11 +356,11 @@ func parseResource(call *ast.CallExpr, parentPrefix string, parentMW []string, f
 	line := fset.Position(call.Pos()).Line
 
 	return []AnalyzedRoute{
-		{Method: "GET", Path: fullPath, ControllerType: ctrlType, MethodName: "Index", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
-		{Method: "GET", Path: fullPath + "/:id", ControllerType: ctrlType, MethodName: "Show", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
-		{Method: "POST", Path: fullPath, ControllerType: ctrlType, MethodName: "Store", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
-		{Method: "PUT", Path: fullPath + "/:id", ControllerType: ctrlType, MethodName: "Update", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
-		{Method: "DELETE", Path: fullPath + "/:id", ControllerType: ctrlType, MethodName: "Destroy", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
+		{Method: "GET", Path: fullPath, Group: parentPrefix, ControllerType: ctrlType, MethodName: "Index", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
+		{Method: "GET", Path: fullPath + "/:id", Group: parentPrefix, ControllerType: ctrlType, MethodName: "Show", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
+		{Method: "POST", Path: fullPath, Group: parentPrefix, ControllerType: ctrlType, MethodName: "Store", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
+		{Method: "PUT", Path: fullPath + "/:id", Group: parentPrefix, ControllerType: ctrlType, MethodName: "Update", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
+		{Method: "DELETE", Path: fullPath + "/:id", Group: parentPrefix, ControllerType: ctrlType, MethodName: "Destroy", HandlerPackage: handlerPkg, Middleware: allMW, File: file, Line: line},
 	}
 }
 
@@ -292,6 +387,7 @@ func parseRoute(method string, call *ast.CallExpr, parentPrefix string, parentMW
 	return AnalyzedRoute{
 		Method:         httpMethods[method],
 		Path:           parentPrefix + routePath,
+		Group:          parentPrefix,
 		ControllerType: ctrlType,
 		MethodName:     methodName,
 		HandlerPackage: handlerPkg,
@@ -311,13 +407,16 @@ func extractStringLit(expr ast.Expr) string {
 }
 
 // extractMiddlewareName extracts the middleware function name from an expression.
-// Handles: middleware.Auth → "Auth", middleware.RequireRole("admin") → "RequireRole".
+// Handles: middleware.Auth → "Auth", middleware.RequireRole("admin") → "RequireRole",
+// and a registered name such as "auth", which is returned as written.
 // It also unwraps the pickle.MiddlewareFunc(x) / MiddlewareFunc(x) conversion the
 // router forces, classifying by the inner expression: pickle.MiddlewareFunc(session.CSRF) → "CSRF".
 func extractMiddlewareName(expr ast.Expr) string {
 	switch e := expr.(type) {
 	case *ast.SelectorExpr:
 		return e.Sel.Name
+	case *ast.BasicLit:
+		return extractStringLit(e)
 	case *ast.CallExpr:
 		// Unwrap a single-arg MiddlewareFunc(x) / pickle.MiddlewareFunc(x) conversion
 		// and classify by the inner expression. Genuine constructor middleware such as
@@ -334,13 +433,14 @@ func extractMiddlewareName(expr ast.Expr) string {
 }
 
 // isMiddlewareFuncConversion reports whether fun refers to the MiddlewareFunc type,
-// either as a bare identifier (MiddlewareFunc) or pickle-qualified (pickle.MiddlewareFunc).
+// either as a bare identifier (MiddlewareFunc) or package-qualified (pickle.MiddlewareFunc).
+// Any qualifier is accepted since layout.http_package can rename the http package.
 func isMiddlewareFuncConversion(fun ast.Expr) bool {
 	switch f := fun.(type) {
 	case *ast.Ident:
 		return f.Name == "MiddlewareFunc"
 	case *ast.SelectorExpr:
-		if pkg, ok := f.X.(*ast.Ident); ok && pkg.Name == "pickle" {
+		if _, ok := f.X.(*ast.Ident); ok {
 			return f.Sel.Name == "MiddlewareFunc"
 		}
 	}
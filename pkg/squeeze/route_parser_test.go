@@ -131,6 +131,9 @@ var API = pickle.Routes(func(r *pickle.Router) {
 	if r.Path != "/api/admin/users" {
 		t.Errorf("expected /api/admin/users, got %s", r.Path)
 	}
+	if r.Group != "/api/admin" {
+		t.Errorf("expected group /api/admin, got %q", r.Group)
+	}
 	if len(r.Middleware) < 2 {
 		t.Errorf("expected both RateLimit and RequireAdmin middleware, got %v", r.Middleware)
 	}
@@ -170,6 +173,56 @@ var API = pickle.Routes(func(r *pickle.Router) {
 	}
 }
 
+func TestParseRoutes_NamedRoutes(t *testing.T) {
+	dir := t.TempDir()
+	writeRouteFile(t, dir, "web.go", `package routes
+
+import (
+	pickle "myapp/app/http"
+	"myapp/app/http/controllers"
+)
+
+var API = pickle.Routes(func(r *pickle.Router) {
+	r.Get("/", controllers.HomeController{}.Index).Name("home")
+	r.Get("/about", controllers.HomeController{}.About)
+	r.Resource("/posts", controllers.PostController{}).Names("posts")
+	r.Group("/admin", func(r *pickle.Router) {
+		r.Get("/users/:id", controllers.UserController{}.Show, middleware.Auth).Name("users.show")
+		r.Group("/reports", func(r *pickle.Router) {
+			r.Get("/daily", controllers.ReportController{}.Daily).Name("daily")
+		}).Name("reports.")
+	}).Name("admin.")
+})
+`)
+
+	routes, err := ParseRoutes(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, r := range routes {
+		got[r.Method+" "+r.Path] = r.Name
+	}
+	want := map[string]string{
+		"GET /":                    "home",
+		"GET /about":               "",
+		"GET /posts":               "posts.index",
+		"DELETE /posts/:id":        "posts.destroy",
+		"GET /admin/users/:id":     "admin.users.show",
+		"GET /admin/reports/daily": "admin.reports.daily",
+	}
+	for route, name := range want {
+		if n, ok := got[route]; !ok {
+			t.Errorf("route %s not parsed", route)
+		} else if n != name {
+			t.Errorf("route %s name = %q, want %q", route, n, name)
+		}
+	}
+	if len(routes) != 9 {
+		t.Errorf("expected 9 routes, got %d", len(routes))
+	}
+}
+
 func TestParseRoutes_MissingDir(t *testing.T) {
 	routes, err := ParseRoutes("/nonexistent/routes/dir")
 	if err != nil {
@@ -274,6 +327,53 @@ var API = pickle.Routes(func(r *pickle.Router) {
 	}
 }
 
+func TestParseRoutes_NamedMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	writeRouteFile(t, dir, "middleware.go", `package routes
+
+import (
+	pickle "myapp/app/http"
+	"myapp/app/http/middleware"
+)
+
+func init() {
+	pickle.RegisterMiddleware("auth", middleware.Auth)
+	pickle.RegisterMiddleware("api", middleware.CORS, "auth")
+}
+`)
+	writeRouteFile(t, dir, "web.go", `package routes
+
+import (
+	pickle "myapp/app/http"
+	"myapp/app/http/controllers"
+)
+
+var API = pickle.Routes(func(r *pickle.Router) {
+	r.Group("/api", func(r *pickle.Router) {
+		r.Post("/posts", controllers.PostController{}.Store, "throttle")
+	}, "api")
+})
+`)
+
+	routes, err := ParseRoutes(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(routes))
+	}
+	got := routes[0].Middleware
+	if len(got) != 3 || got[0] != "CORS" || got[1] != "Auth" || got[2] != "throttle" {
+		t.Fatalf("middleware = %v, want [CORS Auth throttle]", got)
+	}
+
+	// Without pickle.yaml, both resolved and conventional names classify.
+	var mc MiddlewareConfig
+	if !routes[0].HasAuthMiddleware(mc) || !routes[0].HasRateLimitMiddleware(mc) {
+		t.Errorf("expected auth and rate limit classification for %v", got)
+	}
+}
+
 func TestExtractMiddlewareName_Parameterized(t *testing.T) {
 	// parseGroup handles middleware.RequireRole("admin") — selector expression
 	dir := t.TempDir()
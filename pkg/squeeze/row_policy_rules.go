@@ -298,12 +298,12 @@ func assignsIdentifier(statement ast.Stmt, name string) bool {
 func ruleRowPolicyContextSpoof(ctx *AnalysisContext) []Finding {
 	var findings []Finding
 	for name, method := range ctx.Methods {
-		for _, line := range FindCallsTo(method.Body, method.Fset, "models", "NewVerifiedPolicyContext") {
+		for _, line := range FindCallsTo(method.Body, method.Fset, ctx.modelsPkg(), "NewVerifiedPolicyContext") {
 			findings = append(findings, Finding{Rule: "row_policy_context_spoof", Severity: SeverityError, File: method.File, Line: line, Message: name + " constructs verified policy identity directly — only generated authentication, job, CLI, or test adapters may create PolicyContext"})
 		}
 	}
 	for name, fn := range ctx.FuncRegistry {
-		for _, line := range FindCallsTo(fn.Body, fn.Fset, "models", "NewVerifiedPolicyContext") {
+		for _, line := range FindCallsTo(fn.Body, fn.Fset, ctx.modelsPkg(), "NewVerifiedPolicyContext") {
 			findings = append(findings, Finding{Rule: "row_policy_context_spoof", Severity: SeverityError, File: fn.Fset.Position(fn.Body.Pos()).Filename, Line: line, Message: name + "() constructs verified policy identity directly — use a generated trusted adapter"})
 		}
 	}
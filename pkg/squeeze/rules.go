@@ -5,6 +5,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/shortontech/pickle/pkg/generator"
@@ -31,10 +32,20 @@ type AnalysisContext struct {
 	Actions              []ActionInfo
 	GraphQLExposed       map[string]bool // table/model names exposed via GraphQL policies (nil = no policies)
 	ProjectDir           string
-	RoleBirths           map[string]string    // role -> birth policy timestamp (for pre_birth_annotation)
-	ScopeAllowedMethods  map[string]bool      // method names allowed on ScopeBuilder (for scope_side_effect)
-	TablesWithVisibility map[string]bool      // table names that have visibility annotations (for missing_visibility_scope)
-	LiveRLS              []LiveRLSObservation // populated only by an explicit live catalog inspection
+	RoleBirths           map[string]string          // role -> birth policy timestamp (for pre_birth_annotation)
+	ScopeAllowedMethods  map[string]bool            // method names allowed on ScopeBuilder (for scope_side_effect)
+	TablesWithVisibility map[string]bool            // table names that have visibility annotations (for missing_visibility_scope)
+	LiveRLS              []LiveRLSObservation       // populated only by an explicit live catalog inspection
+	ModelsPkg            string                     // package name controllers import models as (default "models")
+	ModelFields          map[string]map[string]bool // generated model struct -> field names; nil if app/models wasn't parsed
+}
+
+// modelsPkg returns the configured models package name, defaulting to "models".
+func (ctx *AnalysisContext) modelsPkg() string {
+	if ctx.ModelsPkg == "" {
+		return "models"
+	}
+	return ctx.ModelsPkg
 }
 
 // LiveRLSObservation is sanitized catalog evidence for live-only row-policy
@@ -62,13 +73,19 @@ func AllRules() map[string]Rule {
 		"resource_id_unscoped":                 ruleResourceIDUnscoped,
 		"public_projection":                    rulePublicProjection,
 		"required_fields":                      ruleRequiredFields,
+		"nullable_update":                      ruleNullableUpdate,
+		"unused_request_field":                 ruleUnusedRequestField,
 		"unbounded_query":                      ruleUnboundedQuery,
 		"rate_limit_auth":                      ruleRateLimitAuth,
 		"auth_without_middleware":              ruleAuthWithoutMiddleware,
+		"error_leak":                           ruleErrorLeak,
 		"param_mismatch":                       ruleParamMismatch,
+		"dangling_route":                       ruleDanglingRoute,
 		"csrf_missing":                         ruleCsrfMissing,
 		"sensitive_field_encryption":           ruleSensitiveFieldEncryption,
 		"public_sensitive_conflict":            rulePublicSensitiveConflict,
+		"fk_index":                             ruleFKIndex,
+		"literal_default":                      ruleLiteralDefault,
 		"immutable_raw_update":                 ruleImmutableRawUpdate,
 		"immutable_raw_insert_missing_version": ruleImmutableRawInsertMissingVersion,
 		"immutable_timestamps_call":            ruleImmutableTimestampsCall,
@@ -88,6 +105,7 @@ func AllRules() map[string]Rule {
 		"float_column":                         ruleFloatColumn,
 		"float_request_field":                  ruleFloatRequestField,
 		"raw_sql":                              ruleRawSQL,
+		"sql_injection_risk":                   ruleSQLInjectionRisk,
 		"raw_query_builder_access":             ruleRawQueryBuilderAccess,
 		"rls_guidance":                         ruleRLSGuidance,
 		"row_policy_invalid":                   ruleRowPolicyInvalid,
@@ -149,7 +167,7 @@ func ruleNoPrintf(ctx *AnalysisContext) []Finding {
 					Severity: SeverityWarning,
 					File:     m.File,
 					Line:     line,
-					Message:  "fmt." + fn + " in controller — use structured logging instead",
+					Message:  "fmt." + fn + " in controller — log through ctx.Logger() instead",
 				})
 			}
 		}
@@ -218,6 +236,11 @@ func ruleOwnershipScoping(ctx *AnalysisContext) []Finding {
 			continue
 		}
 
+		// An access policy checked through ctx.Authorize() owns the decision.
+		if bodyCallsAuthorize(method.Body) {
+			continue
+		}
+
 		authVars := FindAuthTaintedVars(method.Body)
 		chains := ExtractCallChainsRecursive(method.Body, method.Fset, ctx.FuncRegistry, authVars)
 
@@ -407,6 +430,60 @@ func ruleEnumValidation(ctx *AnalysisContext) []Finding {
 		}
 	}
 
+	return append(findings, enumLiteralAssignments(ctx)...)
+}
+
+// enumLiteralAssignments flags controller assignments like post.Status = "draft"
+// where "draft" is a oneof= value with a generated requests constant.
+func enumLiteralAssignments(ctx *AnalysisContext) []Finding {
+	consts := map[string]map[string]string{} // field → value → constant
+	for _, enum := range generator.CollectRequestEnums(ctx.Requests) {
+		if consts[enum.Field] == nil {
+			consts[enum.Field] = map[string]string{}
+		}
+		for i, value := range enum.Values {
+			if _, ok := consts[enum.Field][value]; !ok {
+				consts[enum.Field][value] = enum.Consts[i]
+			}
+		}
+	}
+	if len(consts) == 0 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, m := range ctx.Methods {
+		ast.Inspect(m.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != len(assign.Rhs) {
+				return true
+			}
+			for i, lhs := range assign.Lhs {
+				sel, ok := lhs.(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				lit, ok := assign.Rhs[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					continue
+				}
+				if name, ok := consts[sel.Sel.Name][value]; ok {
+					findings = append(findings, Finding{
+						Rule:     "enum_validation",
+						Severity: SeverityWarning,
+						File:     m.File,
+						Line:     m.Fset.Position(lit.Pos()).Line,
+						Message:  sel.Sel.Name + " = " + lit.Value + " — use string(requests." + name + ") so the value stays in sync with its oneof= rule",
+					})
+				}
+			}
+			return true
+		})
+	}
 	return findings
 }
 
@@ -423,7 +500,7 @@ func ruleUUIDErrorHandling(ctx *AnalysisContext) []Finding {
 					Severity: SeverityError,
 					File:     m.File,
 					Line:     call.Line,
-					Message:  "uuid.MustParse(ctx.Param(...)) — panics on invalid input, use uuid.Parse with error handling",
+					Message:  "uuid.MustParse(ctx.Param(...)) — panics on invalid input, use ctx.ParamUUID(...) and handle the error",
 				})
 			} else if call.HasCtxAuth {
 				findings = append(findings, Finding{
@@ -441,9 +518,24 @@ func ruleUUIDErrorHandling(ctx *AnalysisContext) []Finding {
 }
 
 // rulePublicProjection flags unauthenticated routes that return model data without .Public().
+// When the schema is known, models with no hidden columns are skipped — there is
+// nothing for .Public() to strip.
 func rulePublicProjection(ctx *AnalysisContext) []Finding {
 	var findings []Finding
 
+	// Model struct name → whether any column is hidden from serialization.
+	hasHidden := make(map[string]bool)
+	for _, table := range ctx.Tables {
+		name := names.TableToStructName(table.Name)
+		hasHidden[name] = false
+		for _, col := range table.Columns {
+			if generator.IsHiddenColumn(col) {
+				hasHidden[name] = true
+				break
+			}
+		}
+	}
+
 	for _, route := range ctx.Routes {
 		// Only check routes without auth middleware
 		if route.HasAuthMiddleware(ctx.Config.Middleware) {
@@ -456,10 +548,16 @@ func rulePublicProjection(ctx *AnalysisContext) []Finding {
 			continue
 		}
 
-		modelVars := FindModelVars(method.Body)
+		modelVars := FindModelVarsIn(method.Body, ctx.modelsPkg())
+		modelTypes := FindModelVarTypes(method.Body, ctx.modelsPkg())
 		jsonCalls := FindCtxJSONCalls(method.Body, method.Fset)
 		for _, jc := range jsonCalls {
 			if PayloadIsModelWithoutPublic(jc.PayloadExpr, modelVars) {
+				if ident, ok := jc.PayloadExpr.(*ast.Ident); ok {
+					if hidden, known := hasHidden[modelTypes[ident.Name]]; known && !hidden {
+						continue
+					}
+				}
 				findings = append(findings, Finding{
 					Rule:     "public_projection",
 					Severity: SeverityError,
@@ -474,17 +572,24 @@ func rulePublicProjection(ctx *AnalysisContext) []Finding {
 	return findings
 }
 
-// ruleRequiredFields flags Create() calls where the model struct literal is missing required fields.
+// ruleRequiredFields flags Create() calls that would write a zero value into
+// a NOT NULL column with no default: the model literal leaves the field out
+// or sets it to "" or uuid.Nil, or the generated model has no field for the
+// column at all because it predates the migration that added it. The
+// database accepts the zero value, so none of these fail at insert time
+// except binary and JSON columns, whose zero value is NULL.
 func ruleRequiredFields(ctx *AnalysisContext) []Finding {
 	var findings []Finding
 
 	// Columns computed by the query builder for immutable/append-only tables
 	computedHashCols := map[string]bool{"row_hash": true, "prev_hash": true}
 
-	// Build a map of table name -> required fields (not nullable, no default, not PK)
-	requiredByTable := make(map[string][]string)
+	// Build a map of model name -> required columns (not nullable, no default,
+	// not PK). Keys use the generator's own table-to-struct naming, so a literal
+	// maps back to the table its model was generated from.
+	requiredByModel := make(map[string][]*schema.Column)
 	for _, table := range ctx.Tables {
-		var required []string
+		var required []*schema.Column
 		for _, col := range table.Columns {
 			if col.IsPrimaryKey || col.IsNullable || col.HasDefault || col.DefaultValue != nil {
 				continue
@@ -497,67 +602,163 @@ func ruleRequiredFields(ctx *AnalysisContext) []Finding {
 			if (table.IsImmutable || table.IsAppendOnly) && computedHashCols[col.Name] {
 				continue
 			}
-			required = append(required, col.Name)
+			required = append(required, col)
 		}
 		if len(required) > 0 {
-			requiredByTable[table.Name] = required
+			requiredByModel[names.TableToStructName(table.Name)] = required
 		}
 	}
 
 	for _, m := range ctx.Methods {
 		// Find composite literals in the method (and recursively in called functions)
 		lits := FindCompositeLiteralsRecursive(m.Body, m.Fset, ctx.FuncRegistry)
-		for _, lit := range lits {
-			if lit.PackageName != "models" {
-				continue
-			}
+		if len(lits) == 0 {
+			continue
+		}
 
-			// Map model type name to table name
-			// Post -> posts, Category -> categories
-			tableName := names.Pluralize(lit.TypeName)
-			required, ok := requiredByTable[tableName]
-			if !ok {
-				continue
+		// Models this method creates, e.g. models.QueryPost().Create(&post) —
+		// chain contains ["models", "QueryPost", "Create"]
+		authVarsReq := FindAuthTaintedVars(m.Body)
+		created := make(map[string]bool)
+		for _, chain := range ExtractCallChainsRecursive(m.Body, m.Fset, ctx.FuncRegistry, authVarsReq) {
+			chainNames := chain.Names()
+			for i, name := range chainNames {
+				if name == "Create" && i > 0 && strings.HasPrefix(chainNames[i-1], "Query") {
+					created[strings.TrimPrefix(chainNames[i-1], "Query")] = true
+				}
 			}
+		}
 
-			// Check if there's a Create call on the specific model's query builder
-			// e.g. models.QueryPost().Create(&post) — chain contains ["models", "QueryPost", "Create"]
-			authVarsReq := FindAuthTaintedVars(m.Body)
-			chains := ExtractCallChainsRecursive(m.Body, m.Fset, ctx.FuncRegistry, authVarsReq)
-			expectedQuery := "Query" + lit.TypeName
-			hasCreate := false
-			for _, chain := range chains {
-				chainNames := chain.Names()
-				for i, name := range chainNames {
-					if name == "Create" && i > 0 && chainNames[i-1] == expectedQuery {
-						hasCreate = true
-						break
-					}
-				}
+		for _, lit := range lits {
+			if lit.PackageName != ctx.modelsPkg() || !created[lit.TypeName] {
+				continue
 			}
-			if !hasCreate {
+			required, ok := requiredByModel[lit.TypeName]
+			if !ok {
 				continue
 			}
 
-			// Check which required fields are set
 			setFields := make(map[string]bool)
 			for _, f := range lit.FieldNames {
 				setFields[f] = true
 			}
+			zeroFields := make(map[string]bool)
+			for _, f := range lit.ZeroFields {
+				zeroFields[f] = true
+			}
+			modelFields, modelKnown := ctx.ModelFields[lit.TypeName]
+
+			for _, col := range required {
+				goField := names.SnakeToPascal(col.Name)
+				var message string
+				switch {
+				case modelKnown && !modelFields[goField]:
+					message = ctx.modelsPkg() + "." + lit.TypeName + " has no field " + goField + " but column " + col.Name + " is NOT NULL with no default — the model is older than the migrations, run pickle generate"
+				case zeroFields[goField]:
+					message = lit.TypeName + "{} sets required field " + goField + " to " + zeroValueOf(col) + " (column " + col.Name + " is NOT NULL with no default) — the zero value is stored as if it were data"
+				case !setFields[goField]:
+					message = lit.TypeName + "{} missing required field " + goField + " (column " + col.Name + " is NOT NULL with no default) — " + omittedEffect(col)
+				default:
+					continue
+				}
+				findings = append(findings, Finding{
+					Rule:     "required_fields",
+					Severity: SeverityError,
+					File:     m.File,
+					Line:     lit.Line,
+					Message:  message,
+				})
+			}
+		}
+	}
 
-			for _, reqCol := range required {
-				goField := names.SnakeToPascal(reqCol)
-				if !setFields[goField] {
-					findings = append(findings, Finding{
-						Rule:     "required_fields",
-						Severity: SeverityError,
-						File:     m.File,
-						Line:     lit.Line,
-						Message:  lit.TypeName + "{} missing required field " + goField + " (column " + reqCol + " is NOT NULL with no default)",
-					})
+	return findings
+}
+
+// zeroValueOf describes the Go zero value of col's model field.
+func zeroValueOf(col *schema.Column) string {
+	switch names.ColumnBaseGoType(col) {
+	case "string":
+		return `""`
+	case "uuid.UUID":
+		return "uuid.Nil"
+	case "bool":
+		return "false"
+	case "time.Time":
+		return "the zero time"
+	case "[]byte", "json.RawMessage":
+		return "nil"
+	default:
+		return "0"
+	}
+}
+
+// omittedEffect says what Create writes for a required column left out of
+// the model literal.
+func omittedEffect(col *schema.Column) string {
+	switch names.ColumnBaseGoType(col) {
+	case "[]byte", "json.RawMessage":
+		return "Create inserts NULL and the insert fails"
+	}
+	return "Create silently inserts " + zeroValueOf(col)
+}
+
+// ruleNullableUpdate flags Update handlers that assign a dereferenced request
+// pointer to a NOT NULL model column without a nil guard. Update requests use
+// pointer fields so absent keys stay nil; `post.Title = *req.Title` outside an
+// `if req.Title != nil` block panics whenever the client omits the field.
+func ruleNullableUpdate(ctx *AnalysisContext) []Finding {
+	var findings []Finding
+
+	notNullByModel := make(map[string]map[string]bool)
+	for _, table := range ctx.Tables {
+		cols := make(map[string]bool)
+		for _, col := range table.Columns {
+			if !col.IsNullable {
+				cols[col.Name] = true
+			}
+		}
+		notNullByModel[names.TableToStructName(table.Name)] = cols
+	}
+
+	for _, m := range ctx.Methods {
+		modelTypes := FindModelVarTypes(m.Body, ctx.modelsPkg())
+		derefs := FindUnguardedDerefs(m.Body, m.Fset)
+		if len(derefs) == 0 {
+			continue
+		}
+
+		// Only Update handlers matter: the model must be written back with
+		// models.QueryX().Update(...) or UpdateColumns(...)
+		authVars := FindAuthTaintedVars(m.Body)
+		chains := ExtractCallChainsRecursive(m.Body, m.Fset, ctx.FuncRegistry, authVars)
+		updated := make(map[string]bool)
+		for _, chain := range chains {
+			chainNames := chain.Names()
+			for i, name := range chainNames {
+				if (name == "Update" || name == "UpdateColumns") && i > 0 && strings.HasPrefix(chainNames[i-1], "Query") {
+					updated[strings.TrimPrefix(chainNames[i-1], "Query")] = true
 				}
 			}
 		}
+
+		for _, deref := range derefs {
+			typeName, ok := modelTypes[deref.Target]
+			if !ok || !updated[typeName] {
+				continue
+			}
+			column := names.PascalToSnake(deref.Field)
+			if !notNullByModel[typeName][column] {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:     "nullable_update",
+				Severity: SeverityError,
+				File:     m.File,
+				Line:     deref.Line,
+				Message:  deref.Target + "." + deref.Field + " = *" + deref.Source + " without a nil check — panics when the field is omitted (column " + column + " is NOT NULL), wrap in if " + deref.Source + " != nil",
+			})
+		}
 	}
 
 	return findings
@@ -635,87 +836,68 @@ func ruleReadScoping(ctx *AnalysisContext) []Finding {
 	return findings
 }
 
-// ruleUnboundedQuery flags routes that call .All() without .Limit().
-// Unauthenticated routes are errors (DoS vector). Authenticated routes are warnings (resource waste).
+// ruleUnboundedQuery flags Index routes whose query chains end in .All()
+// without .Limit() or .Paginate(). Methods that also call Count (hand-rolled
+// pagination) and chains filtered by WhereID* (small, bounded results) are
+// skipped, as are routes excluded by the unbounded_query config.
 func ruleUnboundedQuery(ctx *AnalysisContext) []Finding {
 	var findings []Finding
+	cfg := ctx.Config.UnboundedQuery
 
 	for _, route := range ctx.Routes {
+		if route.MethodName != "Index" || cfg.skips(route, ctx.Config.Middleware) {
+			continue
+		}
 		key := route.ControllerType + "." + route.MethodName
 		method, ok := ctx.Methods[key]
 		if !ok {
 			continue
 		}
 
-		authVarsUnbounded := FindAuthTaintedVars(method.Body)
-		chains := ExtractCallChainsRecursive(method.Body, method.Fset, ctx.FuncRegistry, authVarsUnbounded)
+		authVars := FindAuthTaintedVars(method.Body)
+		chains := ExtractCallChainsRecursive(method.Body, method.Fset, ctx.FuncRegistry, authVars)
+		if chainsCall(chains, "Count") {
+			continue
+		}
 
 		for _, chain := range chains {
-			chainNames := chain.Names()
-
-			isQueryChain := false
-			hasAll := false
-			hasLimit := false
-			for _, name := range chainNames {
-				if strings.HasPrefix(name, "Query") {
+			isQueryChain, hasAll, bounded := false, false, false
+			for _, name := range chain.Names() {
+				switch {
+				case strings.HasPrefix(name, "Query"):
 					isQueryChain = true
-				}
-				if name == "All" {
+				case name == "All":
 					hasAll = true
-				}
-				if name == "Limit" || name == "Paginate" {
-					hasLimit = true
+				case name == "Limit" || name == "Paginate" || strings.HasPrefix(name, "WhereID"):
+					bounded = true
 				}
 			}
 
-			if isQueryChain && hasAll && !hasLimit {
+			if isQueryChain && hasAll && !bounded {
 				findings = append(findings, Finding{
 					Rule:     "unbounded_query",
-					Severity: SeverityError,
+					Severity: SeverityWarning,
 					File:     method.File,
 					Line:     method.Line,
-					Message:  route.Method + " " + route.Path + " — .All() without .Limit() (unbounded response size)",
+					Message:  route.Method + " " + route.Path + " — .All() without .Limit() or .Paginate(); paginate list endpoints with ctx.Pagination(...)",
 				})
 			}
 		}
 	}
 
-	// Also scan standalone functions in the FuncRegistry (service layer / helpers)
-	for name, fn := range ctx.FuncRegistry {
-		chains := ExtractCallChains(fn.Body, fn.Fset)
-
-		for _, chain := range chains {
-			chainNames := chain.Names()
-
-			isQueryChain := false
-			hasAll := false
-			hasLimit := false
-			for _, n := range chainNames {
-				if strings.HasPrefix(n, "Query") {
-					isQueryChain = true
-				}
-				if n == "All" {
-					hasAll = true
-				}
-				if n == "Limit" || n == "Paginate" {
-					hasLimit = true
-				}
-			}
+	return findings
+}
 
-			if isQueryChain && hasAll && !hasLimit {
-				file := fn.Fset.Position(fn.Body.Pos()).Filename
-				findings = append(findings, Finding{
-					Rule:     "unbounded_query",
-					Severity: SeverityError,
-					File:     file,
-					Line:     fn.Fset.Position(fn.Body.Pos()).Line,
-					Message:  name + "() — .All() without .Limit() (unbounded result set)",
-				})
+// chainsCall reports whether any chain has a segment with the given name.
+func chainsCall(chains []CallChain, name string) bool {
+	for _, chain := range chains {
+		for _, seg := range chain.Segments {
+			if seg.Name == name {
+				return true
 			}
 		}
 	}
-
-	return findings
+	return false
 }
 
 // authMethodNames are controller method names that handle credential-based authentication.
@@ -807,6 +989,39 @@ func ruleParamMismatch(ctx *AnalysisContext) []Finding {
 	return findings
 }
 
+// ruleDanglingRoute flags routes whose handler method is not defined on the
+// controller. Only controllers with at least one parsed method are checked,
+// so handlers from packages squeeze does not scan never produce a finding.
+func ruleDanglingRoute(ctx *AnalysisContext) []Finding {
+	known := make(map[string]bool)
+	for key := range ctx.Methods {
+		if ctrl, _, ok := strings.Cut(key, "."); ok {
+			known[ctrl] = true
+		}
+	}
+
+	var findings []Finding
+	for _, route := range ctx.Routes {
+		if route.ControllerType == "" || route.MethodName == "" || !known[route.ControllerType] {
+			continue
+		}
+		if route.HandlerPackage != "" && route.HandlerPackage != "controllers" {
+			continue
+		}
+		if _, ok := ctx.Methods[route.ControllerType+"."+route.MethodName]; ok {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:     "dangling_route",
+			Severity: SeverityError,
+			File:     route.File,
+			Line:     route.Line,
+			Message:  route.Method + " " + route.Path + " — " + route.ControllerType + " has no " + route.MethodName + " method",
+		})
+	}
+	return findings
+}
+
 // ruleAuthWithoutMiddleware flags controllers on unauthenticated routes that call ctx.Auth().
 // Without auth middleware, ctx.Auth() panics. This is always a bug.
 func ruleAuthWithoutMiddleware(ctx *AnalysisContext) []Finding {
@@ -837,6 +1052,78 @@ func ruleAuthWithoutMiddleware(ctx *AnalysisContext) []Finding {
 	return findings
 }
 
+// ruleErrorLeak flags controllers on unauthenticated routes that return a raw
+// error through ctx.Error(). ctx.Error() only hides the message when
+// APP_DEBUG is off, so a debug deploy would show SQL or other internals to
+// anyone who can reach the route.
+func ruleErrorLeak(ctx *AnalysisContext) []Finding {
+	var findings []Finding
+
+	for _, route := range ctx.Routes {
+		if route.HasAuthMiddleware(ctx.Config.Middleware) {
+			continue
+		}
+
+		key := route.ControllerType + "." + route.MethodName
+		method, ok := ctx.Methods[key]
+		if !ok {
+			continue
+		}
+
+		for _, line := range findRawErrorReturns(method.Body, method.Fset) {
+			findings = append(findings, Finding{
+				Rule:     "error_leak",
+				Severity: SeverityWarning,
+				File:     method.File,
+				Line:     line,
+				Message:  route.Method + " " + route.Path + " — returns ctx.Error(err) on an unauthenticated route; the error text reaches clients when APP_DEBUG is on. Return a fixed message instead",
+			})
+		}
+	}
+
+	return findings
+}
+
+// findRawErrorReturns returns the lines of `return ctx.Error(x)` statements
+// whose argument is an error variable or field, rather than a sentinel error
+// or one built from a fixed message.
+func findRawErrorReturns(body *ast.BlockStmt, fset *token.FileSet) []int {
+	var lines []int
+	ast.Inspect(body, func(n ast.Node) bool {
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return true
+		}
+		call, ok := ret.Results[0].(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Error" {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != "ctx" {
+			return true
+		}
+		var name string
+		switch arg := call.Args[0].(type) {
+		case *ast.Ident:
+			name = arg.Name
+		case *ast.SelectorExpr:
+			name = arg.Sel.Name
+		default:
+			return true
+		}
+		// Sentinel errors (ErrNotFound, models.ErrConflict) carry a fixed message.
+		if strings.HasPrefix(name, "Err") {
+			return true
+		}
+		lines = append(lines, fset.Position(ret.Pos()).Line)
+		return true
+	})
+	return lines
+}
+
 // ruleCsrfMissing flags state-changing routes without CSRF middleware when the project uses sessions.
 // The session driver is always generated by Pickle, so its presence isn't a signal. Instead, we scan
 // controllers and helper functions for calls to session.Create — that's the real indicator.
@@ -4,6 +4,7 @@ import (
 	"go/ast"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/shortontech/pickle/pkg/generator"
@@ -329,6 +330,20 @@ func Handler() {
 	}
 }
 
+func TestFindParamNames_TypedIntAccessors(t *testing.T) {
+	src := `package controllers
+func Handler() {
+	page, _ := ctx.ParamInt("page")
+	id, _ := ctx.ParamInt64("postId")
+	_, _ = page, id
+}`
+	m := method(t, src)
+	calls := FindParamNames(m.Body, m.Fset)
+	if len(calls) != 2 || calls[0].Name != "page" || calls[1].Name != "postId" {
+		t.Errorf("expected params 'page' and 'postId', got %v", calls)
+	}
+}
+
 func TestFindAuthTaintedVars(t *testing.T) {
 	src := `package controllers
 import "uuid"
@@ -1154,6 +1169,60 @@ func Handler() {
 	}
 }
 
+// ---- Rule: error_leak ----
+
+func TestRuleErrorLeak_FlagsRawErrorOnPublicRoute(t *testing.T) {
+	src := `package controllers
+func Handler() {
+	post, err := models.QueryPost().First()
+	if err != nil {
+		return ctx.Error(err)
+	}
+	if post.Draft {
+		return ctx.Error(ErrNotFound)
+	}
+	return ctx.Error(errors.New("could not publish"))
+}`
+	m := method(t, src)
+	ctx := &AnalysisContext{
+		Config: defaultConfig(),
+		Methods: map[string]*ControllerMethod{
+			"PostController.Show": m,
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "GET", Path: "/posts/:id", ControllerType: "PostController", MethodName: "Show", Middleware: []string{}},
+		},
+	}
+	findings := ruleErrorLeak(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	if findings[0].Line != 5 || findings[0].Severity != SeverityWarning {
+		t.Errorf("finding = %+v, want a warning on line 5", findings[0])
+	}
+}
+
+func TestRuleErrorLeak_PassesWithAuthMiddleware(t *testing.T) {
+	src := `package controllers
+func Handler() {
+	_, err := models.QueryPost().First()
+	return ctx.Error(err)
+}`
+	m := method(t, src)
+	ctx := &AnalysisContext{
+		Config: defaultConfig(),
+		Methods: map[string]*ControllerMethod{
+			"PostController.Show": m,
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "GET", Path: "/posts/:id", ControllerType: "PostController", MethodName: "Show", Middleware: []string{"Auth"}},
+		},
+	}
+	if findings := ruleErrorLeak(ctx); len(findings) != 0 {
+		t.Errorf("expected 0 findings when auth middleware present, got %d", len(findings))
+	}
+}
+
 // ---- Rule: param_mismatch ----
 
 func TestRuleParamMismatch_FlagsWrongParamName(t *testing.T) {
@@ -1214,6 +1283,48 @@ func TestRuleParamMismatch_SkipsUnknownController(t *testing.T) {
 	}
 }
 
+// ---- Rule: dangling_route ----
+
+func TestRuleDanglingRoute_FlagsMissingMethod(t *testing.T) {
+	ctx := &AnalysisContext{
+		Methods: map[string]*ControllerMethod{
+			"UserController.Index": {ControllerType: "UserController", MethodName: "Index"},
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "GET", Path: "/users", ControllerType: "UserController", MethodName: "Index", HandlerPackage: "controllers"},
+			{Method: "GET", Path: "/users/:id", ControllerType: "UserController", MethodName: "Shwo", HandlerPackage: "controllers", File: "routes/web.go", Line: 12},
+		},
+	}
+	findings := ruleDanglingRoute(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+	f := findings[0]
+	if f.Rule != "dangling_route" || f.Severity != SeverityError || f.Line != 12 {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if !strings.Contains(f.Message, "UserController has no Shwo method") {
+		t.Errorf("message = %q", f.Message)
+	}
+}
+
+func TestRuleDanglingRoute_SkipsUnscannedControllers(t *testing.T) {
+	ctx := &AnalysisContext{
+		Methods: map[string]*ControllerMethod{
+			"UserController.Index": {ControllerType: "UserController", MethodName: "Index"},
+		},
+		Routes: []AnalyzedRoute{
+			// Controller type with no parsed methods: defined elsewhere.
+			{Method: "GET", Path: "/health", ControllerType: "HealthController", MethodName: "Check", HandlerPackage: "controllers"},
+			// Same type name in another package.
+			{Method: "GET", Path: "/admin/users", ControllerType: "UserController", MethodName: "Export", HandlerPackage: "admin"},
+		},
+	}
+	if findings := ruleDanglingRoute(ctx); len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %+v", findings)
+	}
+}
+
 // ---- Rule: ownership_scoping ----
 
 func TestRuleOwnershipScoping_FlagsMissingScope(t *testing.T) {
@@ -1239,6 +1350,31 @@ func Handler() {
 	}
 }
 
+func TestRuleOwnershipScoping_PassesWithAuthorize(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	post, _ := models.QueryPost().WhereID(id).First()
+	if resp, ok := ctx.Authorize(policies.PostPolicy{}.Delete(ctx.Auth(), post)); !ok {
+		return resp
+	}
+	models.QueryPost().WhereID(id).Delete()
+}`
+	m := method(t, src)
+	ctx := &AnalysisContext{
+		Config: defaultConfig(),
+		Methods: map[string]*ControllerMethod{
+			"PostController.Destroy": m,
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "DELETE", Path: "/posts/:id", ControllerType: "PostController", MethodName: "Destroy", Middleware: []string{"Auth"}},
+		},
+	}
+	if findings := ruleOwnershipScoping(ctx); len(findings) != 0 {
+		t.Errorf("expected 0 findings when an access policy is checked, got %d", len(findings))
+	}
+}
+
 func TestRuleOwnershipScoping_PassesWithOwnershipWhere(t *testing.T) {
 	src := `package controllers
 import "models"
@@ -1626,6 +1762,92 @@ func Handler() {
 	}
 }
 
+func unboundedIndexCtx(t *testing.T, src string, route AnalyzedRoute) *AnalysisContext {
+	t.Helper()
+	return &AnalysisContext{
+		Methods: map[string]*ControllerMethod{
+			route.ControllerType + "." + route.MethodName: method(t, src),
+		},
+		Routes: []AnalyzedRoute{route},
+	}
+}
+
+func TestRuleUnboundedQuery_WarnsOnIndexOnly(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	posts, _ := models.QueryPost().WhereAuthorID(id).All()
+	_ = posts
+}`
+	ctx := unboundedIndexCtx(t, src, AnalyzedRoute{Method: "GET", Path: "/posts", ControllerType: "PostController", MethodName: "Index"})
+	findings := ruleUnboundedQuery(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(findings))
+	}
+	if findings[0].Severity != SeverityWarning {
+		t.Errorf("severity = %v, want warning", findings[0].Severity)
+	}
+	if !strings.Contains(findings[0].Message, "Paginate") {
+		t.Errorf("message should suggest pagination: %s", findings[0].Message)
+	}
+
+	ctx = unboundedIndexCtx(t, src, AnalyzedRoute{Method: "GET", Path: "/posts/export", ControllerType: "PostController", MethodName: "Export"})
+	if findings := ruleUnboundedQuery(ctx); len(findings) != 0 {
+		t.Errorf("expected non-Index routes to be skipped, got %d findings", len(findings))
+	}
+}
+
+func TestRuleUnboundedQuery_SkipsCountAndWhereID(t *testing.T) {
+	route := AnalyzedRoute{Method: "GET", Path: "/posts", ControllerType: "PostController", MethodName: "Index"}
+	for name, src := range map[string]string{
+		"count": `package controllers
+import "models"
+func Handler() {
+	total, _ := models.QueryPost().Count()
+	posts, _ := models.QueryPost().All()
+	_, _ = total, posts
+}`,
+		"where id": `package controllers
+import "models"
+func Handler() {
+	posts, _ := models.QueryPost().WhereIDIn(ids).All()
+	_ = posts
+}`,
+	} {
+		if findings := ruleUnboundedQuery(unboundedIndexCtx(t, src, route)); len(findings) != 0 {
+			t.Errorf("%s: expected 0 findings, got %d", name, len(findings))
+		}
+	}
+}
+
+func TestRuleUnboundedQuery_ConfigSkipsAdminAndPaths(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	users, _ := models.QueryUser().All()
+	_ = users
+}`
+	admin := AnalyzedRoute{Method: "GET", Path: "/admin/users", ControllerType: "UserController", MethodName: "Index", Middleware: []string{"Auth", "RequireAdmin"}}
+	internal := AnalyzedRoute{Method: "GET", Path: "/internal/users", ControllerType: "UserController", MethodName: "Index"}
+	lookalike := AnalyzedRoute{Method: "GET", Path: "/internals", ControllerType: "UserController", MethodName: "Index"}
+
+	if findings := ruleUnboundedQuery(unboundedIndexCtx(t, src, admin)); len(findings) != 1 {
+		t.Fatalf("admin route without config: expected 1 finding, got %d", len(findings))
+	}
+
+	cfg := SqueezeConfig{UnboundedQuery: UnboundedQueryConfig{SkipAdmin: true, SkipPaths: []string{"/internal/"}}}
+	for _, tc := range []struct {
+		route AnalyzedRoute
+		want  int
+	}{{admin, 0}, {internal, 0}, {lookalike, 1}} {
+		ctx := unboundedIndexCtx(t, src, tc.route)
+		ctx.Config = cfg
+		if findings := ruleUnboundedQuery(ctx); len(findings) != tc.want {
+			t.Errorf("%s: expected %d findings, got %d", tc.route.Path, tc.want, len(findings))
+		}
+	}
+}
+
 // ---- Rule: public_projection ----
 
 func TestRulePublicProjection_FlagsBareModelVar(t *testing.T) {
@@ -1700,6 +1922,65 @@ func Handler() {
 	}
 }
 
+func TestRulePublicProjection_UsesHiddenColumns(t *testing.T) {
+	src := `package controllers
+import "models"
+func Handler() {
+	user, _ := models.QueryUser().First()
+	return ctx.JSON(200, user)
+}`
+	m := method(t, src)
+	users := &schema.Table{Name: "users"}
+	users.UUID("id").PrimaryKey()
+	users.String("name").NotNull()
+	ctx := &AnalysisContext{
+		Config: defaultConfig(),
+		Tables: []*schema.Table{users},
+		Methods: map[string]*ControllerMethod{
+			"UserController.Show": m,
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "GET", Path: "/users/:id", ControllerType: "UserController", MethodName: "Show", Middleware: []string{}},
+		},
+	}
+	if findings := rulePublicProjection(ctx); len(findings) != 0 {
+		t.Errorf("expected no findings for a model without hidden columns, got %d", len(findings))
+	}
+
+	users.String("api_token").NotNull().Hidden()
+	if findings := rulePublicProjection(ctx); len(findings) != 1 {
+		t.Errorf("expected 1 finding once a column is Hidden(), got %d", len(findings))
+	}
+}
+
+func TestRulePublicProjection_RenamedModelsPackage(t *testing.T) {
+	src := `package controllers
+import db "myapp/app/models"
+func Handler() {
+	user, _ := db.QueryUser().First()
+	return ctx.JSON(200, user)
+}`
+	m := method(t, src)
+	ctx := &AnalysisContext{
+		Config:    defaultConfig(),
+		ModelsPkg: "db",
+		Methods: map[string]*ControllerMethod{
+			"UserController.Show": m,
+		},
+		Routes: []AnalyzedRoute{
+			{Method: "GET", Path: "/users/:id", ControllerType: "UserController", MethodName: "Show", Middleware: []string{}},
+		},
+	}
+	if findings := rulePublicProjection(ctx); len(findings) != 1 {
+		t.Fatalf("expected 1 finding for db.QueryUser() result, got %d", len(findings))
+	}
+
+	ctx.ModelsPkg = ""
+	if findings := rulePublicProjection(ctx); len(findings) != 0 {
+		t.Errorf("expected db.QueryUser() to be ignored when models package is the default, got %d findings", len(findings))
+	}
+}
+
 // ---- AllRules ----
 
 func TestAllRules_ContainsExpectedRules(t *testing.T) {
@@ -1708,7 +1989,7 @@ func TestAllRules_ContainsExpectedRules(t *testing.T) {
 		"no_printf", "no_recover", "ownership_scoping", "read_scoping",
 		"enum_validation", "uuid_error_handling", "public_projection",
 		"required_fields", "unbounded_query", "rate_limit_auth",
-		"auth_without_middleware", "param_mismatch", "csrf_missing",
+		"auth_without_middleware", "error_leak", "param_mismatch", "dangling_route", "csrf_missing",
 		"sensitive_field_encryption", "public_sensitive_conflict",
 	}
 	for _, name := range expected {
@@ -2105,3 +2386,26 @@ func TestRuleFloatRequestField_PassesString(t *testing.T) {
 		t.Errorf("string field should not trigger, got %d findings", len(findings))
 	}
 }
+
+func TestRuleEnumValidation_FlagsLiteralEnumAssignment(t *testing.T) {
+	src := `package controllers
+func Handler() {
+	post.Status = "published"
+	post.Status = string(requests.StatusDraft)
+	post.Title = "published"
+}`
+	ctx := &AnalysisContext{
+		Requests: []generator.RequestDef{{
+			Name:   "UpdatePostRequest",
+			Fields: []generator.RequestField{{Name: "Status", Validate: "omitempty,oneof=draft published"}},
+		}},
+		Methods: map[string]*ControllerMethod{"C.Handler": method(t, src)},
+	}
+	findings := ruleEnumValidation(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	if findings[0].Severity != SeverityWarning || !strings.Contains(findings[0].Message, "requests.StatusPublished") || findings[0].Line != 3 {
+		t.Errorf("unexpected finding: %s", findings[0])
+	}
+}
@@ -0,0 +1,212 @@
+package squeeze
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// identifierArgs lists the query builder methods whose arguments are
+// written into the SQL text instead of being sent as parameters, and which
+// of their arguments are. -1 means every argument.
+var identifierArgs = map[string][]int{
+	"OrderBy":           {0, 1},
+	"GroupBy":           {-1},
+	"Select":            {-1},
+	"WhereJSON":         {0, 1},
+	"WhereJSONContains": {0},
+	"WhereRaw":          {0},
+	"WhereExists":       {0},
+	"WhereNotExists":    {0},
+	"Having":            {0},
+}
+
+// requestInputMethods are the Context methods that return client-controlled
+// strings.
+var requestInputMethods = map[string]bool{"Query": true, "Param": true, "PeekJSON": true}
+
+// ruleSQLInjectionRisk flags query builder calls whose column, direction or
+// SQL fragment argument comes from the request — ctx.Query, ctx.Param, the
+// URL query, a form value or a bound request field — rather than a literal.
+// Those arguments are not parameterized. A value checked against literals
+// first (a switch on it, an == comparison, or a lookup in an allowlist map)
+// counts as sanitized.
+func ruleSQLInjectionRisk(ctx *AnalysisContext) []Finding {
+	var findings []Finding
+	for _, m := range ctx.Methods {
+		findings = append(findings, sqlInjectionFindings(m.Body, m.Fset, m.File)...)
+	}
+	return findings
+}
+
+func sqlInjectionFindings(body *ast.BlockStmt, fset *token.FileSet, file string) []Finding {
+	if body == nil {
+		return nil
+	}
+	sanitized := allowlistedExprs(body)
+	tainted := make(map[string]bool)
+	isTainted := func(expr ast.Expr) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			switch e := n.(type) {
+			case *ast.CallExpr:
+				if isRequestInputCall(e) {
+					found = true
+				}
+			case *ast.SelectorExpr, *ast.Ident:
+				name := exprString(e.(ast.Expr))
+				if sanitized[name] {
+					return false
+				}
+				if tainted[name] {
+					found = true
+				}
+			}
+			return !found
+		})
+		return found
+	}
+
+	var findings []Finding
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 {
+				if isTainted(node.Rhs[0]) {
+					for _, lhs := range node.Lhs {
+						if name := exprString(lhs); name != "" && name != "_" && name != "err" {
+							tainted[name] = true
+						}
+					}
+				}
+			} else if len(node.Rhs) == len(node.Lhs) {
+				for i, rhs := range node.Rhs {
+					if name := exprString(node.Lhs[i]); name != "" && isTainted(rhs) {
+						tainted[name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range node.Values {
+				if i < len(node.Names) && isTainted(value) {
+					tainted[node.Names[i].Name] = true
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			positions, ok := identifierArgs[sel.Sel.Name]
+			if !ok || isRequestInputReceiver(sel.X) {
+				return true
+			}
+			for i, arg := range node.Args {
+				if !argListed(positions, i) {
+					continue
+				}
+				if _, lit := arg.(*ast.BasicLit); lit || !isTainted(arg) {
+					continue
+				}
+				findings = append(findings, Finding{
+					Rule:     "sql_injection_risk",
+					Severity: SeverityError,
+					File:     file,
+					Line:     fset.Position(arg.Pos()).Line,
+					Message:  fmt.Sprintf("%s() argument %d comes from request input and is written into the SQL unparameterized — map it to a fixed column or direction first", sel.Sel.Name, i+1),
+				})
+			}
+		}
+		return true
+	})
+	return findings
+}
+
+// isRequestInputCall reports whether call reads client input directly:
+// ctx.Query/Param/PeekJSON, a generated Bind<Request>, URL.Query().Get,
+// or Request().FormValue.
+func isRequestInputCall(call *ast.CallExpr) bool {
+	var fn string
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		fn = f.Name
+	case *ast.SelectorExpr:
+		fn = f.Sel.Name
+		if ident, ok := f.X.(*ast.Ident); ok && ident.Name == "ctx" && requestInputMethods[fn] {
+			return true
+		}
+		if fn == "Get" && isRequestInputReceiver(f.X) {
+			return true
+		}
+		if fn == "FormValue" || fn == "PostFormValue" {
+			return true
+		}
+	}
+	return strings.HasPrefix(fn, "Bind") && strings.HasSuffix(fn, "Request")
+}
+
+// isRequestInputReceiver reports whether expr is a url.Values taken from the
+// request, as in r.URL.Query(), so .Get on it is input and its own Query
+// isn't mistaken for a query builder call.
+func isRequestInputReceiver(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Query" || len(call.Args) != 0 {
+		return false
+	}
+	inner, ok := sel.X.(*ast.SelectorExpr)
+	return ok && inner.Sel.Name == "URL"
+}
+
+// allowlistedExprs returns the variables and fields body checks against
+// literals: switch tags, == / != comparisons with a non-empty string
+// literal, and map index keys.
+func allowlistedExprs(body *ast.BlockStmt) map[string]bool {
+	out := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SwitchStmt:
+			if node.Tag != nil {
+				out[exprString(node.Tag)] = true
+			}
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			if isAllowedLiteral(node.Y) {
+				out[exprString(node.X)] = true
+			}
+			if isAllowedLiteral(node.X) {
+				out[exprString(node.Y)] = true
+			}
+		case *ast.IndexExpr:
+			out[exprString(node.Index)] = true
+		}
+		return true
+	})
+	delete(out, "")
+	return out
+}
+
+// isAllowedLiteral reports whether expr is a non-empty string literal. A
+// comparison with "" is an emptiness check, not an allowlist.
+func isAllowedLiteral(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING && lit.Value != `""` && lit.Value != "``"
+}
+
+func argListed(positions []int, i int) bool {
+	for _, p := range positions {
+		if p == -1 || p == i {
+			return true
+		}
+	}
+	return false
+}
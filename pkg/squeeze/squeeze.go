@@ -61,11 +61,20 @@ func Analyze(projectDir string) (*AnalysisContext, error) {
 		}
 	}
 
+	// 5b. Parse generated model structs
+	modelFields, err := ParseModelFields(project.Layout.ModelsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("parsing models: %w", err)
+		}
+		modelFields = nil
+	}
+
 	// 6. Get schema from migrations
 	tables, views, relationships, migrations, err := generator.RunSchemaInspectorWithMigrations(project)
 	if err != nil {
 		// Schema inspection is optional — warn and continue
-		fmt.Printf("  warning: schema inspection failed: %v\n", err)
+		fmt.Fprintf(os.Stderr, "  warning: schema inspection failed: %v\n", err)
 		tables = nil
 	}
 
@@ -128,6 +137,8 @@ func Analyze(projectDir string) (*AnalysisContext, error) {
 		HasGraphQL:     hasGraphQL,
 		GraphQLExposed: graphQLExposed,
 		ProjectDir:     projectDir,
+		ModelsPkg:      project.Layout.ModelsPkg,
+		ModelFields:    modelFields,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package tickle
 
 import (
 	"fmt"
+	"go/token"
 	"os"
 	"strings"
 
@@ -11,8 +12,10 @@ import (
 
 // ScopeBlock represents a template block extracted from a scopes file.
 type ScopeBlock struct {
-	Scope string // "all", "string", "numeric", "timestamp"
-	Body  string // The function template text
+	Scope   string   // "all", "string", "numeric", "timestamp", "json", "table", "custom"
+	Body    string   // The function template text
+	Name    string   // custom scopes only: the method name, e.g. "Active"
+	Columns []string // custom scopes only: columns a model needs to receive the scope
 }
 
 // ColumnDef holds the info needed to stamp out scope functions.
@@ -20,7 +23,7 @@ type ColumnDef struct {
 	PascalName  string // "UserID", "Status", "CreatedAt"
 	SnakeName   string // "user_id", "status", "created_at"
 	GoType      string // "uuid.UUID", "string", "time.Time"
-	Scope       string // "all", "string", "numeric", "timestamp"
+	Scope       string // "all", "string", "numeric", "timestamp", "json"
 	IsEncrypted bool   // AES-SIV deterministic — only equality scopes
 	IsSealed    bool   // AES-GCM non-deterministic — no scopes at all
 }
@@ -34,6 +37,8 @@ func ScopeForType(colType schema.ColumnType) string {
 		return "numeric"
 	case schema.Timestamp, schema.Date:
 		return "timestamp"
+	case schema.JSONB:
+		return "json"
 	default:
 		return "other"
 	}
@@ -65,10 +70,11 @@ func ParseScopeBlocks(path string) ([]ScopeBlock, error) {
 		if strings.HasPrefix(trimmed, "// pickle:scope ") {
 			// Save previous block if any
 			if currentScope != "" {
-				blocks = append(blocks, ScopeBlock{
-					Scope: currentScope,
-					Body:  strings.TrimSpace(currentBody.String()),
-				})
+				block, err := newScopeBlock(currentScope, currentBody.String())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				blocks = append(blocks, block)
 			}
 			currentScope = strings.TrimPrefix(trimmed, "// pickle:scope ")
 			currentBody.Reset()
@@ -77,10 +83,11 @@ func ParseScopeBlocks(path string) ([]ScopeBlock, error) {
 
 		if trimmed == "// pickle:end" {
 			if currentScope != "" {
-				blocks = append(blocks, ScopeBlock{
-					Scope: currentScope,
-					Body:  strings.TrimSpace(currentBody.String()),
-				})
+				block, err := newScopeBlock(currentScope, currentBody.String())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				blocks = append(blocks, block)
 			}
 			currentScope = ""
 			currentBody.Reset()
@@ -100,6 +107,24 @@ func ParseScopeBlocks(path string) ([]ScopeBlock, error) {
 	return blocks, nil
 }
 
+// newScopeBlock builds a block from its directive. Custom directives take the
+// form "custom Name [column...]": Name is the generated method, and the listed
+// columns restrict the scope to models that have all of them.
+func newScopeBlock(directive, body string) (ScopeBlock, error) {
+	block := ScopeBlock{Scope: directive, Body: strings.TrimSpace(body)}
+	fields := strings.Fields(directive)
+	if len(fields) == 0 || fields[0] != "custom" {
+		return block, nil
+	}
+	if len(fields) < 2 || !token.IsIdentifier(fields[1]) || !token.IsExported(fields[1]) {
+		return ScopeBlock{}, fmt.Errorf("pickle:scope custom needs an exported method name, got %q", directive)
+	}
+	block.Scope = "custom"
+	block.Name = fields[1]
+	block.Columns = fields[2:]
+	return block, nil
+}
+
 // ColumnsFromTable extracts column definitions from a schema table.
 func ColumnsFromTable(table *schema.Table) []ColumnDef {
 	var cols []ColumnDef
@@ -133,7 +158,7 @@ func GenerateScopes(blocks []ScopeBlock, columns []ColumnDef, modelName string)
 		}
 
 		for _, block := range blocks {
-			if IsTableScope(block.Scope) {
+			if IsTableScope(block.Scope) || IsCustomScope(block.Scope) {
 				continue
 			}
 			if !scopeMatches(block.Scope, col.Scope) {
@@ -174,6 +199,50 @@ func IsTableScope(scope string) bool {
 	return scope == "table" || scope == "table_owned"
 }
 
+// IsCustomScope returns true if the scope is a named compound scope, stamped
+// once per model rather than once per column.
+func IsCustomScope(scope string) bool {
+	return scope == "custom"
+}
+
+// GenerateCustomScopes stamps out custom scopes for a model. A scope that
+// lists required columns is skipped for models missing any of them; sealed
+// columns count as missing since they can't be queried.
+func GenerateCustomScopes(blocks []ScopeBlock, columns []ColumnDef, modelName string) string {
+	queryable := map[string]bool{}
+	for _, col := range columns {
+		if !col.IsSealed {
+			queryable[col.SnakeName] = true
+		}
+	}
+
+	var b strings.Builder
+	for _, block := range blocks {
+		if !IsCustomScope(block.Scope) {
+			continue
+		}
+		missing := false
+		for _, col := range block.Columns {
+			if !queryable[col] {
+				missing = true
+				break
+			}
+		}
+		if missing {
+			continue
+		}
+
+		expanded := block.Body
+		expanded = strings.ReplaceAll(expanded, "QueryBuilder[T]", modelName+"Query")
+		expanded = strings.ReplaceAll(expanded, "__Model__", modelName)
+
+		b.WriteString(expanded)
+		b.WriteString("\n\n")
+	}
+
+	return b.String()
+}
+
 // GenerateTableScopes stamps out table-level scope functions for a model.
 // "table" blocks are emitted for non-owned tables, "table_owned" for owned tables.
 func GenerateTableScopes(blocks []ScopeBlock, modelName string, owned bool) string {
@@ -131,7 +131,7 @@ func TestScopeForType(t *testing.T) {
 		{schema.Date, "timestamp"},
 		{schema.UUID, "other"},
 		{schema.Boolean, "other"},
-		{schema.JSONB, "other"},
+		{schema.JSONB, "json"},
 	}
 
 	for _, tt := range tests {
@@ -141,3 +141,80 @@ func TestScopeForType(t *testing.T) {
 		}
 	}
 }
+
+func TestCustomCompoundScope(t *testing.T) {
+	dir := t.TempDir()
+	content := `//go:build pickle_template
+
+package scopes
+
+// pickle:scope all
+func (q *QueryBuilder[T]) Where__Column__(val __type__) *QueryBuilder[T] {
+	q.where("__column__", val)
+	return q
+}
+
+// pickle:scope custom Active deleted_at status
+// Active returns __Model__ records that are live and active.
+func (q *QueryBuilder[T]) Active() *QueryBuilder[T] {
+	q.WhereRaw("deleted_at IS NULL AND status = 'active'")
+	return q
+}
+
+// pickle:end
+`
+	path := filepath.Join(dir, "custom.go")
+	os.WriteFile(path, []byte(content), 0o644)
+
+	blocks, err := ParseScopeBlocks(path)
+	if err != nil {
+		t.Fatalf("ParseScopeBlocks: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+	custom := blocks[1]
+	if custom.Scope != "custom" || custom.Name != "Active" {
+		t.Fatalf("custom block = %+v", custom)
+	}
+	if strings.Join(custom.Columns, ",") != "deleted_at,status" {
+		t.Errorf("custom columns = %v", custom.Columns)
+	}
+
+	posts := &schema.Table{Name: "posts"}
+	posts.String("status", 20).NotNull()
+	posts.Timestamp("deleted_at").Nullable()
+	postCols := ColumnsFromTable(posts)
+
+	perColumn, err := GenerateScopes(blocks, postCols, "Post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(perColumn, "Active") {
+		t.Error("custom scope should not be stamped per column")
+	}
+
+	out := GenerateCustomScopes(blocks, postCols, "Post")
+	if strings.Count(out, "func (q *PostQuery) Active() *PostQuery") != 1 {
+		t.Errorf("custom scope should be stamped once for Post, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Post records that are live") {
+		t.Error("__Model__ placeholder not replaced")
+	}
+
+	tags := &schema.Table{Name: "tags"}
+	tags.String("status", 20).NotNull()
+	if out := GenerateCustomScopes(blocks, ColumnsFromTable(tags), "Tag"); out != "" {
+		t.Errorf("Tag lacks deleted_at and should not get Active, got:\n%s", out)
+	}
+}
+
+func TestCustomScopeRequiresExportedName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.go")
+	os.WriteFile(path, []byte("// pickle:scope custom active\nfunc x() {}\n// pickle:end\n"), 0o644)
+
+	if _, err := ParseScopeBlocks(path); err == nil {
+		t.Fatal("expected an error for an unexported custom scope name")
+	}
+}
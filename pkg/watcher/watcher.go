@@ -1,9 +1,12 @@
 package watcher
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -124,10 +127,7 @@ func watchImpl(projectDir string, watchDirs []string, onChange OnChange) error {
 				continue
 			}
 
-			changed := make([]string, 0, len(pending))
-			for path := range pending {
-				changed = append(changed, path)
-			}
+			changed := sortedPaths(pending)
 			pending = map[string]bool{}
 
 			onChange(changed)
@@ -146,6 +146,7 @@ type AppWatchConfig struct {
 	Name          string
 	ProjectDir    string
 	MigrationDirs []string // absolute paths to all migration dirs (including shared)
+	ExtraDirs     []string // additional dirs relative to ProjectDir, from pickle.yaml watch.dirs
 }
 
 // WatchMonorepo monitors multiple apps for changes. When a shared migration
@@ -163,7 +164,7 @@ func WatchMonorepo(rootDir string, apps []AppWatchConfig, onChange func(appName
 
 	for _, app := range apps {
 		// Watch standard app directories
-		for _, rel := range WatchDirs {
+		for _, rel := range append(append([]string{}, WatchDirs...), app.ExtraDirs...) {
 			path := filepath.Join(app.ProjectDir, rel)
 			if info, err := os.Stat(path); err == nil && info.IsDir() {
 				if err := addRecursive(w, path); err != nil {
@@ -231,11 +232,7 @@ func WatchMonorepo(rootDir string, apps []AppWatchConfig, onChange func(appName
 
 		case <-timer.C:
 			for appName, paths := range pending {
-				changed := make([]string, 0, len(paths))
-				for p := range paths {
-					changed = append(changed, p)
-				}
-				onChange(appName, changed)
+				onChange(appName, sortedPaths(paths))
 			}
 			pending = map[string]map[string]bool{}
 
@@ -292,13 +289,35 @@ func addRecursive(w *fsnotify.Watcher, dir string) error {
 	})
 }
 
-// isRelevant filters events to only Go source file changes.
+// sortedPaths returns the keys of a pending set in a stable order.
+func sortedPaths(set map[string]bool) []string {
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// isRelevant filters events to Go source file changes a regeneration should
+// react to. Pickle's own output (*_gen.go, files headed "Code generated ...
+// DO NOT EDIT." and anything under .pickle-tmp) is ignored so that writing it
+// can't retrigger generation. Editor scratch files are ignored too: an editor
+// that saves by writing a temp file and renaming it over the original then
+// produces events for the original path only, which the debounce window
+// collapses into a single change.
 func isRelevant(event fsnotify.Event) bool {
 	// Only care about writes, creates, and renames
 	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
 		return false
 	}
 
+	for _, part := range strings.Split(filepath.ToSlash(event.Name), "/") {
+		if part == ".pickle-tmp" {
+			return false
+		}
+	}
+
 	// Only care about .go files (or directories for create events)
 	if event.Has(fsnotify.Create) {
 		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
@@ -306,5 +325,38 @@ func isRelevant(event fsnotify.Event) bool {
 		}
 	}
 
-	return strings.HasSuffix(event.Name, ".go")
+	base := filepath.Base(event.Name)
+	if !strings.HasSuffix(base, ".go") || strings.HasSuffix(base, "_gen.go") {
+		return false
+	}
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(base, "#") {
+		return false
+	}
+	return !isGeneratedFile(event.Name)
+}
+
+// isGeneratedFile reports whether path starts with a standard generated-code
+// header. Missing or unreadable files are treated as hand-written so a
+// deletion still triggers regeneration.
+func isGeneratedFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	for _, line := range bytes.Split(head[:n], []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !bytes.HasPrefix(line, []byte("//")) {
+			return false
+		}
+		if bytes.HasPrefix(line, []byte("// Code generated ")) && bytes.HasSuffix(line, []byte(" DO NOT EDIT.")) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,59 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestIsRelevantIgnoresGeneratedAndScratchFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	handWritten := write("user_controller.go", "package controllers\n")
+	generated := write("user_query.go", "// Code generated by Pickle. DO NOT EDIT.\npackage models\n")
+	taggedGenerated := write("tx.go", "//go:build !ignore\n\n// Code generated by Pickle. DO NOT EDIT.\npackage models\n")
+	genSuffix := write("pickle_gen.go", "package models\n")
+	tmp := write(".pickle-tmp/inspect/main.go", "package main\n")
+	lock := write(".#user_controller.go", "")
+	deleted := filepath.Join(dir, "removed_migration.go")
+
+	cases := []struct {
+		name string
+		ev   fsnotify.Event
+		want bool
+	}{
+		{"hand-written write", fsnotify.Event{Name: handWritten, Op: fsnotify.Write}, true},
+		{"renamed away", fsnotify.Event{Name: deleted, Op: fsnotify.Rename}, true},
+		{"generated header", fsnotify.Event{Name: generated, Op: fsnotify.Write}, false},
+		{"generated header after build tag", fsnotify.Event{Name: taggedGenerated, Op: fsnotify.Write}, false},
+		{"_gen.go suffix", fsnotify.Event{Name: genSuffix, Op: fsnotify.Create}, false},
+		{".pickle-tmp", fsnotify.Event{Name: tmp, Op: fsnotify.Write}, false},
+		{"editor lock file", fsnotify.Event{Name: lock, Op: fsnotify.Create}, false},
+		{"chmod only", fsnotify.Event{Name: handWritten, Op: fsnotify.Chmod}, false},
+		{"non-go file", fsnotify.Event{Name: filepath.Join(dir, "notes.md"), Op: fsnotify.Write}, false},
+	}
+	for _, tc := range cases {
+		if got := isRelevant(tc.ev); got != tc.want {
+			t.Errorf("%s: isRelevant = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSortedPaths(t *testing.T) {
+	got := sortedPaths(map[string]bool{"b.go": true, "a.go": true, "c.go": true})
+	if len(got) != 3 || got[0] != "a.go" || got[1] != "b.go" || got[2] != "c.go" {
+		t.Errorf("sortedPaths = %v", got)
+	}
+}